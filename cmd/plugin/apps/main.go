@@ -22,7 +22,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	// load credential helpers
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -33,18 +35,22 @@ import (
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	tanzucliv1alpha1 "github.com/vmware-tanzu/tanzu-framework/apis/cli/v1alpha1"
+	"github.com/vmware-tanzu/tanzu-framework/pkg/v1/buildinfo"
 	"github.com/vmware-tanzu/tanzu-framework/pkg/v1/cli/command/plugin"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 
 	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	fluxnotificationv1beta1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/fluxcd/notification/v1beta1"
 	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
 	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/logs"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/logger"
 )
@@ -57,11 +63,19 @@ func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = cartov1alpha1.AddToScheme(scheme)
 	_ = knativeservingv1.AddToScheme(scheme)
+	_ = fluxnotificationv1beta1.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
-	ctx := context.Background()
+	// interrupts cancel the context for commands to stop what they are doing, such as uploading the
+	// source code, a second interrupt exits right away
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 
 	p, err := plugin.NewPlugin(&tanzucliv1alpha1.PluginDescriptor{
 		Name:           "apps",
@@ -79,10 +93,15 @@ func main() {
 
 	// setup logs.Tail() for all commands using stern
 	ctx = logs.StashTailer(ctx, &logs.SternTailer{})
+	// setup logs.Fetch() for workload get --with-logs
+	ctx = logs.StashFetcher(ctx, &logs.KubernetesFetcher{})
+	// record the changes made to workloads in their last-modified-by annotation
+	ctx = commands.StashAuditor(ctx, commands.NewAuditor(buildinfo.Version))
 
 	c := cli.Initialize(fmt.Sprintf("tanzu %s", p.Cmd.Use), scheme)
 	p.AddCommands(
 		commands.NewClusterSupplyChainCommand(ctx, c),
+		commands.NewDeliverableCommand(ctx, c),
 		commands.NewWorkloadCommand(ctx, c),
 
 		// hidden commands
@@ -93,8 +112,15 @@ func main() {
 	// TODO can we normalize all of these flags?
 	p.Cmd.PersistentFlags().StringVar(&c.KubeConfigFile, cli.StripDash(flags.KubeConfigFlagName), "", "kubeconfig `file` (default is $HOME/.kube/config)")
 	p.Cmd.MarkFlagFilename(cli.StripDash(flags.KubeConfigFlagName))
+	p.Cmd.PersistentFlags().StringVar(&c.ViperConfigFile, cli.StripDash(flags.ConfigFlagName), "", "plugin config `file` (default is $HOME/.config/tanzu-apps/config.yaml, then $HOME/.config/tanzu/apps.yaml)")
+	p.Cmd.MarkFlagFilename(cli.StripDash(flags.ConfigFlagName))
 	p.Cmd.PersistentFlags().StringVar(&c.CurrentContext, cli.StripDash(flags.ContextFlagName), "", "`name` of the kubeconfig context to use (default is current-context defined by kubeconfig)")
+	p.Cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.ContextFlagName), completion.SuggestContexts(ctx, c))
 	p.Cmd.PersistentFlags().BoolVar(&color.NoColor, cli.StripDash(flags.NoColorFlagName), color.NoColor, "disable color output in terminals")
+	p.Cmd.PersistentFlags().StringVar(&c.ErrorFormat, cli.StripDash(flags.ErrorFormatFlagName), cli.ErrorFormatText, "`format` of the errors printed on stderr, one of text or json")
+	p.Cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.ErrorFormatFlagName), func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return cli.ErrorFormats, cobra.ShellCompDirectiveNoFileComp
+	})
 	p.Cmd.PersistentFlags().Int32VarP(c.Verbose, cli.StripDash(flags.VerboseLevelFlagName), "v", 1, "number for the log level verbosity")
 	if markHiddenErr := p.Cmd.LocalFlags().MarkHidden("azure-container-registry-config"); markHiddenErr != nil {
 		c.Eprintf("%s %s: %s\n", printer.Serrorf("Error:"), "Unable to hide plugin unused flags", markHiddenErr)
@@ -128,6 +154,16 @@ func main() {
 
 	p.Cmd.SilenceErrors = true
 	if err := p.Execute(); err != nil {
+		if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+			os.Exit(130)
+		}
+		if c.ErrorFormat == cli.ErrorFormatJSON {
+			// every error is printed for tools to parse, silent errors included
+			if printErr := c.PrintStructuredError(err); printErr != nil {
+				c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+			}
+			os.Exit(cli.ExitCode(err))
+		}
 		// silent errors should not log, but still exit with an error code
 		// typically the command has already been logged with more detail
 		if !errors.Is(err, cli.SilentError) {
@@ -141,6 +177,6 @@ func main() {
 				c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
 			}
 		}
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
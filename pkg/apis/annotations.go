@@ -17,3 +17,10 @@ limitations under the License.
 package apis
 
 const ServiceClaimAnnotationName = "serviceclaims.supplychain.apps.x-tanzu.vmware.com/extensions"
+
+// smoke checks run by the workload commands once the workload is ready, see `workload verify`
+const VerifyURLAnnotationName = "apps.tanzu.vmware.com/verify-url"
+const VerifyCommandAnnotationName = "apps.tanzu.vmware.com/verify-cmd"
+
+// LastModifiedByAnnotationName records who last created or updated the workload with the CLI, see `--audit`
+const LastModifiedByAnnotationName = "apps.tanzu.vmware.com/last-modified-by"
@@ -17,9 +17,49 @@ limitations under the License.
 package v1alpha1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
 )
 
 func (sc *ClusterSupplyChain) GetGroupVersionKind() schema.GroupVersionKind {
 	return SchemeGroupVersion.WithKind("ClusterSupplyChain")
 }
+
+// WorkloadTypes returns the workload types named by the selector of the supply chain, either as the
+// value of the workload-type label or as the values of an In requirement on it
+func (sc *ClusterSupplyChain) WorkloadTypes() []string {
+	types := sets.NewString()
+	if t, ok := sc.Spec.Selector[apis.WorkloadTypeLabelName]; ok && t != "" {
+		types.Insert(t)
+	}
+	for _, r := range sc.Spec.SelectorMatchExpressions {
+		if r.Key == apis.WorkloadTypeLabelName && r.Operator == metav1.LabelSelectorOpIn {
+			types.Insert(r.Values...)
+		}
+	}
+	return types.List()
+}
+
+// SelectsWorkloadType tells whether the requirements of the supply chain selector on the workload-type
+// label are met by a workload of the type. The requirements on other labels and fields are not
+// considered, a supply chain without requirements on the workload-type label selects any type.
+func (sc *ClusterSupplyChain) SelectsWorkloadType(workloadType string) bool {
+	selector := &metav1.LabelSelector{}
+	if t, ok := sc.Spec.Selector[apis.WorkloadTypeLabelName]; ok {
+		selector.MatchLabels = map[string]string{apis.WorkloadTypeLabelName: t}
+	}
+	for _, r := range sc.Spec.SelectorMatchExpressions {
+		if r.Key == apis.WorkloadTypeLabelName {
+			selector.MatchExpressions = append(selector.MatchExpressions, r)
+		}
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(labels.Set{apis.WorkloadTypeLabelName: workloadType})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+)
+
+func TestClusterSupplyChain_WorkloadTypes(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         SupplyChainSpec
+		types        []string
+		selectsWeb   bool
+		selectsOther bool
+	}{{
+		name: "selector",
+		spec: SupplyChainSpec{
+			Selector: map[string]string{apis.WorkloadTypeLabelName: "web"},
+		},
+		types:      []string{"web"},
+		selectsWeb: true,
+	}, {
+		name: "selector on other labels",
+		spec: SupplyChainSpec{
+			Selector: map[string]string{
+				apis.WorkloadTypeLabelName:        "web",
+				"apps.tanzu.vmware.com/has-tests": "true",
+			},
+		},
+		types:      []string{"web"},
+		selectsWeb: true,
+	}, {
+		name: "match expression in",
+		spec: SupplyChainSpec{
+			SelectorMatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      apis.WorkloadTypeLabelName,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{"worker", "web", "server"},
+			}},
+		},
+		types:      []string{"server", "web", "worker"},
+		selectsWeb: true,
+	}, {
+		name: "match expression not in",
+		spec: SupplyChainSpec{
+			SelectorMatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      apis.WorkloadTypeLabelName,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{"web"},
+			}},
+		},
+		types:        []string{},
+		selectsOther: true,
+	}, {
+		name: "no requirement on the type",
+		spec: SupplyChainSpec{
+			Selector: map[string]string{"apps.tanzu.vmware.com/has-tests": "true"},
+		},
+		types:        []string{},
+		selectsWeb:   true,
+		selectsOther: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sc := &ClusterSupplyChain{Spec: test.spec}
+			if diff := cmp.Diff(test.types, sc.WorkloadTypes()); diff != "" {
+				t.Errorf("WorkloadTypes() (-want, +got) = %v", diff)
+			}
+			if got := sc.SelectsWorkloadType("web"); got != test.selectsWeb {
+				t.Errorf("SelectsWorkloadType(web) = %t, want %t", got, test.selectsWeb)
+			}
+			if got := sc.SelectsWorkloadType("other"); got != test.selectsOther {
+				t.Errorf("SelectsWorkloadType(other) = %t, want %t", got, test.selectsOther)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	// DeliverableConfigMapKey is the data key holding the deliverable definition in a ConfigMap
+	// stamped by supply chains that hand off to a separate run cluster.
+	DeliverableConfigMapKey = "deliverable"
+)
+
+func (d *Deliverable) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind(DeliverableKind)
+}
+
+func (d *Deliverable) Load(in io.Reader) error {
+	if err := yaml.NewYAMLOrJSONDecoder(in, 4096).Decode(d); err != nil {
+		return err
+	}
+
+	if apiVersion, kind := SchemeGroupVersion.Identifier(), DeliverableKind; d.APIVersion != apiVersion || d.Kind != kind {
+		return fmt.Errorf("resource must have API Version %q and Kind %q", apiVersion, kind)
+	}
+	d.APIVersion = ""
+	d.Kind = ""
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeliverable_Load(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      *Deliverable
+		shouldErr bool
+	}{{
+		name: "loads deliverable",
+		input: `
+apiVersion: carto.run/v1alpha1
+kind: Deliverable
+metadata:
+  name: petclinic
+  labels:
+    app.kubernetes.io/part-of: petclinic
+spec:
+  source:
+    image: registry.example.com/petclinic-bundle:latest
+`,
+		want: &Deliverable{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "petclinic",
+				Labels: map[string]string{
+					"app.kubernetes.io/part-of": "petclinic",
+				},
+			},
+			Spec: DeliverableSpec{
+				Source: &Source{
+					Image: "registry.example.com/petclinic-bundle:latest",
+				},
+			},
+		},
+	}, {
+		name: "not a deliverable",
+		input: `
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: petclinic
+`,
+		shouldErr: true,
+	}, {
+		name:      "malformed",
+		input:     `{"apiVersion": `,
+		shouldErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := &Deliverable{}
+			err := got.Load(strings.NewReader(test.input))
+
+			if (err == nil) == test.shouldErr {
+				t.Errorf("Load() shouldErr %t %v", test.shouldErr, err)
+			} else if test.shouldErr {
+				return
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Load() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
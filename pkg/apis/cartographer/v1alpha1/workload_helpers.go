@@ -40,6 +40,19 @@ const (
 	WorkloadConditionReady  = "Ready"
 	WorkloadAnnotationParam = "annotations"
 	WorkloadMavenParam      = "maven"
+	// WorkloadPodAnnotationParam holds the annotations supply chains set on the pod template of the workload
+	WorkloadPodAnnotationParam = "pod-annotations"
+	// WorkloadPausedParam is set by workload pause, supply chains honoring it stop reconciling the workload
+	WorkloadPausedParam = "paused"
+	// WorkloadBuildCacheImageParam is the image the build caches its layers to between builds
+	WorkloadBuildCacheImageParam = "buildCacheImage"
+	// WorkloadClusterBuilderParam is the name of the ClusterBuilder building the source of the workload
+	WorkloadClusterBuilderParam = "clusterBuilder"
+	// WorkloadRunImageParam is the run image the built app image is based on
+	WorkloadRunImageParam = "runImage"
+	// WorkloadImagePullSecretsParam are the secrets the pods of the workload pull their images with, in the
+	// format of the imagePullSecrets of a pod spec
+	WorkloadImagePullSecretsParam = "imagePullSecrets"
 )
 
 type MavenSource struct {
@@ -149,6 +162,40 @@ func (w *WorkloadSpec) Validate() validation.FieldErrors {
 		}
 	}
 	errs = errs.Also(w.ValidateMavenSource())
+	errs = errs.Also(w.ValidateBuildParams())
+
+	return errs
+}
+
+// IsBuiltFromSource tells whether the supply chain builds the workload from git, a source image or a
+// maven artifact, rather than deploying a pre-built image
+func (w *WorkloadSpec) IsBuiltFromSource() bool {
+	if w.GetMavenSource() != nil {
+		return true
+	}
+	return w.Source != nil && (w.Source.Git != nil || w.Source.Image != "")
+}
+
+// ValidateBuildParams rejects the params configuring the build of the source on workloads that are not
+// built from source, the supply chain would silently ignore them
+func (w *WorkloadSpec) ValidateBuildParams() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	if w.IsBuiltFromSource() {
+		return errs
+	}
+
+	for _, p := range []struct {
+		param string
+		flag  string
+	}{
+		{param: WorkloadBuildCacheImageParam, flag: flags.BuildCacheImageFlagName},
+		{param: WorkloadClusterBuilderParam, flag: flags.BuilderFlagName},
+		{param: WorkloadRunImageParam, flag: flags.RunImageFlagName},
+	} {
+		if w.HasParam(p.param) {
+			errs = errs.Also(validation.ErrForbiddenFieldWithDetail(p.flag, fmt.Sprintf("only applies to workloads built from source, set with %s, %s, %s or %s", flags.GitRepoFlagName, flags.SourceImageFlagName, flags.LocalPathFlagName, flags.MavenArtifactFlagName)))
+		}
+	}
 
 	return errs
 }
@@ -265,21 +312,98 @@ func (w *WorkloadSpec) GetParam(key string, value interface{}) {
 	}
 }
 
+// HasParam tells whether the param is set on the workload
+func (w *WorkloadSpec) HasParam(key string) bool {
+	for _, p := range w.Params {
+		if p.Name == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *WorkloadSpec) IsPaused() bool {
+	paused := false
+	w.GetParam(WorkloadPausedParam, &paused)
+	return paused
+}
+
+func (w *WorkloadSpec) MergePaused(paused bool) {
+	if paused {
+		w.MergeParams(WorkloadPausedParam, true)
+	} else {
+		w.RemoveParam(WorkloadPausedParam)
+	}
+}
+
+// GetImagePullSecrets returns the image pull secrets set in the params of the workload
+func (w *WorkloadSpec) GetImagePullSecrets() []corev1.LocalObjectReference {
+	secrets := []corev1.LocalObjectReference{}
+	w.GetParam(WorkloadImagePullSecretsParam, &secrets)
+	return secrets
+}
+
+// MergeImagePullSecret appends the secret to the image pull secrets of the workload, unless it is
+// already one of them
+func (w *WorkloadSpec) MergeImagePullSecret(name string) {
+	secrets := w.GetImagePullSecrets()
+	for _, secret := range secrets {
+		if secret.Name == name {
+			return
+		}
+	}
+	w.MergeParams(WorkloadImagePullSecretsParam, append(secrets, corev1.LocalObjectReference{Name: name}))
+}
+
+// RemoveImagePullSecret removes the secret from the image pull secrets of the workload, the param is
+// removed along with the last secret
+func (w *WorkloadSpec) RemoveImagePullSecret(name string) {
+	secrets := []corev1.LocalObjectReference{}
+	for _, secret := range w.GetImagePullSecrets() {
+		if secret.Name != name {
+			secrets = append(secrets, secret)
+		}
+	}
+	if len(secrets) == 0 {
+		w.RemoveParam(WorkloadImagePullSecretsParam)
+		return
+	}
+	w.MergeParams(WorkloadImagePullSecretsParam, secrets)
+}
+
 func (w *WorkloadSpec) MergeAnnotationParams(key string, value string) {
-	annotations := make(map[string]string)
-	w.GetParam(WorkloadAnnotationParam, &annotations)
-	annotations[key] = value
-	w.MergeParams(WorkloadAnnotationParam, annotations)
+	w.mergeMapParam(WorkloadAnnotationParam, key, value)
 }
 
 func (w *WorkloadSpec) RemoveAnnotationParams(name string) {
-	annotations := make(map[string]string)
-	w.GetParam(WorkloadAnnotationParam, &annotations)
-	delete(annotations, name)
-	if len(annotations) == 0 {
-		w.RemoveParam(WorkloadAnnotationParam)
+	w.removeMapParam(WorkloadAnnotationParam, name)
+}
+
+func (w *WorkloadSpec) MergePodAnnotationParams(key string, value string) {
+	w.mergeMapParam(WorkloadPodAnnotationParam, key, value)
+}
+
+func (w *WorkloadSpec) RemovePodAnnotationParams(name string) {
+	w.removeMapParam(WorkloadPodAnnotationParam, name)
+}
+
+// mergeMapParam sets a key of a param holding a map of strings
+func (w *WorkloadSpec) mergeMapParam(param, key, value string) {
+	values := make(map[string]string)
+	w.GetParam(param, &values)
+	values[key] = value
+	w.MergeParams(param, values)
+}
+
+// removeMapParam deletes a key of a param holding a map of strings, the param is removed with its last key
+func (w *WorkloadSpec) removeMapParam(param, key string) {
+	values := make(map[string]string)
+	w.GetParam(param, &values)
+	delete(values, key)
+	if len(values) == 0 {
+		w.RemoveParam(param)
 	} else {
-		w.MergeParams(WorkloadAnnotationParam, annotations)
+		w.MergeParams(param, values)
 	}
 }
 
@@ -350,13 +474,26 @@ func (w *WorkloadSpec) MergeImage(image string) {
 }
 
 func (w *WorkloadSpec) MergeEnv(env corev1.EnvVar) {
-	for i := range w.Env {
-		if w.Env[i].Name == env.Name {
-			w.Env[i] = env
-			return
+	w.Env = mergeEnvVar(w.Env, env)
+}
+
+// mergeEnvVar replaces the first variable with the name of env, or appends env. Other variables with the
+// same name, from a file that repeats it, are dropped so the value of env is the only one left.
+func mergeEnvVar(vars []corev1.EnvVar, env corev1.EnvVar) []corev1.EnvVar {
+	merged := []corev1.EnvVar{}
+	found := false
+	for i := range vars {
+		if vars[i].Name != env.Name {
+			merged = append(merged, vars[i])
+		} else if !found {
+			merged = append(merged, env)
+			found = true
 		}
 	}
-	w.Env = append(w.Env, env)
+	if !found {
+		merged = append(merged, env)
+	}
+	return merged
 }
 
 func (w *WorkloadSpec) RemoveEnv(name string) {
@@ -394,6 +531,38 @@ func (w *WorkloadSpec) MergeResources(r *corev1.ResourceRequirements) {
 	}
 }
 
+// RemoveResourceLimit removes the limit of the named resource, the resources are unset once no
+// limit or request is left
+func (w *WorkloadSpec) RemoveResourceLimit(name corev1.ResourceName) {
+	if w.Resources == nil {
+		return
+	}
+	delete(w.Resources.Limits, name)
+	w.pruneResources()
+}
+
+// RemoveResourceRequest removes the request of the named resource, the resources are unset once no
+// limit or request is left
+func (w *WorkloadSpec) RemoveResourceRequest(name corev1.ResourceName) {
+	if w.Resources == nil {
+		return
+	}
+	delete(w.Resources.Requests, name)
+	w.pruneResources()
+}
+
+func (w *WorkloadSpec) pruneResources() {
+	if len(w.Resources.Limits) == 0 {
+		w.Resources.Limits = nil
+	}
+	if len(w.Resources.Requests) == 0 {
+		w.Resources.Requests = nil
+	}
+	if w.Resources.Limits == nil && w.Resources.Requests == nil {
+		w.Resources = nil
+	}
+}
+
 func (w *Workload) MergeAnnotations(key, value string) {
 	if w.Annotations == nil {
 		w.Annotations = map[string]string{}
@@ -461,6 +630,27 @@ func (w *WorkloadSpec) MergeServiceClaim(sc WorkloadServiceClaim) {
 	w.ServiceClaims = append(w.ServiceClaims, sc)
 }
 
+// ReplaceServiceClaims sets the service claims of the workload to claims, the claims that are not part of claims
+// are deleted along with their entry in the service claims annotation
+func (w *Workload) ReplaceServiceClaims(claims []WorkloadServiceClaim) {
+	names := map[string]bool{}
+	for _, sc := range claims {
+		names[sc.Name] = true
+	}
+	for _, sc := range w.Spec.ServiceClaims {
+		if !names[sc.Name] {
+			w.Spec.DeleteServiceClaim(sc.Name)
+			w.DeleteServiceClaimAnnotation(sc.Name)
+		}
+	}
+	for _, sc := range claims {
+		w.Spec.MergeServiceClaim(sc)
+	}
+	if len(w.Spec.ServiceClaims) == 0 {
+		w.Spec.ServiceClaims = nil
+	}
+}
+
 func NewServiceClaim(name string, serviceRef corev1.ObjectReference) WorkloadServiceClaim {
 	return WorkloadServiceClaim{
 		Name: name,
@@ -492,13 +682,7 @@ func (w *WorkloadSpec) MergeBuildEnv(env corev1.EnvVar) {
 	if w.Build == nil {
 		w.Build = &WorkloadBuild{}
 	}
-	for i := range w.Build.Env {
-		if w.Build.Env[i].Name == env.Name {
-			w.Build.Env[i] = env
-			return
-		}
-	}
-	w.Build.Env = append(w.Build.Env, env)
+	w.Build.Env = mergeEnvVar(w.Build.Env, env)
 }
 
 func WorkloadReadyConditionFunc(target client.Object) (bool, error) {
@@ -522,6 +706,44 @@ func WorkloadReadyConditionFunc(target client.Object) (bool, error) {
 	return false, nil
 }
 
+// WorkloadConditionFunc generalizes WorkloadReadyConditionFunc to any condition type of the workload, or of
+// the resource of its supply chain with the name when resource is set. The condition is met once it has the
+// status, a condition expected to be True that becomes False fails.
+func WorkloadConditionFunc(resource, conditionType string, status metav1.ConditionStatus) func(client.Object) (bool, error) {
+	return func(target client.Object) (bool, error) {
+		obj, ok := target.(*Workload)
+		if !ok {
+			return false, nil
+		}
+		if obj.Generation != obj.Status.ObservedGeneration {
+			return false, nil
+		}
+		conditions := obj.Status.Conditions
+		subject := "workload"
+		if resource != "" {
+			conditions = nil
+			subject = fmt.Sprintf("resource %q", resource)
+			for _, r := range obj.Status.Resources {
+				if r.Name == resource {
+					conditions = r.Conditions
+				}
+			}
+		}
+		for _, cond := range conditions {
+			if cond.Type != conditionType {
+				continue
+			}
+			if cond.Status == status {
+				return true, nil
+			}
+			if status == metav1.ConditionTrue && cond.Status == metav1.ConditionFalse {
+				return true, fmt.Errorf("Condition %s of %s is False: %s", conditionType, subject, cond.Message)
+			}
+		}
+		return false, nil
+	}
+}
+
 func (w *Workload) DeprecationWarnings() []string {
 	warnings := []string{}
 	var serviceClaimDeprecationWarningMsg = "Cross namespace service claims are deprecated. Please use `tanzu service claim create` instead."
@@ -447,6 +447,91 @@ func TestWorkload_Validate(t *testing.T) {
 			},
 		},
 		want: validation.ErrMissingField(flags.MavenVersionFlagName),
+	}, {
+		name: "build params with git",
+		workload: Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-workload",
+				Namespace: "default",
+			},
+			Spec: WorkloadSpec{
+				Params: []Param{{
+					Name:  WorkloadClusterBuilderParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`"my-builder"`)},
+				}, {
+					Name:  WorkloadRunImageParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`"registry.example/run:bionic"`)},
+				}},
+				Source: &Source{
+					Git: &GitSource{
+						URL: "git@github.com/example/repo.git",
+						Ref: GitRef{
+							Branch: "main",
+						},
+					},
+				},
+			},
+		},
+		want: validation.FieldErrors{},
+	}, {
+		name: "build params with maven",
+		workload: Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-workload",
+				Namespace: "default",
+			},
+			Spec: WorkloadSpec{
+				Params: []Param{{
+					Name:  WorkloadMavenParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"foo","groupId":"bar","version":"0.1.1"}`)},
+				}, {
+					Name:  WorkloadBuildCacheImageParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`"registry.example/cache"`)},
+				}},
+			},
+		},
+		want: validation.FieldErrors{},
+	}, {
+		name: "build params with pre-built image",
+		workload: Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-workload",
+				Namespace: "default",
+			},
+			Spec: WorkloadSpec{
+				Params: []Param{{
+					Name:  WorkloadBuildCacheImageParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`"registry.example/cache"`)},
+				}, {
+					Name:  WorkloadClusterBuilderParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`"my-builder"`)},
+				}},
+				Image: "ubuntu:bionic",
+			},
+		},
+		want: validation.FieldErrors{}.Also(
+			validation.ErrForbiddenFieldWithDetail(flags.BuildCacheImageFlagName, "only applies to workloads built from source, set with --git-repo, --source-image, --local-path or --maven-artifact"),
+			validation.ErrForbiddenFieldWithDetail(flags.BuilderFlagName, "only applies to workloads built from source, set with --git-repo, --source-image, --local-path or --maven-artifact"),
+		),
+	}, {
+		name: "run image with subPath only",
+		workload: Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-workload",
+				Namespace: "default",
+			},
+			Spec: WorkloadSpec{
+				Params: []Param{{
+					Name:  WorkloadRunImageParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`"registry.example/run:bionic"`)},
+				}},
+				Source: &Source{
+					Subpath: "app",
+				},
+				Image: "ubuntu:bionic",
+			},
+		},
+		want: validation.ErrForbiddenFieldWithDetail(flags.RunImageFlagName, "only applies to workloads built from source, set with --git-repo, --source-image, --local-path or --maven-artifact"),
 	}}
 
 	for _, test := range tests {
@@ -868,6 +953,192 @@ func TestWorkloadSpec_RemoveParam(t *testing.T) {
 	}
 }
 
+func TestWorkloadSpec_MergePaused(t *testing.T) {
+	tests := []struct {
+		name   string
+		seed   *WorkloadSpec
+		paused bool
+		want   *WorkloadSpec
+	}{{
+		name:   "pause",
+		seed:   &WorkloadSpec{},
+		paused: true,
+		want: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  WorkloadPausedParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`true`)},
+				},
+			},
+		},
+	}, {
+		name: "resume",
+		seed: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  "foo",
+					Value: apiextensionsv1.JSON{Raw: []byte(`"bar"`)},
+				},
+				{
+					Name:  WorkloadPausedParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`true`)},
+				},
+			},
+		},
+		want: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  "foo",
+					Value: apiextensionsv1.JSON{Raw: []byte(`"bar"`)},
+				},
+			},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.seed
+			got.MergePaused(test.paused)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("MergePaused() (-want, +got) = %v", diff)
+			}
+			if expected, actual := test.paused, got.IsPaused(); expected != actual {
+				t.Errorf("IsPaused() expected %v, actually %v", expected, actual)
+			}
+		})
+	}
+}
+
+func TestWorkloadSpec_MergeImagePullSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		seed   *WorkloadSpec
+		secret string
+		want   *WorkloadSpec
+	}{{
+		name:   "add",
+		seed:   &WorkloadSpec{},
+		secret: "registry-credentials",
+		want: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  WorkloadImagePullSecretsParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`[{"name":"registry-credentials"}]`)},
+				},
+			},
+		},
+	}, {
+		name: "append",
+		seed: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  WorkloadImagePullSecretsParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`[{"name":"registry-credentials"}]`)},
+				},
+			},
+		},
+		secret: "registry-token",
+		want: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  WorkloadImagePullSecretsParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`[{"name":"registry-credentials"},{"name":"registry-token"}]`)},
+				},
+			},
+		},
+	}, {
+		name: "already present",
+		seed: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  WorkloadImagePullSecretsParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`[{"name":"registry-credentials"}]`)},
+				},
+			},
+		},
+		secret: "registry-credentials",
+		want: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  WorkloadImagePullSecretsParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`[{"name":"registry-credentials"}]`)},
+				},
+			},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.seed
+			got.MergeImagePullSecret(test.secret)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("MergeImagePullSecret() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestWorkloadSpec_RemoveImagePullSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		seed   *WorkloadSpec
+		secret string
+		want   *WorkloadSpec
+	}{{
+		name: "remove",
+		seed: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  WorkloadImagePullSecretsParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`[{"name":"registry-credentials"},{"name":"registry-token"}]`)},
+				},
+			},
+		},
+		secret: "registry-credentials",
+		want: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  WorkloadImagePullSecretsParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`[{"name":"registry-token"}]`)},
+				},
+			},
+		},
+	}, {
+		name: "remove last",
+		seed: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  "foo",
+					Value: apiextensionsv1.JSON{Raw: []byte(`"bar"`)},
+				},
+				{
+					Name:  WorkloadImagePullSecretsParam,
+					Value: apiextensionsv1.JSON{Raw: []byte(`[{"name":"registry-credentials"}]`)},
+				},
+			},
+		},
+		secret: "registry-credentials",
+		want: &WorkloadSpec{
+			Params: []Param{
+				{
+					Name:  "foo",
+					Value: apiextensionsv1.JSON{Raw: []byte(`"bar"`)},
+				},
+			},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.seed
+			got.RemoveImagePullSecret(test.secret)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("RemoveImagePullSecret() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
 func TestWorkloadSpec_MergeAnnotationParams(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -1021,6 +1292,49 @@ func TestWorkloadSpec_RemoveAnnotationParams(t *testing.T) {
 	}
 }
 
+func TestWorkloadSpec_PodAnnotationParams(t *testing.T) {
+	got := &WorkloadSpec{
+		Params: []Param{
+			{
+				Name:  WorkloadAnnotationParam,
+				Value: apiextensionsv1.JSON{Raw: []byte(`{"foo":"bar"}`)},
+			},
+		},
+	}
+
+	got.MergePodAnnotationParams("sidecar.istio.io/inject", "true")
+	got.MergePodAnnotationParams("foo", "baz")
+	want := &WorkloadSpec{
+		Params: []Param{
+			{
+				Name:  WorkloadAnnotationParam,
+				Value: apiextensionsv1.JSON{Raw: []byte(`{"foo":"bar"}`)},
+			},
+			{
+				Name:  WorkloadPodAnnotationParam,
+				Value: apiextensionsv1.JSON{Raw: []byte(`{"foo":"baz","sidecar.istio.io/inject":"true"}`)},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("MergePodAnnotationParams() (-want, +got) = %v", diff)
+	}
+
+	got.RemovePodAnnotationParams("foo")
+	got.RemovePodAnnotationParams("sidecar.istio.io/inject")
+	want = &WorkloadSpec{
+		Params: []Param{
+			{
+				Name:  WorkloadAnnotationParam,
+				Value: apiextensionsv1.JSON{Raw: []byte(`{"foo":"bar"}`)},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RemovePodAnnotationParams() (-want, +got) = %v", diff)
+	}
+}
+
 func TestWorkloadSpec_MergeMavenSource(t *testing.T) {
 	temp := "jar"
 	tests := []struct {
@@ -1356,6 +1670,22 @@ func TestWorkloadSpec_MergeEnv(t *testing.T) {
 				{Name: "NAME", Value: "replace"},
 			},
 		},
+	}, {
+		name: "replace duplicates",
+		seed: &WorkloadSpec{
+			Env: []corev1.EnvVar{
+				{Name: "NAME", Value: "initial"},
+				{Name: "FOO", Value: "foo"},
+				{Name: "NAME", Value: "duplicate"},
+			},
+		},
+		env: corev1.EnvVar{Name: "NAME", Value: "replace"},
+		want: &WorkloadSpec{
+			Env: []corev1.EnvVar{
+				{Name: "NAME", Value: "replace"},
+				{Name: "FOO", Value: "foo"},
+			},
+		},
 	}}
 
 	for _, test := range tests {
@@ -1510,6 +1840,138 @@ func TestWorkloadSpec_MergeResources(t *testing.T) {
 	}
 }
 
+func TestWorkloadSpec_RemoveResourceLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		seed     *WorkloadSpec
+		resource corev1.ResourceName
+		want     *WorkloadSpec
+	}{{
+		name:     "no resources",
+		seed:     &WorkloadSpec{},
+		resource: "nvidia.com/gpu",
+		want:     &WorkloadSpec{},
+	}, {
+		name: "found",
+		seed: &WorkloadSpec{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("1"),
+					"nvidia.com/gpu":   resource.MustParse("1"),
+				},
+			},
+		},
+		resource: "nvidia.com/gpu",
+		want: &WorkloadSpec{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("1"),
+				},
+			},
+		},
+	}, {
+		name: "last limit",
+		seed: &WorkloadSpec{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					"nvidia.com/gpu": resource.MustParse("1"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("500m"),
+				},
+			},
+		},
+		resource: "nvidia.com/gpu",
+		want: &WorkloadSpec{
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("500m"),
+				},
+			},
+		},
+	}, {
+		name: "last resource",
+		seed: &WorkloadSpec{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					"nvidia.com/gpu": resource.MustParse("1"),
+				},
+			},
+		},
+		resource: "nvidia.com/gpu",
+		want:     &WorkloadSpec{},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.seed
+			got.RemoveResourceLimit(test.resource)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("RemoveResourceLimit() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestWorkloadSpec_RemoveResourceRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		seed     *WorkloadSpec
+		resource corev1.ResourceName
+		want     *WorkloadSpec
+	}{{
+		name:     "no resources",
+		seed:     &WorkloadSpec{},
+		resource: "nvidia.com/gpu",
+		want:     &WorkloadSpec{},
+	}, {
+		name: "found",
+		seed: &WorkloadSpec{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					"nvidia.com/gpu": resource.MustParse("1"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("500m"),
+					"nvidia.com/gpu":   resource.MustParse("1"),
+				},
+			},
+		},
+		resource: "nvidia.com/gpu",
+		want: &WorkloadSpec{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					"nvidia.com/gpu": resource.MustParse("1"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("500m"),
+				},
+			},
+		},
+	}, {
+		name: "last resource",
+		seed: &WorkloadSpec{
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					"nvidia.com/gpu": resource.MustParse("1"),
+				},
+			},
+		},
+		resource: "nvidia.com/gpu",
+		want:     &WorkloadSpec{},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.seed
+			got.RemoveResourceRequest(test.resource)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("RemoveResourceRequest() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
 func TestWorkloadSpec_MergeServiceClaim(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1900,6 +2362,124 @@ func TestWorkloadReadyConditionFunc(t *testing.T) {
 	}
 }
 
+func TestWorkloadConditionFunc(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+	workload := &Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: defaultNamespace,
+			Name:      workloadName,
+		},
+		Status: WorkloadStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   WorkloadConditionReady,
+					Status: metav1.ConditionUnknown,
+				},
+				{
+					Type:   "SupplyChainReady",
+					Status: metav1.ConditionTrue,
+				},
+				{
+					Type:    "ResourcesSubmitted",
+					Status:  metav1.ConditionFalse,
+					Message: "template error",
+				},
+			},
+			Resources: []RealizedResource{
+				{
+					Name: "deliverable",
+					Conditions: []metav1.Condition{
+						{
+							Type:   WorkloadConditionReady,
+							Status: metav1.ConditionTrue,
+						},
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name          string
+		resource      string
+		conditionType string
+		status        metav1.ConditionStatus
+		workload      *Workload
+		err           error
+		expected      bool
+	}{{
+		name:          "true status",
+		conditionType: "SupplyChainReady",
+		status:        metav1.ConditionTrue,
+		workload:      workload,
+		expected:      true,
+	}, {
+		name:          "unknown status",
+		conditionType: WorkloadConditionReady,
+		status:        metav1.ConditionTrue,
+		workload:      workload,
+	}, {
+		name:          "expected unknown status",
+		conditionType: WorkloadConditionReady,
+		status:        metav1.ConditionUnknown,
+		workload:      workload,
+		expected:      true,
+	}, {
+		name:          "false status",
+		conditionType: "ResourcesSubmitted",
+		status:        metav1.ConditionTrue,
+		workload:      workload,
+		expected:      true,
+		err:           fmt.Errorf("Condition ResourcesSubmitted of workload is False: template error"),
+	}, {
+		name:          "expected false status",
+		conditionType: "ResourcesSubmitted",
+		status:        metav1.ConditionFalse,
+		workload:      workload,
+		expected:      true,
+	}, {
+		name:          "missing condition",
+		conditionType: "ResourcesHealthy",
+		status:        metav1.ConditionTrue,
+		workload:      workload,
+	}, {
+		name:          "resource condition",
+		resource:      "deliverable",
+		conditionType: WorkloadConditionReady,
+		status:        metav1.ConditionTrue,
+		workload:      workload,
+		expected:      true,
+	}, {
+		name:          "missing resource",
+		resource:      "config-writer",
+		conditionType: WorkloadConditionReady,
+		status:        metav1.ConditionTrue,
+		workload:      workload,
+	}, {
+		name:          "wrong generation",
+		conditionType: "SupplyChainReady",
+		status:        metav1.ConditionTrue,
+		workload: &Workload{
+			Status: WorkloadStatus{
+				ObservedGeneration: 10,
+				Conditions:         workload.Status.Conditions,
+			},
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actualBool, err := WorkloadConditionFunc(test.resource, test.conditionType, test.status)(test.workload)
+
+			if expected, actual := fmt.Sprintf("%s", test.err), fmt.Sprintf("%s", err); expected != actual {
+				t.Errorf("expected error %v, actually %v", expected, actual)
+			}
+			if test.expected != actualBool {
+				t.Errorf("expected bool value %v, actually %v", test.expected, actualBool)
+			}
+		})
+	}
+}
+
 func TestMergeServiceClaimAnnotation(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -1982,6 +2562,104 @@ func TestDeleteServiceClaimAnnotation(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkload_ReplaceServiceClaims(t *testing.T) {
+	database := WorkloadServiceClaim{
+		Name: "database",
+		Ref: &WorkloadServiceClaimReference{
+			APIVersion: "services.tanzu.vmware.com/v1alpha1",
+			Kind:       "PostgreSQL",
+			Name:       "my-prod-db",
+		},
+	}
+	cache := WorkloadServiceClaim{
+		Name: "cache",
+		Ref: &WorkloadServiceClaimReference{
+			APIVersion: "services.tanzu.vmware.com/v1alpha1",
+			Kind:       "Redis",
+			Name:       "my-cache",
+		},
+	}
+	updatedDatabase := WorkloadServiceClaim{
+		Name: "database",
+		Ref: &WorkloadServiceClaimReference{
+			APIVersion: "services.tanzu.vmware.com/v1alpha1",
+			Kind:       "PostgreSQL",
+			Name:       "prod-db",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		seed   *Workload
+		claims []WorkloadServiceClaim
+		want   *Workload
+	}{{
+		name:   "add",
+		seed:   &Workload{},
+		claims: []WorkloadServiceClaim{database},
+		want: &Workload{
+			Spec: WorkloadSpec{
+				ServiceClaims: []WorkloadServiceClaim{database},
+			},
+		},
+	}, {
+		name: "update",
+		seed: &Workload{
+			Spec: WorkloadSpec{
+				ServiceClaims: []WorkloadServiceClaim{database, cache},
+			},
+		},
+		claims: []WorkloadServiceClaim{updatedDatabase, cache},
+		want: &Workload{
+			Spec: WorkloadSpec{
+				ServiceClaims: []WorkloadServiceClaim{updatedDatabase, cache},
+			},
+		},
+	}, {
+		name: "delete missing claims",
+		seed: &Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					apis.ServiceClaimAnnotationName: `{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"cache":{"namespace":"test-ns"},"database":{"namespace":"my-prod-ns"}}}}`,
+				},
+			},
+			Spec: WorkloadSpec{
+				ServiceClaims: []WorkloadServiceClaim{database, cache},
+			},
+		},
+		claims: []WorkloadServiceClaim{cache},
+		want: &Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					apis.ServiceClaimAnnotationName: `{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"cache":{"namespace":"test-ns"}}}}`,
+				},
+			},
+			Spec: WorkloadSpec{
+				ServiceClaims: []WorkloadServiceClaim{cache},
+			},
+		},
+	}, {
+		name: "delete all claims",
+		seed: &Workload{
+			Spec: WorkloadSpec{
+				ServiceClaims: []WorkloadServiceClaim{database, cache},
+			},
+		},
+		want: &Workload{},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.seed
+			got.ReplaceServiceClaims(test.claims)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ReplaceServiceClaims() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
 func TestWorkloadSpec_MergeBuildEnv(t *testing.T) {
 	tests := []struct {
 		name string
@@ -2034,6 +2712,26 @@ func TestWorkloadSpec_MergeBuildEnv(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "replace duplicates",
+		seed: &WorkloadSpec{
+			Build: &WorkloadBuild{
+				Env: []corev1.EnvVar{
+					{Name: "NAME", Value: "initial"},
+					{Name: "FOO", Value: "bar"},
+					{Name: "NAME", Value: "duplicate"},
+				},
+			},
+		},
+		env: corev1.EnvVar{Name: "NAME", Value: "replace"},
+		want: &WorkloadSpec{
+			Build: &WorkloadBuild{
+				Env: []corev1.EnvVar{
+					{Name: "NAME", Value: "replace"},
+					{Name: "FOO", Value: "bar"},
+				},
+			},
+		},
 	}}
 
 	for _, test := range tests {
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ReceiverKind        = "Receiver"
+	ReceiverWebhookPath = "/hook/"
+	GitHubReceiver      = "github"
+	GitLabReceiver      = "gitlab"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Receiver is the Schema for the receivers API
+type Receiver struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ReceiverSpec `json:"spec,omitempty"`
+	// +optional
+	Status ReceiverStatus `json:"status,omitempty"`
+}
+
+// ReceiverSpec defines the desired state of Receiver
+type ReceiverSpec struct {
+	// Type of webhook sender, used to determine
+	// the validation procedure and payload deserialization.
+	Type string `json:"type"`
+
+	// A list of events to handle,
+	// e.g. 'push' for GitHub or 'Push Hook' for GitLab.
+	// +optional
+	Events []string `json:"events,omitempty"`
+
+	// A list of resources to be notified about changes.
+	Resources []CrossNamespaceObjectReference `json:"resources"`
+
+	// Secret reference containing the token used
+	// to validate the payload authenticity
+	SecretRef LocalObjectReference `json:"secretRef"`
+
+	// This flag tells the controller to suspend subsequent events handling.
+	// Defaults to false.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// ReceiverStatus defines the observed state of Receiver
+type ReceiverStatus struct {
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Generated webhook URL in the format
+	// of '/hook/sha256sum(token+name+namespace)'.
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// CrossNamespaceObjectReference contains enough information to let you locate the
+// typed referenced object at cluster level
+type CrossNamespaceObjectReference struct {
+	// API version of the referent
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Kind of the referent
+	Kind string `json:"kind"`
+
+	// Name of the referent
+	Name string `json:"name"`
+
+	// Namespace of the referent
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// LocalObjectReference contains enough information to locate the referenced Kubernetes resource
+// object in the same namespace
+type LocalObjectReference struct {
+	// Name of the referent
+	Name string `json:"name"`
+}
+
+// GetWebhookPath returns the incoming webhook path for the given token.
+func (in *Receiver) GetWebhookPath(token string) string {
+	digest := sha256.Sum256([]byte(token + in.GetName() + in.GetNamespace()))
+	return fmt.Sprintf("%s%x", ReceiverWebhookPath, digest)
+}
+
+// +kubebuilder:object:root=true
+
+// ReceiverList contains a list of Receiver
+type ReceiverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Receiver `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&Receiver{},
+		&ReceiverList{},
+	)
+}
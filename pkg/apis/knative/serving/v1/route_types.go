@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const RouteConditionReady = "Ready"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+type Route struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +optional
+	Status RouteStatus `json:"status,omitempty"`
+}
+
+// RouteStatus communicates the observed state of the Route (from the controller).
+type RouteStatus struct {
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// URL holds the url that will distribute traffic over the provided traffic targets.
+	// It generally has the form http[s]://{route-name}.{route-namespace}.{cluster-level-suffix}
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Traffic holds the configured traffic distribution.
+	// These entries will always contain RevisionName references.
+	// +optional
+	Traffic []TrafficTarget `json:"traffic,omitempty"`
+}
+
+// TrafficTarget holds a single entry of the routing table for a Route.
+type TrafficTarget struct {
+	// Tag is optionally used to expose a dedicated url for referencing
+	// this target exclusively.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+	// RevisionName of a specific revision to which to send this portion of
+	// traffic.
+	// +optional
+	RevisionName string `json:"revisionName,omitempty"`
+	// LatestRevision may be optionally provided to indicate that the latest
+	// ready Revision of the Configuration should be used for this traffic
+	// target.
+	// +optional
+	LatestRevision *bool `json:"latestRevision,omitempty"`
+	// Percent indicates that percentage based routing should be used and
+	// the value indicates the percent of traffic that is be routed to this
+	// Revision or Configuration.
+	// +optional
+	Percent *int64 `json:"percent,omitempty"`
+	// URL displays the URL for accessing named traffic targets.
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RouteList is a list of Route resources
+type RouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Route `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&Route{},
+		&RouteList{},
+	)
+}
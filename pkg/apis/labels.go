@@ -19,3 +19,13 @@ package apis
 const AppPartOfLabelName = "app.kubernetes.io/part-of"
 const WorkloadTypeLabelName = "apps.tanzu.vmware.com/workload-type"
 const ComponentLabelName = "app.kubernetes.io/component"
+const OwnerLabelName = "apps.tanzu.vmware.com/owner"
+
+// PullRequestLabelName holds the number of the pull request a review workload was created for, so
+// the workloads of merged pull requests can be found and cleaned up
+const PullRequestLabelName = "apps.tanzu.vmware.com/pull-request"
+
+// KnativeVisibilityLabelName set to KnativeVisibilityClusterLocal keeps a Knative service reachable
+// from inside the cluster only
+const KnativeVisibilityLabelName = "networking.knative.dev/visibility"
+const KnativeVisibilityClusterLocal = "cluster-local"
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -30,8 +31,11 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/flowcontrol"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/retry"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/telemetry"
 )
 
 var (
@@ -41,6 +45,10 @@ var (
 	// The maximum number of tokens in the bucket is capped at 'burst'.
 	burst int     = 100
 	qps   float32 = 100
+
+	// retryBackoff is the wait before the first retry of a request failing with a transient
+	// error, it doubles after each retry
+	retryBackoff = 500 * time.Millisecond
 )
 
 type Client interface {
@@ -85,7 +93,11 @@ func (c *client) ToRESTMapper() (meta.RESTMapper, error) {
 
 func (c *client) Get(ctx context.Context, key crclient.ObjectKey, obj crclient.Object) error {
 	c.log.V(2).Info("API Request", "host", c.KubeRestConfig().Host, "key", key, "action", "Get")
-	err := c.Client().Get(ctx, key, obj)
+	start := time.Now()
+	err := c.retry(ctx, func() error {
+		return c.Client().Get(ctx, key, obj)
+	})
+	c.traceRequest(ctx, "Get", obj, start, err)
 	c.log.V(2).Info("Results", "object", obj)
 	c.logError(err)
 	return err
@@ -93,7 +105,11 @@ func (c *client) Get(ctx context.Context, key crclient.ObjectKey, obj crclient.O
 
 func (c *client) List(ctx context.Context, list crclient.ObjectList, opts ...crclient.ListOption) error {
 	c.log.V(2).Info("API Request", "host", c.KubeRestConfig().Host, "action", "List")
-	err := c.Client().List(ctx, list, opts...)
+	start := time.Now()
+	err := c.retry(ctx, func() error {
+		return c.Client().List(ctx, list, opts...)
+	})
+	c.traceRequest(ctx, "List", list, start, err)
 	c.log.V(2).Info("Results", "objects", list)
 	c.logError(err)
 	return err
@@ -101,7 +117,11 @@ func (c *client) List(ctx context.Context, list crclient.ObjectList, opts ...crc
 
 func (c *client) Create(ctx context.Context, obj crclient.Object, opts ...crclient.CreateOption) error {
 	c.log.V(2).Info("API Request", "host", c.KubeRestConfig().Host, "action", "Create", "object", obj)
-	err := c.Client().Create(ctx, obj, opts...)
+	start := time.Now()
+	err := c.retryWrite(ctx, func() error {
+		return c.Client().Create(ctx, obj, opts...)
+	})
+	c.traceRequest(ctx, "Create", obj, start, err)
 	c.log.V(2).Info("Results", "object", obj)
 	c.logError(err)
 	return err
@@ -109,14 +129,22 @@ func (c *client) Create(ctx context.Context, obj crclient.Object, opts ...crclie
 
 func (c *client) Delete(ctx context.Context, obj crclient.Object, opts ...crclient.DeleteOption) error {
 	c.log.V(2).Info("API Request", "host", c.KubeRestConfig().Host, "action", "Delete", "object", obj)
-	err := c.Client().Delete(ctx, obj, opts...)
+	start := time.Now()
+	err := c.retryWrite(ctx, func() error {
+		return c.Client().Delete(ctx, obj, opts...)
+	})
+	c.traceRequest(ctx, "Delete", obj, start, err)
 	c.logError(err)
 	return err
 }
 
 func (c *client) Update(ctx context.Context, obj crclient.Object, opts ...crclient.UpdateOption) error {
 	c.log.V(2).Info("API Request", "host", c.KubeRestConfig().Host, "action", "Update", "object", obj)
-	err := c.Client().Update(ctx, obj, opts...)
+	start := time.Now()
+	err := c.retryWrite(ctx, func() error {
+		return c.Client().Update(ctx, obj, opts...)
+	})
+	c.traceRequest(ctx, "Update", obj, start, err)
 	c.log.V(2).Info("Results", "object", obj)
 	c.logError(err)
 	return err
@@ -124,7 +152,11 @@ func (c *client) Update(ctx context.Context, obj crclient.Object, opts ...crclie
 
 func (c *client) Patch(ctx context.Context, obj crclient.Object, patch crclient.Patch, opts ...crclient.PatchOption) error {
 	c.log.V(2).Info("API Request", "host", c.KubeRestConfig().Host, "action", "Patch", "data", patch)
-	err := c.Client().Patch(ctx, obj, patch, opts...)
+	start := time.Now()
+	err := c.retryWrite(ctx, func() error {
+		return c.Client().Patch(ctx, obj, patch, opts...)
+	})
+	c.traceRequest(ctx, "Patch", obj, start, err)
 	c.log.V(2).Info("Results", "object", obj)
 	c.logError(err)
 	return err
@@ -132,12 +164,58 @@ func (c *client) Patch(ctx context.Context, obj crclient.Object, patch crclient.
 
 func (c *client) DeleteAllOf(ctx context.Context, obj crclient.Object, opts ...crclient.DeleteAllOfOption) error {
 	c.log.V(2).Info("API Request", "host", c.KubeRestConfig().Host, "action", "DeleteAllOf")
-	err := c.Client().DeleteAllOf(ctx, obj, opts...)
+	start := time.Now()
+	err := c.retryWrite(ctx, func() error {
+		return c.Client().DeleteAllOf(ctx, obj, opts...)
+	})
+	c.traceRequest(ctx, "DeleteAllOf", obj, start, err)
 	c.log.V(2).Info("Results", "object", obj)
 	c.logError(err)
 	return err
 }
 
+// retry repeats a read failing with a transient error (429 or 5xx) up to the configured number of
+// retries, with an exponential backoff
+func (c *client) retry(ctx context.Context, fn func() error) error {
+	return c.retryPolicy(ctx, retry.Policy{On: retry.TransientErrorClasses}, fn)
+}
+
+// retryWrite repeats a write only when the server rejected it without processing it (429 or 503),
+// a write failing after the server accepted it may have been applied already
+func (c *client) retryWrite(ctx context.Context, fn func() error) error {
+	return c.retryPolicy(ctx, retry.Policy{On: retry.TransientErrorClasses, If: retry.Rejected}, fn)
+}
+
+func (c *client) retryPolicy(ctx context.Context, policy retry.Policy, fn func() error) error {
+	if retry.IsDisabled(ctx) {
+		return fn()
+	}
+	policy.Retries = c.retries
+	policy.Backoff = retryBackoff
+	policy.Factor = 2
+	return policy.Do(ctx, fn, func(attempt int, class string, err error) {
+		c.log.V(1).Info("Retrying API request", "attempt", attempt, "class", class, "error", err.Error())
+	})
+}
+
+// traceRequest records the request as an event of the span of the context, when traced
+func (c *client) traceRequest(ctx context.Context, action string, obj runtime.Object, start time.Time, err error) {
+	span := telemetry.SpanFromContext(ctx)
+	if span == nil {
+		return
+	}
+	kind := fmt.Sprintf("%T", obj)
+	if gvk, gvkErr := apiutil.GVKForObject(obj, c.scheme); gvkErr == nil {
+		kind = gvk.Kind
+	}
+	attributes := []telemetry.Attribute{
+		telemetry.String("k8s.action", action),
+		telemetry.String("k8s.kind", kind),
+		telemetry.Duration("duration_ms", time.Since(start)),
+	}
+	span.AddEvent("API Request", append(attributes, telemetry.Error(err)...)...)
+}
+
 func (c *client) logError(err error) {
 	if err != nil && c.log.V(2).Enabled() {
 		c.log.V(2).Error(err, "API Error")
@@ -169,6 +247,23 @@ func NewClient(kubeConfigFile string, currentContext string, scheme *runtime.Sch
 	}
 }
 
+// NewClientWithRetries returns a client whose requests time out after requestTimeout, when not
+// zero, and are retried up to retries times when they fail with a transient error
+func NewClientWithRetries(kubeConfigFile string, currentContext string, scheme *runtime.Scheme, requestTimeout time.Duration, retries int) Client {
+	c := NewClient(kubeConfigFile, currentContext, scheme).(*client)
+	c.requestTimeout = requestTimeout
+	c.retries = retries
+	return c
+}
+
+// NewClientWithConnection returns a client with retries that reaches the API server through the
+// connection overrides
+func NewClientWithConnection(kubeConfigFile string, currentContext string, scheme *runtime.Scheme, requestTimeout time.Duration, retries int, connection ConnectionOptions) Client {
+	c := NewClientWithRetries(kubeConfigFile, currentContext, scheme, requestTimeout, retries).(*client)
+	c.connection = connection
+	return c
+}
+
 type client struct {
 	defaultNamespace string
 	kubeConfigFile   string
@@ -179,6 +274,9 @@ type client struct {
 	kubeClientset    *kubernetes.Clientset
 	client           crclient.Client
 	log              logr.Logger
+	requestTimeout   time.Duration
+	retries          int
+	connection       ConnectionOptions
 }
 
 func (c *client) lazyLoadKubeConfig() clientcmd.ClientConfig {
@@ -206,6 +304,7 @@ func (c *client) lazyLoadRestConfigOrDie() *rest.Config {
 			c.logError(err)
 			os.Exit(2)
 		}
+		c.connection.Apply(restConfig)
 		restConfig.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
 		c.restConfig = restConfig
 	}
@@ -222,7 +321,10 @@ func (c *client) lazyLoadKubernetesClientsetOrDie() *kubernetes.Clientset {
 
 func (c *client) lazyLoadClientOrDie() crclient.Client {
 	if c.client == nil {
-		restConfig := c.lazyLoadRestConfigOrDie()
+		// the timeout only applies to this client, watches started from the rest config are
+		// bounded by their own wait timeout
+		restConfig := rest.CopyConfig(c.lazyLoadRestConfigOrDie())
+		restConfig.Timeout = c.requestTimeout
 		client, err := crclient.New(restConfig, crclient.Options{Scheme: c.scheme})
 		if err != nil {
 			fmt.Printf("%s Unable to connect: connection refused. Confirm kubeconfig details and try again.\n", printer.Serrorf("Error:"))
@@ -18,15 +18,19 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	rtesting "github.com/vmware-labs/reconciler-runtime/testing"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/retry"
 	clitestingresource "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing/resource"
 )
 
@@ -158,3 +162,155 @@ func TestNewClientWithEnvVarKubeconfigPathWithColon(t *testing.T) {
 		t.Errorf("error durring get RESTMapper: %v", err)
 	}
 }
+
+func TestNewClientWithRetries(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clitestingresource.AddToScheme(scheme)
+
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	r := &clitestingresource.TestResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-namespace",
+			Name:      "my-resource",
+		},
+	}
+	key := types.NamespacedName{Namespace: "my-namespace", Name: "my-resource"}
+	ctx := context.TODO()
+
+	tests := []struct {
+		name          string
+		retries       int
+		err           error
+		failures      int
+		expectedErr   bool
+		expectedCalls int
+	}{{
+		name:          "transient error retried",
+		retries:       3,
+		err:           apierrs.NewTooManyRequests("slow down", 1),
+		failures:      2,
+		expectedCalls: 3,
+	}, {
+		name:          "retries exhausted",
+		retries:       1,
+		err:           apierrs.NewServiceUnavailable("unavailable"),
+		failures:      2,
+		expectedErr:   true,
+		expectedCalls: 2,
+	}, {
+		name:          "no retries",
+		retries:       0,
+		err:           apierrs.NewInternalError(fmt.Errorf("boom")),
+		failures:      1,
+		expectedErr:   true,
+		expectedCalls: 1,
+	}, {
+		name:          "not transient",
+		retries:       3,
+		err:           apierrs.NewForbidden(schema.GroupResource{}, "my-resource", fmt.Errorf("denied")),
+		failures:      1,
+		expectedErr:   true,
+		expectedCalls: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewClientWithRetries("testdata/.kube/config", "", scheme, time.Second, test.retries)
+			fakeClient := rtesting.NewFakeClient(scheme, r.DeepCopy())
+			calls := 0
+			fakeClient.AddReactor("get", "*", func(action rtesting.Action) (bool, runtime.Object, error) {
+				calls++
+				if calls <= test.failures {
+					return true, nil, test.err
+				}
+				return false, nil, nil
+			})
+			c.(*client).client = fakeClient
+
+			err := c.Get(ctx, key, &clitestingresource.TestResource{})
+			if (err != nil) != test.expectedErr {
+				t.Errorf("Get() expected error %t, got %v", test.expectedErr, err)
+			}
+			if calls != test.expectedCalls {
+				t.Errorf("Get() expected %d calls, got %d", test.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestNewClientWithRetriesWrites(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clitestingresource.AddToScheme(scheme)
+
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	r := &clitestingresource.TestResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-namespace",
+			Name:      "my-resource",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		err           error
+		expectedErr   bool
+		expectedCalls int
+	}{{
+		name:          "throttled write retried",
+		ctx:           context.TODO(),
+		err:           apierrs.NewTooManyRequests("slow down", 1),
+		expectedCalls: 2,
+	}, {
+		name:          "unavailable write retried",
+		ctx:           context.TODO(),
+		err:           apierrs.NewServiceUnavailable("unavailable"),
+		expectedCalls: 2,
+	}, {
+		name:          "accepted write not retried",
+		ctx:           context.TODO(),
+		err:           apierrs.NewInternalError(fmt.Errorf("boom")),
+		expectedErr:   true,
+		expectedCalls: 1,
+	}, {
+		name:          "timed out write not retried",
+		ctx:           context.TODO(),
+		err:           apierrs.NewServerTimeout(schema.GroupResource{}, "update", 1),
+		expectedErr:   true,
+		expectedCalls: 1,
+	}, {
+		name:          "retries disabled",
+		ctx:           retry.StashDisabled(context.TODO()),
+		err:           apierrs.NewTooManyRequests("slow down", 1),
+		expectedErr:   true,
+		expectedCalls: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewClientWithRetries("testdata/.kube/config", "", scheme, time.Second, 3)
+			fakeClient := rtesting.NewFakeClient(scheme, r.DeepCopy())
+			calls := 0
+			fakeClient.AddReactor("delete", "*", func(action rtesting.Action) (bool, runtime.Object, error) {
+				calls++
+				if calls == 1 {
+					return true, nil, test.err
+				}
+				return false, nil, nil
+			})
+			c.(*client).client = fakeClient
+
+			err := c.Delete(test.ctx, r.DeepCopy())
+			if (err != nil) != test.expectedErr {
+				t.Errorf("Delete() expected error %t, got %v", test.expectedErr, err)
+			}
+			if calls != test.expectedCalls {
+				t.Errorf("Delete() expected %d calls, got %d", test.expectedCalls, calls)
+			}
+		})
+	}
+}
@@ -22,21 +22,38 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/resource"
 
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
 )
 
-const defaultTanzuIgnoreFile = ".tanzuignore"
+const (
+	// DefaultsConfigKey holds the default values of flags in the plugin config, by flag name
+	DefaultsConfigKey = "defaults"
+	// ExcludeEnvVarsConfigKey lists the env vars of flags that are ignored
+	ExcludeEnvVarsConfigKey = "exclude-env-vars"
+)
+
+const (
+	defaultTanzuIgnoreFile = ".tanzuignore"
+	defaultViperConfigName = "apps"
+	defaultViperConfigDir  = "tanzu-apps"
+	defaultViperConfigFile = "config.yaml"
+	defaultRetries         = 3
+)
 
 type Config struct {
 	Name string
 	Client
 	Scheme          *runtime.Scheme
 	ViperConfigFile string
+	Viper           *viper.Viper
 	KubeConfigFile  string
 	CurrentContext  string
 	TanzuIgnoreFile string
@@ -46,6 +63,17 @@ type Config struct {
 	Stderr          io.Writer
 	Verbose         *int32
 	Builder         *resource.Builder
+	// RequestTimeout and Retries apply to the requests of the cluster clients
+	RequestTimeout time.Duration
+	Retries        int
+	// Connection overrides how the API server of the current context is reached, it is read
+	// from the plugin config
+	Connection ConnectionOptions
+	// ErrorFormat is the format of the error returned by the command, text or json
+	ErrorFormat string
+	// ContextClients, when set, are returned by ClientForContext instead of connecting to the
+	// named context
+	ContextClients map[string]Client
 }
 
 func NewDefaultConfig(name string, scheme *runtime.Scheme) *Config {
@@ -59,6 +87,9 @@ func NewDefaultConfig(name string, scheme *runtime.Scheme) *Config {
 		Stderr:          os.Stderr,
 		Verbose:         &v,
 		TanzuIgnoreFile: defaultTanzuIgnoreFile,
+		Viper:           viper.New(),
+		Retries:         defaultRetries,
+		ErrorFormat:     ErrorFormatText,
 	}
 }
 
@@ -120,6 +151,26 @@ func (c *Config) Eboldf(format string, a ...interface{}) (n int, err error) {
 	return printer.BoldColor.Fprintf(c.Stderr, format, a...)
 }
 
+// ClientForContext returns a client for another context within the same kube config, for
+// commands that need to reach a second cluster. The connection overrides only apply to the
+// current context, the other cluster is reached with its kubeconfig settings.
+func (c *Config) ClientForContext(context string) Client {
+	if client, ok := c.ContextClients[context]; ok {
+		return client
+	}
+	return NewClientWithRetries(c.KubeConfigFile, context, c.Scheme, c.RequestTimeout, c.Retries)
+}
+
+// FlagDefault returns the default value of a flag set in the plugin config, flags set on the command
+// line take precedence over it
+func (c *Config) FlagDefault(name string) (interface{}, bool) {
+	key := DefaultsConfigKey + "." + StripDash(name)
+	if c.Viper == nil || !c.Viper.IsSet(key) {
+		return nil, false
+	}
+	return c.Viper.Get(key), true
+}
+
 func Initialize(name string, scheme *runtime.Scheme) *Config {
 	c := NewDefaultConfig(name, scheme)
 
@@ -129,10 +180,50 @@ func Initialize(name string, scheme *runtime.Scheme) *Config {
 }
 
 func (c *Config) init() {
+	c.initViper()
+	c.initConnection()
 	if c.Client == nil {
-		c.Client = NewClient(c.KubeConfigFile, c.CurrentContext, c.Scheme)
+		c.Client = NewClientWithConnection(c.KubeConfigFile, c.CurrentContext, c.Scheme, c.RequestTimeout, c.Retries, c.Connection)
 	}
 	if c.Builder == nil {
 		c.Builder = resource.NewBuilder(c.Client)
 	}
 }
+
+// initViper loads the plugin config file. The file is optional, when not set explicitly it is
+// looked up as config.yaml within the tanzu-apps config directory, then as apps.yaml within the
+// tanzu config directory.
+func (c *Config) initViper() {
+	if c.ViperConfigFile != "" {
+		c.Viper.SetConfigFile(c.ViperConfigFile)
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return
+		}
+		pluginConfigFile := filepath.Join(home, ".config", defaultViperConfigDir, defaultViperConfigFile)
+		if _, err := os.Stat(pluginConfigFile); err == nil {
+			c.Viper.SetConfigFile(pluginConfigFile)
+		} else {
+			c.Viper.AddConfigPath(filepath.Join(home, ".config", "tanzu"))
+			c.Viper.SetConfigName(defaultViperConfigName)
+			c.Viper.SetConfigType("yaml")
+		}
+	}
+	if err := c.Viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			c.Eprintf("%s unable to read config file: %s\n", printer.Swarnf("Warning:"), err)
+		}
+	}
+}
+
+// initConnection reads the connection overrides from the plugin config, invalid overrides are
+// ignored with a warning
+func (c *Config) initConnection() {
+	connection, err := ConnectionOptionsFromConfig(c.Viper)
+	if err != nil {
+		c.Eprintf("%s %s, connecting with the kubeconfig settings\n", printer.Swarnf("Warning:"), err)
+		return
+	}
+	c.Connection = connection
+}
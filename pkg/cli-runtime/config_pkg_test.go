@@ -20,12 +20,14 @@ import (
 	"bytes"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/fatih/color"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -101,3 +103,147 @@ func TestInit(t *testing.T) {
 		t.Errorf("Expected c.Client tp be set, actually %v", c.Client)
 	}
 }
+
+func TestInit_Connection(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	scheme := runtime.NewScheme()
+	c := NewDefaultConfig("cli name", scheme)
+	output := &bytes.Buffer{}
+	c.Stdout = output
+	c.Stderr = output
+
+	c.KubeConfigFile = "testdata/.kube/config"
+	c.ViperConfigFile = "testdata/missing.yaml"
+	c.Viper.Set(ConnectionConfigKey, map[string]interface{}{"server": "https://127.0.0.1:16443", "tls-server-name": "api.cluster.internal"})
+	c.init()
+
+	if expected, actual := "https://127.0.0.1:16443", c.KubeRestConfig().Host; expected != actual {
+		t.Errorf("Expected host %q, actually %q", expected, actual)
+	}
+	if expected, actual := "api.cluster.internal", c.KubeRestConfig().TLSClientConfig.ServerName; expected != actual {
+		t.Errorf("Expected tls server name %q, actually %q", expected, actual)
+	}
+}
+
+func TestInit_InvalidConnection(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	scheme := runtime.NewScheme()
+	c := NewDefaultConfig("cli name", scheme)
+	output := &bytes.Buffer{}
+	c.Stdout = output
+	c.Stderr = output
+
+	c.Viper.Set(ConnectionConfigKey, map[string]interface{}{"server": "127.0.0.1:16443"})
+	c.initConnection()
+
+	if !c.Connection.IsZero() {
+		t.Errorf("Expected no connection overrides, actually %v", c.Connection)
+	}
+	expected := "Warning: invalid connection server \"127.0.0.1:16443\", expected an http or https URL, connecting with the kubeconfig settings\n"
+	if diff := cmp.Diff(expected, output.String()); diff != "" {
+		t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+	}
+}
+
+func TestInitViper(t *testing.T) {
+	scheme := runtime.NewScheme()
+	c := NewDefaultConfig("cli name", scheme)
+	output := &bytes.Buffer{}
+	c.Stdout = output
+	c.Stderr = output
+
+	c.ViperConfigFile = "testdata/apps.yaml"
+	c.initViper()
+
+	if diff := cmp.Diff([]string{"kapp.k14s.io/"}, c.Viper.GetStringSlice("label-prefix-guard")); diff != "" {
+		t.Errorf("Unexpected config value (-expected, +actual): %s", diff)
+	}
+	if diff := cmp.Diff("", strings.TrimSpace(output.String())); diff != "" {
+		t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+	}
+}
+
+func TestInitViper_Home(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		guard []string
+	}{{
+		name:  "no config file",
+		files: map[string]string{},
+	}, {
+		name: "tanzu config directory",
+		files: map[string]string{
+			filepath.Join(".config", "tanzu", "apps.yaml"): "label-prefix-guard: [kapp.k14s.io/]\n",
+		},
+		guard: []string{"kapp.k14s.io/"},
+	}, {
+		name: "tanzu-apps config directory",
+		files: map[string]string{
+			filepath.Join(".config", "tanzu-apps", "config.yaml"): "label-prefix-guard: [carto.run/]\n",
+		},
+		guard: []string{"carto.run/"},
+	}, {
+		name: "tanzu-apps config directory takes precedence",
+		files: map[string]string{
+			filepath.Join(".config", "tanzu", "apps.yaml"):        "label-prefix-guard: [kapp.k14s.io/]\n",
+			filepath.Join(".config", "tanzu-apps", "config.yaml"): "label-prefix-guard: [carto.run/]\n",
+		},
+		guard: []string{"carto.run/"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			for name, content := range test.files {
+				path := filepath.Join(home, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("unexpected error creating the config directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("unexpected error writing the config file: %v", err)
+				}
+			}
+
+			scheme := runtime.NewScheme()
+			c := NewDefaultConfig("cli name", scheme)
+			output := &bytes.Buffer{}
+			c.Stdout = output
+			c.Stderr = output
+			c.initViper()
+
+			if diff := cmp.Diff(test.guard, c.Viper.GetStringSlice("label-prefix-guard"), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected config value (-expected, +actual): %s", diff)
+			}
+			if diff := cmp.Diff("", output.String()); diff != "" {
+				t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
+
+func TestInitViper_Missing(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	scheme := runtime.NewScheme()
+	c := NewDefaultConfig("cli name", scheme)
+	output := &bytes.Buffer{}
+	c.Stdout = output
+	c.Stderr = output
+
+	c.ViperConfigFile = "testdata/missing.yaml"
+	c.initViper()
+
+	if !strings.HasPrefix(output.String(), "Warning: unable to read config file:") {
+		t.Errorf("Expected warning, actually %q", output.String())
+	}
+}
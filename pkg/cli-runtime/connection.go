@@ -0,0 +1,117 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/spf13/viper"
+	"k8s.io/client-go/rest"
+)
+
+// ConnectionConfigKey is the plugin config key holding the overrides of how the API server of
+// the current context is reached
+const ConnectionConfigKey = "connection"
+
+// ConnectionOptions override how the API server is reached, for clusters only reachable through
+// a bastion or an SSH tunnel at another address than the one in the kubeconfig
+type ConnectionOptions struct {
+	// Server replaces the API server URL of the kubeconfig context
+	Server string `mapstructure:"server"`
+	// TLSServerName is sent with SNI and checked against the server certificate. When Server is
+	// set it defaults to the host of the kubeconfig server, so its certificate keeps verifying.
+	TLSServerName string `mapstructure:"tls-server-name"`
+	// Dial holds the options of every connection opened to the API server
+	Dial DialOptions `mapstructure:"dial"`
+}
+
+// DialOptions tune the connections opened to the API server
+type DialOptions struct {
+	// Address, when set, is dialed instead of the host of the server URL
+	Address   string        `mapstructure:"address"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+	KeepAlive time.Duration `mapstructure:"keep-alive"`
+}
+
+// IsZero returns true when no override is set
+func (o ConnectionOptions) IsZero() bool {
+	return o == ConnectionOptions{}
+}
+
+// ConnectionOptionsFromConfig returns the connection overrides declared in the plugin config
+func ConnectionOptionsFromConfig(v *viper.Viper) (ConnectionOptions, error) {
+	opts := ConnectionOptions{}
+	if v == nil || !v.IsSet(ConnectionConfigKey) {
+		return opts, nil
+	}
+	if err := v.UnmarshalKey(ConnectionConfigKey, &opts); err != nil {
+		return ConnectionOptions{}, fmt.Errorf("invalid %s: %w", ConnectionConfigKey, err)
+	}
+	if err := opts.Validate(); err != nil {
+		return ConnectionOptions{}, err
+	}
+	return opts, nil
+}
+
+// Validate checks the server is an http(s) URL and the dial address is a host and a port
+func (o ConnectionOptions) Validate() error {
+	if o.Server != "" {
+		u, err := url.Parse(o.Server)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("invalid %s server %q, expected an http or https URL", ConnectionConfigKey, o.Server)
+		}
+	}
+	if o.Dial.Address != "" {
+		if _, _, err := net.SplitHostPort(o.Dial.Address); err != nil {
+			return fmt.Errorf("invalid %s dial address %q, expected host:port", ConnectionConfigKey, o.Dial.Address)
+		}
+	}
+	if o.Dial.Timeout < 0 || o.Dial.KeepAlive < 0 {
+		return fmt.Errorf("invalid %s dial options, durations must not be negative", ConnectionConfigKey)
+	}
+	return nil
+}
+
+// Apply sets the overrides on the rest config, every client built from it, including watches
+// and log streams, reaches the API server through them
+func (o ConnectionOptions) Apply(restConfig *rest.Config) {
+	if o.Server != "" {
+		if restConfig.TLSClientConfig.ServerName == "" && o.TLSServerName == "" {
+			if u, err := url.Parse(restConfig.Host); err == nil && u.Hostname() != "" {
+				restConfig.TLSClientConfig.ServerName = u.Hostname()
+			}
+		}
+		restConfig.Host = o.Server
+	}
+	if o.TLSServerName != "" {
+		restConfig.TLSClientConfig.ServerName = o.TLSServerName
+	}
+	if o.Dial != (DialOptions{}) {
+		dialer := &net.Dialer{Timeout: o.Dial.Timeout, KeepAlive: o.Dial.KeepAlive}
+		address := o.Dial.Address
+		restConfig.Dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if address != "" {
+				addr = address
+			}
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+	"k8s.io/client-go/rest"
+)
+
+func TestConnectionOptionsFromConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      interface{}
+		expected    ConnectionOptions
+		shouldError bool
+	}{{
+		name: "no connection",
+	}, {
+		name: "server and tls server name",
+		config: map[string]interface{}{
+			"server":          "https://127.0.0.1:16443",
+			"tls-server-name": "api.cluster.internal",
+		},
+		expected: ConnectionOptions{Server: "https://127.0.0.1:16443", TLSServerName: "api.cluster.internal"},
+	}, {
+		name: "dial options",
+		config: map[string]interface{}{
+			"dial": map[string]interface{}{
+				"address":    "localhost:16443",
+				"timeout":    "10s",
+				"keep-alive": "1m",
+			},
+		},
+		expected: ConnectionOptions{Dial: DialOptions{Address: "localhost:16443", Timeout: 10 * time.Second, KeepAlive: time.Minute}},
+	}, {
+		name:        "invalid server",
+		config:      map[string]interface{}{"server": "127.0.0.1:16443"},
+		shouldError: true,
+	}, {
+		name:        "invalid dial address",
+		config:      map[string]interface{}{"dial": map[string]interface{}{"address": "localhost"}},
+		shouldError: true,
+	}, {
+		name:        "invalid dial timeout",
+		config:      map[string]interface{}{"dial": map[string]interface{}{"timeout": "soon"}},
+		shouldError: true,
+	}, {
+		name:        "negative dial timeout",
+		config:      map[string]interface{}{"dial": map[string]interface{}{"timeout": "-1s"}},
+		shouldError: true,
+	}, {
+		name:        "invalid connection",
+		config:      "https://127.0.0.1:16443",
+		shouldError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := viper.New()
+			if test.config != nil {
+				v.Set(ConnectionConfigKey, test.config)
+			}
+			opts, err := ConnectionOptionsFromConfig(v)
+			if (err != nil) != test.shouldError {
+				t.Errorf("ConnectionOptionsFromConfig() error = %v, shouldError %v", err, test.shouldError)
+			}
+			if diff := cmp.Diff(test.expected, opts); diff != "" {
+				t.Errorf("ConnectionOptionsFromConfig() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestConnectionOptionsApply(t *testing.T) {
+	tests := []struct {
+		name               string
+		opts               ConnectionOptions
+		serverName         string
+		expectedHost       string
+		expectedServerName string
+	}{{
+		name:         "no overrides",
+		expectedHost: "https://api.cluster.example:6443",
+	}, {
+		name:               "server keeps the kubeconfig host for sni",
+		opts:               ConnectionOptions{Server: "https://127.0.0.1:16443"},
+		expectedHost:       "https://127.0.0.1:16443",
+		expectedServerName: "api.cluster.example",
+	}, {
+		name:               "server keeps the kubeconfig tls server name",
+		opts:               ConnectionOptions{Server: "https://127.0.0.1:16443"},
+		serverName:         "api.cluster.internal",
+		expectedHost:       "https://127.0.0.1:16443",
+		expectedServerName: "api.cluster.internal",
+	}, {
+		name:               "tls server name",
+		opts:               ConnectionOptions{Server: "https://127.0.0.1:16443", TLSServerName: "kubernetes.default"},
+		serverName:         "api.cluster.internal",
+		expectedHost:       "https://127.0.0.1:16443",
+		expectedServerName: "kubernetes.default",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			restConfig := &rest.Config{Host: "https://api.cluster.example:6443"}
+			restConfig.TLSClientConfig.ServerName = test.serverName
+			test.opts.Apply(restConfig)
+			if diff := cmp.Diff(test.expectedHost, restConfig.Host); diff != "" {
+				t.Errorf("Apply() host (-want, +got) = %s", diff)
+			}
+			if diff := cmp.Diff(test.expectedServerName, restConfig.TLSClientConfig.ServerName); diff != "" {
+				t.Errorf("Apply() server name (-want, +got) = %s", diff)
+			}
+			if restConfig.Dial != nil {
+				t.Errorf("Apply() expected no dial func")
+			}
+		})
+	}
+}
+
+func TestConnectionOptionsApply_DialAddress(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	defer listener.Close()
+	accepted := make(chan struct{})
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			conn.Close()
+			close(accepted)
+		}
+	}()
+
+	restConfig := &rest.Config{Host: "https://api.cluster.example:6443"}
+	opts := ConnectionOptions{Dial: DialOptions{Address: listener.Addr().String(), Timeout: 5 * time.Second}}
+	opts.Apply(restConfig)
+	if restConfig.Dial == nil {
+		t.Fatalf("Apply() expected a dial func")
+	}
+	conn, err := restConfig.Dial(context.TODO(), "tcp", "api.cluster.example:6443")
+	if err != nil {
+		t.Fatalf("Dial() unexpected error: %v", err)
+	}
+	defer conn.Close()
+	select {
+	case <-accepted:
+	case <-time.After(5 * time.Second):
+		t.Errorf("Dial() expected the connection to reach the dial address")
+	}
+}
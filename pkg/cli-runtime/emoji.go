@@ -31,6 +31,7 @@ const (
 	IncomingEnvelop Icon = '📨'
 	Canoe           Icon = '🛶'
 	Inbox           Icon = '📥'
+	Outbox          Icon = '📤'
 	Question        Icon = '❓'
 	ThumpsUp        Icon = '👍'
 )
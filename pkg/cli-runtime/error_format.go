@@ -0,0 +1,109 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// formats of the errors returned by a command, set with --error-format
+const (
+	ErrorFormatText = "text"
+	ErrorFormatJSON = "json"
+)
+
+var ErrorFormats = []string{ErrorFormatText, ErrorFormatJSON}
+
+// reasons of the errors that are not returned by the API server
+const (
+	ErrorReasonError           = "Error"
+	ErrorReasonInvalid         = "Invalid"
+	ErrorReasonTimeout         = "Timeout"
+	ErrorReasonFailedCondition = "FailedCondition"
+	ErrorReasonConflict        = "Conflict"
+	ErrorReasonVerifyFailed    = "VerifyFailed"
+)
+
+// StructuredError is the machine readable form of an error returned by a command, printed on
+// stderr with --error-format json
+type StructuredError struct {
+	// Code is the code the process exits with
+	Code int `json:"code"`
+	// Reason is a CamelCase category of the error, the reason of the API server for errors of
+	// requests to the cluster
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	// Errors are the field errors of a validation failure
+	Errors []validationError `json:"errors,omitempty"`
+}
+
+// NewStructuredError describes err for scripts and other tools
+func NewStructuredError(err error) StructuredError {
+	serr := StructuredError{
+		Code:    ExitCode(err),
+		Reason:  ErrorReasonError,
+		Message: err.Error(),
+	}
+
+	var aggregate utilerrors.Aggregate
+	if errors.As(err, &aggregate) {
+		for _, err := range aggregate.Errors() {
+			if ferr, ok := err.(*k8sfield.Error); ok {
+				serr.Errors = append(serr.Errors, newValidationError(ferr))
+			}
+		}
+	}
+
+	switch {
+	case len(serr.Errors) != 0:
+		serr.Reason = ErrorReasonInvalid
+	case serr.Code == ExitCodeTimeout:
+		serr.Reason = ErrorReasonTimeout
+	case serr.Code == ExitCodeFailedCondition:
+		serr.Reason = ErrorReasonFailedCondition
+	case serr.Code == ExitCodeConflict:
+		serr.Reason = ErrorReasonConflict
+	case serr.Code == ExitCodeVerifyFailed:
+		serr.Reason = ErrorReasonVerifyFailed
+	case apierrors.ReasonForError(err) != metav1.StatusReasonUnknown:
+		serr.Reason = string(apierrors.ReasonForError(err))
+	}
+	return serr
+}
+
+// PrintStructuredError prints err as a single line JSON object on stderr
+func (c *Config) PrintStructuredError(err error) error {
+	b, merr := json.Marshal(NewStructuredError(err))
+	if merr != nil {
+		return merr
+	}
+	_, perr := fmt.Fprintf(c.Stderr, "%s\n", b)
+	return perr
+}
+
+func errorFormatJSON(cmd *cobra.Command) bool {
+	f := cmd.Flags().Lookup(StripDash(ErrorFormatFlagName))
+	return f != nil && f.Value.String() == ErrorFormatJSON
+}
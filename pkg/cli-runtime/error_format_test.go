@@ -0,0 +1,84 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+)
+
+func TestPrintStructuredError(t *testing.T) {
+	workloads := schema.GroupResource{Group: "carto.run", Resource: "workloads"}
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{{
+		name:     "error",
+		err:      fmt.Errorf("test error"),
+		expected: `{"code":1,"reason":"Error","message":"test error"}`,
+	}, {
+		name: "validation error",
+		err: cli.SilenceError(validation.ErrMissingField("--git-repo").Also(
+			validation.ErrInvalidValue("abc", "--limit-cpu"),
+		).ToAggregate()),
+		expected: `{"code":1,"reason":"Invalid","message":"[--git-repo: Required value, --limit-cpu: Invalid value: \"abc\"]","errors":[{"field":"--git-repo","type":"FieldValueRequired","message":"--git-repo: Required value"},{"field":"--limit-cpu","type":"FieldValueInvalid","message":"--limit-cpu: Invalid value: \"abc\"","value":"abc"}]}`,
+	}, {
+		name:     "conflict",
+		err:      cli.SilenceError(cli.WithExitCode(apierrs.NewConflict(workloads, "my-workload", fmt.Errorf("modified")), cli.ExitCodeConflict)),
+		expected: `{"code":4,"reason":"Conflict","message":"Operation cannot be fulfilled on workloads.carto.run \"my-workload\": modified"}`,
+	}, {
+		name:     "timeout",
+		err:      cli.SilenceError(cli.WithExitCode(fmt.Errorf("timeout after 10m0s waiting for \"my-workload\" to become ready"), cli.ExitCodeTimeout)),
+		expected: `{"code":2,"reason":"Timeout","message":"timeout after 10m0s waiting for \"my-workload\" to become ready"}`,
+	}, {
+		name:     "failed condition",
+		err:      cli.SilenceError(cli.WithExitCode(fmt.Errorf("Failed to become ready: build failed"), cli.ExitCodeFailedCondition)),
+		expected: `{"code":3,"reason":"FailedCondition","message":"Failed to become ready: build failed"}`,
+	}, {
+		name:     "verify failed",
+		err:      cli.WithExitCode(fmt.Errorf("smoke check failed"), cli.ExitCodeVerifyFailed),
+		expected: `{"code":5,"reason":"VerifyFailed","message":"smoke check failed"}`,
+	}, {
+		name:     "api error",
+		err:      cli.SilenceError(apierrs.NewNotFound(workloads, "my-workload")),
+		expected: `{"code":1,"reason":"NotFound","message":"workloads.carto.run \"my-workload\" not found"}`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stderr := &bytes.Buffer{}
+			c := cli.NewDefaultConfig("test", runtime.NewScheme())
+			c.Stderr = stderr
+
+			if err := c.PrintStructuredError(test.err); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected, actual := test.expected+"\n", stderr.String(); expected != actual {
+				t.Errorf("expected output to be %q, actually %q", expected, actual)
+			}
+		})
+	}
+}
@@ -16,6 +16,10 @@ limitations under the License.
 
 package cli
 
+import (
+	"errors"
+)
+
 var SilentError = &silentError{}
 
 type silentError struct {
@@ -38,3 +42,43 @@ func (e *silentError) Is(err error) bool {
 func SilenceError(err error) error {
 	return &silentError{err: err}
 }
+
+// exit codes returned by the plugin so automation can branch on the failure mode
+const (
+	ExitCodeError           = 1
+	ExitCodeTimeout         = 2
+	ExitCodeFailedCondition = 3
+	ExitCodeConflict        = 4
+	ExitCodeVerifyFailed    = 5
+)
+
+type exitCodeError struct {
+	err  error
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// WithExitCode sets the code the process exits with when err is returned by a command
+func WithExitCode(err error, code int) error {
+	return &exitCodeError{err: err, code: code}
+}
+
+// ExitCode returns the code the process should exit with for err, ExitCodeError unless a specific
+// code was set with WithExitCode
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitCodeError
+}
@@ -41,3 +41,43 @@ func TestSilenceError(t *testing.T) {
 		t.Errorf("errors expected to match, expected %q, actually %q", expected, actual)
 	}
 }
+
+func TestExitCode(t *testing.T) {
+	err := fmt.Errorf("test error")
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{{
+		name:     "no error",
+		err:      nil,
+		expected: 0,
+	}, {
+		name:     "error",
+		err:      err,
+		expected: cli.ExitCodeError,
+	}, {
+		name:     "exit code",
+		err:      cli.WithExitCode(err, cli.ExitCodeTimeout),
+		expected: cli.ExitCodeTimeout,
+	}, {
+		name:     "silent exit code",
+		err:      cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeConflict)),
+		expected: cli.ExitCodeConflict,
+	}, {
+		name:     "wrapped exit code",
+		err:      fmt.Errorf("wrapped: %w", cli.WithExitCode(err, cli.ExitCodeFailedCondition)),
+		expected: cli.ExitCodeFailedCondition,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := cli.ExitCode(test.err); actual != test.expected {
+				t.Errorf("ExitCode() expected %d, actually %d", test.expected, actual)
+			}
+		})
+	}
+	if expected, actual := err, errors.Unwrap(cli.WithExitCode(err, cli.ExitCodeTimeout)); expected != actual {
+		t.Errorf("errors expected to match, expected %v, actually %v", expected, actual)
+	}
+}
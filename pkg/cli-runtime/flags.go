@@ -18,6 +18,7 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -29,6 +30,7 @@ import (
 const (
 	AllNamespacesFlagName = "--all-namespaces"
 	ContextFlagName       = "--context"
+	ErrorFormatFlagName   = "--error-format"
 	KubeConfigFlagName    = "--kubeconfig"
 	NamespaceFlagName     = "--namespace"
 	NoColorFlagName       = "--no-color"
@@ -61,7 +63,11 @@ func NamespaceFlag(ctx context.Context, cmd *cobra.Command, c *Config, namespace
 	prior := cmd.PreRunE
 	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
 		if *namespace == "" {
-			*namespace = c.DefaultNamespace()
+			if value, ok := c.FlagDefault(NamespaceFlagName); ok {
+				*namespace = fmt.Sprint(value)
+			} else {
+				*namespace = c.DefaultNamespace()
+			}
 		}
 		if prior != nil {
 			if err := prior(cmd, args); err != nil {
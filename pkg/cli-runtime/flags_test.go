@@ -120,6 +120,7 @@ func TestNamespaceFlag(t *testing.T) {
 		prior           func(cmd *cobra.Command, args []string) error
 		namespace       string
 		actualNamespace string
+		defaults        map[string]interface{}
 		err             error
 	}{{
 		name:      "default",
@@ -129,6 +130,16 @@ func TestNamespaceFlag(t *testing.T) {
 		name:      "explicit namespace",
 		args:      []string{cli.NamespaceFlagName, "my-namespace"},
 		namespace: "my-namespace",
+	}, {
+		name:      "plugin config default",
+		args:      []string{},
+		defaults:  map[string]interface{}{"namespace": "dev"},
+		namespace: "dev",
+	}, {
+		name:      "explicit namespace over plugin config default",
+		args:      []string{cli.NamespaceFlagName, "my-namespace"},
+		defaults:  map[string]interface{}{"namespace": "dev"},
+		namespace: "my-namespace",
 	}, {
 		name: "prior PreRunE",
 		args: []string{},
@@ -151,6 +162,9 @@ func TestNamespaceFlag(t *testing.T) {
 			scheme := runtime.NewScheme()
 			c := cli.NewDefaultConfig("test", scheme)
 			c.Client = clitesting.NewFakeCliClient(clitesting.NewFakeClient(scheme))
+			if test.defaults != nil {
+				c.Viper.Set(cli.DefaultsConfigKey, test.defaults)
+			}
 			cmd := &cobra.Command{
 				PreRunE: test.prior,
 				RunE: func(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
+)
+
+// EventPrefix marks the controller events printed in the stream of the logs
+const EventPrefix = "[event]"
+
+// EventsPollInterval spaces the polls of the events tailed along with the logs
+var EventsPollInterval = 5 * time.Second
+
+// EventFilter accepts the objects whose events are tailed
+type EventFilter func(involved corev1.ObjectReference) bool
+
+// EventTail prints the warning events of the objects of a namespace accepted by a filter, like a
+// GitRepository failing to fetch the source, to explain why no pods are started. An event repeated
+// by its controller is printed again each time it happens.
+type EventTail struct {
+	namespace string
+	filter    EventFilter
+	since     time.Time
+	printed   map[string]bool
+}
+
+// NewEventTail tails the events that happened within since, or later
+func NewEventTail(namespace string, filter EventFilter, since time.Duration) *EventTail {
+	return &EventTail{
+		namespace: namespace,
+		filter:    filter,
+		since:     time.Now().Add(-since),
+		printed:   map[string]bool{},
+	}
+}
+
+// Poll prints the events not printed by a previous poll
+func (t *EventTail) Poll(ctx context.Context, c *cli.Config) error {
+	events := &corev1.EventList{}
+	if err := c.List(ctx, events, client.InNamespace(t.namespace)); err != nil {
+		return err
+	}
+	warnings := []corev1.Event{}
+	for _, event := range events.Items {
+		if event.Type != corev1.EventTypeWarning || eventTime(event).Before(t.since) || !t.filter(event.InvolvedObject) {
+			continue
+		}
+		key := fmt.Sprintf("%s/%d", event.UID, event.Count)
+		if t.printed[key] {
+			continue
+		}
+		t.printed[key] = true
+		warnings = append(warnings, event)
+	}
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return eventTime(warnings[i]).Before(eventTime(warnings[j]))
+	})
+	for _, event := range warnings {
+		message := strings.Join(strings.Fields(Redact(event.Message)), " ")
+		c.Printf("%s %s/%s %s: %s\n", printer.WarnColor.Sprint(EventPrefix), event.InvolvedObject.Kind, event.InvolvedObject.Name, event.Reason, message)
+	}
+	return nil
+}
+
+// Run polls the events every EventsPollInterval until the context is done, a failed poll is
+// retried with the next one
+func (t *EventTail) Run(ctx context.Context, c *cli.Config) {
+	ticker := time.NewTicker(EventsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = t.Poll(ctx, c)
+		}
+	}
+}
+
+// eventTime is the last time the event happened, as set by either the core or the events API
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
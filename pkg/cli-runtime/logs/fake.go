@@ -18,6 +18,7 @@ package logs
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/stretchr/testify/mock"
@@ -30,11 +31,15 @@ var _ Tailer = &FakeTailer{}
 
 type FakeTailer struct {
 	mock.Mock
+	// output is locked for concurrent tails to share the output of the config
+	output sync.Mutex
 }
 
-func (f *FakeTailer) Tail(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, timestamps bool) error {
-	args := f.Called(ctx, namespace, selector, containers, since, timestamps)
+func (f *FakeTailer) Tail(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, lines int64, timestamps bool) error {
+	args := f.Called(ctx, namespace, selector, containers, since, lines, timestamps)
+	f.output.Lock()
 	c.Printf("...tail output...\n")
+	f.output.Unlock()
 	if err := args.Error(0); err != nil {
 		return err
 	}
@@ -42,3 +47,24 @@ func (f *FakeTailer) Tail(ctx context.Context, c *cli.Config, namespace string,
 	<-ctx.Done()
 	return nil
 }
+
+var _ Dumper = &FakeTailer{}
+
+func (f *FakeTailer) Dump(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, lines int64, timestamps bool) error {
+	args := f.Called(ctx, namespace, selector, containers, since, lines, timestamps)
+	f.output.Lock()
+	c.Printf("...dump output...\n")
+	f.output.Unlock()
+	return args.Error(0)
+}
+
+var _ Fetcher = &FakeFetcher{}
+
+type FakeFetcher struct {
+	mock.Mock
+}
+
+func (f *FakeFetcher) Fetch(ctx context.Context, c *cli.Config, namespace, pod, container string, lines int64, previous bool) (string, error) {
+	args := f.Called(ctx, namespace, pod, container, lines, previous)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+)
+
+// MaxFetchBytes bounds the logs read by a fetch, whatever the number of lines
+const MaxFetchBytes int64 = 64 * 1024
+
+// Fetcher reads the last lines logged by a container, from its previous instance when previous
+// is set
+type Fetcher interface {
+	Fetch(ctx context.Context, c *cli.Config, namespace, pod, container string, lines int64, previous bool) (string, error)
+}
+
+func Fetch(ctx context.Context, c *cli.Config, namespace, pod, container string, lines int64, previous bool) (string, error) {
+	fetcher := RetrieveFetcher(ctx)
+	if fetcher == nil {
+		return "", fmt.Errorf("unable to retrieve fetcher from the context: set the fetcher on context with StashFetcher(ctx context.Context, fetcher Fetcher) context.Context")
+	}
+	return fetcher.Fetch(ctx, c, namespace, pod, container, lines, previous)
+}
+
+var fetcherStashKey = struct{ name string }{name: "fetcher"}
+
+func StashFetcher(ctx context.Context, fetcher Fetcher) context.Context {
+	return context.WithValue(ctx, fetcherStashKey, fetcher)
+}
+
+func RetrieveFetcher(ctx context.Context) Fetcher {
+	value := ctx.Value(fetcherStashKey)
+	if fetcher, ok := value.(Fetcher); ok {
+		return fetcher
+	}
+	return nil
+}
+
+var _ Fetcher = &KubernetesFetcher{}
+
+// KubernetesFetcher reads the logs from the API server
+type KubernetesFetcher struct{}
+
+func (f *KubernetesFetcher) Fetch(ctx context.Context, c *cli.Config, namespace, pod, container string, lines int64, previous bool) (string, error) {
+	clientset, err := corev1client.NewForConfig(c.KubeRestConfig())
+	if err != nil {
+		return "", err
+	}
+	limitBytes := MaxFetchBytes
+	raw, err := clientset.Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container:  container,
+		TailLines:  &lines,
+		LimitBytes: &limitBytes,
+		Previous:   previous,
+	}).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+var redactions = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	// credentials in URLs
+	{regexp.MustCompile(`(://[^:/@\s]+:)[^@/\s]+@`), "${1}[REDACTED]@"},
+	// authorization header values
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`), "${1} [REDACTED]"},
+	// values of keys that commonly hold secrets
+	{regexp.MustCompile(`(?i)\b((?:[a-z0-9]+[_-])*(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key)["']?\s*[:=]\s*["']?)([^\s"',;&]+)`), "${1}[REDACTED]"},
+}
+
+// Redact hides the values that look like credentials in logs, for them to be shared safely
+func Redact(text string) string {
+	for _, r := range redactions {
+		text = r.pattern.ReplaceAllString(text, r.replacement)
+	}
+	return text
+}
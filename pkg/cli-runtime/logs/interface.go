@@ -26,16 +26,36 @@ import (
 	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
 )
 
+// AllLines tails every line the containers logged within the since window
+const AllLines int64 = -1
+
+// Tailer streams the logs of the containers of the selected pods until the context is done. A since of
+// zero streams the logs since each pod started.
 type Tailer interface {
-	Tail(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, timestamps bool) error
+	Tail(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, lines int64, timestamps bool) error
 }
 
-func Tail(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, timestamps bool) error {
+func Tail(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, lines int64, timestamps bool) error {
 	tailer := RetrieveTailer(ctx)
 	if tailer == nil {
 		return fmt.Errorf("unable to retrieve tailer from the context: set the tailer on context with StashTailer(ctx context.Context, tailer Tailer) context.Context")
 	}
-	return tailer.Tail(ctx, c, namespace, selector, containers, since, timestamps)
+	return tailer.Tail(ctx, c, namespace, selector, containers, since, lines, timestamps)
+}
+
+// Dumper prints the logs the containers of the selected pods logged so far, one container after the
+// other, and returns once they are printed. A since of zero prints the logs since each pod started.
+type Dumper interface {
+	Dump(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, lines int64, timestamps bool) error
+}
+
+// Dump prints the logs with the tailer of the context, which must also be a Dumper
+func Dump(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, lines int64, timestamps bool) error {
+	dumper, ok := RetrieveTailer(ctx).(Dumper)
+	if !ok {
+		return fmt.Errorf("unable to retrieve dumper from the context: set a tailer implementing Dumper on context with StashTailer(ctx context.Context, tailer Tailer) context.Context")
+	}
+	return dumper.Dump(ctx, c, namespace, selector, containers, since, lines, timestamps)
 }
 
 var tailerStashKey = struct{}{}
@@ -51,3 +71,16 @@ func RetrieveTailer(ctx context.Context) Tailer {
 	}
 	return nil
 }
+
+type namespacePrefixStashKey struct{}
+
+// StashNamespacePrefix prefixes each log line with the namespace of its pod, for the logs of pods
+// tailed from more than one namespace to be told apart
+func StashNamespacePrefix(ctx context.Context) context.Context {
+	return context.WithValue(ctx, namespacePrefixStashKey{}, true)
+}
+
+func hasNamespacePrefix(ctx context.Context) bool {
+	prefix, _ := ctx.Value(namespacePrefixStashKey{}).(bool)
+	return prefix
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+)
+
+// Reconnect spaces the reconnections of a tail whose streams are closed by the API server. The first
+// reconnection happens after Initial, every consecutive one waits Factor times longer up to Max. A tail
+// that stayed connected for longer than Max starts over from Initial.
+type Reconnect struct {
+	Initial time.Duration
+	Max     time.Duration
+	Factor  float64
+}
+
+// DefaultReconnect reconnects after a second and doubles the delay up to half a minute
+func DefaultReconnect() Reconnect {
+	return Reconnect{
+		Initial: time.Second,
+		Max:     30 * time.Second,
+		Factor:  2,
+	}
+}
+
+func (r Reconnect) next(delay time.Duration) time.Duration {
+	if r.Factor > 1 {
+		delay = time.Duration(float64(delay) * r.Factor)
+	}
+	if r.Max != 0 && delay > r.Max {
+		delay = r.Max
+	}
+	return delay
+}
+
+type reconnectStashKey struct{}
+
+// StashReconnect sets the backoff used by TailReconnecting
+func StashReconnect(ctx context.Context, reconnect Reconnect) context.Context {
+	return context.WithValue(ctx, reconnectStashKey{}, reconnect)
+}
+
+// RetrieveReconnect returns the stashed backoff, by default DefaultReconnect
+func RetrieveReconnect(ctx context.Context) Reconnect {
+	if reconnect, ok := ctx.Value(reconnectStashKey{}).(Reconnect); ok {
+		return reconnect
+	}
+	return DefaultReconnect()
+}
+
+// TailReconnecting tails like Tail, and tails again when the tail ends before the context is done, for
+// example when the API server closes the watch of the pods. The reconnected tail resumes from the time the
+// stream was lost, with every line logged since, so the output of long running builds is not dropped.
+func TailReconnecting(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, lines int64, timestamps bool) error {
+	reconnect := RetrieveReconnect(ctx)
+	delay := reconnect.Initial
+	for {
+		connected := time.Now()
+		err := Tail(ctx, c, namespace, selector, containers, since, lines, timestamps)
+		if ctx.Err() != nil {
+			return err
+		}
+		lost := time.Now()
+		if reconnect.Max != 0 && lost.Sub(connected) > reconnect.Max {
+			delay = reconnect.Initial
+		}
+		reason := "stream closed"
+		if err != nil {
+			reason = err.Error()
+		}
+		c.Einfof("Log stream lost (%s), reconnecting in %s\n", reason, delay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		c.Einfof("Stream reconnected\n")
+		// the logs are read with a precision of a second, some lines may be printed again
+		since = time.Since(lost).Truncate(time.Second) + time.Second
+		lines = AllLines
+		delay = reconnect.next(delay)
+	}
+}
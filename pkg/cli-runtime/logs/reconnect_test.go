@@ -0,0 +1,80 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/logs"
+)
+
+func TestTailReconnecting(t *testing.T) {
+	selector := labels.SelectorFromSet(labels.Set{"app": "my-app"})
+	tailer := &logs.FakeTailer{}
+	tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Minute, int64(10), true).Return(fmt.Errorf("lost watch connection")).Once()
+	tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, true).Return(fmt.Errorf("lost watch connection")).Twice()
+	tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, true).Return(nil).Once()
+
+	ctx := logs.StashTailer(context.Background(), tailer)
+	ctx = logs.StashReconnect(ctx, logs.Reconnect{Initial: time.Millisecond, Max: 3 * time.Millisecond, Factor: 2})
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	c := cli.NewDefaultConfig("test", runtime.NewScheme())
+	output := &bytes.Buffer{}
+	c.Stdout = output
+	c.Stderr = output
+
+	if err := logs.TailReconnecting(ctx, c, "default", selector, []string{}, time.Minute, 10, true); err != nil {
+		t.Errorf("TailReconnecting() expected no error, got %v", err)
+	}
+	expected := `...tail output...
+Log stream lost (lost watch connection), reconnecting in 1ms
+Stream reconnected
+...tail output...
+Log stream lost (lost watch connection), reconnecting in 2ms
+Stream reconnected
+...tail output...
+Log stream lost (lost watch connection), reconnecting in 3ms
+Stream reconnected
+...tail output...
+`
+	if diff := cmp.Diff(expected, output.String()); diff != "" {
+		t.Errorf("TailReconnecting() (-want, +got) = %s", diff)
+	}
+	tailer.AssertExpectations(t)
+}
+
+func TestRetrieveReconnect(t *testing.T) {
+	if diff := cmp.Diff(logs.DefaultReconnect(), logs.RetrieveReconnect(context.Background())); diff != "" {
+		t.Errorf("RetrieveReconnect() (-want, +got) = %s", diff)
+	}
+	reconnect := logs.Reconnect{Initial: time.Millisecond}
+	if diff := cmp.Diff(reconnect, logs.RetrieveReconnect(logs.StashReconnect(context.Background(), reconnect))); diff != "" {
+		t.Errorf("RetrieveReconnect() (-want, +got) = %s", diff)
+	}
+}
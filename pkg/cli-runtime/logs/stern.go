@@ -20,15 +20,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/stern/stern/stern"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
 )
@@ -37,16 +43,201 @@ var _ Tailer = &SternTailer{}
 
 type SternTailer struct{}
 
-func (s *SternTailer) Tail(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, timestamps bool) error {
-	containerQuery := regexp.MustCompile(".*")
-	if len(containers) != 0 {
-		escapedContainers := []string{}
-		for _, c := range containers {
-			escapedContainers = append(escapedContainers, regexp.QuoteMeta(c))
+func (s *SternTailer) Tail(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, lines int64, timestamps bool) error {
+	containerQuery := containerRegexp(containers)
+	template := logTemplate(ctx)
+
+	// the clientset is built from the rest config of the client, rather than from the kubeconfig
+	// file, for the log streams to honor the connection overrides
+	clientset, err := corev1client.NewForConfig(c.KubeRestConfig())
+	if err != nil {
+		return err
+	}
+
+	var tailLines *int64
+	if lines != AllLines {
+		tailLines = &lines
+	}
+	// the API requires a positive since, a since of zero covers every line the pods logged
+	sinceSeconds := int64(since.Seconds())
+	if since == 0 {
+		sinceSeconds = math.MaxInt32
+	}
+
+	added, removed, err := stern.Watch(ctx,
+		clientset.Pods(namespace),
+		// the pod query and field selector are required, but the label selector is used instead
+		regexp.MustCompile(""),
+		nil,
+		containerQuery,
+		nil,
+		true,
+		false,
+		[]stern.ContainerState{stern.RUNNING, stern.TERMINATED},
+		selector,
+		fields.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to set up watch: %w", err)
+	}
+
+	tails := map[string]*activeTail{}
+	var m sync.Mutex
+	errCh := make(chan error, 1)
+	lost := func() {
+		select {
+		case errCh <- fmt.Errorf("lost watch connection"):
+		default:
+		}
+	}
+
+	go func() {
+		for p := range added {
+			targetID := p.GetID()
+			m.Lock()
+			if tail, ok := tails[targetID]; ok {
+				if tail.isActive() {
+					m.Unlock()
+					continue
+				}
+				tail.Close()
+				delete(tails, targetID)
+			}
+			tail := &activeTail{
+				Tail: stern.NewTail(clientset, p.Node, p.Namespace, p.Pod, p.Container, template, c.Stdout, c.Stderr, &stern.TailOptions{
+					Timestamps:   timestamps,
+					Location:     time.Local,
+					SinceSeconds: sinceSeconds,
+					TailLines:    tailLines,
+				}),
+				done: make(chan struct{}),
+			}
+			tails[targetID] = tail
+			m.Unlock()
+
+			go func(tail *activeTail) {
+				defer close(tail.done)
+				if err := tail.Start(ctx); err != nil {
+					fmt.Fprintf(c.Stderr, "unexpected error: %v\n", err)
+				}
+			}(tail)
+		}
+		lost()
+	}()
+
+	go func() {
+		for p := range removed {
+			targetID := p.GetID()
+			m.Lock()
+			if tail, ok := tails[targetID]; ok {
+				tail.Close()
+				delete(tails, targetID)
+			}
+			m.Unlock()
+		}
+		lost()
+	}()
+
+	select {
+	case err := <-errCh:
+		if ctx.Err() != nil {
+			// the watch is closed once the context is canceled
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// activeTail tracks when the tail of a container ends, for the container to be tailed again when
+// it is restarted
+type activeTail struct {
+	*stern.Tail
+	done chan struct{}
+}
+
+func (t *activeTail) isActive() bool {
+	select {
+	case <-t.done:
+		return false
+	default:
+		return true
+	}
+}
+
+var _ Dumper = &SternTailer{}
+
+func (s *SternTailer) Dump(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration, lines int64, timestamps bool) error {
+	containerQuery := containerRegexp(containers)
+	template := logTemplate(ctx)
+
+	clientset, err := corev1client.NewForConfig(c.KubeRestConfig())
+	if err != nil {
+		return err
+	}
+
+	var tailLines *int64
+	if lines != AllLines {
+		tailLines = &lines
+	}
+
+	pods, err := clientset.Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+	// oldest pods first, for the logs of a build to come before the logs of the app it built
+	sort.SliceStable(pods.Items, func(i, j int) bool {
+		if !pods.Items[i].CreationTimestamp.Equal(&pods.Items[j].CreationTimestamp) {
+			return pods.Items[i].CreationTimestamp.Before(&pods.Items[j].CreationTimestamp)
+		}
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+	for _, pod := range pods.Items {
+		sinceSeconds := int64(since.Seconds())
+		if since == 0 {
+			sinceSeconds = int64(time.Since(pod.CreationTimestamp.Time).Seconds()) + 1
+		}
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			// containers that are still waiting have nothing logged
+			if (status.State.Running == nil && status.State.Terminated == nil) || !containerQuery.MatchString(status.Name) {
+				continue
+			}
+			tail := stern.NewTail(clientset, pod.Spec.NodeName, pod.Namespace, pod.Name, status.Name, template, c.Stdout, c.Stderr, &stern.TailOptions{
+				Timestamps:   timestamps,
+				Location:     time.Local,
+				SinceSeconds: sinceSeconds,
+				TailLines:    tailLines,
+				Follow:       false,
+			})
+			if err := tail.Start(ctx); err != nil {
+				return err
+			}
 		}
-		containerQuery = regexp.MustCompile(fmt.Sprintf("^(%s)$", strings.Join(escapedContainers, "|")))
 	}
+	return nil
+}
+
+// containerRegexp matches the names of the containers to print the logs of, every container when
+// none is given
+func containerRegexp(containers []string) *regexp.Regexp {
+	if len(containers) == 0 {
+		return regexp.MustCompile(".*")
+	}
+	escapedContainers := []string{}
+	for _, c := range containers {
+		escapedContainers = append(escapedContainers, regexp.QuoteMeta(c))
+	}
+	return regexp.MustCompile(fmt.Sprintf("^(%s)$", strings.Join(escapedContainers, "|")))
+}
+
+// logTemplate prints each log line prefixed with its pod and container, and with the namespace of
+// the pod when the context asks for it
+func logTemplate(ctx context.Context) *template.Template {
 	t := "{{color .ContainerColor .PodName}}{{color .PodColor \"[\"}}{{color .PodColor .ContainerName}}{{color .PodColor \"]\"}} {{.Message}}\n"
+	if hasNamespacePrefix(ctx) {
+		t = "{{color .ContainerColor .Namespace}}{{color .ContainerColor \"/\"}}" + t
+	}
 	funs := map[string]interface{}{
 		"json": func(in interface{}) (string, error) {
 			b, err := json.Marshal(in)
@@ -63,30 +254,5 @@ func (s *SternTailer) Tail(ctx context.Context, c *cli.Config, namespace string,
 	if err != nil {
 		panic(err)
 	}
-
-	configStern := stern.Config{
-		KubeConfig:     c.KubeConfigFile,
-		ContextName:    c.CurrentContext,
-		Namespaces:     []string{namespace},
-		Timestamps:     timestamps,
-		Location:       time.Local,
-		LabelSelector:  selector,
-		ContainerQuery: containerQuery,
-		ContainerStates: []stern.ContainerState{
-			stern.RUNNING,
-			stern.TERMINATED,
-		},
-		InitContainers: true,
-		Since:          since,
-
-		// PodQuery and FieldSelector are required, but we use LabelSelector instead
-		PodQuery:      regexp.MustCompile(""),
-		FieldSelector: fields.Everything(),
-
-		Template: template,
-		Out:      c.Stdout,
-		ErrOut:   c.Stderr,
-	}
-
-	return stern.Run(ctx, &configStern)
+	return template
 }
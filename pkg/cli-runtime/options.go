@@ -18,9 +18,15 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/telemetry"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
 )
 
@@ -37,7 +43,24 @@ import (
 func ValidateE(ctx context.Context, obj validation.Validatable) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		ctx := WithCommand(ctx, cmd)
+		if f := cmd.Flags().Lookup(StripDash(ErrorFormatFlagName)); f != nil {
+			if err := validation.Enum(f.Value.String(), ErrorFormatFlagName, ErrorFormats); len(err) != 0 {
+				return err.ToAggregate()
+			}
+		}
+		if errorFormatJSON(cmd) {
+			// the structured error is not mixed with the usage
+			cmd.SilenceUsage = true
+		}
 		if err := obj.Validate(ctx); len(err) != 0 {
+			if outputJSON(cmd) {
+				// machine readable output is not mixed with the usage and the human readable errors
+				cmd.SilenceUsage = true
+				if printErr := printValidationErrors(cmd, err); printErr != nil {
+					return printErr
+				}
+				return SilenceError(err.ToAggregate())
+			}
 			return err.ToAggregate()
 		}
 		cmd.SilenceUsage = true
@@ -45,6 +68,50 @@ func ValidateE(ctx context.Context, obj validation.Validatable) func(cmd *cobra.
 	}
 }
 
+// validationError is the machine readable form of a field error, printed when the validation of a
+// command fails with --output json
+type validationError struct {
+	Field   string      `json:"field"`
+	Type    string      `json:"type"`
+	Message string      `json:"message"`
+	Value   interface{} `json:"value,omitempty"`
+}
+
+func newValidationError(err *k8sfield.Error) validationError {
+	verr := validationError{
+		Field:   err.Field,
+		Type:    string(err.Type),
+		Message: err.Error(),
+	}
+	// missing and forbidden fields have no meaningful value
+	if err.Type != k8sfield.ErrorTypeRequired && err.Type != k8sfield.ErrorTypeForbidden {
+		verr.Value = err.BadValue
+	}
+	return verr
+}
+
+func outputJSON(cmd *cobra.Command) bool {
+	f := cmd.Flags().Lookup("output")
+	return f != nil && f.Value.String() == "json"
+}
+
+func printValidationErrors(cmd *cobra.Command, errs validation.FieldErrors) error {
+	out := struct {
+		Errors []validationError `json:"errors"`
+	}{
+		Errors: []validationError{},
+	}
+	for _, err := range errs {
+		out.Errors = append(out.Errors, newValidationError(err))
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", b)
+	return err
+}
+
 type Executable interface {
 	Exec(ctx context.Context, c *Config) error
 }
@@ -67,6 +134,16 @@ func ExecE(ctx context.Context, c *Config, obj Executable) func(cmd *cobra.Comma
 			ctx = WithStdout(ctx, c.Stdout)
 			c.Stdout = c.Stderr
 		}
-		return obj.Exec(ctx, c)
+
+		// the execution is traced when an OTLP endpoint is set with the OTEL_* env vars
+		tracer := telemetry.TracerFromEnv(os.Getenv)
+		ctx = telemetry.StashTracer(ctx, tracer)
+		ctx, span := telemetry.Start(ctx, cmd.CommandPath(), telemetry.String("cli.command", cmd.CommandPath()))
+		err := obj.Exec(ctx, c)
+		span.End(err)
+		if ferr := tracer.Flush(context.Background()); ferr != nil {
+			c.Eprintf("%s unable to export traces: %s\n", printer.Swarnf("Warning:"), ferr)
+		}
+		return err
 	}
 }
@@ -19,7 +19,12 @@ package cli_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -40,10 +45,14 @@ func (o *StubValidate) Validate(ctx context.Context) validation.FieldErrors {
 
 func TestValidateE(t *testing.T) {
 	tests := []struct {
-		name          string
-		opts          *StubValidate
-		expectedErr   error
-		usageSilenced bool
+		name           string
+		opts           *StubValidate
+		output         string
+		errorFormat    string
+		notValidated   bool
+		expectedErr    error
+		expectedOutput string
+		usageSilenced  bool
 	}{{
 		name:          "valid, no error",
 		opts:          &StubValidate{},
@@ -61,15 +70,59 @@ func TestValidateE(t *testing.T) {
 		},
 		expectedErr:   validation.ErrMissingField("field-name").ToAggregate(),
 		usageSilenced: false,
+	}, {
+		name: "validation error with json output",
+		opts: &StubValidate{
+			validationErr: validation.ErrMissingField("field-name").Also(
+				validation.ErrInvalidValue(-1, "other-field"),
+			),
+		},
+		output: "json",
+		expectedErr: validation.ErrMissingField("field-name").Also(
+			validation.ErrInvalidValue(-1, "other-field"),
+		).ToAggregate(),
+		expectedOutput: `{"errors":[{"field":"field-name","type":"FieldValueRequired","message":"field-name: Required value"},{"field":"other-field","type":"FieldValueInvalid","message":"other-field: Invalid value: -1","value":-1}]}` + "\n",
+		usageSilenced:  true,
+	}, {
+		name: "validation error with yaml output",
+		opts: &StubValidate{
+			validationErr: validation.ErrMissingField("field-name"),
+		},
+		output:        "yaml",
+		expectedErr:   validation.ErrMissingField("field-name").ToAggregate(),
+		usageSilenced: false,
+	}, {
+		name: "validation error with json error format",
+		opts: &StubValidate{
+			validationErr: validation.ErrMissingField("field-name"),
+		},
+		errorFormat:   "json",
+		expectedErr:   validation.ErrMissingField("field-name").ToAggregate(),
+		usageSilenced: true,
+	}, {
+		name:          "invalid error format",
+		opts:          &StubValidate{},
+		errorFormat:   "xml",
+		notValidated:  true,
+		expectedErr:   validation.EnumInvalidValue("xml", cli.ErrorFormatFlagName, cli.ErrorFormats).ToAggregate(),
+		usageSilenced: false,
 	}}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			ctx := context.Background()
 			cmd := &cobra.Command{}
+			output := &bytes.Buffer{}
+			cmd.SetOut(output)
+			if test.output != "" {
+				cmd.Flags().String("output", test.output, "")
+			}
+			if test.errorFormat != "" {
+				cmd.Flags().String(cli.StripDash(cli.ErrorFormatFlagName), test.errorFormat, "")
+			}
 			err := cli.ValidateE(ctx, test.opts)(cmd, []string{})
 
-			if expected, actual := true, test.opts.called; true != actual {
+			if expected, actual := !test.notValidated, test.opts.called; expected != actual {
 				t.Errorf("expected called to be %v, actually %v", expected, actual)
 			}
 			if expected, actual := test.expectedErr, err; fmt.Sprintf("%s", expected) != fmt.Sprintf("%s", actual) {
@@ -78,6 +131,12 @@ func TestValidateE(t *testing.T) {
 			if expected, actual := test.usageSilenced, cmd.SilenceUsage; expected != actual {
 				t.Errorf("expected cmd.SilenceUsage to be %v, actually %v", expected, actual)
 			}
+			if expected, actual := test.expectedOutput, output.String(); expected != actual {
+				t.Errorf("expected output to be %q, actually %q", expected, actual)
+			}
+			if expected, actual := test.output == "json", errors.Is(err, cli.SilentError); err != nil && expected != actual {
+				t.Errorf("expected error to be silenced %v, actually %v", expected, actual)
+			}
 		})
 	}
 }
@@ -161,3 +220,35 @@ func TestExecE(t *testing.T) {
 		})
 	}
 }
+
+func TestExecETraced(t *testing.T) {
+	var exported string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		exported = string(body)
+	}))
+	defer server.Close()
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", server.URL)
+
+	cmd := &cobra.Command{Use: "apply"}
+	config := &cli.Config{
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+	}
+	if err := cli.ExecE(context.Background(), config, &StubExec{execErr: fmt.Errorf("test exec error")})(cmd, []string{}); err == nil {
+		t.Errorf("expected exec error")
+	}
+	for _, expected := range []string{`"name":"apply"`, `"message":"test exec error"`} {
+		if !strings.Contains(exported, expected) {
+			t.Errorf("expected exported traces to contain %s, actually %s", expected, exported)
+		}
+	}
+
+	server.Close()
+	if err := cli.ExecE(context.Background(), config, &StubExec{})(cmd, []string{}); err != nil {
+		t.Errorf("expected no error when the export fails, actually %v", err)
+	}
+	if expected, actual := "unable to export traces", config.Stderr.(*bytes.Buffer).String(); !strings.Contains(actual, expected) {
+		t.Errorf("expected stderr to contain %q, actually %q", expected, actual)
+	}
+}
@@ -17,7 +17,14 @@ limitations under the License.
 package parsers
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
+
+	"sigs.k8s.io/yaml"
 )
 
 func KeyValue(kv string) []string {
@@ -30,3 +37,53 @@ func DeletableKeyValue(kv string) []string {
 	}
 	return []string{kv[0 : len(kv)-1]}
 }
+
+// KeyValueFile parses a YAML or JSON map, or a properties file with one "key=value" per line when
+// properties is true, into sorted "key=value" pairs
+func KeyValueFile(content []byte, properties bool) ([]string, error) {
+	kvs := map[string]string{}
+	if properties {
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for n := 1; scanner.Scan(); n++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+				continue
+			}
+			kv := KeyValue(line)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("line %d: expected \"key=value\", got %q", n, line)
+			}
+			kvs[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else {
+		m := map[string]interface{}{}
+		// keep numbers as written rather than as floats
+		useNumber := func(d *json.Decoder) *json.Decoder {
+			d.UseNumber()
+			return d
+		}
+		if err := yaml.Unmarshal(content, &m, useNumber); err != nil {
+			return nil, err
+		}
+		for k, v := range m {
+			switch v.(type) {
+			case map[string]interface{}, []interface{}:
+				return nil, fmt.Errorf("value for %q must be a string", k)
+			case nil:
+				kvs[k] = ""
+			default:
+				kvs[k] = fmt.Sprint(v)
+			}
+		}
+	}
+
+	pairs := make([]string, 0, len(kvs))
+	for k, v := range kvs {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(pairs)
+	return pairs, nil
+}
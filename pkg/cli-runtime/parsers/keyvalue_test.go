@@ -71,3 +71,68 @@ func TestDeletableKeyValue(t *testing.T) {
 		})
 	}
 }
+
+func TestKeyValueFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		properties  bool
+		expected    []string
+		shouldError bool
+	}{{
+		name: "yaml",
+		content: `
+team: payments
+cost-center: 1234
+critical: true
+`,
+		expected: []string{"cost-center=1234", "critical=true", "team=payments"},
+	}, {
+		name:     "json",
+		content:  `{"team": "payments", "replicas": 10000000}`,
+		expected: []string{"replicas=10000000", "team=payments"},
+	}, {
+		name:        "yaml nested value",
+		content:     `team: {name: payments}`,
+		shouldError: true,
+	}, {
+		name:        "yaml not a map",
+		content:     `- team`,
+		shouldError: true,
+	}, {
+		name: "properties",
+		content: `
+# team ownership
+team = payments
+! legacy comment
+owner=jane@example.com
+url=https://example.com/?a=b
+`,
+		properties: true,
+		expected:   []string{"owner=jane@example.com", "team=payments", "url=https://example.com/?a=b"},
+	}, {
+		name:        "properties invalid line",
+		content:     "team",
+		properties:  true,
+		shouldError: true,
+	}, {
+		name:     "empty",
+		content:  "",
+		expected: []string{},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := parsers.KeyValueFile([]byte(test.content), test.properties)
+			if (err != nil) != test.shouldError {
+				t.Fatalf("KeyValueFile() shouldError %v, got %v", test.shouldError, err)
+			}
+			if test.shouldError {
+				return
+			}
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Errorf("KeyValueFile() = (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/fatih/color"
@@ -47,12 +48,45 @@ type Object interface {
 }
 
 func ExportResource(obj Object, format OutputFormat, scheme *runtime.Scheme) (string, error) {
+	u, err := exportObject(obj, scheme)
+	if err != nil {
+		return "", err
+	}
+	return printObject(u, format)
+}
+
+// ExportResources exports each object like ExportResource, as a multi-document YAML stream or
+// a JSON array, ready to be committed to a GitOps repository
+func ExportResources(objList []Object, format OutputFormat, scheme *runtime.Scheme) (string, error) {
+	exported := []interface{}{}
+	docs := []string{}
+	for _, o := range objList {
+		u, err := exportObject(o, scheme)
+		if err != nil {
+			return "", err
+		}
+		exported = append(exported, u)
+		if format == OutputFormatYaml || format == OutputFormatYml {
+			doc, err := printObject(u, format)
+			if err != nil {
+				return "", err
+			}
+			docs = append(docs, doc)
+		}
+	}
+	if format == OutputFormatYaml || format == OutputFormatYml {
+		return strings.Join(docs, "\n"), nil
+	}
+	return printObject(exported, format)
+}
+
+func exportObject(obj Object, scheme *runtime.Scheme) (map[string]interface{}, error) {
 	copy := obj.DeepCopyObject().(Object)
 
 	// force apiVersion and kind to be set
 	gvks, _, err := scheme.ObjectKinds(obj)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	copy.SetGroupVersionKind(gvks[0])
 
@@ -70,13 +104,13 @@ func ExportResource(obj Object, format OutputFormat, scheme *runtime.Scheme) (st
 	// remove status and other nuisance fields
 	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(copy)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
 	unstructured.RemoveNestedField(u, "status")
 
-	return printObject(u, format)
+	return u, nil
 }
 
 func setGVK(obj Object, scheme *runtime.Scheme) (Object, error) {
@@ -99,6 +133,31 @@ func OutputResource(obj Object, format OutputFormat, scheme *runtime.Scheme) (st
 	return printObject(copy, format)
 }
 
+// OutputResourceWithFields outputs the object like OutputResource, with additional top level fields
+// next to its apiVersion, kind, metadata, spec and status
+func OutputResourceWithFields(obj Object, fields map[string]interface{}, format OutputFormat, scheme *runtime.Scheme) (string, error) {
+	copy, err := setGVK(obj, scheme)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(copy)
+	if err != nil {
+		return "", err
+	}
+	// the fields of the object are kept raw, so they are output in the same order as without the
+	// additional fields
+	out := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return "", err
+	}
+	for key, value := range fields {
+		if out[key], err = json.Marshal(value); err != nil {
+			return "", err
+		}
+	}
+	return printObject(out, format)
+}
+
 func OutputResources(objList []Object, format OutputFormat, scheme *runtime.Scheme) (string, error) {
 	updatedList := []Object{}
 
@@ -131,6 +190,12 @@ var (
 	DiffSubtractionColor = color.New(color.FgRed)
 	DiffUnchangedColor   = color.New()
 	DiffContextToShow    = 4
+
+	// DiffAdditionHighlightColor and DiffSubtractionHighlightColor mark the words that changed within a
+	// line replaced by another, when DiffHighlightWords is set and colors are enabled
+	DiffAdditionHighlightColor    = color.New(color.FgGreen, color.ReverseVideo)
+	DiffSubtractionHighlightColor = color.New(color.FgRed, color.ReverseVideo)
+	DiffHighlightWords            = true
 )
 
 // ResourceDiff returns the results of diffing left and right as an pretty
@@ -142,6 +207,8 @@ var (
 // the line.
 // When the right and left are equal it will prepend a "   |" before
 // the line.
+// When colors are enabled, the words that changed between a removed line
+// and the line added in its place are highlighted.
 func ResourceDiff(left, right Object, scheme *runtime.Scheme) (string, bool, error) {
 	leftLines, err := yamlLines(left, scheme)
 	if err != nil {
@@ -153,6 +220,10 @@ func ResourceDiff(left, right Object, scheme *runtime.Scheme) (string, bool, err
 	}
 
 	diff := difflib.Diff(leftLines, rightLines)
+	pairs := map[int]int{}
+	if DiffHighlightWords && !color.NoColor {
+		pairs = replacedLines(diff)
+	}
 
 	var sb strings.Builder
 	inElipsis := false
@@ -163,10 +234,20 @@ func ResourceDiff(left, right Object, scheme *runtime.Scheme) (string, bool, err
 		case difflib.RightOnly:
 			inElipsis = false
 			hasDiff = true
+			if pair, ok := pairs[lineNum]; ok {
+				_, added := diffWords(diff[pair].Payload, record.Payload)
+				sb.WriteString(highlightedLine(DiffAdditionColor.Sprintf("%3s %3d + |", "", record.LineRight+1), added, DiffAdditionColor, DiffAdditionHighlightColor))
+				continue
+			}
 			sb.WriteString(DiffAdditionColor.Sprintf("%3s %3d + |%s\n", "", record.LineRight+1, record.Payload))
 		case difflib.LeftOnly:
 			inElipsis = false
 			hasDiff = true
+			if pair, ok := pairs[lineNum]; ok {
+				removed, _ := diffWords(record.Payload, diff[pair].Payload)
+				sb.WriteString(highlightedLine(DiffSubtractionColor.Sprintf("%3d %3s - |", record.LineLeft+1, ""), removed, DiffSubtractionColor, DiffSubtractionHighlightColor))
+				continue
+			}
 			sb.WriteString(DiffSubtractionColor.Sprintf("%3d %3s - |%s\n", record.LineLeft+1, "", record.Payload))
 		case difflib.Common:
 			if !inContext(lineNum, diff) {
@@ -183,6 +264,85 @@ func ResourceDiff(left, right Object, scheme *runtime.Scheme) (string, bool, err
 	return sb.String(), !hasDiff, nil
 }
 
+// replacedLines pairs each removed line with the line added in its place, by position within a block of
+// removed lines followed by a block of added lines. Only lines sharing words are paired, both ways.
+func replacedLines(diff []difflib.DiffRecord) map[int]int {
+	pairs := map[int]int{}
+	for i := 0; i < len(diff); {
+		if diff[i].Delta != difflib.LeftOnly {
+			i++
+			continue
+		}
+		removed := 0
+		for i+removed < len(diff) && diff[i+removed].Delta == difflib.LeftOnly {
+			removed++
+		}
+		added := 0
+		for i+removed+added < len(diff) && diff[i+removed+added].Delta == difflib.RightOnly {
+			added++
+		}
+		for n := 0; n < min(removed, added); n++ {
+			left, right := i+n, i+removed+n
+			if sharesWords(diff[left].Payload, diff[right].Payload) {
+				pairs[left] = right
+				pairs[right] = left
+			}
+		}
+		i += removed + added
+	}
+	return pairs
+}
+
+// diffWordsPattern splits a line into words, runs of whitespace and single punctuation characters
+var diffWordsPattern = regexp.MustCompile(`\w+|\s+|[^\w\s]`)
+
+// diffWord is a segment of a line, changed when it is not part of the other line
+type diffWord struct {
+	text    string
+	changed bool
+}
+
+// diffWords returns the segments of both lines, marking the words removed from left and added to right
+func diffWords(left, right string) ([]diffWord, []diffWord) {
+	removed, added := []diffWord{}, []diffWord{}
+	for _, record := range difflib.Diff(diffWordsPattern.FindAllString(left, -1), diffWordsPattern.FindAllString(right, -1)) {
+		switch record.Delta {
+		case difflib.LeftOnly:
+			removed = append(removed, diffWord{text: record.Payload, changed: true})
+		case difflib.RightOnly:
+			added = append(added, diffWord{text: record.Payload, changed: true})
+		case difflib.Common:
+			removed = append(removed, diffWord{text: record.Payload})
+			added = append(added, diffWord{text: record.Payload})
+		}
+	}
+	return removed, added
+}
+
+func sharesWords(left, right string) bool {
+	removed, _ := diffWords(left, right)
+	for _, word := range removed {
+		if !word.changed && strings.TrimSpace(word.text) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func highlightedLine(prefix string, words []diffWord, lineColor, highlightColor *color.Color) string {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	for _, word := range words {
+		if word.changed {
+			sb.WriteString(highlightColor.Sprint(word.text))
+		} else {
+			sb.WriteString(lineColor.Sprint(word.text))
+		}
+	}
+	sb.WriteString(lineColor.Sprint("\n"))
+	return sb.String()
+}
+
 func inContext(lineNum int, diff []difflib.DiffRecord) bool {
 	start := max(0, lineNum-DiffContextToShow)
 	end := min(len(diff), lineNum+DiffContextToShow+1)
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -254,6 +255,126 @@ spec: {}
 	}
 }
 
+func TestExportResources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	cartov1alpha1.AddToScheme(scheme)
+
+	objs := []printer.Object{
+		&cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "my-workload",
+				Namespace:       "default",
+				ResourceVersion: "999",
+				Labels: map[string]string{
+					"name": "value",
+				},
+			},
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: "my-image",
+			},
+			Status: cartov1alpha1.WorkloadStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:   cartov1alpha1.WorkloadConditionReady,
+						Status: metav1.ConditionTrue,
+					},
+				},
+			},
+		},
+		&cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "another-workload",
+				Namespace:         "default",
+				CreationTimestamp: metav1.Now(),
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		objs        []printer.Object
+		format      printer.OutputFormat
+		want        string
+		shouldError bool
+	}{{
+		name:   "empty",
+		format: printer.OutputFormatYaml,
+		objs:   []printer.Object{},
+		want:   ``,
+	}, {
+		name:   "export in yaml",
+		format: printer.OutputFormatYaml,
+		objs:   objs,
+		want: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  labels:
+    name: value
+  name: my-workload
+  namespace: default
+spec:
+  image: my-image
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: another-workload
+  namespace: default
+spec: {}
+`,
+	}, {
+		name:   "export in json",
+		format: printer.OutputFormatJson,
+		objs:   objs,
+		want: `
+[
+	{
+		"apiVersion": "carto.run/v1alpha1",
+		"kind": "Workload",
+		"metadata": {
+			"labels": {
+				"name": "value"
+			},
+			"name": "my-workload",
+			"namespace": "default"
+		},
+		"spec": {
+			"image": "my-image"
+		}
+	},
+	{
+		"apiVersion": "carto.run/v1alpha1",
+		"kind": "Workload",
+		"metadata": {
+			"name": "another-workload",
+			"namespace": "default"
+		},
+		"spec": {}
+	}
+]
+`,
+	}, {
+		name:        "unknown format",
+		format:      printer.OutputFormat("table"),
+		objs:        objs,
+		shouldError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := printer.ExportResources(test.objs, test.format, scheme)
+			if (err != nil) != test.shouldError {
+				t.Errorf("ExportResources() error = %v, expected %v", err, test.shouldError)
+			}
+			if diff := cmp.Diff(strings.TrimSpace(test.want), got); diff != "" {
+				t.Errorf("ExportResources() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
 func TestOutputResource(t *testing.T) {
 	scheme := runtime.NewScheme()
 	cartov1alpha1.AddToScheme(scheme)
@@ -465,6 +586,127 @@ status:
 	}
 }
 
+func TestOutputResourceWithFields(t *testing.T) {
+	scheme := runtime.NewScheme()
+	cartov1alpha1.AddToScheme(scheme)
+
+	workload := &cartov1alpha1.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-workload",
+			Namespace: "default",
+		},
+		Spec: cartov1alpha1.WorkloadSpec{
+			Image: "ubuntu:bionic",
+		},
+		Status: cartov1alpha1.WorkloadStatus{
+			SupplyChainRef: cartov1alpha1.ObjectReference{
+				Name: "my-supply-chain",
+			},
+		},
+	}
+	fields := map[string]interface{}{
+		"summary": map[string]interface{}{
+			"supplyChain": "my-supply-chain",
+			"pods": map[string]int{
+				"total": 1,
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		fields       map[string]interface{}
+		want         string
+		shouldError  bool
+		outputFormat printer.OutputFormat
+	}{{
+		name:         "print output with yaml",
+		outputFormat: printer.OutputFormatYaml,
+		fields:       fields,
+		want: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  image: ubuntu:bionic
+status:
+  supplyChainRef:
+    name: my-supply-chain
+summary:
+  pods:
+    total: 1
+  supplyChain: my-supply-chain
+`,
+	}, {
+		name:         "print output with json",
+		outputFormat: printer.OutputFormatJson,
+		fields:       fields,
+		want: `
+{
+	"apiVersion": "carto.run/v1alpha1",
+	"kind": "Workload",
+	"metadata": {
+		"name": "my-workload",
+		"namespace": "default",
+		"creationTimestamp": null
+	},
+	"spec": {
+		"image": "ubuntu:bionic"
+	},
+	"status": {
+		"supplyChainRef": {
+			"name": "my-supply-chain"
+		}
+	},
+	"summary": {
+		"pods": {
+			"total": 1
+		},
+		"supplyChain": "my-supply-chain"
+	}
+}
+`,
+	}, {
+		name:         "no fields",
+		outputFormat: printer.OutputFormatYaml,
+		want: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  image: ubuntu:bionic
+status:
+  supplyChainRef:
+    name: my-supply-chain
+`,
+	}, {
+		name:         "not valid output",
+		outputFormat: "myFormat",
+		fields:       fields,
+		shouldError:  true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := printer.OutputResourceWithFields(workload, test.fields, test.outputFormat, scheme)
+			if (err != nil) != test.shouldError {
+				t.Errorf("OutputResourceWithFields() error = %v, expected %v", err, test.shouldError)
+			}
+			if diff := cmp.Diff(strings.TrimSpace(test.want), got); diff != "" {
+				t.Errorf("OutputResourceWithFields() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
 func TestOutputResources(t *testing.T) {
 	scheme := runtime.NewScheme()
 	cartov1alpha1.AddToScheme(scheme)
@@ -993,3 +1235,70 @@ func TestResourceDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceDiffHighlightWords(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	scheme := runtime.NewScheme()
+	cartov1alpha1.AddToScheme(scheme)
+
+	workload := func(image string) *cartov1alpha1.Workload {
+		return &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "change",
+			},
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: image,
+			},
+		}
+	}
+	removed := printer.DiffSubtractionColor.Sprint
+	removedWord := printer.DiffSubtractionHighlightColor.Sprint
+	added := printer.DiffAdditionColor.Sprint
+	addedWord := printer.DiffAdditionHighlightColor.Sprint
+	unchanged := printer.DiffUnchangedColor.Sprint
+
+	tests := []struct {
+		name      string
+		highlight bool
+		want      string
+	}{{
+		name:      "highlight changed words",
+		highlight: true,
+		want: unchanged("...\n") +
+			unchanged("  3,  3   |kind: Workload\n") +
+			unchanged("  4,  4   |metadata:\n") +
+			unchanged("  5,  5   |  name: change\n") +
+			unchanged("  6,  6   |spec:\n") +
+			removed("  7     - |") + removed("  ") + removed("image") + removed(":") + removed(" ") + removed("ubuntu") + removed(":") + removedWord("bionic") + removed("\n") +
+			added("      7 + |") + added("  ") + added("image") + added(":") + added(" ") + added("ubuntu") + added(":") + addedWord("focal") + added("\n"),
+	}, {
+		name:      "highlight disabled",
+		highlight: false,
+		want: unchanged("...\n") +
+			unchanged("  3,  3   |kind: Workload\n") +
+			unchanged("  4,  4   |metadata:\n") +
+			unchanged("  5,  5   |  name: change\n") +
+			unchanged("  6,  6   |spec:\n") +
+			removed("  7     - |  image: ubuntu:bionic\n") +
+			added("      7 + |  image: ubuntu:focal\n"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			highlight := printer.DiffHighlightWords
+			printer.DiffHighlightWords = test.highlight
+			defer func() { printer.DiffHighlightWords = highlight }()
+
+			got, _, err := printer.ResourceDiff(workload("ubuntu:bionic"), workload("ubuntu:focal"), scheme)
+			if err != nil {
+				t.Fatalf("ResourceDiff() error = %v", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ResourceDiff() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// error classes a Policy can retry on
+const (
+	Conflict    = "conflict"
+	Timeout     = "timeout"
+	Throttled   = "throttled"
+	Unavailable = "unavailable"
+)
+
+var ErrorClasses = []string{Conflict, Timeout, Throttled, Unavailable}
+
+// TransientErrorClasses are the classes of server side errors (429 and 5xx) that are expected to
+// clear up on their own
+var TransientErrorClasses = []string{Timeout, Throttled, Unavailable}
+
+// Rejected reports whether the server turned the request away before processing it (429 or 503),
+// only then is it safe to repeat a request that changes the cluster
+func Rejected(err error) bool {
+	return apierrs.IsTooManyRequests(err) || apierrs.IsServiceUnavailable(err)
+}
+
+// Classify returns the class of an API error, or an empty string when the error is not retryable
+func Classify(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case apierrs.IsConflict(err):
+		return Conflict
+	case apierrs.IsTimeout(err), apierrs.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return Timeout
+	case apierrs.IsTooManyRequests(err):
+		return Throttled
+	case apierrs.IsServiceUnavailable(err), apierrs.IsInternalError(err):
+		return Unavailable
+	}
+	return ""
+}
+
+// Policy retries an operation failing with one of the On error classes up to Retries times,
+// waiting Backoff between attempts. When Factor is greater than 1 the wait is multiplied by it
+// after each attempt for an exponential backoff. When If is set only the errors it accepts are
+// retried.
+type Policy struct {
+	On      []string
+	If      func(err error) bool
+	Retries int
+	Backoff time.Duration
+	Factor  float64
+}
+
+// RetryFunc is notified before each retry with the attempt number (starting at 1) and the
+// error that caused it
+type RetryFunc = func(attempt int, class string, err error)
+
+// Do calls fn until it succeeds, fails with an error the policy does not retry, or the retries
+// are exhausted. The last error is returned.
+func (p Policy) Do(ctx context.Context, fn func() error, onRetry RetryFunc) error {
+	backoff := p.Backoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt > p.Retries {
+			return err
+		}
+		class := Classify(err)
+		if !p.retries(class) || (p.If != nil && !p.If(err)) {
+			return err
+		}
+		if onRetry != nil {
+			onRetry(attempt, class, err)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		if p.Factor > 1 {
+			backoff = time.Duration(float64(backoff) * p.Factor)
+		}
+	}
+}
+
+func (p Policy) retries(class string) bool {
+	if class == "" {
+		return false
+	}
+	for _, c := range p.On {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+type disabledStashKey struct{}
+
+// StashDisabled turns off the retries of the requests made with the context, for an operation that
+// is retried as a whole not to multiply the attempts
+func StashDisabled(ctx context.Context) context.Context {
+	return context.WithValue(ctx, disabledStashKey{}, true)
+}
+
+// IsDisabled returns true when the retries of the requests made with the context are turned off
+func IsDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(disabledStashKey{}).(bool)
+	return disabled
+}
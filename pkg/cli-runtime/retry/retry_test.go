@@ -0,0 +1,213 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retry_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/retry"
+)
+
+func TestClassify(t *testing.T) {
+	gr := schema.GroupResource{Group: "carto.run", Resource: "workloads"}
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{{
+		name:     "nil",
+		err:      nil,
+		expected: "",
+	}, {
+		name:     "conflict",
+		err:      apierrs.NewConflict(gr, "my-workload", fmt.Errorf("modified")),
+		expected: retry.Conflict,
+	}, {
+		name:     "wrapped conflict",
+		err:      fmt.Errorf("updating: %w", apierrs.NewConflict(gr, "my-workload", fmt.Errorf("modified"))),
+		expected: retry.Conflict,
+	}, {
+		name:     "timeout",
+		err:      apierrs.NewTimeoutError("timeout", 1),
+		expected: retry.Timeout,
+	}, {
+		name:     "server timeout",
+		err:      apierrs.NewServerTimeout(gr, "update", 1),
+		expected: retry.Timeout,
+	}, {
+		name:     "deadline exceeded",
+		err:      context.DeadlineExceeded,
+		expected: retry.Timeout,
+	}, {
+		name:     "throttled",
+		err:      apierrs.NewTooManyRequests("slow down", 1),
+		expected: retry.Throttled,
+	}, {
+		name:     "unavailable",
+		err:      apierrs.NewServiceUnavailable("down"),
+		expected: retry.Unavailable,
+	}, {
+		name:     "internal error",
+		err:      apierrs.NewInternalError(fmt.Errorf("boom")),
+		expected: retry.Unavailable,
+	}, {
+		name:     "not retryable",
+		err:      apierrs.NewNotFound(gr, "my-workload"),
+		expected: "",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := retry.Classify(test.err); actual != test.expected {
+				t.Errorf("Classify() wanted %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPolicyDo(t *testing.T) {
+	gr := schema.GroupResource{Group: "carto.run", Resource: "workloads"}
+	conflict := apierrs.NewConflict(gr, "my-workload", fmt.Errorf("modified"))
+	notFound := apierrs.NewNotFound(gr, "my-workload")
+	throttled := apierrs.NewTooManyRequests("slow down", 1)
+	unavailable := apierrs.NewServiceUnavailable("unavailable")
+	internal := apierrs.NewInternalError(fmt.Errorf("boom"))
+
+	tests := []struct {
+		name            string
+		policy          retry.Policy
+		errs            []error
+		expectedErr     error
+		expectedCalls   int
+		expectedRetries []string
+	}{{
+		name:          "success",
+		policy:        retry.Policy{On: []string{retry.Conflict}, Retries: 3},
+		errs:          []error{nil},
+		expectedCalls: 1,
+	}, {
+		name:            "retry until success",
+		policy:          retry.Policy{On: []string{retry.Conflict}, Retries: 3},
+		errs:            []error{conflict, conflict, nil},
+		expectedCalls:   3,
+		expectedRetries: []string{"1 conflict", "2 conflict"},
+	}, {
+		name:            "retries exhausted",
+		policy:          retry.Policy{On: []string{retry.Conflict}, Retries: 2},
+		errs:            []error{conflict, conflict, conflict, nil},
+		expectedErr:     conflict,
+		expectedCalls:   3,
+		expectedRetries: []string{"1 conflict", "2 conflict"},
+	}, {
+		name:          "class not in policy",
+		policy:        retry.Policy{On: []string{retry.Timeout}, Retries: 3},
+		errs:          []error{conflict, nil},
+		expectedErr:   conflict,
+		expectedCalls: 1,
+	}, {
+		name:          "not retryable",
+		policy:        retry.Policy{On: retry.ErrorClasses, Retries: 3},
+		errs:          []error{notFound, nil},
+		expectedErr:   notFound,
+		expectedCalls: 1,
+	}, {
+		name:            "transient errors",
+		policy:          retry.Policy{On: retry.TransientErrorClasses, Retries: 3, Factor: 2},
+		errs:            []error{throttled, unavailable, nil},
+		expectedCalls:   3,
+		expectedRetries: []string{"1 throttled", "2 unavailable"},
+	}, {
+		name:          "transient errors do not include conflicts",
+		policy:        retry.Policy{On: retry.TransientErrorClasses, Retries: 3},
+		errs:          []error{conflict, nil},
+		expectedErr:   conflict,
+		expectedCalls: 1,
+	}, {
+		name:            "filtered errors",
+		policy:          retry.Policy{On: retry.TransientErrorClasses, If: retry.Rejected, Retries: 3},
+		errs:            []error{throttled, unavailable, internal, nil},
+		expectedErr:     internal,
+		expectedCalls:   3,
+		expectedRetries: []string{"1 throttled", "2 unavailable"},
+	}, {
+		name:          "no policy",
+		policy:        retry.Policy{},
+		errs:          []error{conflict, nil},
+		expectedErr:   conflict,
+		expectedCalls: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			retries := []string{}
+			err := test.policy.Do(context.Background(), func() error {
+				err := test.errs[calls]
+				calls++
+				return err
+			}, func(attempt int, class string, err error) {
+				retries = append(retries, fmt.Sprintf("%d %s", attempt, class))
+			})
+
+			if err != test.expectedErr {
+				t.Errorf("Do() wanted error %v, got %v", test.expectedErr, err)
+			}
+			if calls != test.expectedCalls {
+				t.Errorf("Do() wanted %d calls, got %d", test.expectedCalls, calls)
+			}
+			if test.expectedRetries == nil {
+				test.expectedRetries = []string{}
+			}
+			if diff := cmp.Diff(test.expectedRetries, retries); diff != "" {
+				t.Errorf("Do() retries (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestPolicyDoExponentialBackoff(t *testing.T) {
+	unavailable := apierrs.NewServiceUnavailable("unavailable")
+	policy := retry.Policy{On: retry.TransientErrorClasses, Retries: 2, Backoff: 10 * time.Millisecond, Factor: 2}
+
+	start := time.Now()
+	err := policy.Do(context.Background(), func() error {
+		return unavailable
+	}, nil)
+	if err != unavailable {
+		t.Errorf("Do() wanted error %v, got %v", unavailable, err)
+	}
+	// waits 10ms then 20ms
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("Do() wanted to wait at least 30ms between attempts, waited %s", elapsed)
+	}
+}
+
+func TestStashDisabled(t *testing.T) {
+	if retry.IsDisabled(context.Background()) {
+		t.Errorf("IsDisabled() wanted false for an empty context")
+	}
+	if !retry.IsDisabled(retry.StashDisabled(context.Background())) {
+		t.Errorf("IsDisabled() wanted true for a stashed context")
+	}
+}
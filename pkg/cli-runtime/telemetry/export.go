@@ -0,0 +1,160 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ScopeName is the instrumentation scope of the exported spans
+const ScopeName = "github.com/vmware-tanzu/apps-cli-plugin"
+
+// OTLP span kind and status codes
+const (
+	spanKindInternal = 1
+	statusCodeOk     = 1
+	statusCodeError  = 2
+)
+
+// Flush exports the ended spans to the OTLP endpoint of the tracer, within its timeout. The
+// exported spans are removed from the tracer even when the export fails, so they are not sent
+// twice.
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.m.Lock()
+	spans := t.ended
+	t.ended = nil
+	t.m.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(t.exportRequest(spans))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range t.Headers {
+		req.Header.Set(key, value)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%s responded %s", t.Endpoint, res.Status)
+	}
+	return nil
+}
+
+// exportRequest is the ExportTraceServiceRequest of the spans, in the JSON encoding of OTLP
+func (t *Tracer) exportRequest(spans []*Span) map[string]interface{} {
+	otlpSpans := make([]map[string]interface{}, 0, len(spans))
+	for _, span := range spans {
+		otlpSpans = append(otlpSpans, span.otlp())
+	}
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": otlpAttributes([]Attribute{String("service.name", t.ServiceName)}),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": ScopeName},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *Span) otlp() map[string]interface{} {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	events := make([]interface{}, 0, len(s.events))
+	for _, e := range s.events {
+		events = append(events, map[string]interface{}{
+			"timeUnixNano": unixNano(e.time),
+			"name":         e.name,
+			"attributes":   otlpAttributes(e.attributes),
+		})
+	}
+	status := map[string]interface{}{"code": statusCodeOk}
+	if s.err != nil {
+		status = map[string]interface{}{"code": statusCodeError, "message": s.err.Error()}
+	}
+	span := map[string]interface{}{
+		"traceId":           s.traceID,
+		"spanId":            s.spanID,
+		"name":              s.name,
+		"kind":              spanKindInternal,
+		"startTimeUnixNano": unixNano(s.start),
+		"endTimeUnixNano":   unixNano(s.end),
+		"attributes":        otlpAttributes(s.attributes),
+		"events":            events,
+		"status":            status,
+	}
+	if s.parentID != "" {
+		span["parentSpanId"] = s.parentID
+	}
+	return span
+}
+
+func otlpAttributes(attributes []Attribute) []interface{} {
+	kvs := make([]interface{}, 0, len(attributes))
+	for _, attribute := range attributes {
+		var value map[string]interface{}
+		switch v := attribute.Value.(type) {
+		case string:
+			value = map[string]interface{}{"stringValue": v}
+		case int64:
+			// 64 bit integers are encoded as strings by the JSON mapping of protobuf
+			value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		case bool:
+			value = map[string]interface{}{"boolValue": v}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+		}
+		kvs = append(kvs, map[string]interface{}{"key": attribute.Key, "value": value})
+	}
+	return kvs
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package telemetry records the traces of a command and exports them to an OpenTelemetry
+// collector with the OTLP/HTTP JSON protocol. Tracing is off unless an OTLP endpoint is set with
+// the standard OTEL_EXPORTER_OTLP_* env vars, every function is a no-op when it is off.
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// env vars of the OpenTelemetry exporter spec read by TracerFromEnv
+const (
+	EndpointEnv       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	TracesEndpointEnv = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	HeadersEnv        = "OTEL_EXPORTER_OTLP_HEADERS"
+	TimeoutEnv        = "OTEL_EXPORTER_OTLP_TIMEOUT"
+	ServiceNameEnv    = "OTEL_SERVICE_NAME"
+	SDKDisabledEnv    = "OTEL_SDK_DISABLED"
+)
+
+// DefaultServiceName is the service.name of the exported spans when OTEL_SERVICE_NAME is not set
+const DefaultServiceName = "tanzu-apps"
+
+const defaultExportTimeout = 10 * time.Second
+
+// Tracer collects the ended spans of a command until they are exported by Flush. A nil Tracer
+// is disabled.
+type Tracer struct {
+	Endpoint    string
+	Headers     map[string]string
+	Timeout     time.Duration
+	ServiceName string
+
+	m     sync.Mutex
+	ended []*Span
+}
+
+// TracerFromEnv returns the tracer configured by the OTEL_* env vars looked up with getenv, or
+// nil when no OTLP endpoint is set or the SDK is disabled
+func TracerFromEnv(getenv func(key string) string) *Tracer {
+	if strings.EqualFold(getenv(SDKDisabledEnv), "true") {
+		return nil
+	}
+	endpoint := getenv(TracesEndpointEnv)
+	if endpoint == "" {
+		if base := getenv(EndpointEnv); base != "" {
+			endpoint = strings.TrimSuffix(base, "/") + "/v1/traces"
+		}
+	}
+	if endpoint == "" {
+		return nil
+	}
+	t := &Tracer{
+		Endpoint:    endpoint,
+		Headers:     parseHeaders(getenv(HeadersEnv)),
+		Timeout:     defaultExportTimeout,
+		ServiceName: DefaultServiceName,
+	}
+	if ms, err := strconv.Atoi(getenv(TimeoutEnv)); err == nil && ms > 0 {
+		t.Timeout = time.Duration(ms) * time.Millisecond
+	}
+	if name := getenv(ServiceNameEnv); name != "" {
+		t.ServiceName = name
+	}
+	return t
+}
+
+// parseHeaders reads the comma separated "key=value" pairs of OTEL_EXPORTER_OTLP_HEADERS, the
+// values may be URL encoded
+func parseHeaders(value string) map[string]string {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			continue
+		}
+		v := strings.TrimSpace(parts[1])
+		if unescaped, err := url.PathUnescape(v); err == nil {
+			v = unescaped
+		}
+		headers[key] = v
+	}
+	return headers
+}
+
+type tracerStashKey struct{}
+type spanStashKey struct{}
+
+// StashTracer sets the tracer the spans started with the context are recorded by
+func StashTracer(ctx context.Context, tracer *Tracer) context.Context {
+	return context.WithValue(ctx, tracerStashKey{}, tracer)
+}
+
+// RetrieveTracer returns the tracer of the context, or nil
+func RetrieveTracer(ctx context.Context) *Tracer {
+	tracer, _ := ctx.Value(tracerStashKey{}).(*Tracer)
+	return tracer
+}
+
+// SpanFromContext returns the span started last with the context, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanStashKey{}).(*Span)
+	return span
+}
+
+// Start begins a span, child of the span of the context if any. The returned context carries the
+// span for the spans and events recorded during the operation. The span is nil when the context
+// has no tracer.
+func Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	tracer := RetrieveTracer(ctx)
+	if tracer == nil {
+		return ctx, nil
+	}
+	span := &Span{
+		tracer:     tracer,
+		name:       name,
+		spanID:     newID(8),
+		start:      time.Now(),
+		attributes: attributes,
+	}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+	} else {
+		span.traceID = newID(16)
+	}
+	return context.WithValue(ctx, spanStashKey{}, span), span
+}
+
+// AddEvent records an event on the span of the context
+func AddEvent(ctx context.Context, name string, attributes ...Attribute) {
+	SpanFromContext(ctx).AddEvent(name, attributes...)
+}
+
+// Span is an operation of a command, a nil Span records nothing
+type Span struct {
+	tracer   *Tracer
+	name     string
+	traceID  string
+	spanID   string
+	parentID string
+	start    time.Time
+
+	m          sync.Mutex
+	end        time.Time
+	attributes []Attribute
+	events     []event
+	err        error
+}
+
+type event struct {
+	name       string
+	time       time.Time
+	attributes []Attribute
+}
+
+// SetAttributes adds attributes to the span
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.attributes = append(s.attributes, attributes...)
+}
+
+// AddEvent records a point in time of the span, like a request sent during the operation
+func (s *Span) AddEvent(name string, attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.events = append(s.events, event{name: name, time: time.Now(), attributes: attributes})
+}
+
+// End completes the span, an error marks the span as failed. Ending a span more than once has no
+// effect.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.m.Lock()
+	if !s.end.IsZero() {
+		s.m.Unlock()
+		return
+	}
+	s.end = time.Now()
+	s.err = err
+	s.m.Unlock()
+
+	s.tracer.m.Lock()
+	defer s.tracer.m.Unlock()
+	s.tracer.ended = append(s.tracer.ended, s)
+}
+
+// Attribute is a key value pair describing a span or an event
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String is a string attribute
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int is an integer attribute
+func Int(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool is a boolean attribute
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Duration is an attribute holding a duration in milliseconds
+func Duration(key string, value time.Duration) Attribute {
+	return Int(key, value.Milliseconds())
+}
+
+// Error is the attribute of the error of an operation, when any
+func Error(err error) []Attribute {
+	if err == nil {
+		return nil
+	}
+	return []Attribute{String("error", err.Error())}
+}
+
+func newID(size int) string {
+	id := make([]byte, size)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
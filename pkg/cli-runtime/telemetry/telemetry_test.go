@@ -0,0 +1,208 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/telemetry"
+)
+
+func TestTracerFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected *telemetry.Tracer
+	}{{
+		name: "disabled without endpoint",
+		env:  map[string]string{},
+	}, {
+		name: "disabled sdk",
+		env: map[string]string{
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318",
+			"OTEL_SDK_DISABLED":           "true",
+		},
+	}, {
+		name: "endpoint",
+		env: map[string]string{
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318/",
+		},
+		expected: &telemetry.Tracer{
+			Endpoint:    "http://collector:4318/v1/traces",
+			Headers:     map[string]string{},
+			Timeout:     10 * time.Second,
+			ServiceName: "tanzu-apps",
+		},
+	}, {
+		name: "traces endpoint",
+		env: map[string]string{
+			"OTEL_EXPORTER_OTLP_ENDPOINT":        "http://collector:4318",
+			"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "https://traces.example.com/otlp",
+			"OTEL_EXPORTER_OTLP_HEADERS":         "api-key=s3cr3t, team=dev%20x,invalid",
+			"OTEL_EXPORTER_OTLP_TIMEOUT":         "2500",
+			"OTEL_SERVICE_NAME":                  "ci-pipeline",
+		},
+		expected: &telemetry.Tracer{
+			Endpoint:    "https://traces.example.com/otlp",
+			Headers:     map[string]string{"api-key": "s3cr3t", "team": "dev x"},
+			Timeout:     2500 * time.Millisecond,
+			ServiceName: "ci-pipeline",
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := telemetry.TracerFromEnv(func(key string) string { return test.env[key] })
+			if test.expected == nil {
+				if actual != nil {
+					t.Errorf("TracerFromEnv() expected nil tracer, got %+v", actual)
+				}
+				return
+			}
+			if actual == nil {
+				t.Fatalf("TracerFromEnv() expected tracer")
+			}
+			if diff := cmp.Diff(test.expected.Endpoint, actual.Endpoint); diff != "" {
+				t.Errorf("Endpoint (-expected, +actual) = %s", diff)
+			}
+			if diff := cmp.Diff(test.expected.Headers, actual.Headers); diff != "" {
+				t.Errorf("Headers (-expected, +actual) = %s", diff)
+			}
+			if diff := cmp.Diff(test.expected.Timeout, actual.Timeout); diff != "" {
+				t.Errorf("Timeout (-expected, +actual) = %s", diff)
+			}
+			if diff := cmp.Diff(test.expected.ServiceName, actual.ServiceName); diff != "" {
+				t.Errorf("ServiceName (-expected, +actual) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	ctx := telemetry.StashTracer(context.TODO(), nil)
+	ctx, span := telemetry.Start(ctx, "apply")
+	if span != nil {
+		t.Errorf("Start() expected nil span without tracer")
+	}
+	telemetry.AddEvent(ctx, "API Request")
+	span.SetAttributes(telemetry.String("key", "value"))
+	span.End(nil)
+	if err := telemetry.RetrieveTracer(ctx).Flush(ctx); err != nil {
+		t.Errorf("Flush() unexpected error: %v", err)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	var requests []map[string]interface{}
+	var apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("api-key")
+		body, _ := ioutil.ReadAll(r.Body)
+		request := map[string]interface{}{}
+		if err := json.Unmarshal(body, &request); err != nil {
+			t.Errorf("invalid export request: %v", err)
+		}
+		requests = append(requests, request)
+	}))
+	defer server.Close()
+
+	tracer := &telemetry.Tracer{
+		Endpoint:    server.URL + "/v1/traces",
+		Headers:     map[string]string{"api-key": "s3cr3t"},
+		Timeout:     time.Second,
+		ServiceName: "tanzu-apps",
+	}
+	ctx := telemetry.StashTracer(context.TODO(), tracer)
+	ctx, root := telemetry.Start(ctx, "tanzu apps workload apply", telemetry.String("cli.command", "tanzu apps workload apply"))
+	telemetry.AddEvent(ctx, "API Request", telemetry.String("k8s.action", "Get"), telemetry.Duration("duration_ms", 1500*time.Millisecond))
+	_, wait := telemetry.Start(ctx, "wait", telemetry.Bool("tail", false))
+	wait.End(fmt.Errorf("timeout"))
+	root.End(nil)
+	root.End(fmt.Errorf("ended twice"))
+
+	if err := tracer.Flush(ctx); err != nil {
+		t.Fatalf("Flush() unexpected error: %v", err)
+	}
+	if err := tracer.Flush(ctx); err != nil {
+		t.Fatalf("Flush() unexpected error: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("Flush() expected 1 export request, got %d", len(requests))
+	}
+	if apiKey != "s3cr3t" {
+		t.Errorf("Flush() expected api-key header, got %q", apiKey)
+	}
+
+	resourceSpans := requests[0]["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	if diff := cmp.Diff(map[string]interface{}{
+		"attributes": []interface{}{
+			map[string]interface{}{"key": "service.name", "value": map[string]interface{}{"stringValue": "tanzu-apps"}},
+		},
+	}, resourceSpans["resource"]); diff != "" {
+		t.Errorf("resource (-expected, +actual) = %s", diff)
+	}
+	spans := resourceSpans["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	waitSpan, rootSpan := spans[0].(map[string]interface{}), spans[1].(map[string]interface{})
+	if rootSpan["traceId"] != waitSpan["traceId"] || rootSpan["spanId"] != waitSpan["parentSpanId"] {
+		t.Errorf("expected wait span to be a child of the root span, got %v and %v", rootSpan, waitSpan)
+	}
+	if _, ok := rootSpan["parentSpanId"]; ok {
+		t.Errorf("expected root span without parent, got %v", rootSpan["parentSpanId"])
+	}
+	if diff := cmp.Diff(map[string]interface{}{"code": float64(2), "message": "timeout"}, waitSpan["status"]); diff != "" {
+		t.Errorf("wait status (-expected, +actual) = %s", diff)
+	}
+	if diff := cmp.Diff(map[string]interface{}{"code": float64(1)}, rootSpan["status"]); diff != "" {
+		t.Errorf("root status (-expected, +actual) = %s", diff)
+	}
+	events := rootSpan["events"].([]interface{})
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if diff := cmp.Diff([]interface{}{
+		map[string]interface{}{"key": "k8s.action", "value": map[string]interface{}{"stringValue": "Get"}},
+		map[string]interface{}{"key": "duration_ms", "value": map[string]interface{}{"intValue": "1500"}},
+	}, events[0].(map[string]interface{})["attributes"]); diff != "" {
+		t.Errorf("event attributes (-expected, +actual) = %s", diff)
+	}
+}
+
+func TestFlushError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	tracer := &telemetry.Tracer{Endpoint: server.URL, Timeout: time.Second}
+	_, span := telemetry.Start(telemetry.StashTracer(context.TODO(), tracer), "apply")
+	span.End(nil)
+	if err := tracer.Flush(context.TODO()); err == nil {
+		t.Errorf("Flush() expected error")
+	}
+}
@@ -26,6 +26,7 @@ type ReactionFunc = rtesting.ReactionFunc
 
 type Action = rtesting.Action
 type GetAction = rtesting.GetAction
+type UpdateAction = rtesting.UpdateAction
 
 var NewFakeClient = rtesting.NewFakeClient
 
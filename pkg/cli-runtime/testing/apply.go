@@ -0,0 +1,396 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// managedObject identifies an object whose field managers are tracked
+type managedObject struct {
+	gvk schema.GroupVersionKind
+	key types.NamespacedName
+}
+
+// Get returns the object with the field managers tracked by the patches, the ones of the object as given
+// otherwise
+func (c *fakeclient) Get(ctx context.Context, key crclient.ObjectKey, obj crclient.Object) error {
+	if err := c.Client.Get(ctx, key, obj); err != nil {
+		return err
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	if managedFields, ok := c.managedFields[managedObject{gvk: gvk, key: key}]; ok {
+		obj.SetManagedFields(managedFields)
+	}
+	return nil
+}
+
+// Patch emulates server-side apply patches, which the fake client does not support, and tracks the field
+// managers of the fields changed by the other patches. Field managers are tracked in the fake client and
+// left out of the objects the fake client creates and updates, so they are not part of the expectations.
+//
+// An apply merges the labels, annotations and top level fields but the status of the object into the
+// existing object, which is then updated, or created when it does not exist. The fields the field manager
+// applied before and no longer applies are removed, unless another field manager owns them. Changing a
+// field owned by another field manager is a conflict, unless forced, and a resource version that is not
+// the one of the existing object is a conflict. The fields are compared as deduced from the values, with
+// lists as atomic values.
+func (c *fakeclient) Patch(ctx context.Context, obj crclient.Object, patch crclient.Patch, opts ...crclient.PatchOption) error {
+	patchOpts := &crclient.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	managed := managedObject{gvk: gvk, key: crclient.ObjectKeyFromObject(obj)}
+	existing, err := c.Scheme().New(gvk)
+	if err != nil {
+		return err
+	}
+	live := existing.(crclient.Object)
+	if err := c.Get(ctx, managed.key, live); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		live = nil
+	}
+
+	if patch.Type() != types.ApplyPatchType {
+		if err := c.Client.Patch(ctx, obj, patch, opts...); err != nil || live == nil {
+			return err
+		}
+		managedFields, err := updateManagedFields(live, obj, patchOpts.FieldManager, gvk.GroupVersion().String())
+		if err != nil {
+			return err
+		}
+		c.managedFields[managed] = managedFields
+		obj.SetManagedFields(managedFields)
+		return nil
+	}
+
+	dryRun := len(patchOpts.DryRun) != 0
+	force := patchOpts.Force != nil && *patchOpts.Force
+	config, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	var liveFields map[string]interface{}
+	var managedFields []metav1.ManagedFieldsEntry
+	if live != nil {
+		if resourceVersion := obj.GetResourceVersion(); resourceVersion != "" && resourceVersion != live.GetResourceVersion() {
+			return apierrs.NewConflict(schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(gvk.Kind) + "s"}, obj.GetName(), fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
+		}
+		if liveFields, err = runtime.DefaultUnstructuredConverter.ToUnstructured(live); err != nil {
+			return err
+		}
+		managedFields = live.GetManagedFields()
+	}
+	merged, managedFields, err := applyManagedFields(liveFields, config, managedFields, patchOpts.FieldManager, gvk.GroupVersion().String(), force)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.Scheme().New(gvk)
+	if err != nil {
+		return err
+	}
+	applied := result.(crclient.Object)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(merged, applied); err != nil {
+		return err
+	}
+	applied.SetManagedFields(nil)
+	switch {
+	case dryRun:
+	case live == nil:
+		err = c.Client.Create(ctx, applied)
+	default:
+		err = c.Client.Update(ctx, applied)
+	}
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		c.managedFields[managed] = managedFields
+	}
+	applied.SetManagedFields(managedFields)
+
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(applied)
+		if err != nil {
+			return err
+		}
+		u.SetUnstructuredContent(content)
+		return nil
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(applied).Elem())
+	return nil
+}
+
+// managedFieldsSet is the set of fields of a field manager
+type managedFieldsSet struct {
+	entry metav1.ManagedFieldsEntry
+	set   *fieldpath.Set
+}
+
+func decodeManagedFields(managedFields []metav1.ManagedFieldsEntry) ([]managedFieldsSet, error) {
+	sets := []managedFieldsSet{}
+	for _, entry := range managedFields {
+		set := &fieldpath.Set{}
+		if entry.FieldsV1 != nil {
+			if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+				return nil, err
+			}
+		}
+		sets = append(sets, managedFieldsSet{entry: entry, set: set})
+	}
+	return sets, nil
+}
+
+func encodeManagedFields(sets []managedFieldsSet) ([]metav1.ManagedFieldsEntry, error) {
+	managedFields := []metav1.ManagedFieldsEntry{}
+	for _, s := range sets {
+		if s.set.Empty() {
+			continue
+		}
+		raw, err := s.set.ToJSON()
+		if err != nil {
+			return nil, err
+		}
+		entry := s.entry
+		entry.FieldsType = "FieldsV1"
+		entry.FieldsV1 = &metav1.FieldsV1{Raw: raw}
+		managedFields = append(managedFields, entry)
+	}
+	return managedFields, nil
+}
+
+// ownedFields are the fields of an object a field manager owns, the labels, annotations and top level fields
+// but the status
+func ownedFields(obj map[string]interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for key, value := range obj {
+		switch key {
+		case "apiVersion", "kind", "status":
+		case "metadata":
+			metadata := map[string]interface{}{}
+			if m, ok := value.(map[string]interface{}); ok {
+				for _, field := range []string{"labels", "annotations"} {
+					if v, ok := m[field]; ok && v != nil {
+						metadata[field] = v
+					}
+				}
+			}
+			fields[key] = metadata
+		default:
+			if value != nil {
+				fields[key] = value
+			}
+		}
+	}
+	return fields
+}
+
+// withOwnedFields replaces the owned fields of the object with the fields
+func withOwnedFields(obj, fields map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	metadata := map[string]interface{}{}
+	for key, value := range obj {
+		switch key {
+		case "apiVersion", "kind", "status":
+			result[key] = value
+		case "metadata":
+			m, _ := value.(map[string]interface{})
+			for field, v := range m {
+				if field != "labels" && field != "annotations" {
+					metadata[field] = v
+				}
+			}
+		}
+	}
+	for key, value := range fields {
+		if key != "metadata" {
+			result[key] = value
+			continue
+		}
+		m, _ := value.(map[string]interface{})
+		for field, v := range m {
+			metadata[field] = v
+		}
+	}
+	result["metadata"] = metadata
+	return result
+}
+
+// applyManagedFields merges the config applied by the field manager into the live object, nil when it does
+// not exist, the way the API server does, and returns the merged object along with its field managers
+func applyManagedFields(live, config map[string]interface{}, managedFields []metav1.ManagedFieldsEntry, manager, apiVersion string, force bool) (map[string]interface{}, []metav1.ManagedFieldsEntry, error) {
+	base := config
+	if live != nil {
+		base = live
+	}
+	liveValue, err := typed.DeducedParseableType.FromUnstructured(ownedFields(base))
+	if err != nil {
+		return nil, nil, err
+	}
+	if live == nil {
+		if liveValue, err = typed.DeducedParseableType.FromUnstructured(map[string]interface{}{"metadata": map[string]interface{}{}}); err != nil {
+			return nil, nil, err
+		}
+	}
+	configValue, err := typed.DeducedParseableType.FromUnstructured(ownedFields(config))
+	if err != nil {
+		return nil, nil, err
+	}
+	merged, err := liveValue.Merge(configValue)
+	if err != nil {
+		return nil, nil, err
+	}
+	configSet, err := configValue.ToFieldSet()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sets, err := decodeManagedFields(managedFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	applier := -1
+	others := fieldpath.NewSet()
+	for i, s := range sets {
+		if s.entry.Manager == manager && s.entry.Operation == metav1.ManagedFieldsOperationApply {
+			applier = i
+			continue
+		}
+		others = others.Union(s.set)
+	}
+	if applier != -1 {
+		// remove the fields applied last time and no longer applied, unless another field manager owns them
+		merged = merged.RemoveItems(sets[applier].set.Difference(configSet).Difference(others))
+	}
+
+	compare, err := liveValue.Compare(merged)
+	if err != nil {
+		return nil, nil, err
+	}
+	changed := compare.Modified.Union(compare.Added)
+	causes := []metav1.StatusCause{}
+	for i, s := range sets {
+		if i == applier {
+			continue
+		}
+		conflicts := s.set.Intersection(changed)
+		conflicts.Iterate(func(path fieldpath.Path) {
+			causes = append(causes, metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldManagerConflict,
+				Message: fmt.Sprintf("conflict with %q", s.entry.Manager),
+				Field:   path.String(),
+			})
+		})
+		sets[i].set = s.set.Difference(changed).Difference(compare.Removed)
+	}
+	if len(causes) != 0 && !force {
+		sort.Slice(causes, func(i, j int) bool { return causes[i].Field < causes[j].Field })
+		messages := []string{}
+		for _, cause := range causes {
+			messages = append(messages, fmt.Sprintf("%s: %s", cause.Message, cause.Field))
+		}
+		return nil, nil, apierrs.NewApplyConflict(causes, fmt.Sprintf("Apply failed with %d conflict(s): %s", len(causes), strings.Join(messages, ", ")))
+	}
+
+	entry := metav1.ManagedFieldsEntry{Manager: manager, Operation: metav1.ManagedFieldsOperationApply, APIVersion: apiVersion}
+	if applier == -1 {
+		sets = append(sets, managedFieldsSet{entry: entry, set: configSet})
+	} else {
+		sets[applier].set = configSet
+	}
+	managedFields, err = encodeManagedFields(sets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields, ok := merged.AsValue().Unstructured().(map[string]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("unable to merge the config of %q", manager)
+	}
+	return withOwnedFields(base, fields), managedFields, nil
+}
+
+// updateManagedFields returns the field managers of an object changed by a patch of the field manager, the
+// way the API server tracks them for updates. The field manager owns the fields it changed, which other
+// field managers no longer own, and no field manager owns the removed fields.
+func updateManagedFields(live, updated crclient.Object, manager, apiVersion string) ([]metav1.ManagedFieldsEntry, error) {
+	liveFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return nil, err
+	}
+	updatedFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(updated)
+	if err != nil {
+		return nil, err
+	}
+	liveValue, err := typed.DeducedParseableType.FromUnstructured(ownedFields(liveFields))
+	if err != nil {
+		return nil, err
+	}
+	updatedValue, err := typed.DeducedParseableType.FromUnstructured(ownedFields(updatedFields))
+	if err != nil {
+		return nil, err
+	}
+	compare, err := liveValue.Compare(updatedValue)
+	if err != nil {
+		return nil, err
+	}
+	changed := compare.Modified.Union(compare.Added)
+
+	sets, err := decodeManagedFields(live.GetManagedFields())
+	if err != nil {
+		return nil, err
+	}
+	updater := -1
+	for i, s := range sets {
+		if s.entry.Manager == manager && s.entry.Operation == metav1.ManagedFieldsOperationUpdate {
+			updater = i
+		}
+		sets[i].set = s.set.Difference(changed).Difference(compare.Removed)
+	}
+	if updater == -1 {
+		entry := metav1.ManagedFieldsEntry{Manager: manager, Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: apiVersion}
+		sets = append(sets, managedFieldsSet{entry: entry, set: fieldpath.NewSet()})
+		updater = len(sets) - 1
+	}
+	sets[updater].set = sets[updater].set.Union(changed)
+	return encodeManagedFields(sets)
+}
@@ -62,6 +62,7 @@ func NewFakeCliClient(c crclient.Client) cli.Client {
 	return &fakeclient{
 		defaultNamespace: "default",
 		Client:           c,
+		managedFields:    map[managedObject][]metav1.ManagedFieldsEntry{},
 	}
 }
 
@@ -110,6 +111,8 @@ type FakeCachedDiscoveryClient struct {
 type fakeclient struct {
 	defaultNamespace string
 	crclient.Client
+	// managedFields are the field managers of the objects changed by a patch, by object
+	managedFields map[managedObject][]metav1.ManagedFieldsEntry
 }
 
 func testRESTMapper() meta.RESTMapper {
@@ -147,6 +150,21 @@ func testDynamicResources() []*restmapper.APIGroupResources {
 				},
 			},
 		},
+		{
+			Group: metav1.APIGroup{
+				Name: "services.tanzu.vmware.com",
+				Versions: []metav1.GroupVersionForDiscovery{
+					{GroupVersion: "services.tanzu.vmware.com/v1alpha1", Version: "v1alpha1"},
+				},
+				PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "services.tanzu.vmware.com/v1alpha1", Version: "v1alpha1"},
+			},
+			VersionedResources: map[string][]metav1.APIResource{
+				"v1alpha1": {
+					{Name: "mysqls", Namespaced: true, Kind: "MySQL"},
+					{Name: "postgresqls", Namespaced: true, Kind: "PostgreSQL"},
+				},
+			},
+		},
 	}
 }
 
@@ -24,6 +24,7 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/cobra"
@@ -48,8 +49,8 @@ import (
 // if the object does not exist and create operations will error if the resource does exist).
 //
 // ExpectCreates and ExpectUpdates each contain objects that are compared directly to resources
-// received by the client. ExpectDeletes and ExpectDeleteCollections contain references to the
-// resources impacted by the call since these calls do not receive an object.
+// received by the client. ExpectPatches, ExpectDeletes and ExpectDeleteCollections contain references
+// to the resources impacted by the call since these calls do not receive a full object.
 //
 // Errors can be injected into API calls by reactor functions specified in WithReactors. A
 // ReactionFunc is able to intercept each client operation to observe or mutate the request or
@@ -130,6 +131,10 @@ type CommandTestCase struct {
 	// ExpectUpdates asserts each resource with the resources passed to the Update method of the
 	// fake client in order.
 	ExpectUpdates []client.Object
+	// ExpectPatches asserts references to the Patch method of the fake client in order, along with
+	// the patch. Server-side apply patches are emulated with creates and updates, and are not part
+	// of the patches.
+	ExpectPatches []rtesting.PatchRef
 	// ExpectDeletes assert references to the Delete method of the fake client in order.
 	// Unlike Create and Update, Delete does not receive a full resource, so a reference is used
 	// instead. The Group will be blank for 'core' resources. The Resource is not a Kind, but
@@ -204,6 +209,7 @@ func (tc CommandTestCase) Run(t *testing.T, scheme *runtime.Scheme, cmdFactory f
 			WithReactors:            tc.WithReactors,
 			ExpectCreates:           tc.ExpectCreates,
 			ExpectUpdates:           tc.ExpectUpdates,
+			ExpectPatches:           tc.ExpectPatches,
 			ExpectDeletes:           tc.ExpectDeletes,
 			ExpectDeleteCollections: tc.ExpectDeleteCollections,
 		}
@@ -256,7 +262,9 @@ func (tc CommandTestCase) Run(t *testing.T, scheme *runtime.Scheme, cmdFactory f
 		cmd.SilenceUsage = true
 		cmd.SetArgs(tc.Args)
 
-		c.Stdin = bytes.NewBuffer(tc.Stdin)
+		// stdin is read one byte at a time, like a terminal, so the answers of prompts are not consumed
+		// ahead by the buffered reads of an earlier prompt
+		c.Stdin = iotest.OneByteReader(bytes.NewBuffer(tc.Stdin))
 		output := &bytes.Buffer{}
 		cmd.SetOutput(output)
 		c.Stdout = output
@@ -25,3 +25,9 @@ func ErrMissingFieldWithDetail(field string, detail string) FieldErrors {
 		k8sfield.Required(k8sfield.NewPath(field), detail),
 	}
 }
+
+func ErrForbiddenFieldWithDetail(field string, detail string) FieldErrors {
+	return FieldErrors{
+		k8sfield.Forbidden(k8sfield.NewPath(field), detail),
+	}
+}
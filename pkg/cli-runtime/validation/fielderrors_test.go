@@ -56,3 +56,29 @@ func TestErrMissingFieldWithDetail(t *testing.T) {
 		})
 	}
 }
+
+func TestErrForbiddenFieldWithDetail(t *testing.T) {
+	tests := []struct {
+		testName string
+		field    string
+		msg      string
+		expected validation.FieldErrors
+	}{
+		{
+			testName: "valid with msg",
+			expected: validation.FieldErrors{k8sfield.Forbidden(k8sfield.NewPath(flags.LabelFlagName), "protected label")},
+			field:    flags.LabelFlagName,
+			msg:      "protected label",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			expected := test.expected
+			actual := validation.ErrForbiddenFieldWithDetail(test.field, test.msg)
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Errorf("%s() = (-expected, +actual): %s", test.testName, diff)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package validation
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/parsers"
@@ -84,3 +85,33 @@ func JsonOrYamlKeyValues(kvs []string, field string) FieldErrors {
 	}
 	return errs
 }
+
+// DeletableIntKeyValues validates "key=value" pairs whose value is an integer, or "key-" to remove the key
+func DeletableIntKeyValues(kvs []string, field string) FieldErrors {
+	return deletableTypedKeyValues(kvs, field, func(value string) error {
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err
+	})
+}
+
+// DeletableBoolKeyValues validates "key=value" pairs whose value is a boolean, or "key-" to remove the key
+func DeletableBoolKeyValues(kvs []string, field string) FieldErrors {
+	return deletableTypedKeyValues(kvs, field, func(value string) error {
+		_, err := strconv.ParseBool(value)
+		return err
+	})
+}
+
+func deletableTypedKeyValues(kvs []string, field string, parse func(string) error) FieldErrors {
+	errs := FieldErrors{}
+	for i, kv := range kvs {
+		kvErrs := DeletableKeyValue(kv, CurrentField)
+		if len(kvErrs) == 0 {
+			if keyValue := parsers.DeletableKeyValue(kv); len(keyValue) > 1 && parse(keyValue[1]) != nil {
+				kvErrs = ErrInvalidValue(kv, CurrentField)
+			}
+		}
+		errs = errs.Also(kvErrs.ViaFieldIndex(field, i))
+	}
+	return errs
+}
@@ -211,3 +211,69 @@ func TestJsonOrYamlKeyValues(t *testing.T) {
 		})
 	}
 }
+
+func TestDeletableIntKeyValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected validation.FieldErrors
+		value    []string
+	}{{
+		name:     "valid",
+		expected: validation.FieldErrors{},
+		value:    []string{"replicas=3", "offset=-1"},
+	}, {
+		name:     "delete",
+		expected: validation.FieldErrors{},
+		value:    []string{"replicas-"},
+	}, {
+		name:     "invalid key value",
+		expected: validation.ErrInvalidValue("replicas", validation.CurrentField).ViaFieldIndex(clitesting.TestField, 0),
+		value:    []string{"replicas"},
+	}, {
+		name:     "not an integer",
+		expected: validation.ErrInvalidValue("ratio=0.5", validation.CurrentField).ViaFieldIndex(clitesting.TestField, 1),
+		value:    []string{"replicas=3", "ratio=0.5"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := validation.DeletableIntKeyValues(test.value, clitesting.TestField)
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Errorf("DeletableIntKeyValues() = (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
+
+func TestDeletableBoolKeyValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected validation.FieldErrors
+		value    []string
+	}{{
+		name:     "valid",
+		expected: validation.FieldErrors{},
+		value:    []string{"debug=true", "cache=false"},
+	}, {
+		name:     "delete",
+		expected: validation.FieldErrors{},
+		value:    []string{"debug-"},
+	}, {
+		name:     "invalid key value",
+		expected: validation.ErrInvalidValue("=true", validation.CurrentField).ViaFieldIndex(clitesting.TestField, 0),
+		value:    []string{"=true"},
+	}, {
+		name:     "not a boolean",
+		expected: validation.ErrInvalidValue("debug=yes", validation.CurrentField).ViaFieldIndex(clitesting.TestField, 0),
+		value:    []string{"debug=yes"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := validation.DeletableBoolKeyValues(test.value, clitesting.TestField)
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Errorf("DeletableBoolKeyValues() = (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
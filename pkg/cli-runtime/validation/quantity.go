@@ -17,27 +17,144 @@ limitations under the License.
 package validation
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/api/resource"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	k8sfield "k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/parsers"
 )
 
 func Quantity(str, field string) FieldErrors {
 	errs := FieldErrors{}
 
 	if _, err := resource.ParseQuantity(str); err != nil {
-		// TODO capture info about why the quantity is invalid
-		errs = errs.Also(ErrInvalidValue(str, field))
+		errs = errs.Also(ErrInvalidQuantity(str, field))
 	}
 
 	return errs
 }
 
+// ErrInvalidQuantity reports a value that is not a resource quantity, suggesting the quantity the
+// user most likely meant when the value only differs from one by the case or spelling of its suffix
+func ErrInvalidQuantity(value, field string) FieldErrors {
+	detail := "must be a number with an optional suffix, like 500m, 2 or 512Mi"
+	if suggestion := suggestQuantity(value); suggestion != "" {
+		detail = fmt.Sprintf("%s, did you mean %q?", detail, suggestion)
+	}
+	return FieldErrors{
+		k8sfield.Invalid(k8sfield.NewPath(field), value, detail),
+	}
+}
+
 func CompareQuantity(limit, request, field string) FieldErrors {
 	errs := FieldErrors{}
 	limitQ, _ := resource.ParseQuantity(limit)
 	requestQ, _ := resource.ParseQuantity(request)
 
 	if limitQ.Cmp(requestQ) < 0 {
-		errs = errs.Also(ErrInvalidValue(request, field))
+		errs = errs.Also(ErrQuantityAboveLimit(request, limit, field))
+	}
+	return errs
+}
+
+// ErrQuantityAboveLimit reports a requested quantity greater than the limit of the same resource
+func ErrQuantityAboveLimit(request, limit, field string) FieldErrors {
+	return FieldErrors{
+		k8sfield.Invalid(k8sfield.NewPath(field), request, fmt.Sprintf("must be less than or equal to the limit of %s", limit)),
+	}
+}
+
+var quantityPattern = regexp.MustCompile(`^\s*([+-]?[0-9.]+(?:[eE][+-]?[0-9]+)?)\s*([a-zA-Z]*)\s*$`)
+
+// quantitySuffixes maps the lower cased suffixes users commonly type to the suffix of the quantity
+var quantitySuffixes = map[string]string{
+	"":    "",
+	"m":   "m",
+	"k":   "k",
+	"kb":  "k",
+	"ki":  "Ki",
+	"kib": "Ki",
+	"mb":  "M",
+	"mi":  "Mi",
+	"mib": "Mi",
+	"g":   "G",
+	"gb":  "G",
+	"gi":  "Gi",
+	"gib": "Gi",
+	"t":   "T",
+	"tb":  "T",
+	"ti":  "Ti",
+	"tib": "Ti",
+	"p":   "P",
+	"pb":  "P",
+	"pi":  "Pi",
+	"pib": "Pi",
+	"ei":  "Ei",
+	"eib": "Ei",
+}
+
+// suggestQuantity returns a valid quantity close to the value, or an empty string when there is none
+func suggestQuantity(value string) string {
+	match := quantityPattern.FindStringSubmatch(value)
+	if match == nil {
+		return ""
+	}
+	number, suffix := match[1], match[2]
+	if strings.HasPrefix(number, ".") {
+		number = "0" + number
+	}
+	if strings.HasSuffix(number, ".") {
+		number = strings.TrimSuffix(number, ".")
 	}
+	canonical, ok := quantitySuffixes[strings.ToLower(suffix)]
+	if !ok {
+		return ""
+	}
+	// "M" is mega, not milli, keep the case the user typed when it is a valid suffix
+	if suffix == "M" {
+		canonical = suffix
+	}
+	suggestion := number + canonical
+	if suggestion == value {
+		return ""
+	}
+	if _, err := resource.ParseQuantity(suggestion); err != nil {
+		return ""
+	}
+	return suggestion
+}
+
+// DeletableResourceQuantity validates a "name=quantity" pair of a resource, like "nvidia.com/gpu=1",
+// or "name-" to remove the resource
+func DeletableResourceQuantity(rq, field string) FieldErrors {
+	errs := DeletableKeyValue(rq, field)
+	if len(errs) != 0 {
+		return errs
+	}
+
+	parts := parsers.DeletableKeyValue(rq)
+	if len(k8svalidation.IsQualifiedName(parts[0])) != 0 {
+		return errs.Also(ErrInvalidValue(rq, field))
+	}
+	if len(parts) == 2 {
+		if _, err := resource.ParseQuantity(parts[1]); err != nil {
+			errs = errs.Also(ErrInvalidValue(rq, field))
+		}
+	}
+
+	return errs
+}
+
+func DeletableResourceQuantities(rqs []string, field string) FieldErrors {
+	errs := FieldErrors{}
+
+	for i, rq := range rqs {
+		errs = errs.Also(DeletableResourceQuantity(rq, CurrentField).ViaFieldIndex(field, i))
+	}
+
 	return errs
 }
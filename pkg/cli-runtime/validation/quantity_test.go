@@ -40,11 +40,11 @@ func TestQuantity(t *testing.T) {
 		value:    "2M",
 	}, {
 		name:     "empty",
-		expected: validation.ErrInvalidValue("", clitesting.TestField),
+		expected: validation.ErrInvalidQuantity("", clitesting.TestField),
 		value:    "",
 	}, {
 		name:     "invalid",
-		expected: validation.ErrInvalidValue("/", clitesting.TestField),
+		expected: validation.ErrInvalidQuantity("/", clitesting.TestField),
 		value:    "/",
 	}}
 
@@ -58,3 +58,134 @@ func TestQuantity(t *testing.T) {
 		})
 	}
 }
+
+func TestErrInvalidQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{{
+		name:     "no suggestion",
+		value:    "lots",
+		expected: `test-field: Invalid value: "lots": must be a number with an optional suffix, like 500m, 2 or 512Mi`,
+	}, {
+		name:     "unknown suffix",
+		value:    "2cores",
+		expected: `test-field: Invalid value: "2cores": must be a number with an optional suffix, like 500m, 2 or 512Mi`,
+	}, {
+		name:     "lower case binary suffix",
+		value:    "500mi",
+		expected: `test-field: Invalid value: "500mi": must be a number with an optional suffix, like 500m, 2 or 512Mi, did you mean "500Mi"?`,
+	}, {
+		name:     "byte suffix",
+		value:    "1.5GB",
+		expected: `test-field: Invalid value: "1.5GB": must be a number with an optional suffix, like 500m, 2 or 512Mi, did you mean "1.5G"?`,
+	}, {
+		name:     "binary byte suffix",
+		value:    "512MiB",
+		expected: `test-field: Invalid value: "512MiB": must be a number with an optional suffix, like 500m, 2 or 512Mi, did you mean "512Mi"?`,
+	}, {
+		name:     "space before suffix",
+		value:    "500 M",
+		expected: `test-field: Invalid value: "500 M": must be a number with an optional suffix, like 500m, 2 or 512Mi, did you mean "500M"?`,
+	}, {
+		name:     "leading decimal point",
+		value:    " .5 ",
+		expected: `test-field: Invalid value: " .5 ": must be a number with an optional suffix, like 500m, 2 or 512Mi, did you mean "0.5"?`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := validation.ErrInvalidQuantity(test.value, clitesting.TestField).ToAggregate().Error()
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Errorf("%s() = (-expected, +actual): %s", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestCompareQuantity(t *testing.T) {
+	if errs := validation.CompareQuantity("1Gi", "512Mi", clitesting.TestField); len(errs) != 0 {
+		t.Errorf("CompareQuantity() expected no errors, got %v", errs)
+	}
+	expected := `test-field: Invalid value: "2Gi": must be less than or equal to the limit of 1Gi`
+	if actual := validation.CompareQuantity("1Gi", "2Gi", clitesting.TestField).ToAggregate().Error(); actual != expected {
+		t.Errorf("CompareQuantity() = %q, expected %q", actual, expected)
+	}
+}
+
+func TestDeletableResourceQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected validation.FieldErrors
+		value    string
+	}{{
+		name:     "valid",
+		expected: validation.FieldErrors{},
+		value:    "cpu=500m",
+	}, {
+		name:     "valid extended resource",
+		expected: validation.FieldErrors{},
+		value:    "nvidia.com/gpu=1",
+	}, {
+		name:     "valid delete",
+		expected: validation.FieldErrors{},
+		value:    "nvidia.com/gpu-",
+	}, {
+		name:     "empty",
+		expected: validation.ErrInvalidValue("", clitesting.TestField),
+		value:    "",
+	}, {
+		name:     "missing name",
+		expected: validation.ErrInvalidValue("=1", clitesting.TestField),
+		value:    "=1",
+	}, {
+		name:     "invalid name",
+		expected: validation.ErrInvalidValue("nvidia.com/gpu/a100=1", clitesting.TestField),
+		value:    "nvidia.com/gpu/a100=1",
+	}, {
+		name:     "invalid quantity",
+		expected: validation.ErrInvalidValue("nvidia.com/gpu=one", clitesting.TestField),
+		value:    "nvidia.com/gpu=one",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected := test.expected
+			actual := validation.DeletableResourceQuantity(test.value, clitesting.TestField)
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Errorf("%s() = (-expected, +actual): %s", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestDeletableResourceQuantities(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected validation.FieldErrors
+		values   []string
+	}{{
+		name:     "empty",
+		expected: validation.FieldErrors{},
+		values:   []string{},
+	}, {
+		name:     "valid",
+		expected: validation.FieldErrors{},
+		values:   []string{"nvidia.com/gpu=1", "example.com/fpga-"},
+	}, {
+		name:     "invalid",
+		expected: validation.ErrInvalidValue("nvidia.com/gpu", validation.CurrentField).ViaFieldIndex(clitesting.TestField, 1),
+		values:   []string{"cpu=1", "nvidia.com/gpu"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected := test.expected
+			actual := validation.DeletableResourceQuantities(test.values, clitesting.TestField)
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Errorf("%s() = (-expected, +actual): %s", test.name, diff)
+			}
+		})
+	}
+}
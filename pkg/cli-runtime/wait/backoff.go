@@ -0,0 +1,113 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// PollBackoffConfigKey is the plugin config key holding the backoff between the polls of the
+// cluster while waiting
+const PollBackoffConfigKey = "poll-backoff"
+
+// Backoff spaces the polls of the cluster while waiting. The first poll happens after Initial,
+// every following delay is multiplied by Factor up to Max, and each delay is stretched by a
+// random fraction of up to Jitter, so that many clients waiting together do not poll in lockstep.
+type Backoff struct {
+	Initial time.Duration `mapstructure:"initial"`
+	Max     time.Duration `mapstructure:"max"`
+	Factor  float64       `mapstructure:"factor"`
+	Jitter  float64       `mapstructure:"jitter"`
+}
+
+// DefaultBackoff starts polling after BackOffTime and doubles the delay up to a minute
+func DefaultBackoff() Backoff {
+	return Backoff{
+		Initial: BackOffTime,
+		Max:     time.Minute,
+		Factor:  2,
+		Jitter:  0.2,
+	}
+}
+
+// BackoffFromConfig returns the backoff declared in the plugin config, the settings it omits keep
+// their default
+func BackoffFromConfig(v *viper.Viper) (Backoff, error) {
+	backoff := DefaultBackoff()
+	if v == nil || !v.IsSet(PollBackoffConfigKey) {
+		return backoff, nil
+	}
+	if err := v.UnmarshalKey(PollBackoffConfigKey, &backoff); err != nil {
+		return Backoff{}, fmt.Errorf("invalid %s: %w", PollBackoffConfigKey, err)
+	}
+	if err := backoff.Validate(); err != nil {
+		return Backoff{}, err
+	}
+	return backoff, nil
+}
+
+// Validate checks the delays are positive, the factor does not shrink them and the jitter is a fraction
+func (b Backoff) Validate() error {
+	if b.Initial <= 0 || b.Max < 0 {
+		return fmt.Errorf("invalid %s, initial must be positive and max must not be negative", PollBackoffConfigKey)
+	}
+	if b.Factor < 1 {
+		return fmt.Errorf("invalid %s factor %v, expected at least 1", PollBackoffConfigKey, b.Factor)
+	}
+	if b.Jitter < 0 || b.Jitter > 1 {
+		return fmt.Errorf("invalid %s jitter %v, expected between 0 and 1", PollBackoffConfigKey, b.Jitter)
+	}
+	return nil
+}
+
+// Delays returns a func returning the successive delays between two polls
+func (b Backoff) Delays() func() time.Duration {
+	next := b.Initial
+	return func() time.Duration {
+		delay := next
+		if b.Factor > 1 && (b.Max == 0 || next < b.Max) {
+			next = time.Duration(float64(next) * b.Factor)
+			if b.Max != 0 && next > b.Max {
+				next = b.Max
+			}
+		}
+		if b.Jitter > 0 {
+			delay += time.Duration(rand.Float64() * b.Jitter * float64(delay))
+		}
+		return delay
+	}
+}
+
+type backoffStashKey struct{}
+
+// StashBackoff sets the backoff used by UntilDelete and UntilCondition
+func StashBackoff(ctx context.Context, backoff Backoff) context.Context {
+	return context.WithValue(ctx, backoffStashKey{}, backoff)
+}
+
+// RetrieveBackoff returns the stashed backoff, by default DefaultBackoff
+func RetrieveBackoff(ctx context.Context) Backoff {
+	if backoff, ok := ctx.Value(backoffStashKey{}).(Backoff); ok {
+		return backoff
+	}
+	return DefaultBackoff()
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+)
+
+func TestBackoffFromConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      interface{}
+		expected    Backoff
+		shouldError bool
+	}{{
+		name:     "no backoff",
+		expected: DefaultBackoff(),
+	}, {
+		name: "partial backoff",
+		config: map[string]interface{}{
+			"initial": "2s",
+			"jitter":  0.5,
+		},
+		expected: Backoff{Initial: 2 * time.Second, Max: time.Minute, Factor: 2, Jitter: 0.5},
+	}, {
+		name: "full backoff",
+		config: map[string]interface{}{
+			"initial": "1s",
+			"max":     "30s",
+			"factor":  1.5,
+			"jitter":  0,
+		},
+		expected: Backoff{Initial: time.Second, Max: 30 * time.Second, Factor: 1.5},
+	}, {
+		name:        "invalid initial",
+		config:      map[string]interface{}{"initial": "0s"},
+		shouldError: true,
+	}, {
+		name:        "invalid factor",
+		config:      map[string]interface{}{"factor": 0.5},
+		shouldError: true,
+	}, {
+		name:        "invalid jitter",
+		config:      map[string]interface{}{"jitter": 2},
+		shouldError: true,
+	}, {
+		name:        "invalid duration",
+		config:      map[string]interface{}{"max": "later"},
+		shouldError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := viper.New()
+			if test.config != nil {
+				v.Set(PollBackoffConfigKey, test.config)
+			}
+			backoff, err := BackoffFromConfig(v)
+			if (err != nil) != test.shouldError {
+				t.Errorf("BackoffFromConfig() error = %v, shouldError %v", err, test.shouldError)
+			}
+			if diff := cmp.Diff(test.expected, backoff); diff != "" {
+				t.Errorf("BackoffFromConfig() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestBackoffDelays(t *testing.T) {
+	tests := []struct {
+		name     string
+		backoff  Backoff
+		expected []time.Duration
+	}{{
+		name:     "fixed",
+		backoff:  Backoff{Initial: time.Second, Factor: 1},
+		expected: []time.Duration{time.Second, time.Second, time.Second},
+	}, {
+		name:     "exponential",
+		backoff:  Backoff{Initial: time.Second, Max: 5 * time.Second, Factor: 2},
+		expected: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+	}, {
+		name:     "unbounded",
+		backoff:  Backoff{Initial: time.Second, Factor: 3},
+		expected: []time.Duration{time.Second, 3 * time.Second, 9 * time.Second},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			delay := test.backoff.Delays()
+			actual := []time.Duration{}
+			for range test.expected {
+				actual = append(actual, delay())
+			}
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Errorf("Delays() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestBackoffDelaysJitter(t *testing.T) {
+	backoff := Backoff{Initial: time.Second, Max: 4 * time.Second, Factor: 2, Jitter: 0.5}
+	delay := backoff.Delays()
+	for _, base := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		if actual := delay(); actual < base || actual > base+base/2 {
+			t.Errorf("Delays() = %s, expected between %s and %s", actual, base, base+base/2)
+		}
+	}
+}
+
+func TestRetrieveBackoff(t *testing.T) {
+	ctx := context.Background()
+	if diff := cmp.Diff(DefaultBackoff(), RetrieveBackoff(ctx)); diff != "" {
+		t.Errorf("RetrieveBackoff() (-want, +got) = %s", diff)
+	}
+	backoff := Backoff{Initial: time.Second, Factor: 1}
+	if diff := cmp.Diff(backoff, RetrieveBackoff(StashBackoff(ctx, backoff))); diff != "" {
+		t.Errorf("RetrieveBackoff() (-want, +got) = %s", diff)
+	}
+}
+
+// closingWatchClient closes the first watches it opens, like an API server ending long watches
+type closingWatchClient struct {
+	client.WithWatch
+	closing int
+	watches int
+}
+
+func (c *closingWatchClient) Watch(ctx context.Context, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+	c.watches++
+	if c.watches <= c.closing {
+		w := watch.NewFake()
+		w.Stop()
+		return w, nil
+	}
+	return c.WithWatch.Watch(ctx, list, opts...)
+}
+
+func TestUntilConditionWatchClosed(t *testing.T) {
+	workload := &cartov1alpha1.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "my-workload",
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = StashBackoff(ctx, Backoff{Initial: time.Millisecond, Factor: 1})
+	c := &closingWatchClient{
+		WithWatch: fake.NewClientBuilder().WithScheme(scheme).WithObjects(workload.DeepCopy()).Build(),
+		closing:   2,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- UntilCondition(ctx, c, types.NamespacedName{Name: workload.Name, Namespace: workload.Namespace}, &cartov1alpha1.WorkloadList{}, func(client.Object) (bool, error) {
+			return true, nil
+		})
+	}()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("UntilCondition() error = %v", err)
+			}
+			if expected, actual := 3, c.watches; expected != actual {
+				t.Errorf("expected %d watches, actually %d", expected, actual)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+			current := &cartov1alpha1.Workload{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(workload), current); err != nil {
+				t.Fatalf("Get error %v", err)
+			}
+			if err := c.Update(ctx, current); err != nil {
+				t.Fatalf("Update error %v", err)
+			}
+		}
+	}
+}
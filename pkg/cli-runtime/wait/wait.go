@@ -18,52 +18,100 @@ package wait
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/telemetry"
 )
 
 var (
 	BackOffTime = 5 * time.Second
 )
 
+// ErrConditionFailed matches the errors returned by UntilCondition when the condition reports a failure
+var ErrConditionFailed = errors.New("condition failed")
+
+type conditionError struct {
+	err error
+}
+
+func (e *conditionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *conditionError) Unwrap() error {
+	return e.err
+}
+
+func (e *conditionError) Is(err error) bool {
+	return err == ErrConditionFailed
+}
+
 type ConditionFunc = func(client.Object) (bool, error)
 
+// UntilCondition watches the target until the condition is met. A watch closed by the API server
+// is established again after a delay of the stashed backoff.
 func UntilCondition(ctx context.Context, watchClient client.WithWatch, target types.NamespacedName, listType client.ObjectList, condition ConditionFunc) error {
+	delay := RetrieveBackoff(ctx).Delays()
+	for {
+		done, err := untilConditionWatch(ctx, watchClient, target, listType, condition)
+		if done || err != nil {
+			return err
+		}
+		t := time.NewTimer(delay())
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// untilConditionWatch watches the target once, done is false when the watch closed before the
+// condition was met
+func untilConditionWatch(ctx context.Context, watchClient client.WithWatch, target types.NamespacedName, listType client.ObjectList, condition ConditionFunc) (bool, error) {
 	eventWatcher, err := watchClient.Watch(ctx, listType, &client.ListOptions{Namespace: target.Namespace})
 	if err != nil {
-		return err
+		return true, err
 	}
 	defer eventWatcher.Stop()
 	for {
 		select {
-		case event := <-eventWatcher.ResultChan():
-			obj := event.Object.(client.Object)
-			if obj.GetName() != target.Name || obj.GetNamespace() != target.Namespace {
+		case event, ok := <-eventWatcher.ResultChan():
+			if !ok {
+				return false, nil
+			}
+			obj, ok := event.Object.(client.Object)
+			if !ok || obj.GetName() != target.Name || obj.GetNamespace() != target.Namespace {
 				continue
 			}
 			cond, err := condition(obj)
 			if err != nil {
-				return err
+				return true, &conditionError{err: err}
 			}
 			if cond {
-				return nil
+				return true, nil
 			}
 		case <-ctx.Done():
-			return ctx.Err()
+			return true, ctx.Err()
 		}
 	}
 }
 
+// UntilDelete polls the object until it is not found, the polls are spaced by the stashed backoff
 func UntilDelete(ctx context.Context, c client.Client, obj client.Object) error {
-	t := time.NewTicker(BackOffTime)
-	defer t.Stop()
+	delay := RetrieveBackoff(ctx).Delays()
 	for {
+		t := time.NewTimer(delay())
 		select {
 		case <-ctx.Done():
+			t.Stop()
 			return ctx.Err()
 		case <-t.C:
 			if err := c.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}, obj); err != nil {
@@ -80,8 +128,11 @@ type Worker func(context.Context) error
 
 // Race multiple worker functions each in a goroutine. The first worker to return
 // commits the result of the Race function. All workers must return when the context
-// is closed before the Race function will return.
-func Race(ctx context.Context, timeout time.Duration, workers []Worker) error {
+// is closed before the Race function will return. The race is traced as a "wait" span.
+func Race(ctx context.Context, timeout time.Duration, workers []Worker) (err error) {
+	ctx, span := telemetry.Start(ctx, "wait", telemetry.Duration("wait.timeout_ms", timeout))
+	defer func() { span.End(err) }()
+
 	var wg sync.WaitGroup
 	output := make(chan error, len(workers)+1)
 
@@ -18,6 +18,7 @@ package wait
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -147,6 +148,9 @@ func TestUntilReady(t *testing.T) {
 			if expected, actual := fmt.Sprintf("%s", test.err), fmt.Sprintf("%s", err); expected != actual {
 				t.Errorf("expected error %v, actually %v", expected, actual)
 			}
+			if expected, actual := test.err != nil, errors.Is(err, ErrConditionFailed); expected != actual {
+				t.Errorf("expected error to match ErrConditionFailed %v, actually %v", expected, actual)
+			}
 		})
 	}
 }
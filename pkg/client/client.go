@@ -0,0 +1,61 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client exposes the behavior of the workload commands to programs embedding the plugin, like
+// internal tools and IDE plugins, without cobra, prompts or terminal output.
+package client
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	fluxnotificationv1beta1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/fluxcd/notification/v1beta1"
+	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+)
+
+// DefaultFieldManager owns the fields of the workloads applied by the plugin
+const DefaultFieldManager = "tanzu-apps"
+
+// Client runs the workload operations of the commands against a cluster
+type Client struct {
+	cluster cli.Client
+}
+
+// New returns a client for the cluster, the scheme of the cluster client must include the types of
+// NewScheme
+func New(cluster cli.Client) *Client {
+	return &Client{cluster: cluster}
+}
+
+// NewForContext returns a client for a context of a kubeconfig file, the default kubeconfig and its
+// current context when empty
+func NewForContext(kubeConfigFile, context string) *Client {
+	return New(cli.NewClient(kubeConfigFile, context, NewScheme()))
+}
+
+// NewScheme returns a scheme with the types the plugin reads and writes
+func NewScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = cartov1alpha1.AddToScheme(scheme)
+	_ = knativeservingv1.AddToScheme(scheme)
+	_ = fluxnotificationv1beta1.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
+	return scheme
+}
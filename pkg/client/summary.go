@@ -0,0 +1,120 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+// WorkloadSummary is what workload get reports about a workload, derived from the workload and the
+// resources stamped for it, for dashboards to read instead of interpreting the resources themselves
+type WorkloadSummary struct {
+	// ReadyCondition is the Ready condition of the workload, when reported
+	ReadyCondition *metav1.Condition `json:"readyCondition,omitempty"`
+	// SupplyChain is the name of the supply chain selected for the workload
+	SupplyChain string `json:"supplyChain,omitempty"`
+	// Pods counts the pods of the workload
+	Pods WorkloadPodsSummary `json:"pods"`
+	// KnativeServices are the Knative services of the workload, with their url
+	KnativeServices []KnativeServiceSummary `json:"knativeServices"`
+}
+
+// WorkloadPodsSummary counts the pods of a workload by phase, and the ones with all their
+// containers ready
+type WorkloadPodsSummary struct {
+	Total     int `json:"total"`
+	Ready     int `json:"ready"`
+	Pending   int `json:"pending"`
+	Running   int `json:"running"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Unknown   int `json:"unknown"`
+}
+
+// KnativeServiceSummary is a Knative service of a workload, with its url and whether it is ready
+type KnativeServiceSummary struct {
+	Name  string `json:"name"`
+	URL   string `json:"url,omitempty"`
+	Ready bool   `json:"ready"`
+}
+
+// SummarizeWorkload queries the pods and the Knative services of the workload for its summary
+func SummarizeWorkload(ctx context.Context, c crclient.Reader, workload *cartov1alpha1.Workload) (*WorkloadSummary, error) {
+	summary := &WorkloadSummary{
+		ReadyCondition:  printer.FindCondition(workload.Status.Conditions, cartov1alpha1.WorkloadConditionReady),
+		SupplyChain:     workload.Status.SupplyChainRef.Name,
+		KnativeServices: []KnativeServiceSummary{},
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, crclient.InNamespace(workload.Namespace), crclient.MatchingLabels{cartov1alpha1.WorkloadLabelName: workload.Name}); err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		summary.Pods.Total++
+		switch pod.Status.Phase {
+		case corev1.PodPending:
+			summary.Pods.Pending++
+		case corev1.PodRunning:
+			summary.Pods.Running++
+		case corev1.PodSucceeded:
+			summary.Pods.Succeeded++
+		case corev1.PodFailed:
+			summary.Pods.Failed++
+		default:
+			summary.Pods.Unknown++
+		}
+		if podReady(pod) {
+			summary.Pods.Ready++
+		}
+	}
+
+	ksvcs := &knativeservingv1.ServiceList{}
+	if err := c.List(ctx, ksvcs, crclient.InNamespace(workload.Namespace), crclient.MatchingLabels{cartov1alpha1.WorkloadLabelName: workload.Name}); err != nil {
+		return nil, err
+	}
+	ksvcs = ksvcs.DeepCopy()
+	printer.SortByNamespaceAndName(ksvcs.Items)
+	for _, ksvc := range ksvcs.Items {
+		ready := printer.FindCondition(ksvc.Status.Conditions, knativeservingv1.ServiceConditionReady)
+		summary.KnativeServices = append(summary.KnativeServices, KnativeServiceSummary{
+			Name:  ksvc.Name,
+			URL:   ksvc.Status.URL,
+			Ready: ready != nil && ready.Status == metav1.ConditionTrue,
+		})
+	}
+
+	return summary, nil
+}
+
+// podReady tells whether the Ready condition of the pod is true, all its containers are ready
+func podReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
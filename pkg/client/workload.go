@@ -0,0 +1,493 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	"k8s.io/utils/pointer"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/logs"
+)
+
+// ApplyOptions tune how ApplyWorkload applies a workload
+type ApplyOptions struct {
+	// FieldManager owns the fields set by the workload, DefaultFieldManager when empty
+	FieldManager string
+	// DryRun validates the workload with the cluster without persisting it
+	DryRun bool
+	// ForceConflicts takes over the fields owned by other field managers that the workload changes or
+	// removes, instead of failing with a conflict
+	ForceConflicts bool
+}
+
+// ApplyWorkload creates the workload, or updates it when it exists, like workload apply --file. The
+// workload is merged into the existing workload the same way as the content of --file, its namespace
+// defaults to the one of the current context. Returns the workload as applied by the cluster.
+func (c *Client) ApplyWorkload(ctx context.Context, workload *cartov1alpha1.Workload, opts ApplyOptions) (*cartov1alpha1.Workload, error) {
+	if workload.Namespace == "" {
+		workload = workload.DeepCopy()
+		workload.Namespace = c.cluster.DefaultNamespace()
+	}
+	current := &cartov1alpha1.Workload{}
+	applied := workload.DeepCopy()
+	if err := c.cluster.Get(ctx, crclient.ObjectKeyFromObject(workload), current); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return nil, err
+		}
+		current = nil
+	} else {
+		applied = current.DeepCopy()
+		applied.Merge(workload)
+	}
+	if err := applied.Validate().ToAggregate(); err != nil {
+		return nil, err
+	}
+	return ServerSideApply(ctx, c.cluster, current, applied, opts)
+}
+
+// ServerSideApply applies the changes from the current workload, nil when it does not exist yet, to the
+// workload as a server-side apply patch owned by the field manager. The patch holds the fields the field
+// manager applied before along with the labels, annotations and spec the changes set, so the fields of
+// controllers and other users are left alone, and is conditional on the resource version of the current
+// workload. Changing a field owned by another field manager is a conflict unless forced.
+//
+// An apply only removes the fields the field manager owns alone. The other fields the changes remove are
+// removed by a merge patch sent before the apply, and restored when the apply fails. Removing a field owned
+// by another field manager is also a conflict unless forced. Returns the object returned by the server, with the removals of a dry run.
+func ServerSideApply(ctx context.Context, c crclient.Client, current, workload *cartov1alpha1.Workload, opts ApplyOptions) (*cartov1alpha1.Workload, error) {
+	fieldManager := opts.FieldManager
+	if fieldManager == "" {
+		fieldManager = DefaultFieldManager
+	}
+	config, removals, err := applyConfiguration(current, workload, fieldManager)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceVersion := ""
+	var removal map[string]interface{}
+	if current != nil {
+		resourceVersion = current.ResourceVersion
+		owners, err := removalOwners(current, removals, fieldManager)
+		if err != nil {
+			return nil, err
+		}
+		if err := removalConflicts(owners, fieldManager); err != nil && !opts.ForceConflicts {
+			return nil, err
+		}
+		if len(owners) != 0 {
+			removal = removedFields(removals)
+		}
+	}
+	if removal != nil && !opts.DryRun {
+		if resourceVersion, err = mergePatchWorkload(ctx, c, workload, removal, resourceVersion, fieldManager); err != nil {
+			return nil, err
+		}
+	}
+
+	applied := &unstructured.Unstructured{Object: config}
+	applied.SetAPIVersion(cartov1alpha1.SchemeGroupVersion.String())
+	applied.SetKind(cartov1alpha1.WorkloadKind)
+	applied.SetNamespace(workload.Namespace)
+	applied.SetName(workload.Name)
+	applied.SetResourceVersion(resourceVersion)
+	patchOpts := []crclient.PatchOption{crclient.FieldOwner(fieldManager)}
+	if opts.ForceConflicts {
+		patchOpts = append(patchOpts, crclient.ForceOwnership)
+	}
+	if opts.DryRun {
+		patchOpts = append(patchOpts, crclient.DryRunAll)
+	}
+	if err := c.Patch(ctx, applied, crclient.Apply, patchOpts...); err != nil {
+		if removal != nil && !opts.DryRun {
+			// the removals were already sent, they are restored so a failed apply leaves the workload unchanged
+			restore, rerr := restoredFields(current, removals)
+			if rerr == nil {
+				_, rerr = mergePatchWorkload(ctx, c, workload, restore, resourceVersion, fieldManager)
+			}
+			if rerr != nil {
+				return nil, fmt.Errorf("%w, and the fields removed before the apply could not be restored: %s", err, rerr)
+			}
+		}
+		return nil, err
+	}
+
+	result := applied.Object
+	if removal != nil && opts.DryRun {
+		// the removals are not sent with a dry run, the server would not see them in the apply
+		if result, err = mergePatch(result, removals); err != nil {
+			return nil, err
+		}
+	}
+	returned := &cartov1alpha1.Workload{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(result, returned); err != nil {
+		return nil, err
+	}
+	return returned, nil
+}
+
+// restoredFields returns a merge patch setting the fields removed by the removals back to their value in
+// the current workload
+func restoredFields(current *cartov1alpha1.Workload, removals map[string]interface{}) (map[string]interface{}, error) {
+	fields, err := appliedFields(current)
+	if err != nil {
+		return nil, err
+	}
+	var restore func(removals, fields map[string]interface{}) map[string]interface{}
+	restore = func(removals, fields map[string]interface{}) map[string]interface{} {
+		restored := map[string]interface{}{}
+		for key, value := range removals {
+			if v, ok := value.(map[string]interface{}); ok {
+				f, _ := fields[key].(map[string]interface{})
+				restored[key] = restore(v, f)
+				continue
+			}
+			restored[key] = fields[key]
+		}
+		return restored
+	}
+	return restore(removals, fields), nil
+}
+
+// mergePatchWorkload sends the merge patch of the fields to the workload, as long as it is still at the
+// resource version. Returns the resource version of the patched workload.
+func mergePatchWorkload(ctx context.Context, c crclient.Client, workload *cartov1alpha1.Workload, fields map[string]interface{}, resourceVersion, fieldManager string) (string, error) {
+	metadata, _ := fields["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["resourceVersion"] = resourceVersion
+	fields["metadata"] = metadata
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	patched := &cartov1alpha1.Workload{ObjectMeta: metav1.ObjectMeta{Namespace: workload.Namespace, Name: workload.Name}}
+	if err := c.Patch(ctx, patched, crclient.RawPatch(types.MergePatchType, data), crclient.FieldOwner(fieldManager)); err != nil {
+		return "", err
+	}
+	return patched.ResourceVersion, nil
+}
+
+// IsApplyConflict returns whether an apply failed on fields owned by other field managers, rather than on
+// a newer version of the workload
+func IsApplyConflict(err error) bool {
+	return apierrs.IsConflict(err) && apierrs.HasStatusCause(err, metav1.CauseTypeFieldManagerConflict)
+}
+
+// appliedFields are the fields of a workload an apply sets, the labels, annotations and spec. The metadata
+// and the spec are always set, so a change removes the labels rather than the whole metadata.
+func appliedFields(workload *cartov1alpha1.Workload) (map[string]interface{}, error) {
+	if workload == nil {
+		return map[string]interface{}{}, nil
+	}
+	metadata := map[string]interface{}{}
+	if len(workload.Labels) != 0 {
+		metadata["labels"] = workload.Labels
+	}
+	if len(workload.Annotations) != 0 {
+		metadata["annotations"] = workload.Annotations
+	}
+	// round trip through json for the values to be the ones the server sees
+	data, err := json.Marshal(map[string]interface{}{"metadata": metadata, "spec": workload.Spec})
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return withoutNulls(fields), nil
+}
+
+// withoutNulls drops the null fields, a null field is not set
+func withoutNulls(fields map[string]interface{}) map[string]interface{} {
+	for key, value := range fields {
+		switch v := value.(type) {
+		case nil:
+			delete(fields, key)
+		case map[string]interface{}:
+			withoutNulls(v)
+		}
+	}
+	return fields
+}
+
+// applyConfiguration returns the fields the field manager applies to change the current workload into the
+// workload, the fields it applied before with the changes merged into them, along with the changes
+// removing fields as a merge patch
+func applyConfiguration(current, workload *cartov1alpha1.Workload, fieldManager string) (map[string]interface{}, map[string]interface{}, error) {
+	from, err := appliedFields(current)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := appliedFields(workload)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err := mergePatchBetween(from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owned := map[string]interface{}{}
+	if current != nil {
+		if err := managedfields.ExtractInto(current, typed.DeducedParseableType, fieldManager, &owned, ""); err != nil {
+			return nil, nil, err
+		}
+		delete(owned, "apiVersion")
+		delete(owned, "kind")
+		delete(owned, "status")
+		metadata, _ := owned["metadata"].(map[string]interface{})
+		for field := range metadata {
+			if field != "labels" && field != "annotations" {
+				delete(metadata, field)
+			}
+		}
+		if len(metadata) == 0 {
+			delete(owned, "metadata")
+		}
+	}
+	config, err := mergePatch(owned, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, removedFields(changes), nil
+}
+
+// removedFields keeps the fields of a merge patch removing fields
+func removedFields(patch map[string]interface{}) map[string]interface{} {
+	removed := map[string]interface{}{}
+	for key, value := range patch {
+		switch v := value.(type) {
+		case nil:
+			removed[key] = nil
+		case map[string]interface{}:
+			if r := removedFields(v); len(r) != 0 {
+				removed[key] = r
+			}
+		}
+	}
+	return removed
+}
+
+// removalOwners returns the field managers, other than the apply of the field manager, owning each field
+// removed by the merge patch. A removed field owned by no field manager has no owners.
+func removalOwners(current *cartov1alpha1.Workload, removals map[string]interface{}, fieldManager string) (map[string][]string, error) {
+	paths := []fieldpath.Path{}
+	var collect func(fieldpath.Path, map[string]interface{})
+	collect = func(prefix fieldpath.Path, fields map[string]interface{}) {
+		for key, value := range fields {
+			path := append(prefix.Copy(), fieldpath.PathElement{FieldName: pointer.String(key)})
+			if v, ok := value.(map[string]interface{}); ok {
+				collect(path, v)
+				continue
+			}
+			paths = append(paths, path)
+		}
+	}
+	collect(fieldpath.Path{}, removals)
+
+	owners := map[string][]string{}
+	for _, path := range paths {
+		owners[path.String()] = []string{}
+	}
+	for _, entry := range current.ManagedFields {
+		if entry.FieldsV1 == nil || (entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply) {
+			continue
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			if ownsPath(set, path) {
+				owners[path.String()] = append(owners[path.String()], entry.Manager)
+			}
+		}
+	}
+
+	// the removed fields the field manager owns alone are removed by the apply
+	for _, entry := range current.ManagedFields {
+		if entry.FieldsV1 == nil || entry.Manager != fieldManager || entry.Operation != metav1.ManagedFieldsOperationApply {
+			continue
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			if len(owners[path.String()]) == 0 && ownsPath(set, path) {
+				delete(owners, path.String())
+			}
+		}
+	}
+	return owners, nil
+}
+
+// ownsPath returns whether the set holds the path or fields below it
+func ownsPath(set *fieldpath.Set, path fieldpath.Path) bool {
+	owned := false
+	set.Iterate(func(p fieldpath.Path) {
+		if len(p) >= len(path) && p[:len(path)].Equals(path) {
+			owned = true
+		}
+	})
+	return owned
+}
+
+// removalConflicts returns a conflict for the removed fields owned by other field managers, in the form of
+// the conflicts of an apply
+func removalConflicts(owners map[string][]string, fieldManager string) error {
+	paths := []string{}
+	for path := range owners {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	causes := []metav1.StatusCause{}
+	messages := []string{}
+	for _, path := range paths {
+		for _, manager := range owners[path] {
+			message := fmt.Sprintf("conflict with %q", manager)
+			causes = append(causes, metav1.StatusCause{Type: metav1.CauseTypeFieldManagerConflict, Message: message, Field: path})
+			messages = append(messages, fmt.Sprintf("%s: %s", message, path))
+		}
+	}
+	if len(causes) == 0 {
+		return nil
+	}
+	return apierrs.NewApplyConflict(causes, fmt.Sprintf("Removing fields owned by other field managers than %q failed with %d conflict(s): %s", fieldManager, len(causes), strings.Join(messages, ", ")))
+}
+
+// mergePatchBetween returns the json merge patch changing from into to
+func mergePatchBetween(from, to map[string]interface{}) (map[string]interface{}, error) {
+	fromData, err := json.Marshal(from)
+	if err != nil {
+		return nil, err
+	}
+	toData, err := json.Marshal(to)
+	if err != nil {
+		return nil, err
+	}
+	data, err := jsonpatch.CreateMergePatch(fromData, toData)
+	if err != nil {
+		return nil, err
+	}
+	patch := map[string]interface{}{}
+	return patch, json.Unmarshal(data, &patch)
+}
+
+// mergePatch applies the json merge patch to the fields
+func mergePatch(fields, patch map[string]interface{}) (map[string]interface{}, error) {
+	fieldsData, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	patchData, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	data, err := jsonpatch.MergePatch(fieldsData, patchData)
+	if err != nil {
+		return nil, err
+	}
+	merged := map[string]interface{}{}
+	return merged, json.Unmarshal(data, &merged)
+}
+
+// WorkloadStatus is a workload with the summary workload get --include-summary adds to it
+type WorkloadStatus struct {
+	Workload *cartov1alpha1.Workload `json:"workload"`
+	Summary  *WorkloadSummary        `json:"summary"`
+}
+
+// GetWorkloadStatus gets the workload and summarizes its readiness, pods and Knative services
+func (c *Client) GetWorkloadStatus(ctx context.Context, namespace, name string) (*WorkloadStatus, error) {
+	workload := &cartov1alpha1.Workload{}
+	if err := c.cluster.Get(ctx, crclient.ObjectKey{Namespace: namespace, Name: name}, workload); err != nil {
+		return nil, err
+	}
+	summary, err := SummarizeWorkload(ctx, c.cluster, workload)
+	if err != nil {
+		return nil, err
+	}
+	return &WorkloadStatus{Workload: workload, Summary: summary}, nil
+}
+
+// TailOptions tune which logs TailWorkload prints
+type TailOptions struct {
+	// Component only prints the logs of the pods of a component of the supply chain, like build
+	Component string
+	// Since prints the logs newer than the duration, all logs when zero
+	Since time.Duration
+	// Lines is how many lines of each container are printed, all when logs.AllLines
+	Lines int64
+	// Timestamps prefixes each line with its timestamp
+	Timestamps bool
+	// Follow keeps printing the logs until the context is done, instead of returning once the
+	// current logs are printed
+	Follow bool
+}
+
+// TailWorkload writes the logs of the pods of the workload in its namespace to out, like workload tail.
+// The logs are read with the tailer stashed in the context, the one of the plugin when none is.
+func (c *Client) TailWorkload(ctx context.Context, namespace, name string, opts TailOptions, out io.Writer) error {
+	workload := &cartov1alpha1.Workload{}
+	if err := c.cluster.Get(ctx, crclient.ObjectKey{Namespace: namespace, Name: name}, workload); err != nil {
+		return err
+	}
+
+	selector := labels.Set{cartov1alpha1.WorkloadLabelName: workload.Name}
+	if opts.Component != "" {
+		selector[apis.ComponentLabelName] = opts.Component
+	}
+	if logs.RetrieveTailer(ctx) == nil {
+		ctx = logs.StashTailer(ctx, &logs.SternTailer{})
+	}
+	config := cli.NewDefaultConfig("apps", c.cluster.Scheme())
+	config.Client = c.cluster
+	config.Stdout = out
+	config.Stderr = out
+
+	containers := []string{}
+	if opts.Follow {
+		return logs.Tail(ctx, config, namespace, selector.AsSelector(), containers, opts.Since, opts.Lines, opts.Timestamps)
+	}
+	return logs.Dump(ctx, config, namespace, selector.AsSelector(), containers, opts.Since, opts.Lines, opts.Timestamps)
+}
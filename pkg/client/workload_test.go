@@ -0,0 +1,483 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/logs"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/client"
+)
+
+func TestApplyWorkload(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	tests := []struct {
+		name     string
+		given    []crclient.Object
+		workload *cartov1alpha1.Workload
+		expected *cartov1alpha1.Workload
+		dryRun   bool
+		err      bool
+	}{{
+		name: "create",
+		workload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   workloadName,
+				Labels: map[string]string{apis.AppPartOfLabelName: workloadName},
+			},
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: "ubuntu:bionic",
+			},
+		},
+		expected: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+				Labels:    map[string]string{apis.AppPartOfLabelName: workloadName},
+			},
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: "ubuntu:bionic",
+			},
+		},
+	}, {
+		name: "merge into existing",
+		given: []crclient.Object{
+			&cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+					Labels:    map[string]string{apis.WorkloadTypeLabelName: "web"},
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					Image: "ubuntu:bionic",
+				},
+			},
+		},
+		workload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+				Labels:    map[string]string{apis.AppPartOfLabelName: workloadName},
+			},
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: "ubuntu:focal",
+			},
+		},
+		expected: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+				Labels: map[string]string{
+					apis.WorkloadTypeLabelName: "web",
+					apis.AppPartOfLabelName:    workloadName,
+				},
+			},
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: "ubuntu:focal",
+			},
+		},
+	}, {
+		name: "dry run",
+		workload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: workloadName,
+			},
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: "ubuntu:bionic",
+			},
+		},
+		dryRun: true,
+	}, {
+		name: "invalid workload",
+		workload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "My-Workload",
+			},
+		},
+		err: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			cluster := clitesting.NewFakeCliClient(clitesting.NewFakeClient(client.NewScheme(), test.given...))
+			c := client.New(cluster)
+
+			applied, err := c.ApplyWorkload(ctx, test.workload, client.ApplyOptions{DryRun: test.dryRun})
+			if (err != nil) != test.err {
+				t.Fatalf("ApplyWorkload() error = %v, expected error %v", err, test.err)
+			}
+			if test.err {
+				return
+			}
+			if applied.Name != workloadName || applied.Namespace != defaultNamespace {
+				t.Errorf("ApplyWorkload() applied %s/%s", applied.Namespace, applied.Name)
+			}
+
+			actual := &cartov1alpha1.Workload{}
+			err = cluster.Get(ctx, crclient.ObjectKey{Namespace: defaultNamespace, Name: workloadName}, actual)
+			if test.expected == nil {
+				if err == nil {
+					t.Errorf("ApplyWorkload() persisted a dry run workload")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if diff := cmp.Diff(test.expected.Labels, actual.Labels); diff != "" {
+				t.Errorf("ApplyWorkload() labels (-expected, +actual): %s", diff)
+			}
+			if diff := cmp.Diff(test.expected.Spec, actual.Spec); diff != "" {
+				t.Errorf("ApplyWorkload() spec (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
+
+func TestServerSideApply(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+	otherManager := "kubectl"
+	serviceAccountName := "my-sa"
+
+	tests := []struct {
+		name           string
+		other          func(*cartov1alpha1.Workload)
+		own            func(*cartov1alpha1.Workload)
+		change         func(*cartov1alpha1.Workload)
+		forceConflicts bool
+		failApply      bool
+		expected       *cartov1alpha1.Workload
+		conflict       bool
+	}{{
+		name: "keep the fields of other managers",
+		other: func(w *cartov1alpha1.Workload) {
+			w.Labels = map[string]string{apis.WorkloadTypeLabelName: "web"}
+			w.Spec.Image = "ubuntu:bionic"
+		},
+		change: func(w *cartov1alpha1.Workload) {
+			w.Spec.ServiceAccountName = &serviceAccountName
+		},
+		expected: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{apis.WorkloadTypeLabelName: "web"},
+			},
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image:              "ubuntu:bionic",
+				ServiceAccountName: &serviceAccountName,
+			},
+		},
+	}, {
+		name: "conflict changing a field of another manager",
+		other: func(w *cartov1alpha1.Workload) {
+			w.Spec.Image = "ubuntu:bionic"
+		},
+		change: func(w *cartov1alpha1.Workload) {
+			w.Spec.Image = "ubuntu:focal"
+		},
+		conflict: true,
+	}, {
+		name: "force conflicts changing a field of another manager",
+		other: func(w *cartov1alpha1.Workload) {
+			w.Spec.Image = "ubuntu:bionic"
+		},
+		change: func(w *cartov1alpha1.Workload) {
+			w.Spec.Image = "ubuntu:focal"
+		},
+		forceConflicts: true,
+		expected: &cartov1alpha1.Workload{
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: "ubuntu:focal",
+			},
+		},
+	}, {
+		name: "conflict removing a field of another manager",
+		other: func(w *cartov1alpha1.Workload) {
+			w.Labels = map[string]string{apis.WorkloadTypeLabelName: "web"}
+			w.Spec.Image = "ubuntu:bionic"
+		},
+		change: func(w *cartov1alpha1.Workload) {
+			w.Labels = nil
+		},
+		conflict: true,
+	}, {
+		name: "force conflicts removing a field of another manager",
+		other: func(w *cartov1alpha1.Workload) {
+			w.Labels = map[string]string{apis.WorkloadTypeLabelName: "web"}
+			w.Spec.Image = "ubuntu:bionic"
+		},
+		change: func(w *cartov1alpha1.Workload) {
+			w.Labels = nil
+		},
+		forceConflicts: true,
+		expected: &cartov1alpha1.Workload{
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: "ubuntu:bionic",
+			},
+		},
+	}, {
+		name: "restore the removed fields when the apply fails",
+		other: func(w *cartov1alpha1.Workload) {
+			w.Labels = map[string]string{apis.WorkloadTypeLabelName: "web"}
+			w.Spec.Image = "ubuntu:bionic"
+		},
+		change: func(w *cartov1alpha1.Workload) {
+			w.Labels = nil
+			w.Spec.Image = "ubuntu:focal"
+		},
+		forceConflicts: true,
+		failApply:      true,
+		expected: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{apis.WorkloadTypeLabelName: "web"},
+			},
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: "ubuntu:bionic",
+			},
+		},
+	}, {
+		name: "remove an own field",
+		other: func(w *cartov1alpha1.Workload) {
+			w.Spec.Image = "ubuntu:bionic"
+		},
+		own: func(w *cartov1alpha1.Workload) {
+			w.Labels = map[string]string{apis.AppPartOfLabelName: workloadName}
+		},
+		change: func(w *cartov1alpha1.Workload) {
+			w.Labels = nil
+		},
+		expected: &cartov1alpha1.Workload{
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: "ubuntu:bionic",
+			},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			cluster := clitesting.NewFakeCliClient(clitesting.NewFakeClient(client.NewScheme()))
+			var patcher crclient.Client = cluster
+			key := crclient.ObjectKey{Namespace: defaultNamespace, Name: workloadName}
+			apply := func(change func(*cartov1alpha1.Workload), opts client.ApplyOptions) error {
+				var current *cartov1alpha1.Workload
+				workload := &cartov1alpha1.Workload{ObjectMeta: metav1.ObjectMeta{Namespace: defaultNamespace, Name: workloadName}}
+				if err := cluster.Get(ctx, key, workload); err == nil {
+					current = workload.DeepCopy()
+				}
+				change(workload)
+				_, err := client.ServerSideApply(ctx, patcher, current, workload, opts)
+				return err
+			}
+
+			if err := apply(test.other, client.ApplyOptions{FieldManager: otherManager}); err != nil {
+				t.Fatalf("ServerSideApply() error = %v", err)
+			}
+			if test.own != nil {
+				if err := apply(test.own, client.ApplyOptions{}); err != nil {
+					t.Fatalf("ServerSideApply() error = %v", err)
+				}
+			}
+			if test.failApply {
+				patcher = &failApplyClient{Client: cluster}
+			}
+			err := apply(test.change, client.ApplyOptions{ForceConflicts: test.forceConflicts})
+			if test.failApply {
+				if !errors.Is(err, errApplyFailed) {
+					t.Fatalf("ServerSideApply() error = %v, expected %v", err, errApplyFailed)
+				}
+				err = nil
+			}
+			if actual := client.IsApplyConflict(err); actual != test.conflict {
+				t.Fatalf("ServerSideApply() error = %v, expected conflict %v", err, test.conflict)
+			}
+			if test.conflict {
+				return
+			}
+			if err != nil {
+				t.Fatalf("ServerSideApply() error = %v", err)
+			}
+
+			actual := &cartov1alpha1.Workload{}
+			if err := cluster.Get(ctx, key, actual); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if diff := cmp.Diff(test.expected.Labels, actual.Labels); diff != "" {
+				t.Errorf("ServerSideApply() labels (-expected, +actual): %s", diff)
+			}
+			if diff := cmp.Diff(test.expected.Spec, actual.Spec); diff != "" {
+				t.Errorf("ServerSideApply() spec (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
+
+var errApplyFailed = errors.New("apply failed")
+
+// failApplyClient fails the server-side apply patches, while merge patches still reach the cluster
+type failApplyClient struct {
+	crclient.Client
+}
+
+func (c *failApplyClient) Patch(ctx context.Context, obj crclient.Object, patch crclient.Patch, opts ...crclient.PatchOption) error {
+	if patch.Type() == types.ApplyPatchType {
+		return errApplyFailed
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func TestGetWorkloadStatus(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	workload := &cartov1alpha1.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: defaultNamespace,
+			Name:      workloadName,
+		},
+		Status: cartov1alpha1.WorkloadStatus{
+			SupplyChainRef: cartov1alpha1.ObjectReference{Name: "source-to-url"},
+		},
+	}
+	pod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: defaultNamespace,
+				Name:      name,
+				Labels:    map[string]string{cartov1alpha1.WorkloadLabelName: workloadName},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		given    []crclient.Object
+		expected *client.WorkloadSummary
+		err      bool
+	}{{
+		name:  "summary",
+		given: []crclient.Object{workload, pod("build-pod", corev1.PodSucceeded), pod("run-pod", corev1.PodRunning)},
+		expected: &client.WorkloadSummary{
+			SupplyChain:     "source-to-url",
+			Pods:            client.WorkloadPodsSummary{Total: 2, Running: 1, Succeeded: 1},
+			KnativeServices: []client.KnativeServiceSummary{},
+		},
+	}, {
+		name: "not found",
+		err:  true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := client.New(clitesting.NewFakeCliClient(clitesting.NewFakeClient(client.NewScheme(), test.given...)))
+
+			status, err := c.GetWorkloadStatus(context.Background(), defaultNamespace, workloadName)
+			if (err != nil) != test.err {
+				t.Fatalf("GetWorkloadStatus() error = %v, expected error %v", err, test.err)
+			}
+			if test.err {
+				return
+			}
+			if status.Workload.Name != workloadName {
+				t.Errorf("GetWorkloadStatus() workload = %q, expected %q", status.Workload.Name, workloadName)
+			}
+			if diff := cmp.Diff(test.expected, status.Summary); diff != "" {
+				t.Errorf("GetWorkloadStatus() summary (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTailWorkload(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	workload := &cartov1alpha1.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: defaultNamespace,
+			Name:      workloadName,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		given    []crclient.Object
+		opts     client.TailOptions
+		selector labels.Selector
+		expected string
+		err      bool
+	}{{
+		name:     "dump",
+		given:    []crclient.Object{workload},
+		opts:     client.TailOptions{Lines: logs.AllLines},
+		selector: labels.SelectorFromSet(labels.Set{cartov1alpha1.WorkloadLabelName: workloadName}),
+		expected: "...dump output...\n",
+	}, {
+		name:  "component",
+		given: []crclient.Object{workload},
+		opts:  client.TailOptions{Component: "build", Since: time.Minute, Lines: 10, Timestamps: true},
+		selector: labels.SelectorFromSet(labels.Set{
+			cartov1alpha1.WorkloadLabelName: workloadName,
+			apis.ComponentLabelName:         "build",
+		}),
+		expected: "...dump output...\n",
+	}, {
+		name: "not found",
+		opts: client.TailOptions{Lines: logs.AllLines},
+		err:  true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tailer := &logs.FakeTailer{}
+			if test.selector != nil {
+				tailer.On("Dump", mock.Anything, defaultNamespace, test.selector, []string{}, test.opts.Since, test.opts.Lines, test.opts.Timestamps).Return(nil).Once()
+			}
+			ctx := logs.StashTailer(context.Background(), tailer)
+			c := client.New(clitesting.NewFakeCliClient(clitesting.NewFakeClient(client.NewScheme(), test.given...)))
+			output := &bytes.Buffer{}
+
+			err := c.TailWorkload(ctx, defaultNamespace, workloadName, test.opts, output)
+			if (err != nil) != test.err {
+				t.Fatalf("TailWorkload() error = %v, expected error %v", err, test.err)
+			}
+			if diff := cmp.Diff(test.expected, output.String()); diff != "" {
+				t.Errorf("TailWorkload() output (-expected, +actual): %s", diff)
+			}
+			tailer.AssertExpectations(t)
+		})
+	}
+}
@@ -75,9 +75,10 @@ func NewClusterSupplyChainGetCommand(ctx context.Context, c *cli.Config) *cobra.
 	opts := &ClusterSupplyChainGetOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "get",
-		Short: "Get details from a cluster supply chain",
-		Long:  strings.TrimSpace(`Get details from a cluster supply chain`),
+		Use:     "get",
+		Aliases: []string{"g"},
+		Short:   "Get details from a cluster supply chain",
+		Long:    strings.TrimSpace(`Get details from a cluster supply chain`),
 		Example: strings.Join([]string{
 			fmt.Sprintf("%s cluster-supply-chain get", c.Name),
 		}, "\n"),
@@ -88,8 +88,9 @@ func NewClusterSupplyChainListCommand(ctx context.Context, c *cli.Config) *cobra
 	opts := &ClusterSupplyChainListOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "list",
-		Short: "table listing of cluster supply chains",
+		Use:     "list",
+		Aliases: []string{"ls", "l"},
+		Short:   "table listing of cluster supply chains",
 		Long: strings.TrimSpace(`
 List cluster supply chains.
 `),
@@ -17,11 +17,13 @@ limitations under the License.
 package commands_test
 
 import (
+	"context"
 	"strings"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/runtime"
 
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
 	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
 
 	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
@@ -46,3 +48,29 @@ func TestClusterSupplyChainCommand(t *testing.T) {
 
 	table.Run(t, scheme, commands.NewClusterSupplyChainCommand)
 }
+
+func TestClusterSupplyChainCommandAliases(t *testing.T) {
+	scheme := runtime.NewScheme()
+	c := cli.NewDefaultConfig("test", scheme)
+	cmd := commands.NewClusterSupplyChainCommand(context.Background(), c)
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"ls"}, expected: "list"},
+		{args: []string{"l"}, expected: "list"},
+		{args: []string{"g"}, expected: "get"},
+	}
+	for _, test := range tests {
+		t.Run(strings.Join(test.args, " "), func(t *testing.T) {
+			found, _, err := cmd.Find(test.args)
+			if err != nil {
+				t.Fatalf("Find() errored %v", err)
+			}
+			if found.Name() != test.expected {
+				t.Errorf("Find() wanted %q, got %q", test.expected, found.Name())
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+)
+
+func NewDeliverableCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deliverable",
+		Short: "Deliverable inspection",
+		Long: strings.TrimSpace(`
+A deliverable is stamped by a supply chain for a workload and runs the delivery of its
+configuration. Deliverables can be promoted with GitOps to clusters where the workload
+does not exist.
+`),
+		Aliases: []string{"deliverables", "dlv"},
+	}
+
+	cmd.AddCommand(NewDeliverableGetCommand(ctx, c))
+
+	return cmd
+}
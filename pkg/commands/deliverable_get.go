@@ -0,0 +1,175 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+type DeliverableGetOptions struct {
+	Namespace string
+	Name      string
+
+	Output      string
+	AllMessages bool
+	Timestamps  bool
+}
+
+var (
+	_ validation.Validatable = (*DeliverableGetOptions)(nil)
+	_ cli.Executable         = (*DeliverableGetOptions)(nil)
+)
+
+func (opts *DeliverableGetOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Namespace == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+	}
+
+	if opts.Name == "" {
+		errs = errs.Also(validation.ErrMissingField(cli.NameArgumentName))
+	}
+
+	if opts.Output != "" {
+		errs = errs.Also(validation.Enum(opts.Output, flags.OutputFlagName, []string{printer.OutputFormatJson, printer.OutputFormatYaml, printer.OutputFormatYml}))
+	}
+
+	return errs
+}
+
+func (opts *DeliverableGetOptions) Exec(ctx context.Context, c *cli.Config) error {
+	deliverable := &cartov1alpha1.Deliverable{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, deliverable); err != nil {
+		if apierrs.IsNotFound(err) {
+			nsGet := &corev1.Namespace{}
+			if getErr := c.Get(ctx, types.NamespacedName{Name: opts.Namespace}, nsGet); getErr != nil && apierrs.IsNotFound(getErr) {
+				c.Eprintf("%s %s\n", printer.Serrorf("Error:"), fmt.Sprintf("namespace %q not found, it may not exist or user does not have permissions to read it.", opts.Namespace))
+				return cli.SilenceError(getErr)
+			}
+			c.Errorf("Deliverable %q not found\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Name))
+			return cli.SilenceError(err)
+		}
+		return err
+	}
+
+	if opts.Output != "" {
+		export, err := printer.OutputResource(deliverable, printer.OutputFormat(opts.Output), c.Scheme)
+		if err != nil {
+			c.Eprintf("%s %s\n", printer.Serrorf("Failed to output deliverable:"), err)
+			return cli.SilenceError(err)
+		}
+		c.Printf("%s\n", export)
+		return nil
+	}
+
+	theme, err := printer.ThemeFromConfig(c.Viper)
+	if err != nil {
+		c.Eprintf("%s %s, using the %s theme\n", printer.Swarnf("Warning:"), err, printer.DefaultThemeName)
+	}
+
+	c.Boldf("%s\n", theme.Overview)
+	if err := printer.DeliverableOverviewPrinter(c.Stdout, deliverable); err != nil {
+		return err
+	}
+	c.Printf("\n")
+
+	// the source printers of the workload apply to the source of the deliverable
+	if deliverable.Spec.Source != nil {
+		c.Boldf("%s\n", theme.Source)
+		source := &cartov1alpha1.Workload{Spec: cartov1alpha1.WorkloadSpec{Source: deliverable.Spec.Source}}
+		if deliverable.Spec.Source.Image != "" {
+			if err := printer.WorkloadLocalSourceImagePrinter(c.Stdout, source); err != nil {
+				return err
+			}
+		}
+		if deliverable.Spec.Source.Git != nil {
+			if err := printer.WorkloadSourceGitPrinter(c.Stdout, source); err != nil {
+				return err
+			}
+		}
+		c.Printf("\n")
+	}
+
+	c.Boldf("%s\n", theme.Delivery)
+	if err := printer.DeliveryInfoPrinter(c.Stdout, deliverable); err != nil {
+		return err
+	}
+	c.Printf("\n")
+	if len(deliverable.Status.Resources) == 0 {
+		c.Infof(printer.AddPaddingStart("Delivery resources not found.\n"))
+	} else if err := printer.DeliverableResourcesPrinter(c.Stdout, deliverable, opts.Timestamps); err != nil {
+		return err
+	}
+
+	c.Printf("\n")
+	c.Boldf("%s\n", theme.Messages)
+	if areAllResourcesReady(printer.FindCondition(deliverable.Status.Conditions, cartov1alpha1.ConditionReady)) {
+		c.Infof(printer.AddPaddingStart("No messages found.\n"))
+	} else if err := printer.MessagesPrinter(c.Stdout, printer.DeliverableMessages(deliverable), opts.AllMessages); err != nil {
+		return err
+	}
+	c.Printf("\n")
+
+	return nil
+}
+
+func NewDeliverableGetCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	opts := &DeliverableGetOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "get",
+		Aliases: []string{"g"},
+		Short:   "Get details from a deliverable",
+		Long: strings.TrimSpace(`
+Get details from a deliverable, the delivery it is processed by, its resources and
+conditions. Unlike workload get, no workload is required, so deliverables promoted to a
+cluster with GitOps can be inspected.
+`),
+		Example:           examplesFor(c, "deliverable get"),
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestDeliverableNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		cli.NameArg(&opts.Name),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVarP(&opts.Output, cli.StripDash(flags.OutputFlagName), "o", "", "output the Deliverable formatted. Supported formats: \"json\", \"yaml\", \"yml\"")
+	cmd.Flags().BoolVar(&opts.AllMessages, cli.StripDash(flags.AllMessagesFlagName), false, "show every message instead of collapsing the ones repeated by several resources")
+	cmd.Flags().BoolVar(&opts.Timestamps, cli.StripDash(flags.TimestampsFlagName), false, "show how long ago each delivery resource transitioned, falling back to the latest transition of any of its conditions")
+
+	return cmd
+}
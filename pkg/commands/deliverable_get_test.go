@@ -0,0 +1,210 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"testing"
+
+	diemetav1 "dies.dev/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestDeliverableGetOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name:        "invalid empty",
+			Validatable: &commands.DeliverableGetOptions{},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrMissingField(flags.NamespaceFlagName),
+				validation.ErrMissingField(cli.NameArgumentName),
+			),
+		},
+		{
+			Name: "valid",
+			Validatable: &commands.DeliverableGetOptions{
+				Namespace: "default",
+				Name:      "my-deliverable",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid output",
+			Validatable: &commands.DeliverableGetOptions{
+				Namespace: "default",
+				Name:      "my-deliverable",
+				Output:    "table",
+			},
+			ExpectFieldErrors: validation.EnumInvalidValue("table", flags.OutputFlagName, []string{"json", "yaml", "yml"}),
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestDeliverableGetCommand(t *testing.T) {
+	defaultNamespace := "default"
+	deliverableName := "my-deliverable"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	parent := diecartov1alpha1.DeliverableBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(deliverableName)
+			d.Namespace(defaultNamespace)
+		})
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: defaultNamespace},
+	}
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "invalid args",
+			Args:        []string{},
+			ShouldError: true,
+		},
+		{
+			Name:         "no delivery",
+			Args:         []string{deliverableName},
+			GivenObjects: []client.Object{parent},
+			ExpectOutput: `
+📡 Overview
+   name:   my-deliverable
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   No messages found.
+
+`,
+		},
+		{
+			Name: "promoted deliverable",
+			Args: []string{deliverableName},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(cartov1alpha1.WorkloadLabelName, "my-workload")
+					}).
+					SpecDie(func(d *diecartov1alpha1.DeliverableSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://example.com/gitops.git",
+								Ref: cartov1alpha1.GitRef{Branch: "production"},
+							},
+							Subpath: "config/my-workload",
+						})
+					}).
+					StatusDie(func(d *diecartov1alpha1.DeliverableStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionFalse).Reason("HealthyConditionRule").
+								Message("the app is not ready yet"),
+						)
+						d.DeliveryRef(cartov1alpha1.ObjectReference{Name: "delivery-basic"})
+						d.Resources(
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("source-provider").
+								ConditionsDie(
+									diecartov1alpha1.WorkloadConditionResourceReadyBlank.Status(metav1.ConditionTrue),
+									diecartov1alpha1.WorkloadConditionResourceHealthyBlank.Status(metav1.ConditionTrue),
+								).DieRelease(),
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("deployer").
+								ConditionsDie(
+									diecartov1alpha1.WorkloadConditionResourceReadyBlank.Status(metav1.ConditionUnknown),
+									diecartov1alpha1.WorkloadConditionResourceHealthyBlank.Status(metav1.ConditionUnknown),
+								).DieRelease(),
+						)
+					}),
+			},
+			ExpectOutput: `
+📡 Overview
+   name:       my-deliverable
+   workload:   my-workload
+
+💾 Source
+   type:       git
+   url:        https://example.com/gitops.git
+   sub-path:   config/my-workload
+   branch:     production
+
+🚚 Delivery
+   name:   delivery-basic
+
+   RESOURCE          READY     HEALTHY   TIME        OUTPUT
+   source-provider   True      True      <unknown>   not found
+   deployer          Unknown   Unknown   <unknown>   not found
+
+💬 Messages
+   Deliverable [HealthyConditionRule]:   the app is not ready yet
+
+`,
+		},
+		{
+			Name:         "output yaml",
+			Args:         []string{deliverableName, flags.OutputFlagName, "yaml"},
+			GivenObjects: []client.Object{parent},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Deliverable
+metadata:
+  creationTimestamp: "1970-01-01T00:00:01Z"
+  name: my-deliverable
+  namespace: default
+  resourceVersion: "999"
+spec: {}
+status:
+  deliveryRef: {}
+`,
+		},
+		{
+			Name:         "not found",
+			Args:         []string{deliverableName},
+			GivenObjects: []client.Object{namespace},
+			ShouldError:  true,
+			ExpectOutput: `
+Deliverable "default/my-deliverable" not found
+`,
+		},
+		{
+			Name:        "namespace not found",
+			Args:        []string{deliverableName},
+			ShouldError: true,
+			ExpectOutput: `
+Error: namespace "default" not found, it may not exist or user does not have permissions to read it.
+`,
+		},
+	}
+
+	table.Run(t, scheme, commands.NewDeliverableGetCommand)
+}
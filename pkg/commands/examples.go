@@ -0,0 +1,143 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+// Example is a sample invocation of a command, rendered in the command help. Every example is
+// also executed by the tests against a fake cluster, so it must keep working as the command
+// evolves.
+type Example struct {
+	// Args follow the command path, e.g. the workload name and flags
+	Args []string
+}
+
+// Command returns the example as typed by the user, without the plugin name
+func (e Example) Command(command string) string {
+	return strings.Join(append([]string{command}, e.Args...), " ")
+}
+
+// Examples is the registry of examples for each command, keyed by the command path below the
+// plugin root
+var Examples = map[string][]Example{
+	"deliverable get": {
+		{Args: []string{"my-workload"}},
+		{Args: []string{"my-workload", flags.NamespaceFlagName, "production", flags.OutputFlagName, "yaml"}},
+	},
+	"workload annotate": {
+		{Args: []string{"my-workload", "example.com/reviewed-by=my-team", "example.com/draft-"}},
+	},
+	"workload apply": {
+		{Args: []string{flags.FilePathFlagName, "workload.yaml"}},
+		{Args: []string{flags.FilePathFlagName, "workload.yaml", flags.DryRunFlagName, flags.OutputFormatFlagName, DryRunFormatKustomizePatch}},
+	},
+	"workload clone": {
+		{Args: []string{"my-workload", "my-workload-feature", flags.LabelFlagName, "branch=feature"}},
+		{Args: []string{"my-workload", "my-workload", flags.TargetNamespaceFlagName, "dev"}},
+	},
+	"workload create": {
+		{Args: []string{"my-workload", flags.GitRepoFlagName, "https://example.com/my-workload.git", flags.GitBranchFlagName, "main"}},
+		{Args: []string{"my-workload", flags.LocalPathFlagName, ".", flags.SourceImageFlagName, "registry.example/repository:tag"}},
+		{Args: []string{flags.FilePathFlagName, "workload.yaml"}},
+		{Args: []string{"my-workload", flags.GitRepoFlagName, "https://example.com/my-workload.git", flags.GitBranchFlagName, "main", flags.NamespaceFlagName, "dev", flags.CreateNamespaceFlagName}},
+	},
+	"workload delete": {
+		{Args: []string{"my-workload"}},
+		{Args: []string{flags.AllFlagName}},
+		{Args: []string{flags.SelectorFlagName, "team=experiments"}},
+		{Args: []string{"my-workload", flags.WaitFlagName, flags.WaitDeliverableFlagName}},
+	},
+	"workload diff": {
+		{Args: []string{flags.FilePathFlagName, "workload.yaml"}},
+	},
+	"workload get": {
+		{Args: []string{"my-workload"}},
+		{Args: []string{"my-workload", flags.OutputFlagName, OutputFormatWide}},
+		{Args: []string{"my-workload", flags.ExportDeliverableFlagName, flags.ToContextFlagName, "run-cluster"}},
+	},
+	"workload init": {
+		{Args: []string{"my-workload", flags.GitRepoFlagName, "https://github.com/sample-accelerators/spring-petclinic", flags.GitBranchFlagName, "main", flags.TypeFlagName, "web"}},
+		{Args: []string{flags.FromFlagName, "my-workload", flags.FilePathFlagName, "-"}},
+	},
+	"workload label": {
+		{Args: []string{"my-workload", "team=my-team"}},
+		{Args: []string{"my-workload", "team=other-team", flags.OverwriteFlagName}},
+	},
+	"workload list": {
+		{Args: []string{}},
+		{Args: []string{flags.AllNamespacesFlagName}},
+		{Args: []string{flags.ExportFlagName, flags.OutputFlagName, "yaml"}},
+		{Args: []string{flags.FieldSelectorFlagName, "status.ready!=True", flags.SortByFlagName, "latest-ready-time"}},
+		{Args: []string{flags.InactiveFlagName, "720h"}},
+		{Args: []string{flags.SupplyChainFlagName, "source-to-url", flags.ReadyFlagName + "=false"}},
+	},
+	"workload patch": {
+		{Args: []string{"my-workload", flags.PatchFileFlagName, "patch.yaml"}},
+		{Args: []string{"my-workload", flags.PatchTypeFlagName, "json", flags.PatchFileFlagName, "patch.json"}},
+	},
+	"workload pause": {
+		{Args: []string{"my-workload"}},
+	},
+	"workload promote": {
+		{Args: []string{"my-workload", flags.ToContextFlagName, "production"}},
+		{Args: []string{"my-workload", flags.ToContextFlagName, "production", flags.TargetNamespaceFlagName, "apps"}},
+	},
+	"workload relabel": {
+		{Args: []string{"my-workload", flags.PartOfFlagName, "my-app", flags.OwnerFlagName, "my-team"}},
+		{Args: []string{flags.SelectorFlagName, "app.kubernetes.io/part-of=old-app", flags.PartOfFlagName, "new-app"}},
+	},
+	"workload register-webhook": {
+		{Args: []string{"my-workload", flags.ReceiverURLFlagName, "https://hooks.example.com"}},
+	},
+	"workload resume": {
+		{Args: []string{"my-workload"}},
+	},
+	"workload run-local": {
+		{Args: []string{"my-workload", flags.LocalPathFlagName, "."}},
+	},
+	"workload tail": {
+		{Args: []string{"my-workload"}},
+		{Args: []string{"my-workload", flags.SinceFlagName, "1h"}},
+		{Args: []string{"my-workload", flags.ComponentFlagName, "build", flags.LinesFlagName, "100"}},
+		{Args: []string{"my-workload", flags.ComponentFlagName, "build", fmt.Sprintf("%s=false", flags.FollowFlagName)}},
+	},
+	"workload update": {
+		{Args: []string{"my-workload", fmt.Sprintf("%s=false", flags.DebugFlagName)}},
+		{Args: []string{"my-workload", flags.LocalPathFlagName, "."}},
+		{Args: []string{"my-workload", flags.EnvFlagName, "key=value"}},
+		{Args: []string{"my-workload", flags.BuildEnvFlagName, "key=value"}},
+		{Args: []string{flags.FilePathFlagName, "workload.yaml"}},
+	},
+	"workload verify": {
+		{Args: []string{"my-workload", flags.VerifyURLFlagName, "/healthz"}},
+	},
+}
+
+// examplesFor renders the registered examples of a command for its help
+func examplesFor(c *cli.Config, command string) string {
+	lines := []string{}
+	for _, example := range Examples[command] {
+		lines = append(lines, fmt.Sprintf("%s %s", c.Name, example.Command(command)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,717 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	diecorev1 "dies.dev/apis/core/v1"
+	diemetav1 "dies.dev/apis/meta/v1"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
+	rtesting "github.com/vmware-labs/reconciler-runtime/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	fluxnotificationv1beta1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/fluxcd/notification/v1beta1"
+	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/logs"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	diev1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/knative/serving/v1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/logger"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/source"
+)
+
+const exampleWorkloadYaml = `
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: my-workload
+spec:
+  source:
+    git:
+      url: https://example.com/my-workload.git
+      ref:
+        branch: main
+`
+
+// exampleFileDir runs an example from its own directory holding the file it references, files
+// added to the examples directory would change the digest of the published source
+func exampleFileDir(name, content string) func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+	return func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return ctx, err
+		}
+		return ctx, os.Chdir(dir)
+	}
+}
+
+// exampleDir returns to the examples directory once an example is done
+func exampleDir(dir string) func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+	return func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+		return os.Chdir(dir)
+	}
+}
+
+// TestExamples executes every example registered in commands.Examples, with prompts accepted,
+// against a fake cluster holding the objects the examples refer to
+func TestExamples(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+	sourceImage := "registry.example/repository:tag"
+	// digest of the source in the examples directory
+	sourceImageDigest := "sha256:02da3ae5cfe615129af20072da1d729b035509680c95afd72659cb0143a718df"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	_ = knativeservingv1.AddToScheme(scheme)
+	_ = fluxnotificationv1beta1.AddToScheme(scheme)
+
+	reg, err := ggcrregistry.TLS("registry.example")
+	utilruntime.Must(err)
+	defer reg.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// examples run from a directory holding the files they reference
+	dir := t.TempDir()
+	for name, content := range map[string]string{"workload.yaml": exampleWorkloadYaml, "hello.go": "package main\n"} {
+		utilruntime.Must(os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+		// the uploaded source digest depends on the file mode, do not let the umask change it
+		utilruntime.Must(os.Chmod(filepath.Join(dir, name), 0644))
+	}
+	wd, err := os.Getwd()
+	utilruntime.Must(err)
+	utilruntime.Must(os.Chdir(dir))
+	defer os.Chdir(wd)
+
+	namespace := diecorev1.NamespaceBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(defaultNamespace)
+		})
+	parent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+		}).
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Source(&cartov1alpha1.Source{Image: sourceImage})
+		})
+	deliverable := diecartov1alpha1.DeliverableBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+			d.AddLabel(cartov1alpha1.WorkloadLabelName, workloadName)
+		}).
+		SpecDie(func(d *diecartov1alpha1.DeliverableSpecDie) {
+			d.Source(&cartov1alpha1.Source{Image: "registry.example/my-workload-bundle:latest"})
+		})
+	publishedWorkload := parent.
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Source(&cartov1alpha1.Source{Image: sourceImage + "@" + sourceImageDigest})
+		})
+	gitWorkload := parent.
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Source(&cartov1alpha1.Source{
+				Git: &cartov1alpha1.GitSource{
+					URL: "https://example.com/my-workload.git",
+					Ref: cartov1alpha1.GitRef{Branch: "main"},
+				},
+			})
+		})
+	tail := func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+		tailer := &logs.FakeTailer{}
+		tailer.On("Tail", mock.Anything, defaultNamespace, mock.Anything, []string{}, mock.Anything, mock.Anything, false).Return(nil).Once()
+		ctx = logs.StashTailer(ctx, tailer)
+		// simulate a user exit after 10ms
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		_ = cancel
+		return ctx, nil
+	}
+
+	// test cases for each example, keyed by the example command
+	cases := map[string]clitesting.CommandTestCase{
+		"deliverable get my-workload": {
+			GivenObjects: []client.Object{deliverable},
+		},
+		"deliverable get my-workload --namespace production --output yaml": {
+			GivenObjects: []client.Object{
+				deliverable.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Namespace("production")
+					}),
+			},
+		},
+		"workload annotate my-workload example.com/reviewed-by=my-team example.com/draft-": {
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation("example.com/draft", "true")
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation("example.com/reviewed-by", "my-team")
+					}),
+			},
+		},
+		"workload apply --file workload.yaml": {
+			GivenObjects:  []client.Object{namespace, parent},
+			ExpectUpdates: []client.Object{gitWorkload},
+			ExpectPatches: []rtesting.PatchRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+				PatchType: types.MergePatchType,
+				Patch:     []byte(`{"metadata":{"resourceVersion":"999"},"spec":{"source":{"image":null}}}`),
+			}},
+		},
+		"workload apply --file workload.yaml --dry-run --output-format kustomize-patch": {
+			GivenObjects: []client.Object{namespace, parent},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: my-workload
+spec:
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://example.com/my-workload.git
+`,
+		},
+		"workload clone my-workload my-workload-feature --label branch=feature": {
+			GivenObjects: []client.Object{parent},
+			ExpectCreates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("my-workload-feature")
+						d.AddLabel("branch", "feature")
+					}),
+			},
+		},
+		"workload clone my-workload my-workload --target-namespace dev": {
+			GivenObjects: []client.Object{parent},
+			ExpectCreates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Namespace("dev")
+					}),
+			},
+		},
+		"workload create my-workload --git-repo https://example.com/my-workload.git --git-branch main": {
+			GivenObjects:  []client.Object{namespace},
+			ExpectCreates: []client.Object{gitWorkload},
+		},
+		"workload create my-workload --local-path . --source-image registry.example/repository:tag": {
+			GivenObjects:  []client.Object{namespace},
+			ExpectCreates: []client.Object{publishedWorkload},
+		},
+		"workload create --file workload.yaml": {
+			GivenObjects:  []client.Object{namespace},
+			ExpectCreates: []client.Object{gitWorkload},
+		},
+		"workload create my-workload --git-repo https://example.com/my-workload.git --git-branch main --namespace dev --create-namespace": {
+			ExpectCreates: []client.Object{
+				diecorev1.NamespaceBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("dev")
+					}),
+				gitWorkload.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Namespace("dev")
+					}),
+			},
+		},
+		"workload delete my-workload": {
+			GivenObjects: []client.Object{parent},
+			ExpectDeletes: []rtesting.DeleteRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+			}},
+		},
+		"workload delete --all": {
+			GivenObjects: []client.Object{parent},
+			ExpectDeleteCollections: []rtesting.DeleteCollectionRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Fields:    fields.Everything(),
+				Labels:    labels.NewSelector(),
+			}},
+		},
+		"workload delete my-workload --wait --wait-deliverable": {
+			GivenObjects: []client.Object{parent},
+			ExpectDeletes: []rtesting.DeleteRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+			}},
+		},
+		"workload delete --selector team=experiments": {
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("team", "experiments")
+					}),
+			},
+			ExpectDeletes: []rtesting.DeleteRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+			}},
+		},
+		"workload diff --file workload.yaml": {
+			GivenObjects: []client.Object{parent},
+		},
+		"workload get my-workload": {
+			GivenObjects: []client.Object{parent},
+		},
+		"workload get my-workload --output wide": {
+			GivenObjects: []client.Object{parent},
+		},
+		"workload get my-workload --export-deliverable --to-context run-cluster": {
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.Resources(
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("deliverable").
+								StampedRef(&corev1.ObjectReference{
+									Kind:      cartov1alpha1.DeliverableKind,
+									Namespace: defaultNamespace,
+									Name:      workloadName,
+								}).
+								DieRelease(),
+						)
+					}),
+				deliverable,
+			},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				// the run cluster is the same fake cluster
+				config.ContextClients = map[string]cli.Client{"run-cluster": config.Client}
+				return ctx, nil
+			},
+			ExpectUpdates: []client.Object{deliverable},
+		},
+		"workload init my-workload --git-repo https://github.com/sample-accelerators/spring-petclinic --git-branch main --type web": {
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				// keep the workload.yaml of the other examples
+				return ctx, os.Chdir(t.TempDir())
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				return os.Chdir(dir)
+			},
+		},
+		"workload init --from my-workload --file -": {
+			GivenObjects: []client.Object{parent},
+		},
+		"workload label my-workload team=my-team": {
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("team", "my-team")
+					}),
+			},
+		},
+		"workload label my-workload team=other-team --overwrite": {
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("team", "my-team")
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("team", "other-team")
+					}),
+			},
+		},
+		"workload list": {
+			GivenObjects: []client.Object{parent},
+		},
+		"workload list --all-namespaces": {
+			GivenObjects: []client.Object{parent},
+		},
+		"workload list --export --output yaml": {
+			GivenObjects: []client.Object{parent},
+		},
+		"workload list --field-selector status.ready!=True --sort-by latest-ready-time": {
+			GivenObjects: []client.Object{parent},
+		},
+		"workload list --inactive 720h": {
+			GivenObjects: []client.Object{parent},
+		},
+		"workload list --supply-chain source-to-url --ready=false": {
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.SupplyChainRef(cartov1alpha1.ObjectReference{Name: "source-to-url"})
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionFalse),
+						)
+					}),
+			},
+		},
+		"workload patch my-workload --patch-file patch.yaml": {
+			Prepare:      exampleFileDir("patch.yaml", "spec:\n  serviceAccountName: my-sa\n"),
+			CleanUp:      exampleDir(dir),
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					DieStamp(func(r *cartov1alpha1.Workload) {
+						r.Spec.MergeServiceAccountName("my-sa")
+					}),
+			},
+		},
+		"workload patch my-workload --patch-type json --patch-file patch.json": {
+			Prepare:      exampleFileDir("patch.json", `[{"op":"add","path":"/spec/env","value":[{"name":"LOG_LEVEL","value":"debug"}]}]`),
+			CleanUp:      exampleDir(dir),
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					DieStamp(func(r *cartov1alpha1.Workload) {
+						r.Spec.MergeEnv(corev1.EnvVar{Name: "LOG_LEVEL", Value: "debug"})
+					}),
+			},
+		},
+		"workload pause my-workload": {
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					DieStamp(func(r *cartov1alpha1.Workload) {
+						r.Spec.MergePaused(true)
+					}),
+			},
+		},
+		"workload promote my-workload --to-context production": {
+			GivenObjects: []client.Object{parent},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				// the production cluster is empty
+				config.ContextClients = map[string]cli.Client{"production": clitesting.NewFakeCliClient(clitesting.NewFakeClient(scheme))}
+				return ctx, nil
+			},
+			Verify: func(t *testing.T, output string, err error) {
+				if msg := `Created workload "my-workload" in context "production"`; !strings.Contains(output, msg) {
+					t.Errorf("expected output to contain %q, got %q", msg, output)
+				}
+			},
+		},
+		"workload promote my-workload --to-context production --target-namespace apps": {
+			GivenObjects: []client.Object{parent},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				// the production cluster is the same fake cluster
+				config.ContextClients = map[string]cli.Client{"production": config.Client}
+				return ctx, nil
+			},
+			ExpectCreates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Namespace("apps")
+					}),
+			},
+		},
+		"workload relabel my-workload --part-of my-app --owner my-team": {
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.AppPartOfLabelName, "my-app")
+						d.AddLabel(apis.OwnerLabelName, "my-team")
+					}),
+			},
+		},
+		"workload relabel --selector app.kubernetes.io/part-of=old-app --part-of new-app": {
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.AppPartOfLabelName, "old-app")
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.AppPartOfLabelName, "new-app")
+					}),
+			},
+		},
+		"workload register-webhook my-workload --receiver-url https://hooks.example.com": {
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.com/my-org/my-workload.git",
+								Ref: cartov1alpha1.GitRef{Branch: "main"},
+							},
+						})
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.Resources(
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("source-provider").
+								StampedRef(&corev1.ObjectReference{
+									APIVersion: "source.toolkit.fluxcd.io/v1beta1",
+									Kind:       "GitRepository",
+									Namespace:  defaultNamespace,
+									Name:       workloadName,
+								}).
+								DieRelease(),
+						)
+					}),
+			},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				t.Setenv(commands.GitHubTokenEnv, "my-token")
+				github := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodPost {
+						w.WriteHeader(http.StatusCreated)
+						return
+					}
+					w.Write([]byte(`[]`))
+				}))
+				t.Cleanup(github.Close)
+				var transport roundTripperFunc = func(r *http.Request) (*http.Response, error) {
+					r.URL.Host = strings.TrimPrefix(github.URL, "https://")
+					return github.Client().Transport.RoundTrip(r)
+				}
+				config.Client = &webhookTokenClient{Client: config.Client, token: "test-token"}
+				return commands.StashGitProviderTransport(ctx, transport), nil
+			},
+			ExpectCreates: []client.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      "my-workload-webhook",
+						Labels:    map[string]string{cartov1alpha1.WorkloadLabelName: workloadName},
+						OwnerReferences: []metav1.OwnerReference{{
+							APIVersion: "carto.run/v1alpha1",
+							Kind:       "Workload",
+							Name:       workloadName,
+						}},
+					},
+					Data: map[string][]byte{"token": []byte("test-token")},
+				},
+				&fluxnotificationv1beta1.Receiver{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{cartov1alpha1.WorkloadLabelName: workloadName},
+						OwnerReferences: []metav1.OwnerReference{{
+							APIVersion: "carto.run/v1alpha1",
+							Kind:       "Workload",
+							Name:       workloadName,
+						}},
+					},
+					Spec: fluxnotificationv1beta1.ReceiverSpec{
+						Type:   "github",
+						Events: []string{"push"},
+						Resources: []fluxnotificationv1beta1.CrossNamespaceObjectReference{{
+							APIVersion: "source.toolkit.fluxcd.io/v1beta1",
+							Kind:       "GitRepository",
+							Namespace:  defaultNamespace,
+							Name:       workloadName,
+						}},
+						SecretRef: fluxnotificationv1beta1.LocalObjectReference{Name: "my-workload-webhook"},
+					},
+				},
+			},
+		},
+		"workload resume my-workload": {
+			GivenObjects: []client.Object{
+				parent.
+					DieStamp(func(r *cartov1alpha1.Workload) {
+						r.Spec.MergePaused(true)
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				parent.
+					DieStamp(func(r *cartov1alpha1.Workload) {
+						r.Spec.Params = []cartov1alpha1.Param{}
+					}),
+			},
+		},
+		"workload run-local my-workload --local-path .": {
+			GivenObjects: []client.Object{parent},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				// simulate a user exit once the initial source is published
+				ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+				_ = cancel
+				return ctx, nil
+			},
+			ExpectUpdates: []client.Object{publishedWorkload},
+		},
+		"workload tail my-workload": {
+			GivenObjects: []client.Object{parent},
+			Prepare:      tail,
+		},
+		"workload tail my-workload --since 1h": {
+			GivenObjects: []client.Object{parent},
+			Prepare:      tail,
+		},
+		"workload tail my-workload --component build --lines 100": {
+			GivenObjects: []client.Object{parent},
+			Prepare:      tail,
+		},
+		"workload tail my-workload --component build --follow=false": {
+			GivenObjects: []client.Object{parent},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				tailer.On("Dump", mock.Anything, defaultNamespace, mock.Anything, []string{}, time.Duration(0), logs.AllLines, false).Return(nil).Once()
+				return logs.StashTailer(ctx, tailer), nil
+			},
+		},
+		"workload update my-workload --debug=false": {
+			GivenObjects: []client.Object{namespace, parent},
+		},
+		"workload update my-workload --local-path .": {
+			GivenObjects:  []client.Object{namespace, parent},
+			ExpectUpdates: []client.Object{publishedWorkload},
+		},
+		"workload update my-workload --env key=value": {
+			GivenObjects: []client.Object{namespace, parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Env(corev1.EnvVar{Name: "key", Value: "value"})
+					}),
+			},
+		},
+		"workload update my-workload --build-env key=value": {
+			GivenObjects: []client.Object{namespace, parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Build(&cartov1alpha1.WorkloadBuild{
+							Env: []corev1.EnvVar{{Name: "key", Value: "value"}},
+						})
+					}),
+			},
+		},
+		"workload update --file workload.yaml": {
+			GivenObjects:  []client.Object{namespace, parent},
+			ExpectUpdates: []client.Object{gitWorkload},
+			ExpectPatches: []rtesting.PatchRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+				PatchType: types.MergePatchType,
+				Patch:     []byte(`{"metadata":{"resourceVersion":"999"},"spec":{"source":{"image":null}}}`),
+			}},
+		},
+		"workload verify my-workload --verify-url /healthz": {
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionTrue),
+						)
+					}),
+				diev1.ServiceBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(workloadName)
+						d.Namespace(defaultNamespace)
+						d.AddLabel(cartov1alpha1.WorkloadLabelName, workloadName)
+					}).
+					StatusDie(func(d *diev1.ServiceStatusDie) {
+						d.URL(server.URL)
+					}),
+			},
+		},
+	}
+
+	root := func(ctx context.Context, c *cli.Config) *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.AddCommand(commands.NewDeliverableCommand(ctx, c))
+		cmd.AddCommand(commands.NewWorkloadCommand(ctx, c))
+		return cmd
+	}
+	commandPaths := []string{}
+	for command := range commands.Examples {
+		commandPaths = append(commandPaths, command)
+	}
+	sort.Strings(commandPaths)
+
+	table := clitesting.CommandTestSuite{}
+	for _, command := range commandPaths {
+		cmd, _, err := root(context.Background(), cli.NewDefaultConfig("test", scheme)).Find(strings.Fields(command))
+		utilruntime.Must(err)
+		for _, example := range commands.Examples[command] {
+			name := example.Command(command)
+			tc, ok := cases[name]
+			if !ok {
+				t.Errorf("missing test case for example %q", name)
+				continue
+			}
+			delete(cases, name)
+
+			tc.Name = name
+			tc.Args = append(strings.Fields(command), example.Args...)
+			if cmd.Flags().Lookup(cli.StripDash(flags.YesFlagName)) != nil {
+				tc.Args = append(tc.Args, flags.YesFlagName)
+			}
+			prepare := tc.Prepare
+			tc.Prepare = func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				ctx = source.StashContainerRemoteTransport(ctx, reg.Client().Transport)
+				ctx = logger.StashSourceImageLogger(ctx, logger.NewNoopLogger())
+				if prepare != nil {
+					return prepare(t, ctx, config, tc)
+				}
+				return ctx, nil
+			}
+			table = append(table, tc)
+		}
+	}
+	for name := range cases {
+		t.Errorf("test case for unregistered example %q", name)
+	}
+
+	table.Run(t, scheme, root)
+}
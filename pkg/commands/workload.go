@@ -17,31 +17,45 @@ limitations under the License.
 package commands
 
 import (
-	"bufio"
+	"bytes"
 	"context"
-	"errors"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
+	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
 	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
 	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/parsers"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/telemetry"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	appsclient "github.com/vmware-tanzu/apps-cli-plugin/pkg/client"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/logger"
@@ -50,10 +64,56 @@ import (
 )
 
 const (
-	AnnotationReservedKey     = "annotations"
-	MavenOverwrittenNoticeMsg = "Maven configuration flags have overwritten values provided by \"--params-yaml\"."
+	AnnotationReservedKey        = "annotations"
+	MavenOverwrittenNoticeMsg    = "Maven configuration flags have overwritten values provided by \"--params-yaml\"."
+	LabelPrefixGuardConfigKey    = "label-prefix-guard"
+	ProtectedNamespacesConfigKey = "protected-namespaces"
+	SourceLineEndingsConfigKey   = "source-line-endings"
+	OutputFormatNameAndURL       = "name-and-url"
+	OutputFormatWide             = "wide"
+	VisibilityPublic             = "public"
+	DefaultFieldManager          = appsclient.DefaultFieldManager
 )
 
+// modes of --source-image-pull, the source image is checked to exist in the registry and either
+// kept with its tag or pinned to the digest the tag points to
+const (
+	SourceImagePullTag    = "tag"
+	SourceImagePullDigest = "digest"
+)
+
+var sourceImagePullModes = []string{SourceImagePullTag, SourceImagePullDigest}
+
+// formats of the workload printed by --dry-run, either the workload as is or ready to be layered on
+// top of the manifests of a GitOps repository
+const (
+	DryRunFormatYaml           = "yaml"
+	DryRunFormatKustomizePatch = "kustomize-patch"
+	DryRunFormatYtt            = "ytt"
+)
+
+var dryRunFormats = []string{DryRunFormatYaml, DryRunFormatKustomizePatch, DryRunFormatYtt}
+
+var sha256Regex = regexp.MustCompile("^[a-f0-9]{64}$")
+
+// fileHTTPClient downloads the workload files given as a URL
+var fileHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+type fileTransportStashKey struct{}
+
+// StashFileTransport sets the transport used to download the workload files given as a URL
+func StashFileTransport(ctx context.Context, transport http.RoundTripper) context.Context {
+	return context.WithValue(ctx, fileTransportStashKey{}, transport)
+}
+
+func retrieveFileTransport(ctx context.Context) http.RoundTripper {
+	transport, ok := ctx.Value(fileTransportStashKey{}).(http.RoundTripper)
+	if !ok {
+		return nil
+	}
+	return transport
+}
+
 func NewWorkloadCommand(ctx context.Context, c *cli.Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "workload",
@@ -73,10 +133,33 @@ Workload configuration includes:
 	cmd.AddCommand(NewWorkloadListCommand(ctx, c))
 	cmd.AddCommand(NewWorkloadGetCommand(ctx, c))
 	cmd.AddCommand(NewWorkloadTailCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadInitCommand(ctx, c))
 	cmd.AddCommand(NewWorkloadCreateCommand(ctx, c))
 	cmd.AddCommand(NewWorkloadUpdateCommand(ctx, c))
 	cmd.AddCommand(NewWorkloadApplyCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadCloneCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadPromoteCommand(ctx, c))
 	cmd.AddCommand(NewWorkloadDeleteCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadDiffCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadVerifyCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadRelabelCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadLabelCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadAnnotateCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadPatchCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadPauseCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadResumeCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadRunLocalCommand(ctx, c))
+	cmd.AddCommand(NewWorkloadRegisterWebhookCommand(ctx, c))
+
+	cmd.PersistentFlags().DurationVar(&c.RequestTimeout, cli.StripDash(flags.RequestTimeoutFlagName), c.RequestTimeout, "time to wait for each request to the cluster before giving up, zero means no timeout")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.RequestTimeoutFlagName), completion.SuggestDurationUnits(ctx, completion.CommonDurationUnits))
+	cmd.PersistentFlags().IntVar(&c.Retries, cli.StripDash(flags.RetriesFlagName), c.Retries, "maximum `number` of retries, with exponential backoff, of reads from the cluster failing with a transient error (429 or 5xx) and of writes the cluster rejected (429 or 503)")
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if c.Retries < 0 {
+			return validation.ErrInvalidValue(c.Retries, flags.RetriesFlagName).ToAggregate()
+		}
+		return nil
+	}
 
 	return cmd
 }
@@ -85,34 +168,59 @@ type WorkloadOptions struct {
 	Namespace string
 	Name      string
 
-	App         string
-	Type        string
-	Labels      []string
-	Annotations []string
-	Params      []string
-	ParamsYaml  []string
-	Debug       bool
-	LiveUpdate  bool
+	App            string
+	Type           string
+	ValidateType   bool
+	Visibility     string
+	Labels         []string
+	Annotations    []string
+	PodAnnotations []string
+	LabelFile      string
+	AnnotationFile string
+	Params         []string
+	ParamsInt      []string
+	ParamsBool     []string
+	ParamsYaml     []string
+	ParamFiles     []string
+	Debug          bool
+	LiveUpdate     bool
 
 	FilePath        string
+	FileSHA256      string
+	ValuesFiles     []string
+	Set             []string
+	VerifySignature string
+	SignatureKey    string
 	GitRepo         string
 	GitCommit       string
 	GitBranch       string
 	GitTag          string
+	GitPR           int
+	GitPRLabel      bool
 	SourceImage     string
+	SourceImagePull string
 	LocalPath       string
+	ForcePush       bool
 	ExcludePathFile string
+	MaxSourceSize   string
 	Image           string
 	SubPath         string
 
-	BuildEnv    []string
-	Env         []string
-	ServiceRefs []string
+	BuildEnv          []string
+	BuildEnvFile      string
+	BuildCacheImage   string
+	Builder           string
+	RunImage          string
+	Env               []string
+	ServiceRefs       []string
+	ServiceRefSecrets []string
+	ImagePullSecrets  []string
 
 	ServiceAccountName string
 
 	LimitCPU    string
 	LimitMemory string
+	Limits      []string
 
 	MavenGroup    string
 	MavenArtifact string
@@ -120,19 +228,38 @@ type WorkloadOptions struct {
 	MavenType     string
 
 	CACertPaths      []string
+	RegistryCAs      []string
 	RegistryUsername string
 	RegistryPassword string
 	RegistryToken    string
+	RegistryProxy    string
 
 	RequestCPU    string
 	RequestMemory string
-
-	Wait           bool
-	WaitTimeout    time.Duration
-	Tail           bool
-	TailTimestamps bool
-	DryRun         bool
-	Yes            bool
+	Requests      []string
+
+	Wait            bool
+	WaitFor         []string
+	WaitTimeout     time.Duration
+	Tail            bool
+	TailTimestamps  bool
+	DryRun          bool
+	OutputFormat    string
+	Yes             bool
+	Force           bool
+	AllowProtected  bool
+	Output          string
+	VerifyURL       string
+	VerifyCommand   string
+	TrustVerifyCmd  bool
+	DiffTool        string
+	ImagePin        bool
+	FieldManager    string
+	ForceConflicts  bool
+	ConflictRetries int
+	Audit           bool
+	CreateNamespace bool
+	NamespaceLabels []string
 }
 
 var _ validation.Validatable = (*WorkloadUpdateOptions)(nil)
@@ -143,14 +270,58 @@ func (opts *WorkloadOptions) Validate(ctx context.Context) validation.FieldError
 	errs = errs.Also(validation.K8sName(opts.Namespace, flags.NamespaceFlagName))
 	if opts.FilePath == "" {
 		errs = errs.Also(validation.K8sName(opts.Name, cli.NameArgumentName))
+	} else if hasScheme(opts.FilePath, "http") {
+		// workload definitions are only downloaded over TLS
+		errs = errs.Also(validation.ErrInvalidValue(opts.FilePath, flags.FilePathFlagName))
+	}
+	if opts.FileSHA256 != "" {
+		if opts.FilePath == "" {
+			errs = errs.Also(validation.ErrMissingField(flags.FilePathFlagName))
+		}
+		if !sha256Regex.MatchString(opts.FileSHA256) {
+			errs = errs.Also(validation.ErrInvalidValue(opts.FileSHA256, flags.FileSHA256FlagName))
+		}
+	}
+	if templating(opts.ValuesFiles, opts.Set) {
+		// the values are rendered into the placeholders of the file
+		if opts.FilePath == "" {
+			errs = errs.Also(validation.ErrMissingField(flags.FilePathFlagName))
+		}
+		errs = errs.Also(validation.KeyValues(opts.Set, flags.SetFlagName))
+	}
+	if opts.VerifySignature != "" || opts.SignatureKey != "" {
+		if opts.FilePath == "" {
+			errs = errs.Also(validation.ErrMissingField(flags.FilePathFlagName))
+		}
+		if opts.VerifySignature == "" {
+			errs = errs.Also(validation.ErrMissingField(flags.VerifySignatureFlagName))
+		} else if hasScheme(opts.VerifySignature, "http") {
+			// signatures are only downloaded over TLS, like the workload definitions they sign
+			errs = errs.Also(validation.ErrInvalidValue(opts.VerifySignature, flags.VerifySignatureFlagName))
+		}
+		if opts.SignatureKey == "" {
+			errs = errs.Also(validation.ErrMissingField(flags.SignatureKeyFlagName))
+		}
 	}
+	errs = errs.Also(opts.validateGitPR())
 	errs = errs.Also(validation.DeletableKeyValues(opts.Labels, flags.LabelFlagName))
 	errs = errs.Also(validation.DeletableKeyValues(opts.Annotations, flags.AnnotationFlagName))
+	errs = errs.Also(validation.DeletableKeyValues(opts.PodAnnotations, flags.PodAnnotationFlagName))
 	errs = errs.Also(validation.DeletableKeyValues(opts.Params, flags.ParamFlagName))
+	errs = errs.Also(validation.DeletableIntKeyValues(opts.ParamsInt, flags.ParamIntFlagName))
+	errs = errs.Also(validation.DeletableBoolKeyValues(opts.ParamsBool, flags.ParamBoolFlagName))
 	errs = errs.Also(validation.JsonOrYamlKeyValues(opts.ParamsYaml, flags.ParamYamlFlagName))
+	errs = errs.Also(validation.KeyValues(opts.ParamFiles, flags.ParamFileFlagName))
 	errs = errs.Also(validation.DeletableEnvVars(opts.Env, flags.EnvFlagName))
 	errs = errs.Also(validation.DeletableEnvVars(opts.BuildEnv, flags.BuildEnvFlagName))
+	if opts.Builder != "" {
+		errs = errs.Also(validation.K8sName(opts.Builder, flags.BuilderFlagName))
+	}
 	errs = errs.Also(validation.DeletableKeyObjectReferences(opts.ServiceRefs, flags.ServiceRefFlagName))
+	errs = errs.Also(validateServiceRefSecrets(opts.ServiceRefSecrets, opts.ServiceRefs))
+	for i, secret := range opts.ImagePullSecrets {
+		errs = errs.Also(validation.K8sName(strings.TrimSuffix(secret, "-"), validation.CurrentField).ViaFieldIndex(flags.ImagePullSecretFlagName, i))
+	}
 
 	if opts.LimitCPU != "" {
 		errs = errs.Also(validation.Quantity(opts.LimitCPU, flags.LimitCPUFlagName))
@@ -172,6 +343,7 @@ func (opts *WorkloadOptions) Validate(ctx context.Context) validation.FieldError
 	if opts.LimitMemory != "" && opts.RequestMemory != "" {
 		errs = errs.Also(validation.CompareQuantity(opts.LimitMemory, opts.RequestMemory, flags.RequestMemoryFlagName))
 	}
+	errs = errs.Also(validateResourceQuantities(opts.Limits, opts.Requests))
 
 	if opts.RegistryPassword != "" || opts.RegistryUsername != "" || opts.RegistryToken != "" || len(opts.CACertPaths) != 0 {
 		if opts.SourceImage == "" {
@@ -182,9 +354,309 @@ func (opts *WorkloadOptions) Validate(ctx context.Context) validation.FieldError
 		}
 	}
 
+	if opts.RegistryProxy != "" {
+		if _, err := source.ParseProxy(opts.RegistryProxy); err != nil {
+			errs = errs.Also(validation.ErrInvalidValue(opts.RegistryProxy, flags.RegistryProxyFlagName))
+		}
+	}
+
+	if len(opts.RegistryCAs) != 0 {
+		errs = errs.Also(validation.KeyValues(opts.RegistryCAs, flags.RegistryCAFlagName))
+		// the certificates are used to publish the source or to resolve the digest of the image
+		if opts.LocalPath == "" && !opts.ImagePin && opts.SourceImagePull == "" {
+			errs = errs.Also(validation.ErrMissingOneOf(flags.LocalPathFlagName, flags.ImagePinFlagName, flags.SourceImagePullFlagName))
+		}
+	}
+
+	if opts.SourceImagePull != "" {
+		errs = errs.Also(validation.Enum(opts.SourceImagePull, flags.SourceImagePullFlagName, sourceImagePullModes))
+		// the source image is either published from the local path or published by another pipeline
+		if opts.LocalPath != "" {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.LocalPathFlagName, flags.SourceImagePullFlagName))
+		}
+	}
+
+	if opts.MaxSourceSize != "" {
+		errs = errs.Also(validation.Quantity(opts.MaxSourceSize, flags.MaxSourceSizeFlagName))
+	}
+
+	if opts.Output != "" {
+		errs = errs.Also(validation.Enum(opts.Output, flags.OutputFlagName, []string{printer.OutputFormatJson, OutputFormatNameAndURL}))
+	}
+
+	if opts.OutputFormat != "" {
+		errs = errs.Also(validation.Enum(opts.OutputFormat, flags.OutputFormatFlagName, dryRunFormats))
+		if !opts.DryRun {
+			errs = errs.Also(validation.ErrMissingField(flags.DryRunFlagName))
+		}
+		// a patch only layers the workload on top of existing manifests, it can not hold the namespace
+		if opts.OutputFormat != DryRunFormatYaml && opts.CreateNamespace {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.CreateNamespaceFlagName, flags.OutputFormatFlagName))
+		}
+	}
+
+	if opts.Visibility != "" {
+		errs = errs.Also(validation.Enum(opts.Visibility, flags.VisibilityFlagName, []string{apis.KnativeVisibilityClusterLocal, VisibilityPublic}))
+	}
+
+	errs = errs.Also(validateWaitFor(opts.WaitFor))
+
+	if len(opts.NamespaceLabels) != 0 {
+		errs = errs.Also(validation.KeyValues(opts.NamespaceLabels, flags.NamespaceLabelFlagName))
+		if !opts.CreateNamespace {
+			errs = errs.Also(validation.ErrMissingField(flags.CreateNamespaceFlagName))
+		}
+	}
+
+	if opts.ConflictRetries < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(opts.ConflictRetries, flags.ConflictRetriesFlagName))
+	}
+
+	if opts.VerifyURL != "" || opts.VerifyCommand != "" || opts.TrustVerifyCmd {
+		// smoke checks run once the workload is ready
+		if !opts.waiting() && !opts.Tail && !opts.TailTimestamps {
+			errs = errs.Also(validation.ErrMissingField(flags.WaitFlagName))
+		}
+		errs = errs.Also(validateVerifyURL(opts.VerifyURL))
+	}
+
+	return errs
+}
+
+// ValidateProtectedPrefixes rejects labels and annotations set or removed from the command line
+// when their key starts with a prefix declared as protected in the plugin config, unless forced.
+func (opts *WorkloadOptions) ValidateProtectedPrefixes(c *cli.Config) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	if opts.Force {
+		return errs
+	}
+
+	guard := func(key, field string) {
+		errs = errs.Also(validateProtectedPrefix(c, key, field))
+	}
+
+	for _, label := range opts.Labels {
+		guard(parsers.DeletableKeyValue(label)[0], flags.LabelFlagName)
+	}
+	for _, annotation := range opts.Annotations {
+		guard(parsers.DeletableKeyValue(annotation)[0], flags.AnnotationFlagName)
+	}
+	for _, annotation := range opts.PodAnnotations {
+		guard(parsers.DeletableKeyValue(annotation)[0], flags.PodAnnotationFlagName)
+	}
+	if opts.App != "" {
+		guard(apis.AppPartOfLabelName, flags.AppFlagName)
+	}
+	if opts.Type != "" {
+		guard(apis.WorkloadTypeLabelName, flags.TypeFlagName)
+	}
+	if opts.Visibility != "" {
+		guard(apis.KnativeVisibilityLabelName, flags.VisibilityFlagName)
+	}
+
+	return errs
+}
+
+// protectedPrefix returns the prefix declared as protected in the plugin config the key of a label or
+// annotation starts with
+func protectedPrefix(c *cli.Config, key string) (string, bool) {
+	for _, prefix := range c.Viper.GetStringSlice(LabelPrefixGuardConfigKey) {
+		if prefix != "" && strings.HasPrefix(key, prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// validateProtectedPrefix rejects setting or removing the label or annotation of the field when its key
+// starts with a protected prefix
+func validateProtectedPrefix(c *cli.Config, key, field string) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	if prefix, ok := protectedPrefix(c, key); ok {
+		errs = errs.Also(validation.ErrForbiddenFieldWithDetail(field, fmt.Sprintf("%q uses the prefix %q which is owned by the platform, changing it may break controllers managing the workload. Use %s to override", key, prefix, flags.ForceFlagName)))
+	}
+	return errs
+}
+
+// validateProtectedNamespace rejects changing workloads in a namespace declared as protected in the
+// plugin config, unless allowed.
+func validateProtectedNamespace(c *cli.Config, namespace string, allowProtected bool) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	if allowProtected || namespace == "" {
+		return errs
+	}
+	for _, protected := range c.Viper.GetStringSlice(ProtectedNamespacesConfigKey) {
+		if protected == namespace {
+			errs = errs.Also(validation.ErrForbiddenFieldWithDetail(flags.NamespaceFlagName, fmt.Sprintf("namespace %q is protected by the platform, workloads are not expected to be changed in it. Use %s to override", namespace, flags.AllowProtectedFlagName)))
+			break
+		}
+	}
+	return errs
+}
+
+// validateServiceRefSecrets checks the "service-ref-name=secret-name" pairs of --service-ref-secret, a
+// service ref name can not be bound to a secret and to another service at once.
+func validateServiceRefSecrets(refs, serviceRefs []string) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	bound := sets.NewString()
+	for _, ref := range serviceRefs {
+		if strings.Contains(ref, "=") {
+			bound.Insert(parsers.KeyValue(ref)[0])
+		}
+	}
+	for i, ref := range refs {
+		parts := strings.Split(ref, "=")
+		if len(parts) == 2 {
+			errs = errs.Also(validation.K8sName(parts[0], validation.CurrentField).ViaFieldIndex(flags.ServiceRefSecretFlagName, i))
+			errs = errs.Also(validation.K8sName(parts[1], validation.CurrentField).ViaFieldIndex(flags.ServiceRefSecretFlagName, i))
+			if bound.Has(parts[0]) {
+				errs = errs.Also(validation.ErrMultipleOneOf(flags.ServiceRefFlagName, flags.ServiceRefSecretFlagName))
+			}
+		} else if !strings.HasSuffix(ref, "-") {
+			errs = errs.Also(validation.ErrInvalidValue(ref, validation.CurrentField).ViaFieldIndex(flags.ServiceRefSecretFlagName, i))
+		}
+	}
+	return errs
+}
+
+// validateResourceQuantities checks the "name=quantity" pairs of --limit and --request, a request can
+// not be greater than the limit of the same resource.
+func validateResourceQuantities(limits, requests []string) validation.FieldErrors {
+	limitErrs := validation.DeletableResourceQuantities(limits, flags.LimitFlagName)
+	requestErrs := validation.DeletableResourceQuantities(requests, flags.RequestFlagName)
+	errs := validation.FieldErrors{}.Also(limitErrs, requestErrs)
+	if len(errs) != 0 {
+		return errs
+	}
+
+	limitQuantities := map[string]string{}
+	for _, limit := range limits {
+		if parts := parsers.DeletableKeyValue(limit); len(parts) == 2 {
+			limitQuantities[parts[0]] = parts[1]
+		}
+	}
+	for i, request := range requests {
+		parts := parsers.DeletableKeyValue(request)
+		limit, ok := limitQuantities[parts[0]]
+		if !ok || len(parts) == 1 {
+			continue
+		}
+		if len(validation.CompareQuantity(limit, parts[1], validation.CurrentField)) != 0 {
+			errs = errs.Also(validation.ErrInvalidValue(request, validation.CurrentField).ViaFieldIndex(flags.RequestFlagName, i))
+		}
+	}
 	return errs
 }
 
+// validateServiceRefs fails fast when a service ref set from the command line can not be bound, the
+// workload would otherwise be created but never become ready. Kinds of --service-ref must be known by the
+// cluster and secrets of --service-ref-secret must exist in the namespace. Checks that can not be made,
+// like when the user is not allowed to read secrets or the kinds served by the cluster can not be
+// discovered, are skipped.
+func (opts *WorkloadOptions) validateServiceRefs(ctx context.Context, c *cli.Config, namespace string) error {
+	var msgs []string
+	if len(opts.ServiceRefs) != 0 {
+		if mapper, err := c.ToRESTMapper(); err != nil {
+			c.Eprintf("%s unable to validate the kinds of the service refs against the cluster: %s\n", printer.Swarnf("Warning:"), err)
+		} else {
+			msgs = append(msgs, opts.unservedServiceRefs(mapper)...)
+		}
+	}
+	for _, ref := range opts.ServiceRefSecrets {
+		parts := parsers.DeletableKeyValue(ref)
+		if len(parts) == 1 {
+			continue
+		}
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: parts[1]}, secret); err != nil && apierrs.IsNotFound(err) {
+			msgs = append(msgs, fmt.Sprintf("service ref %q refers to secret %q which was not found in namespace %q, the workload would never bind to it", parts[0], parts[1], namespace))
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	for _, msg := range msgs {
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), msg)
+	}
+	return cli.SilenceError(fmt.Errorf("unable to bind service refs"))
+}
+
+// unservedServiceRefs describes the service refs of --service-ref to a kind the cluster does not serve
+func (opts *WorkloadOptions) unservedServiceRefs(mapper meta.RESTMapper) []string {
+	var msgs []string
+	for _, ref := range opts.ServiceRefs {
+		parts := parsers.DeletableKeyValue(ref)
+		if len(parts) == 1 {
+			continue
+		}
+		objRef := parsers.ObjectReference(parts[1])
+		gv, err := schema.ParseGroupVersion(objRef.APIVersion)
+		if err != nil {
+			continue
+		}
+		if _, err := mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: objRef.Kind}, gv.Version); err != nil && meta.IsNoMatchError(err) {
+			msgs = append(msgs, fmt.Sprintf("service ref %q refers to kind %q in %q which is not served by the cluster, the workload would never bind to it", parts[0], objRef.Kind, objRef.APIVersion))
+		}
+	}
+	return msgs
+}
+
+// validateImagePullSecrets fails fast when a secret of --image-pull-secret is not found in the namespace,
+// the pods of the workload would otherwise fail to pull their images. A secret that does not hold registry
+// credentials is only warned about. Secrets that can not be read are skipped.
+func (opts *WorkloadOptions) validateImagePullSecrets(ctx context.Context, c *cli.Config, namespace string) error {
+	var msgs []string
+	for _, name := range opts.ImagePullSecrets {
+		if strings.HasSuffix(name, "-") {
+			continue
+		}
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+			if apierrs.IsNotFound(err) {
+				msgs = append(msgs, fmt.Sprintf("image pull secret %q was not found in namespace %q, the pods of the workload would fail to pull their images", name, namespace))
+			}
+			continue
+		}
+		if secret.Type != corev1.SecretTypeDockerConfigJson && secret.Type != corev1.SecretTypeDockercfg {
+			c.Eprintf("%s image pull secret %q is of type %q, expected %q\n", printer.Swarnf("Warning:"), name, secret.Type, corev1.SecretTypeDockerConfigJson)
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	for _, msg := range msgs {
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), msg)
+	}
+	return cli.SilenceError(fmt.Errorf("unable to find image pull secrets"))
+}
+
+// validateType fails fast when no cluster supply chain selects the type of the workload, the workload
+// would otherwise be created but never reconciled. The types selected by the supply chains are listed
+// as candidates. The check is skipped when the supply chains can not be listed.
+func (opts *WorkloadOptions) validateType(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) error {
+	workloadType := workload.Labels[apis.WorkloadTypeLabelName]
+	if !opts.ValidateType || workloadType == "" {
+		return nil
+	}
+	supplyChains := &cartov1alpha1.ClusterSupplyChainList{}
+	if err := c.List(ctx, supplyChains); err != nil {
+		c.Eprintf("%s unable to validate workload type %q against the cluster supply chains: %s\n", printer.Swarnf("Warning:"), workloadType, err)
+		return nil
+	}
+	types := sets.NewString()
+	for i := range supplyChains.Items {
+		if supplyChains.Items[i].SelectsWorkloadType(workloadType) {
+			return nil
+		}
+		types.Insert(supplyChains.Items[i].WorkloadTypes()...)
+	}
+	c.Eprintf("%s no supply chain selects workloads of type %q, the workload would never be reconciled\n", printer.Serrorf("Error:"), workloadType)
+	if types.Len() != 0 {
+		c.Eprintf("Supported types: %s\n", strings.Join(types.List(), ", "))
+	}
+	return cli.SilenceError(fmt.Errorf("unsupported workload type %q", workloadType))
+}
+
 func DisplayCommandNextSteps(c *cli.Config, workload *cartov1alpha1.Workload) {
 	if workload.Namespace != c.Client.DefaultNamespace() {
 		c.Infof("To see logs:   \"tanzu apps workload tail %s %s %s\"\n", workload.Name, flags.NamespaceFlagName, workload.Namespace)
@@ -216,6 +688,14 @@ func (opts *WorkloadOptions) ApplyOptionsToWorkload(ctx context.Context, workloa
 			workload.Spec.MergeAnnotationParams(kv[0], kv[1])
 		}
 	}
+	for _, annotation := range opts.PodAnnotations {
+		kv := parsers.DeletableKeyValue(annotation)
+		if len(kv) == 1 {
+			workload.Spec.RemovePodAnnotationParams(kv[0])
+		} else {
+			workload.Spec.MergePodAnnotationParams(kv[0], kv[1])
+		}
+	}
 
 	for _, p := range opts.Params {
 		kv := parsers.DeletableKeyValue(p)
@@ -225,6 +705,27 @@ func (opts *WorkloadOptions) ApplyOptionsToWorkload(ctx context.Context, workloa
 			workload.Spec.MergeParams(kv[0], kv[1])
 		}
 	}
+	// typed params are encoded as JSON numbers and booleans, for supply chains that do not accept strings
+	for _, p := range opts.ParamsInt {
+		kv := parsers.DeletableKeyValue(p)
+		if len(kv) == 1 {
+			workload.Spec.RemoveParam(kv[0])
+		} else {
+			// errors are caught during the validation phase
+			i, _ := strconv.ParseInt(kv[1], 10, 64)
+			workload.Spec.MergeParams(kv[0], i)
+		}
+	}
+	for _, p := range opts.ParamsBool {
+		kv := parsers.DeletableKeyValue(p)
+		if len(kv) == 1 {
+			workload.Spec.RemoveParam(kv[0])
+		} else {
+			// errors are caught during the validation phase
+			b, _ := strconv.ParseBool(kv[1])
+			workload.Spec.MergeParams(kv[0], b)
+		}
+	}
 
 	var mavenSourceViaFlags bool
 	if opts.MavenArtifact != "" || opts.MavenVersion != "" || opts.MavenGroup != "" || opts.MavenType != "" {
@@ -273,6 +774,13 @@ func (opts *WorkloadOptions) ApplyOptionsToWorkload(ctx context.Context, workloa
 		workload.MergeLabels(apis.WorkloadTypeLabelName, opts.Type)
 	}
 
+	switch opts.Visibility {
+	case apis.KnativeVisibilityClusterLocal:
+		workload.MergeLabels(apis.KnativeVisibilityLabelName, apis.KnativeVisibilityClusterLocal)
+	case VisibilityPublic:
+		delete(workload.Labels, apis.KnativeVisibilityLabelName)
+	}
+
 	if opts.Debug {
 		workload.Spec.MergeParams("debug", "true")
 	} else if cli.CommandFromContext(ctx).Flags().Changed(cli.StripDash(flags.DebugFlagName)) {
@@ -328,6 +836,25 @@ func (opts *WorkloadOptions) ApplyOptionsToWorkload(ctx context.Context, workloa
 		}
 	}
 
+	for _, p := range []struct {
+		param string
+		flag  string
+		value string
+	}{
+		{param: cartov1alpha1.WorkloadBuildCacheImageParam, flag: flags.BuildCacheImageFlagName, value: opts.BuildCacheImage},
+		{param: cartov1alpha1.WorkloadClusterBuilderParam, flag: flags.BuilderFlagName, value: opts.Builder},
+		{param: cartov1alpha1.WorkloadRunImageParam, flag: flags.RunImageFlagName, value: opts.RunImage},
+	} {
+		if !cli.CommandFromContext(ctx).Flags().Changed(cli.StripDash(p.flag)) {
+			continue
+		}
+		if p.value == "" {
+			workload.Spec.RemoveParam(p.param)
+		} else {
+			workload.Spec.MergeParams(p.param, p.value)
+		}
+	}
+
 	for _, ref := range opts.ServiceRefs {
 		parts := parsers.DeletableKeyValue(ref)
 		serviceRefKey := parts[0]
@@ -346,6 +873,59 @@ func (opts *WorkloadOptions) ApplyOptionsToWorkload(ctx context.Context, workloa
 		}
 	}
 
+	for _, ref := range opts.ServiceRefSecrets {
+		parts := parsers.DeletableKeyValue(ref)
+		serviceRefKey := parts[0]
+		if len(parts) == 1 {
+			workload.Spec.DeleteServiceClaim(serviceRefKey)
+		} else {
+			// the secret is read from the namespace of the workload
+			workload.Spec.MergeServiceClaim(cartov1alpha1.NewServiceClaim(serviceRefKey, corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Secret",
+				Name:       parts[1],
+			}))
+		}
+		workload.DeleteServiceClaimAnnotation(serviceRefKey)
+	}
+
+	for _, secret := range opts.ImagePullSecrets {
+		if name := strings.TrimSuffix(secret, "-"); name != secret {
+			workload.Spec.RemoveImagePullSecret(name)
+		} else {
+			workload.Spec.MergeImagePullSecret(secret)
+		}
+	}
+
+	// --limit-cpu, --limit-memory, --request-cpu and --request-memory are applied after, they win over
+	// the same resources set with --limit and --request
+	for _, limit := range opts.Limits {
+		parts := parsers.DeletableKeyValue(limit)
+		if len(parts) == 1 {
+			workload.Spec.RemoveResourceLimit(corev1.ResourceName(parts[0]))
+		} else {
+			workload.Spec.MergeResources(&corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					// parse errors are handled by the opt validation
+					corev1.ResourceName(parts[0]): resource.MustParse(parts[1]),
+				},
+			})
+		}
+	}
+	for _, request := range opts.Requests {
+		parts := parsers.DeletableKeyValue(request)
+		if len(parts) == 1 {
+			workload.Spec.RemoveResourceRequest(corev1.ResourceName(parts[0]))
+		} else {
+			workload.Spec.MergeResources(&corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					// parse errors are handled by the opt validation
+					corev1.ResourceName(parts[0]): resource.MustParse(parts[1]),
+				},
+			})
+		}
+	}
+
 	if opts.LimitCPU != "" {
 		workload.Spec.MergeResources(&corev1.ResourceRequirements{
 			Limits: corev1.ResourceList{
@@ -389,6 +969,59 @@ func (opts *WorkloadOptions) ApplyOptionsToWorkload(ctx context.Context, workloa
 	return ctx
 }
 
+// PinImage resolves the tag of the pre-built image of the workload to the digest it currently points to when
+// --image-pin is set, so the workload does not drift when the tag is pushed again
+func (opts *WorkloadOptions) PinImage(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) error {
+	if !opts.ImagePin {
+		return nil
+	}
+	if workload.Spec.Image == "" {
+		return validation.ErrMissingField(flags.ImageFlagName).ToAggregate()
+	}
+	if strings.Contains(workload.Spec.Image, "@") {
+		c.Infof("Image %q is already pinned to a digest\n", workload.Spec.Image)
+		return nil
+	}
+
+	digestedImage, err := source.ImageDigest(ctx, opts.registryOpts(), workload.Spec.Image)
+	if err != nil {
+		c.Eprintf("%s unable to resolve the digest of image %q: %s\n", printer.Serrorf("Error:"), workload.Spec.Image, err)
+		return cli.SilenceError(err)
+	}
+	c.Infof("Pinned image %q to %q\n", workload.Spec.Image, digestedImage)
+	workload.Spec.Image = digestedImage
+	return nil
+}
+
+// PullSourceImage checks the source image of the workload, published by another pipeline, exists in the
+// registry when --source-image-pull is set, and pins it to the digest its tag points to with the digest mode
+func (opts *WorkloadOptions) PullSourceImage(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) error {
+	if opts.SourceImagePull == "" {
+		return nil
+	}
+	if workload.Spec.Source == nil || workload.Spec.Source.Image == "" {
+		return validation.ErrMissingField(flags.SourceImageFlagName).ToAggregate()
+	}
+	image := workload.Spec.Source.Image
+	if strings.Contains(image, "@") {
+		c.Infof("Source image %q is already pinned to a digest\n", image)
+		return nil
+	}
+
+	digestedImage, err := source.ImageDigest(ctx, opts.registryOpts(), image)
+	if err != nil {
+		c.Eprintf("%s unable to find source image %q: %s\n", printer.Serrorf("Error:"), image, err)
+		return cli.SilenceError(err)
+	}
+	if opts.SourceImagePull == SourceImagePullDigest {
+		c.Infof("Pinned source image %q to %q\n", image, digestedImage)
+		workload.Spec.Source.Image = digestedImage
+	} else {
+		c.Infof("Found source image %q\n", image)
+	}
+	return nil
+}
+
 // PublishLocalSource packages the specified source code in the --local-path flag and creates an image
 // that will be eventually published to the registry specified in the --source-image flag.
 // Returns a boolean that indicates if user does actually want to publish the image and an error in case of failure
@@ -398,10 +1031,6 @@ func (opts *WorkloadOptions) PublishLocalSource(ctx context.Context, c *cli.Conf
 	}
 
 	taggedImage := strings.Split(workload.Spec.Source.Image, "@sha")[0]
-	okToPush := opts.checkToPublishLocalSource(taggedImage, c, workload)
-	if !okToPush {
-		return okToPush, nil
-	}
 
 	var contentDir string
 	var fileExclusions []string
@@ -428,12 +1057,58 @@ func (opts *WorkloadOptions) PublishLocalSource(ctx context.Context, c *cli.Conf
 		return false, fmt.Errorf("unsupported file format %q", opts.LocalPath)
 	}
 
-	c.Infof("Publishing source in %q to %q...\n", opts.LocalPath, taggedImage)
+	switch lineEndings := c.Viper.GetString(SourceLineEndingsConfigKey); lineEndings {
+	case "", source.LineEndingsKeep:
+	case source.LineEndingsLF:
+		// scripts checked out with CRLF line endings on Windows fail to run in the build
+		normalizedDir, err := source.NormalizeLineEndings(contentDir, fileExclusions)
+		if err != nil {
+			return false, err
+		}
+		defer os.RemoveAll(normalizedDir)
+		contentDir = normalizedDir
+		fileExclusions = []string{}
+	default:
+		return false, fmt.Errorf("invalid %s %q in the plugin config, expected one of %s", SourceLineEndingsConfigKey, lineEndings, strings.Join(source.LineEndings, ", "))
+	}
+
+	if !opts.ForcePush {
+		// the source is not uploaded again when the tag already points to the same content
+		if digestedImage, unchanged := source.PublishedImage(ctx, contentDir, fileExclusions, opts.registryOpts(), taggedImage); unchanged {
+			c.Infof("Source in %q unchanged, skipping publish to %q\n", opts.LocalPath, taggedImage)
+			workload.Spec.Source.Image = digestedImage
+			return true, nil
+		}
+	}
+
+	// the size is reported before the prompt so a large source can be declined
+	opts.warnLargeSource(c, contentDir, fileExclusions)
+	okToPush := opts.checkToPublishLocalSource(taggedImage, c, workload)
+	if !okToPush {
+		return okToPush, nil
+	}
+
+	upload, resumed := source.PrepareUpload(ctx, contentDir, fileExclusions, taggedImage)
+	if resumed {
+		// the source packaged before the interruption is reused and the layers already in the registry
+		// are not uploaded again
+		c.Infof("Resuming interrupted publish of source in %q to %q...\n", opts.LocalPath, taggedImage)
+	} else {
+		c.Infof("Publishing source in %q to %q...\n", opts.LocalPath, taggedImage)
+	}
+	ctx = source.StashUploadState(ctx, upload)
 
-	currentRegistryOpts := source.RegistryOpts{CACertPaths: opts.CACertPaths, RegistryUsername: opts.RegistryUsername, RegistryPassword: opts.RegistryPassword, RegistryToken: opts.RegistryToken}
 	ctx = logger.StashSourceImageLogger(ctx, logger.NewNoopLogger())
+	if opts.Output == printer.OutputFormatJson {
+		ctx = source.StashProgressReporter(ctx, source.NewJSONProgressReporter(c.Stderr))
+	}
 
-	digestedImage, err := source.ImgpkgPush(ctx, contentDir, fileExclusions, &currentRegistryOpts, taggedImage)
+	start := time.Now()
+	digestedImage, err := source.ImgpkgPush(ctx, contentDir, fileExclusions, opts.registryOpts(), taggedImage)
+	telemetry.AddEvent(ctx, "Source Push", append([]telemetry.Attribute{
+		telemetry.String("source.image", taggedImage),
+		telemetry.Duration("duration_ms", time.Since(start)),
+	}, telemetry.Error(err)...)...)
 	if err != nil {
 		return okToPush, err
 	}
@@ -448,6 +1123,48 @@ func (opts *WorkloadOptions) PublishLocalSource(ctx context.Context, c *cli.Conf
 	return okToPush, nil
 }
 
+// registryOpts are the options to talk to the registries of the source image and of the pre-built image,
+// the certificates of --registry-ca are only trusted for the registry host they are set for
+func (opts *WorkloadOptions) registryOpts() *source.RegistryOpts {
+	hostCACertPaths := map[string][]string{}
+	for _, ca := range opts.RegistryCAs {
+		// parse errors are handled by the opt validation
+		kv := parsers.KeyValue(ca)
+		hostCACertPaths[kv[0]] = append(hostCACertPaths[kv[0]], kv[1])
+	}
+	return &source.RegistryOpts{
+		CACertPaths:      opts.CACertPaths,
+		HostCACertPaths:  hostCACertPaths,
+		RegistryUsername: opts.RegistryUsername,
+		RegistryPassword: opts.RegistryPassword,
+		RegistryToken:    opts.RegistryToken,
+		Proxy:            opts.RegistryProxy,
+	}
+}
+
+// warnLargeSource warns when the source to publish is larger than --max-source-size and lists the largest
+// files, data dumps or build outputs left in the local path by accident make the upload painfully slow
+func (opts *WorkloadOptions) warnLargeSource(c *cli.Config, dir string, excludedFiles []string) {
+	if opts.MaxSourceSize == "" {
+		return
+	}
+	maxSize, err := resource.ParseQuantity(opts.MaxSourceSize)
+	if err != nil || maxSize.IsZero() {
+		return
+	}
+	total, largest, err := source.Size(dir, excludedFiles, 5)
+	if err != nil || total <= maxSize.Value() {
+		return
+	}
+	c.Infof("WARNING: the source in %q is %s, larger than %s %s. The largest files are:\n", opts.LocalPath, source.FormatSize(total), flags.MaxSourceSizeFlagName, opts.MaxSourceSize)
+	for _, file := range largest {
+		c.Infof("  %-8s %s\n", source.FormatSize(file.Size), file.Path)
+	}
+	if opts.ExcludePathFile != "" {
+		c.Infof("Files that are not needed to build the workload can be excluded with the %s file\n", opts.ExcludePathFile)
+	}
+}
+
 func (opts *WorkloadOptions) checkToPublishLocalSource(taggedImage string, c *cli.Config, workload *cartov1alpha1.Workload) bool {
 	okToPush := true
 	if !opts.Yes {
@@ -462,48 +1179,69 @@ func (opts *WorkloadOptions) checkToPublishLocalSource(taggedImage string, c *cl
 	return okToPush
 }
 
+// loadExcludedPaths lists the paths of the local source not to publish: .git and node_modules,
+// the patterns of the .gitignore files and of the exclude path file, at any depth of the source
 func (opts *WorkloadOptions) loadExcludedPaths(c *cli.Config) []string {
-	exclude := []string{}
+	exclude, err := opts.excludedPaths()
+	if err != nil {
+		c.Infof("Unable to read %s file.\n", strings.Join(opts.ignoreFiles(), " or "))
+		return []string{}
+	}
 	if opts.ExcludePathFile != "" {
-		p := filepath.Join(opts.LocalPath, opts.ExcludePathFile)
-		if _, err := os.Stat(p); errors.Is(err, os.ErrNotExist) {
-			return exclude
-		}
-
-		f, err := os.Open(p)
-		if err != nil {
-			c.Infof("Unable to read %s file.\n", opts.ExcludePathFile)
-			return exclude
+		if _, err := os.Stat(filepath.Join(opts.LocalPath, opts.ExcludePathFile)); err == nil {
+			c.Infof("The files and/or directories listed in the %s file are being excluded from the uploaded source code.\n", opts.ExcludePathFile)
 		}
-		defer f.Close()
-		r := bufio.NewReader(f)
-		for {
-			l, _, err := r.ReadLine()
-			if err == io.EOF {
-				break
-			}
-			p := strings.TrimSpace(string(l))
-			if len(p) == 0 || strings.HasPrefix(p, "#") {
-				continue
-			}
-			if strings.HasSuffix(p, string(os.PathSeparator)) {
-				p = p[:len(p)-1]
-			}
-			exclude = append(exclude, p)
-		}
-		c.Infof("The files and/or directories listed in the %s file are being excluded from the uploaded source code.\n", opts.ExcludePathFile)
 	}
 	return exclude
 }
 
+// excludedPaths lists the paths of the local source not to publish, without reporting them
+func (opts *WorkloadOptions) excludedPaths() ([]string, error) {
+	return source.ExcludedPaths(opts.LocalPath, opts.ignoreFiles()...)
+}
+
+func (opts *WorkloadOptions) ignoreFiles() []string {
+	ignoreFiles := []string{source.GitIgnoreFile}
+	if opts.ExcludePathFile != "" {
+		ignoreFiles = append(ignoreFiles, opts.ExcludePathFile)
+	}
+	return ignoreFiles
+}
+
 func loadNamespace(ctx context.Context, c *cli.Config, name string) (*corev1.Namespace, error) {
 	ns := &corev1.Namespace{}
-	if err := c.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil && apierrs.IsNotFound(err) {
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil {
 		return nil, err
 	}
 	return ns, nil
 }
 
+// missingNamespace checks the namespace of the workload exists. With --create-namespace, a missing
+// namespace is returned to be created along with the workload, otherwise it is an error.
+func (opts *WorkloadOptions) missingNamespace(ctx context.Context, c *cli.Config, name string) (*corev1.Namespace, error) {
+	if !opts.CreateNamespace {
+		return nil, validateNamespace(ctx, c, name)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, &corev1.Namespace{}); err == nil {
+		return nil, nil
+	} else if !apierrs.IsNotFound(err) {
+		return nil, err
+	}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	for _, label := range opts.NamespaceLabels {
+		kv := parsers.KeyValue(label)
+		if namespace.Labels == nil {
+			namespace.Labels = map[string]string{}
+		}
+		namespace.Labels[kv[0]] = kv[1]
+	}
+	return namespace, nil
+}
+
 func validateNamespace(ctx context.Context, c *cli.Config, name string) error {
 	if _, nsErr := loadNamespace(ctx, c, name); nsErr != nil {
 		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), fmt.Sprintf("namespace %q not found, it may not exist or user does not have permissions to read it.", name))
@@ -512,6 +1250,50 @@ func validateNamespace(ctx context.Context, c *cli.Config, name string) error {
 	return nil
 }
 
+// PrintDryRun prints the resources instead of applying them, a namespace that is not nil is printed
+// before the workload
+func (opts *WorkloadOptions) PrintDryRun(ctx context.Context, c *cli.Config, namespace *corev1.Namespace, workload *cartov1alpha1.Workload) error {
+	if opts.OutputFormat == "" || opts.OutputFormat == DryRunFormatYaml {
+		if namespace != nil {
+			cli.DryRunResource(ctx, namespace, corev1.SchemeGroupVersion.WithKind("Namespace"))
+		}
+		cli.DryRunResource(ctx, workload, workload.GetGroupVersionKind())
+		return nil
+	}
+
+	// the namespace is left to the kustomization or to the manifests the patch is layered on
+	patch := workload.DeepCopy()
+	patch.Namespace = ""
+	export, err := printer.ExportResource(patch, printer.OutputFormat(printer.OutputFormatYaml), c.Scheme)
+	if err != nil {
+		return err
+	}
+	if opts.OutputFormat == DryRunFormatYtt {
+		export = yttOverlay(workload.Name, export)
+	}
+	fmt.Fprintf(cli.StdoutFromContext(ctx), "%s\n", export)
+	return nil
+}
+
+// yttOverlay annotates the exported workload as a ytt overlay of the workload with the same name,
+// merging its labels and annotations and replacing its spec
+func yttOverlay(name, export string) string {
+	lines := []string{
+		`#@ load("@ytt:overlay", "overlay")`,
+		fmt.Sprintf(`#@overlay/match by=overlay.subset({"kind": %q, "metadata": {"name": %q}})`, cartov1alpha1.WorkloadKind, name),
+	}
+	for _, line := range strings.Split(export, "\n") {
+		switch line {
+		case "metadata:":
+			lines = append(lines, "#@overlay/match-child-defaults missing_ok=True")
+		case "spec:":
+			lines = append(lines, "#@overlay/replace")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (opts *WorkloadOptions) Update(ctx context.Context, c *cli.Config, currentWorkload *cartov1alpha1.Workload, workload *cartov1alpha1.Workload) (bool, error) {
 	okToUpdate := false
 
@@ -521,7 +1303,12 @@ func (opts *WorkloadOptions) Update(ctx context.Context, c *cli.Config, currentW
 		}
 	}
 
-	difference, noChange, err := printer.ResourceDiff(currentWorkload, workload, c.Scheme)
+	// preview the change with a dry run of the apply, fields owned by other managers are kept by the server
+	applied, err := opts.serverSideApply(ctx, c, currentWorkload, workload, true)
+	if err != nil {
+		return okToUpdate, err
+	}
+	difference, noChange, err := printer.ResourceDiff(currentWorkload, applied, c.Scheme)
 	if err != nil {
 		return okToUpdate, err
 	}
@@ -531,7 +1318,7 @@ func (opts *WorkloadOptions) Update(ctx context.Context, c *cli.Config, currentW
 		return okToUpdate, nil
 	}
 	c.Printf("Update workload:\n")
-	c.Printf("%s\n", difference)
+	opts.printDiff(ctx, c, currentWorkload, applied, difference)
 
 	if noticeMsgs := workload.GetNotices(ctx); len(noticeMsgs) != 0 {
 		for _, msg := range noticeMsgs {
@@ -557,11 +1344,15 @@ func (opts *WorkloadOptions) Update(ctx context.Context, c *cli.Config, currentW
 		okToUpdate = opts.Yes
 	}
 
-	if err := c.Update(ctx, workload); err != nil {
+	// the audit record is left out of the diff, it changes with every update
+	if err := auditWorkload(ctx, workload, opts.Audit); err != nil {
+		return false, err
+	}
+	if err := opts.updateWorkload(ctx, c, currentWorkload, workload); err != nil {
 		okToUpdate = false
-		if apierrs.IsConflict(err) {
+		if apierrs.IsConflict(err) && !appsclient.IsApplyConflict(err) {
 			c.Printf("%s conflict updating workload, the object was modified by another user; please run the update command again\n", printer.Serrorf("Error:"))
-			return okToUpdate, cli.SilenceError(err)
+			return okToUpdate, cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeConflict))
 		}
 		return okToUpdate, err
 	}
@@ -570,7 +1361,8 @@ func (opts *WorkloadOptions) Update(ctx context.Context, c *cli.Config, currentW
 	return okToUpdate, nil
 }
 
-func (opts *WorkloadOptions) Create(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) (bool, error) {
+// Create creates the workload once confirmed, a namespace that is not nil is created before it
+func (opts *WorkloadOptions) Create(ctx context.Context, c *cli.Config, namespace *corev1.Namespace, workload *cartov1alpha1.Workload) (bool, error) {
 	okToCreate := false
 
 	if msgs := workload.DeprecationWarnings(); len(msgs) != 0 {
@@ -579,13 +1371,30 @@ func (opts *WorkloadOptions) Create(ctx context.Context, c *cli.Config, workload
 		}
 	}
 
-	diff, _, err := printer.ResourceDiff(nil, workload, c.Scheme)
+	// a dry run of the apply fails in a namespace that is not created yet, the workload is shown as is
+	applied := workload
+	if namespace == nil {
+		var err error
+		if applied, err = opts.serverSideApply(ctx, c, nil, workload, true); err != nil {
+			return okToCreate, err
+		}
+	}
+	diff, _, err := printer.ResourceDiff(nil, applied, c.Scheme)
 	if err != nil {
 		return okToCreate, err
 	}
 
+	if namespace != nil {
+		namespaceDiff, _, err := printer.ResourceDiff(nil, namespace, c.Scheme)
+		if err != nil {
+			return okToCreate, err
+		}
+		c.Printf("Create namespace:\n")
+		c.Printf("%s\n", namespaceDiff)
+	}
+
 	c.Printf("Create workload:\n")
-	c.Printf("%s\n", diff)
+	opts.printDiff(ctx, c, nil, applied, diff)
 
 	if noticeMsgs := workload.GetNotices(ctx); len(noticeMsgs) != 0 {
 		for _, msg := range noticeMsgs {
@@ -610,7 +1419,18 @@ func (opts *WorkloadOptions) Create(ctx context.Context, c *cli.Config, workload
 		okToCreate = opts.Yes
 	}
 
-	if err := c.Create(ctx, workload); err != nil {
+	if namespace != nil {
+		if err := c.Create(ctx, namespace); err != nil {
+			return okToCreate, err
+		}
+		c.Successf("Created namespace %q\n", namespace.Name)
+	}
+	if err := auditWorkload(ctx, workload, opts.Audit); err != nil {
+		deleteCreatedNamespace(ctx, c, namespace)
+		return okToCreate, err
+	}
+	if _, err := opts.serverSideApply(ctx, c, nil, workload, false); err != nil {
+		deleteCreatedNamespace(ctx, c, namespace)
 		return okToCreate, err
 	}
 
@@ -618,85 +1438,458 @@ func (opts *WorkloadOptions) Create(ctx context.Context, c *cli.Config, workload
 	return okToCreate, nil
 }
 
-func (opts *WorkloadOptions) LoadInputWorkload(input io.Reader, workload *cartov1alpha1.Workload) error {
-	var in io.Reader
+// deleteCreatedNamespace deletes the namespace created by --create-namespace when the workload could not be
+// created in it, a namespace left behind is reported so it can be deleted by hand
+func deleteCreatedNamespace(ctx context.Context, c *cli.Config, namespace *corev1.Namespace) {
+	if namespace == nil {
+		return
+	}
+	if err := c.Delete(ctx, namespace); err != nil && !apierrs.IsNotFound(err) {
+		c.Eprintf("%s namespace %q was created but the workload was not, it is kept: %s\n", printer.Swarnf("Warning:"), namespace.Name, err)
+		return
+	}
+	c.Infof("Deleted namespace %q created for the workload\n", namespace.Name)
+}
+
+// updateWorkload applies the workload to the cluster. On a conflict, the changes from the current workload
+// are replayed onto the latest version of the workload on the cluster, which is applied again up to
+// --conflict-retries times. The workload holds the object returned by the server.
+func (opts *WorkloadOptions) updateWorkload(ctx context.Context, c *cli.Config, currentWorkload, workload *cartov1alpha1.Workload) error {
+	for attempt := 1; ; attempt++ {
+		_, err := opts.serverSideApply(ctx, c, currentWorkload, workload, false)
+		if err == nil || !apierrs.IsConflict(err) || appsclient.IsApplyConflict(err) || attempt > opts.ConflictRetries {
+			return err
+		}
+		c.Infof("Conflict updating workload %q, applying the changes to its latest version (%d/%d)\n", workload.Name, attempt, opts.ConflictRetries)
+		latestWorkload := &cartov1alpha1.Workload{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(workload), latestWorkload); err != nil {
+			return err
+		}
+		rebased, err := rebaseWorkload(currentWorkload, workload, latestWorkload)
+		if err != nil {
+			return err
+		}
+		currentWorkload = latestWorkload
+		rebased.DeepCopyInto(workload)
+	}
+}
+
+// rebaseWorkload replays the changes made from the current workload to the workload onto the latest
+// workload, as a json merge patch
+func rebaseWorkload(currentWorkload, workload, latestWorkload *cartov1alpha1.Workload) (*cartov1alpha1.Workload, error) {
+	current, err := json.Marshal(currentWorkload)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := json.Marshal(workload)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := json.Marshal(latestWorkload)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonpatch.CreateMergePatch(current, changed)
+	if err != nil {
+		return nil, err
+	}
+	rebased, err := jsonpatch.MergePatch(latest, patch)
+	if err != nil {
+		return nil, err
+	}
+	rebasedWorkload := &cartov1alpha1.Workload{}
+	if err := json.Unmarshal(rebased, rebasedWorkload); err != nil {
+		return nil, err
+	}
+	return rebasedWorkload, nil
+}
+
+// serverSideApply applies the changes from the current workload, nil when it is created, to the workload
+// with the field manager of the command. The object returned by the server is copied into the workload,
+// unless it is a dry run. Fields owned by other field managers the changes conflict with are printed.
+func (opts *WorkloadOptions) serverSideApply(ctx context.Context, c *cli.Config, currentWorkload, workload *cartov1alpha1.Workload, dryRun bool) (*cartov1alpha1.Workload, error) {
+	applied, err := appsclient.ServerSideApply(ctx, c.Client, currentWorkload, workload, appsclient.ApplyOptions{
+		FieldManager:   opts.FieldManager,
+		DryRun:         dryRun,
+		ForceConflicts: opts.ForceConflicts,
+	})
+	if appsclient.IsApplyConflict(err) {
+		c.Eprintf("%s fields of workload %q are owned by other field managers, use %s to take them over\n", printer.Serrorf("Error:"), workload.Name, flags.ForceConflictsFlagName)
+		if status, ok := err.(apierrs.APIStatus); ok && status.Status().Details != nil {
+			for _, cause := range status.Status().Details.Causes {
+				c.Eprintf("  %s: %s\n", cause.Field, cause.Message)
+			}
+		}
+		return nil, cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeConflict))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		applied.DeepCopyInto(workload)
+	}
+	return applied, nil
+}
+
+// redirectOutput sends the human readable output to stderr with --output name-and-url, so stdout only
+// holds the line printed by printNameAndURL. The returned func restores the output.
+func (opts *WorkloadOptions) redirectOutput(c *cli.Config) (io.Writer, func()) {
+	stdout := c.Stdout
+	if opts.Output != OutputFormatNameAndURL {
+		return stdout, func() {}
+	}
+	c.Stdout = c.Stderr
+	return stdout, func() { c.Stdout = stdout }
+}
+
+// printNameAndURL prints "NAME URL" on stdout once the workload is ready with --output name-and-url, the
+// URL is the one of the Knative service of the workload
+func (opts *WorkloadOptions) printNameAndURL(ctx context.Context, c *cli.Config, stdout io.Writer, workload *cartov1alpha1.Workload) error {
+	if opts.Output != OutputFormatNameAndURL {
+		return nil
+	}
+	url := getWorkloadURL(ctx, c, workload)
+	if url == "" {
+		err := fmt.Errorf("workload %q has no URL", workload.Name)
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+		return cli.SilenceError(err)
+	}
+	fmt.Fprintf(stdout, "%s %s\n", workload.Name, url)
+	return nil
+}
+
+// Verify runs the smoke checks configured by flags or workload annotations once the workload is ready
+func (opts *WorkloadOptions) Verify(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) error {
+	if _, err := verifyWorkload(ctx, c, workload, opts.VerifyURL, opts.VerifyCommand, opts.TrustVerifyCmd); err != nil {
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+		return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeVerifyFailed))
+	}
+	return nil
+}
 
-	f, err := os.Open(opts.FilePath)
-	in = f
-	if f == nil && opts.FilePath == "-" {
-		in = input
-	} else if err != nil {
-		return fmt.Errorf("unable to open file %q: %w", opts.FilePath, err)
+func (opts *WorkloadOptions) LoadInputWorkload(ctx context.Context, input io.Reader, workload *cartov1alpha1.Workload) error {
+	content, err := opts.readInputFile(ctx, input)
+	if err != nil {
+		return err
 	}
-	defer f.Close()
+	return opts.loadWorkloadContent(content, workload)
+}
 
-	if err := workload.Load(in); err != nil {
+// loadWorkloadContent decodes the content of --file into the workload
+func (opts *WorkloadOptions) loadWorkloadContent(content []byte, workload *cartov1alpha1.Workload) error {
+	if err := workload.Load(bytes.NewReader(content)); err != nil {
 		return fmt.Errorf("unable to load file %q: %w", opts.FilePath, err)
 	}
 	return nil
 }
 
+// readInputFile returns the content of --file, downloaded from a URL or read from stdin when set
+// to "-", once checked against --file-sha256 and --verify-signature and rendered with --values and --set
+func (opts *WorkloadOptions) readInputFile(ctx context.Context, input io.Reader) ([]byte, error) {
+	var in io.Reader
+
+	if isFileURL(opts.FilePath) {
+		content, err := downloadFile(ctx, opts.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to download file %q: %w", opts.FilePath, err)
+		}
+		in = bytes.NewReader(content)
+	} else {
+		f, err := os.Open(opts.FilePath)
+		in = f
+		if f == nil && opts.FilePath == "-" {
+			in = input
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to open file %q: %w", opts.FilePath, err)
+		}
+		defer f.Close()
+	}
+
+	content, err := io.ReadAll(in)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file %q: %w", opts.FilePath, err)
+	}
+	if opts.FileSHA256 != "" {
+		sum := sha256.Sum256(content)
+		if digest := hex.EncodeToString(sum[:]); digest != opts.FileSHA256 {
+			return nil, fmt.Errorf("file %q has sha256 %s, expected %s", opts.FilePath, digest, opts.FileSHA256)
+		}
+	}
+	if opts.VerifySignature != "" {
+		if err := opts.verifyFileSignature(ctx, content); err != nil {
+			return nil, err
+		}
+	}
+	// the digest and the signature are of the template, the values are rendered once it is trusted
+	if templating(opts.ValuesFiles, opts.Set) {
+		values, err := loadTemplateValues(opts.ValuesFiles, opts.Set)
+		if err != nil {
+			return nil, err
+		}
+		return renderWorkloadTemplate(opts.FilePath, content, values)
+	}
+	return content, nil
+}
+
+func isFileURL(path string) bool {
+	return hasScheme(path, "https")
+}
+
+// hasScheme tells whether the path is a URL of the scheme, which is not case sensitive
+func hasScheme(path, scheme string) bool {
+	u, err := url.Parse(path)
+	return err == nil && strings.EqualFold(u.Scheme, scheme)
+}
+
+func downloadFile(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := fileHTTPClient
+	if transport := retrieveFileTransport(ctx); transport != nil {
+		client = &http.Client{Timeout: fileHTTPClient.Timeout, Transport: transport}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %d, expected %d", url, resp.StatusCode, http.StatusOK)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// LoadMetadataFiles adds the labels and annotations read from --label-file and --annotation-file ahead
+// of the ones set with --label and --annotation, so values from the flags take precedence
+func (opts *WorkloadOptions) LoadMetadataFiles() error {
+	if opts.LabelFile != "" {
+		labels, err := loadKeyValueFile(opts.LabelFile)
+		if err != nil {
+			return err
+		}
+		opts.Labels = append(labels, opts.Labels...)
+	}
+	if opts.AnnotationFile != "" {
+		annotations, err := loadKeyValueFile(opts.AnnotationFile)
+		if err != nil {
+			return err
+		}
+		opts.Annotations = append(annotations, opts.Annotations...)
+	}
+	return nil
+}
+
+// LoadParamFiles adds the params read from --param-file ahead of the ones set with --param-yaml, so values
+// from --param-yaml take precedence
+func (opts *WorkloadOptions) LoadParamFiles() error {
+	params := []string{}
+	for _, p := range opts.ParamFiles {
+		kv := parsers.DeletableKeyValue(p)
+		content, err := os.ReadFile(kv[1])
+		if err != nil {
+			return fmt.Errorf("unable to open file %q: %w", kv[1], err)
+		}
+		if _, err := parsers.JsonYamlToObject(string(content)); err != nil {
+			return fmt.Errorf("unable to load file %q: %w", kv[1], err)
+		}
+		params = append(params, fmt.Sprintf("%s=%s", kv[0], content))
+	}
+	opts.ParamsYaml = append(params, opts.ParamsYaml...)
+	return nil
+}
+
+// LoadBuildEnvFile adds the build environment variables read from --build-env-file ahead of the ones set
+// with --build-env, so values from --build-env take precedence
+func (opts *WorkloadOptions) LoadBuildEnvFile() error {
+	if opts.BuildEnvFile == "" {
+		return nil
+	}
+	env, err := loadKeyValueFile(opts.BuildEnvFile)
+	if err != nil {
+		return err
+	}
+	opts.BuildEnv = append(env, opts.BuildEnv...)
+	return nil
+}
+
+func loadKeyValueFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file %q: %w", path, err)
+	}
+	kvs, err := parsers.KeyValueFile(content, filepath.Ext(path) == ".properties")
+	if err != nil {
+		return nil, fmt.Errorf("unable to load file %q: %w", path, err)
+	}
+	return kvs, nil
+}
+
 func (opts *WorkloadOptions) DefineFlags(ctx context.Context, c *cli.Config, cmd *cobra.Command) {
 	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
-	cmd.Flags().StringVarP(&opts.FilePath, cli.StripDash(flags.FilePathFlagName), "f", "", "`file path` containing the description of a single workload, other flags are layered on top of this resource. Use value \"-\" to read from stdin")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVarP(&opts.FilePath, cli.StripDash(flags.FilePathFlagName), "f", "", "`file path` or https URL containing the description of a single workload, other flags are layered on top of this resource. Use value \"-\" to read from stdin")
+	cmd.Flags().StringVar(&opts.FileSHA256, cli.StripDash(flags.FileSHA256FlagName), "", "expected sha256 `digest` of the "+flags.FilePathFlagName+" content, the command fails when it does not match")
+	cmd.Flags().StringArrayVar(&opts.ValuesFiles, cli.StripDash(flags.ValuesFlagName), []string{}, "`file path` of yaml values rendered into the Go template placeholders of the "+flags.FilePathFlagName+" content as .Values, later files take precedence (flag can be used multiple times)")
+	cmd.Flags().StringArrayVar(&opts.Set, cli.StripDash(flags.SetFlagName), []string{}, "value rendered into the Go template placeholders of the "+flags.FilePathFlagName+" content represented as a `\"key=value\" pair`, a dotted key sets a nested value, taking precedence over "+flags.ValuesFlagName+" (flag can be used multiple times)")
+	cmd.Flags().StringVar(&opts.VerifySignature, cli.StripDash(flags.VerifySignatureFlagName), "", "`file path` or https URL of a detached GPG or cosign signature of the "+flags.FilePathFlagName+" content, the command fails when it does not verify with "+flags.SignatureKeyFlagName)
+	cmd.Flags().StringVar(&opts.SignatureKey, cli.StripDash(flags.SignatureKeyFlagName), "", "`file path` of the armored GPG public key or PEM public key the "+flags.VerifySignatureFlagName+" signature is checked with")
 	cmd.Flags().StringVar(&opts.App, cli.StripDash(flags.AppFlagName), "", "application `name` the workload is a part of")
 	cmd.Flags().StringVar(&opts.Type, cli.StripDash(flags.TypeFlagName), "", "distinguish workload `type`")
-	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.TypeFlagName), func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"web"}, cobra.ShellCompDirectiveNoFileComp
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.TypeFlagName), completion.SuggestWorkloadTypes(ctx, c))
+	cmd.Flags().BoolVar(&opts.ValidateType, cli.StripDash(flags.ValidateTypeFlagName), false, "fail when no cluster supply chain selects the workload type, listing the supported types")
+	cmd.Flags().StringVar(&opts.Visibility, cli.StripDash(flags.VisibilityFlagName), "", "`visibility` of the Knative service of a web workload, \"cluster-local\" to only reach it from inside the cluster or \"public\" to expose it")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.VisibilityFlagName), func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{apis.KnativeVisibilityClusterLocal, VisibilityPublic}, cobra.ShellCompDirectiveNoFileComp
 	})
-	cmd.Flags().StringSliceVar(&opts.Labels, cli.StripDash(flags.LabelFlagName), []string{}, "label is represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
+	cmd.Flags().StringSliceVarP(&opts.Labels, cli.StripDash(flags.LabelFlagName), "l", []string{}, "label is represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
 	cmd.Flags().StringSliceVar(&opts.Annotations, cli.StripDash(flags.AnnotationFlagName), []string{}, "annotation is represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
+	cmd.Flags().StringSliceVar(&opts.PodAnnotations, cli.StripDash(flags.PodAnnotationFlagName), []string{}, "annotation of the pod template of the workload, for sidecar injectors and other controllers watching pods, represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
+	cmd.Flags().StringVar(&opts.LabelFile, cli.StripDash(flags.LabelFileFlagName), "", "`file path` to a YAML, JSON or .properties file with labels to add to the workload, values from "+flags.LabelFlagName+" take precedence")
+	cmd.MarkFlagFilename(cli.StripDash(flags.LabelFileFlagName), ".yaml", ".yml", ".json", ".properties")
+	cmd.Flags().StringVar(&opts.AnnotationFile, cli.StripDash(flags.AnnotationFileFlagName), "", "`file path` to a YAML, JSON or .properties file with annotations to add to the workload, values from "+flags.AnnotationFlagName+" take precedence")
+	cmd.MarkFlagFilename(cli.StripDash(flags.AnnotationFileFlagName), ".yaml", ".yml", ".json", ".properties")
 	cmd.Flags().StringArrayVar(&opts.Params, cli.StripDash(flags.ParamFlagName), []string{}, "additional parameters represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
+	cmd.Flags().StringArrayVar(&opts.ParamsInt, cli.StripDash(flags.ParamIntFlagName), []string{}, "parameter with an integer value, set as a JSON number instead of a string, represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
+	cmd.Flags().StringArrayVar(&opts.ParamsBool, cli.StripDash(flags.ParamBoolFlagName), []string{}, "parameter with a boolean value, set as a JSON boolean instead of a string, represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
 	cmd.Flags().StringArrayVar(&opts.ParamsYaml, cli.StripDash(flags.ParamYamlFlagName), []string{}, "specify nested parameters using YAML or JSON formatted values represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
+	cmd.Flags().StringArrayVar(&opts.ParamFiles, cli.StripDash(flags.ParamFileFlagName), []string{}, "specify nested parameters from YAML or JSON files represented as a `\"key=file path\" pair`, values from "+flags.ParamYamlFlagName+" take precedence (flag can be used multiple times)")
 	cmd.Flags().BoolVar(&opts.Debug, cli.StripDash(flags.DebugFlagName), false, "put the workload in debug mode ("+flags.DebugFlagName+"=false to disable)")
 	cmd.Flags().BoolVar(&opts.LiveUpdate, cli.StripDash(flags.LiveUpdateFlagName), false, "put the workload in live update mode ("+flags.LiveUpdateFlagName+"=false to disable)")
 	cmd.Flags().StringVar(&opts.GitRepo, cli.StripDash(flags.GitRepoFlagName), "", "git `url` to remote source code")
 	cmd.Flags().StringVar(&opts.GitBranch, cli.StripDash(flags.GitBranchFlagName), "", "`branch` within the git repo to checkout")
 	cmd.Flags().StringVar(&opts.GitCommit, cli.StripDash(flags.GitCommitFlagName), "", "commit `SHA` within the git repo to checkout")
 	cmd.Flags().StringVar(&opts.GitTag, cli.StripDash(flags.GitTagFlagName), "", "`tag` within the git repo to checkout")
+	cmd.Flags().IntVar(&opts.GitPR, cli.StripDash(flags.GitPRFlagName), 0, "`number` of the GitHub pull request or GitLab merge request whose head branch is checked out, resolved with the API of the provider of the git repo")
+	cmd.Flags().BoolVar(&opts.GitPRLabel, cli.StripDash(flags.GitPRLabelFlagName), false, "label the workload with the number of the pull request of "+flags.GitPRFlagName+" for later cleanup")
 	cmd.Flags().StringVarP(&opts.SourceImage, cli.StripDash(flags.SourceImageFlagName), "s", "", "destination `image` repository where source code is staged before being built")
+	cmd.Flags().StringVar(&opts.SourceImagePull, cli.StripDash(flags.SourceImagePullFlagName), "", "use the source image already published by another pipeline, checking it exists in the registry, with `mode` \"digest\" to pin it to the digest its tag points to, one of "+strings.Join(sourceImagePullModes, ", "))
+	cmd.Flags().Lookup(cli.StripDash(flags.SourceImagePullFlagName)).NoOptDefVal = SourceImagePullTag
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.SourceImagePullFlagName), func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return sourceImagePullModes, cobra.ShellCompDirectiveNoFileComp
+	})
 	cmd.Flags().StringVar(&opts.SubPath, cli.StripDash(flags.SubPathFlagName), "", "relative `path` inside the repo or image to treat as application root (to unset, pass empty string \"\")")
 	cmd.Flags().StringVar(&opts.LocalPath, cli.StripDash(flags.LocalPathFlagName), "", "`path` to a directory, .zip, .jar or .war file containing workload source code")
 	cmd.MarkFlagDirname(cli.StripDash(flags.LocalPathFlagName))
+	cmd.Flags().BoolVar(&opts.ForcePush, cli.StripDash(flags.ForcePushFlagName), false, "publish the source of "+flags.LocalPathFlagName+" even when the source image already holds the same content")
+	cmd.Flags().StringVar(&opts.MaxSourceSize, cli.StripDash(flags.MaxSourceSizeFlagName), "100Mi", "warn before publishing the source of "+flags.LocalPathFlagName+" when it is larger than `size`, listing the largest files (\"0\" to disable)")
 	cmd.Flags().StringVar(&opts.Image, cli.StripDash(flags.ImageFlagName), "", "pre-built `image`, skips the source resolution and build phases of the supply chain")
+	cmd.Flags().BoolVar(&opts.ImagePin, cli.StripDash(flags.ImagePinFlagName), false, "resolve the tag of the pre-built image to the digest it points to and set the image with the digest")
 	cmd.Flags().StringArrayVar(&opts.Env, cli.StripDash(flags.EnvFlagName), []string{}, "environment variables represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
 	cmd.Flags().StringArrayVar(&opts.BuildEnv, cli.StripDash(flags.BuildEnvFlagName), []string{}, "build environment variables represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
+	cmd.Flags().StringVar(&opts.BuildEnvFile, cli.StripDash(flags.BuildEnvFileFlagName), "", "`file path` to a YAML, JSON or .properties file with build environment variables, values from "+flags.BuildEnvFlagName+" take precedence")
+	cmd.MarkFlagFilename(cli.StripDash(flags.BuildEnvFileFlagName), ".yaml", ".yml", ".json", ".properties")
 	cmd.Flags().StringArrayVar(&opts.ServiceRefs, cli.StripDash(flags.ServiceRefFlagName), []string{}, "`object reference` for a service to bind to the workload \"service-ref-name=apiVersion:kind:service-binding-name\" (\"service-ref-name-\" to remove, flag can be used multiple times)")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.ServiceRefFlagName), completion.SuggestServiceRefs(ctx, c))
+	cmd.Flags().StringArrayVar(&opts.ServiceRefSecrets, cli.StripDash(flags.ServiceRefSecretFlagName), []string{}, "`secret` in the workload namespace to bind to the workload as a service \"service-ref-name=secret-name\" (\"service-ref-name-\" to remove, flag can be used multiple times)")
+	cmd.Flags().StringArrayVar(&opts.ImagePullSecrets, cli.StripDash(flags.ImagePullSecretFlagName), []string{}, "`secret` in the workload namespace holding the registry credentials the pods of the workload pull their images with (\"secret-\" to remove, flag can be used multiple times)")
 	cmd.Flags().StringVar(&opts.ServiceAccountName, cli.StripDash(flags.ServiceAccountFlagName), "", "name of service account permitted to create resources submitted by the supply chain (to unset, pass empty string \"\")")
+	cmd.Flags().StringArrayVar(&opts.Limits, cli.StripDash(flags.LimitFlagName), []string{}, "the maximum amount of a resource allowed, such as an extended resource, represented as a `\"name=quantity\" pair` like \"nvidia.com/gpu=1\" (\"name-\" to remove, flag can be used multiple times)")
 	cmd.Flags().StringVar(&opts.LimitCPU, cli.StripDash(flags.LimitCPUFlagName), "", "the maximum amount of cpu allowed, in CPU `cores` (500m = .5 cores)")
 	cmd.Flags().StringVar(&opts.LimitMemory, cli.StripDash(flags.LimitMemoryFlagName), "", "the maximum amount of memory allowed, in `bytes` (500Mi = 500MiB = 500 * 1024 * 1024)")
 	cmd.Flags().StringVar(&opts.MavenArtifact, cli.StripDash(flags.MavenArtifactFlagName), "", "name of maven artifact")
 	cmd.Flags().StringVar(&opts.MavenGroup, cli.StripDash(flags.MavenGroupFlagName), "", "maven project to pull artifact from")
 	cmd.Flags().StringVar(&opts.MavenVersion, cli.StripDash(flags.MavenVersionFlagName), "", "version number of maven artifact")
 	cmd.Flags().StringVar(&opts.MavenType, cli.StripDash(flags.MavenTypeFlagName), "", "maven packaging type, defaults to jar")
+	cmd.Flags().StringVar(&opts.Builder, cli.StripDash(flags.BuilderFlagName), "", "`name` of the ClusterBuilder building the source of the workload (to unset, pass empty string \"\")")
+	cmd.Flags().StringVar(&opts.BuildCacheImage, cli.StripDash(flags.BuildCacheImageFlagName), "", "`image` the build caches its layers to between builds of the source (to unset, pass empty string \"\")")
+	cmd.Flags().StringVar(&opts.RunImage, cli.StripDash(flags.RunImageFlagName), "", "run `image` the app image built from the source is based on (to unset, pass empty string \"\")")
 	cmd.Flags().StringArrayVar(&opts.CACertPaths, cli.StripDash(flags.RegistryCertFlagName), []string{}, "file path to CA certificate used to authenticate with registry, flag can be used multiple times")
+	cmd.Flags().StringArrayVar(&opts.RegistryCAs, cli.StripDash(flags.RegistryCAFlagName), []string{}, "CA certificate used to authenticate with one registry only, represented as a `\"host=path\" pair` (flag can be used multiple times)")
 	cmd.Flags().StringVar(&opts.RegistryPassword, cli.StripDash(flags.RegistryPasswordFlagName), "", "username for authenticating with registry")
 	cmd.Flags().StringVar(&opts.RegistryUsername, cli.StripDash(flags.RegistryUsernameFlagName), "", "password for authenticating with registry")
 	cmd.Flags().StringVar(&opts.RegistryToken, cli.StripDash(flags.RegistryTokenFlagName), "", "token for authenticating with registry")
+	cmd.Flags().StringVar(&opts.RegistryProxy, cli.StripDash(flags.RegistryProxyFlagName), "", "`url` of the proxy the registry is reached through, taking precedence over HTTPS_PROXY, hosts of NO_PROXY are reached directly")
+	cmd.Flags().StringArrayVar(&opts.Requests, cli.StripDash(flags.RequestFlagName), []string{}, "the minimum amount of a resource required, such as an extended resource, represented as a `\"name=quantity\" pair` like \"nvidia.com/gpu=1\" (\"name-\" to remove, flag can be used multiple times)")
 	cmd.Flags().StringVar(&opts.RequestCPU, cli.StripDash(flags.RequestCPUFlagName), "", "the minimum amount of cpu required, in CPU `cores` (500m = .5 cores)")
 	cmd.Flags().StringVar(&opts.RequestMemory, cli.StripDash(flags.RequestMemoryFlagName), "", "the minimum amount of memory required, in `bytes` (500Mi = 500MiB = 500 * 1024 * 1024)")
 	cmd.Flags().BoolVar(&opts.Wait, cli.StripDash(flags.WaitFlagName), false, "waits for workload to become ready")
+	cmd.Flags().StringArrayVar(&opts.WaitFor, cli.StripDash(flags.WaitForFlagName), []string{}, "waits for workload to meet a `condition` instead of becoming ready, as \"condition=[resource/]type[=status]\" where the status defaults to True (flag can be used multiple times, all conditions must be met)")
 	cmd.Flags().DurationVar(&opts.WaitTimeout, cli.StripDash(flags.WaitTimeoutFlagName), 10*time.Minute, "timeout for workload to become ready when waiting")
 	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.WaitTimeoutFlagName), completion.SuggestDurationUnits(ctx, completion.CommonDurationUnits))
 	cmd.Flags().BoolVar(&opts.Tail, cli.StripDash(flags.TailFlagName), false, "show logs while waiting for workload to become ready")
 	cmd.Flags().BoolVar(&opts.TailTimestamps, cli.StripDash(flags.TailTimestampFlagName), false, "show logs and add timestamp to each log line while waiting for workload to become ready")
+	cmd.Flags().StringVar(&opts.VerifyURL, cli.StripDash(flags.VerifyURLFlagName), "", "`url` that must answer an HTTP GET with 200 once the workload is ready, a path is resolved against the workload URL")
+	cmd.Flags().StringVar(&opts.VerifyCommand, cli.StripDash(flags.VerifyCmdFlagName), "", "shell `command` that must exit successfully once the workload is ready")
+	cmd.Flags().BoolVar(&opts.TrustVerifyCmd, cli.StripDash(flags.TrustVerifyCmdFlagName), false, fmt.Sprintf("run the shell command of the %q annotation of the workload once it is ready, when %s is not set", apis.VerifyCommandAnnotationName, flags.VerifyCmdFlagName))
 	cmd.MarkFlagFilename(cli.StripDash(flags.FilePathFlagName), ".yaml", ".yml")
+	cmd.MarkFlagFilename(cli.StripDash(flags.ValuesFlagName), ".yaml", ".yml")
 	cmd.Flags().BoolVar(&opts.DryRun, cli.StripDash(flags.DryRunFlagName), false, "print kubernetes resources to stdout rather than apply them to the cluster, messages normally on stdout will be sent to stderr")
+	cmd.Flags().StringVar(&opts.OutputFormat, cli.StripDash(flags.OutputFormatFlagName), "", "`format` of the workload printed by "+flags.DryRunFlagName+", \"kustomize-patch\" and \"ytt\" print it as a patch for the manifests of a GitOps repository, one of "+strings.Join(dryRunFormats, ", ")+" (default \"yaml\")")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.OutputFormatFlagName), func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return dryRunFormats, cobra.ShellCompDirectiveNoFileComp
+	})
 	cmd.Flags().BoolVarP(&opts.Yes, cli.StripDash(flags.YesFlagName), "y", false, "accept all prompts")
+	cmd.Flags().BoolVar(&opts.Force, cli.StripDash(flags.ForceFlagName), false, "allow changing labels and annotations with a prefix protected by the plugin config")
+	cmd.Flags().BoolVar(&opts.AllowProtected, cli.StripDash(flags.AllowProtectedFlagName), false, "allow changing a workload in a namespace protected by the plugin config")
+	cmd.Flags().StringVarP(&opts.Output, cli.StripDash(flags.OutputFlagName), "o", "", "output machine readable progress events on stderr, or only the name and URL of the workload once ready on stdout. Supported formats: \"json\", \"name-and-url\"")
+	cmd.Flags().StringVar(&opts.FieldManager, cli.StripDash(flags.FieldManagerFlagName), DefaultFieldManager, "`name` of the field manager owning the fields of the workload set by the command, fields owned by other managers are left alone")
+	cmd.Flags().BoolVar(&opts.ForceConflicts, cli.StripDash(flags.ForceConflictsFlagName), false, "take over the fields of the workload owned by other field managers that the command changes or removes, instead of failing with a conflict")
+	cmd.Flags().IntVar(&opts.ConflictRetries, cli.StripDash(flags.ConflictRetriesFlagName), 3, "maximum `number` of times the changes are applied again to the latest version of the workload when the update fails with a conflict")
+	auditFlag(cmd, &opts.Audit)
+	cmd.Flags().StringVar(&opts.DiffTool, cli.StripDash(flags.DiffToolFlagName), "", "external diff `command` to show the changes to the workload with when the output is a terminal, it is run with the current and the new workload files as its last arguments")
 }
 
+// DefineCreateNamespaceFlags defines the flags of the commands creating workloads to create their namespace
+// when it is missing
+func (opts *WorkloadOptions) DefineCreateNamespaceFlags(ctx context.Context, c *cli.Config, cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&opts.CreateNamespace, cli.StripDash(flags.CreateNamespaceFlagName), false, "create the namespace of the workload when it does not exist")
+	cmd.Flags().StringArrayVar(&opts.NamespaceLabels, cli.StripDash(flags.NamespaceLabelFlagName), []string{}, "label of the namespace created with "+flags.CreateNamespaceFlagName+", represented as a `\"key=value\" pair` (flag can be used multiple times)")
+}
+
+// DefineEnvVars sets the flags not set on the command line, before the command runs, from their
+// TANZU_APPS_* env var unless it is excluded by the plugin config, then from the defaults of the plugin
+// config. The flags set this way are not marked as changed.
 func (opts *WorkloadOptions) DefineEnvVars(ctx context.Context, c *cli.Config, cmd *cobra.Command) {
+	prior := cmd.PreRunE
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := applyFlagDefaults(c, cmd); err != nil {
+			return err
+		}
+		if prior != nil {
+			return prior(cmd, args)
+		}
+		return nil
+	}
+}
+
+func applyFlagDefaults(c *cli.Config, cmd *cobra.Command) error {
+	excluded := sets.NewString(c.Viper.GetStringSlice(cli.ExcludeEnvVarsConfigKey)...)
 	v := viper.New()
 	v.SetEnvPrefix(flags.TanzuAppsEnvVarPrefix)
+	var err error
 	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed || err != nil {
+			return
+		}
 		ev := flags.FlagToEnvVar(f.Name)
-		if _, ok := flags.EnvVarAllowedList[ev]; ok {
+		if _, ok := flags.EnvVarAllowedList[ev]; ok && !excluded.Has(ev) {
 			v.BindEnv(f.Name, ev)
 		}
+		// the value is set without marking the flag as changed, the defaults are not flags of the command line
+		if v.IsSet(f.Name) {
+			f.Value.Set(fmt.Sprintf("%v", v.Get(f.Name)))
+			return
+		}
 
-		if !f.Changed && v.IsSet(f.Name) {
-			val := v.Get(f.Name)
-			cmd.Flags().Set(f.Name, fmt.Sprintf("%v", val))
+		if _, ok := flags.DefaultsAllowedList["--"+f.Name]; !ok {
+			return
+		}
+		value, ok := c.FlagDefault(f.Name)
+		if !ok {
+			return
+		}
+		values, ok := value.([]interface{})
+		if !ok {
+			values = []interface{}{value}
+		}
+		for _, value := range values {
+			if setErr := f.Value.Set(fmt.Sprintf("%v", value)); setErr != nil {
+				err = fmt.Errorf("invalid default %q for %s in the plugin config: %w", value, "--"+f.Name, setErr)
+				return
+			}
 		}
 	})
+	return err
 }
@@ -17,20 +17,29 @@ limitations under the License.
 package commands
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
 	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/logs"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/retry"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/wait"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/watch"
@@ -41,6 +50,15 @@ import (
 
 type WorkloadApplyOptions struct {
 	WorkloadOptions
+
+	Offline              bool
+	IgnoreUnknown        bool
+	FromImageScan        bool
+	ReplaceServiceClaims bool
+	Strict               bool
+
+	RetryOn      []string
+	RetryBackoff time.Duration
 }
 
 var (
@@ -50,27 +68,177 @@ var (
 )
 
 func (opts *WorkloadApplyOptions) Validate(ctx context.Context) validation.FieldErrors {
-	return opts.WorkloadOptions.Validate(ctx)
+	errs := opts.WorkloadOptions.Validate(ctx)
+
+	// offline mode never talks to the cluster, so it can only render the workload
+	if opts.Offline {
+		if !opts.DryRun {
+			errs = errs.Also(validation.ErrMissingField(flags.DryRunFlagName))
+		}
+		// these flags resolve the workload with the API of the git provider or of the registry
+		if opts.GitPR != 0 {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.GitPRFlagName, flags.OfflineFlagName))
+		}
+		if opts.ImagePin {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.ImagePinFlagName, flags.OfflineFlagName))
+		}
+		if opts.SourceImagePull != "" {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.SourceImagePullFlagName, flags.OfflineFlagName))
+		}
+		if opts.FromImageScan {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.FromImageScanFlagName, flags.OfflineFlagName))
+		}
+	}
+	// the service claims can only be replaced by the claims of a file
+	if opts.ReplaceServiceClaims && opts.FilePath == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.FilePathFlagName))
+	}
+	// only the documents of a file can be skipped
+	if opts.IgnoreUnknown && opts.FilePath == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.FilePathFlagName))
+	}
+	// the file is checked against the schema of the cluster
+	if opts.Strict {
+		if opts.FilePath == "" {
+			errs = errs.Also(validation.ErrMissingField(flags.FilePathFlagName))
+		}
+		if opts.Offline {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.StrictFlagName, flags.OfflineFlagName))
+		}
+	}
+
+	// conflicts are retried by --conflict-retries, on the latest version of the workload
+	for _, class := range opts.RetryOn {
+		errs = errs.Also(validation.Enum(class, flags.RetryOnFlagName, retry.TransientErrorClasses))
+	}
+	if opts.RetryBackoff < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(opts.RetryBackoff, flags.RetryBackoffFlagName))
+	}
+
+	return errs
 }
 
 func (opts *WorkloadApplyOptions) Exec(ctx context.Context, c *cli.Config) error {
-	var createError error
-	var updateError error
-	okToCreate := false
-	okToUpdate := false
+	stdout, restoreOutput := opts.redirectOutput(c)
+	defer restoreOutput()
 
-	fileWorkload := &cartov1alpha1.Workload{}
-	if opts.FilePath != "" {
-		if err := opts.WorkloadOptions.LoadInputWorkload(c.Stdin, fileWorkload); err != nil {
+	if err := opts.LoadMetadataFiles(); err != nil {
+		return err
+	}
+	if err := opts.LoadParamFiles(); err != nil {
+		return err
+	}
+	if err := opts.LoadBuildEnvFile(); err != nil {
+		return err
+	}
+	if err := opts.ValidateProtectedPrefixes(c).ToAggregate(); err != nil {
+		return err
+	}
+
+	if opts.FilePath == "" {
+		return opts.applyAndWait(ctx, c, stdout, &cartov1alpha1.Workload{})
+	}
+
+	content, err := opts.readInputFile(ctx, c.Stdin)
+	if err != nil {
+		return err
+	}
+	documents, err := opts.workloadDocuments(c, content)
+	if err != nil {
+		return err
+	}
+	if len(documents) > 1 && opts.Name != "" {
+		return fmt.Errorf("a workload name cannot be set when file %q contains multiple workloads", opts.FilePath)
+	}
+	// every document is loaded and checked before any workload is applied
+	fileWorkloads := make([]*cartov1alpha1.Workload, len(documents))
+	for i, document := range documents {
+		fileWorkloads[i] = &cartov1alpha1.Workload{}
+		if err := opts.loadWorkloadContent(document, fileWorkloads[i]); err != nil {
 			return err
 		}
+		if !opts.Offline {
+			if err := opts.checkFileSchema(ctx, c, document); err != nil {
+				return err
+			}
+		}
+	}
 
+	name, namespace := opts.Name, opts.Namespace
+	// the source prompted for a workload without one only applies to that workload
+	gitRepo, gitBranch, image := opts.GitRepo, opts.GitBranch, opts.Image
+	for _, fileWorkload := range fileWorkloads {
+		opts.Name, opts.Namespace = name, namespace
+		opts.GitRepo, opts.GitBranch, opts.Image = gitRepo, gitBranch, image
 		if opts.Name == "" {
 			opts.Name = fileWorkload.Name
 		}
 		if fileWorkload.Namespace != "" && !cli.CommandFromContext(ctx).Flags().Changed(cli.StripDash(flags.NamespaceFlagName)) {
 			opts.Namespace = fileWorkload.Namespace
 		}
+		if err := opts.applyAndWait(ctx, c, stdout, fileWorkload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// workloadDocuments splits the content of --file into its YAML documents, so each workload is applied
+// in turn. Documents of another kind fail the apply, unless --ignore-unknown skips them.
+func (opts *WorkloadApplyOptions) workloadDocuments(c *cli.Config, content []byte) ([][]byte, error) {
+	apiVersion, kind := cartov1alpha1.SchemeGroupVersion.Identifier(), cartov1alpha1.WorkloadKind
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+	documents := [][]byte{}
+	skipped := 0
+	for i := 1; ; {
+		document, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to load file %q: %w", opts.FilePath, err)
+		}
+		raw := map[string]interface{}{}
+		if err := yaml.Unmarshal(document, &raw); err != nil {
+			return nil, fmt.Errorf("unable to load file %q: %w", opts.FilePath, err)
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		documentAPIVersion, _ := raw["apiVersion"].(string)
+		documentKind, _ := raw["kind"].(string)
+		if documentAPIVersion != apiVersion || documentKind != kind {
+			if !opts.IgnoreUnknown {
+				return nil, fmt.Errorf("document %d of file %q has API Version %q and Kind %q, expected API Version %q and Kind %q (use %s to skip it)", i, opts.FilePath, documentAPIVersion, documentKind, apiVersion, kind, flags.IgnoreUnknownFlagName)
+			}
+			c.Infof("Skipping document %d with API Version %q and Kind %q\n", i, documentAPIVersion, documentKind)
+			skipped++
+		} else {
+			documents = append(documents, document)
+		}
+		i++
+	}
+	if len(documents) == 0 {
+		if skipped > 0 {
+			return nil, fmt.Errorf("file %q does not contain a workload", opts.FilePath)
+		}
+		// an empty file fails to load the same way as a single document
+		return [][]byte{content}, nil
+	}
+	return documents, nil
+}
+
+// applyAndWait applies the workload described by the file and flags, then waits for it and tails its
+// logs when requested
+func (opts *WorkloadApplyOptions) applyAndWait(ctx context.Context, c *cli.Config, stdout io.Writer, fileWorkload *cartov1alpha1.Workload) error {
+	okToCreate := false
+	okToUpdate := false
+
+	// ask for the name of a workload in a file that does not have one, rather than failing
+	if opts.Name == "" && opts.interactive(ctx, c) {
+		if err := opts.promptName(c); err != nil {
+			c.Infof("Skipping workload apply: %s\n", err)
+			return cli.SilenceError(err)
+		}
 	}
 
 	// validate that a namespace and name are provided
@@ -81,44 +249,104 @@ func (opts *WorkloadApplyOptions) Exec(ctx context.Context, c *cli.Config) error
 	if opts.Namespace == "" {
 		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
 	}
+	errs = errs.Also(validateProtectedNamespace(c, opts.Namespace, opts.AllowProtected))
 	if err := errs.ToAggregate(); err != nil {
 		return err
 	}
 
-	workload := &cartov1alpha1.Workload{}
-	var currentWorkload *cartov1alpha1.Workload
-	err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, workload)
-	if err == nil {
-		currentWorkload = workload.DeepCopy()
-	} else {
-		if !apierrs.IsNotFound(err) {
-			return err
-		}
-		if apierrs.IsNotFound(err) {
-			if nsErr := validateNamespace(ctx, c, opts.Namespace); nsErr != nil {
-				return nsErr
-			}
-		}
+	var workload *cartov1alpha1.Workload
+	applyCtx := ctx
+	if len(opts.RetryOn) != 0 {
+		// the apply is retried as a whole, its requests are not retried on their own
+		applyCtx = retry.StashDisabled(ctx)
+	}
+	policy := retry.Policy{On: opts.RetryOn, Retries: c.Retries, Backoff: opts.RetryBackoff}
+	err := policy.Do(ctx, func() error {
+		var err error
+		workload, okToCreate, okToUpdate, err = opts.applyWorkload(applyCtx, c, fileWorkload)
+		return err
+	}, func(attempt int, class string, err error) {
+		c.Infof("Retrying in %s after %s error (%d/%d): %s\n", opts.RetryBackoff, class, attempt, c.Retries, err)
+	})
+	if err != nil {
+		return err
 	}
 
-	workload.Name = opts.Name
-	workload.Namespace = opts.Namespace
-	if opts.FilePath != "" {
-		var serviceAccountCopy string
-		// avoid passing a nil pointer to MergeServiceAccountName func
-		if fileWorkload.Spec.ServiceAccountName != nil {
-			serviceAccountCopy = *fileWorkload.Spec.ServiceAccountName
+	if okToCreate || okToUpdate {
+		c.Printf("\n")
+		DisplayCommandNextSteps(c, workload)
+		c.Printf("\n")
+	}
+
+	anyTail := opts.Tail || opts.TailTimestamps
+	if (okToCreate || okToUpdate) && (opts.waiting() || anyTail) {
+		c.Infof("Waiting for workload %q to %s...\n", opts.Name, opts.waitGoal())
+
+		ctx := withPollBackoff(ctx, c)
+		latency := newLatencyRecorder(ctx, workload)
+		progress := opts.newConditionProgress(c, workload)
+		workers := []wait.Worker{
+			func(ctx context.Context) error {
+				clientWithWatch, err := watch.GetWatcher(ctx, c)
+				if err != nil {
+					panic(err)
+				}
+				return wait.UntilCondition(ctx, clientWithWatch, types.NamespacedName{Name: workload.Name, Namespace: workload.Namespace}, &cartov1alpha1.WorkloadList{}, progress.Condition(latency.Condition(opts.waitCondition())))
+			},
 		}
 
-		workload.Spec.MergeServiceAccountName(serviceAccountCopy)
+		if anyTail {
+			workers = append(workers, func(ctx context.Context) error {
+				selector, err := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workload.Name))
+				if err != nil {
+					panic(err)
+				}
+				containers := []string{}
+				return logs.Tail(ctx, c, opts.Namespace, selector, containers, time.Second, logs.AllLines, opts.TailTimestamps)
+			})
+		}
+
+		if err := wait.Race(ctx, opts.WaitTimeout, workers); err != nil {
+			if err == context.DeadlineExceeded {
+				c.Printf("%s timeout after %s waiting for %q to %s\n", printer.Serrorf("Error:"), opts.WaitTimeout, workload.Name, opts.waitGoal())
+				return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeTimeout))
+			}
+			c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+			if errors.Is(err, wait.ErrConditionFailed) {
+				return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeFailedCondition))
+			}
+			return cli.SilenceError(err)
+		}
+		c.Infof("Workload %q %s\n", workload.Name, opts.waitReached())
+		opts.printLatency(c, workload, latency.Latency())
+		if err := opts.Verify(ctx, c, workload); err != nil {
+			return err
+		}
+		if err := opts.printNameAndURL(ctx, c, stdout, workload); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	workload.Merge(fileWorkload)
+// applyWorkload merges the file and flags into the current state of the workload on the cluster, then
+// creates or updates it. The whole sequence is repeated when the retry policy applies.
+func (opts *WorkloadApplyOptions) applyWorkload(ctx context.Context, c *cli.Config, fileWorkload *cartov1alpha1.Workload) (*cartov1alpha1.Workload, bool, bool, error) {
+	ctx, workload, currentWorkload, namespace, err := opts.mergeWorkload(ctx, c, fileWorkload)
+	if err != nil {
+		return nil, false, false, err
+	}
 
-	ctx = opts.ApplyOptionsToWorkload(ctx, workload)
+	// ask for the source of a new workload, the source of a local path is published to --source-image
+	if currentWorkload == nil && opts.LocalPath == "" && !workload.Spec.IsSourceFound() && opts.interactive(ctx, c) {
+		if err := opts.promptSource(c, workload); err != nil {
+			c.Infof("Skipping workload apply: %s\n", err)
+			return nil, false, false, cli.SilenceError(err)
+		}
+	}
 
 	// validate complex flag interactions with existing state
-	errs = workload.Validate()
+	errs := workload.Validate()
 	// local path requires a source image
 	if opts.LocalPath != "" && (workload.Spec.Source == nil || workload.Spec.Source.Image == "") {
 		errs = errs.Also(
@@ -128,75 +356,156 @@ func (opts *WorkloadApplyOptions) Exec(ctx context.Context, c *cli.Config) error
 	if err := errs.ToAggregate(); err != nil {
 		// show command usage before error
 		cli.CommandFromContext(ctx).SilenceUsage = false
-		return err
+		return nil, false, false, err
+	}
+
+	if !opts.Offline {
+		if err := opts.validateServiceRefs(ctx, c, workload.Namespace); err != nil {
+			return nil, false, false, err
+		}
+		if err := opts.validateImagePullSecrets(ctx, c, workload.Namespace); err != nil {
+			return nil, false, false, err
+		}
+		if err := opts.validateType(ctx, c, workload); err != nil {
+			return nil, false, false, err
+		}
+	}
+
+	if err := opts.ResolveGitPR(ctx, c, workload); err != nil {
+		return nil, false, false, err
+	}
+
+	if err := opts.PinImage(ctx, c, workload); err != nil {
+		return nil, false, false, err
+	}
+
+	if err := opts.PullSourceImage(ctx, c, workload); err != nil {
+		return nil, false, false, err
 	}
 
 	if opts.DryRun {
-		cli.DryRunResource(ctx, workload, workload.GetGroupVersionKind())
-		return nil
+		return workload, false, false, opts.PrintDryRun(ctx, c, namespace, workload)
 	}
 
 	// If user answers yes to survey prompt about publishing source, continue with creation or update
 	if okToPush, err := opts.PublishLocalSource(ctx, c, currentWorkload, workload); err != nil {
-		return err
+		return nil, false, false, err
 	} else if !okToPush {
-		return nil
+		return workload, false, false, nil
 	}
 
 	// If there is no workload, create a new one
 	if currentWorkload == nil {
-		okToCreate, createError = opts.Create(ctx, c, workload)
-		if createError != nil {
-			return createError
-		}
-	} else {
-		okToUpdate, updateError = opts.Update(ctx, c, currentWorkload, workload)
-		if updateError != nil {
-			return updateError
+		okToCreate, err := opts.Create(ctx, c, namespace, workload)
+		return workload, okToCreate, false, err
+	}
+	okToUpdate, err := opts.Update(ctx, c, currentWorkload, workload)
+	return workload, false, okToUpdate, err
+}
+
+// mergeWorkload merges the file and flags into the current state of the workload on the cluster, with
+// the defaults of the workload profile, of the namespace and of the image scan. The current workload
+// is nil when it does not exist, the namespace is not nil when it is to be created.
+func (opts *WorkloadApplyOptions) mergeWorkload(ctx context.Context, c *cli.Config, fileWorkload *cartov1alpha1.Workload) (context.Context, *cartov1alpha1.Workload, *cartov1alpha1.Workload, *corev1.Namespace, error) {
+	workload := &cartov1alpha1.Workload{}
+	var currentWorkload *cartov1alpha1.Workload
+	var namespace *corev1.Namespace
+	// offline dry runs render the workload purely from flags and file
+	if !opts.Offline {
+		err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, workload)
+		if err == nil {
+			currentWorkload = workload.DeepCopy()
+		} else {
+			if !apierrs.IsNotFound(err) {
+				return ctx, nil, nil, nil, err
+			}
+			if namespace, err = opts.missingNamespace(ctx, c, opts.Namespace); err != nil {
+				return ctx, nil, nil, nil, err
+			}
 		}
 	}
 
-	if okToCreate || okToUpdate {
-		c.Printf("\n")
-		DisplayCommandNextSteps(c, workload)
-		c.Printf("\n")
+	workload.Name = opts.Name
+	workload.Namespace = opts.Namespace
+	if opts.FilePath != "" {
+		var serviceAccountCopy string
+		// avoid passing a nil pointer to MergeServiceAccountName func
+		if fileWorkload.Spec.ServiceAccountName != nil {
+			serviceAccountCopy = *fileWorkload.Spec.ServiceAccountName
+		}
+
+		workload.Spec.MergeServiceAccountName(serviceAccountCopy)
 	}
 
-	anyTail := opts.Tail || opts.TailTimestamps
-	if (okToCreate || okToUpdate) && (opts.Wait || anyTail) {
-		c.Infof("Waiting for workload %q to become ready...\n", opts.Name)
+	if opts.ReplaceServiceClaims {
+		workload.ReplaceServiceClaims(fileWorkload.Spec.ServiceClaims)
+	}
+	workload.Merge(fileWorkload)
 
-		workers := []wait.Worker{
-			func(ctx context.Context) error {
-				clientWithWatch, err := watch.GetWatcher(ctx, c)
-				if err != nil {
-					panic(err)
-				}
-				return wait.UntilCondition(ctx, clientWithWatch, types.NamespacedName{Name: workload.Name, Namespace: workload.Namespace}, &cartov1alpha1.WorkloadList{}, cartov1alpha1.WorkloadReadyConditionFunc)
-			},
+	ctx = opts.ApplyOptionsToWorkload(ctx, workload)
+	ctx, err := opts.applyImageScan(ctx, c, workload)
+	if err != nil {
+		return ctx, nil, nil, nil, err
+	}
+	ctx, err = applyWorkloadProfile(ctx, c.Viper, workload)
+	if err != nil {
+		return ctx, nil, nil, nil, err
+	}
+	// offline dry runs do not read the defaults of the namespace from the cluster
+	if !opts.Offline {
+		if ctx, err = applyNamespaceDefaults(ctx, c, workload); err != nil {
+			return ctx, nil, nil, nil, err
 		}
+	}
+	return ctx, workload, currentWorkload, namespace, nil
+}
 
-		if anyTail {
-			workers = append(workers, func(ctx context.Context) error {
-				selector, err := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workload.Name))
-				if err != nil {
-					panic(err)
-				}
-				containers := []string{}
-				return logs.Tail(ctx, c, opts.Namespace, selector, containers, time.Second, opts.TailTimestamps)
-			})
-		}
+// interactive tells whether missing values can be asked for, which requires a terminal that does not
+// provide the workload file and prompts that are not disabled with --yes
+func (opts *WorkloadApplyOptions) interactive(ctx context.Context, c *cli.Config) bool {
+	return !opts.Yes && opts.FilePath != "-" && isTerminal(ctx, c.Stdin)
+}
 
-		if err := wait.Race(ctx, opts.WaitTimeout, workers); err != nil {
-			if err == context.DeadlineExceeded {
-				c.Printf("%s timeout after %s waiting for %q to become ready\n", printer.Serrorf("Error:"), opts.WaitTimeout, workload.Name)
-				return cli.SilenceError(err)
-			}
-			c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
-			return cli.SilenceError(err)
+// promptName asks for the name of the workload
+func (opts *WorkloadApplyOptions) promptName(c *cli.Config) error {
+	name := ""
+	err := survey.AskOne(&survey.Input{Message: "Workload name:"}, &name, survey.WithValidator(func(ans interface{}) error {
+		s, _ := ans.(string)
+		return validation.K8sName(s, cli.NameArgumentName).ToAggregate()
+	}), printer.WithSurveyStdio(c.Stdin, c.Stdout, c.Stderr))
+	if err != nil {
+		return err
+	}
+	opts.Name = name
+	return nil
+}
+
+// promptSource asks for the git repository of the workload, or for its image when there is no
+// repository. The answers are kept in the options so a retried apply does not ask again.
+func (opts *WorkloadApplyOptions) promptSource(c *cli.Config, workload *cartov1alpha1.Workload) error {
+	stdio := printer.WithSurveyStdio(c.Stdin, c.Stdout, c.Stderr)
+	repo := ""
+	if err := survey.AskOne(&survey.Input{Message: "Git repository URL (empty for a pre-built image):"}, &repo, stdio); err != nil {
+		return err
+	}
+	if repo != "" {
+		branch := ""
+		if err := survey.AskOne(&survey.Input{Message: "Git branch:", Default: "main"}, &branch, stdio); err != nil {
+			return err
 		}
-		c.Infof("Workload %q is ready\n", workload.Name)
+		opts.GitRepo, opts.GitBranch = repo, branch
+		workload.Spec.MergeGit(cartov1alpha1.GitSource{
+			URL: repo,
+			Ref: cartov1alpha1.GitRef{Branch: branch},
+		})
+		return nil
 	}
+	image := ""
+	if err := survey.AskOne(&survey.Input{Message: "Image:"}, &image, survey.WithValidator(survey.Required), stdio); err != nil {
+		return err
+	}
+	opts.Image = image
+	workload.Spec.MergeImage(image)
 	return nil
 }
 
@@ -209,8 +518,9 @@ func NewWorkloadApplyCommand(ctx context.Context, c *cli.Config) *cobra.Command
 	opts.LoadDefaults(c)
 
 	cmd := &cobra.Command{
-		Use:   "apply",
-		Short: "Apply configuration to a new or existing workload",
+		Use:     "apply",
+		Aliases: []string{"a"},
+		Short:   "Apply configuration to a new or existing workload",
 		Long: strings.TrimSpace(`
 Apply configuration to a new or existing workload. If the resource does not exist, it will be created.
 
@@ -219,10 +529,11 @@ Workload configuration options include:
 - runtime resource limits
 - environment variables
 - services to bind
+
+When run in a terminal, apply prompts for a workload name and, for a new workload, a source that are not
+provided with flags or --file. Use --yes to disable the prompts.
 `),
-		Example: strings.Join([]string{
-			fmt.Sprintf("%s workload apply %s workload.yaml", c.Name, flags.FilePathFlagName),
-		}, "\n"),
+		Example:           examplesFor(c, "workload apply"),
 		PreRunE:           cli.ValidateE(ctx, opts),
 		RunE:              cli.ExecE(ctx, c, opts),
 		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
@@ -235,6 +546,34 @@ Workload configuration options include:
 	// Define common flags
 	opts.DefineFlags(ctx, c, cmd)
 
+	// offline dry runs must not read the kubeconfig to default the namespace
+	prior := cmd.PreRunE
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if opts.Offline && opts.Namespace == "" {
+			opts.Namespace = metav1.NamespaceDefault
+		}
+		// ask for a missing name rather than failing validation, a file may still provide it
+		if opts.Name == "" && opts.FilePath == "" && opts.interactive(ctx, c) {
+			if err := opts.promptName(c); err != nil {
+				c.Infof("Skipping workload apply: %s\n", err)
+				return cli.SilenceError(err)
+			}
+		}
+		return prior(cmd, args)
+	}
+	cmd.Flags().StringSliceVar(&opts.RetryOn, cli.StripDash(flags.RetryOnFlagName), []string{}, fmt.Sprintf("retry the apply, up to %s times, when it fails with one of the error `classes` (%s) instead of retrying its requests, flag can be used multiple times", flags.RetriesFlagName, strings.Join(retry.TransientErrorClasses, ", ")))
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.RetryOnFlagName), func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return retry.TransientErrorClasses, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().DurationVar(&opts.RetryBackoff, cli.StripDash(flags.RetryBackoffFlagName), 5*time.Second, "time to wait between retries")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.RetryBackoffFlagName), completion.SuggestDurationUnits(ctx, completion.CommonDurationUnits))
+	opts.DefineCreateNamespaceFlags(ctx, c, cmd)
+	cmd.Flags().BoolVar(&opts.IgnoreUnknown, cli.StripDash(flags.IgnoreUnknownFlagName), false, fmt.Sprintf("skip the documents of %s that are not workloads, instead of failing", flags.FilePathFlagName))
+	cmd.Flags().BoolVar(&opts.Offline, cli.StripDash(flags.OfflineFlagName), false, fmt.Sprintf("render the workload from flags and file without contacting the cluster, requires %s", flags.DryRunFlagName))
+	cmd.Flags().BoolVar(&opts.ReplaceServiceClaims, cli.StripDash(flags.ReplaceClaimsFlagName), false, fmt.Sprintf("replace the service claims of the workload with the ones in %s, removing the claims the file does not contain", flags.FilePathFlagName))
+	cmd.Flags().BoolVar(&opts.Strict, cli.StripDash(flags.StrictFlagName), false, fmt.Sprintf("fail when %s contains fields unknown to the Workload schema of the cluster or a deprecated API version, instead of warning about them", flags.FilePathFlagName))
+	cmd.Flags().BoolVar(&opts.FromImageScan, cli.StripDash(flags.FromImageScanFlagName), false, fmt.Sprintf("prefill the part-of label, the ports param and the source annotations the workload does not set from the labels and exposed ports of the config of %s", flags.ImageFlagName))
+
 	// Bind flags to environment variables
 	opts.DefineEnvVars(ctx, c, cmd)
 
@@ -18,28 +18,43 @@ package commands_test
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	diecorev1 "dies.dev/apis/core/v1"
 	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	regv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/mock"
+	rtesting "github.com/vmware-labs/reconciler-runtime/testing"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
 	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
 	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/logs"
 	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
@@ -48,7 +63,9 @@ import (
 	watchfakes "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/watch/fake"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
 	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	diev1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/knative/serving/v1"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/source"
 )
 
 func TestWorkloadApplyOptionsValidate(t *testing.T) {
@@ -75,6 +92,180 @@ func TestWorkloadApplyOptionsValidate(t *testing.T) {
 			},
 			ExpectFieldErrors: validation.ErrInvalidArrayValue("FOO", flags.EnvFlagName, 0),
 		},
+		{
+			Name: "retry policy",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					Name:      "my-resource",
+				},
+				RetryOn:      []string{"timeout", "throttled"},
+				RetryBackoff: 10 * time.Second,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid retry policy",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					Name:      "my-resource",
+				},
+				RetryOn:      []string{"conflict", "notfound"},
+				RetryBackoff: -1 * time.Second,
+			},
+			ExpectFieldErrors: validation.EnumInvalidValue("conflict", flags.RetryOnFlagName, []string{"timeout", "throttled", "unavailable"}).Also(
+				validation.EnumInvalidValue("notfound", flags.RetryOnFlagName, []string{"timeout", "throttled", "unavailable"}),
+				validation.ErrInvalidValue(-1*time.Second, flags.RetryBackoffFlagName),
+			),
+		},
+		{
+			Name: "offline with dry run",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					Name:      "my-resource",
+					DryRun:    true,
+				},
+				Offline: true,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "offline without dry run",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					Name:      "my-resource",
+				},
+				Offline: true,
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.DryRunFlagName),
+		},
+		{
+			Name: "offline with git pr",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					Name:      "my-resource",
+					DryRun:    true,
+					GitPR:     12,
+				},
+				Offline: true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.GitPRFlagName, flags.OfflineFlagName),
+		},
+		{
+			Name: "offline with image pin",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					Name:      "my-resource",
+					DryRun:    true,
+					Image:     "ubuntu:bionic",
+					ImagePin:  true,
+				},
+				Offline: true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.ImagePinFlagName, flags.OfflineFlagName),
+		},
+		{
+			Name: "offline with source image pull",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace:       "default",
+					Name:            "my-resource",
+					DryRun:          true,
+					SourceImage:     "repo.example/my-source",
+					SourceImagePull: "tag",
+				},
+				Offline: true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.SourceImagePullFlagName, flags.OfflineFlagName),
+		},
+		{
+			Name: "offline with image scan",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					Name:      "my-resource",
+					Image:     "ubuntu:bionic",
+					DryRun:    true,
+				},
+				Offline:       true,
+				FromImageScan: true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.FromImageScanFlagName, flags.OfflineFlagName),
+		},
+		{
+			Name: "replace service claims without file",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					Name:      "my-resource",
+				},
+				ReplaceServiceClaims: true,
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.FilePathFlagName),
+		},
+		{
+			Name: "values without file",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace:   "default",
+					Name:        "my-resource",
+					ValuesFiles: []string{"testdata/values-staging.yaml"},
+				},
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.FilePathFlagName),
+		},
+		{
+			Name: "invalid set",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					FilePath:  "testdata/workload-template.yaml",
+					Set:       []string{"environment"},
+				},
+			},
+			ExpectFieldErrors: validation.ErrInvalidArrayValue("environment", flags.SetFlagName, 0),
+		},
+		{
+			Name: "strict without file",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					Name:      "my-resource",
+				},
+				Strict: true,
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.FilePathFlagName),
+		},
+		{
+			Name: "strict offline",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					FilePath:  "testdata/workload.yaml",
+					DryRun:    true,
+				},
+				Offline: true,
+				Strict:  true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.StrictFlagName, flags.OfflineFlagName),
+		},
+		{
+			Name: "ignore unknown without file",
+			Validatable: &commands.WorkloadApplyOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace: "default",
+					Name:      "my-workload",
+					Image:     "ubuntu:bionic",
+				},
+				IgnoreUnknown: true,
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.FilePathFlagName),
+		},
 	}
 
 	table.Run(t)
@@ -92,15 +283,117 @@ func TestWorkloadApplyCommand(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = cartov1alpha1.AddToScheme(scheme)
 	_ = corev1.AddToScheme(scheme)
+	_ = knativeservingv1.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
 
 	var cmd *cobra.Command
 
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	reg, err := ggcrregistry.TLS("registry.example")
+	utilruntime.Must(err)
+	defer reg.Close()
+	pinnedImage := "registry.example/my-image:1.0"
+	pinnedRef, err := name.ParseReference(pinnedImage)
+	utilruntime.Must(err)
+	utilruntime.Must(remote.Write(pinnedRef, empty.Image, remote.WithTransport(reg.Client().Transport)))
+	pinnedDigest, err := empty.Image.Digest()
+	utilruntime.Must(err)
+	scannedImage := "registry.example/my-scanned-image:1.0"
+	scannedRef, err := name.ParseReference(scannedImage)
+	utilruntime.Must(err)
+	scanned, err := mutate.ConfigFile(empty.Image, &regv1.ConfigFile{
+		Config: regv1.Config{
+			Labels: map[string]string{
+				"org.opencontainers.image.title":    "my-app",
+				"org.opencontainers.image.source":   "https://example.com/repo.git",
+				"org.opencontainers.image.revision": "abcd1234",
+			},
+			ExposedPorts: map[string]struct{}{"9090/tcp": {}, "8080/tcp": {}, "5353/udp": {}},
+		},
+	})
+	utilruntime.Must(err)
+	utilruntime.Must(remote.Write(scannedRef, scanned, remote.WithTransport(reg.Client().Transport)))
+	stashRegistry := func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+		return source.StashContainerRemoteTransport(ctx, reg.Client().Transport), nil
+	}
+
+	// removal is the merge patch of the fields removed by an update that no field manager owns, an apply
+	// cannot remove them
+	removal := func(namespace, name, patch string) rtesting.PatchRef {
+		return rtesting.PatchRef{
+			Group:     "carto.run",
+			Kind:      "Workload",
+			Namespace: namespace,
+			Name:      name,
+			PatchType: types.MergePatchType,
+			Patch:     []byte(patch),
+		}
+	}
+
+	// imageOwnedBy is the field manager applying the image of the workload
+	imageOwnedBy := func(manager string) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{
+			Manager:    manager,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: cartov1alpha1.SchemeGroupVersion.String(),
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:image":{}}}`)},
+		}
+	}
+
 	parent := diecartov1alpha1.WorkloadBlank.
 		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
 			d.Name(workloadName)
 			d.Namespace(defaultNamespace)
 		})
 
+	stubDatabaseClaim := cartov1alpha1.WorkloadServiceClaim{
+		Name: "database",
+		Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+			APIVersion: "services.tanzu.vmware.com/v1alpha1",
+			Kind:       "Secret",
+			Name:       "stub-db",
+		},
+	}
+	cacheClaim := cartov1alpha1.WorkloadServiceClaim{
+		Name: "cache",
+		Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+			APIVersion: "services.tanzu.vmware.com/v1alpha1",
+			Kind:       "Redis",
+			Name:       "my-cache",
+		},
+	}
+	serviceClaimsParent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name("service")
+			d.Namespace(defaultNamespace)
+		}).
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.ServiceClaims(
+				cartov1alpha1.WorkloadServiceClaim{
+					Name: "database",
+					Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+						APIVersion: "services.tanzu.vmware.com/v1alpha1",
+						Kind:       "PostgreSQL",
+						Name:       "old-db",
+					},
+				},
+				cacheClaim,
+			)
+			d.Source(&cartov1alpha1.Source{
+				Git: &cartov1alpha1.GitSource{
+					URL: "https://github.com/spring-projects/spring-petclinic.git",
+					Ref: cartov1alpha1.GitRef{Branch: "main"},
+				},
+			})
+		})
+
 	givenNamespaceDefault := []client.Object{
 		diecorev1.NamespaceBlank.
 			MetadataDie(func(d *diemetav1.ObjectMetaDie) {
@@ -108,6 +401,78 @@ func TestWorkloadApplyCommand(t *testing.T) {
 			}),
 	}
 
+	// workloadCRD is a Workload CRD whose schema only knows the git source of a workload
+	workloadCRD := func(deprecationWarning *string) *apiextensionsv1.CustomResourceDefinition {
+		object := func(properties map[string]apiextensionsv1.JSONSchemaProps) apiextensionsv1.JSONSchemaProps {
+			return apiextensionsv1.JSONSchemaProps{Type: "object", Properties: properties}
+		}
+		str := apiextensionsv1.JSONSchemaProps{Type: "string"}
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "workloads.carto.run"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "carto.run",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+					Name:               "v1alpha1",
+					Served:             true,
+					Storage:            true,
+					Deprecated:         deprecationWarning != nil,
+					DeprecationWarning: deprecationWarning,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"apiVersion": str,
+								"kind":       str,
+								"metadata":   {Type: "object"},
+								"spec": object(map[string]apiextensionsv1.JSONSchemaProps{
+									"source": object(map[string]apiextensionsv1.JSONSchemaProps{
+										"git": object(map[string]apiextensionsv1.JSONSchemaProps{
+											"url": str,
+											"ref": object(map[string]apiextensionsv1.JSONSchemaProps{
+												"branch": str,
+											}),
+										}),
+									}),
+								}),
+							},
+						},
+					},
+				}},
+			},
+		}
+	}
+	deprecationWarning := "carto.run/v1alpha1 Workload is deprecated, use carto.run/v1 Workload"
+	unknownFieldsWorkload := []byte(`
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: spring-petclinic
+spec:
+  buildTimeout: 10m
+  source:
+    git:
+      url: https://github.com/spring-projects/spring-petclinic.git
+      depth: 1
+      ref:
+        branch: main
+`)
+	schemaWorkloadCreate := &cartov1alpha1.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: defaultNamespace,
+			Name:      "spring-petclinic",
+		},
+		Spec: cartov1alpha1.WorkloadSpec{
+			Source: &cartov1alpha1.Source{
+				Git: &cartov1alpha1.GitSource{
+					URL: "https://github.com/spring-projects/spring-petclinic.git",
+					Ref: cartov1alpha1.GitRef{
+						Branch: "main",
+					},
+				},
+			},
+		},
+	}
+
 	table := clitesting.CommandTestSuite{
 		{
 			Name:        "invalid args",
@@ -145,96 +510,2830 @@ status:
 `,
 		},
 		{
-			Name:         "git source with subPath",
-			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.SubPathFlagName, "./app", flags.YesFlagName},
+			Name:         "dry run of a template with values",
+			Args:         []string{flags.FilePathFlagName, "testdata/workload-template.yaml", flags.ValuesFlagName, "testdata/values-staging.yaml", flags.DryRunFlagName},
 			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
-				&cartov1alpha1.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
-						Name:      workloadName,
-						Labels:    map[string]string{},
-					},
-					Spec: cartov1alpha1.WorkloadSpec{
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: gitRepo,
-								Ref: cartov1alpha1.GitRef{
-									Branch: gitBranch,
-								},
-							},
-							Subpath: "./app",
-						},
-					},
-				},
-			},
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  name: my-workload
-      6 + |  namespace: default
-      7 + |spec:
-      8 + |  source:
-      9 + |    git:
-     10 + |      ref:
-     11 + |        branch: main
-     12 + |      url: https://example.com/repo.git
-     13 + |    subPath: ./app
-
-Created workload "my-workload"
-
-To see logs:   "tanzu apps workload tail my-workload"
-To get status: "tanzu apps workload get my-workload"
-
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  labels:
+    app.kubernetes.io/part-of: spring-petclinic
+    apps.tanzu.vmware.com/workload-type: web
+  name: spring-petclinic-staging
+  namespace: default
+spec:
+  env:
+  - name: SPRING_PROFILES_ACTIVE
+    value: mysql
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://github.com/spring-projects/spring-petclinic.git
+status:
+  supplyChainRef: {}
 `,
 		},
 		{
-			Name: "create git source with invalid namespace",
-			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.NamespaceFlagName, "foo", flags.YesFlagName},
-			WithReactors: []clitesting.ReactionFunc{
-				clitesting.InduceFailure("get", "Namespace", clitesting.InduceFailureOpts{
-					Error: apierrors.NewNotFound(corev1.Resource("Namespace"), "foo"),
-				}),
-			},
-			ShouldError: true,
+			Name: "dry run of a template with layered values",
+			Args: []string{flags.FilePathFlagName, "testdata/workload-template.yaml",
+				flags.ValuesFlagName, "testdata/values-staging.yaml", flags.ValuesFlagName, "testdata/values-postgres.yaml",
+				flags.SetFlagName, "environment=prod", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
 			ExpectOutput: `
-Error: namespace "foo" not found, it may not exist or user does not have permissions to read it.
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  labels:
+    app.kubernetes.io/part-of: spring-petclinic
+    apps.tanzu.vmware.com/workload-type: web
+  name: spring-petclinic-prod
+  namespace: default
+spec:
+  env:
+  - name: SPRING_PROFILES_ACTIVE
+    value: postgres
+  source:
+    git:
+      ref:
+        branch: release
+      url: https://github.com/spring-projects/spring-petclinic.git
+status:
+  supplyChainRef: {}
 `,
 		},
 		{
-			Name: "Update git source with subPath from file",
-			Args: []string{workloadName, flags.FilePathFlagName, "./testdata/workload-subPath.yaml", flags.YesFlagName},
-			GivenObjects: []client.Object{
-				parent.
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Source(&cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: "https://github.com/spring-projects/spring-petclinic.git",
-								Ref: cartov1alpha1.GitRef{
-									Branch: gitBranch,
-								},
-							},
-						})
-					}),
-			},
-			ExpectUpdates: []client.Object{
-				&cartov1alpha1.Workload{
+			Name:         "template with a nested value set",
+			Args:         []string{flags.FilePathFlagName, "testdata/workload-template.yaml", flags.SetFlagName, "environment=dev", flags.SetFlagName, "git.branch=dev", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  labels:
+    app.kubernetes.io/part-of: spring-petclinic
+    apps.tanzu.vmware.com/workload-type: web
+  name: spring-petclinic-dev
+  namespace: default
+spec:
+  env:
+  - name: SPRING_PROFILES_ACTIVE
+    value: mysql
+  source:
+    git:
+      ref:
+        branch: dev
+      url: https://github.com/spring-projects/spring-petclinic.git
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name:         "template missing a nested value",
+			Args:         []string{flags.FilePathFlagName, "testdata/workload-template.yaml", flags.SetFlagName, "environment=dev", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name:         "template with missing values file",
+			Args:         []string{flags.FilePathFlagName, "testdata/workload-template.yaml", flags.ValuesFlagName, "testdata/missing.yaml", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name:         "dry run as a kustomize patch",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LabelFlagName, "team=checkout", flags.DryRunFlagName, flags.OutputFormatFlagName, "kustomize-patch"},
+			GivenObjects: givenNamespaceDefault,
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  labels:
+    team: checkout
+  name: my-workload
+spec:
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://example.com/repo.git
+`,
+		},
+		{
+			Name:         "dry run as a ytt overlay",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LabelFlagName, "team=checkout", flags.DryRunFlagName, flags.OutputFormatFlagName, "ytt"},
+			GivenObjects: givenNamespaceDefault,
+			ExpectOutput: `
+#@ load("@ytt:overlay", "overlay")
+#@overlay/match by=overlay.subset({"kind": "Workload", "metadata": {"name": "my-workload"}})
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+#@overlay/match-child-defaults missing_ok=True
+metadata:
+  labels:
+    team: checkout
+  name: my-workload
+#@overlay/replace
+spec:
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://example.com/repo.git
+`,
+		},
+		{
+			Name:         "labels and annotations from files",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LabelFileFlagName, "testdata/labels.yaml", flags.LabelFlagName, "team=checkout", flags.AnnotationFileFlagName, "testdata/annotations.properties", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  labels:
+    cost-center: "1234"
+    team: checkout
+  name: my-workload
+  namespace: default
+spec:
+  params:
+  - name: annotations
+    value:
+      owner: jane@example.com
+      runbook: https://example.com/runbooks/payments
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://example.com/repo.git
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name:         "missing label file",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LabelFileFlagName, "testdata/missing.yaml", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name:         "params from files",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ParamFileFlagName, "ports=testdata/param-file.yaml", flags.ParamFileFlagName, "scaling=testdata/param-file.yaml", flags.ParamYamlFlagName, "scaling={\"minScale\": 2}", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  params:
+  - name: ports
+    value:
+    - containerPort: 8080
+      name: http
+    - containerPort: 9090
+      name: metrics
+  - name: scaling
+    value:
+      minScale: 2
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://example.com/repo.git
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name: "workload profile",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.WorkloadProfileConfigKey, map[string]interface{}{
+					"labels":      []string{"cost-center=1234"},
+					"annotations": []string{"owner=platform"},
+				})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation("owner", "payments")
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						})
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation("owner", "payments")
+						d.AddLabel("cost-center", "1234")
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						})
+					}),
+			},
+			ExpectOutput: `
+Update workload:
+...
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  annotations:
+  6,  6   |    owner: payments
+      7 + |  labels:
+      8 + |    cost-center: "1234"
+  7,  9   |  name: my-workload
+  8, 10   |  namespace: default
+  9, 11   |spec:
+ 10, 12   |  source:
+...
+
+NOTICE: label "cost-center" set to "1234" by the workload-profile of the plugin config for all namespaces.
+
+Updated workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name:         "missing param file",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ParamFileFlagName, "ports=testdata/missing.yaml", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name:         "invalid param file",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ParamFileFlagName, "ports=testdata/param-file-invalid.yaml", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name:         "pin image",
+			Args:         []string{workloadName, flags.ImageFlagName, pinnedImage, flags.ImagePinFlagName, flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Prepare:      stashRegistry,
+			ExpectOutput: `
+Pinned image "registry.example/my-image:1.0" to "registry.example/my-image:1.0@` + pinnedDigest.String() + `"
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  image: registry.example/my-image:1.0@` + pinnedDigest.String() + `
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name:         "pin image already pinned",
+			Args:         []string{workloadName, flags.ImageFlagName, pinnedImage + "@" + pinnedDigest.String(), flags.ImagePinFlagName, flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectOutput: `
+Image "registry.example/my-image:1.0@` + pinnedDigest.String() + `" is already pinned to a digest
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  image: registry.example/my-image:1.0@` + pinnedDigest.String() + `
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name:         "pin image tag not found",
+			Args:         []string{workloadName, flags.ImageFlagName, "registry.example/my-image:missing", flags.ImagePinFlagName, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Prepare:      stashRegistry,
+			ShouldError:  true,
+		},
+		{
+			Name:         "pin image without image",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ImagePinFlagName, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name:         "from image scan",
+			Args:         []string{workloadName, flags.ImageFlagName, scannedImage, flags.FromImageScanFlagName, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Prepare:      stashRegistry,
+			ExpectCreates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Labels(map[string]string{apis.AppPartOfLabelName: "my-app"})
+						d.Annotations(map[string]string{
+							"org.opencontainers.image.revision": "abcd1234",
+							"org.opencontainers.image.source":   "https://example.com/repo.git",
+						})
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image(scannedImage)
+						d.Params(cartov1alpha1.Param{
+							Name:  "ports",
+							Value: apiextensionsv1.JSON{Raw: []byte(`[{"containerPort":5353,"protocol":"UDP"},{"containerPort":8080},{"containerPort":9090}]`)},
+						})
+					}),
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  annotations:
+      6 + |    org.opencontainers.image.revision: abcd1234
+      7 + |    org.opencontainers.image.source: https://example.com/repo.git
+      8 + |  labels:
+      9 + |    app.kubernetes.io/part-of: my-app
+     10 + |  name: my-workload
+     11 + |  namespace: default
+     12 + |spec:
+     13 + |  image: registry.example/my-scanned-image:1.0
+     14 + |  params:
+     15 + |  - name: ports
+     16 + |    value:
+     17 + |    - containerPort: 5353
+     18 + |      protocol: UDP
+     19 + |    - containerPort: 8080
+     20 + |    - containerPort: 9090
+
+NOTICE: label "app.kubernetes.io/part-of" set to "my-app" by the config of image "registry.example/my-scanned-image:1.0".
+
+NOTICE: annotation "org.opencontainers.image.revision" set to "abcd1234" by the config of image "registry.example/my-scanned-image:1.0".
+
+NOTICE: annotation "org.opencontainers.image.source" set to "https://example.com/repo.git" by the config of image "registry.example/my-scanned-image:1.0".
+
+NOTICE: param "ports" set to "5353/udp,8080/tcp,9090/tcp" by the config of image "registry.example/my-scanned-image:1.0".
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name:         "from image scan keeps values set by flags",
+			Args:         []string{workloadName, flags.ImageFlagName, scannedImage, flags.FromImageScanFlagName, flags.LabelFlagName, apis.AppPartOfLabelName + "=my-part", flags.ParamFlagName, "ports=8080", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Prepare:      stashRegistry,
+			ExpectCreates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Labels(map[string]string{apis.AppPartOfLabelName: "my-part"})
+						d.Annotations(map[string]string{
+							"org.opencontainers.image.revision": "abcd1234",
+							"org.opencontainers.image.source":   "https://example.com/repo.git",
+						})
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image(scannedImage)
+						d.Params(cartov1alpha1.Param{
+							Name:  "ports",
+							Value: apiextensionsv1.JSON{Raw: []byte(`"8080"`)},
+						})
+					}),
+			},
+		},
+		{
+			Name:         "from image scan image not found",
+			Args:         []string{workloadName, flags.ImageFlagName, "registry.example/my-scanned-image:missing", flags.FromImageScanFlagName, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Prepare:      stashRegistry,
+			ShouldError:  true,
+		},
+		{
+			Name:         "from image scan without image",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.FromImageScanFlagName, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name:         "pull source image",
+			Args:         []string{workloadName, flags.SourceImageFlagName, pinnedImage, flags.SourceImagePullFlagName, flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Prepare:      stashRegistry,
+			ExpectOutput: `
+Found source image "registry.example/my-image:1.0"
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  source:
+    image: registry.example/my-image:1.0
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name:         "pull source image pinned to digest",
+			Args:         []string{workloadName, flags.SourceImageFlagName, pinnedImage, flags.SourceImagePullFlagName + "=" + commands.SourceImagePullDigest, flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Prepare:      stashRegistry,
+			ExpectOutput: `
+Pinned source image "registry.example/my-image:1.0" to "registry.example/my-image:1.0@` + pinnedDigest.String() + `"
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  source:
+    image: registry.example/my-image:1.0@` + pinnedDigest.String() + `
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name:         "pull source image already pinned",
+			Args:         []string{workloadName, flags.SourceImageFlagName, pinnedImage + "@" + pinnedDigest.String(), flags.SourceImagePullFlagName + "=" + commands.SourceImagePullDigest, flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectOutput: `
+Source image "registry.example/my-image:1.0@` + pinnedDigest.String() + `" is already pinned to a digest
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  source:
+    image: registry.example/my-image:1.0@` + pinnedDigest.String() + `
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name:         "pull source image not found",
+			Args:         []string{workloadName, flags.SourceImageFlagName, "registry.example/my-image:missing", flags.SourceImagePullFlagName, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Prepare:      stashRegistry,
+			ShouldError:  true,
+		},
+		{
+			Name:         "pull source image without source image",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.SourceImagePullFlagName, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name: "offline dry run",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.DryRunFlagName, flags.OfflineFlagName},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("get", "Workload"),
+				clitesting.InduceFailure("get", "Namespace"),
+				clitesting.InduceFailure("get", "ConfigMap"),
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://example.com/repo.git
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name: "offline dry run from file",
+			Args: []string{flags.FilePathFlagName, file, flags.DryRunFlagName, flags.OfflineFlagName},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("get", "Workload"),
+				clitesting.InduceFailure("get", "Namespace"),
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  labels:
+    app.kubernetes.io/part-of: spring-petclinic
+    apps.tanzu.vmware.com/workload-type: web
+  name: spring-petclinic
+  namespace: default
+spec:
+  env:
+  - name: SPRING_PROFILES_ACTIVE
+    value: mysql
+  resources:
+    limits:
+      cpu: 500m
+      memory: 1Gi
+    requests:
+      cpu: 100m
+      memory: 1Gi
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://github.com/spring-projects/spring-petclinic.git
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name:        "offline requires dry run",
+			Args:        []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.OfflineFlagName},
+			ShouldError: true,
+		},
+		{
+			Name:         "git source with subPath",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.SubPathFlagName, "./app", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+							Subpath: "./app",
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+     13 + |    subPath: ./app
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name:         "diff tool",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.DiffToolFlagName, "delta --side-by-side", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExecHelper:   "DiffTool",
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				return commands.StashTerminal(ctx, true), nil
+			},
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+delta --side-by-side current.yaml (0 lines) new.yaml (12 lines)
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name:         "diff tool with quoted args",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.DiffToolFlagName, `delta "--side-by-side"`, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExecHelper:   "DiffTool",
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				return commands.StashTerminal(ctx, true), nil
+			},
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+delta --side-by-side current.yaml (0 lines) new.yaml (12 lines)
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name:         "diff tool without terminal",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.DiffToolFlagName, "delta --side-by-side", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExecHelper:   "DiffTool",
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name:         "diff tool from plugin config fails",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExecHelper:   "DiffTool",
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(cli.DefaultsConfigKey+".diff-tool", "missing-diff")
+				return commands.StashTerminal(ctx, true), nil
+			},
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+exec: "missing-diff": executable file not found in $PATH
+WARNING: unable to run diff tool "missing-diff", showing the built-in diff: exit status 127
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name: "protected label prefix",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LabelFlagName, "kapp.k14s.io/app=my-app", flags.YesFlagName},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return c
+			}(),
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+			Verify: func(t *testing.T, output string, err error) {
+				msg := `--label: Forbidden: "kapp.k14s.io/app" uses the prefix "kapp.k14s.io/" which is owned by the platform, changing it may break controllers managing the workload. Use --force to override`
+				if err.Error() != msg {
+					t.Errorf("expected error %q, got %q", msg, err.Error())
+				}
+			},
+		},
+		{
+			Name: "protected label prefix with force",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LabelFlagName, "kapp.k14s.io/app=my-app", flags.ForceFlagName, flags.YesFlagName},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return c
+			}(),
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels: map[string]string{
+							"kapp.k14s.io/app": "my-app",
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "protected namespace",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(commands.ProtectedNamespacesConfigKey, []string{"kube-system", defaultNamespace})
+				return c
+			}(),
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+			Verify: func(t *testing.T, output string, err error) {
+				msg := `--namespace: Forbidden: namespace "default" is protected by the platform, workloads are not expected to be changed in it. Use --allow-protected to override`
+				if err.Error() != msg {
+					t.Errorf("expected error %q, got %q", msg, err.Error())
+				}
+			},
+		},
+		{
+			Name: "protected namespace allowed",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.AllowProtectedFlagName, flags.YesFlagName},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(commands.ProtectedNamespacesConfigKey, []string{"kube-system", defaultNamespace})
+				return c
+			}(),
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "create git source with invalid namespace",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.NamespaceFlagName, "foo", flags.YesFlagName},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("get", "Namespace", clitesting.InduceFailureOpts{
+					Error: apierrors.NewNotFound(corev1.Resource("Namespace"), "foo"),
+				}),
+			},
+			ShouldError: true,
+			ExpectOutput: `
+Error: namespace "foo" not found, it may not exist or user does not have permissions to read it.
+`,
+		},
+		{
+			Name: "create git source with a created namespace",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.NamespaceFlagName, "foo", flags.CreateNamespaceFlagName, flags.NamespaceLabelFlagName, "team=payments", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Client = &namespacedClient{Client: config.Client}
+				return ctx, nil
+			},
+			ExpectCreates: []client.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "foo",
+						Labels: map[string]string{"team": "payments"},
+					},
+				},
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      workloadName,
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create namespace:
+      1 + |---
+      2 + |apiVersion: v1
+      3 + |kind: Namespace
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    team: payments
+      7 + |  name: foo
+      8 + |spec: {}
+
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: foo
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created namespace "foo"
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload --namespace foo"
+To get status: "tanzu apps workload get my-workload --namespace foo"
+
+`,
+		},
+		{
+			Name: "create namespace failing to read the namespace",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.NamespaceFlagName, "foo", flags.CreateNamespaceFlagName, flags.YesFlagName},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("get", "Namespace"),
+			},
+			ShouldError: true,
+		},
+		{
+			Name: "dry run git source with a created namespace",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.NamespaceFlagName, "foo", flags.CreateNamespaceFlagName, flags.DryRunFlagName},
+			ExpectOutput: `
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  creationTimestamp: null
+  name: foo
+spec: {}
+status: {}
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: foo
+spec:
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://example.com/repo.git
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name:         "create git source in an existing namespace with --create-namespace",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.CreateNamespaceFlagName, flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://example.com/repo.git
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name: "create with audit record",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				ctx = commands.StashClock(ctx, func() time.Time {
+					return time.Date(2022, time.June, 9, 15, 12, 0, 0, time.UTC)
+				})
+				ctx = commands.StashAuditor(ctx, &commands.Auditor{
+					Version: "v0.9.0",
+					User:    func() string { return "alice" },
+				})
+				return ctx, nil
+			},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Annotations: map[string]string{
+							apis.LastModifiedByAnnotationName: `{"user":"alice","time":"2022-06-09T15:12:00Z","command":"apply","flags":["--git-branch","--git-repo","--yes"],"version":"v0.9.0"}`,
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name: "update replaces audit record",
+			Args: []string{workloadName, flags.GitBranchFlagName, "release", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				ctx = commands.StashClock(ctx, func() time.Time {
+					return time.Date(2022, time.June, 9, 15, 12, 0, 0, time.UTC)
+				})
+				ctx = commands.StashAuditor(ctx, &commands.Auditor{
+					Version: "v0.9.0",
+					User:    func() string { return "alice" },
+				})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"bob","time":"2022-06-01T10:00:00Z","command":"apply","flags":["--git-repo"],"version":"v0.8.0"}`)
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						})
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"alice","time":"2022-06-09T15:12:00Z","command":"apply","flags":["--git-branch","--yes"],"version":"v0.9.0"}`)
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: "release",
+								},
+							},
+						})
+					}),
+			},
+			ExpectOutput: `
+Update workload:
+...
+  9,  9   |spec:
+ 10, 10   |  source:
+ 11, 11   |    git:
+ 12, 12   |      ref:
+ 13     - |        branch: main
+     13 + |        branch: release
+ 14, 14   |      url: https://example.com/repo.git
+
+Updated workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name: "create without audit record",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.AuditFlagName + "=false", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				ctx = commands.StashAuditor(ctx, &commands.Auditor{
+					Version: "v0.9.0",
+					User:    func() string { return "alice" },
+				})
+				return ctx, nil
+			},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name: "Update git source with subPath from file",
+			Args: []string{workloadName, flags.FilePathFlagName, "./testdata/workload-subPath.yaml", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.com/spring-projects/spring-petclinic.git",
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						})
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.com/spring-projects/spring-petclinic.git",
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+							Subpath: "./app",
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Update workload:
+...
+  9,  9   |    git:
+ 10, 10   |      ref:
+ 11, 11   |        branch: main
+ 12, 12   |      url: https://github.com/spring-projects/spring-petclinic.git
+     13 + |    subPath: ./app
+
+Updated workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name:         "Create git source with subPath from file",
+			Args:         []string{workloadName, flags.FilePathFlagName, "./testdata/workload-subPath.yaml", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.com/spring-projects/spring-petclinic.git",
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+							Subpath: "./app",
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://github.com/spring-projects/spring-petclinic.git
+     13 + |    subPath: ./app
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name:        "subPath with no source",
+			Args:        []string{workloadName, flags.SubPathFlagName, "./app", flags.YesFlagName},
+			ShouldError: true,
+		},
+		{
+			Name: "wait with timeout error",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.WaitFlagName, flags.WaitTimeoutFlagName, "1ns"},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				workload := &cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Status: cartov1alpha1.WorkloadStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   cartov1alpha1.WorkloadConditionReady,
+								Status: metav1.ConditionTrue,
+							},
+						},
+					},
+				}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+				return ctx, nil
+			},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeTimeout),
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to become ready...
+Error: timeout after 1ns waiting for "my-workload" to become ready
+`,
+		},
+		{
+			Name: "successful wait for ready cond",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.WaitFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				workload := &cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Status: cartov1alpha1.WorkloadStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   cartov1alpha1.WorkloadConditionReady,
+								Status: metav1.ConditionTrue,
+							},
+						},
+					},
+				}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+				return ctx, nil
+			},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to become ready...
+  Ready=True
+Workload "my-workload" is ready
+
+Latency
+   ready:   0s
+`,
+		},
+		{
+			Name: "successful wait for custom condition",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.WaitForFlagName, "condition=SupplyChainReady"},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				workload := &cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Status: cartov1alpha1.WorkloadStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   cartov1alpha1.WorkloadConditionReady,
+								Status: metav1.ConditionUnknown,
+							},
+							{
+								Type:   "SupplyChainReady",
+								Status: metav1.ConditionTrue,
+							},
+						},
+					},
+				}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+				return ctx, nil
+			},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to meet condition=SupplyChainReady...
+  Ready=Unknown
+  SupplyChainReady=True
+Workload "my-workload" met condition=SupplyChainReady
+
+Latency
+   ready:   0s
+`,
+		},
+		{
+			Name: "output name and url once ready",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.OutputFlagName, commands.OutputFormatNameAndURL},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				workload := &cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Status: cartov1alpha1.WorkloadStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   cartov1alpha1.WorkloadConditionReady,
+								Status: metav1.ConditionTrue,
+							},
+						},
+					},
+				}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+				return ctx, nil
+			},
+			GivenObjects: append([]client.Object{
+				diev1.ServiceBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(workloadName)
+						d.Namespace(defaultNamespace)
+						d.AddLabel(cartov1alpha1.WorkloadLabelName, workloadName)
+					}).
+					StatusDie(func(d *diev1.ServiceStatusDie) {
+						d.URL("https://my-workload.default.example.com")
+					}),
+			}, givenNamespaceDefault...),
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to become ready...
+  Ready=True
+Workload "my-workload" is ready
+
+Latency
+   ready:   0s
+my-workload https://my-workload.default.example.com
+`,
+		},
+		{
+			Name: "output name and url without url",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.OutputFlagName, commands.OutputFormatNameAndURL},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				workload := &cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Status: cartov1alpha1.WorkloadStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   cartov1alpha1.WorkloadConditionReady,
+								Status: metav1.ConditionTrue,
+							},
+						},
+					},
+				}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+				return ctx, nil
+			},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ShouldError: true,
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to become ready...
+  Ready=True
+Workload "my-workload" is ready
+
+Latency
+   ready:   0s
+Error: workload "my-workload" has no URL
+`,
+		},
+		{
+			Name: "verify url once ready",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.WaitFlagName, flags.VerifyURLFlagName, server.URL + "/healthz"},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				workload := &cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Status: cartov1alpha1.WorkloadStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   cartov1alpha1.WorkloadConditionReady,
+								Status: metav1.ConditionTrue,
+							},
+						},
+					},
+				}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+				return ctx, nil
+			},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to become ready...
+  Ready=True
+Workload "my-workload" is ready
+
+Latency
+   ready:   0s
+Verifying workload "my-workload" with GET ` + server.URL + `/healthz
+Workload "my-workload" verified
+`,
+		},
+		{
+			Name: "verify url fails",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.WaitFlagName, flags.VerifyURLFlagName, server.URL + "/broken"},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				workload := &cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Status: cartov1alpha1.WorkloadStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   cartov1alpha1.WorkloadConditionReady,
+								Status: metav1.ConditionTrue,
+							},
+						},
+					},
+				}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+				return ctx, nil
+			},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeVerifyFailed),
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to become ready...
+  Ready=True
+Workload "my-workload" is ready
+
+Latency
+   ready:   0s
+Verifying workload "my-workload" with GET ` + server.URL + `/broken
+Error: smoke check failed: GET ` + server.URL + `/broken returned 503, expected 200
+`,
+		},
+		{
+			Name: "tail while waiting for ready cond",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.TailFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				workload := &cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Status: cartov1alpha1.WorkloadStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   cartov1alpha1.WorkloadConditionReady,
+								Status: metav1.ConditionTrue,
+							},
+						},
+					},
+				}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+
+				return ctx, nil
+			},
+			GivenObjects: givenNamespaceDefault,
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to become ready...
+...tail output...
+Workload "my-workload" is ready
+
+Latency
+   ready:   0s
+`,
+		},
+		{
+			Name: "error during create",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("create", "Workload"),
+			},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ShouldError: true,
+		},
+		{
+			Name: "watcher error",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.WaitFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				fakewatch := watchfakes.NewFakeWithWatch(true, config.Client, []watch.Event{})
+				ctx = watchhelper.WithWatcher(ctx, fakewatch)
+				return ctx, nil
+			},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ShouldError: true,
+		},
+		{
+			Name: "create - wait error for false condition",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LabelFlagName, "apps.tanzu.vmware.com/workload-type=web", flags.LabelFlagName, "apps.tanzu.vmware.com/workload-type-", flags.YesFlagName, flags.WaitFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				workload := &cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Status: cartov1alpha1.WorkloadStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:    cartov1alpha1.WorkloadConditionReady,
+								Status:  metav1.ConditionFalse,
+								Reason:  "OopsieDoodle",
+								Message: "a hopefully informative message about what went wrong",
+							},
+						},
+					},
+				}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+				return ctx, nil
+			},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeFailedCondition),
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to become ready...
+  Ready=False (OopsieDoodle)
+Error: Failed to become ready: a hopefully informative message about what went wrong
+`,
+		},
+		{
+			Name: "filepath with multiple workloads",
+			Args: []string{flags.FilePathFlagName, "testdata/workloads-multi.yaml", flags.IgnoreUnknownFlagName, flags.YesFlagName},
+			GivenObjects: append(givenNamespaceDefault,
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("backend")
+						d.Namespace(defaultNamespace)
+						d.AddLabel(apis.AppPartOfLabelName, "shop")
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			),
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      "frontend",
+						Labels: map[string]string{
+							apis.AppPartOfLabelName: "shop",
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Image: "ubuntu:bionic",
+					},
+				},
+			},
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      "backend",
+						Labels: map[string]string{
+							apis.AppPartOfLabelName: "shop",
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Image: "ubuntu:focal",
+					},
+				},
+			},
+			ExpectOutput: `Skipping document 2 with API Version "v1" and Kind "ConfigMap"
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    app.kubernetes.io/part-of: shop
+      7 + |  name: frontend
+      8 + |  namespace: default
+      9 + |spec:
+     10 + |  image: ubuntu:bionic
+
+Created workload "frontend"
+
+To see logs:   "tanzu apps workload tail frontend"
+To get status: "tanzu apps workload get frontend"
+
+Update workload:
+...
+  6,  6   |    app.kubernetes.io/part-of: shop
+  7,  7   |  name: backend
+  8,  8   |  namespace: default
+  9,  9   |spec:
+ 10     - |  image: ubuntu:bionic
+     10 + |  image: ubuntu:focal
+
+Updated workload "backend"
+
+To see logs:   "tanzu apps workload tail backend"
+To get status: "tanzu apps workload get backend"
+
+`,
+		},
+		{
+			Name:         "filepath with unknown kind",
+			Args:         []string{flags.FilePathFlagName, "testdata/workloads-multi.yaml", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+			Verify: func(t *testing.T, output string, err error) {
+				expected := `document 2 of file "testdata/workloads-multi.yaml" has API Version "v1" and Kind "ConfigMap", expected API Version "carto.run/v1alpha1" and Kind "Workload" (use --ignore-unknown to skip it)`
+				if err.Error() != expected {
+					t.Errorf("expected error %q, got %q", expected, err.Error())
+				}
+			},
+		},
+		{
+			Name:         "filepath with multiple workloads and name",
+			Args:         []string{workloadName, flags.FilePathFlagName, "testdata/workloads-multi.yaml", flags.IgnoreUnknownFlagName, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+			ExpectOutput: `
+Skipping document 2 with API Version "v1" and Kind "ConfigMap"
+`,
+			Verify: func(t *testing.T, output string, err error) {
+				expected := `a workload name cannot be set when file "testdata/workloads-multi.yaml" contains multiple workloads`
+				if err.Error() != expected {
+					t.Errorf("expected error %q, got %q", expected, err.Error())
+				}
+			},
+		},
+		{
+			Name:         "filepath",
+			Args:         []string{flags.FilePathFlagName, "testdata/workload.yaml", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      "spring-petclinic",
+						Labels: map[string]string{
+							apis.AppPartOfLabelName:               "spring-petclinic",
+							"apps.tanzu.vmware.com/workload-type": "web",
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.com/spring-projects/spring-petclinic.git",
+								Ref: cartov1alpha1.GitRef{
+									Branch: "main",
+								},
+							},
+						},
+						Env: []corev1.EnvVar{
+							{
+								Name:  "SPRING_PROFILES_ACTIVE",
+								Value: "mysql",
+							},
+						},
+						Resources: &corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("500m"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    app.kubernetes.io/part-of: spring-petclinic
+      7 + |    apps.tanzu.vmware.com/workload-type: web
+      8 + |  name: spring-petclinic
+      9 + |  namespace: default
+     10 + |spec:
+     11 + |  env:
+     12 + |  - name: SPRING_PROFILES_ACTIVE
+     13 + |    value: mysql
+     14 + |  resources:
+     15 + |    limits:
+     16 + |      cpu: 500m
+     17 + |      memory: 1Gi
+     18 + |    requests:
+     19 + |      cpu: 100m
+     20 + |      memory: 1Gi
+     21 + |  source:
+     22 + |    git:
+     23 + |      ref:
+     24 + |        branch: main
+     25 + |      url: https://github.com/spring-projects/spring-petclinic.git
+
+Created workload "spring-petclinic"
+
+To see logs:   "tanzu apps workload tail spring-petclinic"
+To get status: "tanzu apps workload get spring-petclinic"
+
+`,
+		},
+		{
+			Name:          "warn about fields unknown to the workload schema of the cluster",
+			Args:          []string{flags.FilePathFlagName, "-", flags.YesFlagName},
+			Stdin:         unknownFieldsWorkload,
+			GivenObjects:  append([]client.Object{workloadCRD(nil)}, givenNamespaceDefault...),
+			ExpectCreates: []client.Object{schemaWorkloadCreate},
+			ExpectOutput: `
+Warning: field "spec.buildTimeout" is unknown to the Workload schema of the cluster and is dropped
+Warning: field "spec.source.git.depth" is unknown to the Workload schema of the cluster and is dropped
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: spring-petclinic
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://github.com/spring-projects/spring-petclinic.git
+
+Created workload "spring-petclinic"
+
+To see logs:   "tanzu apps workload tail spring-petclinic"
+To get status: "tanzu apps workload get spring-petclinic"
+
+`,
+		},
+		{
+			Name: "warn about a deprecated workload version",
+			Args: []string{flags.FilePathFlagName, "-", flags.YesFlagName},
+			Stdin: []byte(`
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: spring-petclinic
+spec:
+  source:
+    git:
+      url: https://github.com/spring-projects/spring-petclinic.git
+      ref:
+        branch: main
+`),
+			GivenObjects:  append([]client.Object{workloadCRD(&deprecationWarning)}, givenNamespaceDefault...),
+			ExpectCreates: []client.Object{schemaWorkloadCreate},
+			ExpectOutput: `
+Warning: carto.run/v1alpha1 Workload is deprecated, use carto.run/v1 Workload
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: spring-petclinic
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://github.com/spring-projects/spring-petclinic.git
+
+Created workload "spring-petclinic"
+
+To see logs:   "tanzu apps workload tail spring-petclinic"
+To get status: "tanzu apps workload get spring-petclinic"
+
+`,
+		},
+		{
+			Name:         "strict with fields unknown to the workload schema of the cluster",
+			Args:         []string{flags.FilePathFlagName, "-", flags.StrictFlagName, flags.YesFlagName},
+			Stdin:        unknownFieldsWorkload,
+			GivenObjects: append([]client.Object{workloadCRD(nil)}, givenNamespaceDefault...),
+			ShouldError:  true,
+			Verify: func(t *testing.T, output string, err error) {
+				if expected := `file "-" does not match the Workload schema of the cluster: field "spec.buildTimeout" is unknown to the Workload schema of the cluster and is dropped, field "spec.source.git.depth" is unknown to the Workload schema of the cluster and is dropped`; err == nil || err.Error() != expected {
+					t.Errorf("expected error %q, got %v", expected, err)
+				}
+			},
+		},
+		{
+			Name:         "strict without workload schema",
+			Args:         []string{flags.FilePathFlagName, "-", flags.StrictFlagName, flags.YesFlagName},
+			Stdin:        unknownFieldsWorkload,
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name:          "not strict without workload schema",
+			Args:          []string{flags.FilePathFlagName, "-", flags.YesFlagName},
+			Stdin:         unknownFieldsWorkload,
+			GivenObjects:  givenNamespaceDefault,
+			ExpectCreates: []client.Object{schemaWorkloadCreate},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: spring-petclinic
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://github.com/spring-projects/spring-petclinic.git
+
+Created workload "spring-petclinic"
+
+To see logs:   "tanzu apps workload tail spring-petclinic"
+To get status: "tanzu apps workload get spring-petclinic"
+
+`,
+		},
+		{
+			Name: "create - accept yaml file through stdin - using --yes flag",
+			Args: []string{flags.FilePathFlagName, "-", flags.YesFlagName},
+			Stdin: []byte(`
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: spring-petclinic
+  labels:
+    app.kubernetes.io/part-of: spring-petclinic
+    apps.tanzu.vmware.com/workload-type: web
+spec:
+  env:
+  - name: SPRING_PROFILES_ACTIVE
+    value: mysql
+  resources:
+    requests:
+      memory: 1Gi
+      cpu: 100m
+    limits:
+      memory: 1Gi
+      cpu: 500m
+  source:
+    git:
+      url: https://github.com/spring-projects/spring-petclinic.git
+      ref:
+        branch: main
+`),
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      "spring-petclinic",
+						Labels: map[string]string{
+							apis.AppPartOfLabelName:               "spring-petclinic",
+							"apps.tanzu.vmware.com/workload-type": "web",
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.com/spring-projects/spring-petclinic.git",
+								Ref: cartov1alpha1.GitRef{
+									Branch: "main",
+								},
+							},
+						},
+						Env: []corev1.EnvVar{
+							{
+								Name:  "SPRING_PROFILES_ACTIVE",
+								Value: "mysql",
+							},
+						},
+						Resources: &corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("500m"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    app.kubernetes.io/part-of: spring-petclinic
+      7 + |    apps.tanzu.vmware.com/workload-type: web
+      8 + |  name: spring-petclinic
+      9 + |  namespace: default
+     10 + |spec:
+     11 + |  env:
+     12 + |  - name: SPRING_PROFILES_ACTIVE
+     13 + |    value: mysql
+     14 + |  resources:
+     15 + |    limits:
+     16 + |      cpu: 500m
+     17 + |      memory: 1Gi
+     18 + |    requests:
+     19 + |      cpu: 100m
+     20 + |      memory: 1Gi
+     21 + |  source:
+     22 + |    git:
+     23 + |      ref:
+     24 + |        branch: main
+     25 + |      url: https://github.com/spring-projects/spring-petclinic.git
+
+Created workload "spring-petclinic"
+
+To see logs:   "tanzu apps workload tail spring-petclinic"
+To get status: "tanzu apps workload get spring-petclinic"
+
+`,
+		},
+		{
+			Name: "update - accept yaml file through stdin - using --yes flag",
+			Args: []string{flags.FilePathFlagName, "-", flags.YesFlagName},
+			Stdin: []byte(`
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: spring-petclinic
+  labels:
+    app.kubernetes.io/part-of: spring-petclinic
+    apps.tanzu.vmware.com/workload-type: web
+spec:
+  env:
+  - name: SPRING_PROFILES_ACTIVE
+    value: mysql
+  resources:
+    requests:
+      memory: 1Gi
+      cpu: 100m
+    limits:
+      memory: 1Gi
+      cpu: 500m
+  source:
+    git:
+      url: https://github.com/spring-projects/spring-petclinic.git
+      ref:
+        branch: main
+`),
+			GivenObjects: []client.Object{
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("spring-petclinic")
+						d.Namespace(defaultNamespace)
+						d.AddLabel("preserve-me", "should-exist")
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+						d.Env(
+							corev1.EnvVar{
+								Name:  "OVERRIDE_VAR",
+								Value: "doesnt matter",
+							},
+						)
+					}),
+				givenNamespaceDefault[0],
+			},
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      "spring-petclinic",
+						Labels: map[string]string{
+							"preserve-me":                         "should-exist",
+							"app.kubernetes.io/part-of":           "spring-petclinic",
+							"apps.tanzu.vmware.com/workload-type": "web",
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.com/spring-projects/spring-petclinic.git",
+								Ref: cartov1alpha1.GitRef{
+									Branch: "main",
+								},
+							},
+						},
+						Env: []corev1.EnvVar{
+							{
+								Name:  "OVERRIDE_VAR",
+								Value: "doesnt matter",
+							},
+							{
+								Name:  "SPRING_PROFILES_ACTIVE",
+								Value: "mysql",
+							},
+						},
+						Resources: &corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("500m"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, "spring-petclinic", `{"metadata":{"resourceVersion":"999"},"spec":{"image":null}}`),
+			},
+			ExpectOutput: `
+Update workload:
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+      6 + |    app.kubernetes.io/part-of: spring-petclinic
+      7 + |    apps.tanzu.vmware.com/workload-type: web
+  6,  8   |    preserve-me: should-exist
+  7,  9   |  name: spring-petclinic
+  8, 10   |  namespace: default
+  9, 11   |spec:
+ 10, 12   |  env:
+ 11, 13   |  - name: OVERRIDE_VAR
+ 12, 14   |    value: doesnt matter
+ 13     - |  image: ubuntu:bionic
+     15 + |  - name: SPRING_PROFILES_ACTIVE
+     16 + |    value: mysql
+     17 + |  resources:
+     18 + |    limits:
+     19 + |      cpu: 500m
+     20 + |      memory: 1Gi
+     21 + |    requests:
+     22 + |      cpu: 100m
+     23 + |      memory: 1Gi
+     24 + |  source:
+     25 + |    git:
+     26 + |      ref:
+     27 + |        branch: main
+     28 + |      url: https://github.com/spring-projects/spring-petclinic.git
+
+Updated workload "spring-petclinic"
+
+To see logs:   "tanzu apps workload tail spring-petclinic"
+To get status: "tanzu apps workload get spring-petclinic"
+
+`,
+		},
+		{
+			Name: "update - accept yaml file through stdin - using --dry-run flag",
+			Args: []string{flags.FilePathFlagName, "-", flags.DryRunFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			Stdin: []byte(`
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+  resourceVersion: "999"
+spec:
+  image: ubuntu:bionic
+status:
+  supplyChainRef: {}
+`),
+		},
+		{
+			Name:         "create - accept yaml file through stdin - using --dry-run flag",
+			Args:         []string{flags.FilePathFlagName, "-", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Stdin: []byte(`
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://example.com/repo.git
+status:
+  supplyChainRef: {}
+`),
+		},
+		{
+			Name:         "filepath - service account build-env",
+			Args:         []string{flags.FilePathFlagName, "testdata/workload-build-env.yaml", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      "spring-petclinic",
+						Labels: map[string]string{
+							apis.AppPartOfLabelName:               "spring-petclinic",
+							"apps.tanzu.vmware.com/workload-type": "web",
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Build: &cartov1alpha1.WorkloadBuild{Env: []corev1.EnvVar{
+							{Name: "BP_MAVEN_POM_FILE", Value: "skip-pom.xml"},
+						}},
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.com/spring-projects/spring-petclinic.git",
+								Ref: cartov1alpha1.GitRef{
+									Branch: "main",
+								},
+							},
+						},
+						Env: []corev1.EnvVar{
+							{
+								Name:  "SPRING_PROFILES_ACTIVE",
+								Value: "mysql",
+							},
+						},
+						Resources: &corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("500m"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    app.kubernetes.io/part-of: spring-petclinic
+      7 + |    apps.tanzu.vmware.com/workload-type: web
+      8 + |  name: spring-petclinic
+      9 + |  namespace: default
+     10 + |spec:
+     11 + |  build:
+     12 + |    env:
+     13 + |    - name: BP_MAVEN_POM_FILE
+     14 + |      value: skip-pom.xml
+     15 + |  env:
+     16 + |  - name: SPRING_PROFILES_ACTIVE
+     17 + |    value: mysql
+     18 + |  resources:
+     19 + |    limits:
+     20 + |      cpu: 500m
+     21 + |      memory: 1Gi
+     22 + |    requests:
+     23 + |      cpu: 100m
+     24 + |      memory: 1Gi
+     25 + |  source:
+     26 + |    git:
+     27 + |      ref:
+     28 + |        branch: main
+     29 + |      url: https://github.com/spring-projects/spring-petclinic.git
+
+Created workload "spring-petclinic"
+
+To see logs:   "tanzu apps workload tail spring-petclinic"
+To get status: "tanzu apps workload get spring-petclinic"
+
+`,
+		},
+		{
+			Name:        "fail to accept yaml file through stdin missing --yes flag",
+			Args:        []string{flags.FilePathFlagName, "-"},
+			ShouldError: true,
+		},
+		{
+			Name:        "filepath - missing",
+			Args:        []string{workloadName, flags.FilePathFlagName, "testdata/missing.yaml", flags.YesFlagName},
+			ShouldError: true,
+		},
+		{
+			Name: "noop",
+			Args: []string{workloadName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			ExpectOutput: `
+Workload is unchanged, skipping update
+`,
+		},
+		{
+			Name: "no source resource",
+			Args: []string{workloadName},
+			GivenObjects: []client.Object{
+				parent,
+			},
+		},
+		{
+			Name: "get failed",
+			Args: []string{workloadName},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("get", "Workload"),
+			},
+			ShouldError: true,
+		},
+		{
+			Name: "update - dry run",
+			Args: []string{workloadName, flags.DebugFlagName, flags.DryRunFlagName, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: "1970-01-01T00:00:01Z"
+  name: my-workload
+  namespace: default
+  resourceVersion: "999"
+spec:
+  image: ubuntu:bionic
+  params:
+  - name: debug
+    value: "true"
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name: "error during update",
+			Args: []string{workloadName, flags.DebugFlagName, flags.YesFlagName},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("update", "Workload"),
+			},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Image: "ubuntu:bionic",
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "debug",
+								Value: apiextensionsv1.JSON{Raw: []byte(`"true"`)},
+							},
+						},
+					},
+				},
+			},
+			ShouldError: true,
+		},
+		{
+			Name: "update with the default field manager",
+			Args: []string{workloadName, flags.DebugFlagName, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			Prepare: recordFieldManagers,
+			CleanUp: verifyFieldManagers(commands.DefaultFieldManager, commands.DefaultFieldManager),
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
+						Labels:    map[string]string{},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: "https://github.com/spring-projects/spring-petclinic.git",
-								Ref: cartov1alpha1.GitRef{
-									Branch: gitBranch,
-								},
+						Image: "ubuntu:bionic",
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "debug",
+								Value: apiextensionsv1.JSON{Raw: []byte(`"true"`)},
 							},
-							Subpath: "./app",
 						},
 					},
 				},
@@ -242,11 +3341,13 @@ Error: namespace "foo" not found, it may not exist or user does not have permiss
 			ExpectOutput: `
 Update workload:
 ...
-  9,  9   |    git:
- 10, 10   |      ref:
- 11, 11   |        branch: main
- 12, 12   |      url: https://github.com/spring-projects/spring-petclinic.git
-     13 + |    subPath: ./app
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8,  8   |  image: ubuntu:bionic
+      9 + |  params:
+     10 + |  - name: debug
+     11 + |    value: "true"
 
 Updated workload "my-workload"
 
@@ -256,45 +3357,46 @@ To get status: "tanzu apps workload get my-workload"
 `,
 		},
 		{
-			Name:         "Create git source with subPath from file",
-			Args:         []string{workloadName, flags.FilePathFlagName, "./testdata/workload-subPath.yaml", flags.YesFlagName},
-			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
+			Name: "update with a field manager",
+			Args: []string{workloadName, flags.DebugFlagName, flags.FieldManagerFlagName, "my-pipeline", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			Prepare: recordFieldManagers,
+			CleanUp: verifyFieldManagers("my-pipeline", "my-pipeline"),
+			ExpectUpdates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
+						Labels:    map[string]string{},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: "https://github.com/spring-projects/spring-petclinic.git",
-								Ref: cartov1alpha1.GitRef{
-									Branch: gitBranch,
-								},
+						Image: "ubuntu:bionic",
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "debug",
+								Value: apiextensionsv1.JSON{Raw: []byte(`"true"`)},
 							},
-							Subpath: "./app",
 						},
 					},
 				},
 			},
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  name: my-workload
-      6 + |  namespace: default
-      7 + |spec:
-      8 + |  source:
-      9 + |    git:
-     10 + |      ref:
-     11 + |        branch: main
-     12 + |      url: https://github.com/spring-projects/spring-petclinic.git
-     13 + |    subPath: ./app
+Update workload:
+...
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8,  8   |  image: ubuntu:bionic
+      9 + |  params:
+     10 + |  - name: debug
+     11 + |    value: "true"
 
-Created workload "my-workload"
+Updated workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
@@ -302,36 +3404,203 @@ To get status: "tanzu apps workload get my-workload"
 `,
 		},
 		{
-			Name:        "subPath with no source",
-			Args:        []string{workloadName, flags.SubPathFlagName, "./app", flags.YesFlagName},
+			Name: "conflict during update",
+			Args: []string{workloadName, flags.DebugFlagName, flags.YesFlagName, flags.ConflictRetriesFlagName, "0"},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("update", "Workload", clitesting.InduceFailureOpts{
+					Error: apierrs.NewConflict(schema.GroupResource{Group: "carto.run", Resource: "workloads"}, workloadName, fmt.Errorf("induced conflict")),
+				}),
+			},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Image: "ubuntu:bionic",
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "debug",
+								Value: apiextensionsv1.JSON{Raw: []byte(`"true"`)},
+							},
+						},
+					},
+				},
+			},
 			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeConflict),
+			ExpectOutput: `
+Update workload:
+...
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8,  8   |  image: ubuntu:bionic
+      9 + |  params:
+     10 + |  - name: debug
+     11 + |    value: "true"
+
+Error: conflict updating workload, the object was modified by another user; please run the update command again
+`,
 		},
 		{
-			Name: "wait with timeout error",
-			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.WaitFlagName, flags.WaitTimeoutFlagName, "1ns"},
+			Name: "conflict with another field manager",
+			Args: []string{workloadName, flags.ImageFlagName, "ubuntu:focal", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.ManagedFields(imageOwnedBy("kubectl"))
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeConflict),
+			ExpectOutput: `
+Error: fields of workload "my-workload" are owned by other field managers, use --force-conflicts to take them over
+  .spec.image: conflict with "kubectl"
+`,
+		},
+		{
+			Name: "force conflicts with another field manager",
+			Args: []string{workloadName, flags.ImageFlagName, "ubuntu:focal", flags.ForceConflictsFlagName, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.ManagedFields(imageOwnedBy("kubectl"))
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:focal")
+					}),
+			},
+			ExpectOutput: `
+Update workload:
+...
+  4,  4   |metadata:
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8     - |  image: ubuntu:bionic
+      8 + |  image: ubuntu:focal
+
+Updated workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name: "conflict during update applied to the latest workload",
+			Args: []string{workloadName, flags.DebugFlagName, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
-				workload := &cartov1alpha1.Workload{
+				config.Client = &conflictOnceClient{
+					Client: config.Client,
+					change: func(workload *cartov1alpha1.Workload) {
+						workload.Labels = map[string]string{"team": "payments"}
+					},
+				}
+				return ctx, nil
+			},
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
+						Labels:    map[string]string{"team": "payments"},
 					},
-					Status: cartov1alpha1.WorkloadStatus{
-						Conditions: []metav1.Condition{
+					Spec: cartov1alpha1.WorkloadSpec{
+						Image: "ubuntu:bionic",
+					},
+				},
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{"team": "payments"},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Image: "ubuntu:bionic",
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "debug",
+								Value: apiextensionsv1.JSON{Raw: []byte(`"true"`)},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Update workload:
+...
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8,  8   |  image: ubuntu:bionic
+      9 + |  params:
+     10 + |  - name: debug
+     11 + |    value: "true"
+
+Conflict updating workload "my-workload", applying the changes to its latest version (1/3)
+Updated workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name: "timeout during update with retry",
+			Args: []string{workloadName, flags.DebugFlagName, flags.YesFlagName, flags.RetryOnFlagName, "timeout", flags.RetryBackoffFlagName, "0s"},
+			WithReactors: []clitesting.ReactionFunc{
+				induceFailureOnce(clitesting.InduceFailure("update", "Workload", clitesting.InduceFailureOpts{
+					Error: apierrs.NewServerTimeout(schema.GroupResource{Group: "carto.run", Resource: "workloads"}, "patch", 0),
+				})),
+			},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Image: "ubuntu:bionic",
+						Params: []cartov1alpha1.Param{
 							{
-								Type:   cartov1alpha1.WorkloadConditionReady,
-								Status: metav1.ConditionTrue,
+								Name:  "debug",
+								Value: apiextensionsv1.JSON{Raw: []byte(`"true"`)},
 							},
 						},
 					},
-				}
-				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
-					{Type: watch.Modified, Object: workload},
-				})
-				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
-				return ctx, nil
-			},
-			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
+				},
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
@@ -339,45 +3608,54 @@ To get status: "tanzu apps workload get my-workload"
 						Labels:    map[string]string{},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: gitRepo,
-								Ref: cartov1alpha1.GitRef{
-									Branch: gitBranch,
-								},
+						Image: "ubuntu:bionic",
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "debug",
+								Value: apiextensionsv1.JSON{Raw: []byte(`"true"`)},
 							},
 						},
 					},
 				},
 			},
-			ShouldError: true,
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  name: my-workload
-      6 + |  namespace: default
-      7 + |spec:
-      8 + |  source:
-      9 + |    git:
-     10 + |      ref:
-     11 + |        branch: main
-     12 + |      url: https://example.com/repo.git
+Update workload:
+...
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8,  8   |  image: ubuntu:bionic
+      9 + |  params:
+     10 + |  - name: debug
+     11 + |    value: "true"
 
-Created workload "my-workload"
+Retrying in 0s after timeout error (1/3): The patch operation against workloads.carto.run could not be completed at this time, please try again.
+Update workload:
+...
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8,  8   |  image: ubuntu:bionic
+      9 + |  params:
+     10 + |  - name: debug
+     11 + |    value: "true"
+
+Updated workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
-Waiting for workload "my-workload" to become ready...
-Error: timeout after 1ns waiting for "my-workload" to become ready
 `,
 		},
 		{
-			Name: "successful wait for ready cond",
-			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.WaitFlagName},
+			Name: "update - wait error with timeout",
+			Args: []string{workloadName, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-db", flags.WaitFlagName, flags.YesFlagName, flags.WaitTimeoutFlagName, "1ns"},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
 				workload := &cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
@@ -399,53 +3677,62 @@ Error: timeout after 1ns waiting for "my-workload" to become ready
 				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
 				return ctx, nil
 			},
-			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
+			ExpectUpdates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
-						Labels:    map[string]string{},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: gitRepo,
-								Ref: cartov1alpha1.GitRef{
-									Branch: gitBranch,
+						Image: "ubuntu:bionic",
+						ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
+							{
+								Name: "database",
+								Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+									APIVersion: "services.tanzu.vmware.com/v1alpha1",
+									Kind:       "PostgreSQL",
+									Name:       "my-prod-db",
 								},
 							},
 						},
 					},
 				},
 			},
+			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeTimeout),
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  name: my-workload
-      6 + |  namespace: default
-      7 + |spec:
-      8 + |  source:
-      9 + |    git:
-     10 + |      ref:
-     11 + |        branch: main
-     12 + |      url: https://example.com/repo.git
+Update workload:
+...
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8,  8   |  image: ubuntu:bionic
+      9 + |  serviceClaims:
+     10 + |  - name: database
+     11 + |    ref:
+     12 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
+     13 + |      kind: PostgreSQL
+     14 + |      name: my-prod-db
 
-Created workload "my-workload"
+Updated workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
 Waiting for workload "my-workload" to become ready...
-Workload "my-workload" is ready
+Error: timeout after 1ns waiting for "my-workload" to become ready
 `,
 		},
 		{
-			Name: "tail while waiting for ready cond",
-			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.TailFlagName},
+			Name: "update - wait error for false condition",
+			Args: []string{workloadName, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-db", flags.WaitFlagName, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
 				workload := &cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
@@ -455,8 +3742,10 @@ Workload "my-workload" is ready
 					Status: cartov1alpha1.WorkloadStatus{
 						Conditions: []metav1.Condition{
 							{
-								Type:   cartov1alpha1.WorkloadConditionReady,
-								Status: metav1.ConditionTrue,
+								Type:    cartov1alpha1.WorkloadConditionReady,
+								Status:  metav1.ConditionFalse,
+								Reason:  "OopsieDoodle",
+								Message: "a hopefully informative message about what went wrong",
 							},
 						},
 					},
@@ -465,125 +3754,64 @@ Workload "my-workload" is ready
 					{Type: watch.Modified, Object: workload},
 				})
 				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
-
-				tailer := &logs.FakeTailer{}
-				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
-				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, false).Return(nil).Once()
-				ctx = logs.StashTailer(ctx, tailer)
-
 				return ctx, nil
 			},
-			GivenObjects: givenNamespaceDefault,
-			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
-				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
-				tailer.AssertExpectations(t)
-				return nil
-			},
-			ExpectCreates: []client.Object{
+			ExpectUpdates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
-						Labels:    map[string]string{},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: gitRepo,
-								Ref: cartov1alpha1.GitRef{
-									Branch: gitBranch,
+						Image: "ubuntu:bionic",
+						ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
+							{
+								Name: "database",
+								Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+									APIVersion: "services.tanzu.vmware.com/v1alpha1",
+									Kind:       "PostgreSQL",
+									Name:       "my-prod-db",
 								},
 							},
 						},
 					},
 				},
 			},
+			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeFailedCondition),
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  name: my-workload
-      6 + |  namespace: default
-      7 + |spec:
-      8 + |  source:
-      9 + |    git:
-     10 + |      ref:
-     11 + |        branch: main
-     12 + |      url: https://example.com/repo.git
+Update workload:
+...
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8,  8   |  image: ubuntu:bionic
+      9 + |  serviceClaims:
+     10 + |  - name: database
+     11 + |    ref:
+     12 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
+     13 + |      kind: PostgreSQL
+     14 + |      name: my-prod-db
 
-Created workload "my-workload"
+Updated workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
 Waiting for workload "my-workload" to become ready...
-...tail output...
-Workload "my-workload" is ready
+  Ready=False (OopsieDoodle)
+Error: Failed to become ready: a hopefully informative message about what went wrong
 `,
 		},
 		{
-			Name: "error during create",
-			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
-			WithReactors: []clitesting.ReactionFunc{
-				clitesting.InduceFailure("create", "Workload"),
-			},
-			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
-				&cartov1alpha1.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
-						Name:      workloadName,
-						Labels:    map[string]string{},
-					},
-					Spec: cartov1alpha1.WorkloadSpec{
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: gitRepo,
-								Ref: cartov1alpha1.GitRef{
-									Branch: gitBranch,
-								},
-							},
-						},
-					},
-				},
-			},
-			ShouldError: true,
-		},
-		{
-			Name: "watcher error",
-			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.WaitFlagName},
-			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
-				fakewatch := watchfakes.NewFakeWithWatch(true, config.Client, []watch.Event{})
-				ctx = watchhelper.WithWatcher(ctx, fakewatch)
-				return ctx, nil
-			},
-			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
-				&cartov1alpha1.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
-						Name:      workloadName,
-						Labels:    map[string]string{},
-					},
-					Spec: cartov1alpha1.WorkloadSpec{
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: gitRepo,
-								Ref: cartov1alpha1.GitRef{
-									Branch: gitBranch,
-								},
-							},
-						},
-					},
-				},
-			},
-			ShouldError: true,
-		},
-		{
-			Name: "create - wait error for false condition",
-			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LabelFlagName, "apps.tanzu.vmware.com/workload-type=web", flags.LabelFlagName, "apps.tanzu.vmware.com/workload-type-", flags.YesFlagName, flags.WaitFlagName},
+			Name: "update - successful wait for ready condition",
+			Args: []string{workloadName, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-db", flags.WaitFlagName, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
 				workload := &cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
@@ -593,10 +3821,8 @@ Workload "my-workload" is ready
 					Status: cartov1alpha1.WorkloadStatus{
 						Conditions: []metav1.Condition{
 							{
-								Type:    cartov1alpha1.WorkloadConditionReady,
-								Status:  metav1.ConditionFalse,
-								Reason:  "OopsieDoodle",
-								Message: "a hopefully informative message about what went wrong",
+								Type:   cartov1alpha1.WorkloadConditionReady,
+								Status: metav1.ConditionTrue,
 							},
 						},
 					},
@@ -607,263 +3833,246 @@ Workload "my-workload" is ready
 				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
 				return ctx, nil
 			},
-			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
+			ExpectUpdates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
-						Labels:    map[string]string{},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: gitRepo,
-								Ref: cartov1alpha1.GitRef{
-									Branch: gitBranch,
+						Image: "ubuntu:bionic",
+						ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
+							{
+								Name: "database",
+								Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+									APIVersion: "services.tanzu.vmware.com/v1alpha1",
+									Kind:       "PostgreSQL",
+									Name:       "my-prod-db",
 								},
 							},
 						},
 					},
 				},
 			},
-			ShouldError: true,
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  name: my-workload
-      6 + |  namespace: default
-      7 + |spec:
-      8 + |  source:
-      9 + |    git:
-     10 + |      ref:
-     11 + |        branch: main
-     12 + |      url: https://example.com/repo.git
+Update workload:
+...
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8,  8   |  image: ubuntu:bionic
+      9 + |  serviceClaims:
+     10 + |  - name: database
+     11 + |    ref:
+     12 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
+     13 + |      kind: PostgreSQL
+     14 + |      name: my-prod-db
 
-Created workload "my-workload"
+Updated workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
 Waiting for workload "my-workload" to become ready...
-Error: Failed to become ready: a hopefully informative message about what went wrong
+  Ready=True
+Workload "my-workload" is ready
+
+Latency
+   ready:   0s
 `,
 		},
 		{
-			Name:         "filepath",
-			Args:         []string{flags.FilePathFlagName, "testdata/workload.yaml", flags.YesFlagName},
-			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
+			Name: "update - latency while waiting for ready condition",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.WaitFlagName, flags.OutputFlagName, "json", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				now := time.Date(2022, time.June, 9, 15, 12, 0, 0, time.UTC)
+				ctx = commands.StashClock(ctx, func() time.Time {
+					defer func() { now = now.Add(10 * time.Second) }()
+					return now
+				})
+				workload := func(conditions []metav1.Condition, resources ...cartov1alpha1.RealizedResource) *cartov1alpha1.Workload {
+					return &cartov1alpha1.Workload{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: defaultNamespace,
+							Name:      workloadName,
+						},
+						Status: cartov1alpha1.WorkloadStatus{
+							Conditions: conditions,
+							Resources:  resources,
+						},
+					}
+				}
+				source := cartov1alpha1.RealizedResource{
+					Name:    "source-provider",
+					Outputs: []cartov1alpha1.Output{{Name: "url", Digest: "sha256:source-url"}, {Name: "revision", Digest: "sha256:source-revision"}},
+				}
+				image := cartov1alpha1.RealizedResource{
+					Name:    "image-builder",
+					Outputs: []cartov1alpha1.Output{{Name: "image", Digest: "sha256:image"}},
+				}
+				ready := []metav1.Condition{{Type: cartov1alpha1.WorkloadConditionReady, Status: metav1.ConditionTrue}}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload(nil, source)},
+					{Type: watch.Modified, Object: workload(nil, source, image)},
+					{Type: watch.Modified, Object: workload(ready, source, image)},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+				return ctx, nil
+			},
+			ExpectUpdates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
-						Name:      "spring-petclinic",
-						Labels: map[string]string{
-							apis.AppPartOfLabelName:               "spring-petclinic",
-							"apps.tanzu.vmware.com/workload-type": "web",
-						},
+						Name:      workloadName,
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
 						Source: &cartov1alpha1.Source{
 							Git: &cartov1alpha1.GitSource{
-								URL: "https://github.com/spring-projects/spring-petclinic.git",
+								URL: gitRepo,
 								Ref: cartov1alpha1.GitRef{
-									Branch: "main",
+									Branch: gitBranch,
 								},
 							},
 						},
-						Env: []corev1.EnvVar{
-							{
-								Name:  "SPRING_PROFILES_ACTIVE",
-								Value: "mysql",
-							},
-						},
-						Resources: &corev1.ResourceRequirements{
-							Limits: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("500m"),
-								corev1.ResourceMemory: resource.MustParse("1Gi"),
-							},
-							Requests: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("100m"),
-								corev1.ResourceMemory: resource.MustParse("1Gi"),
-							},
-						},
 					},
 				},
 			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, workloadName, `{"metadata":{"resourceVersion":"999"},"spec":{"image":null}}`),
+			},
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  labels:
-      6 + |    app.kubernetes.io/part-of: spring-petclinic
-      7 + |    apps.tanzu.vmware.com/workload-type: web
-      8 + |  name: spring-petclinic
-      9 + |  namespace: default
-     10 + |spec:
-     11 + |  env:
-     12 + |  - name: SPRING_PROFILES_ACTIVE
-     13 + |    value: mysql
-     14 + |  resources:
-     15 + |    limits:
-     16 + |      cpu: 500m
-     17 + |      memory: 1Gi
-     18 + |    requests:
-     19 + |      cpu: 100m
-     20 + |      memory: 1Gi
-     21 + |  source:
-     22 + |    git:
-     23 + |      ref:
-     24 + |        branch: main
-     25 + |      url: https://github.com/spring-projects/spring-petclinic.git
+Update workload:
+...
+  4,  4   |metadata:
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8     - |  image: ubuntu:bionic
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
 
-Created workload "spring-petclinic"
+Updated workload "my-workload"
 
-To see logs:   "tanzu apps workload tail spring-petclinic"
-To get status: "tanzu apps workload get spring-petclinic"
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to become ready...
+  Ready=True
+{"phase":"condition","workload":"my-workload","type":"Ready","status":"True"}
+Workload "my-workload" is ready
 
+Latency
+   source resolved:   10s
+   image built:       20s
+   ready:             30s
+{"phase":"ready","workload":"my-workload","sourceResolvedSeconds":10,"imageBuiltSeconds":20,"readySeconds":30}
 `,
 		},
 		{
-			Name: "create - accept yaml file through stdin - using --yes flag",
-			Args: []string{flags.FilePathFlagName, "-", flags.YesFlagName},
-			Stdin: []byte(`
-apiVersion: carto.run/v1alpha1
-kind: Workload
-metadata:
-  name: spring-petclinic
-  labels:
-    app.kubernetes.io/part-of: spring-petclinic
-    apps.tanzu.vmware.com/workload-type: web
-spec:
-  env:
-  - name: SPRING_PROFILES_ACTIVE
-    value: mysql
-  resources:
-    requests:
-      memory: 1Gi
-      cpu: 100m
-    limits:
-      memory: 1Gi
-      cpu: 500m
-  source:
-    git:
-      url: https://github.com/spring-projects/spring-petclinic.git
-      ref:
-        branch: main
-`),
-			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
-				&cartov1alpha1.Workload{
+			Name: "update - tail with timestamp while waiting for ready condition",
+			Args: []string{workloadName, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-db", flags.YesFlagName, flags.TailTimestampFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				workload := &cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
-						Name:      "spring-petclinic",
-						Labels: map[string]string{
-							apis.AppPartOfLabelName:               "spring-petclinic",
-							"apps.tanzu.vmware.com/workload-type": "web",
-						},
+						Name:      workloadName,
 					},
-					Spec: cartov1alpha1.WorkloadSpec{
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: "https://github.com/spring-projects/spring-petclinic.git",
-								Ref: cartov1alpha1.GitRef{
-									Branch: "main",
-								},
-							},
-						},
-						Env: []corev1.EnvVar{
+					Status: cartov1alpha1.WorkloadStatus{
+						Conditions: []metav1.Condition{
 							{
-								Name:  "SPRING_PROFILES_ACTIVE",
-								Value: "mysql",
+								Type:   cartov1alpha1.WorkloadConditionReady,
+								Status: metav1.ConditionTrue,
 							},
 						},
-						Resources: &corev1.ResourceRequirements{
-							Limits: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("500m"),
-								corev1.ResourceMemory: resource.MustParse("1Gi"),
-							},
-							Requests: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("100m"),
-								corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, true).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Image: "ubuntu:bionic",
+						ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
+							{
+								Name: "database",
+								Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+									APIVersion: "services.tanzu.vmware.com/v1alpha1",
+									Kind:       "PostgreSQL",
+									Name:       "my-prod-db",
+								},
 							},
 						},
 					},
 				},
 			},
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  labels:
-      6 + |    app.kubernetes.io/part-of: spring-petclinic
-      7 + |    apps.tanzu.vmware.com/workload-type: web
-      8 + |  name: spring-petclinic
-      9 + |  namespace: default
-     10 + |spec:
-     11 + |  env:
-     12 + |  - name: SPRING_PROFILES_ACTIVE
-     13 + |    value: mysql
-     14 + |  resources:
-     15 + |    limits:
-     16 + |      cpu: 500m
-     17 + |      memory: 1Gi
-     18 + |    requests:
-     19 + |      cpu: 100m
-     20 + |      memory: 1Gi
-     21 + |  source:
-     22 + |    git:
-     23 + |      ref:
-     24 + |        branch: main
-     25 + |      url: https://github.com/spring-projects/spring-petclinic.git
+Update workload:
+...
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8,  8   |  image: ubuntu:bionic
+      9 + |  serviceClaims:
+     10 + |  - name: database
+     11 + |    ref:
+     12 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
+     13 + |      kind: PostgreSQL
+     14 + |      name: my-prod-db
 
-Created workload "spring-petclinic"
+Updated workload "my-workload"
 
-To see logs:   "tanzu apps workload tail spring-petclinic"
-To get status: "tanzu apps workload get spring-petclinic"
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to become ready...
+...tail output...
+Workload "my-workload" is ready
 
+Latency
+   ready:   0s
 `,
 		},
 		{
-			Name: "update - accept yaml file through stdin - using --yes flag",
-			Args: []string{flags.FilePathFlagName, "-", flags.YesFlagName},
-			Stdin: []byte(`
-apiVersion: carto.run/v1alpha1
-kind: Workload
-metadata:
-  name: spring-petclinic
-  labels:
-    app.kubernetes.io/part-of: spring-petclinic
-    apps.tanzu.vmware.com/workload-type: web
-spec:
-  env:
-  - name: SPRING_PROFILES_ACTIVE
-    value: mysql
-  resources:
-    requests:
-      memory: 1Gi
-      cpu: 100m
-    limits:
-      memory: 1Gi
-      cpu: 500m
-  source:
-    git:
-      url: https://github.com/spring-projects/spring-petclinic.git
-      ref:
-        branch: main
-`),
+			Name: "update - filepath",
+			Args: []string{flags.FilePathFlagName, "testdata/workload.yaml", flags.SubPathFlagName, "./cmd", flags.YesFlagName},
 			GivenObjects: []client.Object{
-				diecartov1alpha1.WorkloadBlank.
+				parent.
 					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
 						d.Name("spring-petclinic")
-						d.Namespace(defaultNamespace)
 						d.AddLabel("preserve-me", "should-exist")
 					}).
 					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
@@ -875,7 +4084,6 @@ spec:
 							},
 						)
 					}),
-				givenNamespaceDefault[0],
 			},
 			ExpectUpdates: []client.Object{
 				&cartov1alpha1.Workload{
@@ -896,6 +4104,7 @@ spec:
 									Branch: "main",
 								},
 							},
+							Subpath: "./cmd",
 						},
 						Env: []corev1.EnvVar{
 							{
@@ -920,6 +4129,9 @@ spec:
 					},
 				},
 			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, "spring-petclinic", `{"metadata":{"resourceVersion":"999"},"spec":{"image":null}}`),
+			},
 			ExpectOutput: `
 Update workload:
 ...
@@ -951,6 +4163,7 @@ Update workload:
      26 + |      ref:
      27 + |        branch: main
      28 + |      url: https://github.com/spring-projects/spring-petclinic.git
+     29 + |    subPath: ./cmd
 
 Updated workload "spring-petclinic"
 
@@ -960,68 +4173,36 @@ To get status: "tanzu apps workload get spring-petclinic"
 `,
 		},
 		{
-			Name: "update - accept yaml file through stdin - using --dry-run flag",
-			Args: []string{flags.FilePathFlagName, "-", flags.DryRunFlagName},
+			Name: "update - filepath - custom namespace and name",
+			Args: []string{workloadName, flags.NamespaceFlagName, "test-namespace", flags.FilePathFlagName, "testdata/workload.yaml", flags.YesFlagName},
 			GivenObjects: []client.Object{
 				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Namespace("test-namespace")
+						d.AddLabel("preserve-me", "should-exist")
+					}).
 					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
 						d.Image("ubuntu:bionic")
+						d.Env(
+							corev1.EnvVar{
+								Name:  "OVERRIDE_VAR",
+								Value: "doesnt matter",
+							},
+						)
 					}),
 			},
-			Stdin: []byte(`
----
-apiVersion: carto.run/v1alpha1
-kind: Workload
-metadata:
-  creationTimestamp: null
-  name: my-workload
-  namespace: default
-  resourceVersion: "999"
-spec:
-  image: ubuntu:bionic
-status:
-  supplyChainRef: {}
-`),
-		},
-		{
-			Name:         "create - accept yaml file through stdin - using --dry-run flag",
-			Args:         []string{flags.FilePathFlagName, "-", flags.DryRunFlagName},
-			GivenObjects: givenNamespaceDefault,
-			Stdin: []byte(`
-apiVersion: carto.run/v1alpha1
-kind: Workload
-metadata:
-  creationTimestamp: null
-  name: my-workload
-  namespace: default
-spec:
-  source:
-    git:
-      ref:
-        branch: main
-      url: https://example.com/repo.git
-status:
-  supplyChainRef: {}
-`),
-		},
-		{
-			Name:         "filepath - service account build-env",
-			Args:         []string{flags.FilePathFlagName, "testdata/workload-build-env.yaml", flags.YesFlagName},
-			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
+			ExpectUpdates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
-						Name:      "spring-petclinic",
+						Namespace: "test-namespace",
+						Name:      workloadName,
 						Labels: map[string]string{
-							apis.AppPartOfLabelName:               "spring-petclinic",
+							"preserve-me":                         "should-exist",
+							"app.kubernetes.io/part-of":           "spring-petclinic",
 							"apps.tanzu.vmware.com/workload-type": "web",
 						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Build: &cartov1alpha1.WorkloadBuild{Env: []corev1.EnvVar{
-							{Name: "BP_MAVEN_POM_FILE", Value: "skip-pom.xml"},
-						}},
 						Source: &cartov1alpha1.Source{
 							Git: &cartov1alpha1.GitSource{
 								URL: "https://github.com/spring-projects/spring-petclinic.git",
@@ -1031,6 +4212,10 @@ status:
 							},
 						},
 						Env: []corev1.EnvVar{
+							{
+								Name:  "OVERRIDE_VAR",
+								Value: "doesnt matter",
+							},
 							{
 								Name:  "SPRING_PROFILES_ACTIVE",
 								Value: "mysql",
@@ -1049,116 +4234,69 @@ status:
 					},
 				},
 			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal("test-namespace", workloadName, `{"metadata":{"resourceVersion":"999"},"spec":{"image":null}}`),
+			},
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  labels:
+Update workload:
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
       6 + |    app.kubernetes.io/part-of: spring-petclinic
       7 + |    apps.tanzu.vmware.com/workload-type: web
-      8 + |  name: spring-petclinic
-      9 + |  namespace: default
-     10 + |spec:
-     11 + |  build:
-     12 + |    env:
-     13 + |    - name: BP_MAVEN_POM_FILE
-     14 + |      value: skip-pom.xml
-     15 + |  env:
-     16 + |  - name: SPRING_PROFILES_ACTIVE
-     17 + |    value: mysql
-     18 + |  resources:
-     19 + |    limits:
-     20 + |      cpu: 500m
-     21 + |      memory: 1Gi
-     22 + |    requests:
-     23 + |      cpu: 100m
-     24 + |      memory: 1Gi
-     25 + |  source:
-     26 + |    git:
-     27 + |      ref:
-     28 + |        branch: main
-     29 + |      url: https://github.com/spring-projects/spring-petclinic.git
+  6,  8   |    preserve-me: should-exist
+  7,  9   |  name: my-workload
+  8, 10   |  namespace: test-namespace
+  9, 11   |spec:
+ 10, 12   |  env:
+ 11, 13   |  - name: OVERRIDE_VAR
+ 12, 14   |    value: doesnt matter
+ 13     - |  image: ubuntu:bionic
+     15 + |  - name: SPRING_PROFILES_ACTIVE
+     16 + |    value: mysql
+     17 + |  resources:
+     18 + |    limits:
+     19 + |      cpu: 500m
+     20 + |      memory: 1Gi
+     21 + |    requests:
+     22 + |      cpu: 100m
+     23 + |      memory: 1Gi
+     24 + |  source:
+     25 + |    git:
+     26 + |      ref:
+     27 + |        branch: main
+     28 + |      url: https://github.com/spring-projects/spring-petclinic.git
 
-Created workload "spring-petclinic"
+Updated workload "my-workload"
 
-To see logs:   "tanzu apps workload tail spring-petclinic"
-To get status: "tanzu apps workload get spring-petclinic"
+To see logs:   "tanzu apps workload tail my-workload --namespace test-namespace"
+To get status: "tanzu apps workload get my-workload --namespace test-namespace"
 
 `,
 		},
 		{
-			Name:        "fail to accept yaml file through stdin missing --yes flag",
-			Args:        []string{flags.FilePathFlagName, "-"},
-			ShouldError: true,
-		},
-		{
-			Name:        "filepath - missing",
-			Args:        []string{workloadName, flags.FilePathFlagName, "testdata/missing.yaml", flags.YesFlagName},
-			ShouldError: true,
-		},
-		{
-			Name: "noop",
-			Args: []string{workloadName},
-			GivenObjects: []client.Object{
-				parent.
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Image("ubuntu:bionic")
-					}),
-			},
-			ExpectOutput: `
-Workload is unchanged, skipping update
-`,
-		},
-		{
-			Name: "no source resource",
-			Args: []string{workloadName},
-			GivenObjects: []client.Object{
-				parent,
+			Name:         "local path - missing fields",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LocalPathFlagName, "testdata/local-source", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				if expected, actual := false, cmd.SilenceUsage; expected != actual {
+					t.Errorf("expected cmd.SilenceUsage to be %t, actually %t", expected, actual)
+				}
+
+				return nil
 			},
 		},
 		{
-			Name: "get failed",
-			Args: []string{workloadName},
-			WithReactors: []clitesting.ReactionFunc{
-				clitesting.InduceFailure("get", "Workload"),
-			},
+			Name:        "filepath invalid name",
+			Args:        []string{flags.FilePathFlagName, "testdata/workload-invalid-name.yaml", flags.YesFlagName},
 			ShouldError: true,
 		},
 		{
-			Name: "update - dry run",
-			Args: []string{workloadName, flags.DebugFlagName, flags.DryRunFlagName, flags.YesFlagName},
-			GivenObjects: []client.Object{
-				parent.
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Image("ubuntu:bionic")
-					}),
-			},
-			ExpectOutput: `
----
-apiVersion: carto.run/v1alpha1
-kind: Workload
-metadata:
-  creationTimestamp: "1970-01-01T00:00:01Z"
-  name: my-workload
-  namespace: default
-  resourceVersion: "999"
-spec:
-  image: ubuntu:bionic
-  params:
-  - name: debug
-    value: "true"
-status:
-  supplyChainRef: {}
-`,
-		},
-		{
-			Name: "error during update",
-			Args: []string{workloadName, flags.DebugFlagName, flags.YesFlagName},
-			WithReactors: []clitesting.ReactionFunc{
-				clitesting.InduceFailure("update", "Workload"),
-			},
+			Name: "update - serviceclaim with deprecation warning",
+			Args: []string{workloadName, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-ns:my-prod-db", flags.YesFlagName},
 			GivenObjects: []client.Object{
 				parent.
 					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
@@ -1170,108 +4308,250 @@ status:
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
-						Labels:    map[string]string{},
+						Annotations: map[string]string{
+							apis.ServiceClaimAnnotationName: `{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"database":{"namespace":"my-prod-ns"}}}}`,
+						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
 						Image: "ubuntu:bionic",
-						Params: []cartov1alpha1.Param{
+						ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
 							{
-								Name:  "debug",
-								Value: apiextensionsv1.JSON{Raw: []byte(`"true"`)},
+								Name: "database",
+								Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+									APIVersion: "services.tanzu.vmware.com/v1alpha1",
+									Kind:       "PostgreSQL",
+									Name:       "my-prod-db",
+								},
 							},
 						},
 					},
 				},
 			},
-			ShouldError: true,
+			ExpectOutput: `
+WARNING: Cross namespace service claims are deprecated. Please use ` + "`tanzu service claim create`" + ` instead.
+Update workload:
+  1,  1   |---
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+      5 + |  annotations:
+      6 + |    serviceclaims.supplychain.apps.x-tanzu.vmware.com/extensions: '{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"database":{"namespace":"my-prod-ns"}}}}'
+  5,  7   |  name: my-workload
+  6,  8   |  namespace: default
+  7,  9   |spec:
+  8, 10   |  image: ubuntu:bionic
+     11 + |  serviceClaims:
+     12 + |  - name: database
+     13 + |    ref:
+     14 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
+     15 + |      kind: PostgreSQL
+     16 + |      name: my-prod-db
+
+Updated workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
 		},
 		{
-			Name: "conflict during update",
-			Args: []string{workloadName, flags.DebugFlagName, flags.YesFlagName},
-			WithReactors: []clitesting.ReactionFunc{
-				clitesting.InduceFailure("update", "Workload", clitesting.InduceFailureOpts{
-					Error: apierrs.NewConflict(schema.GroupResource{Group: "carto.run", Resource: "workloads"}, workloadName, fmt.Errorf("induced conflict")),
-				}),
-			},
+			Name: "update - replace service claims from file",
+			Args: []string{flags.FilePathFlagName, "testdata/workload-service-ref.yaml", flags.ReplaceClaimsFlagName, flags.YesFlagName},
 			GivenObjects: []client.Object{
-				parent.
+				serviceClaimsParent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.ServiceClaimAnnotationName, `{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"cache":{"namespace":"cache-ns"}}}}`)
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				serviceClaimsParent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Annotations(map[string]string{})
+					}).
 					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Image("ubuntu:bionic")
+						d.ServiceClaims(stubDatabaseClaim)
 					}),
 			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, "service", `{"metadata":{"annotations":null,"resourceVersion":"999"}}`),
+			},
+			ExpectOutput: `
+Update workload:
+  1,  1   |---
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5     - |  annotations:
+  6     - |    serviceclaims.supplychain.apps.x-tanzu.vmware.com/extensions: '{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"cache":{"namespace":"cache-ns"}}}}'
+  7,  5   |  name: service
+  8,  6   |  namespace: default
+  9,  7   |spec:
+ 10,  8   |  serviceClaims:
+ 11,  9   |  - name: database
+ 12     - |    ref:
+ 13     - |      apiVersion: services.tanzu.vmware.com/v1alpha1
+ 14     - |      kind: PostgreSQL
+ 15     - |      name: old-db
+ 16     - |  - name: cache
+ 17, 10   |    ref:
+ 18, 11   |      apiVersion: services.tanzu.vmware.com/v1alpha1
+ 19     - |      kind: Redis
+ 20     - |      name: my-cache
+     12 + |      kind: Secret
+     13 + |      name: stub-db
+ 21, 14   |  source:
+ 22, 15   |    git:
+ 23, 16   |      ref:
+ 24, 17   |        branch: main
+...
+
+Updated workload "service"
+
+To see logs:   "tanzu apps workload tail service"
+To get status: "tanzu apps workload get service"
+
+`,
+		},
+		{
+			Name:         "update - service claims missing from file are kept",
+			Args:         []string{flags.FilePathFlagName, "testdata/workload-service-ref.yaml", flags.YesFlagName},
+			GivenObjects: []client.Object{serviceClaimsParent},
 			ExpectUpdates: []client.Object{
-				&cartov1alpha1.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
-						Name:      workloadName,
-						Labels:    map[string]string{},
-					},
-					Spec: cartov1alpha1.WorkloadSpec{
-						Image: "ubuntu:bionic",
-						Params: []cartov1alpha1.Param{
-							{
-								Name:  "debug",
-								Value: apiextensionsv1.JSON{Raw: []byte(`"true"`)},
-							},
-						},
-					},
-				},
+				serviceClaimsParent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.ServiceClaims(stubDatabaseClaim, cacheClaim)
+					}),
 			},
-			ShouldError: true,
 			ExpectOutput: `
 Update workload:
 ...
-  5,  5   |  name: my-workload
+  8,  8   |  serviceClaims:
+  9,  9   |  - name: database
+ 10, 10   |    ref:
+ 11, 11   |      apiVersion: services.tanzu.vmware.com/v1alpha1
+ 12     - |      kind: PostgreSQL
+ 13     - |      name: old-db
+     12 + |      kind: Secret
+     13 + |      name: stub-db
+ 14, 14   |  - name: cache
+ 15, 15   |    ref:
+ 16, 16   |      apiVersion: services.tanzu.vmware.com/v1alpha1
+ 17, 17   |      kind: Redis
+...
+
+Updated workload "service"
+
+To see logs:   "tanzu apps workload tail service"
+To get status: "tanzu apps workload get service"
+
+`,
+		},
+		{
+			Name: "update - replace service claims from file without claims",
+			Args: []string{flags.FilePathFlagName, "-", flags.ReplaceClaimsFlagName, flags.YesFlagName},
+			Stdin: []byte(`
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: service
+spec:
+  source:
+    git:
+      url: https://github.com/spring-projects/spring-petclinic.git
+      ref:
+        branch: main
+`),
+			GivenObjects: []client.Object{serviceClaimsParent},
+			ExpectUpdates: []client.Object{
+				serviceClaimsParent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.ServiceClaims()
+					}),
+			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, "service", `{"metadata":{"resourceVersion":"999"},"spec":{"serviceClaims":null}}`),
+			},
+			ExpectOutput: `
+Update workload:
+...
+  4,  4   |metadata:
+  5,  5   |  name: service
   6,  6   |  namespace: default
   7,  7   |spec:
-  8,  8   |  image: ubuntu:bionic
-      9 + |  params:
-     10 + |  - name: debug
-     11 + |    value: "true"
+  8     - |  serviceClaims:
+  9     - |  - name: database
+ 10     - |    ref:
+ 11     - |      apiVersion: services.tanzu.vmware.com/v1alpha1
+ 12     - |      kind: PostgreSQL
+ 13     - |      name: old-db
+ 14     - |  - name: cache
+ 15     - |    ref:
+ 16     - |      apiVersion: services.tanzu.vmware.com/v1alpha1
+ 17     - |      kind: Redis
+ 18     - |      name: my-cache
+ 19,  8   |  source:
+ 20,  9   |    git:
+ 21, 10   |      ref:
+ 22, 11   |        branch: main
+...
+
+Updated workload "service"
+
+To see logs:   "tanzu apps workload tail service"
+To get status: "tanzu apps workload get service"
 
-Error: conflict updating workload, the object was modified by another user; please run the update command again
 `,
 		},
 		{
-			Name: "update - wait error with timeout",
-			Args: []string{workloadName, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-db", flags.WaitFlagName, flags.YesFlagName, flags.WaitTimeoutFlagName, "1ns"},
+			Name:         "create - replace service claims from file",
+			Args:         []string{flags.FilePathFlagName, "testdata/workload-service-ref.yaml", flags.ReplaceClaimsFlagName, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				serviceClaimsParent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.ServiceClaims(stubDatabaseClaim)
+					}),
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: service
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  serviceClaims:
+      9 + |  - name: database
+     10 + |    ref:
+     11 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
+     12 + |      kind: Secret
+     13 + |      name: stub-db
+     14 + |  source:
+     15 + |    git:
+     16 + |      ref:
+     17 + |        branch: main
+     18 + |      url: https://github.com/spring-projects/spring-petclinic.git
+
+Created workload "service"
+
+To see logs:   "tanzu apps workload tail service"
+To get status: "tanzu apps workload get service"
+
+`,
+		},
+		{
+			Name: "update - remove service ref",
+			Args: []string{workloadName, flags.ServiceRefFlagName, "database-", flags.YesFlagName},
 			GivenObjects: []client.Object{
 				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.ServiceClaimAnnotationName, `{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"database":{"namespace":"my-prod-ns"}}}}`)
+					}).
 					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
 						d.Image("ubuntu:bionic")
-					}),
-			},
-			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
-				workload := &cartov1alpha1.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
-						Name:      workloadName,
-					},
-					Status: cartov1alpha1.WorkloadStatus{
-						Conditions: []metav1.Condition{
-							{
-								Type:   cartov1alpha1.WorkloadConditionReady,
-								Status: metav1.ConditionTrue,
-							},
-						},
-					},
-				}
-				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
-					{Type: watch.Modified, Object: workload},
-				})
-				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
-				return ctx, nil
-			},
-			ExpectUpdates: []client.Object{
-				&cartov1alpha1.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
-						Name:      workloadName,
-					},
-					Spec: cartov1alpha1.WorkloadSpec{
-						Image: "ubuntu:bionic",
-						ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
-							{
+						d.ServiceClaims(
+							cartov1alpha1.WorkloadServiceClaim{
 								Name: "database",
 								Ref: &cartov1alpha1.WorkloadServiceClaimReference{
 									APIVersion: "services.tanzu.vmware.com/v1alpha1",
@@ -1279,75 +4559,71 @@ Error: conflict updating workload, the object was modified by another user; plea
 									Name:       "my-prod-db",
 								},
 							},
-						},
-					},
-				},
+							cacheClaim,
+						)
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Annotations(map[string]string{})
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+						d.ServiceClaims(cacheClaim)
+					}),
+			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, workloadName, `{"metadata":{"annotations":null,"resourceVersion":"999"}}`),
 			},
-			ShouldError: true,
 			ExpectOutput: `
 Update workload:
-...
-  5,  5   |  name: my-workload
-  6,  6   |  namespace: default
-  7,  7   |spec:
-  8,  8   |  image: ubuntu:bionic
-      9 + |  serviceClaims:
-     10 + |  - name: database
-     11 + |    ref:
-     12 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
-     13 + |      kind: PostgreSQL
-     14 + |      name: my-prod-db
+  1,  1   |---
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5     - |  annotations:
+  6     - |    serviceclaims.supplychain.apps.x-tanzu.vmware.com/extensions: '{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"database":{"namespace":"my-prod-ns"}}}}'
+  7,  5   |  name: my-workload
+  8,  6   |  namespace: default
+  9,  7   |spec:
+ 10,  8   |  image: ubuntu:bionic
+ 11,  9   |  serviceClaims:
+ 12     - |  - name: database
+ 13     - |    ref:
+ 14     - |      apiVersion: services.tanzu.vmware.com/v1alpha1
+ 15     - |      kind: PostgreSQL
+ 16     - |      name: my-prod-db
+ 17, 10   |  - name: cache
+ 18, 11   |    ref:
+ 19, 12   |      apiVersion: services.tanzu.vmware.com/v1alpha1
+ 20, 13   |      kind: Redis
+...
 
 Updated workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
-Waiting for workload "my-workload" to become ready...
-Error: timeout after 1ns waiting for "my-workload" to become ready
 `,
 		},
 		{
-			Name: "update - wait error for false condition",
-			Args: []string{workloadName, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-db", flags.WaitFlagName, flags.YesFlagName},
-			GivenObjects: []client.Object{
-				parent.
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Image("ubuntu:bionic")
-					}),
-			},
-
-			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
-				workload := &cartov1alpha1.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
-						Name:      workloadName,
-					},
-					Status: cartov1alpha1.WorkloadStatus{
-						Conditions: []metav1.Condition{
-							{
-								Type:    cartov1alpha1.WorkloadConditionReady,
-								Status:  metav1.ConditionFalse,
-								Reason:  "OopsieDoodle",
-								Message: "a hopefully informative message about what went wrong",
-							},
-						},
-					},
-				}
-				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
-					{Type: watch.Modified, Object: workload},
-				})
-				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
-				return ctx, nil
-			},
-			ExpectUpdates: []client.Object{
+			Name:         "create - serviceclaim with deprecation warning",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-ns:my-prod-db", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
+						Annotations: map[string]string{
+							apis.ServiceClaimAnnotationName: `{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"database":{"namespace":"my-prod-ns"}}}}`,
+						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Image: "ubuntu:bionic",
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{URL: "https://example.com/repo.git", Ref: cartov1alpha1.GitRef{Branch: "main"}},
+						},
 						ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
 							{
 								Name: "database",
@@ -1361,143 +4637,247 @@ Error: timeout after 1ns waiting for "my-workload" to become ready
 					},
 				},
 			},
-			ShouldError: true,
 			ExpectOutput: `
-Update workload:
-...
-  5,  5   |  name: my-workload
-  6,  6   |  namespace: default
-  7,  7   |spec:
-  8,  8   |  image: ubuntu:bionic
-      9 + |  serviceClaims:
-     10 + |  - name: database
-     11 + |    ref:
-     12 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
-     13 + |      kind: PostgreSQL
-     14 + |      name: my-prod-db
+WARNING: Cross namespace service claims are deprecated. Please use ` + "`tanzu service claim create`" + ` instead.
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  annotations:
+      6 + |    serviceclaims.supplychain.apps.x-tanzu.vmware.com/extensions: '{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"database":{"namespace":"my-prod-ns"}}}}'
+      7 + |  name: my-workload
+      8 + |  namespace: default
+      9 + |spec:
+     10 + |  serviceClaims:
+     11 + |  - name: database
+     12 + |    ref:
+     13 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
+     14 + |      kind: PostgreSQL
+     15 + |      name: my-prod-db
+     16 + |  source:
+     17 + |    git:
+     18 + |      ref:
+     19 + |        branch: main
+     20 + |      url: https://example.com/repo.git
 
-Updated workload "my-workload"
+Created workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
-Waiting for workload "my-workload" to become ready...
-Error: Failed to become ready: a hopefully informative message about what went wrong
 `,
 		},
 		{
-			Name: "update - successful wait for ready condition",
-			Args: []string{workloadName, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-db", flags.WaitFlagName, flags.YesFlagName},
-			GivenObjects: []client.Object{
-				parent.
+			Name:         "create - service ref kind not served by the cluster",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:Postgres:my-prod-db", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+			ExpectOutput: `
+Error: service ref "database" refers to kind "Postgres" in "services.tanzu.vmware.com/v1alpha1" which is not served by the cluster, the workload would never bind to it
+`,
+		},
+		{
+			Name:         "create - service ref kinds not discoverable",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:Postgres:my-prod-db", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Client = &undiscoverableClient{Client: config.Client}
+				return ctx, nil
+			},
+			ExpectCreates: []client.Object{
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(workloadName)
+						d.Namespace(defaultNamespace)
+					}).
 					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Image("ubuntu:bionic")
+						d.ServiceClaims(cartov1alpha1.WorkloadServiceClaim{
+							Name: "database",
+							Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+								APIVersion: "services.tanzu.vmware.com/v1alpha1",
+								Kind:       "Postgres",
+								Name:       "my-prod-db",
+							},
+						})
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{Branch: gitBranch},
+							},
+						})
 					}),
 			},
-			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
-				workload := &cartov1alpha1.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
-						Name:      workloadName,
-					},
-					Status: cartov1alpha1.WorkloadStatus{
-						Conditions: []metav1.Condition{
-							{
-								Type:   cartov1alpha1.WorkloadConditionReady,
-								Status: metav1.ConditionTrue,
-							},
-						},
-					},
-				}
-				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
-					{Type: watch.Modified, Object: workload},
-				})
-				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
-				return ctx, nil
+			ExpectOutput: `
+Warning: unable to validate the kinds of the service refs against the cluster: the server is currently unable to handle the request
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  serviceClaims:
+      9 + |  - name: database
+     10 + |    ref:
+     11 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
+     12 + |      kind: Postgres
+     13 + |      name: my-prod-db
+     14 + |  source:
+     15 + |    git:
+     16 + |      ref:
+     17 + |        branch: main
+     18 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name:         "create - service ref secret not found",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ServiceRefSecretFlagName, "database=my-db-credentials", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+			ExpectOutput: `
+Error: service ref "database" refers to secret "my-db-credentials" which was not found in namespace "default", the workload would never bind to it
+`,
+		},
+		{
+			Name:         "create - image pull secret not found",
+			Args:         []string{workloadName, flags.ImageFlagName, "registry.example/private/app:1.0", flags.ImagePullSecretFlagName, "registry-credentials", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+			ExpectOutput: `
+Error: image pull secret "registry-credentials" was not found in namespace "default", the pods of the workload would fail to pull their images
+`,
+		},
+		{
+			Name: "create - image pull secret",
+			Args: []string{workloadName, flags.ImageFlagName, "registry.example/private/app:1.0", flags.ImagePullSecretFlagName, "registry-credentials", flags.ImagePullSecretFlagName, "registry-token", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				givenNamespaceDefault[0],
+				diecorev1.SecretBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Namespace(defaultNamespace)
+						d.Name("registry-credentials")
+					}).
+					Type(corev1.SecretTypeDockerConfigJson),
+				diecorev1.SecretBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Namespace(defaultNamespace)
+						d.Name("registry-token")
+					}).
+					Type(corev1.SecretTypeOpaque),
 			},
-			ExpectUpdates: []client.Object{
+			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Image: "ubuntu:bionic",
-						ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
-							{
-								Name: "database",
-								Ref: &cartov1alpha1.WorkloadServiceClaimReference{
-									APIVersion: "services.tanzu.vmware.com/v1alpha1",
-									Kind:       "PostgreSQL",
-									Name:       "my-prod-db",
-								},
-							},
-						},
+						Image: "registry.example/private/app:1.0",
+						Params: []cartov1alpha1.Param{{
+							Name:  cartov1alpha1.WorkloadImagePullSecretsParam,
+							Value: apiextensionsv1.JSON{Raw: []byte(`[{"name":"registry-credentials"},{"name":"registry-token"}]`)},
+						}},
 					},
 				},
 			},
-			ExpectOutput: `
-Update workload:
-...
-  5,  5   |  name: my-workload
-  6,  6   |  namespace: default
-  7,  7   |spec:
-  8,  8   |  image: ubuntu:bionic
-      9 + |  serviceClaims:
-     10 + |  - name: database
-     11 + |    ref:
-     12 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
-     13 + |      kind: PostgreSQL
-     14 + |      name: my-prod-db
+			ExpectOutput: `Warning: image pull secret "registry-token" is of type "Opaque", expected "kubernetes.io/dockerconfigjson"
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  image: registry.example/private/app:1.0
+      9 + |  params:
+     10 + |  - name: imagePullSecrets
+     11 + |    value:
+     12 + |    - name: registry-credentials
+     13 + |    - name: registry-token
 
-Updated workload "my-workload"
+Created workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
-Waiting for workload "my-workload" to become ready...
-Workload "my-workload" is ready
 `,
 		},
 		{
-			Name: "update - tail with timestamp while waiting for ready condition",
-			Args: []string{workloadName, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-db", flags.YesFlagName, flags.TailTimestampFlagName},
-			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
-				workload := &cartov1alpha1.Workload{
+			Name:         "create - typed params",
+			Args:         []string{workloadName, flags.ImageFlagName, "ubuntu:bionic", flags.ParamIntFlagName, "replicas=3", flags.ParamBoolFlagName, "debug=true", flags.ParamFlagName, "scanning=strict", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
 					},
-					Status: cartov1alpha1.WorkloadStatus{
-						Conditions: []metav1.Condition{
-							{
-								Type:   cartov1alpha1.WorkloadConditionReady,
-								Status: metav1.ConditionTrue,
-							},
-						},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Image: "ubuntu:bionic",
+						Params: []cartov1alpha1.Param{{
+							Name:  "scanning",
+							Value: apiextensionsv1.JSON{Raw: []byte(`"strict"`)},
+						}, {
+							Name:  "replicas",
+							Value: apiextensionsv1.JSON{Raw: []byte(`3`)},
+						}, {
+							Name:  "debug",
+							Value: apiextensionsv1.JSON{Raw: []byte(`true`)},
+						}},
 					},
-				}
-				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
-					{Type: watch.Modified, Object: workload},
-				})
-				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  image: ubuntu:bionic
+      9 + |  params:
+     10 + |  - name: scanning
+     11 + |    value: strict
+     12 + |  - name: replicas
+     13 + |    value: 3
+     14 + |  - name: debug
+     15 + |    value: true
 
-				tailer := &logs.FakeTailer{}
-				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
-				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, true).Return(nil).Once()
-				ctx = logs.StashTailer(ctx, tailer)
+Created workload "my-workload"
 
-				return ctx, nil
-			},
-			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
-				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
-				tailer.AssertExpectations(t)
-				return nil
-			},
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name:         "create - invalid typed param",
+			Args:         []string{workloadName, flags.ImageFlagName, "ubuntu:bionic", flags.ParamIntFlagName, "replicas=three", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name: "update - remove image pull secret",
+			Args: []string{workloadName, flags.ImagePullSecretFlagName, "registry-credentials-", flags.YesFlagName},
 			GivenObjects: []client.Object{
 				parent.
 					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Image("ubuntu:bionic")
+						d.Image("registry.example/private/app:1.0")
+						d.Params(cartov1alpha1.Param{
+							Name:  cartov1alpha1.WorkloadImagePullSecretsParam,
+							Value: apiextensionsv1.JSON{Raw: []byte(`[{"name":"registry-credentials"}]`)},
+						})
 					}),
 			},
 			ExpectUpdates: []client.Object{
@@ -1507,293 +4887,137 @@ Workload "my-workload" is ready
 						Name:      workloadName,
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Image: "ubuntu:bionic",
-						ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
-							{
-								Name: "database",
-								Ref: &cartov1alpha1.WorkloadServiceClaimReference{
-									APIVersion: "services.tanzu.vmware.com/v1alpha1",
-									Kind:       "PostgreSQL",
-									Name:       "my-prod-db",
-								},
-							},
-						},
+						Image:  "registry.example/private/app:1.0",
+						Params: []cartov1alpha1.Param{},
 					},
 				},
 			},
-			ExpectOutput: `
-Update workload:
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, workloadName, `{"metadata":{"resourceVersion":"999"},"spec":{"params":null}}`),
+			},
+			ExpectOutput: `Update workload:
 ...
   5,  5   |  name: my-workload
   6,  6   |  namespace: default
   7,  7   |spec:
-  8,  8   |  image: ubuntu:bionic
-      9 + |  serviceClaims:
-     10 + |  - name: database
-     11 + |    ref:
-     12 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
-     13 + |      kind: PostgreSQL
-     14 + |      name: my-prod-db
+  8,  8   |  image: registry.example/private/app:1.0
+  9     - |  params:
+ 10     - |  - name: imagePullSecrets
+ 11     - |    value:
+ 12     - |    - name: registry-credentials
 
 Updated workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
-Waiting for workload "my-workload" to become ready...
-...tail output...
-Workload "my-workload" is ready
 `,
 		},
 		{
-			Name: "update - filepath",
-			Args: []string{flags.FilePathFlagName, "testdata/workload.yaml", flags.SubPathFlagName, "./cmd", flags.YesFlagName},
-			GivenObjects: []client.Object{
-				parent.
-					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
-						d.Name("spring-petclinic")
-						d.AddLabel("preserve-me", "should-exist")
-					}).
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Image("ubuntu:bionic")
-						d.Env(
-							corev1.EnvVar{
-								Name:  "OVERRIDE_VAR",
-								Value: "doesnt matter",
-							},
-						)
-					}),
-			},
-			ExpectUpdates: []client.Object{
-				&cartov1alpha1.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
-						Name:      "spring-petclinic",
-						Labels: map[string]string{
-							"preserve-me":                         "should-exist",
-							"app.kubernetes.io/part-of":           "spring-petclinic",
-							"apps.tanzu.vmware.com/workload-type": "web",
-						},
+			Name: "create - workload type not selected by any supply chain",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.TypeFlagName, "wbe", flags.ValidateTypeFlagName, flags.YesFlagName},
+			GivenObjects: append([]client.Object{
+				&cartov1alpha1.ClusterSupplyChain{
+					ObjectMeta: metav1.ObjectMeta{Name: "source-to-url"},
+					Spec: cartov1alpha1.SupplyChainSpec{
+						Selector: map[string]string{apis.WorkloadTypeLabelName: "web"},
 					},
-					Spec: cartov1alpha1.WorkloadSpec{
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: "https://github.com/spring-projects/spring-petclinic.git",
-								Ref: cartov1alpha1.GitRef{
-									Branch: "main",
-								},
-							},
-							Subpath: "./cmd",
-						},
-						Env: []corev1.EnvVar{
-							{
-								Name:  "OVERRIDE_VAR",
-								Value: "doesnt matter",
-							},
-							{
-								Name:  "SPRING_PROFILES_ACTIVE",
-								Value: "mysql",
-							},
-						},
-						Resources: &corev1.ResourceRequirements{
-							Limits: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("500m"),
-								corev1.ResourceMemory: resource.MustParse("1Gi"),
-							},
-							Requests: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("100m"),
-								corev1.ResourceMemory: resource.MustParse("1Gi"),
-							},
-						},
+				},
+				&cartov1alpha1.ClusterSupplyChain{
+					ObjectMeta: metav1.ObjectMeta{Name: "source-to-worker"},
+					Spec: cartov1alpha1.SupplyChainSpec{
+						SelectorMatchExpressions: []metav1.LabelSelectorRequirement{{
+							Key:      apis.WorkloadTypeLabelName,
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   []string{"worker", "server"},
+						}},
 					},
 				},
-			},
+			}, givenNamespaceDefault...),
+			ShouldError: true,
 			ExpectOutput: `
-Update workload:
-...
-  2,  2   |apiVersion: carto.run/v1alpha1
-  3,  3   |kind: Workload
-  4,  4   |metadata:
-  5,  5   |  labels:
-      6 + |    app.kubernetes.io/part-of: spring-petclinic
-      7 + |    apps.tanzu.vmware.com/workload-type: web
-  6,  8   |    preserve-me: should-exist
-  7,  9   |  name: spring-petclinic
-  8, 10   |  namespace: default
-  9, 11   |spec:
- 10, 12   |  env:
- 11, 13   |  - name: OVERRIDE_VAR
- 12, 14   |    value: doesnt matter
- 13     - |  image: ubuntu:bionic
-     15 + |  - name: SPRING_PROFILES_ACTIVE
-     16 + |    value: mysql
-     17 + |  resources:
-     18 + |    limits:
-     19 + |      cpu: 500m
-     20 + |      memory: 1Gi
-     21 + |    requests:
-     22 + |      cpu: 100m
-     23 + |      memory: 1Gi
-     24 + |  source:
-     25 + |    git:
-     26 + |      ref:
-     27 + |        branch: main
-     28 + |      url: https://github.com/spring-projects/spring-petclinic.git
-     29 + |    subPath: ./cmd
-
-Updated workload "spring-petclinic"
-
-To see logs:   "tanzu apps workload tail spring-petclinic"
-To get status: "tanzu apps workload get spring-petclinic"
-
+Error: no supply chain selects workloads of type "wbe", the workload would never be reconciled
+Supported types: server, web, worker
 `,
 		},
 		{
-			Name: "update - filepath - custom namespace and name",
-			Args: []string{workloadName, flags.NamespaceFlagName, "test-namespace", flags.FilePathFlagName, "testdata/workload.yaml", flags.YesFlagName},
-			GivenObjects: []client.Object{
-				parent.
-					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
-						d.Namespace("test-namespace")
-						d.AddLabel("preserve-me", "should-exist")
-					}).
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Image("ubuntu:bionic")
-						d.Env(
-							corev1.EnvVar{
-								Name:  "OVERRIDE_VAR",
-								Value: "doesnt matter",
-							},
-						)
-					}),
-			},
-			ExpectUpdates: []client.Object{
+			Name: "create - workload type selected by a supply chain",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.TypeFlagName, "web", flags.ValidateTypeFlagName, flags.YesFlagName},
+			GivenObjects: append([]client.Object{
+				&cartov1alpha1.ClusterSupplyChain{
+					ObjectMeta: metav1.ObjectMeta{Name: "source-to-url"},
+					Spec: cartov1alpha1.SupplyChainSpec{
+						Selector: map[string]string{apis.WorkloadTypeLabelName: "web"},
+					},
+				},
+			}, givenNamespaceDefault...),
+			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: "test-namespace",
+						Namespace: defaultNamespace,
 						Name:      workloadName,
 						Labels: map[string]string{
-							"preserve-me":                         "should-exist",
-							"app.kubernetes.io/part-of":           "spring-petclinic",
-							"apps.tanzu.vmware.com/workload-type": "web",
+							apis.WorkloadTypeLabelName: "web",
 						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
 						Source: &cartov1alpha1.Source{
 							Git: &cartov1alpha1.GitSource{
-								URL: "https://github.com/spring-projects/spring-petclinic.git",
+								URL: gitRepo,
 								Ref: cartov1alpha1.GitRef{
-									Branch: "main",
+									Branch: gitBranch,
 								},
 							},
 						},
-						Env: []corev1.EnvVar{
-							{
-								Name:  "OVERRIDE_VAR",
-								Value: "doesnt matter",
-							},
-							{
-								Name:  "SPRING_PROFILES_ACTIVE",
-								Value: "mysql",
-							},
-						},
-						Resources: &corev1.ResourceRequirements{
-							Limits: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("500m"),
-								corev1.ResourceMemory: resource.MustParse("1Gi"),
-							},
-							Requests: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse("100m"),
-								corev1.ResourceMemory: resource.MustParse("1Gi"),
-							},
-						},
 					},
 				},
 			},
 			ExpectOutput: `
-Update workload:
-...
-  2,  2   |apiVersion: carto.run/v1alpha1
-  3,  3   |kind: Workload
-  4,  4   |metadata:
-  5,  5   |  labels:
-      6 + |    app.kubernetes.io/part-of: spring-petclinic
-      7 + |    apps.tanzu.vmware.com/workload-type: web
-  6,  8   |    preserve-me: should-exist
-  7,  9   |  name: my-workload
-  8, 10   |  namespace: test-namespace
-  9, 11   |spec:
- 10, 12   |  env:
- 11, 13   |  - name: OVERRIDE_VAR
- 12, 14   |    value: doesnt matter
- 13     - |  image: ubuntu:bionic
-     15 + |  - name: SPRING_PROFILES_ACTIVE
-     16 + |    value: mysql
-     17 + |  resources:
-     18 + |    limits:
-     19 + |      cpu: 500m
-     20 + |      memory: 1Gi
-     21 + |    requests:
-     22 + |      cpu: 100m
-     23 + |      memory: 1Gi
-     24 + |  source:
-     25 + |    git:
-     26 + |      ref:
-     27 + |        branch: main
-     28 + |      url: https://github.com/spring-projects/spring-petclinic.git
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    apps.tanzu.vmware.com/workload-type: web
+      7 + |  name: my-workload
+      8 + |  namespace: default
+      9 + |spec:
+     10 + |  source:
+     11 + |    git:
+     12 + |      ref:
+     13 + |        branch: main
+     14 + |      url: https://example.com/repo.git
 
-Updated workload "my-workload"
+Created workload "my-workload"
 
-To see logs:   "tanzu apps workload tail my-workload --namespace test-namespace"
-To get status: "tanzu apps workload get my-workload --namespace test-namespace"
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
 
 `,
 		},
 		{
-			Name:         "local path - missing fields",
-			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LocalPathFlagName, "testdata/local-source", flags.YesFlagName},
-			GivenObjects: givenNamespaceDefault,
-			ShouldError:  true,
-			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
-				if expected, actual := false, cmd.SilenceUsage; expected != actual {
-					t.Errorf("expected cmd.SilenceUsage to be %t, actually %t", expected, actual)
-				}
-
-				return nil
-			},
-		},
-		{
-			Name:        "filepath invalid name",
-			Args:        []string{flags.FilePathFlagName, "testdata/workload-invalid-name.yaml", flags.YesFlagName},
-			ShouldError: true,
-		},
-		{
-			Name: "update - serviceclaim with deprecation warning",
-			Args: []string{workloadName, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-ns:my-prod-db", flags.YesFlagName},
-			GivenObjects: []client.Object{
-				parent.
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Image("ubuntu:bionic")
-					}),
+			Name:         "create - workload type not validated when supply chains can not be listed",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.TypeFlagName, "web", flags.ValidateTypeFlagName, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("list", "ClusterSupplyChainList"),
 			},
-			ExpectUpdates: []client.Object{
+			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
-						Annotations: map[string]string{
-							apis.ServiceClaimAnnotationName: `{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"database":{"namespace":"my-prod-ns"}}}}`,
+						Labels: map[string]string{
+							apis.WorkloadTypeLabelName: "web",
 						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Image: "ubuntu:bionic",
-						ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
-							{
-								Name: "database",
-								Ref: &cartov1alpha1.WorkloadServiceClaimReference{
-									APIVersion: "services.tanzu.vmware.com/v1alpha1",
-									Kind:       "PostgreSQL",
-									Name:       "my-prod-db",
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
 								},
 							},
 						},
@@ -1801,26 +5025,24 @@ To get status: "tanzu apps workload get my-workload --namespace test-namespace"
 				},
 			},
 			ExpectOutput: `
-WARNING: Cross namespace service claims are deprecated. Please use ` + "`tanzu service claim create`" + ` instead.
-Update workload:
-  1,  1   |---
-  2,  2   |apiVersion: carto.run/v1alpha1
-  3,  3   |kind: Workload
-  4,  4   |metadata:
-      5 + |  annotations:
-      6 + |    serviceclaims.supplychain.apps.x-tanzu.vmware.com/extensions: '{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"database":{"namespace":"my-prod-ns"}}}}'
-  5,  7   |  name: my-workload
-  6,  8   |  namespace: default
-  7,  9   |spec:
-  8, 10   |  image: ubuntu:bionic
-     11 + |  serviceClaims:
-     12 + |  - name: database
-     13 + |    ref:
-     14 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
-     15 + |      kind: PostgreSQL
-     16 + |      name: my-prod-db
+Warning: unable to validate workload type "web" against the cluster supply chains: inducing failure for list ClusterSupplyChainList
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    apps.tanzu.vmware.com/workload-type: web
+      7 + |  name: my-workload
+      8 + |  namespace: default
+      9 + |spec:
+     10 + |  source:
+     11 + |    git:
+     12 + |      ref:
+     13 + |        branch: main
+     14 + |      url: https://example.com/repo.git
 
-Updated workload "my-workload"
+Created workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
@@ -1828,17 +5050,21 @@ To get status: "tanzu apps workload get my-workload"
 `,
 		},
 		{
-			Name:         "create - serviceclaim with deprecation warning",
-			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-ns:my-prod-db", flags.YesFlagName},
-			GivenObjects: givenNamespaceDefault,
+			Name: "create - service ref secret",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ServiceRefSecretFlagName, "database=my-db-credentials", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				givenNamespaceDefault[0],
+				diecorev1.SecretBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Namespace(defaultNamespace)
+						d.Name("my-db-credentials")
+					}),
+			},
 			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
-						Annotations: map[string]string{
-							apis.ServiceClaimAnnotationName: `{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"database":{"namespace":"my-prod-ns"}}}}`,
-						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
 						Source: &cartov1alpha1.Source{
@@ -1848,9 +5074,9 @@ To get status: "tanzu apps workload get my-workload"
 							{
 								Name: "database",
 								Ref: &cartov1alpha1.WorkloadServiceClaimReference{
-									APIVersion: "services.tanzu.vmware.com/v1alpha1",
-									Kind:       "PostgreSQL",
-									Name:       "my-prod-db",
+									APIVersion: "v1",
+									Kind:       "Secret",
+									Name:       "my-db-credentials",
 								},
 							},
 						},
@@ -1858,28 +5084,25 @@ To get status: "tanzu apps workload get my-workload"
 				},
 			},
 			ExpectOutput: `
-WARNING: Cross namespace service claims are deprecated. Please use ` + "`tanzu service claim create`" + ` instead.
 Create workload:
       1 + |---
       2 + |apiVersion: carto.run/v1alpha1
       3 + |kind: Workload
       4 + |metadata:
-      5 + |  annotations:
-      6 + |    serviceclaims.supplychain.apps.x-tanzu.vmware.com/extensions: '{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"database":{"namespace":"my-prod-ns"}}}}'
-      7 + |  name: my-workload
-      8 + |  namespace: default
-      9 + |spec:
-     10 + |  serviceClaims:
-     11 + |  - name: database
-     12 + |    ref:
-     13 + |      apiVersion: services.tanzu.vmware.com/v1alpha1
-     14 + |      kind: PostgreSQL
-     15 + |      name: my-prod-db
-     16 + |  source:
-     17 + |    git:
-     18 + |      ref:
-     19 + |        branch: main
-     20 + |      url: https://example.com/repo.git
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  serviceClaims:
+      9 + |  - name: database
+     10 + |    ref:
+     11 + |      apiVersion: v1
+     12 + |      kind: Secret
+     13 + |      name: my-db-credentials
+     14 + |  source:
+     15 + |    git:
+     16 + |      ref:
+     17 + |        branch: main
+     18 + |      url: https://example.com/repo.git
 
 Created workload "my-workload"
 
@@ -1986,6 +5209,9 @@ To get status: "tanzu apps workload get spring-petclinic"
 					},
 				},
 			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, "spring-petclinic", `{"metadata":{"resourceVersion":"999"},"spec":{"serviceAccountName":null}}`),
+			},
 			ExpectOutput: `
 Update workload:
 ...
@@ -2112,6 +5338,9 @@ To get status: "tanzu apps workload get spring-petclinic"
 					},
 				},
 			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, "spring-petclinic", `{"metadata":{"resourceVersion":"999"},"spec":{"serviceAccountName":null}}`),
+			},
 			ExpectOutput: `
 Update workload:
 ...
@@ -2293,6 +5522,9 @@ To get status: "tanzu apps workload get my-workload"
 					},
 				},
 			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, workloadName, `{"metadata":{"resourceVersion":"999"},"spec":{"serviceAccountName":null}}`),
+			},
 			ExpectOutput: `
 Update workload:
 ...
@@ -2329,13 +5561,170 @@ To get status: "tanzu apps workload get my-workload"
 						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						ServiceAccountName: &serviceAccountName,
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: "https://github.com/sample-accelerators/spring-petclinic",
-								Ref: cartov1alpha1.GitRef{
-									Tag: "tap-1.1",
-								},
+						ServiceAccountName: &serviceAccountName,
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.com/sample-accelerators/spring-petclinic",
+								Ref: cartov1alpha1.GitRef{
+									Tag: "tap-1.1",
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    app.kubernetes.io/part-of: spring-petclinic
+      7 + |    apps.tanzu.vmware.com/workload-type: web
+      8 + |  name: spring-petclinic
+      9 + |  namespace: default
+     10 + |spec:
+     11 + |  serviceAccountName: my-service-account
+     12 + |  source:
+     13 + |    git:
+     14 + |      ref:
+     15 + |        tag: tap-1.1
+     16 + |      url: https://github.com/sample-accelerators/spring-petclinic
+
+Created workload "spring-petclinic"
+
+To see logs:   "tanzu apps workload tail spring-petclinic"
+To get status: "tanzu apps workload get spring-petclinic"
+
+`,
+		},
+		{
+			Name:         "create with serviceAccountName via flag",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ServiceAccountFlagName, serviceAccountName, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						ServiceAccountName: &serviceAccountName,
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  serviceAccountName: my-service-account
+      9 + |  source:
+     10 + |    git:
+     11 + |      ref:
+     12 + |        branch: main
+     13 + |      url: https://example.com/repo.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name:         "create with serviceAccountName from file and flag",
+			Args:         []string{flags.FilePathFlagName, "testdata/service-account-name.yaml", flags.ServiceAccountFlagName, serviceAccountNameUpdated, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      "spring-petclinic",
+						Labels: map[string]string{
+							apis.AppPartOfLabelName:               "spring-petclinic",
+							"apps.tanzu.vmware.com/workload-type": "web",
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						ServiceAccountName: &serviceAccountNameUpdated,
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.com/sample-accelerators/spring-petclinic",
+								Ref: cartov1alpha1.GitRef{
+									Tag: "tap-1.1",
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    app.kubernetes.io/part-of: spring-petclinic
+      7 + |    apps.tanzu.vmware.com/workload-type: web
+      8 + |  name: spring-petclinic
+      9 + |  namespace: default
+     10 + |spec:
+     11 + |  serviceAccountName: my-service-account-updated
+     12 + |  source:
+     13 + |    git:
+     14 + |      ref:
+     15 + |        tag: tap-1.1
+     16 + |      url: https://github.com/sample-accelerators/spring-petclinic
+
+Created workload "spring-petclinic"
+
+To see logs:   "tanzu apps workload tail spring-petclinic"
+To get status: "tanzu apps workload get spring-petclinic"
+
+`,
+		},
+		{
+			Name: "create with multiple param-yaml using valid json and yaml",
+			Args: []string{flags.FilePathFlagName, "testdata/param-yaml.yaml",
+				flags.ParamYamlFlagName, `ports_json={"name": "smtp", "port": 1026}`,
+				flags.ParamYamlFlagName, "ports_nesting_yaml=- deployment:\n    name: smtp\n    port: 1026",
+				flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      "spring-petclinic",
+						Labels: map[string]string{
+							apis.AppPartOfLabelName:               "spring-petclinic",
+							"apps.tanzu.vmware.com/workload-type": "web",
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Image: "my-reponame/my-image:my-tag",
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "ports_json",
+								Value: apiextensionsv1.JSON{Raw: []byte(`{"name":"smtp","port":1026}`)},
+							}, {
+								Name:  "ports_nesting_yaml",
+								Value: apiextensionsv1.JSON{Raw: []byte(`[{"deployment":{"name":"smtp","port":1026}}]`)},
 							},
 						},
 					},
@@ -2353,12 +5742,17 @@ Create workload:
       8 + |  name: spring-petclinic
       9 + |  namespace: default
      10 + |spec:
-     11 + |  serviceAccountName: my-service-account
-     12 + |  source:
-     13 + |    git:
-     14 + |      ref:
-     15 + |        tag: tap-1.1
-     16 + |      url: https://github.com/sample-accelerators/spring-petclinic
+     11 + |  image: my-reponame/my-image:my-tag
+     12 + |  params:
+     13 + |  - name: ports_json
+     14 + |    value:
+     15 + |      name: smtp
+     16 + |      port: 1026
+     17 + |  - name: ports_nesting_yaml
+     18 + |    value:
+     19 + |    - deployment:
+     20 + |        name: smtp
+     21 + |        port: 1026
 
 Created workload "spring-petclinic"
 
@@ -2368,8 +5762,8 @@ To get status: "tanzu apps workload get spring-petclinic"
 `,
 		},
 		{
-			Name:         "create with serviceAccountName via flag",
-			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.ServiceAccountFlagName, serviceAccountName, flags.YesFlagName},
+			Name:         "create from maven artifact using paramyaml",
+			Args:         []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "spring-petclinic", "version": "2.6.0", "groupId": "org.springframework.samples"}`, flags.YesFlagName},
 			GivenObjects: givenNamespaceDefault,
 			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
@@ -2379,13 +5773,10 @@ To get status: "tanzu apps workload get spring-petclinic"
 						Labels:    map[string]string{},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						ServiceAccountName: &serviceAccountName,
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: gitRepo,
-								Ref: cartov1alpha1.GitRef{
-									Branch: gitBranch,
-								},
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "maven",
+								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
 							},
 						},
 					},
@@ -2400,12 +5791,12 @@ Create workload:
       5 + |  name: my-workload
       6 + |  namespace: default
       7 + |spec:
-      8 + |  serviceAccountName: my-service-account
-      9 + |  source:
-     10 + |    git:
-     11 + |      ref:
-     12 + |        branch: main
-     13 + |      url: https://example.com/repo.git
+      8 + |  params:
+      9 + |  - name: maven
+     10 + |    value:
+     11 + |      artifactId: spring-petclinic
+     12 + |      groupId: org.springframework.samples
+     13 + |      version: 2.6.0
 
 Created workload "my-workload"
 
@@ -2415,27 +5806,21 @@ To get status: "tanzu apps workload get my-workload"
 `,
 		},
 		{
-			Name:         "create with serviceAccountName from file and flag",
-			Args:         []string{flags.FilePathFlagName, "testdata/service-account-name.yaml", flags.ServiceAccountFlagName, serviceAccountNameUpdated, flags.YesFlagName},
+			Name:         "create from maven artifact using flags",
+			Args:         []string{workloadName, flags.MavenArtifactFlagName, "spring-petclinic", flags.MavenVersionFlagName, "2.6.0", flags.MavenGroupFlagName, "org.springframework.samples", flags.YesFlagName},
 			GivenObjects: givenNamespaceDefault,
 			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
-						Name:      "spring-petclinic",
-						Labels: map[string]string{
-							apis.AppPartOfLabelName:               "spring-petclinic",
-							"apps.tanzu.vmware.com/workload-type": "web",
-						},
+						Name:      workloadName,
+						Labels:    map[string]string{},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						ServiceAccountName: &serviceAccountNameUpdated,
-						Source: &cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: "https://github.com/sample-accelerators/spring-petclinic",
-								Ref: cartov1alpha1.GitRef{
-									Tag: "tap-1.1",
-								},
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "maven",
+								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
 							},
 						},
 					},
@@ -2447,93 +5832,231 @@ Create workload:
       2 + |apiVersion: carto.run/v1alpha1
       3 + |kind: Workload
       4 + |metadata:
-      5 + |  labels:
-      6 + |    app.kubernetes.io/part-of: spring-petclinic
-      7 + |    apps.tanzu.vmware.com/workload-type: web
-      8 + |  name: spring-petclinic
-      9 + |  namespace: default
-     10 + |spec:
-     11 + |  serviceAccountName: my-service-account-updated
-     12 + |  source:
-     13 + |    git:
-     14 + |      ref:
-     15 + |        tag: tap-1.1
-     16 + |      url: https://github.com/sample-accelerators/spring-petclinic
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  params:
+      9 + |  - name: maven
+     10 + |    value:
+     11 + |      artifactId: spring-petclinic
+     12 + |      groupId: org.springframework.samples
+     13 + |      version: 2.6.0
 
-Created workload "spring-petclinic"
+Created workload "my-workload"
 
-To see logs:   "tanzu apps workload tail spring-petclinic"
-To get status: "tanzu apps workload get spring-petclinic"
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
 
 `,
 		},
 		{
-			Name: "create with multiple param-yaml using valid json and yaml",
-			Args: []string{flags.FilePathFlagName, "testdata/param-yaml.yaml",
-				flags.ParamYamlFlagName, `ports_json={"name": "smtp", "port": 1026}`,
-				flags.ParamYamlFlagName, "ports_nesting_yaml=- deployment:\n    name: smtp\n    port: 1026",
-				flags.YesFlagName},
+			Name: "create from maven artifact taking priority from flags",
+			Args: []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "spring-petclinic-test", "version": "2.6.1", "groupId": "org.springframework.samples.test"}`,
+				flags.MavenArtifactFlagName, "spring-petclinic", flags.MavenVersionFlagName, "2.6.0", flags.MavenGroupFlagName, "org.springframework.samples", flags.YesFlagName},
 			GivenObjects: givenNamespaceDefault,
 			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
-						Name:      "spring-petclinic",
-						Labels: map[string]string{
-							apis.AppPartOfLabelName:               "spring-petclinic",
-							"apps.tanzu.vmware.com/workload-type": "web",
-						},
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "maven",
+								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
+      7 + |spec:
+      8 + |  params:
+      9 + |  - name: maven
+     10 + |    value:
+     11 + |      artifactId: spring-petclinic
+     12 + |      groupId: org.springframework.samples
+     13 + |      version: 2.6.0
+
+NOTICE: Maven configuration flags have overwritten values provided by "--params-yaml".
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name: "update from maven artifact taking priority from flags",
+			Args: []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "foo", "version": "1.0.0", "groupId": "bar"}`,
+				flags.MavenArtifactFlagName, "spring-petclinic-test", flags.MavenVersionFlagName, "2.6.1", flags.MavenGroupFlagName, "org.springframework.samples.test", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Params(cartov1alpha1.Param{
+							Name:  "maven",
+							Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
+						})
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "maven",
+								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic-test","groupId":"org.springframework.samples.test","version":"2.6.1"}`)},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Update workload:
+...
+  7,  7   |spec:
+  8,  8   |  params:
+  9,  9   |  - name: maven
+ 10, 10   |    value:
+ 11     - |      artifactId: spring-petclinic
+ 12     - |      groupId: org.springframework.samples
+ 13     - |      version: 2.6.0
+     11 + |      artifactId: spring-petclinic-test
+     12 + |      groupId: org.springframework.samples.test
+     13 + |      version: 2.6.1
+
+NOTICE: Maven configuration flags have overwritten values provided by "--params-yaml".
+
+Updated workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name: "update workload to add maven param",
+			Args: []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "spring-petclinic", "version": "2.6.0", "groupId": "org.springframework.samples"}`, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {}),
+			},
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Params: []cartov1alpha1.Param{
+							{
+								Name:  "maven",
+								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Update workload:
+...
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7     - |spec: {}
+      7 + |spec:
+      8 + |  params:
+      9 + |  - name: maven
+     10 + |    value:
+     11 + |      artifactId: spring-petclinic
+     12 + |      groupId: org.springframework.samples
+     13 + |      version: 2.6.0
+
+Updated workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name: "update workload to add maven through flags",
+			Args: []string{workloadName, flags.MavenArtifactFlagName, "spring-petclinic", flags.MavenVersionFlagName, "2.6.0", flags.MavenGroupFlagName, "org.springframework.samples", flags.MavenTypeFlagName, "jar", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {}),
+			},
+			ExpectUpdates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels:    map[string]string{},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Image: "my-reponame/my-image:my-tag",
 						Params: []cartov1alpha1.Param{
 							{
-								Name:  "ports_json",
-								Value: apiextensionsv1.JSON{Raw: []byte(`{"name":"smtp","port":1026}`)},
-							}, {
-								Name:  "ports_nesting_yaml",
-								Value: apiextensionsv1.JSON{Raw: []byte(`[{"deployment":{"name":"smtp","port":1026}}]`)},
+								Name:  "maven",
+								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","type":"jar","version":"2.6.0"}`)},
 							},
 						},
 					},
 				},
 			},
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  labels:
-      6 + |    app.kubernetes.io/part-of: spring-petclinic
-      7 + |    apps.tanzu.vmware.com/workload-type: web
-      8 + |  name: spring-petclinic
-      9 + |  namespace: default
-     10 + |spec:
-     11 + |  image: my-reponame/my-image:my-tag
-     12 + |  params:
-     13 + |  - name: ports_json
-     14 + |    value:
-     15 + |      name: smtp
-     16 + |      port: 1026
-     17 + |  - name: ports_nesting_yaml
-     18 + |    value:
-     19 + |    - deployment:
-     20 + |        name: smtp
-     21 + |        port: 1026
+Update workload:
+...
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7     - |spec: {}
+      7 + |spec:
+      8 + |  params:
+      9 + |  - name: maven
+     10 + |    value:
+     11 + |      artifactId: spring-petclinic
+     12 + |      groupId: org.springframework.samples
+     13 + |      type: jar
+     14 + |      version: 2.6.0
 
-Created workload "spring-petclinic"
+Updated workload "my-workload"
 
-To see logs:   "tanzu apps workload tail spring-petclinic"
-To get status: "tanzu apps workload get spring-petclinic"
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
 
 `,
 		},
 		{
-			Name:         "create from maven artifact using paramyaml",
-			Args:         []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "spring-petclinic", "version": "2.6.0", "groupId": "org.springframework.samples"}`, flags.YesFlagName},
-			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
+			Name: "update workload to change maven info through flags",
+			Args: []string{workloadName, flags.MavenVersionFlagName, "2.6.1", flags.MavenTypeFlagName, "jar", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Params(cartov1alpha1.Param{
+							Name:  "maven",
+							Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
+						})
+					}),
+			},
+			ExpectUpdates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
@@ -2544,29 +6067,24 @@ To get status: "tanzu apps workload get spring-petclinic"
 						Params: []cartov1alpha1.Param{
 							{
 								Name:  "maven",
-								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
+								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","type":"jar","version":"2.6.1"}`)},
 							},
 						},
 					},
 				},
 			},
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  name: my-workload
-      6 + |  namespace: default
-      7 + |spec:
-      8 + |  params:
-      9 + |  - name: maven
-     10 + |    value:
-     11 + |      artifactId: spring-petclinic
-     12 + |      groupId: org.springframework.samples
-     13 + |      version: 2.6.0
+Update workload:
+...
+  9,  9   |  - name: maven
+ 10, 10   |    value:
+ 11, 11   |      artifactId: spring-petclinic
+ 12, 12   |      groupId: org.springframework.samples
+ 13     - |      version: 2.6.0
+     13 + |      type: jar
+     14 + |      version: 2.6.1
 
-Created workload "my-workload"
+Updated workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
@@ -2574,10 +6092,18 @@ To get status: "tanzu apps workload get my-workload"
 `,
 		},
 		{
-			Name:         "create from maven artifact using flags",
-			Args:         []string{workloadName, flags.MavenArtifactFlagName, "spring-petclinic", flags.MavenVersionFlagName, "2.6.0", flags.MavenGroupFlagName, "org.springframework.samples", flags.YesFlagName},
-			GivenObjects: givenNamespaceDefault,
-			ExpectCreates: []client.Object{
+			Name: "update workload to override maven info through params yaml",
+			Args: []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "spring-petclinic", "version": "2.6.0", "groupId": "org.springframework.samples"}`, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Params(cartov1alpha1.Param{
+							Name:  "maven",
+							Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"foo","groupId":"bar","version":"1.0.0","type":"baz"}`)},
+						})
+					}),
+			},
+			ExpectUpdates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
@@ -2595,22 +6121,21 @@ To get status: "tanzu apps workload get my-workload"
 				},
 			},
 			ExpectOutput: `
-Create workload:
-      1 + |---
-      2 + |apiVersion: carto.run/v1alpha1
-      3 + |kind: Workload
-      4 + |metadata:
-      5 + |  name: my-workload
-      6 + |  namespace: default
-      7 + |spec:
-      8 + |  params:
-      9 + |  - name: maven
-     10 + |    value:
+Update workload:
+...
+  7,  7   |spec:
+  8,  8   |  params:
+  9,  9   |  - name: maven
+ 10, 10   |    value:
+ 11     - |      artifactId: foo
+ 12     - |      groupId: bar
+ 13     - |      type: baz
+ 14     - |      version: 1.0.0
      11 + |      artifactId: spring-petclinic
      12 + |      groupId: org.springframework.samples
      13 + |      version: 2.6.0
 
-Created workload "my-workload"
+Updated workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
@@ -2618,9 +6143,8 @@ To get status: "tanzu apps workload get my-workload"
 `,
 		},
 		{
-			Name: "create from maven artifact taking priority from flags",
-			Args: []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "spring-petclinic-test", "version": "2.6.1", "groupId": "org.springframework.samples.test"}`,
-				flags.MavenArtifactFlagName, "spring-petclinic", flags.MavenVersionFlagName, "2.6.0", flags.MavenGroupFlagName, "org.springframework.samples", flags.YesFlagName},
+			Name:         "create from maven artifact using paramyaml with type",
+			Args:         []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "spring-petclinic", "version": "2.6.0", "groupId": "org.springframework.samples", "type": "jar"}`, flags.YesFlagName},
 			GivenObjects: givenNamespaceDefault,
 			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
@@ -2633,7 +6157,7 @@ To get status: "tanzu apps workload get my-workload"
 						Params: []cartov1alpha1.Param{
 							{
 								Name:  "maven",
-								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
+								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","type":"jar","version":"2.6.0"}`)},
 							},
 						},
 					},
@@ -2653,9 +6177,8 @@ Create workload:
      10 + |    value:
      11 + |      artifactId: spring-petclinic
      12 + |      groupId: org.springframework.samples
-     13 + |      version: 2.6.0
-
-NOTICE: Maven configuration flags have overwritten values provided by "--params-yaml".
+     13 + |      type: jar
+     14 + |      version: 2.6.0
 
 Created workload "my-workload"
 
@@ -2665,66 +6188,105 @@ To get status: "tanzu apps workload get my-workload"
 `,
 		},
 		{
-			Name: "update from maven artifact taking priority from flags",
-			Args: []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "foo", "version": "1.0.0", "groupId": "bar"}`,
-				flags.MavenArtifactFlagName, "spring-petclinic-test", flags.MavenVersionFlagName, "2.6.1", flags.MavenGroupFlagName, "org.springframework.samples.test", flags.YesFlagName},
-			GivenObjects: []client.Object{
-				parent.
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Params(cartov1alpha1.Param{
-							Name:  "maven",
-							Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
-						})
-					}),
-			},
-			ExpectUpdates: []client.Object{
+			ShouldError: true,
+			Name:        "fails create with multiple param-yaml using invalid json",
+			Args: []string{flags.FilePathFlagName, "testdata/param-yaml.yaml",
+				flags.ParamYamlFlagName, `ports_json={"name": "smtp", "port": 1026`,
+				flags.ParamYamlFlagName, `ports_nesting_yaml=- deployment:\n    name: smtp\n    port: 1026`,
+				flags.YesFlagName},
+			ExpectOutput: "",
+		},
+		{
+			Name: "create with multiple param-yaml using valid json and yaml from file",
+			Args: []string{flags.FilePathFlagName, "testdata/workload-param-yaml.yaml",
+				flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
-						Name:      workloadName,
-						Labels:    map[string]string{},
+						Name:      "spring-petclinic",
+						Labels: map[string]string{
+							apis.AppPartOfLabelName:               "spring-petclinic",
+							"apps.tanzu.vmware.com/workload-type": "web",
+						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.com/spring-projects/spring-petclinic.git",
+								Ref: cartov1alpha1.GitRef{
+									Branch: "main",
+								},
+							},
+						},
 						Params: []cartov1alpha1.Param{
 							{
-								Name:  "maven",
-								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic-test","groupId":"org.springframework.samples.test","version":"2.6.1"}`)},
+								Name:  "ports",
+								Value: apiextensionsv1.JSON{Raw: []byte(`{"ports":[{"name":"http","port":8080,"protocol":"TCP","targetPort":8080},{"name":"https","port":8443,"protocol":"TCP","targetPort":8443}]}`)},
+							}, {
+								Name:  "services",
+								Value: apiextensionsv1.JSON{Raw: []byte(`[{"image":"mysql:5.7","name":"mysql"},{"image":"postgres:9.6","name":"postgres"}]`)},
 							},
 						},
 					},
 				},
 			},
 			ExpectOutput: `
-Update workload:
-...
-  7,  7   |spec:
-  8,  8   |  params:
-  9,  9   |  - name: maven
- 10, 10   |    value:
- 11     - |      artifactId: spring-petclinic
- 12     - |      groupId: org.springframework.samples
- 13     - |      version: 2.6.0
-     11 + |      artifactId: spring-petclinic-test
-     12 + |      groupId: org.springframework.samples.test
-     13 + |      version: 2.6.1
-
-NOTICE: Maven configuration flags have overwritten values provided by "--params-yaml".
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    app.kubernetes.io/part-of: spring-petclinic
+      7 + |    apps.tanzu.vmware.com/workload-type: web
+      8 + |  name: spring-petclinic
+      9 + |  namespace: default
+     10 + |spec:
+     11 + |  params:
+     12 + |  - name: ports
+     13 + |    value:
+     14 + |      ports:
+     15 + |      - name: http
+     16 + |        port: 8080
+     17 + |        protocol: TCP
+     18 + |        targetPort: 8080
+     19 + |      - name: https
+     20 + |        port: 8443
+     21 + |        protocol: TCP
+     22 + |        targetPort: 8443
+     23 + |  - name: services
+     24 + |    value:
+     25 + |    - image: mysql:5.7
+     26 + |      name: mysql
+     27 + |    - image: postgres:9.6
+     28 + |      name: postgres
+     29 + |  source:
+     30 + |    git:
+     31 + |      ref:
+     32 + |        branch: main
+     33 + |      url: https://github.com/spring-projects/spring-petclinic.git
 
-Updated workload "my-workload"
+Created workload "spring-petclinic"
 
-To see logs:   "tanzu apps workload tail my-workload"
-To get status: "tanzu apps workload get my-workload"
+To see logs:   "tanzu apps workload tail spring-petclinic"
+To get status: "tanzu apps workload get spring-petclinic"
 
 `,
-		},
-		{
-			Name: "update workload to add maven param",
-			Args: []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "spring-petclinic", "version": "2.6.0", "groupId": "org.springframework.samples"}`, flags.YesFlagName},
-			GivenObjects: []client.Object{
-				parent.
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {}),
+		}, {
+			Name: "git source with non-allowed env var",
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				os.Setenv("TANZU_APPS_LABEL", "foo=var")
+				return ctx, nil
 			},
-			ExpectUpdates: []client.Object{
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				os.Unsetenv("TANZU_APPS_LABEL")
+				return nil
+			},
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
@@ -2732,200 +6294,184 @@ To get status: "tanzu apps workload get my-workload"
 						Labels:    map[string]string{},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Params: []cartov1alpha1.Param{
-							{
-								Name:  "maven",
-								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
 							},
 						},
 					},
 				},
 			},
 			ExpectOutput: `
-Update workload:
-...
-  3,  3   |kind: Workload
-  4,  4   |metadata:
-  5,  5   |  name: my-workload
-  6,  6   |  namespace: default
-  7     - |spec: {}
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: default
       7 + |spec:
-      8 + |  params:
-      9 + |  - name: maven
-     10 + |    value:
-     11 + |      artifactId: spring-petclinic
-     12 + |      groupId: org.springframework.samples
-     13 + |      version: 2.6.0
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
 
-Updated workload "my-workload"
+Created workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
 `,
-		},
-		{
-			Name: "update workload to add maven through flags",
-			Args: []string{workloadName, flags.MavenArtifactFlagName, "spring-petclinic", flags.MavenVersionFlagName, "2.6.0", flags.MavenGroupFlagName, "org.springframework.samples", flags.MavenTypeFlagName, "jar", flags.YesFlagName},
-			GivenObjects: []client.Object{
-				parent.
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {}),
+		}, {
+			Name: "git source with allowed and non-allowed env var",
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				os.Setenv("TANZU_APPS_LABEL", "foo=var")
+				os.Setenv("TANZU_APPS_TYPE", "web")
+				return ctx, nil
 			},
-			ExpectUpdates: []client.Object{
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				os.Unsetenv("TANZU_APPS_LABEL")
+				os.Unsetenv("TANZU_APPS_TYPE")
+				return nil
+			},
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
-						Labels:    map[string]string{},
+						Labels: map[string]string{
+							"apps.tanzu.vmware.com/workload-type": "web",
+						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Params: []cartov1alpha1.Param{
-							{
-								Name:  "maven",
-								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0","type":"jar"}`)},
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
 							},
 						},
 					},
 				},
 			},
 			ExpectOutput: `
-Update workload:
-...
-  3,  3   |kind: Workload
-  4,  4   |metadata:
-  5,  5   |  name: my-workload
-  6,  6   |  namespace: default
-  7     - |spec: {}
-      7 + |spec:
-      8 + |  params:
-      9 + |  - name: maven
-     10 + |    value:
-     11 + |      artifactId: spring-petclinic
-     12 + |      groupId: org.springframework.samples
-     13 + |      type: jar
-     14 + |      version: 2.6.0
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    apps.tanzu.vmware.com/workload-type: web
+      7 + |  name: my-workload
+      8 + |  namespace: default
+      9 + |spec:
+     10 + |  source:
+     11 + |    git:
+     12 + |      ref:
+     13 + |        branch: main
+     14 + |      url: https://example.com/repo.git
 
-Updated workload "my-workload"
+Created workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
 `,
-		},
-		{
-			Name: "update workload to change maven info through flags",
-			Args: []string{workloadName, flags.MavenVersionFlagName, "2.6.1", flags.MavenTypeFlagName, "jar", flags.YesFlagName},
-			GivenObjects: []client.Object{
-				parent.
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Params(cartov1alpha1.Param{
-							Name:  "maven",
-							Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
-						})
-					}),
+		}, {
+			Name: "git source with allowed env var overwritten",
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				os.Setenv("TANZU_APPS_TYPE", "jar")
+				return ctx, nil
 			},
-			ExpectUpdates: []client.Object{
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				os.Unsetenv("TANZU_APPS_TYPE")
+				return nil
+			},
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.TypeFlagName, "web", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
-						Labels:    map[string]string{},
+						Labels: map[string]string{
+							"apps.tanzu.vmware.com/workload-type": "web",
+						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Params: []cartov1alpha1.Param{
-							{
-								Name:  "maven",
-								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.1","type":"jar"}`)},
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
 							},
 						},
 					},
 				},
 			},
 			ExpectOutput: `
-Update workload:
-...
-  9,  9   |  - name: maven
- 10, 10   |    value:
- 11, 11   |      artifactId: spring-petclinic
- 12, 12   |      groupId: org.springframework.samples
- 13     - |      version: 2.6.0
-     13 + |      type: jar
-     14 + |      version: 2.6.1
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    apps.tanzu.vmware.com/workload-type: web
+      7 + |  name: my-workload
+      8 + |  namespace: default
+      9 + |spec:
+     10 + |  source:
+     11 + |    git:
+     12 + |      ref:
+     13 + |        branch: main
+     14 + |      url: https://example.com/repo.git
 
-Updated workload "my-workload"
+Created workload "my-workload"
 
 To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
 `,
-		},
-		{
-			Name: "update workload to override maven info through params yaml",
-			Args: []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "spring-petclinic", "version": "2.6.0", "groupId": "org.springframework.samples"}`, flags.YesFlagName},
+		}, {
+			Name: "git source with plugin config defaults",
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(cli.DefaultsConfigKey, map[string]interface{}{
+					"namespace": "dev",
+					"type":      "worker",
+				})
+				return ctx, nil
+			},
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
 			GivenObjects: []client.Object{
-				parent.
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Params(cartov1alpha1.Param{
-							Name:  "maven",
-							Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"foo","groupId":"bar","version":"1.0.0","type":"baz"}`)},
-						})
+				diecorev1.NamespaceBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("dev")
 					}),
-			},
-			ExpectUpdates: []client.Object{
-				&cartov1alpha1.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
-						Name:      workloadName,
-						Labels:    map[string]string{},
-					},
-					Spec: cartov1alpha1.WorkloadSpec{
-						Params: []cartov1alpha1.Param{
-							{
-								Name:  "maven",
-								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","version":"2.6.0"}`)},
-							},
-						},
-					},
-				},
-			},
-			ExpectOutput: `
-Update workload:
-...
-  7,  7   |spec:
-  8,  8   |  params:
-  9,  9   |  - name: maven
- 10, 10   |    value:
- 11     - |      artifactId: foo
- 12     - |      groupId: bar
- 13     - |      type: baz
- 14     - |      version: 1.0.0
-     11 + |      artifactId: spring-petclinic
-     12 + |      groupId: org.springframework.samples
-     13 + |      version: 2.6.0
-
-Updated workload "my-workload"
-
-To see logs:   "tanzu apps workload tail my-workload"
-To get status: "tanzu apps workload get my-workload"
-
-`,
-		},
-		{
-			Name:         "create from maven artifact using paramyaml with type",
-			Args:         []string{workloadName, flags.ParamYamlFlagName, `maven={"artifactId": "spring-petclinic", "version": "2.6.0", "groupId": "org.springframework.samples", "type": "jar"}`, flags.YesFlagName},
-			GivenObjects: givenNamespaceDefault,
+			},
 			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
+						Namespace: "dev",
 						Name:      workloadName,
-						Labels:    map[string]string{},
+						Labels: map[string]string{
+							"apps.tanzu.vmware.com/workload-type": "worker",
+						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
-						Params: []cartov1alpha1.Param{
-							{
-								Name:  "maven",
-								Value: apiextensionsv1.JSON{Raw: []byte(`{"artifactId":"spring-petclinic","groupId":"org.springframework.samples","type":"jar","version":"2.6.0"}`)},
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
 							},
 						},
 					},
@@ -2937,49 +6483,63 @@ Create workload:
       2 + |apiVersion: carto.run/v1alpha1
       3 + |kind: Workload
       4 + |metadata:
-      5 + |  name: my-workload
-      6 + |  namespace: default
-      7 + |spec:
-      8 + |  params:
-      9 + |  - name: maven
-     10 + |    value:
-     11 + |      artifactId: spring-petclinic
-     12 + |      groupId: org.springframework.samples
-     13 + |      type: jar
-     14 + |      version: 2.6.0
+      5 + |  labels:
+      6 + |    apps.tanzu.vmware.com/workload-type: worker
+      7 + |  name: my-workload
+      8 + |  namespace: dev
+      9 + |spec:
+     10 + |  source:
+     11 + |    git:
+     12 + |      ref:
+     13 + |        branch: main
+     14 + |      url: https://example.com/repo.git
 
 Created workload "my-workload"
 
-To see logs:   "tanzu apps workload tail my-workload"
-To get status: "tanzu apps workload get my-workload"
+To see logs:   "tanzu apps workload tail my-workload --namespace dev"
+To get status: "tanzu apps workload get my-workload --namespace dev"
 
 `,
-		},
-		{
-			ShouldError: true,
-			Name:        "fails create with multiple param-yaml using invalid json",
-			Args: []string{flags.FilePathFlagName, "testdata/param-yaml.yaml",
-				flags.ParamYamlFlagName, `ports_json={"name": "smtp", "port": 1026`,
-				flags.ParamYamlFlagName, `ports_nesting_yaml=- deployment:\n    name: smtp\n    port: 1026`,
-				flags.YesFlagName},
-			ExpectOutput: "",
-		},
-		{
-			Name: "create with multiple param-yaml using valid json and yaml from file",
-			Args: []string{flags.FilePathFlagName, "testdata/workload-param-yaml.yaml",
-				flags.YesFlagName},
-			GivenObjects: givenNamespaceDefault,
+		}, {
+			Name: "file namespace over plugin config default",
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(cli.DefaultsConfigKey, map[string]interface{}{"namespace": "dev"})
+				return ctx, nil
+			},
+			Args: []string{flags.FilePathFlagName, "testdata/workload-custom-namespace.yaml", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				diecorev1.NamespaceBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("test-namespace")
+					}),
+			},
 			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: defaultNamespace,
+						Namespace: "test-namespace",
 						Name:      "spring-petclinic",
 						Labels: map[string]string{
-							apis.AppPartOfLabelName:               "spring-petclinic",
+							"app.kubernetes.io/part-of":           "spring-petclinic",
 							"apps.tanzu.vmware.com/workload-type": "web",
 						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
+						Env: []corev1.EnvVar{
+							{
+								Name:  "SPRING_PROFILES_ACTIVE",
+								Value: "mysql",
+							},
+						},
+						Resources: &corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("500m"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
 						Source: &cartov1alpha1.Source{
 							Git: &cartov1alpha1.GitSource{
 								URL: "https://github.com/spring-projects/spring-petclinic.git",
@@ -2988,15 +6548,6 @@ To get status: "tanzu apps workload get my-workload"
 								},
 							},
 						},
-						Params: []cartov1alpha1.Param{
-							{
-								Name:  "ports",
-								Value: apiextensionsv1.JSON{Raw: []byte(`{"ports":[{"name":"http","port":8080,"protocol":"TCP","targetPort":8080},{"name":"https","port":8443,"protocol":"TCP","targetPort":8443}]}`)},
-							}, {
-								Name:  "services",
-								Value: apiextensionsv1.JSON{Raw: []byte(`[{"image":"mysql:5.7","name":"mysql"},{"image":"postgres:9.6","name":"postgres"}]`)},
-							},
-						},
 					},
 				},
 			},
@@ -3010,47 +6561,35 @@ Create workload:
       6 + |    app.kubernetes.io/part-of: spring-petclinic
       7 + |    apps.tanzu.vmware.com/workload-type: web
       8 + |  name: spring-petclinic
-      9 + |  namespace: default
+      9 + |  namespace: test-namespace
      10 + |spec:
-     11 + |  params:
-     12 + |  - name: ports
-     13 + |    value:
-     14 + |      ports:
-     15 + |      - name: http
-     16 + |        port: 8080
-     17 + |        protocol: TCP
-     18 + |        targetPort: 8080
-     19 + |      - name: https
-     20 + |        port: 8443
-     21 + |        protocol: TCP
-     22 + |        targetPort: 8443
-     23 + |  - name: services
-     24 + |    value:
-     25 + |    - image: mysql:5.7
-     26 + |      name: mysql
-     27 + |    - image: postgres:9.6
-     28 + |      name: postgres
-     29 + |  source:
-     30 + |    git:
-     31 + |      ref:
-     32 + |        branch: main
-     33 + |      url: https://github.com/spring-projects/spring-petclinic.git
+     11 + |  env:
+     12 + |  - name: SPRING_PROFILES_ACTIVE
+     13 + |    value: mysql
+     14 + |  resources:
+     15 + |    limits:
+     16 + |      cpu: 500m
+     17 + |      memory: 1Gi
+     18 + |    requests:
+     19 + |      cpu: 100m
+     20 + |      memory: 1Gi
+     21 + |  source:
+     22 + |    git:
+     23 + |      ref:
+     24 + |        branch: main
+     25 + |      url: https://github.com/spring-projects/spring-petclinic.git
 
 Created workload "spring-petclinic"
 
-To see logs:   "tanzu apps workload tail spring-petclinic"
-To get status: "tanzu apps workload get spring-petclinic"
+To see logs:   "tanzu apps workload tail spring-petclinic --namespace test-namespace"
+To get status: "tanzu apps workload get spring-petclinic --namespace test-namespace"
 
 `,
 		}, {
-			Name: "git source with non-allowed env var",
+			Name: "plugin config defaults are not audited",
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
-				os.Setenv("TANZU_APPS_LABEL", "foo=var")
-				return ctx, nil
-			},
-			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
-				os.Unsetenv("TANZU_APPS_LABEL")
-				return nil
+				config.Viper.Set(cli.DefaultsConfigKey, map[string]interface{}{"type": "worker"})
+				return prepareAuditor(t, ctx, config, tc)
 			},
 			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
 			GivenObjects: givenNamespaceDefault,
@@ -3059,7 +6598,12 @@ To get status: "tanzu apps workload get spring-petclinic"
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: defaultNamespace,
 						Name:      workloadName,
-						Labels:    map[string]string{},
+						Labels: map[string]string{
+							"apps.tanzu.vmware.com/workload-type": "worker",
+						},
+						Annotations: map[string]string{
+							apis.LastModifiedByAnnotationName: `{"user":"alice","time":"2022-06-09T15:12:00Z","command":"apply","flags":["--git-branch","--git-repo","--yes"],"version":"v0.9.0"}`,
+						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
 						Source: &cartov1alpha1.Source{
@@ -3079,14 +6623,16 @@ Create workload:
       2 + |apiVersion: carto.run/v1alpha1
       3 + |kind: Workload
       4 + |metadata:
-      5 + |  name: my-workload
-      6 + |  namespace: default
-      7 + |spec:
-      8 + |  source:
-      9 + |    git:
-     10 + |      ref:
-     11 + |        branch: main
-     12 + |      url: https://example.com/repo.git
+      5 + |  labels:
+      6 + |    apps.tanzu.vmware.com/workload-type: worker
+      7 + |  name: my-workload
+      8 + |  namespace: default
+      9 + |spec:
+     10 + |  source:
+     11 + |    git:
+     12 + |      ref:
+     13 + |        branch: main
+     14 + |      url: https://example.com/repo.git
 
 Created workload "my-workload"
 
@@ -3095,14 +6641,13 @@ To get status: "tanzu apps workload get my-workload"
 
 `,
 		}, {
-			Name: "git source with allowed and non-allowed env var",
+			Name: "git source with env var over plugin config default",
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
-				os.Setenv("TANZU_APPS_LABEL", "foo=var")
 				os.Setenv("TANZU_APPS_TYPE", "web")
+				config.Viper.Set(cli.DefaultsConfigKey, map[string]interface{}{"type": "worker"})
 				return ctx, nil
 			},
 			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
-				os.Unsetenv("TANZU_APPS_LABEL")
 				os.Unsetenv("TANZU_APPS_TYPE")
 				return nil
 			},
@@ -3153,16 +6698,18 @@ To get status: "tanzu apps workload get my-workload"
 
 `,
 		}, {
-			Name: "git source with allowed env var overwritten",
+			Name: "git source with excluded env var",
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
-				os.Setenv("TANZU_APPS_TYPE", "jar")
+				os.Setenv("TANZU_APPS_TYPE", "web")
+				config.Viper.Set(cli.DefaultsConfigKey, map[string]interface{}{"type": "worker"})
+				config.Viper.Set(cli.ExcludeEnvVarsConfigKey, []string{"TANZU_APPS_TYPE"})
 				return ctx, nil
 			},
 			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
 				os.Unsetenv("TANZU_APPS_TYPE")
 				return nil
 			},
-			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.TypeFlagName, "web", flags.YesFlagName},
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
 			GivenObjects: givenNamespaceDefault,
 			ExpectCreates: []client.Object{
 				&cartov1alpha1.Workload{
@@ -3170,7 +6717,7 @@ To get status: "tanzu apps workload get my-workload"
 						Namespace: defaultNamespace,
 						Name:      workloadName,
 						Labels: map[string]string{
-							"apps.tanzu.vmware.com/workload-type": "web",
+							"apps.tanzu.vmware.com/workload-type": "worker",
 						},
 					},
 					Spec: cartov1alpha1.WorkloadSpec{
@@ -3192,7 +6739,7 @@ Create workload:
       3 + |kind: Workload
       4 + |metadata:
       5 + |  labels:
-      6 + |    apps.tanzu.vmware.com/workload-type: web
+      6 + |    apps.tanzu.vmware.com/workload-type: worker
       7 + |  name: my-workload
       8 + |  namespace: default
       9 + |spec:
@@ -3208,6 +6755,14 @@ To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
 `,
+		}, {
+			Name: "invalid plugin config default",
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(cli.DefaultsConfigKey, map[string]interface{}{"wait-timeout": "soon"})
+				return ctx, nil
+			},
+			Args:        []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
+			ShouldError: true,
 		}, {
 			Name: "update type via allowed env var",
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
@@ -3279,6 +6834,101 @@ To get status: "tanzu apps workload get my-workload"
 
 `,
 		},
+		{
+			Name:         "missing name without a terminal",
+			Args:         []string{flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+			Verify: func(t *testing.T, output string, err error) {
+				if msg := `name: Invalid value: ""`; err.Error() != msg {
+					t.Errorf("expected error %q, got %q", msg, err.Error())
+				}
+			},
+		},
+		{
+			Name:         "missing name on a terminal with yes",
+			Args:         []string{flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				return commands.StashTerminal(ctx, true), nil
+			},
+			ShouldError: true,
+			Verify: func(t *testing.T, output string, err error) {
+				if msg := `name: Invalid value: ""`; err.Error() != msg {
+					t.Errorf("expected error %q, got %q", msg, err.Error())
+				}
+			},
+		},
+		{
+			Name:         "prompt for missing name",
+			Args:         []string{flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch},
+			GivenObjects: givenNamespaceDefault,
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				return commands.StashTerminal(ctx, true), nil
+			},
+			ShouldError: true,
+			Verify: func(t *testing.T, output string, err error) {
+				if !strings.Contains(output, "Workload name:") || !strings.Contains(output, "Skipping workload apply:") {
+					t.Errorf("expected the name to be prompted for, got %q", output)
+				}
+			},
+		},
+		{
+			Name:         "prompt for missing source",
+			Args:         []string{workloadName},
+			GivenObjects: givenNamespaceDefault,
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				return commands.StashTerminal(ctx, true), nil
+			},
+			ShouldError: true,
+			Verify: func(t *testing.T, output string, err error) {
+				if !strings.Contains(output, "Git repository URL") || !strings.Contains(output, "Skipping workload apply:") {
+					t.Errorf("expected the source to be prompted for, got %q", output)
+				}
+			},
+		},
+		{
+			Name:         "prompted source only applies to its document",
+			Args:         []string{flags.FilePathFlagName, "testdata/workloads-prompt.yaml"},
+			GivenObjects: givenNamespaceDefault,
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				return commands.StashTerminal(ctx, true), nil
+			},
+			// each prompt asks for the position of the cursor before reading the answer
+			Stdin: []byte(strings.Join([]string{
+				"\x1b[1;1R\x1b[1;1R" + gitRepo + "\r",
+				"\x1b[1;1R\x1b[1;1R" + gitBranch + "\r",
+				"\x1b[1;1R\x1b[1;1Ry\r",
+				"\x1b[1;1R\x1b[1;1Ry\r",
+			}, "")),
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      "frontend",
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      "backend",
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Image: "ubuntu:focal",
+					},
+				},
+			},
+		},
 	}
 
 	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
@@ -3287,3 +6937,89 @@ To get status: "tanzu apps workload get my-workload"
 		return cmd
 	})
 }
+
+// induceFailureOnce only lets the reaction handle the first matching action
+func induceFailureOnce(reaction clitesting.ReactionFunc) clitesting.ReactionFunc {
+	induced := false
+	return func(action clitesting.Action) (bool, runtime.Object, error) {
+		if induced {
+			return false, nil, nil
+		}
+		handled, obj, err := reaction(action)
+		induced = handled
+		return handled, obj, err
+	}
+}
+
+// conflictOnceClient changes the workload on the cluster like another user would right before its first
+// apply, which then fails with a conflict on the resource version of the workload
+type conflictOnceClient struct {
+	cli.Client
+	change  func(workload *cartov1alpha1.Workload)
+	changed bool
+}
+
+func (c *conflictOnceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	patchOpts := &client.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	if c.changed || patch.Type() != types.ApplyPatchType || len(patchOpts.DryRun) != 0 {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	c.changed = true
+	workload := &cartov1alpha1.Workload{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), workload); err != nil {
+		return err
+	}
+	c.change(workload)
+	if err := c.Update(ctx, workload); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// namespacedClient fails the applies in a namespace that does not exist, like the API server
+type namespacedClient struct {
+	cli.Client
+}
+
+func (c *namespacedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.Get(ctx, client.ObjectKey{Name: obj.GetNamespace()}, &corev1.Namespace{}); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// undiscoverableClient fails to discover the kinds served by the cluster
+type undiscoverableClient struct {
+	cli.Client
+}
+
+func (c *undiscoverableClient) ToRESTMapper() (meta.RESTMapper, error) {
+	return nil, fmt.Errorf("the server is currently unable to handle the request")
+}
+
+func TestHelperProcess_DiffTool(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	// args after "--" are: <tool> <tool args...> <current> <new>
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	current, updated := args[len(args)-2], args[len(args)-1]
+	script := strings.Join(args[1:len(args)-2], " ")
+	if script != "delta --side-by-side" {
+		fmt.Fprintf(os.Stderr, "exec: %q: executable file not found in $PATH\n", args[1])
+		os.Exit(127)
+	}
+	lines := func(path string) int {
+		b, _ := os.ReadFile(path)
+		if len(b) == 0 {
+			return 0
+		}
+		return len(strings.Split(strings.TrimSpace(string(b)), "\n"))
+	}
+	fmt.Printf("%s %s (%d lines) %s (%d lines)\n", script, filepath.Base(current), lines(current), filepath.Base(updated), lines(updated))
+	os.Exit(1)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+// AuditRecord is recorded in the last-modified-by annotation of the workloads created or updated by
+// the CLI, so changes can be traced back to the command that made them. The values of the flags are
+// left out, they may hold credentials.
+type AuditRecord struct {
+	User    string   `json:"user"`
+	Time    string   `json:"time"`
+	Command string   `json:"command"`
+	Flags   []string `json:"flags"`
+	Version string   `json:"version"`
+}
+
+// Auditor records who changed a workload with which version of the CLI
+type Auditor struct {
+	Version string
+	User    func() string
+}
+
+// NewAuditor returns an auditor recording the current OS user
+func NewAuditor(version string) *Auditor {
+	return &Auditor{
+		Version: version,
+		User:    currentUser,
+	}
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+type auditorStashKey struct{}
+
+// StashAuditor sets the auditor recording the changes made to workloads, the changes are not recorded
+// without one
+func StashAuditor(ctx context.Context, auditor *Auditor) context.Context {
+	return context.WithValue(ctx, auditorStashKey{}, auditor)
+}
+
+func retrieveAuditor(ctx context.Context) *Auditor {
+	auditor, _ := ctx.Value(auditorStashKey{}).(*Auditor)
+	return auditor
+}
+
+// auditFlag defines --audit on the commands changing workloads
+func auditFlag(cmd *cobra.Command, audit *bool) {
+	cmd.Flags().BoolVar(audit, cli.StripDash(flags.AuditFlagName), true, "record who changed the workload, when, with which flags and version of the CLI in the \""+apis.LastModifiedByAnnotationName+"\" annotation")
+}
+
+// auditWorkload records the command about to create or update the workload in its last-modified-by
+// annotation, unless disabled with --audit=false
+func auditWorkload(ctx context.Context, workload *cartov1alpha1.Workload, audit bool) error {
+	auditor := retrieveAuditor(ctx)
+	if auditor == nil || !audit {
+		return nil
+	}
+	cmd := cli.CommandFromContext(ctx)
+	record := AuditRecord{
+		User:    auditor.User(),
+		Time:    retrieveClock(ctx)().UTC().Format(time.RFC3339),
+		Command: cmd.CommandPath(),
+		Flags:   []string{},
+		Version: auditor.Version,
+	}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		record.Flags = append(record.Flags, "--"+f.Name)
+	})
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	workload.MergeAnnotations(apis.LastModifiedByAnnotationName, string(b))
+	return nil
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/parsers"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+const (
+	SourceArgumentName = "source"
+	TargetArgumentName = "target"
+)
+
+// WorkloadCloneOptions creates a copy of a workload under another name, and optionally in
+// another namespace
+type WorkloadCloneOptions struct {
+	Namespace       string
+	TargetNamespace string
+	Source          string
+	Target          string
+	Labels          []string
+
+	AllowProtected bool
+	Audit          bool
+	Force          bool
+	Yes            bool
+}
+
+var (
+	_ validation.Validatable = (*WorkloadCloneOptions)(nil)
+	_ cli.Executable         = (*WorkloadCloneOptions)(nil)
+)
+
+func (opts *WorkloadCloneOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Namespace == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+	}
+	if opts.TargetNamespace != "" {
+		errs = errs.Also(validation.K8sName(opts.TargetNamespace, flags.TargetNamespaceFlagName))
+	}
+
+	if opts.Source == "" {
+		errs = errs.Also(validation.ErrMissingField(SourceArgumentName))
+	} else {
+		errs = errs.Also(validation.K8sName(opts.Source, SourceArgumentName))
+	}
+	if opts.Target == "" {
+		errs = errs.Also(validation.ErrMissingField(TargetArgumentName))
+	} else {
+		errs = errs.Also(validation.K8sName(opts.Target, TargetArgumentName))
+	}
+	// a workload cannot be cloned onto itself
+	if opts.Source != "" && opts.Source == opts.Target && opts.targetNamespace() == opts.Namespace {
+		errs = errs.Also(validation.ErrInvalidValue(opts.Target, TargetArgumentName))
+	}
+
+	errs = errs.Also(validation.DeletableKeyValues(opts.Labels, flags.LabelFlagName))
+
+	return errs
+}
+
+func (opts *WorkloadCloneOptions) Exec(ctx context.Context, c *cli.Config) error {
+	if err := validateProtectedNamespace(c, opts.targetNamespace(), opts.AllowProtected).ToAggregate(); err != nil {
+		return err
+	}
+	if !opts.Force {
+		errs := validation.FieldErrors{}
+		for _, label := range opts.Labels {
+			errs = errs.Also(validateProtectedPrefix(c, parsers.DeletableKeyValue(label)[0], flags.LabelFlagName))
+		}
+		if err := errs.ToAggregate(); err != nil {
+			return err
+		}
+	}
+
+	source := &cartov1alpha1.Workload{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Source}, source); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		c.Errorf("Workload %q not found\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Source))
+		return cli.SilenceError(err)
+	}
+
+	workload := opts.clone(c, source)
+	diff, _, err := printer.ResourceDiff(nil, workload, c.Scheme)
+	if err != nil {
+		return err
+	}
+	c.Printf("Clone workload %q to %q:\n", fmt.Sprintf("%s/%s", source.Namespace, source.Name), fmt.Sprintf("%s/%s", workload.Namespace, workload.Name))
+	c.Printf("%s\n", diff)
+
+	if !opts.Yes {
+		okToCreate := false
+		err := survey.AskOne(&survey.Confirm{
+			Message: "Do you want to create this workload?",
+		}, &okToCreate, printer.WithSurveyStdio(c.Stdin, c.Stdout, c.Stderr))
+		if err != nil || !okToCreate {
+			c.Infof("Skipping workload %q\n", workload.Name)
+			return nil
+		}
+	}
+
+	if err := auditWorkload(ctx, workload, opts.Audit); err != nil {
+		return err
+	}
+	if err := c.Create(ctx, workload); err != nil {
+		if !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+		c.Errorf("Workload %q already exists\n", fmt.Sprintf("%s/%s", workload.Namespace, workload.Name))
+		return cli.SilenceError(err)
+	}
+	c.Successf("Created workload %q\n", workload.Name)
+	c.Printf("\n")
+	DisplayCommandNextSteps(c, workload)
+	return nil
+}
+
+// clone copies the source workload into a new workload named after the target, with the labels
+// of --label set and removed
+func (opts *WorkloadCloneOptions) clone(c *cli.Config, source *cartov1alpha1.Workload) *cartov1alpha1.Workload {
+	workload := sanitizeWorkload(c, source, opts.targetNamespace(), opts.Target)
+	for _, label := range opts.Labels {
+		parts := parsers.DeletableKeyValue(label)
+		if len(parts) == 1 {
+			delete(workload.Labels, parts[0])
+		} else {
+			workload.MergeLabels(parts[0], parts[1])
+		}
+	}
+	return workload
+}
+
+// sanitizeWorkload copies the labels, annotations and spec of the source workload into a new
+// workload with the namespace and name. The metadata set by the cluster, the status and the last
+// applied configuration of kubectl, which names the source workload, are not copied. Neither are
+// the labels and annotations with a prefix protected by the plugin config, owned by the controllers
+// of the source workload, nor the bookkeeping of the CLI: the audit annotation, the pull request
+// label and the paused param.
+func sanitizeWorkload(c *cli.Config, source *cartov1alpha1.Workload, namespace, name string) *cartov1alpha1.Workload {
+	copied := source.DeepCopy()
+	workload := &cartov1alpha1.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        name,
+			Labels:      copied.Labels,
+			Annotations: copied.Annotations,
+		},
+		Spec: copied.Spec,
+	}
+	delete(workload.Annotations, corev1.LastAppliedConfigAnnotation)
+	delete(workload.Annotations, apis.LastModifiedByAnnotationName)
+	delete(workload.Labels, apis.PullRequestLabelName)
+	for key := range workload.Labels {
+		if _, ok := protectedPrefix(c, key); ok {
+			delete(workload.Labels, key)
+		}
+	}
+	for key := range workload.Annotations {
+		if _, ok := protectedPrefix(c, key); ok {
+			delete(workload.Annotations, key)
+		}
+	}
+	if len(workload.Labels) == 0 {
+		workload.Labels = nil
+	}
+	if len(workload.Annotations) == 0 {
+		workload.Annotations = nil
+	}
+	workload.Spec.MergePaused(false)
+	return workload
+}
+
+func (opts *WorkloadCloneOptions) targetNamespace() string {
+	if opts.TargetNamespace != "" {
+		return opts.TargetNamespace
+	}
+	return opts.Namespace
+}
+
+func workloadCloneArg(argName string, name *string) cli.Arg {
+	return cli.Arg{
+		Name:  argName,
+		Arity: 1,
+		Set: func(cmd *cobra.Command, args []string, offset int) error {
+			*name = args[offset]
+			return nil
+		},
+	}
+}
+
+func NewWorkloadCloneCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	opts := &WorkloadCloneOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "clone",
+		Short: "Create a copy of a workload",
+		Long: strings.TrimSpace(`
+Create a copy of an existing workload under another name, for example a copy of a
+baseline workload for each developer or branch.
+
+The labels, annotations and spec of the source workload are copied, the metadata
+set by the cluster, the status, the labels and annotations with a prefix protected
+by the plugin config and the pull request label, audit annotation and paused param
+of the CLI are not. Labels of the copy are set and removed
+with --label. The copy is created in the namespace of the source workload unless
+--target-namespace is set.
+`),
+		Example:           examplesFor(c, "workload clone"),
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		workloadCloneArg(SourceArgumentName, &opts.Source),
+		workloadCloneArg(TargetArgumentName, &opts.Target),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVar(&opts.TargetNamespace, cli.StripDash(flags.TargetNamespaceFlagName), "", "`name` of the namespace to create the copy in, defaults to the namespace of the source workload")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.TargetNamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringSliceVarP(&opts.Labels, cli.StripDash(flags.LabelFlagName), "l", []string{}, "label of the copy is represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
+	cmd.Flags().BoolVar(&opts.AllowProtected, cli.StripDash(flags.AllowProtectedFlagName), false, "allow creating the copy in a namespace protected by the plugin config")
+	auditFlag(cmd, &opts.Audit)
+	cmd.Flags().BoolVar(&opts.Force, cli.StripDash(flags.ForceFlagName), false, "allow setting labels of the copy with a prefix protected by the plugin config")
+	cmd.Flags().BoolVarP(&opts.Yes, cli.StripDash(flags.YesFlagName), "y", false, "accept all prompts")
+
+	return cmd
+}
@@ -0,0 +1,426 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestWorkloadCloneOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name:        "invalid empty",
+			Validatable: &commands.WorkloadCloneOptions{},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrMissingField(flags.NamespaceFlagName),
+				validation.ErrMissingField(commands.SourceArgumentName),
+				validation.ErrMissingField(commands.TargetArgumentName),
+			),
+		},
+		{
+			Name: "valid",
+			Validatable: &commands.WorkloadCloneOptions{
+				Namespace: "default",
+				Source:    "my-workload",
+				Target:    "my-workload-feature",
+				Labels:    []string{"branch=feature", "team-"},
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "same name in another namespace",
+			Validatable: &commands.WorkloadCloneOptions{
+				Namespace:       "default",
+				TargetNamespace: "dev",
+				Source:          "my-workload",
+				Target:          "my-workload",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "onto itself",
+			Validatable: &commands.WorkloadCloneOptions{
+				Namespace:       "default",
+				TargetNamespace: "default",
+				Source:          "my-workload",
+				Target:          "my-workload",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("my-workload", commands.TargetArgumentName),
+		},
+		{
+			Name: "invalid names",
+			Validatable: &commands.WorkloadCloneOptions{
+				Namespace:       "default",
+				TargetNamespace: "Dev",
+				Source:          "my-workload",
+				Target:          "my_workload",
+				Labels:          []string{"=feature"},
+			},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.K8sName("Dev", flags.TargetNamespaceFlagName),
+				validation.K8sName("my_workload", commands.TargetArgumentName),
+				validation.DeletableKeyValues([]string{"=feature"}, flags.LabelFlagName),
+			),
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestWorkloadCloneCommand(t *testing.T) {
+	defaultNamespace := "default"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+
+	source := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name("my-workload")
+			d.Namespace(defaultNamespace)
+			d.UID("2c4f8c1e-7f0b-4d52-8a3e-5d4c8b7a9f10")
+			d.ResourceVersion("42")
+			d.Generation(3)
+			d.CreationTimestamp(metav1.Date(2022, 1, 1, 0, 0, 0, 0, metav1.Now().Location()))
+			d.AddLabel(apis.AppPartOfLabelName, "my-app")
+			d.AddLabel("team", "checkout")
+			d.AddAnnotation(corev1.LastAppliedConfigAnnotation, `{"metadata":{"name":"my-workload"}}`)
+			d.AddAnnotation("example.com/reviewed-by", "my-team")
+		}).
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Source(&cartov1alpha1.Source{
+				Git: &cartov1alpha1.GitSource{
+					URL: "https://example.com/my-workload.git",
+					Ref: cartov1alpha1.GitRef{Branch: "main"},
+				},
+			})
+		}).
+		StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+			d.ConditionsDie(diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionTrue))
+		})
+	clone := func(namespace, name string) *diecartov1alpha1.WorkloadDie {
+		return diecartov1alpha1.WorkloadBlank.
+			MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+				d.Name(name)
+				d.Namespace(namespace)
+				d.AddLabel(apis.AppPartOfLabelName, "my-app")
+				d.AddLabel("team", "checkout")
+				d.AddAnnotation("example.com/reviewed-by", "my-team")
+			}).
+			SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+				d.Source(&cartov1alpha1.Source{
+					Git: &cartov1alpha1.GitSource{
+						URL: "https://example.com/my-workload.git",
+						Ref: cartov1alpha1.GitRef{Branch: "main"},
+					},
+				})
+			})
+	}
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "invalid args",
+			Args:        []string{"my-workload"},
+			ShouldError: true,
+		},
+		{
+			Name:         "clone workload",
+			Args:         []string{"my-workload", "my-workload-feature", flags.LabelFlagName, "branch=feature", flags.LabelFlagName, "team-", flags.YesFlagName},
+			GivenObjects: []client.Object{source},
+			ExpectCreates: []client.Object{
+				clone(defaultNamespace, "my-workload-feature").
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Labels(map[string]string{
+							apis.AppPartOfLabelName: "my-app",
+							"branch":                "feature",
+						})
+					}),
+			},
+			ExpectOutput: `
+Clone workload "default/my-workload" to "default/my-workload-feature":
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  annotations:
+      6 + |    example.com/reviewed-by: my-team
+      7 + |  labels:
+      8 + |    app.kubernetes.io/part-of: my-app
+      9 + |    branch: feature
+     10 + |  name: my-workload-feature
+     11 + |  namespace: default
+     12 + |spec:
+     13 + |  source:
+     14 + |    git:
+     15 + |      ref:
+     16 + |        branch: main
+     17 + |      url: https://example.com/my-workload.git
+
+Created workload "my-workload-feature"
+
+To see logs:   "tanzu apps workload tail my-workload-feature"
+To get status: "tanzu apps workload get my-workload-feature"
+`,
+		},
+		{
+			Name:         "clone workload to another namespace",
+			Args:         []string{"my-workload", "my-workload", flags.TargetNamespaceFlagName, "dev", flags.YesFlagName},
+			GivenObjects: []client.Object{source},
+			ExpectCreates: []client.Object{
+				clone("dev", "my-workload"),
+			},
+			ExpectOutput: `
+Clone workload "default/my-workload" to "dev/my-workload":
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  annotations:
+      6 + |    example.com/reviewed-by: my-team
+      7 + |  labels:
+      8 + |    app.kubernetes.io/part-of: my-app
+      9 + |    team: checkout
+     10 + |  name: my-workload
+     11 + |  namespace: dev
+     12 + |spec:
+     13 + |  source:
+     14 + |    git:
+     15 + |      ref:
+     16 + |        branch: main
+     17 + |      url: https://example.com/my-workload.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload --namespace dev"
+To get status: "tanzu apps workload get my-workload --namespace dev"
+`,
+		},
+		{
+			Name:         "clone workload with audit record",
+			Args:         []string{"my-workload", "my-workload", flags.TargetNamespaceFlagName, "dev", flags.YesFlagName},
+			Prepare:      prepareAuditor,
+			GivenObjects: []client.Object{source},
+			ExpectCreates: []client.Object{
+				clone("dev", "my-workload").
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"alice","time":"2022-06-09T15:12:00Z","command":"clone","flags":["--target-namespace","--yes"],"version":"v0.9.0"}`)
+					}),
+			},
+			ExpectOutput: `
+Clone workload "default/my-workload" to "dev/my-workload":
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  annotations:
+      6 + |    example.com/reviewed-by: my-team
+      7 + |  labels:
+      8 + |    app.kubernetes.io/part-of: my-app
+      9 + |    team: checkout
+     10 + |  name: my-workload
+     11 + |  namespace: dev
+     12 + |spec:
+     13 + |  source:
+     14 + |    git:
+     15 + |      ref:
+     16 + |        branch: main
+     17 + |      url: https://example.com/my-workload.git
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload --namespace dev"
+To get status: "tanzu apps workload get my-workload --namespace dev"
+`,
+		},
+		{
+			Name:        "source not found",
+			Args:        []string{"my-workload", "my-workload-feature", flags.YesFlagName},
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/my-workload" not found
+`,
+		},
+		{
+			Name:         "target already exists",
+			Args:         []string{"my-workload", "my-workload-feature", flags.YesFlagName},
+			GivenObjects: []client.Object{source, clone(defaultNamespace, "my-workload-feature")},
+			ShouldError:  true,
+			ExpectCreates: []client.Object{
+				clone(defaultNamespace, "my-workload-feature"),
+			},
+			ExpectOutput: `
+Clone workload "default/my-workload" to "default/my-workload-feature":
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  annotations:
+      6 + |    example.com/reviewed-by: my-team
+      7 + |  labels:
+      8 + |    app.kubernetes.io/part-of: my-app
+      9 + |    team: checkout
+     10 + |  name: my-workload-feature
+     11 + |  namespace: default
+     12 + |spec:
+     13 + |  source:
+     14 + |    git:
+     15 + |      ref:
+     16 + |        branch: main
+     17 + |      url: https://example.com/my-workload.git
+
+Workload "default/my-workload-feature" already exists
+`,
+		},
+		{
+			Name: "protected target namespace",
+			Args: []string{"my-workload", "my-workload", flags.TargetNamespaceFlagName, "tap-install", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.ProtectedNamespacesConfigKey, []string{"tap-install"})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{source},
+			ShouldError:  true,
+		},
+		{
+			Name: "clone workload without protected labels and bookkeeping",
+			Args: []string{"my-workload", "my-workload-feature", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{
+				source.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("kapp.k14s.io/app", "1654791120")
+						d.AddLabel(apis.PullRequestLabelName, "42")
+						d.AddAnnotation("kapp.k14s.io/identity", "v1;default/carto.run/Workload/my-workload;carto.run/v1alpha1")
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"bob","time":"2022-06-01T10:00:00Z","command":"apply","version":"v0.9.0"}`)
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.DieStamp(func(r *cartov1alpha1.WorkloadSpec) {
+							r.MergePaused(true)
+						})
+					}),
+			},
+			ExpectCreates: []client.Object{
+				clone(defaultNamespace, "my-workload-feature"),
+			},
+			ExpectOutput: `
+Clone workload "default/my-workload" to "default/my-workload-feature":
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  annotations:
+      6 + |    example.com/reviewed-by: my-team
+      7 + |  labels:
+      8 + |    app.kubernetes.io/part-of: my-app
+      9 + |    team: checkout
+     10 + |  name: my-workload-feature
+     11 + |  namespace: default
+     12 + |spec:
+     13 + |  source:
+     14 + |    git:
+     15 + |      ref:
+     16 + |        branch: main
+     17 + |      url: https://example.com/my-workload.git
+
+Created workload "my-workload-feature"
+
+To see logs:   "tanzu apps workload tail my-workload-feature"
+To get status: "tanzu apps workload get my-workload-feature"
+`,
+		},
+		{
+			Name: "protected label",
+			Args: []string{"my-workload", "my-workload-feature", flags.LabelFlagName, "kapp.k14s.io/app=1654791120", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{source},
+			ShouldError:  true,
+			Verify: func(t *testing.T, output string, err error) {
+				msg := `--label: Forbidden: "kapp.k14s.io/app" uses the prefix "kapp.k14s.io/" which is owned by the platform, changing it may break controllers managing the workload. Use --force to override`
+				if err == nil || err.Error() != msg {
+					t.Errorf("expected error %q, got %v", msg, err)
+				}
+			},
+		},
+		{
+			Name: "protected label with force",
+			Args: []string{"my-workload", "my-workload-feature", flags.LabelFlagName, "kapp.k14s.io/app=1654791120", flags.ForceFlagName, flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{source},
+			ExpectCreates: []client.Object{
+				clone(defaultNamespace, "my-workload-feature").
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("kapp.k14s.io/app", "1654791120")
+					}),
+			},
+			ExpectOutput: `
+Clone workload "default/my-workload" to "default/my-workload-feature":
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  annotations:
+      6 + |    example.com/reviewed-by: my-team
+      7 + |  labels:
+      8 + |    app.kubernetes.io/part-of: my-app
+      9 + |    kapp.k14s.io/app: "1654791120"
+     10 + |    team: checkout
+     11 + |  name: my-workload-feature
+     12 + |  namespace: default
+     13 + |spec:
+     14 + |  source:
+     15 + |    git:
+     16 + |      ref:
+     17 + |        branch: main
+     18 + |      url: https://example.com/my-workload.git
+
+Created workload "my-workload-feature"
+
+To see logs:   "tanzu apps workload tail my-workload-feature"
+To get status: "tanzu apps workload get my-workload-feature"
+`,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadCloneCommand(ctx, c)
+	})
+}
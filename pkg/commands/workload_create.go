@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -54,10 +55,26 @@ func (opts *WorkloadCreateOptions) Validate(ctx context.Context) validation.Fiel
 }
 
 func (opts *WorkloadCreateOptions) Exec(ctx context.Context, c *cli.Config) error {
+	stdout, restoreOutput := opts.redirectOutput(c)
+	defer restoreOutput()
+
+	if err := opts.LoadMetadataFiles(); err != nil {
+		return err
+	}
+	if err := opts.LoadParamFiles(); err != nil {
+		return err
+	}
+	if err := opts.LoadBuildEnvFile(); err != nil {
+		return err
+	}
+	if err := opts.ValidateProtectedPrefixes(c).ToAggregate(); err != nil {
+		return err
+	}
+
 	workload := &cartov1alpha1.Workload{}
 
 	if opts.FilePath != "" {
-		if err := opts.WorkloadOptions.LoadInputWorkload(c.Stdin, workload); err != nil {
+		if err := opts.WorkloadOptions.LoadInputWorkload(ctx, c.Stdin, workload); err != nil {
 			return err
 		}
 	}
@@ -68,15 +85,20 @@ func (opts *WorkloadCreateOptions) Exec(ctx context.Context, c *cli.Config) erro
 	if workload.Namespace == "" || cli.CommandFromContext(ctx).Flags().Changed(cli.StripDash(flags.NamespaceFlagName)) {
 		workload.Namespace = opts.Namespace
 	}
+	if err := validateProtectedNamespace(c, workload.Namespace, opts.AllowProtected).ToAggregate(); err != nil {
+		return err
+	}
 
 	existingWorkload := &cartov1alpha1.Workload{}
+	var namespace *corev1.Namespace
 
 	if err := c.Get(ctx, client.ObjectKey{Namespace: workload.Namespace, Name: workload.Name}, existingWorkload); err != nil {
 		// return err, except when not found
 		if !apierrs.IsNotFound(err) {
 			return err
 		} else if apierrs.IsNotFound(err) {
-			if nsErr := validateNamespace(ctx, c, opts.Namespace); nsErr != nil {
+			var nsErr error
+			if namespace, nsErr = opts.missingNamespace(ctx, c, opts.Namespace); nsErr != nil {
 				return err
 			}
 		}
@@ -91,6 +113,14 @@ func (opts *WorkloadCreateOptions) Exec(ctx context.Context, c *cli.Config) erro
 	}
 
 	ctx = opts.ApplyOptionsToWorkload(ctx, workload)
+	ctx, err := applyWorkloadProfile(ctx, c.Viper, workload)
+	if err != nil {
+		return err
+	}
+	ctx, err = applyNamespaceDefaults(ctx, c, workload)
+	if err != nil {
+		return err
+	}
 
 	// validate complex flag interactions with existing state
 	errs := workload.Validate()
@@ -106,9 +136,32 @@ func (opts *WorkloadCreateOptions) Exec(ctx context.Context, c *cli.Config) erro
 		return err
 	}
 
+	if err := opts.validateServiceRefs(ctx, c, workload.Namespace); err != nil {
+		return err
+	}
+
+	if err := opts.validateImagePullSecrets(ctx, c, workload.Namespace); err != nil {
+		return err
+	}
+
+	if err := opts.validateType(ctx, c, workload); err != nil {
+		return err
+	}
+
+	if err := opts.ResolveGitPR(ctx, c, workload); err != nil {
+		return err
+	}
+
+	if err := opts.PinImage(ctx, c, workload); err != nil {
+		return err
+	}
+
+	if err := opts.PullSourceImage(ctx, c, workload); err != nil {
+		return err
+	}
+
 	if opts.DryRun {
-		cli.DryRunResource(ctx, workload, workload.GetGroupVersionKind())
-		return nil
+		return opts.PrintDryRun(ctx, c, namespace, workload)
 	}
 
 	// If user answers yes to survey prompt about publishing source, continue with workload creation
@@ -118,7 +171,7 @@ func (opts *WorkloadCreateOptions) Exec(ctx context.Context, c *cli.Config) erro
 		return nil
 	}
 
-	okToCreate, err := opts.Create(ctx, c, workload)
+	okToCreate, err := opts.Create(ctx, c, namespace, workload)
 	if err != nil {
 		return err
 	}
@@ -130,16 +183,19 @@ func (opts *WorkloadCreateOptions) Exec(ctx context.Context, c *cli.Config) erro
 	}
 
 	anyTail := opts.Tail || opts.TailTimestamps
-	if okToCreate && (opts.Wait || anyTail) {
-		c.Infof("Waiting for workload %q to become ready...\n", opts.Name)
+	if okToCreate && (opts.waiting() || anyTail) {
+		c.Infof("Waiting for workload %q to %s...\n", opts.Name, opts.waitGoal())
 
+		ctx := withPollBackoff(ctx, c)
+		latency := newLatencyRecorder(ctx, workload)
+		progress := opts.newConditionProgress(c, workload)
 		workers := []wait.Worker{
 			func(ctx context.Context) error {
 				clientWithWatch, err := watch.GetWatcher(ctx, c)
 				if err != nil {
 					panic(err)
 				}
-				return wait.UntilCondition(ctx, clientWithWatch, types.NamespacedName{Name: workload.Name, Namespace: workload.Namespace}, &cartov1alpha1.WorkloadList{}, cartov1alpha1.WorkloadReadyConditionFunc)
+				return wait.UntilCondition(ctx, clientWithWatch, types.NamespacedName{Name: workload.Name, Namespace: workload.Namespace}, &cartov1alpha1.WorkloadList{}, progress.Condition(latency.Condition(opts.waitCondition())))
 			},
 		}
 
@@ -150,20 +206,30 @@ func (opts *WorkloadCreateOptions) Exec(ctx context.Context, c *cli.Config) erro
 					panic(err)
 				}
 				containers := []string{}
-				return logs.Tail(ctx, c, opts.Namespace, selector, containers, time.Second, opts.TailTimestamps)
+				return logs.Tail(ctx, c, opts.Namespace, selector, containers, time.Second, logs.AllLines, opts.TailTimestamps)
 			})
 		}
 
 		if err := wait.Race(ctx, opts.WaitTimeout, workers); err != nil {
 			if err == context.DeadlineExceeded {
-				c.Printf("%s timeout after %s waiting for %q to become ready\n", printer.Serrorf("Error:"), opts.WaitTimeout, opts.Name)
-				return cli.SilenceError(err)
+				c.Printf("%s timeout after %s waiting for %q to %s\n", printer.Serrorf("Error:"), opts.WaitTimeout, opts.Name, opts.waitGoal())
+				return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeTimeout))
 			}
 			c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+			if errors.Is(err, wait.ErrConditionFailed) {
+				return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeFailedCondition))
+			}
 			return cli.SilenceError(err)
 		}
 
-		c.Infof("Workload %q is ready\n", opts.Name)
+		c.Infof("Workload %q %s\n", opts.Name, opts.waitReached())
+		opts.printLatency(c, workload, latency.Latency())
+		if err := opts.Verify(ctx, c, workload); err != nil {
+			return err
+		}
+		if err := opts.printNameAndURL(ctx, c, stdout, workload); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -177,8 +243,9 @@ func NewWorkloadCreateCommand(ctx context.Context, c *cli.Config) *cobra.Command
 	opts.LoadDefaults(c)
 
 	cmd := &cobra.Command{
-		Use:   "create",
-		Short: "Create a workload with specified configuration",
+		Use:     "create",
+		Aliases: []string{"c"},
+		Short:   "Create a workload with specified configuration",
 		Long: strings.TrimSpace(`
 Create a workload with specified configuration.
 
@@ -188,11 +255,7 @@ Workload configuration options include:
 - environment variables
 - services to bind
 `),
-		Example: strings.Join([]string{
-			fmt.Sprintf("%s workload create my-workload %s https://example.com/my-workload.git", c.Name, flags.GitRepoFlagName),
-			fmt.Sprintf("%s workload create my-workload %s . %s registry.example/repository:tag", c.Name, flags.LocalPathFlagName, flags.SourceImageFlagName),
-			fmt.Sprintf("%s workload create %s workload.yaml", c.Name, flags.FilePathFlagName),
-		}, "\n"),
+		Example: examplesFor(c, "workload create"),
 		PreRunE: cli.ValidateE(ctx, opts),
 		RunE:    cli.ExecE(ctx, c, opts),
 	}
@@ -203,6 +266,7 @@ Workload configuration options include:
 
 	// Define common flags
 	opts.DefineFlags(ctx, c, cmd)
+	opts.DefineCreateNamespaceFlags(ctx, c, cmd)
 
 	// Bind flags to environment variables
 	opts.DefineEnvVars(ctx, c, cmd)
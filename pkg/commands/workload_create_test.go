@@ -27,6 +27,7 @@ import (
 	diemetav1 "dies.dev/apis/meta/v1"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/mock"
+	rtesting "github.com/vmware-labs/reconciler-runtime/testing"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -74,6 +75,31 @@ func TestWorkloadCreateOptionsValidate(t *testing.T) {
 			},
 			ExpectFieldErrors: validation.ErrInvalidArrayValue("FOO", flags.EnvFlagName, 0),
 		},
+		{
+			Name: "namespace labels",
+			Validatable: &commands.WorkloadCreateOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace:       "default",
+					Name:            "my-resource",
+					CreateNamespace: true,
+					NamespaceLabels: []string{"team=payments"},
+				},
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "namespace labels without create namespace",
+			Validatable: &commands.WorkloadCreateOptions{
+				WorkloadOptions: commands.WorkloadOptions{
+					Namespace:       "default",
+					Name:            "my-resource",
+					NamespaceLabels: []string{"team"},
+				},
+			},
+			ExpectFieldErrors: validation.ErrInvalidArrayValue("team", flags.NamespaceLabelFlagName, 0).Also(
+				validation.ErrMissingField(flags.CreateNamespaceFlagName),
+			),
+		},
 		{
 			Name: "invalid build env options",
 			Validatable: &commands.WorkloadCreateOptions{
@@ -121,6 +147,22 @@ func TestWorkloadCreateCommand(t *testing.T) {
 			Args:         []string{workloadName},
 			GivenObjects: givenNamespaceDefault,
 		},
+		{
+			Name: "protected namespace",
+			Args: []string{workloadName, flags.NamespaceFlagName, "kube-system", flags.YesFlagName},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(commands.ProtectedNamespacesConfigKey, []string{"kube-system"})
+				return c
+			}(),
+			ShouldError: true,
+			Verify: func(t *testing.T, output string, err error) {
+				msg := `--namespace: Forbidden: namespace "kube-system" is protected by the platform, workloads are not expected to be changed in it. Use --allow-protected to override`
+				if err.Error() != msg {
+					t.Errorf("expected error %q, got %q", msg, err.Error())
+				}
+			},
+		},
 		{
 			Name:         "dry run",
 			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.DryRunFlagName, flags.YesFlagName},
@@ -143,6 +185,310 @@ status:
   supplyChainRef: {}
 `,
 		},
+		{
+			Name:         "build env from files and flags",
+			Args:         []string{flags.FilePathFlagName, "-", flags.BuildEnvFileFlagName, "testdata/build-env.properties", flags.BuildEnvFlagName, "BP_JVM_VERSION=21", flags.BuildEnvFlagName, "BP_DEBUG-", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			Stdin: []byte(`
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: my-workload
+  namespace: default
+spec:
+  build:
+    env:
+    - name: BP_MAVEN_POM_FILE
+      value: skip-pom.xml
+    - name: BP_DEBUG
+      value: "true"
+    - name: BP_MAVEN_POM_FILE
+      value: duplicate-pom.xml
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://example.com/repo.git
+`),
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: null
+  name: my-workload
+  namespace: default
+spec:
+  build:
+    env:
+    - name: BP_MAVEN_POM_FILE
+      value: pom.xml
+    - name: BP_JVM_VERSION
+      value: "21"
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://example.com/repo.git
+status:
+  supplyChainRef: {}
+`,
+		},
+		{
+			Name:         "missing build env file",
+			Args:         []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.BuildEnvFileFlagName, "testdata/missing.properties", flags.DryRunFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name: "create namespace",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.NamespaceFlagName, "foo", flags.CreateNamespaceFlagName, flags.YesFlagName},
+			ExpectCreates: []client.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+					},
+				},
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      workloadName,
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create namespace:
+      1 + |---
+      2 + |apiVersion: v1
+      3 + |kind: Namespace
+      4 + |metadata:
+      5 + |  name: foo
+      6 + |spec: {}
+
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: foo
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created namespace "foo"
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload --namespace foo"
+To get status: "tanzu apps workload get my-workload --namespace foo"
+
+`,
+		},
+		{
+			Name: "workload profile",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LabelFlagName, "team=payments", flags.YesFlagName},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(commands.WorkloadProfileConfigKey, map[string]interface{}{
+					"labels": []string{"cost-center=1234", "team=platform"},
+					"namespaces": map[string]interface{}{
+						defaultNamespace: map[string]interface{}{
+							"labels": []string{"cost-center=5678"},
+							"params": []string{"scanning=strict"},
+						},
+					},
+				})
+				return c
+			}(),
+			GivenObjects: givenNamespaceDefault,
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels: map[string]string{
+							"cost-center": "5678",
+							"team":        "payments",
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Params: []cartov1alpha1.Param{
+							{Name: "scanning", Value: apiextensionsv1.JSON{Raw: []byte(`"strict"`)}},
+						},
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    cost-center: "5678"
+      7 + |    team: payments
+      8 + |  name: my-workload
+      9 + |  namespace: default
+     10 + |spec:
+     11 + |  params:
+     12 + |  - name: scanning
+     13 + |    value: strict
+     14 + |  source:
+     15 + |    git:
+     16 + |      ref:
+     17 + |        branch: main
+     18 + |      url: https://example.com/repo.git
+
+NOTICE: label "cost-center" set to "5678" by the workload-profile of the plugin config for namespace "default".
+
+NOTICE: param "scanning" set to "strict" by the workload-profile of the plugin config for namespace "default".
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name: "invalid workload profile",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(commands.WorkloadProfileConfigKey, map[string]interface{}{
+					"labels": []string{"cost-center"},
+				})
+				return c
+			}(),
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
+		{
+			Name: "namespace defaults",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.LabelFlagName, "team=payments", flags.YesFlagName},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(commands.WorkloadProfileConfigKey, map[string]interface{}{
+					"params": []string{"scanning=strict"},
+				})
+				return c
+			}(),
+			GivenObjects: []client.Object{
+				givenNamespaceDefault[0],
+				diecorev1.ConfigMapBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(commands.NamespaceDefaultsConfigMapName)
+						d.Namespace(defaultNamespace)
+					}).
+					AddData("labels", "cost-center=1234\nteam=platform\n").
+					AddData("annotations", "# owners are reached on call\nowner=platform\n").
+					AddData("params", "scanning=relaxed\n"),
+			},
+			ExpectCreates: []client.Object{
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+						Labels: map[string]string{
+							"cost-center": "1234",
+							"team":        "payments",
+						},
+						Annotations: map[string]string{
+							"owner": "platform",
+						},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Params: []cartov1alpha1.Param{
+							{Name: "scanning", Value: apiextensionsv1.JSON{Raw: []byte(`"strict"`)}},
+						},
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  annotations:
+      6 + |    owner: platform
+      7 + |  labels:
+      8 + |    cost-center: "1234"
+      9 + |    team: payments
+     10 + |  name: my-workload
+     11 + |  namespace: default
+     12 + |spec:
+     13 + |  params:
+     14 + |  - name: scanning
+     15 + |    value: strict
+     16 + |  source:
+     17 + |    git:
+     18 + |      ref:
+     19 + |        branch: main
+     20 + |      url: https://example.com/repo.git
+
+NOTICE: param "scanning" set to "strict" by the workload-profile of the plugin config for all namespaces.
+
+NOTICE: label "cost-center" set to "1234" by ConfigMap "apps-cli-defaults" of namespace "default".
+
+NOTICE: annotation "owner" set to "platform" by ConfigMap "apps-cli-defaults" of namespace "default".
+
+Created workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+`,
+		},
+		{
+			Name: "invalid namespace defaults",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				givenNamespaceDefault[0],
+				diecorev1.ConfigMapBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(commands.NamespaceDefaultsConfigMapName)
+						d.Namespace(defaultNamespace)
+					}).
+					AddData("labels", "cost-center\n"),
+			},
+			ShouldError: true,
+			Verify: func(t *testing.T, output string, err error) {
+				msg := `invalid ConfigMap "default/apps-cli-defaults": labels: line 1: expected "key=value", got "cost-center"`
+				if err.Error() != msg {
+					t.Errorf("expected error %q, got %q", msg, err.Error())
+				}
+			},
+		},
 		{
 			Name: "wait error for false condition",
 			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.WaitFlagName},
@@ -190,6 +536,7 @@ status:
 				},
 			},
 			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeFailedCondition),
 			ExpectOutput: `
 Create workload:
       1 + |---
@@ -211,6 +558,7 @@ To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
 Waiting for workload "my-workload" to become ready...
+  Ready=False (OopsieDoodle)
 Error: Failed to become ready: a hopefully informative message about what went wrong
 `,
 		},
@@ -259,6 +607,7 @@ Error: Failed to become ready: a hopefully informative message about what went w
 				},
 			},
 			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeTimeout),
 			ExpectOutput: `
 Create workload:
       1 + |---
@@ -348,7 +697,11 @@ To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
 Waiting for workload "my-workload" to become ready...
+  Ready=True
 Workload "my-workload" is ready
+
+Latency
+   ready:   0s
 `,
 		},
 		{
@@ -376,7 +729,7 @@ Workload "my-workload" is ready
 
 				tailer := &logs.FakeTailer{}
 				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
-				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, false).Return(nil).Once()
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, false).Return(nil).Once()
 				ctx = logs.StashTailer(ctx, tailer)
 
 				return ctx, nil
@@ -429,6 +782,9 @@ To get status: "tanzu apps workload get my-workload"
 Waiting for workload "my-workload" to become ready...
 ...tail output...
 Workload "my-workload" is ready
+
+Latency
+   ready:   0s
 `,
 		},
 		{
@@ -456,7 +812,7 @@ Workload "my-workload" is ready
 
 				tailer := &logs.FakeTailer{}
 				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
-				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, true).Return(nil).Once()
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, true).Return(nil).Once()
 				ctx = logs.StashTailer(ctx, tailer)
 
 				return ctx, nil
@@ -509,6 +865,9 @@ To get status: "tanzu apps workload get my-workload"
 Waiting for workload "my-workload" to become ready...
 ...tail output...
 Workload "my-workload" is ready
+
+Latency
+   ready:   0s
 `,
 		},
 		{
@@ -553,6 +912,131 @@ Error: workload "default/my-workload" already exists
 			},
 			ShouldError: true,
 		},
+		{
+			Name: "create namespace with error during create",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.NamespaceFlagName, "foo", flags.CreateNamespaceFlagName, flags.YesFlagName},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("create", "Workload"),
+			},
+			ExpectCreates: []client.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+					},
+				},
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectDeletes: []rtesting.DeleteRef{{
+				Kind: "Namespace",
+				Name: "foo",
+			}},
+			ExpectOutput: `
+Create namespace:
+      1 + |---
+      2 + |apiVersion: v1
+      3 + |kind: Namespace
+      4 + |metadata:
+      5 + |  name: foo
+      6 + |spec: {}
+
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: foo
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created namespace "foo"
+Deleted namespace "foo" created for the workload
+`,
+			ShouldError: true,
+		},
+		{
+			Name: "create namespace with error deleting the namespace",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.NamespaceFlagName, "foo", flags.CreateNamespaceFlagName, flags.YesFlagName},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("create", "Workload"),
+				clitesting.InduceFailure("delete", "Namespace"),
+			},
+			ExpectCreates: []client.Object{
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+					},
+				},
+				&cartov1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "foo",
+						Name:      workloadName,
+						Labels:    map[string]string{},
+					},
+					Spec: cartov1alpha1.WorkloadSpec{
+						Source: &cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: gitRepo,
+								Ref: cartov1alpha1.GitRef{
+									Branch: gitBranch,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectDeletes: []rtesting.DeleteRef{{
+				Kind: "Namespace",
+				Name: "foo",
+			}},
+			ExpectOutput: `
+Create namespace:
+      1 + |---
+      2 + |apiVersion: v1
+      3 + |kind: Namespace
+      4 + |metadata:
+      5 + |  name: foo
+      6 + |spec: {}
+
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  name: my-workload
+      6 + |  namespace: foo
+      7 + |spec:
+      8 + |  source:
+      9 + |    git:
+     10 + |      ref:
+     11 + |        branch: main
+     12 + |      url: https://example.com/repo.git
+
+Created namespace "foo"
+Warning: namespace "foo" was created but the workload was not, it is kept: inducing failure for delete Namespace
+`,
+			ShouldError: true,
+		},
 		{
 			Name: "watcher error",
 			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, gitBranch, flags.YesFlagName, flags.WaitFlagName},
@@ -980,6 +1464,12 @@ To get status: "tanzu apps workload get my-workload"
 			Args:        []string{workloadName, flags.MavenArtifactFlagName, "spring-petclinic", flags.MavenVersionFlagName, "1.2.3", flags.YesFlagName},
 			ShouldError: true,
 		},
+		{
+			Name:         "error builder with pre-built image",
+			Args:         []string{workloadName, flags.ImageFlagName, "ubuntu:bionic", flags.BuilderFlagName, "my-builder", flags.YesFlagName},
+			GivenObjects: givenNamespaceDefault,
+			ShouldError:  true,
+		},
 		{
 			Name: "create with multiple param-yaml using valid json and yaml",
 			Args: []string{flags.FilePathFlagName, "testdata/param-yaml.yaml",
@@ -17,6 +17,7 @@ limitations under the License.
 package commands
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"io"
@@ -27,6 +28,9 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
@@ -42,12 +46,17 @@ type WorkloadDeleteOptions struct {
 	Namespace string
 	Names     []string
 	All       bool
+	Selector  string
+	FromList  string
 
 	FilePath string
 
-	Wait        bool
-	WaitTimeout time.Duration
-	Yes         bool
+	AllowProtected bool
+
+	Wait            bool
+	WaitDeliverable bool
+	WaitTimeout     time.Duration
+	Yes             bool
 }
 
 var (
@@ -70,8 +79,30 @@ func (opts *WorkloadDeleteOptions) Validate(_ context.Context) validation.FieldE
 		errs = errs.Also(validation.ErrMultipleOneOf(flags.AllFlagName, flags.FilePathFlagName))
 	}
 
-	if opts.FilePath == "" && !opts.All && len(opts.Names) == 0 {
-		errs = errs.Also(validation.ErrMissingOneOf(flags.AllFlagName, cli.NamesArgumentName, flags.FilePathFlagName))
+	if opts.All && opts.Selector != "" {
+		errs = errs.Also(validation.ErrMultipleOneOf(flags.AllFlagName, flags.SelectorFlagName))
+	}
+
+	if opts.All && opts.FromList != "" {
+		errs = errs.Also(validation.ErrMultipleOneOf(flags.AllFlagName, flags.FromListFlagName))
+	}
+
+	if opts.FilePath == "-" && opts.FromList == "-" {
+		errs = errs.Also(validation.ErrMultipleOneOf(flags.FilePathFlagName, flags.FromListFlagName))
+	}
+
+	if opts.Selector != "" {
+		if _, err := labels.Parse(opts.Selector); err != nil {
+			errs = errs.Also(validation.ErrInvalidValue(opts.Selector, flags.SelectorFlagName))
+		}
+	}
+
+	if opts.WaitDeliverable && !opts.Wait {
+		errs = errs.Also(validation.ErrMissingField(flags.WaitFlagName))
+	}
+
+	if opts.FilePath == "" && !opts.All && len(opts.Names) == 0 && opts.Selector == "" && opts.FromList == "" {
+		errs = errs.Also(validation.ErrMissingOneOf(flags.AllFlagName, cli.NamesArgumentName, flags.FilePathFlagName, flags.SelectorFlagName, flags.FromListFlagName))
 	}
 
 	return errs
@@ -96,9 +127,44 @@ func (opts *WorkloadDeleteOptions) Exec(ctx context.Context, c *cli.Config) erro
 		}
 	}
 
+	if err := validateProtectedNamespace(c, opts.Namespace, opts.AllowProtected).ToAggregate(); err != nil {
+		return err
+	}
+
+	if opts.FromList != "" {
+		listNames, err := opts.loadNamesList(c.Stdin)
+		if err != nil {
+			return err
+		}
+		names = append(names, listNames...)
+	}
+
+	if opts.Selector != "" {
+		selector, err := labels.Parse(opts.Selector)
+		if err != nil {
+			return err
+		}
+		workloads := &cartov1alpha1.WorkloadList{}
+		if err := c.List(ctx, workloads, client.InNamespace(opts.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return err
+		}
+		if len(workloads.Items) == 0 {
+			c.Infof("No workloads found matching %q\n", opts.Selector)
+		}
+		workloads = workloads.DeepCopy()
+		printer.SortByNamespaceAndName(workloads.Items)
+		for _, workload := range workloads.Items {
+			names = append(names, workload.Name)
+		}
+	}
+	names = uniqueNames(names)
+
+	// prompts can not be answered when the workload or the names are read from stdin
+	fromStdin := opts.FilePath == "-" || opts.FromList == "-"
+
 	if opts.All {
 		if !opts.Yes {
-			if opts.FilePath == "-" {
+			if fromStdin {
 				c.Errorf("Skipping workload, cannot confirm intent. Run command with %s flag to confirm intent when providing input from stdin\n", flags.YesFlagName)
 				return nil
 			} else {
@@ -113,11 +179,31 @@ func (opts *WorkloadDeleteOptions) Exec(ctx context.Context, c *cli.Config) erro
 				}
 			}
 		}
-		err := c.DeleteAllOf(ctx, workload, client.InNamespace(opts.Namespace))
+		workloads := &cartov1alpha1.WorkloadList{}
+		if opts.Wait {
+			// the workloads to wait for are listed before they are deleted
+			if err := c.List(ctx, workloads, client.InNamespace(opts.Namespace)); err != nil {
+				return err
+			}
+		}
+		err := c.DeleteAllOf(ctx, workload, client.InNamespace(opts.Namespace), opts.propagationPolicy())
 		if err != nil {
 			return err
 		}
 		c.Successf("Deleted workloads in namespace %q\n", opts.Namespace)
+		if opts.Wait {
+			c.Infof("Waiting for workloads in namespace %q to be deleted...\n", opts.Namespace)
+			if err := opts.waitForDelete(ctx, c, workloads.DeepCopy().Items); err != nil {
+				if err == context.DeadlineExceeded {
+					c.Printf("%s timeout after %s waiting for workloads in namespace %q to be deleted\n", printer.Serrorf("Error:"), opts.WaitTimeout, opts.Namespace)
+					c.Infof("To view status run: tanzu apps workload list %s %s\n", flags.NamespaceFlagName, opts.Namespace)
+					return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeTimeout))
+				}
+				c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+				return cli.SilenceError(err)
+			}
+			c.Infof("Workloads in namespace %q were deleted\n", opts.Namespace)
+		}
 		return nil
 	}
 
@@ -130,7 +216,7 @@ func (opts *WorkloadDeleteOptions) Exec(ctx context.Context, c *cli.Config) erro
 			return err
 		}
 		if !opts.Yes {
-			if opts.FilePath == "-" {
+			if fromStdin {
 				c.Errorf("Skipping workload, cannot confirm intent. Run command with %s flag to confirm intent when providing input from stdin\n", flags.YesFlagName)
 				return nil
 			} else {
@@ -145,22 +231,17 @@ func (opts *WorkloadDeleteOptions) Exec(ctx context.Context, c *cli.Config) erro
 				}
 			}
 		}
-		if err := c.Delete(ctx, workload); err != nil {
+		if err := c.Delete(ctx, workload, opts.propagationPolicy()); err != nil {
 			return err
 		}
 		c.Successf("Deleted workload %q\n", name)
 		if opts.Wait {
 			c.Infof("Waiting for workload %q to be deleted...\n", name)
-			workers := []wait.Worker{
-				func(ctx context.Context) error {
-					return wait.UntilDelete(ctx, c.Client, workload)
-				},
-			}
-			if err := wait.Race(ctx, opts.WaitTimeout, workers); err != nil {
+			if err := opts.waitForDelete(ctx, c, []cartov1alpha1.Workload{*workload.DeepCopy()}); err != nil {
 				if err == context.DeadlineExceeded {
 					c.Printf("%s timeout after %s waiting for %q to be deleted\n", printer.Serrorf("Error:"), opts.WaitTimeout, name)
 					c.Infof("To view status run: tanzu apps workload get %s %s %s\n", name, flags.NamespaceFlagName, opts.Namespace)
-					return cli.SilenceError(err)
+					return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeTimeout))
 				}
 				c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
 				return cli.SilenceError(err)
@@ -172,6 +253,49 @@ func (opts *WorkloadDeleteOptions) Exec(ctx context.Context, c *cli.Config) erro
 	return nil
 }
 
+// propagationPolicy deletes the workloads in the foreground when waiting, the workloads are only
+// removed once the garbage collector deleted the resources stamped by their supply chain
+func (opts *WorkloadDeleteOptions) propagationPolicy() client.PropagationPolicy {
+	if opts.Wait {
+		return client.PropagationPolicy(metav1.DeletePropagationForeground)
+	}
+	return client.PropagationPolicy(metav1.DeletePropagationBackground)
+}
+
+// waitForDelete waits until the workloads, and their deliverables with --wait-deliverable, are
+// removed from the cluster. Errors reading them while they are deleted fail the wait.
+func (opts *WorkloadDeleteOptions) waitForDelete(ctx context.Context, c *cli.Config, workloads []cartov1alpha1.Workload) error {
+	objs := []client.Object{}
+	for i := range workloads {
+		objs = append(objs, &workloads[i])
+		if !opts.WaitDeliverable {
+			continue
+		}
+		if ref := getWorkloadResourceByKind(&workloads[i], cartov1alpha1.DeliverableKind); ref != nil {
+			namespace := ref.StampedRef.Namespace
+			if namespace == "" {
+				namespace = workloads[i].Namespace
+			}
+			objs = append(objs, &cartov1alpha1.Deliverable{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: ref.StampedRef.Name},
+			})
+		}
+	}
+
+	ctx = withPollBackoff(ctx, c)
+	workers := []wait.Worker{
+		func(ctx context.Context) error {
+			for _, obj := range objs {
+				if err := wait.UntilDelete(ctx, c.Client, obj); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	return wait.Race(ctx, opts.WaitTimeout, workers)
+}
+
 func (opts *WorkloadDeleteOptions) loadInputWorkload(input io.Reader, workload *cartov1alpha1.Workload) error {
 	var in io.Reader
 
@@ -190,22 +314,64 @@ func (opts *WorkloadDeleteOptions) loadInputWorkload(input io.Reader, workload *
 	return nil
 }
 
+// loadNamesList reads the names of the workloads to delete, one per line. Blank lines and lines
+// starting with # are ignored.
+func (opts *WorkloadDeleteOptions) loadNamesList(input io.Reader) ([]string, error) {
+	in := input
+	if opts.FromList != "-" {
+		f, err := os.Open(opts.FromList)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open file %q: %w", opts.FromList, err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	names := []string{}
+	scanner := bufio.NewScanner(in)
+	for line := 1; scanner.Scan(); line++ {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+		if errs := validation.K8sName(name, flags.FromListFlagName); len(errs) != 0 {
+			return nil, fmt.Errorf("invalid workload name %q on line %d of %q", name, line, opts.FromList)
+		}
+		names = append(names, name)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read file %q: %w", opts.FromList, err)
+	}
+	return names, nil
+}
+
+// uniqueNames drops the repeated names, keeping the order they were given in
+func uniqueNames(names []string) []string {
+	seen := sets.NewString()
+	unique := []string{}
+	for _, name := range names {
+		if !seen.Has(name) {
+			seen.Insert(name)
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
+
 func NewWorkloadDeleteCommand(ctx context.Context, c *cli.Config) *cobra.Command {
 	opts := &WorkloadDeleteOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "delete",
-		Short: "Delete workload(s)",
+		Use:     "delete",
+		Aliases: []string{"d", "del", "rm"},
+		Short:   "Delete workload(s)",
 		Long: strings.TrimSpace(`
 Delete one or more workloads by name or all workloads within a namespace.
 
 Deleting a workload prevents new builds while preserving built images in the
 registry.
 `),
-		Example: strings.Join([]string{
-			fmt.Sprintf("%s workload delete my-workload", c.Name),
-			fmt.Sprintf("%s workload delete %s", c.Name, flags.AllFlagName),
-		}, "\n"),
+		Example:           examplesFor(c, "workload delete"),
 		PreRunE:           cli.ValidateE(ctx, opts),
 		RunE:              cli.ExecE(ctx, c, opts),
 		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
@@ -216,8 +382,14 @@ registry.
 	)
 
 	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
 	cmd.Flags().BoolVar(&opts.All, cli.StripDash(flags.AllFlagName), false, "delete all workloads within the namespace")
+	cmd.Flags().StringVar(&opts.Selector, cli.StripDash(flags.SelectorFlagName), "", "label `selector` of the workloads to delete within the namespace")
+	cmd.Flags().StringVar(&opts.FromList, cli.StripDash(flags.FromListFlagName), "", "`file path` listing the names of the workloads to delete, one per line. Use value \"-\" to read from stdin")
+	cmd.MarkFlagFilename(cli.StripDash(flags.FromListFlagName))
+	cmd.Flags().BoolVar(&opts.AllowProtected, cli.StripDash(flags.AllowProtectedFlagName), false, "allow deleting workloads in a namespace protected by the plugin config")
 	cmd.Flags().BoolVar(&opts.Wait, cli.StripDash(flags.WaitFlagName), false, "waits for workload to be deleted")
+	cmd.Flags().BoolVar(&opts.WaitDeliverable, cli.StripDash(flags.WaitDeliverableFlagName), false, "also waits for the deliverable of the workload to be deleted, requires "+flags.WaitFlagName)
 	cmd.Flags().DurationVar(&opts.WaitTimeout, cli.StripDash(flags.WaitTimeoutFlagName), 1*time.Minute, "timeout for workload to be deleted when waiting")
 	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.WaitTimeoutFlagName), completion.SuggestDurationUnits(ctx, completion.CommonDurationUnits))
 	cmd.Flags().BoolVarP(&opts.Yes, cli.StripDash(flags.YesFlagName), "y", false, "accept all prompts")
@@ -25,6 +25,7 @@ import (
 
 	diemetav1 "dies.dev/apis/meta/v1"
 	rtesting "github.com/vmware-labs/reconciler-runtime/testing"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -47,7 +48,7 @@ func TestWorkloadDeleteOptionsValidate(t *testing.T) {
 			Validatable: &commands.WorkloadDeleteOptions{},
 			ExpectFieldErrors: validation.FieldErrors{}.Also(
 				validation.ErrMissingField(flags.NamespaceFlagName),
-				validation.ErrMissingOneOf(flags.AllFlagName, cli.NamesArgumentName, flags.FilePathFlagName),
+				validation.ErrMissingOneOf(flags.AllFlagName, cli.NamesArgumentName, flags.FilePathFlagName, flags.SelectorFlagName, flags.FromListFlagName),
 			),
 		},
 		{
@@ -100,6 +101,57 @@ func TestWorkloadDeleteOptionsValidate(t *testing.T) {
 			},
 			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.AllFlagName, flags.FilePathFlagName),
 		},
+		{
+			Name: "selector",
+			Validatable: &commands.WorkloadDeleteOptions{
+				Namespace: "default",
+				Selector:  "team=experiments",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid selector",
+			Validatable: &commands.WorkloadDeleteOptions{
+				Namespace: "default",
+				Selector:  "team==experiments=",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("team==experiments=", flags.SelectorFlagName),
+		},
+		{
+			Name: "invalid selector + all",
+			Validatable: &commands.WorkloadDeleteOptions{
+				Namespace: "default",
+				Selector:  "team=experiments",
+				All:       true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.AllFlagName, flags.SelectorFlagName),
+		},
+		{
+			Name: "from list",
+			Validatable: &commands.WorkloadDeleteOptions{
+				Namespace: "default",
+				FromList:  "testdata/workloads-list.txt",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid from list + all",
+			Validatable: &commands.WorkloadDeleteOptions{
+				Namespace: "default",
+				FromList:  "testdata/workloads-list.txt",
+				All:       true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.AllFlagName, flags.FromListFlagName),
+		},
+		{
+			Name: "invalid file and from list through stdin",
+			Validatable: &commands.WorkloadDeleteOptions{
+				Namespace: "default",
+				FilePath:  "-",
+				FromList:  "-",
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.FilePathFlagName, flags.FromListFlagName),
+		},
 		{
 			Name: "wait",
 			Validatable: &commands.WorkloadDeleteOptions{
@@ -119,6 +171,25 @@ func TestWorkloadDeleteOptionsValidate(t *testing.T) {
 			},
 			ShouldValidate: true,
 		},
+		{
+			Name: "wait deliverable",
+			Validatable: &commands.WorkloadDeleteOptions{
+				Namespace:       "default",
+				Names:           []string{"my-workload"},
+				Wait:            true,
+				WaitDeliverable: true,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "wait deliverable without wait",
+			Validatable: &commands.WorkloadDeleteOptions{
+				Namespace:       "default",
+				Names:           []string{"my-workload"},
+				WaitDeliverable: true,
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.WaitFlagName),
+		},
 	}
 
 	table.Run(t)
@@ -157,6 +228,25 @@ func TestWorkloadDeleteCommand(t *testing.T) {
 			d.Namespace(defaultNamespace)
 		})
 
+	parentWithDeliverable := parent.
+		StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+			d.Resources(
+				diecartov1alpha1.RealizedResourceBlank.
+					Name("deliverable").
+					StampedRef(&corev1.ObjectReference{
+						Kind:      cartov1alpha1.DeliverableKind,
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					}).
+					DieRelease(),
+			)
+		})
+	deliverable := diecartov1alpha1.DeliverableBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+		})
+
 	table := clitesting.CommandTestSuite{
 		{
 			Name:        "invalid args",
@@ -180,6 +270,52 @@ func TestWorkloadDeleteCommand(t *testing.T) {
 			}},
 			ExpectOutput: `
 Deleted workloads in namespace "default"
+`,
+		},
+		{
+			Name: "delete all workloads after wait",
+			Args: []string{flags.AllFlagName, flags.YesFlagName, flags.WaitFlagName},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ExpectDeleteCollections: []rtesting.DeleteCollectionRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Fields:    fields.Everything(),
+				Labels:    labels.NewSelector(),
+			}},
+			ExpectOutput: `
+Deleted workloads in namespace "default"
+Waiting for workloads in namespace "default" to be deleted...
+Workloads in namespace "default" were deleted
+`,
+		},
+		{
+			Name: "delete all workloads failed with wait timeout error",
+			Args: []string{flags.AllFlagName, flags.YesFlagName, flags.WaitFlagName},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				ctx, cancel := context.WithTimeout(ctx, 1*time.Nanosecond)
+				defer cancel()
+				return ctx, nil
+			},
+			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeTimeout),
+			ExpectDeleteCollections: []rtesting.DeleteCollectionRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Fields:    fields.Everything(),
+				Labels:    labels.NewSelector(),
+			}},
+			ExpectOutput: `
+Deleted workloads in namespace "default"
+Waiting for workloads in namespace "default" to be deleted...
+Error: timeout after 1m0s waiting for workloads in namespace "default" to be deleted
+To view status run: tanzu apps workload list --namespace default
 `,
 		},
 		{
@@ -255,6 +391,40 @@ Deleted workloads in namespace "default"
 			}},
 			ExpectOutput: `
 Deleted workload "test-workload"
+`,
+		},
+		{
+			Name: "delete workload in protected namespace",
+			Args: []string{workloadName, flags.YesFlagName},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(commands.ProtectedNamespacesConfigKey, []string{defaultNamespace})
+				return c
+			}(),
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ShouldError: true,
+		},
+		{
+			Name: "delete workload in protected namespace allowed",
+			Args: []string{workloadName, flags.AllowProtectedFlagName, flags.YesFlagName},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(commands.ProtectedNamespacesConfigKey, []string{defaultNamespace})
+				return c
+			}(),
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ExpectDeletes: []rtesting.DeleteRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+			}},
+			ExpectOutput: `
+Deleted workload "test-workload"
 `,
 		},
 		{
@@ -364,6 +534,46 @@ Workload "test-workload" does not exist
 Deleted workload "test-workload"
 Waiting for workload "test-workload" to be deleted...
 Workload "test-workload" was deleted
+`,
+		},
+		{
+			Name: "delete workload and deliverable after wait",
+			Args: []string{workloadName, flags.YesFlagName, flags.WaitFlagName, flags.WaitDeliverableFlagName},
+			GivenObjects: []client.Object{
+				parentWithDeliverable,
+			},
+			ExpectDeletes: []rtesting.DeleteRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+			}},
+			ExpectOutput: `
+Deleted workload "test-workload"
+Waiting for workload "test-workload" to be deleted...
+Workload "test-workload" was deleted
+`,
+		},
+		{
+			Name: "delete workload waiting for deliverable timeout",
+			Args: []string{workloadName, flags.YesFlagName, flags.WaitFlagName, flags.WaitDeliverableFlagName, flags.WaitTimeoutFlagName, "100ms"},
+			GivenObjects: []client.Object{
+				parentWithDeliverable,
+				deliverable,
+			},
+			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeTimeout),
+			ExpectDeletes: []rtesting.DeleteRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+			}},
+			ExpectOutput: `
+Deleted workload "test-workload"
+Waiting for workload "test-workload" to be deleted...
+Error: timeout after 100ms waiting for "test-workload" to be deleted
+To view status run: tanzu apps workload get test-workload --namespace default
 `,
 		},
 		{
@@ -396,6 +606,7 @@ Workload "test-workload" was deleted
 				return ctx, nil
 			},
 			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeTimeout),
 			ExpectOutput: `
 Deleted workload "test-workload"
 Waiting for workload "test-workload" to be deleted...
@@ -634,6 +845,112 @@ Deleted workload "test-workload"
 Deleted workload "spring-petclinic"
 `,
 		},
+		{
+			Name: "delete workloads from selector",
+			Args: []string{flags.SelectorFlagName, "team=experiments", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("team", "experiments")
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(workloadOtherName)
+						d.Namespace(defaultNamespace)
+						d.AddLabel("team", "payments")
+					}),
+			},
+			ExpectDeletes: []rtesting.DeleteRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+			}},
+			ExpectOutput: `
+Deleted workload "test-workload"
+`,
+		},
+		{
+			Name: "delete workloads from selector, none found",
+			Args: []string{flags.SelectorFlagName, "team=experiments", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ExpectOutput: `
+No workloads found matching "team=experiments"
+`,
+		},
+		{
+			Name: "delete workloads from list",
+			Args: []string{workloadName, flags.FromListFlagName, "testdata/workloads-list.txt", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent,
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(workloadOtherName)
+						d.Namespace(defaultNamespace)
+					}),
+			},
+			ExpectDeletes: []rtesting.DeleteRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadName,
+			}, {
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadOtherName,
+			}},
+			ExpectOutput: `
+Deleted workload "test-workload"
+Deleted workload "test-other-workload"
+`,
+		},
+		{
+			Name:  "delete workloads from list through stdin",
+			Args:  []string{flags.FromListFlagName, "-", flags.YesFlagName},
+			Stdin: []byte("test-other-workload\n"),
+			GivenObjects: []client.Object{
+				parent,
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(workloadOtherName)
+						d.Namespace(defaultNamespace)
+					}),
+			},
+			ExpectDeletes: []rtesting.DeleteRef{{
+				Group:     "carto.run",
+				Kind:      "Workload",
+				Namespace: defaultNamespace,
+				Name:      workloadOtherName,
+			}},
+			ExpectOutput: `
+Deleted workload "test-other-workload"
+`,
+		},
+		{
+			Name:  "delete workloads from list through stdin - missing --yes flag",
+			Args:  []string{flags.FromListFlagName, "-"},
+			Stdin: []byte("test-workload\n"),
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ExpectOutput: `
+Skipping workload, cannot confirm intent. Run command with --yes flag to confirm intent when providing input from stdin
+`,
+		},
+		{
+			Name:        "delete workloads from list with invalid name",
+			Args:        []string{flags.FromListFlagName, "-", flags.YesFlagName},
+			Stdin:       []byte("test-workload\nNot A Name\n"),
+			ShouldError: true,
+		},
+		{
+			Name:        "delete workloads from missing list",
+			Args:        []string{flags.FromListFlagName, "testdata/missing.txt", flags.YesFlagName},
+			ShouldError: true,
+		},
 	}
 
 	table.Run(t, scheme, commands.NewWorkloadDeleteCommand)
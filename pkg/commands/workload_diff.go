@@ -0,0 +1,155 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+type WorkloadDiffOptions struct {
+	Namespace string
+	Name      string
+
+	FilePath    string
+	FileSHA256  string
+	ValuesFiles []string
+	Set         []string
+
+	FromImageScan        bool
+	ReplaceServiceClaims bool
+}
+
+var (
+	_ validation.Validatable = (*WorkloadDiffOptions)(nil)
+	_ cli.Executable         = (*WorkloadDiffOptions)(nil)
+)
+
+func (opts *WorkloadDiffOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Name != "" {
+		errs = errs.Also(validation.K8sName(opts.Name, cli.NameArgumentName))
+	}
+
+	if opts.FilePath == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.FilePathFlagName))
+	}
+	if opts.FileSHA256 != "" && !sha256Regex.MatchString(opts.FileSHA256) {
+		errs = errs.Also(validation.ErrInvalidValue(opts.FileSHA256, flags.FileSHA256FlagName))
+	}
+	errs = errs.Also(validation.KeyValues(opts.Set, flags.SetFlagName))
+
+	return errs
+}
+
+func (opts *WorkloadDiffOptions) Exec(ctx context.Context, c *cli.Config) error {
+	// the workload is merged by the same path as by workload apply, without changing it on the cluster
+	apply := &WorkloadApplyOptions{
+		WorkloadOptions:      WorkloadOptions{FilePath: opts.FilePath, FileSHA256: opts.FileSHA256, ValuesFiles: opts.ValuesFiles, Set: opts.Set},
+		FromImageScan:        opts.FromImageScan,
+		ReplaceServiceClaims: opts.ReplaceServiceClaims,
+	}
+	fileWorkload := &cartov1alpha1.Workload{}
+	if err := apply.LoadInputWorkload(ctx, c.Stdin, fileWorkload); err != nil {
+		return err
+	}
+	if opts.Name == "" {
+		opts.Name = fileWorkload.Name
+	}
+	if fileWorkload.Namespace != "" && !cli.CommandFromContext(ctx).Flags().Changed(cli.StripDash(flags.NamespaceFlagName)) {
+		opts.Namespace = fileWorkload.Namespace
+	}
+
+	errs := validation.FieldErrors{}
+	if opts.Name == "" {
+		errs = errs.Also(validation.ErrMissingField(cli.NameArgumentName))
+	}
+	if opts.Namespace == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+	}
+	if err := errs.ToAggregate(); err != nil {
+		return err
+	}
+
+	apply.Namespace, apply.Name = opts.Namespace, opts.Name
+	_, workload, currentWorkload, _, err := apply.mergeWorkload(ctx, c, fileWorkload)
+	if err != nil {
+		return err
+	}
+
+	difference, noChange, err := printer.ResourceDiff(currentWorkload, workload, c.Scheme)
+	if err != nil {
+		return err
+	}
+	if currentWorkload == nil {
+		c.Printf("Create workload:\n")
+	} else if noChange {
+		c.Infof("Workload is unchanged\n")
+		return nil
+	} else {
+		c.Printf("Update workload:\n")
+	}
+	c.Printf("%s\n", difference)
+	return nil
+}
+
+func NewWorkloadDiffCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	opts := &WorkloadDiffOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the changes applying a file would make to a workload",
+		Long: strings.TrimSpace(`
+Show the changes that "workload apply" would make to a workload on the cluster
+with the content of a file, without changing the workload. The file can be read
+from stdin, for example to compare the workload against a git revision of its
+file.
+`),
+		Example:           examplesFor(c, "workload diff"),
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		cli.OptionalNameArg(&opts.Name),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVarP(&opts.FilePath, cli.StripDash(flags.FilePathFlagName), "f", "", "`file path` or https URL containing the description of a single workload to compare the workload with. Use value \"-\" to read from stdin")
+	cmd.MarkFlagFilename(cli.StripDash(flags.FilePathFlagName), ".yaml", ".yml")
+	cmd.Flags().StringVar(&opts.FileSHA256, cli.StripDash(flags.FileSHA256FlagName), "", "expected sha256 `digest` of the "+flags.FilePathFlagName+" content, the command fails when it does not match")
+	cmd.Flags().StringArrayVar(&opts.ValuesFiles, cli.StripDash(flags.ValuesFlagName), []string{}, "`file path` of yaml values rendered into the Go template placeholders of the "+flags.FilePathFlagName+" content as .Values, later files take precedence (flag can be used multiple times)")
+	cmd.MarkFlagFilename(cli.StripDash(flags.ValuesFlagName), ".yaml", ".yml")
+	cmd.Flags().StringArrayVar(&opts.Set, cli.StripDash(flags.SetFlagName), []string{}, "value rendered into the Go template placeholders of the "+flags.FilePathFlagName+" content represented as a `\"key=value\" pair`, a dotted key sets a nested value, taking precedence over "+flags.ValuesFlagName+" (flag can be used multiple times)")
+	cmd.Flags().BoolVar(&opts.ReplaceServiceClaims, cli.StripDash(flags.ReplaceClaimsFlagName), false, fmt.Sprintf("compare as if the service claims of the workload were replaced with the ones in %s", flags.FilePathFlagName))
+	cmd.Flags().BoolVar(&opts.FromImageScan, cli.StripDash(flags.FromImageScanFlagName), false, fmt.Sprintf("compare as if the part-of label, the ports param and the source annotations the workload does not set were prefilled from the config of %s", flags.ImageFlagName))
+
+	return cmd
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	diecorev1 "dies.dev/apis/core/v1"
+	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestWorkloadDiffOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name:              "invalid empty",
+			Validatable:       &commands.WorkloadDiffOptions{},
+			ExpectFieldErrors: validation.ErrMissingField(flags.FilePathFlagName),
+		},
+		{
+			Name: "valid",
+			Validatable: &commands.WorkloadDiffOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				FilePath:  "workload.yaml",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid name",
+			Validatable: &commands.WorkloadDiffOptions{
+				Namespace: "default",
+				Name:      "my-",
+				FilePath:  "workload.yaml",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("my-", cli.NameArgumentName),
+		},
+		{
+			Name: "invalid file sha256",
+			Validatable: &commands.WorkloadDiffOptions{
+				Namespace:  "default",
+				FilePath:   "workload.yaml",
+				FileSHA256: "abc",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("abc", flags.FileSHA256FlagName),
+		},
+		{
+			Name: "invalid set",
+			Validatable: &commands.WorkloadDiffOptions{
+				Namespace: "default",
+				FilePath:  "workload.yaml",
+				Set:       []string{"environment"},
+			},
+			ExpectFieldErrors: validation.ErrInvalidArrayValue("environment", flags.SetFlagName, 0),
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestWorkloadDiffCommand(t *testing.T) {
+	workloadName := "spring-petclinic"
+	defaultNamespace := "default"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	namespace := diecorev1.NamespaceBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(defaultNamespace)
+		})
+
+	parent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+			d.AddLabel(apis.AppPartOfLabelName, workloadName)
+			d.AddLabel(apis.WorkloadTypeLabelName, "web")
+		}).
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Env(corev1.EnvVar{Name: "SPRING_PROFILES_ACTIVE", Value: "mysql"})
+			d.Resources(&corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			})
+			d.Source(&cartov1alpha1.Source{
+				Git: &cartov1alpha1.GitSource{
+					URL: "https://github.com/spring-projects/spring-petclinic.git",
+					Ref: cartov1alpha1.GitRef{Branch: "main"},
+				},
+			})
+		})
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "invalid args",
+			Args:        []string{},
+			ShouldError: true,
+		},
+		{
+			Name:         "unchanged",
+			Args:         []string{flags.FilePathFlagName, "testdata/workload.yaml"},
+			GivenObjects: []client.Object{parent},
+			ExpectOutput: `
+Workload is unchanged
+`,
+		},
+		{
+			Name: "update",
+			Args: []string{flags.FilePathFlagName, "testdata/workload.yaml"},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Env(corev1.EnvVar{Name: "SPRING_PROFILES_ACTIVE", Value: "postgres"})
+					}),
+			},
+			ExpectOutput: `
+Update workload:
+...
+  9,  9   |  namespace: default
+ 10, 10   |spec:
+ 11, 11   |  env:
+ 12, 12   |  - name: SPRING_PROFILES_ACTIVE
+ 13     - |    value: postgres
+     13 + |    value: mysql
+ 14, 14   |  resources:
+ 15, 15   |    limits:
+ 16, 16   |      cpu: 500m
+ 17, 17   |      memory: 1Gi
+...
+
+`,
+		},
+		{
+			Name:         "update from a template",
+			Args:         []string{workloadName, flags.FilePathFlagName, "testdata/workload-template.yaml", flags.ValuesFlagName, "testdata/values-postgres.yaml", flags.SetFlagName, "environment=prod"},
+			GivenObjects: []client.Object{parent},
+			ExpectOutput: `
+Update workload:
+...
+  9,  9   |  namespace: default
+ 10, 10   |spec:
+ 11, 11   |  env:
+ 12, 12   |  - name: SPRING_PROFILES_ACTIVE
+ 13     - |    value: mysql
+     13 + |    value: postgres
+ 14, 14   |  resources:
+ 15, 15   |    limits:
+ 16, 16   |      cpu: 500m
+ 17, 17   |      memory: 1Gi
+...
+ 20, 20   |      memory: 1Gi
+ 21, 21   |  source:
+ 22, 22   |    git:
+ 23, 23   |      ref:
+ 24     - |        branch: main
+     24 + |        branch: release
+ 25, 25   |      url: https://github.com/spring-projects/spring-petclinic.git
+
+`,
+		},
+		{
+			Name:         "create",
+			Args:         []string{"my-workload", flags.FilePathFlagName, "testdata/workload.yaml"},
+			GivenObjects: []client.Object{namespace},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    app.kubernetes.io/part-of: spring-petclinic
+      7 + |    apps.tanzu.vmware.com/workload-type: web
+      8 + |  name: my-workload
+      9 + |  namespace: default
+     10 + |spec:
+     11 + |  env:
+     12 + |  - name: SPRING_PROFILES_ACTIVE
+     13 + |    value: mysql
+     14 + |  resources:
+     15 + |    limits:
+     16 + |      cpu: 500m
+     17 + |      memory: 1Gi
+     18 + |    requests:
+     19 + |      cpu: 100m
+     20 + |      memory: 1Gi
+     21 + |  source:
+     22 + |    git:
+     23 + |      ref:
+     24 + |        branch: main
+     25 + |      url: https://github.com/spring-projects/spring-petclinic.git
+
+`,
+		},
+		{
+			Name: "create with namespace defaults",
+			Args: []string{"my-workload", flags.FilePathFlagName, "testdata/workload.yaml"},
+			GivenObjects: []client.Object{
+				namespace,
+				diecorev1.ConfigMapBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(commands.NamespaceDefaultsConfigMapName)
+						d.Namespace(defaultNamespace)
+					}).
+					AddData("labels", "cost-center=1234\n"),
+			},
+			ExpectOutput: `
+Create workload:
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    app.kubernetes.io/part-of: spring-petclinic
+      7 + |    apps.tanzu.vmware.com/workload-type: web
+      8 + |    cost-center: "1234"
+      9 + |  name: my-workload
+     10 + |  namespace: default
+     11 + |spec:
+     12 + |  env:
+     13 + |  - name: SPRING_PROFILES_ACTIVE
+     14 + |    value: mysql
+     15 + |  resources:
+     16 + |    limits:
+     17 + |      cpu: 500m
+     18 + |      memory: 1Gi
+     19 + |    requests:
+     20 + |      cpu: 100m
+     21 + |      memory: 1Gi
+     22 + |  source:
+     23 + |    git:
+     24 + |      ref:
+     25 + |        branch: main
+     26 + |      url: https://github.com/spring-projects/spring-petclinic.git
+
+`,
+		},
+		{
+			Name:        "create in missing namespace",
+			Args:        []string{"my-workload", flags.FilePathFlagName, "testdata/workload.yaml"},
+			ShouldError: true,
+			ExpectOutput: `
+Error: namespace "default" not found, it may not exist or user does not have permissions to read it.
+`,
+		},
+		{
+			Name: "stdin",
+			Args: []string{flags.FilePathFlagName, "-", flags.NamespaceFlagName, "dev"},
+			Stdin: []byte(`
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: spring-petclinic
+  namespace: default
+spec:
+  image: registry.example/spring-petclinic:latest
+`),
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Namespace("dev")
+					}),
+			},
+			ExpectOutput: `
+Update workload:
+...
+ 10, 10   |spec:
+ 11, 11   |  env:
+ 12, 12   |  - name: SPRING_PROFILES_ACTIVE
+ 13, 13   |    value: mysql
+     14 + |  image: registry.example/spring-petclinic:latest
+ 14, 15   |  resources:
+ 15, 16   |    limits:
+ 16, 17   |      cpu: 500m
+ 17, 18   |      memory: 1Gi
+ 18, 19   |    requests:
+ 19, 20   |      cpu: 100m
+ 20, 21   |      memory: 1Gi
+ 21     - |  source:
+ 22     - |    git:
+ 23     - |      ref:
+ 24     - |        branch: main
+ 25     - |      url: https://github.com/spring-projects/spring-petclinic.git
+
+`,
+		},
+		{
+			Name:        "missing file",
+			Args:        []string{flags.FilePathFlagName, "testdata/missing.yaml"},
+			ShouldError: true,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadDiffCommand(ctx, c)
+	})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/shlex"
+	"golang.org/x/crypto/ssh/terminal"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+type terminalStashKey struct{}
+
+// StashTerminal sets whether the input and output of the command are considered a terminal
+func StashTerminal(ctx context.Context, isTerminal bool) context.Context {
+	return context.WithValue(ctx, terminalStashKey{}, isTerminal)
+}
+
+func isTerminal(ctx context.Context, stream interface{}) bool {
+	if stashed, ok := ctx.Value(terminalStashKey{}).(bool); ok {
+		return stashed
+	}
+	f, ok := stream.(*os.File)
+	return ok && terminal.IsTerminal(int(f.Fd()))
+}
+
+// diffTool returns the external diff command set with --diff-tool or with the defaults of the plugin config
+func (opts *WorkloadOptions) diffTool(c *cli.Config) string {
+	if opts.DiffTool != "" {
+		return opts.DiffTool
+	}
+	if tool, ok := c.FlagDefault(flags.DiffToolFlagName); ok {
+		return fmt.Sprint(tool)
+	}
+	return ""
+}
+
+// printDiff prints the changes to the workload with the external diff tool when one is set and the output is a
+// terminal, otherwise, or when the diff tool fails, it prints the built-in diff
+func (opts *WorkloadOptions) printDiff(ctx context.Context, c *cli.Config, currentWorkload, workload *cartov1alpha1.Workload, difference string) {
+	if tool := opts.diffTool(c); tool != "" && isTerminal(ctx, c.Stdout) {
+		err := runDiffTool(ctx, c, tool, currentWorkload, workload)
+		if err == nil {
+			c.Printf("\n")
+			return
+		}
+		c.Infof("WARNING: unable to run diff tool %q, showing the built-in diff: %s\n", tool, err)
+	}
+	c.Printf("%s\n", difference)
+}
+
+// runDiffTool writes the current and the new workload to temporary files and runs the diff tool with both files
+// as its last two arguments. The diff tool is split into its arguments with the quoting rules of a shell, but
+// is not run by a shell. Diff tools exit with 1 when the files are different, which is not a failure.
+func runDiffTool(ctx context.Context, c *cli.Config, tool string, currentWorkload, workload *cartov1alpha1.Workload) error {
+	dir, err := os.MkdirTemp("", "workload-diff-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	current := filepath.Join(dir, "current.yaml")
+	if err := writeWorkloadYaml(c, current, currentWorkload); err != nil {
+		return err
+	}
+	updated := filepath.Join(dir, "new.yaml")
+	if err := writeWorkloadYaml(c, updated, workload); err != nil {
+		return err
+	}
+
+	// the command is run without a shell, which is not available on every platform
+	args, err := shlex.Split(tool)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("empty command")
+	}
+	cmd := c.Exec(ctx, args[0], append(args[1:], current, updated)...)
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func writeWorkloadYaml(c *cli.Config, path string, workload *cartov1alpha1.Workload) error {
+	var export string
+	if workload != nil {
+		var err error
+		if export, err = printer.ExportResource(workload, printer.OutputFormat(printer.OutputFormatYaml), c.Scheme); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, []byte(export), 0600)
+}
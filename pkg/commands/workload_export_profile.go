@@ -0,0 +1,63 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+const (
+	// ExportProfileBackup keeps the name, namespace, labels, annotations and spec of the workload, the
+	// export without a profile
+	ExportProfileBackup = "backup"
+	// ExportProfileGitOps keeps what ExportProfileBackup keeps, without the annotations recording who or
+	// which tool last applied the workload, which change with every apply and would make every commit
+	// of a GitOps repository conflict
+	ExportProfileGitOps = "gitops"
+	// ExportProfileDebug keeps the whole metadata but the managed fields, and the status, to share the
+	// state of a workload in a bug report
+	ExportProfileDebug = "debug"
+)
+
+// ExportProfiles are the profiles of --export-profile
+var ExportProfiles = []string{ExportProfileBackup, ExportProfileDebug, ExportProfileGitOps}
+
+// gitOpsExcludedAnnotations are the annotations left out of the export of the gitops profile
+var gitOpsExcludedAnnotations = []string{
+	corev1.LastAppliedConfigAnnotation,
+	apis.LastModifiedByAnnotationName,
+}
+
+// ExportWorkload exports the workload with the metadata and status the profile retains
+func ExportWorkload(workload *cartov1alpha1.Workload, profile string, format printer.OutputFormat, scheme *runtime.Scheme) (string, error) {
+	workload = workload.DeepCopy()
+	switch profile {
+	case ExportProfileDebug:
+		workload.ManagedFields = nil
+		return printer.OutputResource(workload, format, scheme)
+	case ExportProfileGitOps:
+		for _, annotation := range gitOpsExcludedAnnotations {
+			delete(workload.Annotations, annotation)
+		}
+	}
+	return printer.ExportResource(workload, format, scheme)
+}
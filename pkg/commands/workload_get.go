@@ -18,20 +18,29 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
 	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
 	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
 	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/logs"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	appsclient "github.com/vmware-tanzu/apps-cli-plugin/pkg/client"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
@@ -39,13 +48,36 @@ import (
 )
 
 type WorkloadGetOptions struct {
-	Namespace string
-	Name      string
-
-	Export bool
-	Output string
+	Namespace     string
+	AllNamespaces bool
+	Name          string
+	App           string
+
+	Export            bool
+	ExportProfile     string
+	ExportDeliverable bool
+	ToContext         string
+	Output            string
+	AllMessages       bool
+	Timestamps        bool
+	Previous          bool
+	WithLogs          int64
+	IncludeSummary    bool
 }
 
+const (
+	// defaultWithLogsLines is the number of log lines of --with-logs without a value
+	defaultWithLogsLines = 20
+	// maxWithLogsLines bounds the log lines of --with-logs, longer logs are for workload tail
+	maxWithLogsLines = 200
+	// workloadGetFetchTimeout bounds the concurrent queries of the sections of workload get
+	workloadGetFetchTimeout = 30 * time.Second
+)
+
+// sourceProviderKinds are the kinds of the resources stamped by supply chains to fetch the source of
+// a workload, from a git repository or from a source image
+var sourceProviderKinds = []string{"GitRepository", "ImageRepository"}
+
 var (
 	_ validation.Validatable = (*WorkloadGetOptions)(nil)
 	_ cli.Executable         = (*WorkloadGetOptions)(nil)
@@ -54,22 +86,115 @@ var (
 func (opts *WorkloadGetOptions) Validate(ctx context.Context) validation.FieldErrors {
 	errs := validation.FieldErrors{}
 
-	if opts.Namespace == "" {
+	if opts.Namespace == "" && !opts.AllNamespaces {
 		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
 	}
 
-	if opts.Name == "" {
+	if opts.Name == "" && opts.App == "" {
 		errs = errs.Also(validation.ErrMissingField(cli.NameArgumentName))
 	}
 
+	if opts.App != "" {
+		errs = errs.Also(validation.K8sName(opts.App, flags.AppFlagName))
+		if opts.Name != "" {
+			errs = errs.Also(validation.ErrMultipleOneOf(cli.NameArgumentName, flags.AppFlagName))
+		}
+		if opts.Export {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.AppFlagName, flags.ExportFlagName))
+		}
+		if opts.ExportDeliverable {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.AppFlagName, flags.ExportDeliverableFlagName))
+		}
+	} else if opts.AllNamespaces {
+		// a workload name is only unique within a namespace
+		errs = errs.Also(validation.ErrMissingField(flags.AppFlagName))
+	}
+
 	if opts.Output != "" {
-		errs = errs.Also(validation.Enum(opts.Output, flags.OutputFlagName, []string{printer.OutputFormatJson, printer.OutputFormatYaml, printer.OutputFormatYml}))
+		errs = errs.Also(validation.Enum(opts.Output, flags.OutputFlagName, []string{printer.OutputFormatJson, printer.OutputFormatYaml, printer.OutputFormatYml, OutputFormatWide}))
+	}
+	// the wide output adds details to the default view of a single workload, it is not an export format
+	if opts.Output == OutputFormatWide && (opts.App != "" || opts.Export || opts.ExportDeliverable) {
+		errs = errs.Also(validation.ErrInvalidValue(opts.Output, flags.OutputFlagName))
+	}
+
+	if opts.Export && opts.ExportDeliverable {
+		errs = errs.Also(validation.ErrMultipleOneOf(flags.ExportFlagName, flags.ExportDeliverableFlagName))
+	}
+
+	if opts.ExportProfile != "" {
+		errs = errs.Also(validation.Enum(opts.ExportProfile, flags.ExportProfileFlagName, ExportProfiles))
+		if !opts.Export {
+			errs = errs.Also(validation.ErrMissingField(flags.ExportFlagName))
+		}
+	}
+
+	if opts.ToContext != "" && !opts.ExportDeliverable {
+		errs = errs.Also(validation.ErrMissingField(flags.ExportDeliverableFlagName))
+	}
+
+	if opts.Previous {
+		if opts.App != "" {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.AppFlagName, flags.PreviousFlagName))
+		}
+		if opts.Export {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.ExportFlagName, flags.PreviousFlagName))
+		}
+		if opts.ExportDeliverable {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.ExportDeliverableFlagName, flags.PreviousFlagName))
+		}
+		if opts.Output != "" {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.OutputFlagName, flags.PreviousFlagName))
+		}
+	}
+
+	if opts.IncludeSummary {
+		// the summary is added to the workload as printed with the json and yaml output, keeping the raw
+		// output unchanged otherwise
+		if opts.Output == "" || opts.Output == OutputFormatWide {
+			errs = errs.Also(validation.ErrMissingField(flags.OutputFlagName))
+		}
+		if opts.App != "" {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.AppFlagName, flags.IncludeSummaryFlagName))
+		}
+		if opts.Export {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.ExportFlagName, flags.IncludeSummaryFlagName))
+		}
+		if opts.ExportDeliverable {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.ExportDeliverableFlagName, flags.IncludeSummaryFlagName))
+		}
+		if opts.Previous {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.PreviousFlagName, flags.IncludeSummaryFlagName))
+		}
+	}
+
+	if opts.WithLogs != 0 {
+		if opts.WithLogs < 0 || opts.WithLogs > maxWithLogsLines {
+			errs = errs.Also(validation.ErrInvalidValue(opts.WithLogs, flags.WithLogsFlagName))
+		}
+		if opts.App != "" {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.AppFlagName, flags.WithLogsFlagName))
+		}
+		// logs are only shown in the default and wide views
+		if opts.Output != "" && opts.Output != OutputFormatWide {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.OutputFlagName, flags.WithLogsFlagName))
+		}
+		if opts.Export {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.ExportFlagName, flags.WithLogsFlagName))
+		}
+		if opts.ExportDeliverable {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.ExportDeliverableFlagName, flags.WithLogsFlagName))
+		}
 	}
 
 	return errs
 }
 
 func (opts *WorkloadGetOptions) Exec(ctx context.Context, c *cli.Config) error {
+	if opts.App != "" {
+		return opts.execApp(ctx, c)
+	}
+
 	workload := &cartov1alpha1.Workload{}
 	err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, workload)
 	if err != nil {
@@ -86,6 +211,14 @@ func (opts *WorkloadGetOptions) Exec(ctx context.Context, c *cli.Config) error {
 		return err
 	}
 
+	if opts.ExportDeliverable {
+		return opts.exportDeliverable(ctx, c, workload)
+	}
+
+	if opts.Previous {
+		return opts.printPrevious(c, workload)
+	}
+
 	if opts.Export {
 		var format printer.OutputFormat
 		if opts.Output == "" {
@@ -94,7 +227,7 @@ func (opts *WorkloadGetOptions) Exec(ctx context.Context, c *cli.Config) error {
 			format = printer.OutputFormat(opts.Output)
 		}
 
-		export, err := printer.ExportResource(workload, format, c.Scheme)
+		export, err := ExportWorkload(workload, opts.ExportProfile, format, c.Scheme)
 		if err != nil {
 			c.Eprintf("%s %s\n", printer.Serrorf("Failed to export workload:"), err)
 			return cli.SilenceError(err)
@@ -103,8 +236,18 @@ func (opts *WorkloadGetOptions) Exec(ctx context.Context, c *cli.Config) error {
 		return nil
 	}
 
-	if opts.Output != "" {
-		export, err := printer.OutputResource(workload, printer.OutputFormat(opts.Output), c.Scheme)
+	if opts.Output != "" && opts.Output != OutputFormatWide {
+		var export string
+		if opts.IncludeSummary {
+			summary, summaryErr := appsclient.SummarizeWorkload(ctx, c, workload)
+			if summaryErr != nil {
+				c.Eprintf("%s %s\n", printer.Serrorf("Failed to summarize workload:"), summaryErr)
+				return cli.SilenceError(summaryErr)
+			}
+			export, err = printer.OutputResourceWithFields(workload, map[string]interface{}{WorkloadSummaryField: summary}, printer.OutputFormat(opts.Output), c.Scheme)
+		} else {
+			export, err = printer.OutputResource(workload, printer.OutputFormat(opts.Output), c.Scheme)
+		}
 		if err != nil {
 			c.Eprintf("%s %s\n", printer.Serrorf("Failed to output workload:"), err)
 			return cli.SilenceError(err)
@@ -114,33 +257,24 @@ func (opts *WorkloadGetOptions) Exec(ctx context.Context, c *cli.Config) error {
 		return nil
 	}
 
+	sections := fetchWorkloadGetSections(ctx, c, workload)
+
+	theme, err := printer.ThemeFromConfig(c.Viper)
+	if err != nil {
+		c.Eprintf("%s %s, using the %s theme\n", printer.Swarnf("Warning:"), err, printer.DefaultThemeName)
+	}
+
 	//print workload details
-	c.EmojiBoldf(cli.Antenna, "Overview\n")
+	c.Boldf("%s\n", theme.Overview)
 	if err := printer.WorkloadOverviewPrinter(c.Stdout, workload); err != nil {
 		return err
 	}
 	c.Printf("\n")
 	// Print workload source
 	if workload.Spec.Image != "" || workload.Spec.Source != nil {
-		c.EmojiBoldf(cli.FloppyDisk, "Source\n")
-
-		if workload.Spec.Image != "" {
-			if err := printer.WorkloadSourceImagePrinter(c.Stdout, workload); err != nil {
-				return err
-			}
-		}
-
-		if workload.Spec.Source != nil {
-			if workload.Spec.Source.Image != "" {
-				if err := printer.WorkloadLocalSourceImagePrinter(c.Stdout, workload); err != nil {
-					return err
-				}
-			}
-			if workload.Spec.Source.Git != nil {
-				if err := printer.WorkloadSourceGitPrinter(c.Stdout, workload); err != nil {
-					return err
-				}
-			}
+		c.Boldf("%s\n", theme.Source)
+		if err := printer.WorkloadSourcePrinter(c.Stdout, workload, sections.sourceProvider); err != nil {
+			return err
 		}
 		c.Printf("\n")
 	}
@@ -149,7 +283,7 @@ func (opts *WorkloadGetOptions) Exec(ctx context.Context, c *cli.Config) error {
 	if workload.Status.SupplyChainRef == (cartov1alpha1.ObjectReference{}) && len(workload.Status.Conditions) == 0 {
 		c.Infof("Supply Chain reference not found.\n")
 	} else {
-		c.EmojiBoldf(cli.Package, "Supply Chain\n")
+		c.Boldf("%s\n", theme.SupplyChain)
 
 		if err := printer.WorkloadSupplyChainInfoPrinter(c.Stdout, workload); err != nil {
 			return err
@@ -160,35 +294,47 @@ func (opts *WorkloadGetOptions) Exec(ctx context.Context, c *cli.Config) error {
 	c.Printf("\n")
 	if len(workload.Status.Resources) == 0 {
 		c.Infof(printer.AddPaddingStart("Supply Chain resources not found.\n"))
+	} else if opts.Output == OutputFormatWide {
+		if err := printer.WorkloadResourcesWidePrinter(c.Stdout, workload, opts.Timestamps); err != nil {
+			return err
+		}
+		c.Printf("\n")
+		if err := printer.WorkloadResourceOutputsPrinter(c.Stdout, workload); err != nil {
+			return err
+		}
 	} else {
-		if err := printer.WorkloadResourcesPrinter(c.Stdout, workload); err != nil {
+		if err := printer.WorkloadResourcesPrinter(c.Stdout, workload, opts.Timestamps); err != nil {
+			return err
+		}
+	}
+
+	// Print where the config writer delivered the configuration
+	if printer.HasWorkloadGitOps(workload) {
+		c.Printf("\n")
+		c.Boldf("%s\n", theme.GitOps)
+		if err := printer.WorkloadGitOpsPrinter(c.Stdout, workload); err != nil {
 			return err
 		}
 	}
 
 	// Deliverable
 	c.Printf("\n")
-	c.EmojiBoldf(cli.Delivery, "Delivery\n")
+	c.Boldf("%s\n", theme.Delivery)
 	// Print workload deliverable resources
-	wldDeliverable := getWorkloadResourceByKind(workload, cartov1alpha1.DeliverableKind)
 	var deliverableStatusReadyCond *metav1.Condition
 	notFoundMsg := printer.AddPaddingStart("Delivery resources not found.\n")
 	deliverable := &cartov1alpha1.Deliverable{}
-	if wldDeliverable != nil {
-		if err := c.Get(ctx, client.ObjectKey{Namespace: wldDeliverable.StampedRef.Namespace, Name: wldDeliverable.StampedRef.Name}, deliverable); err != nil {
-			c.Printf("\n")
+	if sections.deliverable != nil {
+		deliverable = sections.deliverable
+		deliverableStatusReadyCond = printer.FindCondition(deliverable.Status.Conditions, cartov1alpha1.ConditionReady)
+		if err := printer.DeliveryInfoPrinter(c.Stdout, deliverable); err != nil {
+			return err
+		}
+		c.Printf("\n")
+		if len(deliverable.Status.Resources) == 0 {
 			c.Infof(notFoundMsg)
-		} else if deliverable != nil {
-			deliverableStatusReadyCond = printer.FindCondition(deliverable.Status.Conditions, cartov1alpha1.ConditionReady)
-			if err := printer.DeliveryInfoPrinter(c.Stdout, deliverable); err != nil {
-				return err
-			}
-			c.Printf("\n")
-			if len(deliverable.Status.Resources) == 0 {
-				c.Infof(notFoundMsg)
-			} else if err := printer.DeliverableResourcesPrinter(c.Stdout, deliverable); err != nil {
-				return err
-			}
+		} else if err := printer.DeliverableResourcesPrinter(c.Stdout, deliverable, opts.Timestamps); err != nil {
+			return err
 		}
 	} else {
 		c.Printf("\n")
@@ -197,54 +343,58 @@ func (opts *WorkloadGetOptions) Exec(ctx context.Context, c *cli.Config) error {
 
 	// Print workload issues
 	c.Printf("\n")
-	c.EmojiBoldf(cli.SpeechBalloon, "Messages\n")
+	c.Boldf("%s\n", theme.Messages)
 	workloadStatusReadyCond := printer.FindCondition(workload.Status.Conditions, cartov1alpha1.WorkloadConditionReady)
 	if areAllResourcesReady(workloadStatusReadyCond, deliverableStatusReadyCond) {
 		c.Infof(printer.AddPaddingStart("No messages found.\n"))
 	} else {
-		if err := printer.WorkloadIssuesPrinter(c.Stdout, workload); err != nil {
+		if err := printer.MessagesPrinter(c.Stdout, printer.WorkloadMessages(workload), opts.AllMessages); err != nil {
 			return err
 		}
-		if err := printer.DeliverableIssuesPrinter(c.Stdout, deliverable); err != nil {
+		if err := printer.MessagesPrinter(c.Stdout, printer.DeliverableMessages(deliverable), opts.AllMessages); err != nil {
 			return err
 		}
 	}
 
 	if len(workload.Spec.ServiceClaims) > 0 {
 		c.Printf("\n")
-		c.EmojiBoldf(cli.Repeat, "Services\n")
+		c.Boldf("%s\n", theme.Services)
 		if err := cartov1alpha1.WorkloadServiceClaimPrinter(c.Stdout, workload); err != nil {
 			return err
 		}
 	}
 
-	arg := []string{"Pod"}
-	labelSelectorParams := fmt.Sprintf("%s%s%s", cartov1alpha1.WorkloadLabelName, "=", workload.Name)
-	if tableResult, err := source.FetchResourceObjects(c.Builder, workload.Namespace, labelSelectorParams, arg); err != nil {
-		c.Eprintf("\n")
-		c.Eerrorf("Failed to list pods:\n")
-		c.Eprintf("  %s\n", err)
-	} else {
-		if tableResult != nil {
-			c.Printf("\n")
-			c.EmojiBoldf(cli.Canoe, "Pods\n")
-			printer.PodTablePrinter(c, tableResult)
-		} else {
-			c.Printf("\n")
-			c.Infof("No pods found for workload.\n")
+	if sections.pods != nil {
+		c.Printf("\n")
+		c.Boldf("%s\n", theme.Pods)
+		printer.PodTablePrinter(c, sections.pods)
+		if opts.WithLogs > 0 {
+			opts.printLogs(ctx, c, workload)
 		}
+	} else if sections.podsErr == nil {
+		c.Printf("\n")
+		c.Infof("No pods found for workload.\n")
 	}
 
-	ksvcs := &knativeservingv1.ServiceList{}
-	_ = c.List(ctx, ksvcs, client.InNamespace(workload.Namespace), client.MatchingLabels{cartov1alpha1.WorkloadLabelName: workload.Name})
-	if len(ksvcs.Items) > 0 {
-		ksvcs = ksvcs.DeepCopy()
-		printer.SortByNamespaceAndName(ksvcs.Items)
+	if sections.ksvcs != nil && len(sections.ksvcs.Items) > 0 {
+		ksvcs := sections.ksvcs
 		c.Printf("\n")
-		c.EmojiBoldf(cli.Ship, "Knative Services\n")
+		c.Boldf("%s\n", theme.KnativeServices)
 		if err := printer.KnativeServicePrinter(c, ksvcs); err != nil {
 			return err
 		}
+		for i := range ksvcs.Items {
+			ksvc := &ksvcs.Items[i]
+			route := sections.routes[i]
+			if route == nil || len(route.Status.Traffic) == 0 {
+				continue
+			}
+			c.Printf("\n")
+			c.Printf(printer.AddPaddingStart("%s latest ready revision: %s\n"), ksvc.Name, printer.EmptyString(ksvc.Status.LatestReadyRevisionName))
+			if err := printer.KnativeRouteTrafficPrinter(c, route); err != nil {
+				return err
+			}
+		}
 	}
 
 	c.Printf("\n")
@@ -255,35 +405,524 @@ func (opts *WorkloadGetOptions) Exec(ctx context.Context, c *cli.Config) error {
 	}
 	c.Printf("\n")
 
+	sections.printDiagnostics(c)
+
 	return nil
 }
 
+// workloadGetSections are the resources of the workload get sections that are queried from the
+// cluster, with the errors of the sections that could not be fetched
+type workloadGetSections struct {
+	// sourceProvider is the GitRepository or ImageRepository stamped by the supply chain to fetch
+	// the source of the workload
+	sourceProvider    *unstructured.Unstructured
+	sourceProviderErr error
+	deliverable       *cartov1alpha1.Deliverable
+	deliverableErr    error
+	pods              runtime.Object
+	podsErr           error
+	ksvcs             *knativeservingv1.ServiceList
+	// routes are the routes of ksvcs, by index, nil for a service without a route
+	routes  []*knativeservingv1.Route
+	ksvcErr error
+}
+
+// fetchWorkloadGetSections queries the source provider, deliverable, pods and knative services of the workload
+// concurrently, within a shared timeout. A section that fails does not abort the others, its error
+// is kept for printDiagnostics.
+func fetchWorkloadGetSections(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) *workloadGetSections {
+	ctx, cancel := context.WithTimeout(ctx, workloadGetFetchTimeout)
+	defer cancel()
+
+	sections := &workloadGetSections{}
+	g := &errgroup.Group{}
+	if ref := getWorkloadSourceProvider(workload); ref != nil {
+		g.Go(func() error {
+			sourceProvider := &unstructured.Unstructured{}
+			sourceProvider.SetAPIVersion(ref.APIVersion)
+			sourceProvider.SetKind(ref.Kind)
+			if err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, sourceProvider); err != nil {
+				// the kind may not be installed on the cluster
+				if !apierrs.IsNotFound(err) && !meta.IsNoMatchError(err) && !runtime.IsNotRegisteredError(err) {
+					sections.sourceProviderErr = err
+				}
+				return nil
+			}
+			sections.sourceProvider = sourceProvider
+			return nil
+		})
+	}
+	if wldDeliverable := getWorkloadResourceByKind(workload, cartov1alpha1.DeliverableKind); wldDeliverable != nil {
+		g.Go(func() error {
+			deliverable := &cartov1alpha1.Deliverable{}
+			if err := c.Get(ctx, client.ObjectKey{Namespace: wldDeliverable.StampedRef.Namespace, Name: wldDeliverable.StampedRef.Name}, deliverable); err != nil {
+				if !apierrs.IsNotFound(err) {
+					sections.deliverableErr = err
+				}
+				return nil
+			}
+			sections.deliverable = deliverable
+			return nil
+		})
+	}
+	g.Go(func() error {
+		// the resource builder does not take a context, stop waiting for it once the timeout expires
+		type result struct {
+			pods runtime.Object
+			err  error
+		}
+		done := make(chan result, 1)
+		go func() {
+			labelSelectorParams := fmt.Sprintf("%s%s%s", cartov1alpha1.WorkloadLabelName, "=", workload.Name)
+			pods, err := source.FetchResourceObjects(c.Builder, workload.Namespace, labelSelectorParams, []string{"Pod"})
+			done <- result{pods: pods, err: err}
+		}()
+		select {
+		case r := <-done:
+			sections.pods, sections.podsErr = r.pods, r.err
+		case <-ctx.Done():
+			sections.podsErr = ctx.Err()
+		}
+		return nil
+	})
+	g.Go(func() error {
+		ksvcs := &knativeservingv1.ServiceList{}
+		if err := c.List(ctx, ksvcs, client.InNamespace(workload.Namespace), client.MatchingLabels{cartov1alpha1.WorkloadLabelName: workload.Name}); err != nil {
+			sections.ksvcErr = err
+			return nil
+		}
+		ksvcs = ksvcs.DeepCopy()
+		printer.SortByNamespaceAndName(ksvcs.Items)
+		routes := make([]*knativeservingv1.Route, len(ksvcs.Items))
+		for i := range ksvcs.Items {
+			route := &knativeservingv1.Route{}
+			if err := c.Get(ctx, client.ObjectKey{Namespace: ksvcs.Items[i].Namespace, Name: ksvcs.Items[i].Name}, route); err != nil {
+				if !apierrs.IsNotFound(err) && sections.ksvcErr == nil {
+					sections.ksvcErr = fmt.Errorf("route %q: %w", ksvcs.Items[i].Name, err)
+				}
+				continue
+			}
+			routes[i] = route
+		}
+		sections.ksvcs, sections.routes = ksvcs, routes
+		return nil
+	})
+	// the sections keep their errors, none is returned to the group
+	_ = g.Wait()
+
+	return sections
+}
+
+// printDiagnostics prints the sections that could not be fetched, after the report of the sections
+// that could
+func (s *workloadGetSections) printDiagnostics(c *cli.Config) {
+	diagnostics := []struct {
+		section string
+		err     error
+	}{
+		{section: "source", err: s.sourceProviderErr},
+		{section: "delivery", err: s.deliverableErr},
+		{section: "pods", err: s.podsErr},
+		{section: "knative services", err: s.ksvcErr},
+	}
+	printed := false
+	for _, d := range diagnostics {
+		if d.err == nil {
+			continue
+		}
+		if !printed {
+			c.Eprintf("%s some sections could not be fetched, the report may be incomplete:\n", printer.Swarnf("Warning:"))
+			printed = true
+		}
+		c.Eprintf("  %s: %s\n", d.section, d.err)
+	}
+	if printed {
+		c.Eprintf("\n")
+	}
+}
+
 func NewWorkloadGetCommand(ctx context.Context, c *cli.Config) *cobra.Command {
 	opts := &WorkloadGetOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "get",
-		Short: "Get details from a workload",
-		Long:  strings.TrimSpace(`Get details from a workload`),
-		Example: strings.Join([]string{
-			fmt.Sprintf("%s workload get my-workload", c.Name),
-		}, "\n"),
+		Use:     "get",
+		Aliases: []string{"g"},
+		Short:   "Get details from a workload",
+		Long: strings.TrimSpace(`
+Get details from a workload.
+
+With ` + flags.AppFlagName + `, print a combined status report of all the workloads that
+are part of an application, across namespaces with ` + flags.AllNamespacesFlagName + `.
+`),
+		Example:           examplesFor(c, "workload get"),
 		PreRunE:           cli.ValidateE(ctx, opts),
 		RunE:              cli.ExecE(ctx, c, opts),
 		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
 	}
 
 	cli.Args(cmd,
-		cli.NameArg(&opts.Name),
+		cli.OptionalNameArg(&opts.Name),
 	)
 
-	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cli.AllNamespacesFlag(ctx, cmd, c, &opts.Namespace, &opts.AllNamespaces)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVar(&opts.App, cli.StripDash(flags.AppFlagName), "", "application `name` to report on all the workloads of instead of a single workload")
 	cmd.Flags().BoolVar(&opts.Export, cli.StripDash(flags.ExportFlagName), false, "export workload in yaml format")
-	cmd.Flags().StringVarP(&opts.Output, cli.StripDash(flags.OutputFlagName), "o", "", "output the Workload formatted. Supported formats: \"json\", \"yaml\", \"yml\"")
+	cmd.Flags().StringVar(&opts.ExportProfile, cli.StripDash(flags.ExportProfileFlagName), "", fmt.Sprintf("`profile` of the metadata kept by %s, one of %s (default %s)", flags.ExportFlagName, strings.Join(ExportProfiles, ", "), ExportProfileBackup))
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.ExportProfileFlagName), func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return ExportProfiles, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().BoolVar(&opts.ExportDeliverable, cli.StripDash(flags.ExportDeliverableFlagName), false, "export the deliverable produced by the supply chain, ready to apply on a run cluster")
+	cmd.Flags().StringVar(&opts.ToContext, cli.StripDash(flags.ToContextFlagName), "", "kube config `context` to apply the exported deliverable to instead of printing it")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.ToContextFlagName), completion.SuggestContexts(ctx, c))
+	cmd.Flags().StringVarP(&opts.Output, cli.StripDash(flags.OutputFlagName), "o", "", "output the Workload formatted, or \"wide\" to add the api version, namespace and outputs of the supply chain resources to the default view. Supported formats: \"json\", \"yaml\", \"yml\", \"wide\"")
+	cmd.Flags().BoolVar(&opts.IncludeSummary, cli.StripDash(flags.IncludeSummaryFlagName), false, "add a \"summary\" of the Ready condition, supply chain, pods and Knative service urls of the workload to the json or yaml output")
+	cmd.Flags().BoolVar(&opts.AllMessages, cli.StripDash(flags.AllMessagesFlagName), false, "show every message instead of collapsing the ones repeated by several resources")
+	cmd.Flags().BoolVar(&opts.Previous, cli.StripDash(flags.PreviousFlagName), false, "show the spec last applied with kubectl apply and the changes made to it since, read from the last-applied-configuration annotation")
+	cmd.Flags().BoolVar(&opts.Timestamps, cli.StripDash(flags.TimestampsFlagName), false, "show how long ago each supply chain and delivery resource transitioned, falling back to the latest transition of any of its conditions")
+	cmd.Flags().Int64Var(&opts.WithLogs, cli.StripDash(flags.WithLogsFlagName), 0, fmt.Sprintf("show the last `lines` logged by the most recently restarted or failing container beneath the pods, up to %d", maxWithLogsLines))
+	cmd.Flags().Lookup(cli.StripDash(flags.WithLogsFlagName)).NoOptDefVal = fmt.Sprint(defaultWithLogsLines)
 
 	return cmd
 }
 
+// execApp prints a combined status report of the workloads that are part of the application
+// printLogs prints the last lines logged by the most recently restarted or failing container of
+// the workload pods, with the values that look like credentials redacted
+func (opts *WorkloadGetOptions) printLogs(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(workload.Namespace), client.MatchingLabels{cartov1alpha1.WorkloadLabelName: workload.Name}); err != nil {
+		c.Printf("\n")
+		c.Eprintf("%s unable to list pods for logs: %s\n", printer.Swarnf("Warning:"), err)
+		return
+	}
+	target := findLogsContainer(pods.Items)
+	c.Printf("\n")
+	if target == nil {
+		c.Infof(printer.AddPaddingStart("No restarted or failing containers found.\n"))
+		return
+	}
+
+	text, err := logs.Fetch(ctx, c, target.pod.Namespace, target.pod.Name, target.status.Name, opts.WithLogs, target.previous)
+	if err != nil {
+		c.Eprintf("%s unable to fetch logs of container %q in pod %q: %s\n", printer.Swarnf("Warning:"), target.status.Name, target.pod.Name, err)
+		return
+	}
+	c.Printf(printer.AddPaddingStart("Last %d log lines of container %q in pod %q (%s):\n"), opts.WithLogs, target.status.Name, target.pod.Name, target.describe())
+	text = strings.TrimRight(logs.Redact(text), "\n")
+	if text == "" {
+		c.Infof(printer.AddPaddingStart(printer.AddPaddingStart("No logs found.\n")))
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		c.Printf("%s\n", printer.AddPaddingStart(printer.AddPaddingStart(line)))
+	}
+}
+
+// logsContainer is a container whose logs are shown by --with-logs
+type logsContainer struct {
+	pod    *corev1.Pod
+	status *corev1.ContainerStatus
+	// previous is set when the container is not running, its current instance has not logged yet
+	previous bool
+}
+
+func (l *logsContainer) failing() bool {
+	if waiting := l.status.State.Waiting; waiting != nil && waiting.Reason != "" && waiting.Reason != "ContainerCreating" && waiting.Reason != "PodInitializing" {
+		return true
+	}
+	if terminated := l.status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+		return true
+	}
+	return false
+}
+
+func (l *logsContainer) restartedAt() time.Time {
+	if terminated := l.status.LastTerminationState.Terminated; terminated != nil {
+		return terminated.FinishedAt.Time
+	}
+	return time.Time{}
+}
+
+func (l *logsContainer) describe() string {
+	details := []string{}
+	if l.status.RestartCount == 1 {
+		details = append(details, "restarted 1 time")
+	} else if l.status.RestartCount > 1 {
+		details = append(details, fmt.Sprintf("restarted %d times", l.status.RestartCount))
+	}
+	if waiting := l.status.State.Waiting; waiting != nil && waiting.Reason != "" {
+		details = append(details, waiting.Reason)
+	} else if terminated := l.status.State.Terminated; terminated != nil && terminated.Reason != "" {
+		details = append(details, terminated.Reason)
+	}
+	if l.previous {
+		details = append(details, "previous instance")
+	}
+	return strings.Join(details, ", ")
+}
+
+// findLogsContainer returns the failing container that restarted most recently, or the most
+// recently restarted container when none is failing
+func findLogsContainer(pods []corev1.Pod) *logsContainer {
+	var found *logsContainer
+	for i := range pods {
+		pod := &pods[i]
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for j := range statuses {
+			candidate := &logsContainer{pod: pod, status: &statuses[j]}
+			if !candidate.failing() && candidate.status.RestartCount == 0 {
+				continue
+			}
+			candidate.previous = candidate.status.State.Running == nil && candidate.status.State.Terminated == nil && candidate.status.RestartCount > 0
+			if found == nil || betterLogsContainer(candidate, found) {
+				found = candidate
+			}
+		}
+	}
+	return found
+}
+
+func betterLogsContainer(a, b *logsContainer) bool {
+	if a.failing() != b.failing() {
+		return a.failing()
+	}
+	if !a.restartedAt().Equal(b.restartedAt()) {
+		return a.restartedAt().After(b.restartedAt())
+	}
+	return a.status.RestartCount > b.status.RestartCount
+}
+
+func (opts *WorkloadGetOptions) execApp(ctx context.Context, c *cli.Config) error {
+	workloads := &cartov1alpha1.WorkloadList{}
+	if err := c.List(ctx, workloads, client.InNamespace(opts.Namespace), client.MatchingLabels{apis.AppPartOfLabelName: opts.App}); err != nil {
+		return err
+	}
+	if len(workloads.Items) == 0 {
+		err := fmt.Errorf("no workloads found for app %q", opts.App)
+		c.Errorf("No workloads found for app %q\n", opts.App)
+		return cli.SilenceError(err)
+	}
+	workloads = workloads.DeepCopy()
+	printer.SortByNamespaceAndName(workloads.Items)
+
+	if opts.Output != "" {
+		var list []printer.Object
+		for i := range workloads.Items {
+			list = append(list, &workloads.Items[i])
+		}
+		export, err := printer.OutputResources(list, printer.OutputFormat(opts.Output), c.Scheme)
+		if err != nil {
+			c.Eprintf("%s %s\n", printer.Serrorf("Failed to output workloads:"), err)
+			return cli.SilenceError(err)
+		}
+		c.Printf("%s\n", export)
+		return nil
+	}
+
+	theme, err := printer.ThemeFromConfig(c.Viper)
+	if err != nil {
+		c.Eprintf("%s %s, using the %s theme\n", printer.Swarnf("Warning:"), err, printer.DefaultThemeName)
+	}
+
+	c.Boldf("%s\n", theme.Overview)
+	if err := printer.AppOverviewPrinter(c.Stdout, opts.App, workloads); err != nil {
+		return err
+	}
+	c.Printf("\n")
+	if err := printer.AppWorkloadsPrinter(c.Stdout, workloads); err != nil {
+		return err
+	}
+
+	c.Printf("\n")
+	c.Boldf("%s\n", theme.Messages)
+	if messages := printer.AppMessages(workloads); len(messages) == 0 {
+		c.Infof(printer.AddPaddingStart("No messages found.\n"))
+	} else if err := printer.MessagesPrinter(c.Stdout, messages, opts.AllMessages); err != nil {
+		return err
+	}
+
+	c.Printf("\n")
+	c.Infof("To see a workload: \"tanzu apps workload get <name> %s <namespace>\"\n", flags.NamespaceFlagName)
+	c.Printf("\n")
+	return nil
+}
+
+func (opts *WorkloadGetOptions) exportDeliverable(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) error {
+	deliverable, err := getWorkloadDeliverable(ctx, c, workload)
+	if err != nil {
+		c.Eprintf("%s %s\n", printer.Serrorf("Failed to export deliverable:"), err)
+		return cli.SilenceError(err)
+	}
+
+	if opts.ToContext != "" {
+		return applyDeliverable(ctx, c, c.ClientForContext(opts.ToContext), deliverable, opts.ToContext)
+	}
+
+	format := printer.OutputFormat(printer.OutputFormatYaml)
+	if opts.Output != "" {
+		format = printer.OutputFormat(opts.Output)
+	}
+	export, err := printer.ExportResource(deliverable, format, c.Scheme)
+	if err != nil {
+		c.Eprintf("%s %s\n", printer.Serrorf("Failed to export deliverable:"), err)
+		return cli.SilenceError(err)
+	}
+	c.Printf("%s\n", export)
+	return nil
+}
+
+// printPrevious shows the spec of the workload last applied with kubectl apply, as recorded in its
+// last-applied-configuration annotation, and the changes made to the spec since then
+func (opts *WorkloadGetOptions) printPrevious(c *cli.Config, workload *cartov1alpha1.Workload) error {
+	lastApplied, ok := workload.Annotations[corev1.LastAppliedConfigAnnotation]
+	if !ok {
+		c.Infof("Workload %q has no previously applied spec, it is only recorded by kubectl apply\n", workload.Name)
+		return nil
+	}
+	applied := &cartov1alpha1.Workload{}
+	if err := json.Unmarshal([]byte(lastApplied), applied); err != nil {
+		c.Eprintf("%s unable to read the previously applied spec: %s\n", printer.Serrorf("Error:"), err)
+		return cli.SilenceError(err)
+	}
+
+	// only the specs are compared, the metadata and status of the workload change on their own
+	meta := metav1.ObjectMeta{Namespace: workload.Namespace, Name: workload.Name}
+	previous := &cartov1alpha1.Workload{ObjectMeta: meta, Spec: applied.Spec}
+	current := &cartov1alpha1.Workload{ObjectMeta: meta, Spec: workload.Spec}
+	export, err := printer.ExportResource(previous, printer.OutputFormat(printer.OutputFormatYaml), c.Scheme)
+	if err != nil {
+		return err
+	}
+	difference, noChange, err := printer.ResourceDiff(previous, current, c.Scheme)
+	if err != nil {
+		return err
+	}
+
+	c.Printf("Previously applied spec of workload %q:\n", workload.Name)
+	c.Printf("%s\n\n", export)
+	if noChange {
+		c.Infof("Spec is unchanged since it was applied\n")
+		return nil
+	}
+	c.Printf("Changes since it was applied:\n")
+	c.Printf("%s\n", difference)
+	return nil
+}
+
+// getWorkloadDeliverable resolves the deliverable stamped by the workload's supply chain. Supply
+// chains either stamp the Deliverable directly or, when delivery happens on another cluster,
+// write its definition into a ConfigMap.
+func getWorkloadDeliverable(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) (*cartov1alpha1.Deliverable, error) {
+	if ref := getWorkloadResourceByKind(workload, cartov1alpha1.DeliverableKind); ref != nil {
+		deliverable := &cartov1alpha1.Deliverable{}
+		if err := c.Get(ctx, stampedRefKey(workload, ref), deliverable); err != nil {
+			return nil, err
+		}
+		return deliverable, nil
+	}
+
+	if ref := getWorkloadResourceByKind(workload, "ConfigMap"); ref != nil {
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, stampedRefKey(workload, ref), cm); err != nil {
+			return nil, err
+		}
+		if content, ok := cm.Data[cartov1alpha1.DeliverableConfigMapKey]; ok {
+			deliverable := &cartov1alpha1.Deliverable{}
+			if err := deliverable.Load(strings.NewReader(content)); err != nil {
+				return nil, fmt.Errorf("unable to load deliverable from ConfigMap %q: %w", ref.StampedRef.Name, err)
+			}
+			if deliverable.Namespace == "" {
+				deliverable.Namespace = workload.Namespace
+			}
+			return deliverable, nil
+		}
+	}
+
+	return nil, fmt.Errorf("deliverable for workload %q not found", workload.Name)
+}
+
+// stampedRefKey returns the key of the resource stamped for the workload, a resource stamped without a
+// namespace is in the namespace of the workload
+func stampedRefKey(workload *cartov1alpha1.Workload, ref *cartov1alpha1.RealizedResource) client.ObjectKey {
+	namespace := ref.StampedRef.Namespace
+	if namespace == "" {
+		namespace = workload.Namespace
+	}
+	return client.ObjectKey{Namespace: namespace, Name: ref.StampedRef.Name}
+}
+
+func applyDeliverable(ctx context.Context, c *cli.Config, target cli.Client, deliverable *cartov1alpha1.Deliverable, kubeContext string) error {
+	// the carto.run labels tie the deliverable to the workload and supply chain of the build cluster
+	deliverableLabels := map[string]string{}
+	for key, value := range deliverable.Labels {
+		if !strings.HasPrefix(key, cartov1alpha1.GroupName+"/") {
+			deliverableLabels[key] = value
+		}
+	}
+	if len(deliverableLabels) == 0 {
+		deliverableLabels = nil
+	}
+	desired := &cartov1alpha1.Deliverable{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        deliverable.Name,
+			Namespace:   deliverable.Namespace,
+			Labels:      deliverableLabels,
+			Annotations: deliverable.Annotations,
+		},
+		Spec: *deliverable.Spec.DeepCopy(),
+	}
+
+	current := &cartov1alpha1.Deliverable{}
+	err := target.Get(ctx, client.ObjectKey{Namespace: desired.Namespace, Name: desired.Name}, current)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	if apierrs.IsNotFound(err) {
+		if err := target.Create(ctx, desired); err != nil {
+			return err
+		}
+		c.Successf("Created deliverable %q in context %q\n", desired.Name, kubeContext)
+		return nil
+	}
+
+	// labels and annotations set on the target cluster, for example by its own tooling, are kept
+	current.Labels = mergeStringMaps(current.Labels, desired.Labels)
+	current.Annotations = mergeStringMaps(current.Annotations, desired.Annotations)
+	current.Spec = desired.Spec
+	if err := target.Update(ctx, current); err != nil {
+		return err
+	}
+	c.Successf("Updated deliverable %q in context %q\n", desired.Name, kubeContext)
+	return nil
+}
+
+// mergeStringMaps sets the entries of src in dst, allocating dst when needed
+func mergeStringMaps(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// getWorkloadSourceProvider returns the reference to the resource that fetches the source of the
+// workload, nil when the supply chain has not stamped one
+func getWorkloadSourceProvider(workload *cartov1alpha1.Workload) *corev1.ObjectReference {
+	for _, kind := range sourceProviderKinds {
+		// the group of the kind is needed to fetch it
+		if resource := getWorkloadResourceByKind(workload, kind); resource != nil && resource.StampedRef.APIVersion != "" {
+			return resource.StampedRef
+		}
+	}
+	return nil
+}
+
 func getWorkloadResourceByKind(workload *cartov1alpha1.Workload, kind string) *cartov1alpha1.RealizedResource {
 	for _, resource := range workload.Status.Resources {
 		if resource.StampedRef != nil && resource.StampedRef.Kind == kind {
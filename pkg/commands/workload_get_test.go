@@ -17,21 +17,27 @@ limitations under the License.
 package commands_test
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	diecorev1 "dies.dev/apis/core/v1"
 	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
 	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
 	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
 	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/logs"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
 	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
@@ -39,6 +45,7 @@ import (
 	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
 	diev1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/knative/serving/v1"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	themeprinter "github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
 )
 
 func TestWorkloadGetOptionsValidate(t *testing.T) {
@@ -100,7 +107,251 @@ func TestWorkloadGetOptionsValidate(t *testing.T) {
 				Name:      "my-workload",
 				Output:    "myFormat",
 			},
-			ExpectFieldErrors: validation.EnumInvalidValue("myFormat", flags.OutputFlagName, []string{"json", "yaml", "yml"}),
+			ExpectFieldErrors: validation.EnumInvalidValue("myFormat", flags.OutputFlagName, []string{"json", "yaml", "yml", "wide"}),
+		},
+		{
+			Name: "wide output",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Output:    commands.OutputFormatWide,
+				WithLogs:  20,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "wide output with export",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Output:    commands.OutputFormatWide,
+				Export:    true,
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue(commands.OutputFormatWide, flags.OutputFlagName),
+		},
+		{
+			Name: "wide output with app",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				App:       "my-app",
+				Output:    commands.OutputFormatWide,
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue(commands.OutputFormatWide, flags.OutputFlagName),
+		},
+		{
+			Name: "export deliverable",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace:         "default",
+				Name:              "my-workload",
+				ExportDeliverable: true,
+				ToContext:         "run-cluster",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "export profile",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace:     "default",
+				Name:          "my-workload",
+				Export:        true,
+				ExportProfile: commands.ExportProfileGitOps,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid export profile",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace:     "default",
+				Name:          "my-workload",
+				Export:        true,
+				ExportProfile: "archive",
+			},
+			ExpectFieldErrors: validation.EnumInvalidValue("archive", flags.ExportProfileFlagName, commands.ExportProfiles),
+		},
+		{
+			Name: "export profile without export",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace:     "default",
+				Name:          "my-workload",
+				ExportProfile: commands.ExportProfileDebug,
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.ExportFlagName),
+		},
+		{
+			Name: "export and export deliverable",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace:         "default",
+				Name:              "my-workload",
+				Export:            true,
+				ExportDeliverable: true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.ExportFlagName, flags.ExportDeliverableFlagName),
+		},
+		{
+			Name: "to context without export deliverable",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				ToContext: "run-cluster",
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.ExportDeliverableFlagName),
+		},
+		{
+			Name: "app in all namespaces",
+			Validatable: &commands.WorkloadGetOptions{
+				AllNamespaces: true,
+				App:           "my-app",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "name and app",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				App:       "my-app",
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(cli.NameArgumentName, flags.AppFlagName),
+		},
+		{
+			Name: "invalid app",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				App:       "my-",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("my-", flags.AppFlagName),
+		},
+		{
+			Name: "app and export",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				App:       "my-app",
+				Export:    true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.AppFlagName, flags.ExportFlagName),
+		},
+		{
+			Name: "all namespaces without app",
+			Validatable: &commands.WorkloadGetOptions{
+				AllNamespaces: true,
+				Name:          "my-workload",
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.AppFlagName),
+		},
+		{
+			Name: "with logs",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				WithLogs:  20,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "with too many logs",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				WithLogs:  500,
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue(int64(500), flags.WithLogsFlagName),
+		},
+		{
+			Name: "with logs and output",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				WithLogs:  20,
+				Output:    "yaml",
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.OutputFlagName, flags.WithLogsFlagName),
+		},
+		{
+			Name: "summary without output",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace:      "default",
+				Name:           "my-workload",
+				IncludeSummary: true,
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.OutputFlagName),
+		},
+		{
+			Name: "summary with wide output",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace:      "default",
+				Name:           "my-workload",
+				Output:         "wide",
+				IncludeSummary: true,
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.OutputFlagName),
+		},
+		{
+			Name: "summary with yaml output",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace:      "default",
+				Name:           "my-workload",
+				Output:         "yaml",
+				IncludeSummary: true,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "summary with export",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace:      "default",
+				Name:           "my-workload",
+				Output:         "json",
+				Export:         true,
+				IncludeSummary: true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.ExportFlagName, flags.IncludeSummaryFlagName),
+		},
+		{
+			Name: "app with summary",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace:      "default",
+				App:            "my-app",
+				Output:         "json",
+				IncludeSummary: true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.AppFlagName, flags.IncludeSummaryFlagName),
+		},
+		{
+			Name: "app with logs",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				App:       "my-app",
+				WithLogs:  20,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.AppFlagName, flags.WithLogsFlagName),
+		},
+		{
+			Name: "previous",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Previous:  true,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "previous and export",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Previous:  true,
+				Export:    true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.ExportFlagName, flags.PreviousFlagName),
+		},
+		{
+			Name: "app with previous",
+			Validatable: &commands.WorkloadGetOptions{
+				Namespace: "default",
+				App:       "my-app",
+				Previous:  true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.AppFlagName, flags.PreviousFlagName),
 		},
 	}
 
@@ -116,6 +367,8 @@ func TestWorkloadGetCommand(t *testing.T) {
 	_ = cartov1alpha1.AddToScheme(scheme)
 	_ = corev1.AddToScheme(scheme)
 	_ = knativeservingv1.AddToScheme(scheme)
+	gitRepositoryGVK := schema.GroupVersionKind{Group: "source.toolkit.fluxcd.io", Version: "v1beta1", Kind: "GitRepository"}
+	scheme.AddKnownTypeWithName(gitRepositoryGVK, &unstructured.Unstructured{})
 	objTimeStamp := metav1.NewTime(time.Now().AddDate(-2, 0, 0))
 
 	parent := diecartov1alpha1.WorkloadBlank.
@@ -232,6 +485,102 @@ No pods found for workload.
 
 To see logs: "tanzu apps workload tail my-workload --namespace my-custom-namespace"
 
+`,
+		}, {
+			Name:         "corporate theme",
+			Args:         []string{workloadName},
+			GivenObjects: []client.Object{parent},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(themeprinter.ThemeConfigKey, themeprinter.CorporateThemeName)
+				return c
+			}(),
+			ExpectOutput: `
+Overview
+   name:   my-workload
+   type:   <empty>
+
+Supply Chain reference not found.
+
+   Supply Chain resources not found.
+
+Delivery
+
+   Delivery resources not found.
+
+Messages
+   No messages found.
+
+No pods found for workload.
+
+To see logs: "tanzu apps workload tail my-workload"
+
+`,
+		}, {
+			Name:         "theme with overridden sections",
+			Args:         []string{workloadName},
+			GivenObjects: []client.Object{parent},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(themeprinter.ThemeConfigKey, map[string]interface{}{
+					"sections": map[string]interface{}{
+						"overview": map[string]interface{}{"icon": "*", "header": "Summary"},
+						"messages": map[string]interface{}{"icon": ""},
+					},
+				})
+				return c
+			}(),
+			ExpectOutput: `
+* Summary
+   name:   my-workload
+   type:   <empty>
+
+Supply Chain reference not found.
+
+   Supply Chain resources not found.
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+Messages
+   No messages found.
+
+No pods found for workload.
+
+To see logs: "tanzu apps workload tail my-workload"
+
+`,
+		}, {
+			Name:         "unknown theme",
+			Args:         []string{workloadName},
+			GivenObjects: []client.Object{parent},
+			Config: func() *cli.Config {
+				c := cli.NewDefaultConfig("test", scheme)
+				c.Viper.Set(themeprinter.ThemeConfigKey, "neon")
+				return c
+			}(),
+			ExpectOutput: `
+Warning: unknown theme "neon", expected one of corporate, default, using the default theme
+📡 Overview
+   name:   my-workload
+   type:   <empty>
+
+Supply Chain reference not found.
+
+   Supply Chain resources not found.
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   No messages found.
+
+No pods found for workload.
+
+To see logs: "tanzu apps workload tail my-workload"
+
 `,
 		}, {
 			Name: "no supply chain ref but conditions in status",
@@ -499,40 +848,62 @@ To see logs: "tanzu apps workload tail my-workload"
 
 `,
 		}, {
-			Name: "show issues with unknown status",
+			Name: "gitops destination",
 			Args: []string{workloadName},
 			GivenObjects: []client.Object{
 				parent.
 					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
 						d.ConditionsDie(
 							diecartov1alpha1.WorkloadConditionReadyBlank.
-								Status(metav1.ConditionUnknown).
-								Reason("OopsieDoodle").
-								Message("a hopefully informative message about what went wrong"),
+								Status(metav1.ConditionTrue).Reason("Ready").
+								Message(""),
 						).SupplyChainRef(cartov1alpha1.ObjectReference{
 							APIVersion: "supplychains.tanzu.vmware.com/v1alpha1",
 							Kind:       "SupplyChain",
 							Name:       "my-supply-chain",
 							Namespace:  defaultNamespace,
+						}).Resources(cartov1alpha1.RealizedResource{
+							Name: "config-writer",
+							StampedRef: &corev1.ObjectReference{
+								APIVersion: "carto.run/v1alpha1",
+								Kind:       "Runnable",
+								Namespace:  defaultNamespace,
+								Name:       "my-workload-config-writer",
+							},
+							Outputs: []cartov1alpha1.Output{{
+								Name:    "url",
+								Preview: "https://github.com/example/gitops.git\n",
+							}, {
+								Name:    "branch",
+								Preview: "main\n",
+							}, {
+								Name:    "path",
+								Preview: "config/default/my-workload\n",
+							}},
 						})
 					}),
 			},
-			ExpectOutput: `
-📡 Overview
+			ExpectOutput: `📡 Overview
    name:   my-workload
    type:   <empty>
 
 📦 Supply Chain
    name:   my-supply-chain
 
-   Supply Chain resources not found.
+   RESOURCE        READY   HEALTHY   TIME   OUTPUT
+   config-writer                            Runnable/my-workload-config-writer
+
+📤 GitOps
+   url:      https://github.com/example/gitops.git
+   branch:   main
+   path:     config/default/my-workload
 
 🚚 Delivery
 
    Delivery resources not found.
 
 💬 Messages
-   Workload [OopsieDoodle]:   a hopefully informative message about what went wrong
+   No messages found.
 
 No pods found for workload.
 
@@ -540,13 +911,10 @@ To see logs: "tanzu apps workload tail my-workload"
 
 `,
 		}, {
-			Name: "show issues",
+			Name: "show issues with unknown status",
 			Args: []string{workloadName},
 			GivenObjects: []client.Object{
 				parent.
-					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
-						d.AddLabel(apis.WorkloadTypeLabelName, "web")
-					}).
 					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
 						d.ConditionsDie(
 							diecartov1alpha1.WorkloadConditionReadyBlank.
@@ -564,7 +932,7 @@ To see logs: "tanzu apps workload tail my-workload"
 			ExpectOutput: `
 📡 Overview
    name:   my-workload
-   type:   web
+   type:   <empty>
 
 📦 Supply Chain
    name:   my-supply-chain
@@ -584,42 +952,47 @@ To see logs: "tanzu apps workload tail my-workload"
 
 `,
 		}, {
-			Name: "show status with false condition",
+			Name: "collapse repeated messages",
 			Args: []string{workloadName},
 			GivenObjects: []client.Object{
 				parent.
-					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
-						d.AddLabel(apis.WorkloadTypeLabelName, "web")
-					}).
 					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
 						d.ConditionsDie(
 							diecartov1alpha1.WorkloadConditionReadyBlank.
-								Status(metav1.ConditionFalse).Reason("OopsieDoodle").
-								Message("a hopefully informative message about what went wrong"),
-						).SupplyChainRef(cartov1alpha1.ObjectReference{
-							APIVersion: "supplychains.tanzu.vmware.com/v1alpha1",
-							Kind:       "SupplyChain",
-							Name:       "my-supply-chain",
-							Namespace:  defaultNamespace,
-						})
+								Status(metav1.ConditionFalse).
+								Reason("TemplateRejectedByAPIServer").
+								Message("exceeded quota"),
+						)
+						resource := func(name string) cartov1alpha1.RealizedResource {
+							return diecartov1alpha1.RealizedResourceBlank.
+								Name(name).
+								ConditionsDie(
+									diecartov1alpha1.CreateConditionResourceReadyFalse("TemplateRejectedByAPIServer", "exceeded quota"),
+								).
+								DieRelease()
+						}
+						d.Resources(resource("image-builder"), resource("config-provider"), resource("app-config"))
 					}),
 			},
 			ExpectOutput: `
 📡 Overview
    name:   my-workload
-   type:   web
+   type:   <empty>
 
 📦 Supply Chain
-   name:   my-supply-chain
+   name:   <none>
 
-   Supply Chain resources not found.
+   RESOURCE          READY   HEALTHY   TIME        OUTPUT
+   image-builder     False             <unknown>   not found
+   config-provider   False             <unknown>   not found
+   app-config        False             <unknown>   not found
 
 🚚 Delivery
 
    Delivery resources not found.
 
 💬 Messages
-   Workload [OopsieDoodle]:   a hopefully informative message about what went wrong
+   Workload [TemplateRejectedByAPIServer]:   exceeded quota (×3 resources)
 
 No pods found for workload.
 
@@ -627,17 +1000,155 @@ To see logs: "tanzu apps workload tail my-workload"
 
 `,
 		}, {
-			Name: "show source info - git",
-			Args: []string{workloadName},
+			Name: "show all messages",
+			Args: []string{workloadName, flags.AllMessagesFlagName},
 			GivenObjects: []client.Object{
 				parent.
-					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
-						d.Source(&cartov1alpha1.Source{
-							Git: &cartov1alpha1.GitSource{
-								URL: url,
-								Ref: cartov1alpha1.GitRef{
-									Branch: "master",
-									Tag:    "v1.0.0",
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionFalse).
+								Reason("TemplateRejectedByAPIServer").
+								Message("exceeded quota"),
+						)
+						resource := func(name string) cartov1alpha1.RealizedResource {
+							return diecartov1alpha1.RealizedResourceBlank.
+								Name(name).
+								ConditionsDie(
+									diecartov1alpha1.CreateConditionResourceReadyFalse("TemplateRejectedByAPIServer", "exceeded quota"),
+								).
+								DieRelease()
+						}
+						d.Resources(resource("image-builder"), resource("config-provider"), resource("app-config"))
+					}),
+			},
+			ExpectOutput: `
+📡 Overview
+   name:   my-workload
+   type:   <empty>
+
+📦 Supply Chain
+   name:   <none>
+
+   RESOURCE          READY   HEALTHY   TIME        OUTPUT
+   image-builder     False             <unknown>   not found
+   config-provider   False             <unknown>   not found
+   app-config        False             <unknown>   not found
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   Workload [TemplateRejectedByAPIServer]:          exceeded quota
+   image-builder [TemplateRejectedByAPIServer]:     exceeded quota
+   config-provider [TemplateRejectedByAPIServer]:   exceeded quota
+   app-config [TemplateRejectedByAPIServer]:        exceeded quota
+
+No pods found for workload.
+
+To see logs: "tanzu apps workload tail my-workload"
+
+`,
+		}, {
+			Name: "show issues",
+			Args: []string{workloadName},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.WorkloadTypeLabelName, "web")
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionUnknown).
+								Reason("OopsieDoodle").
+								Message("a hopefully informative message about what went wrong"),
+						).SupplyChainRef(cartov1alpha1.ObjectReference{
+							APIVersion: "supplychains.tanzu.vmware.com/v1alpha1",
+							Kind:       "SupplyChain",
+							Name:       "my-supply-chain",
+							Namespace:  defaultNamespace,
+						})
+					}),
+			},
+			ExpectOutput: `
+📡 Overview
+   name:   my-workload
+   type:   web
+
+📦 Supply Chain
+   name:   my-supply-chain
+
+   Supply Chain resources not found.
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   Workload [OopsieDoodle]:   a hopefully informative message about what went wrong
+
+No pods found for workload.
+
+To see logs: "tanzu apps workload tail my-workload"
+
+`,
+		}, {
+			Name: "show status with false condition",
+			Args: []string{workloadName},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.WorkloadTypeLabelName, "web")
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionFalse).Reason("OopsieDoodle").
+								Message("a hopefully informative message about what went wrong"),
+						).SupplyChainRef(cartov1alpha1.ObjectReference{
+							APIVersion: "supplychains.tanzu.vmware.com/v1alpha1",
+							Kind:       "SupplyChain",
+							Name:       "my-supply-chain",
+							Namespace:  defaultNamespace,
+						})
+					}),
+			},
+			ExpectOutput: `
+📡 Overview
+   name:   my-workload
+   type:   web
+
+📦 Supply Chain
+   name:   my-supply-chain
+
+   Supply Chain resources not found.
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   Workload [OopsieDoodle]:   a hopefully informative message about what went wrong
+
+No pods found for workload.
+
+To see logs: "tanzu apps workload tail my-workload"
+
+`,
+		}, {
+			Name: "show source info - git",
+			Args: []string{workloadName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: url,
+								Ref: cartov1alpha1.GitRef{
+									Branch: "master",
+									Tag:    "v1.0.0",
 									Commit: "abcdef",
 								},
 							},
@@ -684,6 +1195,95 @@ No pods found for workload.
 
 To see logs: "tanzu apps workload tail my-workload"
 
+`,
+		}, {
+			Name: "show source info - git with the fetched revision",
+			Args: []string{workloadName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: url,
+								Ref: cartov1alpha1.GitRef{
+									Branch: "main",
+								},
+							},
+						})
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue),
+						).SupplyChainRef(cartov1alpha1.ObjectReference{
+							APIVersion: "supplychains.tanzu.vmware.com/v1alpha1",
+							Kind:       "SupplyChain",
+							Name:       "my-supply-chain",
+							Namespace:  defaultNamespace,
+						})
+						d.Resources(
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("source-provider").
+								StampedRef(&corev1.ObjectReference{
+									APIVersion: gitRepositoryGVK.GroupVersion().String(),
+									Kind:       gitRepositoryGVK.Kind,
+									Namespace:  defaultNamespace,
+									Name:       workloadName,
+								}).
+								ConditionsDie(
+									diecartov1alpha1.WorkloadConditionResourceReadyBlank.
+										Status(metav1.ConditionTrue),
+									diecartov1alpha1.WorkloadConditionResourceHealthyBlank.
+										Status(metav1.ConditionTrue),
+								).DieRelease(),
+						)
+					}),
+				&unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": gitRepositoryGVK.GroupVersion().String(),
+						"kind":       gitRepositoryGVK.Kind,
+						"metadata": map[string]interface{}{
+							"namespace": defaultNamespace,
+							"name":      workloadName,
+						},
+						"status": map[string]interface{}{
+							"artifact": map[string]interface{}{
+								"revision":       "main/3d4c5a7b1e2f",
+								"lastUpdateTime": time.Now().Add(-5 * time.Hour).UTC().Format(time.RFC3339),
+							},
+						},
+					},
+				},
+			},
+			ExpectOutput: `
+📡 Overview
+   name:   my-workload
+   type:   <empty>
+
+💾 Source
+   type:       git
+   url:        https://example.com
+   branch:     main
+   revision:   main/3d4c5a7b1e2f
+   fetched:    5h ago
+
+📦 Supply Chain
+   name:   my-supply-chain
+
+   RESOURCE          READY   HEALTHY   TIME        OUTPUT
+   source-provider   True    True      <unknown>   GitRepository/my-workload
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   No messages found.
+
+No pods found for workload.
+
+To see logs: "tanzu apps workload tail my-workload"
+
 `,
 		}, {
 			Name: "show source info - git with overview type",
@@ -945,21 +1545,16 @@ To see logs: "tanzu apps workload tail my-workload"
 
 `,
 		}, {
-			Name: "show resources with overview type",
-			Args: []string{workloadName},
+			Name: "show resources wide",
+			Args: []string{workloadName, flags.OutputFlagName, commands.OutputFormatWide},
 			GivenObjects: []client.Object{
 				parent.
-					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
-						d.AddLabel(apis.WorkloadTypeLabelName, "web")
-					}).
 					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
 						d.Source(&cartov1alpha1.Source{
 							Git: &cartov1alpha1.GitSource{
 								URL: url,
 								Ref: cartov1alpha1.GitRef{
 									Branch: "master",
-									Tag:    "v1.0.0",
-									Commit: "abcdef",
 								},
 							},
 						})
@@ -967,8 +1562,7 @@ To see logs: "tanzu apps workload tail my-workload"
 					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
 						d.ConditionsDie(
 							diecartov1alpha1.WorkloadConditionReadyBlank.
-								Status(metav1.ConditionFalse).Reason("OopsieDoodle").
-								Message("a hopefully informative message about what went wrong"),
+								Status(metav1.ConditionTrue),
 						).SupplyChainRef(cartov1alpha1.ObjectReference{
 							APIVersion: "supplychains.tanzu.vmware.com/v1alpha1",
 							Kind:       "SupplyChain",
@@ -978,64 +1572,72 @@ To see logs: "tanzu apps workload tail my-workload"
 						d.Resources(
 							diecartov1alpha1.RealizedResourceBlank.
 								Name("source-provider").
+								StampedRef(&corev1.ObjectReference{
+									APIVersion: "source.toolkit.fluxcd.io/v1beta1",
+									Kind:       "GitRepository",
+									Namespace:  defaultNamespace,
+									Name:       workloadName,
+								}).
+								Outputs(cartov1alpha1.Output{
+									Name:    "revision",
+									Preview: "master/abcdef\n",
+									Digest:  "sha256:3d4c",
+								}).
 								ConditionsDie(
 									diecartov1alpha1.WorkloadConditionResourceReadyBlank.
 										Status(metav1.ConditionTrue),
-									diecartov1alpha1.WorkloadConditionResourceSubmittedBlank.
-										Status(metav1.ConditionTrue),
 									diecartov1alpha1.WorkloadConditionResourceHealthyBlank.
 										Status(metav1.ConditionTrue),
 								).DieRelease(),
 							diecartov1alpha1.RealizedResourceBlank.
-								Name("deliverable").
+								Name("image-provider").
 								StampedRef(&corev1.ObjectReference{
-									Kind: cartov1alpha1.DeliverableKind,
+									APIVersion: "kpack.io/v1alpha2",
+									Kind:       "Image",
+									Namespace:  defaultNamespace,
+									Name:       workloadName,
+								}).
+								Outputs(cartov1alpha1.Output{
+									Name:    "image",
+									Preview: "registry.example/my-workload@sha256:978be33a7f0cbe89bf48fbb438846047a28e1298d6d10d0de2d64bdc102a9e69\n",
+									Digest:  "sha256:5b6a",
 								}).
 								ConditionsDie(
 									diecartov1alpha1.WorkloadConditionResourceReadyBlank.
-										Status(metav1.ConditionUnknown),
-									diecartov1alpha1.WorkloadConditionResourceSubmittedBlank.
-										Status(metav1.ConditionUnknown),
+										Status(metav1.ConditionTrue),
 									diecartov1alpha1.WorkloadConditionResourceHealthyBlank.
-										Status(metav1.ConditionUnknown),
+										Status(metav1.ConditionTrue),
 								).DieRelease(),
-							diecartov1alpha1.RealizedResourceBlank.
-								Name("image-builder").
-								ConditionsDie(
-									diecartov1alpha1.WorkloadConditionResourceReadyBlank.
-										Status(metav1.ConditionFalse),
-									diecartov1alpha1.WorkloadConditionResourceSubmittedBlank.
-										Status(metav1.ConditionFalse),
-									diecartov1alpha1.WorkloadConditionResourceHealthyBlank.
-										Status(metav1.ConditionFalse),
-								).StampedRef(&corev1.ObjectReference{Kind: "image", Name: "petclinic"}).DieRelease())
+						)
 					}),
 			},
 			ExpectOutput: `
 📡 Overview
    name:   my-workload
-   type:   web
+   type:   <empty>
 
 💾 Source
    type:     git
    url:      https://example.com
    branch:   master
-   tag:      v1.0.0
-   commit:   abcdef
 
 📦 Supply Chain
    name:   my-supply-chain
 
-   RESOURCE          READY   HEALTHY   TIME        OUTPUT
-   source-provider   True    True      <unknown>   not found
-   image-builder     False   False     <unknown>   image/petclinic
+   RESOURCE          READY   HEALTHY   TIME        OUTPUT                      API VERSION                        NAMESPACE
+   source-provider   True    True      <unknown>   GitRepository/my-workload   source.toolkit.fluxcd.io/v1beta1   default
+   image-provider    True    True      <unknown>   Image/my-workload           kpack.io/v1alpha2                  default
+
+   RESOURCE          OUTPUT     VALUE                                                                                                  DIGEST
+   source-provider   revision   master/abcdef                                                                                          sha256:3d4c
+   image-provider    image      registry.example/my-workload@sha256:978be33a7f0cbe89bf48fbb438846047a28e1298d6d10d0de2d64bdc102a9e69   sha256:5b6a
 
 🚚 Delivery
 
    Delivery resources not found.
 
 💬 Messages
-   Workload [OopsieDoodle]:   a hopefully informative message about what went wrong
+   No messages found.
 
 No pods found for workload.
 
@@ -1043,15 +1645,113 @@ To see logs: "tanzu apps workload tail my-workload"
 
 `,
 		}, {
-			Name: "show healthy rule condition issue",
+			Name: "show resources with overview type",
 			Args: []string{workloadName},
 			GivenObjects: []client.Object{
 				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.WorkloadTypeLabelName, "web")
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: url,
+								Ref: cartov1alpha1.GitRef{
+									Branch: "master",
+									Tag:    "v1.0.0",
+									Commit: "abcdef",
+								},
+							},
+						})
+					}).
 					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
 						d.ConditionsDie(
 							diecartov1alpha1.WorkloadConditionReadyBlank.
-								Status(metav1.ConditionUnknown).
-								Reason("OopsieDoodle").
+								Status(metav1.ConditionFalse).Reason("OopsieDoodle").
+								Message("a hopefully informative message about what went wrong"),
+						).SupplyChainRef(cartov1alpha1.ObjectReference{
+							APIVersion: "supplychains.tanzu.vmware.com/v1alpha1",
+							Kind:       "SupplyChain",
+							Name:       "my-supply-chain",
+							Namespace:  defaultNamespace,
+						})
+						d.Resources(
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("source-provider").
+								ConditionsDie(
+									diecartov1alpha1.WorkloadConditionResourceReadyBlank.
+										Status(metav1.ConditionTrue),
+									diecartov1alpha1.WorkloadConditionResourceSubmittedBlank.
+										Status(metav1.ConditionTrue),
+									diecartov1alpha1.WorkloadConditionResourceHealthyBlank.
+										Status(metav1.ConditionTrue),
+								).DieRelease(),
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("deliverable").
+								StampedRef(&corev1.ObjectReference{
+									Kind: cartov1alpha1.DeliverableKind,
+								}).
+								ConditionsDie(
+									diecartov1alpha1.WorkloadConditionResourceReadyBlank.
+										Status(metav1.ConditionUnknown),
+									diecartov1alpha1.WorkloadConditionResourceSubmittedBlank.
+										Status(metav1.ConditionUnknown),
+									diecartov1alpha1.WorkloadConditionResourceHealthyBlank.
+										Status(metav1.ConditionUnknown),
+								).DieRelease(),
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("image-builder").
+								ConditionsDie(
+									diecartov1alpha1.WorkloadConditionResourceReadyBlank.
+										Status(metav1.ConditionFalse),
+									diecartov1alpha1.WorkloadConditionResourceSubmittedBlank.
+										Status(metav1.ConditionFalse),
+									diecartov1alpha1.WorkloadConditionResourceHealthyBlank.
+										Status(metav1.ConditionFalse),
+								).StampedRef(&corev1.ObjectReference{Kind: "image", Name: "petclinic"}).DieRelease())
+					}),
+			},
+			ExpectOutput: `
+📡 Overview
+   name:   my-workload
+   type:   web
+
+💾 Source
+   type:     git
+   url:      https://example.com
+   branch:   master
+   tag:      v1.0.0
+   commit:   abcdef
+
+📦 Supply Chain
+   name:   my-supply-chain
+
+   RESOURCE          READY   HEALTHY   TIME        OUTPUT
+   source-provider   True    True      <unknown>   not found
+   image-builder     False   False     <unknown>   image/petclinic
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   Workload [OopsieDoodle]:   a hopefully informative message about what went wrong
+
+No pods found for workload.
+
+To see logs: "tanzu apps workload tail my-workload"
+
+`,
+		}, {
+			Name: "show healthy rule condition issue",
+			Args: []string{workloadName},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionUnknown).
+								Reason("OopsieDoodle").
 								Message("a hopefully informative message about what went wrong"),
 							diecartov1alpha1.WorkloadConditionHealthyBlank.
 								Status(metav1.ConditionUnknown).
@@ -1136,6 +1836,161 @@ To see logs: "tanzu apps workload tail my-workload"
 
 To see logs: "tanzu apps workload tail my-workload"
 
+`,
+		}, {
+			Name: "with logs of the crashing container",
+			Args: []string{workloadName, flags.WithLogsFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				fetcher := &logs.FakeFetcher{}
+				fetcher.On("Fetch", mock.Anything, defaultNamespace, "pod1", "workload", int64(20), true).Return("starting\nconnecting to postgres://app:s3cr3t@db:5432/app\npanic: connection refused\n", nil).Once()
+				return logs.StashFetcher(ctx, fetcher), nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				fetcher := logs.RetrieveFetcher(ctx).(*logs.FakeFetcher)
+				fetcher.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				parent,
+				pod1Die.
+					StatusDie(func(d *diecorev1.PodStatusDie) {
+						d.Phase(corev1.PodRunning)
+						d.ContainerStatuses(
+							corev1.ContainerStatus{
+								Name:         "sidecar",
+								RestartCount: 1,
+								State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+								LastTerminationState: corev1.ContainerState{
+									Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(time.Now())},
+								},
+							},
+							corev1.ContainerStatus{
+								Name:         "workload",
+								RestartCount: 3,
+								State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+								LastTerminationState: corev1.ContainerState{
+									Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(time.Now().Add(-time.Minute))},
+								},
+							},
+						)
+					}),
+			},
+			BuilderObjects: []client.Object{pod1Die},
+			ExpectOutput: `
+📡 Overview
+   name:   my-workload
+   type:   <empty>
+
+Supply Chain reference not found.
+
+   Supply Chain resources not found.
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   No messages found.
+
+🛶 Pods
+   NAME   READY   STATUS   RESTARTS   AGE
+   pod1   0/0              0          <unknown>
+
+   Last 20 log lines of container "workload" in pod "pod1" (restarted 3 times, CrashLoopBackOff, previous instance):
+      starting
+      connecting to postgres://app:[REDACTED]@db:5432/app
+      panic: connection refused
+
+To see logs: "tanzu apps workload tail my-workload"
+
+`,
+		}, {
+			Name: "with logs and no failing containers",
+			Args: []string{workloadName, flags.WithLogsFlagName + "=5"},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				return logs.StashFetcher(ctx, &logs.FakeFetcher{}), nil
+			},
+			GivenObjects: []client.Object{
+				parent,
+				pod1Die.
+					StatusDie(func(d *diecorev1.PodStatusDie) {
+						d.Phase(corev1.PodRunning)
+						d.ContainerStatuses(corev1.ContainerStatus{
+							Name:  "workload",
+							State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+						})
+					}),
+			},
+			BuilderObjects: []client.Object{pod1Die},
+			ExpectOutput: `
+📡 Overview
+   name:   my-workload
+   type:   <empty>
+
+Supply Chain reference not found.
+
+   Supply Chain resources not found.
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   No messages found.
+
+🛶 Pods
+   NAME   READY   STATUS   RESTARTS   AGE
+   pod1   0/0              0          <unknown>
+
+   No restarted or failing containers found.
+
+To see logs: "tanzu apps workload tail my-workload"
+
+`,
+		}, {
+			Name: "with logs fetch error",
+			Args: []string{workloadName, flags.WithLogsFlagName + "=5"},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				fetcher := &logs.FakeFetcher{}
+				fetcher.On("Fetch", mock.Anything, defaultNamespace, "pod1", "workload", int64(5), false).Return("", fmt.Errorf("pods \"pod1\" is forbidden")).Once()
+				return logs.StashFetcher(ctx, fetcher), nil
+			},
+			GivenObjects: []client.Object{
+				parent,
+				pod1Die.
+					StatusDie(func(d *diecorev1.PodStatusDie) {
+						d.Phase(corev1.PodFailed)
+						d.ContainerStatuses(corev1.ContainerStatus{
+							Name:  "workload",
+							State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+						})
+					}),
+			},
+			BuilderObjects: []client.Object{pod1Die},
+			ExpectOutput: `
+📡 Overview
+   name:   my-workload
+   type:   <empty>
+
+Supply Chain reference not found.
+
+   Supply Chain resources not found.
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   No messages found.
+
+🛶 Pods
+   NAME   READY   STATUS   RESTARTS   AGE
+   pod1   0/0              0          <unknown>
+
+Warning: unable to fetch logs of container "workload" in pod "pod1": pods "pod1" is forbidden
+
+To see logs: "tanzu apps workload tail my-workload"
+
 `,
 		}, {
 			Name: "show pods",
@@ -1255,6 +2110,78 @@ No pods found for workload.
 
 To see logs: "tanzu apps workload tail my-workload"
 
+`,
+		}, {
+			Name: "show knative services with route traffic",
+			Args: []string{workloadName},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue),
+						).SupplyChainRef(cartov1alpha1.ObjectReference{
+							APIVersion: "supplychains.tanzu.vmware.com/v1alpha1",
+							Kind:       "SupplyChain",
+							Name:       "my-supply-chain",
+							Namespace:  defaultNamespace,
+						})
+					}),
+				ksvcDieWithURL.
+					StatusDie(func(d *diev1.ServiceStatusDie) {
+						d.LatestReadyRevisionName("ksvc1-00002")
+					}),
+				&knativeservingv1.Route{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ksvc1",
+						Namespace: defaultNamespace,
+					},
+					Status: knativeservingv1.RouteStatus{
+						URL: url,
+						Traffic: []knativeservingv1.TrafficTarget{{
+							RevisionName:   "ksvc1-00002",
+							LatestRevision: &[]bool{true}[0],
+							Percent:        &[]int64{80}[0],
+						}, {
+							RevisionName:   "ksvc1-00001",
+							LatestRevision: &[]bool{false}[0],
+							Percent:        &[]int64{20}[0],
+							Tag:            "previous",
+							URL:            "https://previous-ksvc1.default.example.com",
+						}},
+					},
+				},
+			},
+			ExpectOutput: `
+📡 Overview
+   name:   my-workload
+   type:   <empty>
+
+📦 Supply Chain
+   name:   my-supply-chain
+
+   Supply Chain resources not found.
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   No messages found.
+
+No pods found for workload.
+
+🚢 Knative Services
+   NAME    READY   URL
+   ksvc1   Ready   https://example.com
+
+   ksvc1 latest ready revision: ksvc1-00002
+   REVISION      LATEST   TRAFFIC   TAG        URL
+   ksvc1-00002   true     80%       <empty>    <empty>
+   ksvc1-00001   false    20%       previous   https://previous-ksvc1.default.example.com
+
+To see logs: "tanzu apps workload tail my-workload"
+
 `,
 		}, {
 			Name: "show pods and knative services",
@@ -1450,49 +2377,485 @@ To see logs: "tanzu apps workload tail my-workload"
 								Message("a hopefully informative message about what went wrong"),
 						)
 					}),
-			},
-			ExpectOutput: `
----
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  labels:
+    app.kubernetes.io/part-of: my-workload
+  name: my-workload
+  namespace: default
+spec: {}
+`,
+		}, {
+			Name: "get workload exported data in json format",
+			Args: []string{workloadName, flags.ExportFlagName, flags.OutputFlagName, printer.OutputFormatJson},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.AppPartOfLabelName, workloadName)
+						d.CreationTimestamp(metav1.Date(2021, time.September, 10, 15, 00, 00, 00, time.UTC))
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionUnknown).
+								Reason("Workload Reason").
+								Message("a hopefully informative message about what went wrong"),
+						)
+					}),
+			},
+			ExpectOutput: `
+{
+	"apiVersion": "carto.run/v1alpha1",
+	"kind": "Workload",
+	"metadata": {
+		"labels": {
+			"app.kubernetes.io/part-of": "my-workload"
+		},
+		"name": "my-workload",
+		"namespace": "default"
+	},
+	"spec": {}
+}
+`,
+		}, {
+			Name: "get workload exported with the gitops profile",
+			Args: []string{workloadName, flags.ExportFlagName, flags.ExportProfileFlagName, commands.ExportProfileGitOps},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.AppPartOfLabelName, workloadName)
+						d.AddAnnotation("owner", "payments")
+						d.AddAnnotation(corev1.LastAppliedConfigAnnotation, "{}")
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"alice"}`)
+						d.Generation(2)
+						d.ManagedFields(metav1.ManagedFieldsEntry{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply})
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue).Reason("Ready"),
+						)
+					}),
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  annotations:
+    owner: payments
+  labels:
+    app.kubernetes.io/part-of: my-workload
+  name: my-workload
+  namespace: default
+spec: {}
+`,
+		}, {
+			Name: "get workload exported with the backup profile",
+			Args: []string{workloadName, flags.ExportFlagName, flags.ExportProfileFlagName, commands.ExportProfileBackup},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.AppPartOfLabelName, workloadName)
+						d.AddAnnotation("owner", "payments")
+						d.AddAnnotation(corev1.LastAppliedConfigAnnotation, "{}")
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"alice"}`)
+						d.Generation(2)
+						d.ManagedFields(metav1.ManagedFieldsEntry{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply})
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue).Reason("Ready"),
+						)
+					}),
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  annotations:
+    apps.tanzu.vmware.com/last-modified-by: '{"user":"alice"}'
+    kubectl.kubernetes.io/last-applied-configuration: '{}'
+    owner: payments
+  labels:
+    app.kubernetes.io/part-of: my-workload
+  name: my-workload
+  namespace: default
+spec: {}
+`,
+		}, {
+			Name: "get workload exported with the debug profile",
+			Args: []string{workloadName, flags.ExportFlagName, flags.ExportProfileFlagName, commands.ExportProfileDebug},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.AppPartOfLabelName, workloadName)
+						d.AddAnnotation("owner", "payments")
+						d.AddAnnotation(corev1.LastAppliedConfigAnnotation, "{}")
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"alice"}`)
+						d.Generation(2)
+						d.ManagedFields(metav1.ManagedFieldsEntry{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply})
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue).Reason("Ready"),
+						)
+					}),
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  annotations:
+    apps.tanzu.vmware.com/last-modified-by: '{"user":"alice"}'
+    kubectl.kubernetes.io/last-applied-configuration: '{}'
+    owner: payments
+  creationTimestamp: "1970-01-01T00:00:01Z"
+  generation: 2
+  labels:
+    app.kubernetes.io/part-of: my-workload
+  name: my-workload
+  namespace: default
+  resourceVersion: "999"
+spec: {}
+status:
+  conditions:
+  - lastTransitionTime: null
+    message: ""
+    reason: Ready
+    status: "True"
+    type: Ready
+  supplyChainRef: {}
+`,
+		}, {
+			Name: "previous spec",
+			Args: []string{workloadName, flags.PreviousFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(corev1.LastAppliedConfigAnnotation, `{"apiVersion":"carto.run/v1alpha1","kind":"Workload","metadata":{"name":"my-workload","namespace":"default"},"spec":{"image":"ubuntu:bionic"}}`)
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:jammy")
+					}),
+			},
+			ExpectOutput: `
+Previously applied spec of workload "my-workload":
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: my-workload
+  namespace: default
+spec:
+  image: ubuntu:bionic
+
+Changes since it was applied:
+...
+  4,  4   |metadata:
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8     - |  image: ubuntu:bionic
+      8 + |  image: ubuntu:jammy
+
+`,
+		}, {
+			Name: "previous spec unchanged",
+			Args: []string{workloadName, flags.PreviousFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(corev1.LastAppliedConfigAnnotation, `{"apiVersion":"carto.run/v1alpha1","kind":"Workload","metadata":{"name":"my-workload","namespace":"default"},"spec":{}}`)
+					}),
+			},
+			ExpectOutput: `
+Previously applied spec of workload "my-workload":
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: my-workload
+  namespace: default
+spec: {}
+
+Spec is unchanged since it was applied
+`,
+		}, {
+			Name:         "previous spec not recorded",
+			Args:         []string{workloadName, flags.PreviousFlagName},
+			GivenObjects: []client.Object{parent},
+			ExpectOutput: `
+Workload "my-workload" has no previously applied spec, it is only recorded by kubectl apply
+`,
+		}, {
+			Name: "invalid previous spec",
+			Args: []string{workloadName, flags.PreviousFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(corev1.LastAppliedConfigAnnotation, `{`)
+					}),
+			},
+			ShouldError: true,
+			ExpectOutput: `
+Error: unable to read the previously applied spec: unexpected end of JSON input
+`,
+		}, {
+			Name: "export deliverable",
+			Args: []string{workloadName, flags.ExportDeliverableFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.Resources(
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("deliverable").
+								StampedRef(
+									&corev1.ObjectReference{
+										Kind:      cartov1alpha1.DeliverableKind,
+										Namespace: defaultNamespace,
+										Name:      workloadName,
+									}).
+								DieRelease(),
+						)
+					}),
+				deliverableBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(cartov1alpha1.WorkloadLabelName, workloadName)
+						d.CreationTimestamp(metav1.Date(2021, time.September, 10, 15, 00, 00, 00, time.UTC))
+					}).
+					SpecDie(func(d *diecartov1alpha1.DeliverableSpecDie) {
+						d.Source(&cartov1alpha1.Source{Image: "registry.example.com/my-workload-bundle:latest"})
+					}).
+					ConditionsHealthyReadyTrueDie(),
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Deliverable
+metadata:
+  labels:
+    carto.run/workload-name: my-workload
+  name: my-workload
+  namespace: default
+spec:
+  source:
+    image: registry.example.com/my-workload-bundle:latest
+`,
+		}, {
+			Name: "export deliverable stamped without a namespace",
+			Args: []string{workloadName, flags.ExportDeliverableFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.Resources(
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("deliverable").
+								StampedRef(
+									&corev1.ObjectReference{
+										Kind: cartov1alpha1.DeliverableKind,
+										Name: workloadName,
+									}).
+								DieRelease(),
+						)
+					}),
+				deliverableBlank.
+					SpecDie(func(d *diecartov1alpha1.DeliverableSpecDie) {
+						d.Source(&cartov1alpha1.Source{Image: "registry.example.com/my-workload-bundle:latest"})
+					}),
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Deliverable
+metadata:
+  name: my-workload
+  namespace: default
+spec:
+  source:
+    image: registry.example.com/my-workload-bundle:latest
+`,
+		}, {
+			Name: "export deliverable from config map",
+			Args: []string{workloadName, flags.ExportDeliverableFlagName, flags.OutputFlagName, printer.OutputFormatJson},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.Resources(
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("config-writer").
+								StampedRef(
+									&corev1.ObjectReference{
+										Kind:      "ConfigMap",
+										Namespace: defaultNamespace,
+										Name:      workloadName + "-deliverable",
+									}).
+								DieRelease(),
+						)
+					}),
+				diecorev1.ConfigMapBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(workloadName + "-deliverable")
+						d.Namespace(defaultNamespace)
+					}).
+					AddData(cartov1alpha1.DeliverableConfigMapKey, `
+apiVersion: carto.run/v1alpha1
+kind: Deliverable
+metadata:
+  name: my-workload
+spec:
+  source:
+    image: registry.example.com/my-workload-bundle:latest
+`),
+			},
+			ExpectOutput: `
+{
+	"apiVersion": "carto.run/v1alpha1",
+	"kind": "Deliverable",
+	"metadata": {
+		"name": "my-workload",
+		"namespace": "default"
+	},
+	"spec": {
+		"source": {
+			"image": "registry.example.com/my-workload-bundle:latest"
+		}
+	}
+}
+`,
+		}, {
+			Name: "export deliverable to context",
+			Args: []string{workloadName, flags.ExportDeliverableFlagName, flags.ToContextFlagName, "run-cluster"},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.Resources(
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("config-writer").
+								StampedRef(
+									&corev1.ObjectReference{
+										Kind:      "ConfigMap",
+										Namespace: defaultNamespace,
+										Name:      workloadName + "-deliverable",
+									}).
+								DieRelease(),
+						)
+					}),
+				diecorev1.ConfigMapBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(workloadName + "-deliverable")
+						d.Namespace(defaultNamespace)
+					}).
+					AddData(cartov1alpha1.DeliverableConfigMapKey, `
+apiVersion: carto.run/v1alpha1
+kind: Deliverable
+metadata:
+  name: my-workload
+  labels:
+    carto.run/workload-name: my-workload
+spec:
+  source:
+    image: registry.example.com/my-workload-bundle:latest
+`),
+			},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				// the run cluster is the same fake cluster
+				config.ContextClients = map[string]cli.Client{"run-cluster": config.Client}
+				return ctx, nil
+			},
+			// the carto.run labels of the build cluster are not applied to the run cluster
+			ExpectCreates: []client.Object{
+				deliverableBlank.
+					SpecDie(func(d *diecartov1alpha1.DeliverableSpecDie) {
+						d.Source(&cartov1alpha1.Source{Image: "registry.example.com/my-workload-bundle:latest"})
+					}),
+			},
+			ExpectOutput: `
+Created deliverable "my-workload" in context "run-cluster"
+`,
+		}, {
+			Name: "export deliverable to context keeps its labels and annotations",
+			Args: []string{workloadName, flags.ExportDeliverableFlagName, flags.ToContextFlagName, "run-cluster"},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.Resources(
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("config-writer").
+								StampedRef(
+									&corev1.ObjectReference{
+										Kind:      "ConfigMap",
+										Namespace: defaultNamespace,
+										Name:      workloadName + "-deliverable",
+									}).
+								DieRelease(),
+						)
+					}),
+				diecorev1.ConfigMapBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(workloadName + "-deliverable")
+						d.Namespace(defaultNamespace)
+					}).
+					AddData(cartov1alpha1.DeliverableConfigMapKey, `
 apiVersion: carto.run/v1alpha1
-kind: Workload
+kind: Deliverable
 metadata:
-  labels:
-    app.kubernetes.io/part-of: my-workload
   name: my-workload
-  namespace: default
-spec: {}
-`,
-		}, {
-			Name: "get workload exported data in json format",
-			Args: []string{workloadName, flags.ExportFlagName, flags.OutputFlagName, printer.OutputFormatJson},
-			GivenObjects: []client.Object{
-				parent.
+  labels:
+    carto.run/workload-name: my-workload
+  annotations:
+    team: payments
+spec:
+  source:
+    image: registry.example.com/my-workload-bundle:v2
+`),
+				// the deliverable in the run cluster has labels and annotations of its own
+				deliverableBlank.
 					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
-						d.AddLabel(apis.AppPartOfLabelName, workloadName)
-						d.CreationTimestamp(metav1.Date(2021, time.September, 10, 15, 00, 00, 00, time.UTC))
+						d.AddLabel(cartov1alpha1.WorkloadLabelName, workloadName)
+						d.AddLabel("environment", "production")
+						d.AddAnnotation("team", "checkout")
+						d.AddAnnotation("owner", "ops")
 					}).
-					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
-						d.ConditionsDie(
-							diecartov1alpha1.WorkloadConditionReadyBlank.
-								Status(metav1.ConditionUnknown).
-								Reason("Workload Reason").
-								Message("a hopefully informative message about what went wrong"),
-						)
+					SpecDie(func(d *diecartov1alpha1.DeliverableSpecDie) {
+						d.Source(&cartov1alpha1.Source{Image: "registry.example.com/my-workload-bundle:v1"})
+					}),
+			},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				// the run cluster is the same fake cluster
+				config.ContextClients = map[string]cli.Client{"run-cluster": config.Client}
+				return ctx, nil
+			},
+			ExpectUpdates: []client.Object{
+				deliverableBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(cartov1alpha1.WorkloadLabelName, workloadName)
+						d.AddLabel("environment", "production")
+						d.AddAnnotation("team", "payments")
+						d.AddAnnotation("owner", "ops")
+					}).
+					SpecDie(func(d *diecartov1alpha1.DeliverableSpecDie) {
+						d.Source(&cartov1alpha1.Source{Image: "registry.example.com/my-workload-bundle:v2"})
 					}),
 			},
 			ExpectOutput: `
-{
-	"apiVersion": "carto.run/v1alpha1",
-	"kind": "Workload",
-	"metadata": {
-		"labels": {
-			"app.kubernetes.io/part-of": "my-workload"
-		},
-		"name": "my-workload",
-		"namespace": "default"
-	},
-	"spec": {}
-}
+Updated deliverable "my-workload" in context "run-cluster"
+`,
+		}, {
+			Name:         "export deliverable not found",
+			Args:         []string{workloadName, flags.ExportDeliverableFlagName},
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+			ExpectOutput: `
+Failed to export deliverable: deliverable for workload "my-workload" not found
 `,
 		}, {
 			Name: "get workload output data in yaml format",
@@ -1576,6 +2939,137 @@ status:
 		"supplyChainRef": {}
 	}
 }
+`,
+		}, {
+			Name: "get workload output data in json format with summary",
+			Args: []string{workloadName, flags.OutputFlagName, "json", flags.IncludeSummaryFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue).
+								Reason("Ready"),
+						)
+						d.SupplyChainRef(cartov1alpha1.ObjectReference{
+							Kind: "ClusterSupplyChain",
+							Name: "source-to-url",
+						})
+					}),
+				pod1Die.
+					StatusDie(func(d *diecorev1.PodStatusDie) {
+						d.Phase(corev1.PodRunning)
+						d.Conditions(corev1.PodCondition{
+							Type:   corev1.PodReady,
+							Status: corev1.ConditionTrue,
+						})
+					}),
+				pod2Die.
+					StatusDie(func(d *diecorev1.PodStatusDie) {
+						d.Phase(corev1.PodPending)
+					}),
+				ksvcDieWithURL,
+				ksvcDieWithNoURL,
+			},
+			ExpectOutput: `
+{
+	"apiVersion": "carto.run/v1alpha1",
+	"kind": "Workload",
+	"metadata": {
+		"name": "my-workload",
+		"namespace": "default",
+		"resourceVersion": "999",
+		"creationTimestamp": "1970-01-01T00:00:01Z"
+	},
+	"spec": {},
+	"status": {
+		"conditions": [
+			{
+				"type": "Ready",
+				"status": "True",
+				"lastTransitionTime": null,
+				"reason": "Ready",
+				"message": ""
+			}
+		],
+		"supplyChainRef": {
+			"kind": "ClusterSupplyChain",
+			"name": "source-to-url"
+		}
+	},
+	"summary": {
+		"readyCondition": {
+			"type": "Ready",
+			"status": "True",
+			"lastTransitionTime": null,
+			"reason": "Ready",
+			"message": ""
+		},
+		"supplyChain": "source-to-url",
+		"pods": {
+			"total": 2,
+			"ready": 1,
+			"pending": 1,
+			"running": 1,
+			"succeeded": 0,
+			"failed": 0,
+			"unknown": 0
+		},
+		"knativeServices": [
+			{
+				"name": "ksvc1",
+				"url": "https://example.com",
+				"ready": true
+			},
+			{
+				"name": "ksvc2",
+				"ready": false
+			}
+		]
+	}
+}
+`,
+		}, {
+			Name: "get workload output data in yaml format with summary and nothing running",
+			Args: []string{workloadName, flags.OutputFlagName, "yaml", flags.IncludeSummaryFlagName},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  creationTimestamp: "1970-01-01T00:00:01Z"
+  name: my-workload
+  namespace: default
+  resourceVersion: "999"
+spec: {}
+status:
+  supplyChainRef: {}
+summary:
+  knativeServices: []
+  pods:
+    failed: 0
+    pending: 0
+    ready: 0
+    running: 0
+    succeeded: 0
+    total: 0
+    unknown: 0
+`,
+		}, {
+			Name: "get workload with summary, failing to list the pods",
+			Args: []string{workloadName, flags.OutputFlagName, "json", flags.IncludeSummaryFlagName},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("list", "PodList"),
+			},
+			ShouldError: true,
+			ExpectOutput: `
+Failed to summarize workload: inducing failure for list PodList
 `,
 		}, {
 			Name: "show healthy rule condition issue from workload and deliverable",
@@ -1877,6 +3371,76 @@ To see logs: "tanzu apps workload tail my-workload"
 
 To see logs: "tanzu apps workload tail my-workload"
 
+`,
+		}, {
+			Name: "report sections that could not be fetched",
+			Args: []string{workloadName},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue).
+								Reason(cartov1alpha1.ConditionReady),
+						)
+						d.Resources(
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("source-provider").
+								StampedRef(
+									&corev1.ObjectReference{
+										Kind:      "ImageRepository",
+										Namespace: defaultNamespace,
+										Name:      workloadName,
+									}).
+								ConditionsResourceHealthyReadyTrueDie().
+								DieRelease(),
+							diecartov1alpha1.RealizedResourceBlank.
+								Name("deliverable").
+								StampedRef(
+									&corev1.ObjectReference{
+										Kind:      cartov1alpha1.DeliverableKind,
+										Namespace: defaultNamespace,
+										Name:      workloadName,
+									}).
+								ConditionsResourceHealthyReadyTrueDie().
+								DieRelease(),
+						)
+					}),
+				deliverableBlank,
+			},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("get", "Deliverable"),
+				clitesting.InduceFailure("list", "ServiceList"),
+			},
+			BuilderObjects: []client.Object{pod1Die},
+			ExpectOutput: `
+📡 Overview
+   name:   my-workload
+   type:   <empty>
+
+📦 Supply Chain
+   name:   <none>
+
+   RESOURCE          READY   HEALTHY   TIME        OUTPUT
+   source-provider   True    True      <unknown>   ImageRepository/my-workload
+
+🚚 Delivery
+
+   Delivery resources not found.
+
+💬 Messages
+   No messages found.
+
+🛶 Pods
+   NAME   READY   STATUS   RESTARTS   AGE
+   pod1   0/0              0          <unknown>
+
+To see logs: "tanzu apps workload tail my-workload"
+
+Warning: some sections could not be fetched, the report may be incomplete:
+  delivery: inducing failure for get Deliverable
+  knative services: inducing failure for list ServiceList
+
 `,
 		}, {
 			Name: "show delivery section with no deliverable information",
@@ -1937,6 +3501,113 @@ To see logs: "tanzu apps workload tail my-workload"
 
 `,
 		},
+		{
+			Name: "app in all namespaces",
+			Args: []string{flags.AppFlagName, "my-app", flags.AllNamespacesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.AppPartOfLabelName, "my-app")
+						d.AddLabel(apis.WorkloadTypeLabelName, "web")
+						d.CreationTimestamp(objTimeStamp)
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.SupplyChainRef(cartov1alpha1.ObjectReference{Name: "source-to-url"})
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionTrue),
+						)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("my-api")
+						d.Namespace("prod")
+						d.AddLabel(apis.AppPartOfLabelName, "my-app")
+						d.AddLabel(apis.WorkloadTypeLabelName, "server")
+						d.CreationTimestamp(objTimeStamp)
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionFalse).
+								Reason("OopsieDoodle").
+								Message("a hopefully informative message about what went wrong"),
+						)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("other-workload")
+						d.Namespace(defaultNamespace)
+						d.AddLabel(apis.AppPartOfLabelName, "other-app")
+					}),
+			},
+			ExpectOutput: `
+📡 Overview
+   app:          my-app
+   workloads:    2 (1 ready)
+   namespaces:   default, prod
+
+   NAMESPACE   NAME          TYPE     SUPPLY CHAIN    READY          AGE
+   default     my-workload   web      source-to-url   Ready          2y
+   prod        my-api        server   <empty>         OopsieDoodle   2y
+
+💬 Messages
+   prod/my-api [OopsieDoodle]:   a hopefully informative message about what went wrong
+
+To see a workload: "tanzu apps workload get <name> --namespace <namespace>"
+
+`,
+		},
+		{
+			Name: "app in namespace",
+			Args: []string{flags.AppFlagName, "my-app"},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.AppPartOfLabelName, "my-app")
+						d.CreationTimestamp(objTimeStamp)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("my-api")
+						d.Namespace("prod")
+						d.AddLabel(apis.AppPartOfLabelName, "my-app")
+					}),
+			},
+			ExpectOutput: `
+📡 Overview
+   app:          my-app
+   workloads:    1 (0 ready)
+   namespaces:   default
+
+   NAMESPACE   NAME          TYPE      SUPPLY CHAIN   READY       AGE
+   default     my-workload   <empty>   <empty>        <unknown>   2y
+
+💬 Messages
+   No messages found.
+
+To see a workload: "tanzu apps workload get <name> --namespace <namespace>"
+
+`,
+		},
+		{
+			Name: "app not found",
+			Args: []string{flags.AppFlagName, "my-app", flags.AllNamespacesFlagName},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ShouldError: true,
+			ExpectOutput: `
+No workloads found for app "my-app"
+`,
+		},
+		{
+			Name: "app list error",
+			Args: []string{flags.AppFlagName, "my-app"},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("list", "WorkloadList"),
+			},
+			ShouldError: true,
+		},
 	}
 
 	table.Run(t, scheme, commands.NewWorkloadGetCommand)
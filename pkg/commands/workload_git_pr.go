@@ -0,0 +1,326 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+const (
+	// GitHubTokenEnv holds the token used to read the pull requests of private GitHub repos
+	GitHubTokenEnv = "GITHUB_TOKEN"
+	// GitLabTokenEnv holds the token used to read the merge requests of private GitLab repos
+	GitLabTokenEnv = "GITLAB_TOKEN"
+	// GitTokenHostsConfigKey is the plugin config key listing the hosts, besides github.com and
+	// gitlab.com, the token of GitHubTokenEnv or GitLabTokenEnv is sent to
+	GitTokenHostsConfigKey = "git-token-hosts"
+)
+
+// git providers whose API is supported
+const (
+	gitProviderGitHub = "github"
+	gitProviderGitLab = "gitlab"
+)
+
+// gitProviderTokenEnv is the environment variable holding the token of each provider
+var gitProviderTokenEnv = map[string]string{
+	gitProviderGitHub: GitHubTokenEnv,
+	gitProviderGitLab: GitLabTokenEnv,
+}
+
+// gitProviderHTTPClient calls the API of the git provider to resolve the pull request of --git-pr
+// and to register webhooks
+var gitProviderHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+type gitProviderTransportStashKey struct{}
+
+// StashGitProviderTransport sets the transport used to call the API of the git provider
+func StashGitProviderTransport(ctx context.Context, transport http.RoundTripper) context.Context {
+	return context.WithValue(ctx, gitProviderTransportStashKey{}, transport)
+}
+
+func retrieveGitProviderTransport(ctx context.Context) http.RoundTripper {
+	transport, ok := ctx.Value(gitProviderTransportStashKey{}).(http.RoundTripper)
+	if !ok {
+		return nil
+	}
+	return transport
+}
+
+// pullRequest is the head of a pull request, the branch to checkout and the repo it lives in,
+// which differs from the repo of the workload when the pull request comes from a fork
+type pullRequest struct {
+	Branch string
+	Repo   string
+	State  string
+}
+
+func (opts *WorkloadOptions) validateGitPR() validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.GitPR < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(opts.GitPR, flags.GitPRFlagName))
+	}
+	if opts.GitPR != 0 && (opts.GitBranch != "" || opts.GitCommit != "" || opts.GitTag != "") {
+		errs = errs.Also(validation.ErrMultipleOneOf(flags.GitPRFlagName, flags.GitBranchFlagName, flags.GitCommitFlagName, flags.GitTagFlagName))
+	}
+	if opts.GitPRLabel && opts.GitPR == 0 {
+		errs = errs.Also(validation.ErrMissingField(flags.GitPRFlagName))
+	}
+
+	return errs
+}
+
+// ResolveGitPR checks out the head branch of the pull request of --git-pr, resolved with the API of
+// the provider of the git repo of the workload. A pull request from a fork checks out the fork.
+func (opts *WorkloadOptions) ResolveGitPR(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) error {
+	if opts.GitPR == 0 {
+		return nil
+	}
+	if workload.Spec.Source == nil || workload.Spec.Source.Git == nil || workload.Spec.Source.Git.URL == "" {
+		return validation.ErrMissingField(flags.GitRepoFlagName).ToAggregate()
+	}
+
+	repo := workload.Spec.Source.Git.URL
+	pr, err := resolvePullRequest(ctx, c, repo, opts.GitPR)
+	if err != nil {
+		c.Eprintf("%s unable to resolve pull request %d of %q: %s\n", printer.Serrorf("Error:"), opts.GitPR, repo, err)
+		return cli.SilenceError(err)
+	}
+	if pr.State != "open" && pr.State != "opened" {
+		c.Eprintf("%s pull request %d of %q is %s\n", printer.Swarnf("Warning:"), opts.GitPR, repo, pr.State)
+	}
+	if pr.Repo == "" {
+		pr.Repo = repo
+	}
+	c.Infof("Resolved pull request %d to branch %q of %q\n", opts.GitPR, pr.Branch, pr.Repo)
+
+	workload.Spec.MergeGit(cartov1alpha1.GitSource{
+		URL: pr.Repo,
+		Ref: cartov1alpha1.GitRef{Branch: pr.Branch},
+	})
+	if opts.GitPRLabel {
+		workload.MergeLabels(apis.PullRequestLabelName, strconv.Itoa(opts.GitPR))
+	}
+	return nil
+}
+
+func resolvePullRequest(ctx context.Context, c *cli.Config, repo string, number int) (*pullRequest, error) {
+	provider, api, path, err := gitProviderAPI(repo)
+	if err != nil {
+		return nil, err
+	}
+	header := gitProviderHeader(provider, gitProviderToken(c, provider, repo, ""))
+	if provider == gitProviderGitHub {
+		return resolveGitHubPullRequest(ctx, api, path, header, number)
+	}
+	return resolveGitLabMergeRequest(ctx, api, path, header, number)
+}
+
+// gitProviderAPI returns the provider of a git repo, the url of its API and the path of the repo
+func gitProviderAPI(repo string) (string, string, string, error) {
+	host, path, err := parseGitRepoURL(repo)
+	if err != nil {
+		return "", "", "", err
+	}
+	switch {
+	case host == "github.com":
+		return gitProviderGitHub, "https://api.github.com", path, nil
+	case strings.Contains(host, "github"):
+		// GitHub Enterprise serves its API under the host of the repos
+		return gitProviderGitHub, fmt.Sprintf("https://%s/api/v3", host), path, nil
+	case strings.Contains(host, "gitlab"):
+		return gitProviderGitLab, fmt.Sprintf("https://%s/api/v4", host), path, nil
+	}
+	return "", "", "", fmt.Errorf("git provider %q is not supported, only GitHub and GitLab are", host)
+}
+
+// gitProviderToken returns the token to call the API of the provider of the repo with, the given
+// token or the one of the environment variable of the provider when empty. The environment variable
+// is only sent to github.com, gitlab.com and the hosts of GitTokenHostsConfigKey, a repo hosted
+// anywhere else, even with "github" or "gitlab" in its host, requires the token to be given.
+func gitProviderToken(c *cli.Config, provider, repo, token string) string {
+	if token != "" {
+		return token
+	}
+	host, _, err := parseGitRepoURL(repo)
+	if err != nil || !gitTokenHostAllowed(c, host) {
+		return ""
+	}
+	return os.Getenv(gitProviderTokenEnv[provider])
+}
+
+func gitTokenHostAllowed(c *cli.Config, host string) bool {
+	allowed := []string{"github.com", "gitlab.com"}
+	if c.Viper != nil {
+		allowed = append(allowed, c.Viper.GetStringSlice(GitTokenHostsConfigKey)...)
+	}
+	for _, h := range allowed {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// gitProviderHeader authenticates the calls to the API of the provider with the token, when not empty
+func gitProviderHeader(provider, token string) http.Header {
+	header := http.Header{}
+	if provider == gitProviderGitHub {
+		header.Set("Accept", "application/vnd.github+json")
+		if token != "" {
+			header.Set("Authorization", "Bearer "+token)
+		}
+		return header
+	}
+	if token != "" {
+		header.Set("PRIVATE-TOKEN", token)
+	}
+	return header
+}
+
+// parseGitRepoURL returns the host and the path, without the .git suffix, of a git repo url in the
+// https, ssh or scp like form
+func parseGitRepoURL(repo string) (string, string, error) {
+	raw := repo
+	if !strings.Contains(raw, "://") {
+		// scp like form, git@github.com:owner/repo.git
+		if i := strings.Index(raw, ":"); i > 0 {
+			raw = "ssh://" + raw[:i] + "/" + raw[i+1:]
+		}
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", err
+	}
+	path := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if u.Hostname() == "" || !strings.Contains(path, "/") {
+		return "", "", fmt.Errorf("unable to find the owner and name of the repo in %q", repo)
+	}
+	return u.Hostname(), path, nil
+}
+
+func resolveGitHubPullRequest(ctx context.Context, api, path string, header http.Header, number int) (*pullRequest, error) {
+	type repo struct {
+		FullName string `json:"full_name"`
+		CloneURL string `json:"clone_url"`
+	}
+	type ref struct {
+		Ref  string `json:"ref"`
+		Repo *repo  `json:"repo"`
+	}
+	res := struct {
+		State string `json:"state"`
+		Head  ref    `json:"head"`
+		Base  ref    `json:"base"`
+	}{}
+
+	if err := getGitProviderAPI(ctx, fmt.Sprintf("%s/repos/%s/pulls/%d", api, path, number), header, &res); err != nil {
+		return nil, err
+	}
+	if res.Head.Repo == nil {
+		return nil, fmt.Errorf("the repo of branch %q was deleted", res.Head.Ref)
+	}
+	pr := &pullRequest{Branch: res.Head.Ref, State: res.State}
+	if res.Base.Repo == nil || res.Head.Repo.FullName != res.Base.Repo.FullName {
+		pr.Repo = res.Head.Repo.CloneURL
+	}
+	return pr, nil
+}
+
+func resolveGitLabMergeRequest(ctx context.Context, api, path string, header http.Header, number int) (*pullRequest, error) {
+	res := struct {
+		State           string `json:"state"`
+		SourceBranch    string `json:"source_branch"`
+		SourceProjectID int    `json:"source_project_id"`
+		TargetProjectID int    `json:"target_project_id"`
+	}{}
+
+	if err := getGitProviderAPI(ctx, fmt.Sprintf("%s/projects/%s/merge_requests/%d", api, url.PathEscape(path), number), header, &res); err != nil {
+		return nil, err
+	}
+	pr := &pullRequest{Branch: res.SourceBranch, State: res.State}
+	if res.SourceProjectID != res.TargetProjectID {
+		// the merge request comes from a fork
+		fork := struct {
+			HTTPURLToRepo string `json:"http_url_to_repo"`
+		}{}
+		if err := getGitProviderAPI(ctx, fmt.Sprintf("%s/projects/%d", api, res.SourceProjectID), header, &fork); err != nil {
+			return nil, err
+		}
+		pr.Repo = fork.HTTPURLToRepo
+	}
+	return pr, nil
+}
+
+func getGitProviderAPI(ctx context.Context, url string, header http.Header, into interface{}) error {
+	return callGitProviderAPI(ctx, http.MethodGet, url, header, nil, http.StatusOK, into)
+}
+
+// callGitProviderAPI sends the body, when not nil, as json and decodes the response, when into is
+// not nil, once the provider answered with the expected status
+func callGitProviderAPI(ctx context.Context, method, url string, header http.Header, body interface{}, expected int, into interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header = header.Clone()
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	client := gitProviderHTTPClient
+	if transport := retrieveGitProviderTransport(ctx); transport != nil {
+		client = &http.Client{Timeout: gitProviderHTTPClient.Timeout, Transport: transport}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != expected {
+		return fmt.Errorf("%s %s returned %d, expected %d", method, url, resp.StatusCode, expected)
+	}
+	if into == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(into)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	regv1 "github.com/google/go-containerregistry/pkg/v1"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/source"
+)
+
+// the OCI image labels read by --from-image-scan, see
+// https://github.com/opencontainers/image-spec/blob/main/annotations.md
+const (
+	ociImageTitleLabel    = "org.opencontainers.image.title"
+	ociImageSourceLabel   = "org.opencontainers.image.source"
+	ociImageRevisionLabel = "org.opencontainers.image.revision"
+)
+
+// imageScanPortsParam is the workload param prefilled with the ports exposed by the image
+const imageScanPortsParam = "ports"
+
+// ImageScanDefaults returns the labels and annotations of a workload described by the config of its
+// image. The title of the image is the part-of label when it is a valid label value, and the source
+// and revision of the image are kept as annotations of the same name.
+func ImageScanDefaults(config *regv1.ConfigFile) WorkloadDefaults {
+	defaults := WorkloadDefaults{}
+	labels := config.Config.Labels
+	if title := labels[ociImageTitleLabel]; title != "" && len(k8svalidation.IsValidLabelValue(title)) == 0 {
+		defaults.Labels = append(defaults.Labels, fmt.Sprintf("%s=%s", apis.AppPartOfLabelName, title))
+	}
+	for _, key := range []string{ociImageSourceLabel, ociImageRevisionLabel} {
+		if value := labels[key]; value != "" {
+			defaults.Annotations = append(defaults.Annotations, fmt.Sprintf("%s=%s", key, value))
+		}
+	}
+	return defaults
+}
+
+// ImageScanPort is an exposed port of an image, in the format of the ports param
+type ImageScanPort struct {
+	ContainerPort int    `json:"containerPort"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+// ImageScanPorts returns the ports exposed by the config of an image, sorted by number, along with their
+// "port/protocol" form. Ports that are not numbers are skipped. The protocol is only set when not TCP.
+func ImageScanPorts(config *regv1.ConfigFile) ([]ImageScanPort, []string) {
+	type exposed struct {
+		port     int
+		protocol string
+	}
+	ports := []exposed{}
+	for key := range config.Config.ExposedPorts {
+		parts := strings.SplitN(key, "/", 2)
+		port, err := strconv.Atoi(parts[0])
+		if err != nil || port <= 0 {
+			continue
+		}
+		protocol := "tcp"
+		if len(parts) == 2 && parts[1] != "" {
+			protocol = strings.ToLower(parts[1])
+		}
+		ports = append(ports, exposed{port: port, protocol: protocol})
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].port != ports[j].port {
+			return ports[i].port < ports[j].port
+		}
+		return ports[i].protocol < ports[j].protocol
+	})
+
+	params := make([]ImageScanPort, 0, len(ports))
+	names := make([]string, 0, len(ports))
+	for _, p := range ports {
+		param := ImageScanPort{ContainerPort: p.port}
+		if p.protocol != "tcp" {
+			param.Protocol = strings.ToUpper(p.protocol)
+		}
+		params = append(params, param)
+		names = append(names, fmt.Sprintf("%d/%s", p.port, p.protocol))
+	}
+	return params, names
+}
+
+// applyImageScan prefills the workload from the config of its pre-built image when --from-image-scan is
+// set, for the labels, annotations and params not set by the flags or the file. The values of the image
+// win over the workload profile and the namespace defaults, which are more generic.
+func (opts *WorkloadApplyOptions) applyImageScan(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) (context.Context, error) {
+	if !opts.FromImageScan {
+		return ctx, nil
+	}
+	if workload.Spec.Image == "" {
+		return ctx, validation.ErrMissingField(flags.ImageFlagName).ToAggregate()
+	}
+
+	config, err := source.ImageConfig(ctx, opts.registryOpts(), workload.Spec.Image)
+	if err != nil {
+		c.Eprintf("%s unable to read the config of image %q: %s\n", printer.Serrorf("Error:"), workload.Spec.Image, err)
+		return ctx, cli.SilenceError(err)
+	}
+	origin := fmt.Sprintf("the config of image %q", workload.Spec.Image)
+
+	values := newWorkloadDefaultValues()
+	values.collect(ImageScanDefaults(config), origin)
+	ctx = values.merge(ctx, workload)
+
+	if ports, names := ImageScanPorts(config); len(ports) != 0 && !workload.Spec.HasParam(imageScanPortsParam) {
+		workload.Spec.MergeParams(imageScanPortsParam, ports)
+		ctx = cartov1alpha1.StashWorkloadNotice(ctx, fmt.Sprintf("param %q set to %q by %s.", imageScanPortsParam, strings.Join(names, ","), origin))
+	}
+	return ctx, nil
+}
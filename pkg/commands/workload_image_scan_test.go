@@ -0,0 +1,99 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	regv1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+)
+
+func TestImageScanDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		expected commands.WorkloadDefaults
+	}{{
+		name: "no labels",
+	}, {
+		name: "oci labels",
+		labels: map[string]string{
+			"org.opencontainers.image.title":    "my-app",
+			"org.opencontainers.image.source":   "https://example.com/repo.git",
+			"org.opencontainers.image.revision": "abcd1234",
+			"maintainer":                        "ignored",
+		},
+		expected: commands.WorkloadDefaults{
+			Labels:      []string{"app.kubernetes.io/part-of=my-app"},
+			Annotations: []string{"org.opencontainers.image.source=https://example.com/repo.git", "org.opencontainers.image.revision=abcd1234"},
+		},
+	}, {
+		name: "title not a label value",
+		labels: map[string]string{
+			"org.opencontainers.image.title": "My App",
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &regv1.ConfigFile{Config: regv1.Config{Labels: test.labels}}
+			actual := commands.ImageScanDefaults(config)
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Errorf("ImageScanDefaults() (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
+
+func TestImageScanPorts(t *testing.T) {
+	tests := []struct {
+		name          string
+		exposedPorts  map[string]struct{}
+		expected      []commands.ImageScanPort
+		expectedNames []string
+	}{{
+		name:          "no ports",
+		expected:      []commands.ImageScanPort{},
+		expectedNames: []string{},
+	}, {
+		name:         "ports",
+		exposedPorts: map[string]struct{}{"9090/tcp": {}, "8080": {}, "53/udp": {}, "53/tcp": {}, "http/tcp": {}},
+		expected: []commands.ImageScanPort{
+			{ContainerPort: 53},
+			{ContainerPort: 53, Protocol: "UDP"},
+			{ContainerPort: 8080},
+			{ContainerPort: 9090},
+		},
+		expectedNames: []string{"53/tcp", "53/udp", "8080/tcp", "9090/tcp"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &regv1.ConfigFile{Config: regv1.Config{ExposedPorts: test.exposedPorts}}
+			actual, actualNames := commands.ImageScanPorts(config)
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Errorf("ImageScanPorts() (-expected, +actual): %s", diff)
+			}
+			if diff := cmp.Diff(test.expectedNames, actualNames); diff != "" {
+				t.Errorf("ImageScanPorts() names (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
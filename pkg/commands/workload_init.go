@@ -0,0 +1,390 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/parsers"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+// defaultWorkloadInitFile is where workload init writes the workload without --file
+const defaultWorkloadInitFile = "workload.yaml"
+
+// WorkloadInitOptions generates a workload.yaml skeleton, from the flags and the answers to prompts
+// for the values the flags do not set
+type WorkloadInitOptions struct {
+	Namespace string
+	Name      string
+	From      string
+	FilePath  string
+
+	Type        string
+	GitRepo     string
+	GitBranch   string
+	Env         []string
+	LimitCPU    string
+	LimitMemory string
+
+	Yes bool
+}
+
+var (
+	_ validation.Validatable = (*WorkloadInitOptions)(nil)
+	_ cli.Executable         = (*WorkloadInitOptions)(nil)
+)
+
+func (opts *WorkloadInitOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Name != "" {
+		errs = errs.Also(validation.K8sName(opts.Name, cli.NameArgumentName))
+	} else if opts.From == "" && opts.Yes {
+		// without prompts, the name is only known from the args or the existing workload
+		errs = errs.Also(validation.ErrMissingField(cli.NameArgumentName))
+	}
+
+	if opts.From != "" {
+		errs = errs.Also(validation.K8sName(opts.From, flags.FromFlagName))
+		if opts.Namespace == "" {
+			errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+		}
+	}
+
+	if opts.FilePath == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.FilePathFlagName))
+	}
+
+	errs = errs.Also(validation.DeletableEnvVars(opts.Env, flags.EnvFlagName))
+	if opts.LimitCPU != "" {
+		errs = errs.Also(validation.Quantity(opts.LimitCPU, flags.LimitCPUFlagName))
+	}
+	if opts.LimitMemory != "" {
+		errs = errs.Also(validation.Quantity(opts.LimitMemory, flags.LimitMemoryFlagName))
+	}
+
+	return errs
+}
+
+func (opts *WorkloadInitOptions) Exec(ctx context.Context, c *cli.Config) error {
+	workload := &cartov1alpha1.Workload{}
+	if opts.From != "" {
+		existing := &cartov1alpha1.Workload{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.From}, existing); err != nil {
+			if !apierrs.IsNotFound(err) {
+				return err
+			}
+			c.Errorf("Workload %q not found\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.From))
+			return cli.SilenceError(err)
+		}
+		// only what was applied is kept, the skeleton is not bound to the namespace of the existing workload
+		workload.Name = existing.Name
+		workload.Labels = existing.Labels
+		workload.Spec = existing.Spec
+	}
+	if opts.Name != "" {
+		workload.Name = opts.Name
+	}
+	opts.applyFlags(workload)
+
+	if !opts.Yes {
+		if err := opts.prompt(ctx, c, workload); err != nil {
+			c.Infof("Skipping workload init: %s\n", err)
+			return cli.SilenceError(err)
+		}
+	}
+
+	export, err := printer.ExportResource(workload, printer.OutputFormat(printer.OutputFormatYaml), c.Scheme)
+	if err != nil {
+		return err
+	}
+	if opts.FilePath == "-" {
+		c.Printf("%s\n", export)
+		return nil
+	}
+
+	if _, err := os.Stat(opts.FilePath); err == nil && !opts.Yes {
+		okToOverwrite := false
+		err := survey.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("Overwrite %q?", opts.FilePath),
+		}, &okToOverwrite, printer.WithSurveyStdio(c.Stdin, c.Stdout, c.Stderr))
+		if err != nil || !okToOverwrite {
+			c.Infof("Skipping %q\n", opts.FilePath)
+			return nil
+		}
+	}
+	if err := os.WriteFile(opts.FilePath, []byte(export+"\n"), 0644); err != nil {
+		return err
+	}
+	c.Successf("Wrote workload %q to %q\n", workload.Name, opts.FilePath)
+	c.Infof("To create the workload run: tanzu apps workload apply %s %s\n", flags.FilePathFlagName, opts.FilePath)
+	return nil
+}
+
+// applyFlags sets the values of the flags on the workload
+func (opts *WorkloadInitOptions) applyFlags(workload *cartov1alpha1.Workload) {
+	if opts.Type != "" {
+		workload.MergeLabels(apis.WorkloadTypeLabelName, opts.Type)
+	}
+	if opts.GitRepo != "" || opts.GitBranch != "" {
+		git := cartov1alpha1.GitSource{}
+		if workload.Spec.Source != nil && workload.Spec.Source.Git != nil {
+			git = *workload.Spec.Source.Git
+		}
+		if opts.GitRepo != "" {
+			git.URL = opts.GitRepo
+		}
+		if opts.GitBranch != "" {
+			git.Ref = cartov1alpha1.GitRef{Branch: opts.GitBranch}
+		}
+		workload.Spec.MergeGit(git)
+	}
+	for _, ev := range opts.Env {
+		env, delete := parsers.DeletableEnvVar(ev)
+		if delete {
+			workload.Spec.RemoveEnv(env.Name)
+		} else {
+			workload.Spec.MergeEnv(env)
+		}
+	}
+	if opts.LimitCPU != "" {
+		// parse errors are handled by the opt validation
+		workload.Spec.MergeResources(&corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(opts.LimitCPU)},
+		})
+	}
+	if opts.LimitMemory != "" {
+		// parse errors are handled by the opt validation
+		workload.Spec.MergeResources(&corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(opts.LimitMemory)},
+		})
+	}
+}
+
+// workloadInitAnswers holds the answers to the prompts of workload init
+type workloadInitAnswers struct {
+	Name        string
+	Type        string
+	GitRepo     string
+	GitBranch   string
+	Env         string
+	LimitCPU    string
+	LimitMemory string
+}
+
+// prompt asks for the values not set with a flag, defaulting to the values of the workload
+func (opts *WorkloadInitOptions) prompt(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) error {
+	changed := cli.CommandFromContext(ctx).Flags().Changed
+	answers := workloadInitAnswers{}
+	questions := []*survey.Question{}
+	ask := func(name, message, value string, validate func(string) validation.FieldErrors) {
+		questions = append(questions, &survey.Question{
+			Name:   name,
+			Prompt: &survey.Input{Message: message, Default: value},
+			Validate: func(ans interface{}) error {
+				if s, _ := ans.(string); s != "" {
+					return validate(s).ToAggregate()
+				}
+				return nil
+			},
+		})
+	}
+	none := func(string) validation.FieldErrors { return nil }
+
+	if workload.Name == "" {
+		questions = append(questions, &survey.Question{
+			Name:   "Name",
+			Prompt: &survey.Input{Message: "Workload name:"},
+			Validate: func(ans interface{}) error {
+				s, _ := ans.(string)
+				return validation.K8sName(s, cli.NameArgumentName).ToAggregate()
+			},
+		})
+	}
+	if !changed(cli.StripDash(flags.TypeFlagName)) {
+		value := workload.Labels[apis.WorkloadTypeLabelName]
+		if value == "" {
+			value = "web"
+		}
+		ask("Type", "Workload type:", value, none)
+	}
+	git := &cartov1alpha1.GitSource{Ref: cartov1alpha1.GitRef{Branch: "main"}}
+	if workload.Spec.Source != nil && workload.Spec.Source.Git != nil {
+		git = workload.Spec.Source.Git
+	}
+	if !changed(cli.StripDash(flags.GitRepoFlagName)) {
+		ask("GitRepo", "Git repository URL (empty for no git source):", git.URL, none)
+	}
+	if !changed(cli.StripDash(flags.GitBranchFlagName)) {
+		ask("GitBranch", "Git branch:", git.Ref.Branch, none)
+	}
+	if !changed(cli.StripDash(flags.EnvFlagName)) {
+		env := []string{}
+		for _, e := range workload.Spec.Env {
+			if e.ValueFrom == nil {
+				env = append(env, fmt.Sprintf("%s=%s", e.Name, e.Value))
+			}
+		}
+		ask("Env", "Environment variables, as comma separated key=value pairs:", strings.Join(env, ","), func(s string) validation.FieldErrors {
+			return validation.EnvVars(splitInitList(s), flags.EnvFlagName)
+		})
+	}
+	limits := corev1.ResourceList{}
+	if workload.Spec.Resources != nil && workload.Spec.Resources.Limits != nil {
+		limits = workload.Spec.Resources.Limits
+	}
+	if !changed(cli.StripDash(flags.LimitCPUFlagName)) {
+		value := ""
+		if q, ok := limits[corev1.ResourceCPU]; ok {
+			value = q.String()
+		}
+		ask("LimitCPU", "CPU limit (empty for no limit):", value, func(s string) validation.FieldErrors {
+			return validation.Quantity(s, flags.LimitCPUFlagName)
+		})
+	}
+	if !changed(cli.StripDash(flags.LimitMemoryFlagName)) {
+		value := ""
+		if q, ok := limits[corev1.ResourceMemory]; ok {
+			value = q.String()
+		}
+		ask("LimitMemory", "Memory limit (empty for no limit):", value, func(s string) validation.FieldErrors {
+			return validation.Quantity(s, flags.LimitMemoryFlagName)
+		})
+	}
+
+	if err := survey.Ask(questions, &answers, printer.WithSurveyStdio(c.Stdin, c.Stdout, c.Stderr)); err != nil {
+		return err
+	}
+
+	if answers.Name != "" {
+		workload.Name = answers.Name
+	}
+	if answers.Type != "" {
+		workload.MergeLabels(apis.WorkloadTypeLabelName, answers.Type)
+	}
+	if answers.GitRepo != "" {
+		workload.Spec.MergeGit(cartov1alpha1.GitSource{
+			URL: answers.GitRepo,
+			Ref: cartov1alpha1.GitRef{Branch: answers.GitBranch},
+		})
+	} else if !changed(cli.StripDash(flags.GitRepoFlagName)) && workload.Spec.Source != nil && workload.Spec.Source.Git != nil {
+		workload.Spec.ResetSource()
+	}
+	if !changed(cli.StripDash(flags.EnvFlagName)) {
+		env := []corev1.EnvVar{}
+		for _, e := range workload.Spec.Env {
+			// values from config maps and secrets are not prompted for
+			if e.ValueFrom != nil {
+				env = append(env, e)
+			}
+		}
+		for _, e := range splitInitList(answers.Env) {
+			env = append(env, parsers.EnvVar(e))
+		}
+		workload.Spec.Env = env
+	}
+	if !changed(cli.StripDash(flags.LimitCPUFlagName)) {
+		setInitLimit(workload, corev1.ResourceCPU, answers.LimitCPU)
+	}
+	if !changed(cli.StripDash(flags.LimitMemoryFlagName)) {
+		setInitLimit(workload, corev1.ResourceMemory, answers.LimitMemory)
+	}
+	return nil
+}
+
+// splitInitList splits the comma separated answer of a prompt, ignoring empty items
+func splitInitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setInitLimit sets the limit of the resource to the prompted quantity, or removes it when empty
+func setInitLimit(workload *cartov1alpha1.Workload, name corev1.ResourceName, value string) {
+	if value == "" {
+		if workload.Spec.Resources != nil {
+			delete(workload.Spec.Resources.Limits, name)
+		}
+		return
+	}
+	// parse errors are handled by the prompt validation
+	workload.Spec.MergeResources(&corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{name: resource.MustParse(value)},
+	})
+}
+
+func NewWorkloadInitCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	opts := &WorkloadInitOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Generate a workload.yaml skeleton",
+		Long: strings.TrimSpace(`
+Generate a workload.yaml skeleton, with its git source, type, resource limits and
+environment variables, to be applied with workload apply --file.
+
+The values not set with flags are prompted for. With ` + flags.FromFlagName + `, the skeleton
+starts from the spec and labels of an existing workload of the namespace. The
+skeleton is written to ` + defaultWorkloadInitFile + ` unless set otherwise with ` + flags.FilePathFlagName + `,
+"-" prints it instead.
+`),
+		Example:           examplesFor(c, "workload init"),
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		cli.OptionalNameArg(&opts.Name),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVar(&opts.From, cli.StripDash(flags.FromFlagName), "", "existing workload `name` to start the skeleton from")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.FromFlagName), completion.SuggestWorkloadNames(ctx, c))
+	cmd.Flags().StringVarP(&opts.FilePath, cli.StripDash(flags.FilePathFlagName), "f", defaultWorkloadInitFile, "`file path` the skeleton is written to, \"-\" prints it")
+	cmd.Flags().StringVarP(&opts.Type, cli.StripDash(flags.TypeFlagName), "t", "", "distinguish workload `type`")
+	cmd.Flags().StringVar(&opts.GitRepo, cli.StripDash(flags.GitRepoFlagName), "", "git `url` to remote source code")
+	cmd.Flags().StringVar(&opts.GitBranch, cli.StripDash(flags.GitBranchFlagName), "", "`branch` within the git repo to checkout")
+	cmd.Flags().StringArrayVar(&opts.Env, cli.StripDash(flags.EnvFlagName), []string{}, "environment variables represented as a `\"key=value\" pair` (\"key-\" to remove, flag can be used multiple times)")
+	cmd.Flags().StringVar(&opts.LimitCPU, cli.StripDash(flags.LimitCPUFlagName), "", "the maximum amount of cpu allowed, in CPU `cores` (500m = .5 cores)")
+	cmd.Flags().StringVar(&opts.LimitMemory, cli.StripDash(flags.LimitMemoryFlagName), "", "the maximum amount of memory allowed, in `bytes` (500Mi = 500MiB = 500 * 1024 * 1024)")
+	cmd.Flags().BoolVarP(&opts.Yes, cli.StripDash(flags.YesFlagName), "y", false, "accept all prompts, generating the skeleton from the flags only")
+
+	return cmd
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestWorkloadInitOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name: "prompted name",
+			Validatable: &commands.WorkloadInitOptions{
+				FilePath: "workload.yaml",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "missing name without prompts",
+			Validatable: &commands.WorkloadInitOptions{
+				FilePath: "workload.yaml",
+				Yes:      true,
+			},
+			ExpectFieldErrors: validation.ErrMissingField(cli.NameArgumentName),
+		},
+		{
+			Name: "from existing workload",
+			Validatable: &commands.WorkloadInitOptions{
+				Namespace: "default",
+				From:      "my-workload",
+				FilePath:  "-",
+				Yes:       true,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid values",
+			Validatable: &commands.WorkloadInitOptions{
+				Name:        "my-",
+				From:        "my-",
+				Env:         []string{"=value"},
+				LimitCPU:    "lots",
+				LimitMemory: "500Mi",
+			},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidValue("my-", cli.NameArgumentName),
+				validation.ErrInvalidValue("my-", flags.FromFlagName),
+				validation.ErrMissingField(flags.NamespaceFlagName),
+				validation.ErrMissingField(flags.FilePathFlagName),
+				validation.ErrInvalidArrayValue("=value", flags.EnvFlagName, 0),
+				validation.ErrInvalidQuantity("lots", flags.LimitCPUFlagName),
+			),
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestWorkloadInitCommand(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+	gitRepo := "https://github.com/sample-accelerators/spring-petclinic"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+
+	parent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+			d.AddLabel(apis.WorkloadTypeLabelName, "web")
+			d.AddAnnotation("note", "not copied")
+		}).
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Env(corev1.EnvVar{Name: "FOO", Value: "bar"})
+		}).
+		DieStamp(func(r *cartov1alpha1.Workload) {
+			r.Spec.MergeGit(cartov1alpha1.GitSource{URL: gitRepo, Ref: cartov1alpha1.GitRef{Branch: "main"}})
+		})
+
+	dir := t.TempDir()
+	skeleton := filepath.Join(dir, "workload.yaml")
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "missing name",
+			Args:        []string{flags.YesFlagName},
+			ShouldError: true,
+		},
+		{
+			Name: "print skeleton",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.GitBranchFlagName, "main", flags.TypeFlagName, "web",
+				flags.EnvFlagName, "FOO=bar", flags.LimitCPUFlagName, "500m", flags.LimitMemoryFlagName, "1Gi", flags.FilePathFlagName, "-", flags.YesFlagName},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  labels:
+    apps.tanzu.vmware.com/workload-type: web
+  name: my-workload
+spec:
+  env:
+  - name: FOO
+    value: bar
+  resources:
+    limits:
+      cpu: 500m
+      memory: 1Gi
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://github.com/sample-accelerators/spring-petclinic
+`,
+		},
+		{
+			Name:         "from existing workload",
+			Args:         []string{"my-other-workload", flags.FromFlagName, workloadName, flags.EnvFlagName, "FOO-", flags.TypeFlagName, "worker", flags.FilePathFlagName, "-", flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  labels:
+    apps.tanzu.vmware.com/workload-type: worker
+  name: my-other-workload
+spec:
+  source:
+    git:
+      ref:
+        branch: main
+      url: https://github.com/sample-accelerators/spring-petclinic
+`,
+		},
+		{
+			Name:        "from missing workload",
+			Args:        []string{flags.FromFlagName, workloadName, flags.FilePathFlagName, "-", flags.YesFlagName},
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/my-workload" not found
+`,
+		},
+		{
+			Name: "write skeleton",
+			Args: []string{workloadName, flags.GitRepoFlagName, gitRepo, flags.FilePathFlagName, skeleton, flags.YesFlagName},
+			ExpectOutput: `
+Wrote workload "my-workload" to "` + skeleton + `"
+To create the workload run: tanzu apps workload apply --file ` + skeleton + `
+`,
+			Verify: func(t *testing.T, output string, err error) {
+				content, err := os.ReadFile(skeleton)
+				if err != nil {
+					t.Fatalf("unable to read skeleton: %v", err)
+				}
+				expected := `---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: my-workload
+spec:
+  source:
+    git:
+      ref: {}
+      url: https://github.com/sample-accelerators/spring-petclinic
+`
+				if diff := cmp.Diff(expected, string(content)); diff != "" {
+					t.Errorf("Unexpected skeleton (-expected, +actual): %s", diff)
+				}
+			},
+		},
+		{
+			Name:        "prompts without a terminal",
+			Args:        []string{workloadName, flags.FilePathFlagName, "-"},
+			ShouldError: true,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadInitCommand(ctx, c)
+	})
+}
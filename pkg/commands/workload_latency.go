@@ -0,0 +1,157 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/wait"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+const latencyEventPhase = "ready"
+
+type clockStashKey struct{}
+
+// StashClock sets the clock used to measure how long a workload takes to become ready
+func StashClock(ctx context.Context, now func() time.Time) context.Context {
+	return context.WithValue(ctx, clockStashKey{}, now)
+}
+
+func retrieveClock(ctx context.Context) func() time.Time {
+	now, ok := ctx.Value(clockStashKey{}).(func() time.Time)
+	if !ok {
+		return time.Now
+	}
+	return now
+}
+
+// latencyRecorder observes a workload while waiting for it to become ready, recording when its supply
+// chain first outputs a source and an image that it did not output before the workload was applied
+type latencyRecorder struct {
+	m       sync.Mutex
+	now     func() time.Time
+	start   time.Time
+	outputs sets.String
+	latency printer.WorkloadLatency
+}
+
+func newLatencyRecorder(ctx context.Context, workload *cartov1alpha1.Workload) *latencyRecorder {
+	now := retrieveClock(ctx)
+	r := &latencyRecorder{
+		now:     now,
+		start:   now(),
+		outputs: sets.NewString(),
+	}
+	for _, resource := range workload.Status.Resources {
+		for _, output := range resource.Outputs {
+			r.outputs.Insert(output.Digest)
+		}
+	}
+	return r
+}
+
+// Condition wraps the condition waited for, observing each version of the workload
+func (r *latencyRecorder) Condition(condition wait.ConditionFunc) wait.ConditionFunc {
+	return func(obj client.Object) (bool, error) {
+		r.observe(obj)
+		done, err := condition(obj)
+		if done && err == nil {
+			r.m.Lock()
+			r.latency.Ready = r.now().Sub(r.start)
+			r.m.Unlock()
+		}
+		return done, err
+	}
+}
+
+func (r *latencyRecorder) observe(obj client.Object) {
+	workload, ok := obj.(*cartov1alpha1.Workload)
+	if !ok {
+		return
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	for _, resource := range workload.Status.Resources {
+		for _, output := range resource.Outputs {
+			if r.outputs.Has(output.Digest) {
+				continue
+			}
+			r.outputs.Insert(output.Digest)
+			switch output.Name {
+			case "url", "revision":
+				if r.latency.SourceResolved == 0 {
+					r.latency.SourceResolved = r.now().Sub(r.start)
+				}
+			case "image":
+				if r.latency.ImageBuilt == 0 {
+					r.latency.ImageBuilt = r.now().Sub(r.start)
+				}
+			}
+		}
+	}
+}
+
+func (r *latencyRecorder) Latency() printer.WorkloadLatency {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.latency
+}
+
+// latencyEvent is the machine readable latency of a workload, printed with the progress events
+type latencyEvent struct {
+	Phase                 string   `json:"phase"`
+	Workload              string   `json:"workload"`
+	SourceResolvedSeconds *float64 `json:"sourceResolvedSeconds,omitempty"`
+	ImageBuiltSeconds     *float64 `json:"imageBuiltSeconds,omitempty"`
+	ReadySeconds          float64  `json:"readySeconds"`
+}
+
+// printLatency shows how long the workload took to become ready once applied, as a JSON progress event
+// as well when machine readable output is requested
+func (opts *WorkloadOptions) printLatency(c *cli.Config, workload *cartov1alpha1.Workload, latency printer.WorkloadLatency) {
+	c.Printf("\n")
+	c.Infof("Latency\n")
+	printer.WorkloadLatencyPrinter(c.Stdout, workload, latency)
+
+	if opts.Output != printer.OutputFormatJson {
+		return
+	}
+	seconds := func(d time.Duration) *float64 {
+		if d == 0 {
+			return nil
+		}
+		s := d.Seconds()
+		return &s
+	}
+	// progress is best effort, a failed write must not fail the command
+	_ = json.NewEncoder(c.Stderr).Encode(latencyEvent{
+		Phase:                 latencyEventPhase,
+		Workload:              workload.Name,
+		SourceResolvedSeconds: seconds(latency.SourceResolved),
+		ImageBuiltSeconds:     seconds(latency.ImageBuilt),
+		ReadySeconds:          latency.Ready.Seconds(),
+	})
+}
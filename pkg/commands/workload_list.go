@@ -19,15 +19,19 @@ package commands
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
@@ -36,6 +40,7 @@ import (
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer/table"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
 )
 
@@ -44,8 +49,40 @@ type WorkloadListOptions struct {
 	AllNamespaces bool
 	App           string
 	Output        string
+	Export        bool
+	SortBy        string
+	GroupBy       string
+	FieldSelector string
+	SupplyChain   string
+	Ready         string
+	OlderThan     time.Duration
+	Inactive      time.Duration
 }
 
+const (
+	sortByName            = "name"
+	sortByType            = "type"
+	sortByApp             = "app"
+	sortByReady           = "ready"
+	sortByLatestReadyTime = "latest-ready-time"
+	sortByAge             = "age"
+)
+
+var workloadListSortKeys = []string{sortByName, sortByType, sortByApp, sortByReady, sortByLatestReadyTime, sortByAge}
+
+const groupByApp = "app"
+
+var workloadListGroupKeys = []string{groupByApp}
+
+// workloadListReadyStates are the values of --ready, matched against the status of the Ready
+// condition of the workloads
+var workloadListReadyStates = []string{"true", "false", "unknown"}
+
+// workloadListFields are the fields workloads can be selected on. The API server only selects
+// custom resources on their name and namespace, so the selector is matched against the listed
+// workloads instead.
+var workloadListFields = []string{"metadata.name", "metadata.namespace", "spec.serviceAccountName", "status.ready", "status.supplyChainRef.name"}
+
 var (
 	_ validation.Validatable = (*WorkloadListOptions)(nil)
 	_ cli.Executable         = (*WorkloadListOptions)(nil)
@@ -69,6 +106,49 @@ func (opts *WorkloadListOptions) Validate(ctx context.Context) validation.FieldE
 		errs = errs.Also(validation.Enum(opts.Output, flags.OutputFlagName, []string{printer.OutputFormatJson, printer.OutputFormatYaml, printer.OutputFormatYml}))
 	}
 
+	if opts.SortBy != "" {
+		errs = errs.Also(validation.Enum(opts.SortBy, flags.SortByFlagName, workloadListSortKeys))
+	}
+
+	if opts.GroupBy != "" {
+		errs = errs.Also(validation.Enum(opts.GroupBy, flags.GroupByFlagName, workloadListGroupKeys))
+		if opts.Output != "" {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.GroupByFlagName, flags.OutputFlagName))
+		}
+		if opts.Export {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.GroupByFlagName, flags.ExportFlagName))
+		}
+	}
+
+	if opts.FieldSelector != "" {
+		if selector, err := fields.ParseSelector(opts.FieldSelector); err != nil {
+			errs = errs.Also(validation.ErrInvalidValue(opts.FieldSelector, flags.FieldSelectorFlagName))
+		} else {
+			for _, requirement := range selector.Requirements() {
+				if !sets.NewString(workloadListFields...).Has(requirement.Field) {
+					errs = errs.Also(validation.ErrInvalidValue(opts.FieldSelector, flags.FieldSelectorFlagName))
+					break
+				}
+			}
+		}
+	}
+
+	if opts.SupplyChain != "" {
+		errs = errs.Also(validation.K8sName(opts.SupplyChain, flags.SupplyChainFlagName))
+	}
+
+	if opts.Ready != "" {
+		errs = errs.Also(validation.Enum(strings.ToLower(opts.Ready), flags.ReadyFlagName, workloadListReadyStates))
+	}
+
+	if opts.OlderThan < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(opts.OlderThan, flags.OlderThanFlagName))
+	}
+
+	if opts.Inactive < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(opts.Inactive, flags.InactiveFlagName))
+	}
+
 	return errs
 }
 
@@ -82,7 +162,58 @@ func (opts *WorkloadListOptions) Exec(ctx context.Context, c *cli.Config) error
 		return err
 	}
 
+	workloads = workloads.DeepCopy()
+	if selector, err := opts.fieldSelector(); err != nil {
+		return err
+	} else if !selector.Empty() {
+		items := []cartov1alpha1.Workload{}
+		for _, workload := range workloads.Items {
+			if selector.Matches(workloadFields(&workload)) {
+				items = append(items, workload)
+			}
+		}
+		workloads.Items = items
+	}
+	if opts.OlderThan > 0 || opts.Inactive > 0 {
+		now := time.Now()
+		items := []cartov1alpha1.Workload{}
+		for _, workload := range workloads.Items {
+			if opts.OlderThan > 0 && now.Sub(workload.CreationTimestamp.Time) < opts.OlderThan {
+				continue
+			}
+			if opts.Inactive > 0 && now.Sub(lastStatusChange(&workload)) < opts.Inactive {
+				continue
+			}
+			items = append(items, workload)
+		}
+		workloads.Items = items
+	}
+
+	if opts.Export {
+		opts.sort(workloads.Items)
+		list := []printer.Object{}
+		for i := range workloads.Items {
+			list = append(list, &workloads.Items[i])
+		}
+		format := printer.OutputFormat(printer.OutputFormatYaml)
+		if opts.Output != "" {
+			format = printer.OutputFormat(opts.Output)
+		}
+		export, err := printer.ExportResources(list, format, c.Scheme)
+		if err != nil {
+			c.Eprintf("%s %s\n", printer.Serrorf("Failed to export workloads:"), err)
+			return cli.SilenceError(err)
+		}
+		if export != "" {
+			c.Printf("%s\n", export)
+		}
+		return nil
+	}
+
 	if opts.Output != "" {
+		if opts.SortBy != "" {
+			opts.sort(workloads.Items)
+		}
 		var list []printer.Object
 		for i := range workloads.Items {
 			list = append(list, &workloads.Items[i])
@@ -115,37 +246,178 @@ func (opts *WorkloadListOptions) Exec(ctx context.Context, c *cli.Config) error
 		h.TableHandler(columns, opts.print)
 	})
 
-	workloads = workloads.DeepCopy()
-	printer.SortByNamespaceAndName(workloads.Items)
+	opts.sort(workloads.Items)
+	if opts.GroupBy == groupByApp {
+		groupWorkloadsByApp(workloads.Items)
+	}
 
 	return tablePrinter.PrintObj(workloads, c.Stdout)
 }
 
+// groupWorkloadsByApp orders the workloads by application, keeping their order within each
+// application, the workloads that are not part of an application come last
+func groupWorkloadsByApp(workloads []cartov1alpha1.Workload) {
+	sort.SliceStable(workloads, func(i, j int) bool {
+		a, b := workloads[i].Labels[apis.AppPartOfLabelName], workloads[j].Labels[apis.AppPartOfLabelName]
+		if a == "" || b == "" {
+			return a != "" && b == ""
+		}
+		return a < b
+	})
+}
+
+// fieldSelector combines --field-selector with the fields selected by --supply-chain and --ready
+func (opts *WorkloadListOptions) fieldSelector() (fields.Selector, error) {
+	selectors := []fields.Selector{}
+	if opts.FieldSelector != "" {
+		selector, err := fields.ParseSelector(opts.FieldSelector)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+	if opts.SupplyChain != "" {
+		selectors = append(selectors, fields.OneTermEqualSelector("status.supplyChainRef.name", opts.SupplyChain))
+	}
+	if opts.Ready != "" {
+		// the condition status is capitalized, True, False or Unknown
+		ready := strings.ToLower(opts.Ready)
+		selectors = append(selectors, fields.OneTermEqualSelector("status.ready", strings.ToUpper(ready[:1])+ready[1:]))
+	}
+	return fields.AndSelectors(selectors...), nil
+}
+
+// sort orders the workloads by namespace and name, then by the --sort-by key. Workloads that are
+// not ready come first when sorting by readiness.
+func (opts *WorkloadListOptions) sort(workloads []cartov1alpha1.Workload) {
+	printer.SortByNamespaceAndName(workloads)
+
+	var less func(a, b *cartov1alpha1.Workload) bool
+	switch opts.SortBy {
+	case sortByType:
+		less = func(a, b *cartov1alpha1.Workload) bool {
+			return a.Labels[apis.WorkloadTypeLabelName] < b.Labels[apis.WorkloadTypeLabelName]
+		}
+	case sortByApp:
+		less = func(a, b *cartov1alpha1.Workload) bool {
+			return a.Labels[apis.AppPartOfLabelName] < b.Labels[apis.AppPartOfLabelName]
+		}
+	case sortByReady:
+		rank := map[metav1.ConditionStatus]int{metav1.ConditionFalse: 0, metav1.ConditionUnknown: 1, metav1.ConditionTrue: 2}
+		readyRank := func(workload *cartov1alpha1.Workload) int {
+			cond := printer.FindCondition(workload.Status.Conditions, cartov1alpha1.WorkloadConditionReady)
+			if cond == nil {
+				return rank[metav1.ConditionUnknown]
+			}
+			return rank[cond.Status]
+		}
+		less = func(a, b *cartov1alpha1.Workload) bool {
+			return readyRank(a) < readyRank(b)
+		}
+	case sortByLatestReadyTime:
+		less = func(a, b *cartov1alpha1.Workload) bool {
+			return latestReadyTime(a).Before(latestReadyTime(b))
+		}
+	case sortByAge:
+		less = func(a, b *cartov1alpha1.Workload) bool {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+	default:
+		return
+	}
+	sort.SliceStable(workloads, func(i, j int) bool {
+		return less(&workloads[i], &workloads[j])
+	})
+}
+
+// latestReadyTime is the time the workload became ready, zero when it is not ready
+func latestReadyTime(workload *cartov1alpha1.Workload) time.Time {
+	cond := printer.FindCondition(workload.Status.Conditions, cartov1alpha1.WorkloadConditionReady)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		return time.Time{}
+	}
+	return cond.LastTransitionTime.Time
+}
+
+// lastStatusChange is the latest transition of the workload conditions or of the conditions of
+// its supply chain resources, the creation of the workload when none transitioned
+func lastStatusChange(workload *cartov1alpha1.Workload) time.Time {
+	last := workload.CreationTimestamp.Time
+	conditions := append([]metav1.Condition{}, workload.Status.Conditions...)
+	for _, resource := range workload.Status.Resources {
+		conditions = append(conditions, resource.Conditions...)
+	}
+	for _, cond := range conditions {
+		if cond.LastTransitionTime.Time.After(last) {
+			last = cond.LastTransitionTime.Time
+		}
+	}
+	return last
+}
+
+func workloadFields(workload *cartov1alpha1.Workload) fields.Set {
+	ready := string(metav1.ConditionUnknown)
+	if cond := printer.FindCondition(workload.Status.Conditions, cartov1alpha1.WorkloadConditionReady); cond != nil && cond.Status != "" {
+		ready = string(cond.Status)
+	}
+	serviceAccountName := ""
+	if workload.Spec.ServiceAccountName != nil {
+		serviceAccountName = *workload.Spec.ServiceAccountName
+	}
+	return fields.Set{
+		"metadata.name":              workload.Name,
+		"metadata.namespace":         workload.Namespace,
+		"spec.serviceAccountName":    serviceAccountName,
+		"status.ready":               ready,
+		"status.supplyChainRef.name": workload.Status.SupplyChainRef.Name,
+	}
+}
+
 func NewWorkloadListCommand(ctx context.Context, c *cli.Config) *cobra.Command {
 	opts := &WorkloadListOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "list",
-		Short: "Table listing of workloads",
+		Use:     "list",
+		Aliases: []string{"ls", "l"},
+		Short:   "Table listing of workloads",
 		Long: strings.TrimSpace(`
 List workloads in a namespace or across all namespaces.
 `),
-		Example: strings.Join([]string{
-			fmt.Sprintf("%s workload list", c.Name),
-			fmt.Sprintf("%s workload list %s", c.Name, flags.AllNamespacesFlagName),
-		}, "\n"),
+		Example: examplesFor(c, "workload list"),
 		PreRunE: cli.ValidateE(ctx, opts),
 		RunE:    cli.ExecE(ctx, c, opts),
 	}
 
 	cli.AllNamespacesFlag(ctx, cmd, c, &opts.Namespace, &opts.AllNamespaces)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
 	cmd.Flags().StringVar(&opts.App, cli.StripDash(flags.AppFlagName), "", "application `name` the workload is a part of")
 	cmd.Flags().StringVarP(&opts.Output, cli.StripDash(flags.OutputFlagName), "o", "", "output the Workloads formatted. Supported formats: \"json\", \"yaml\", \"yml\"")
+	cmd.Flags().BoolVar(&opts.Export, cli.StripDash(flags.ExportFlagName), false, "export the workloads as a multi-document yaml, without status and cluster managed metadata, ready to be applied")
+	cmd.Flags().StringVar(&opts.SortBy, cli.StripDash(flags.SortByFlagName), "", "sort workloads by `column`, one of "+strings.Join(workloadListSortKeys, ", ")+" (default name)")
+	cmd.Flags().StringVar(&opts.GroupBy, cli.StripDash(flags.GroupByFlagName), "", "group workloads by `key` and print them as a tree with the readiness of each group, one of "+strings.Join(workloadListGroupKeys, ", "))
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.GroupByFlagName), func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return workloadListGroupKeys, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&opts.FieldSelector, cli.StripDash(flags.FieldSelectorFlagName), "", "`selector` to filter workloads on, supports '=', '==' and '!=' on the fields "+strings.Join(workloadListFields, ", "))
+	cmd.Flags().StringVar(&opts.SupplyChain, cli.StripDash(flags.SupplyChainFlagName), "", "only list workloads selected by the supply chain `name`")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.SupplyChainFlagName), completion.SuggestClusterSupplyChainNames(ctx, c))
+	cmd.Flags().StringVar(&opts.Ready, cli.StripDash(flags.ReadyFlagName), "", "only list workloads whose Ready condition is `status`, one of "+strings.Join(workloadListReadyStates, ", "))
+	cmd.Flags().Lookup(cli.StripDash(flags.ReadyFlagName)).NoOptDefVal = "true"
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.ReadyFlagName), func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return workloadListReadyStates, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().DurationVar(&opts.OlderThan, cli.StripDash(flags.OlderThanFlagName), 0, "only list workloads created longer than `duration` ago")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.OlderThanFlagName), completion.SuggestDurationUnits(ctx, completion.CommonDurationUnits))
+	cmd.Flags().DurationVar(&opts.Inactive, cli.StripDash(flags.InactiveFlagName), 0, "only list workloads whose status, or the status of their supply chain resources, did not change for `duration`")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.InactiveFlagName), completion.SuggestDurationUnits(ctx, completion.CommonDurationUnits))
 
 	return cmd
 }
 
 func (opts *WorkloadListOptions) printList(workloads *cartov1alpha1.WorkloadList, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
+	if opts.GroupBy == groupByApp {
+		return opts.printAppTree(workloads, printOpts)
+	}
 	rows := make([]metav1beta1.TableRow, 0, len(workloads.Items))
 	for i := range workloads.Items {
 		r, err := opts.print(&workloads.Items[i], printOpts)
@@ -157,6 +429,80 @@ func (opts *WorkloadListOptions) printList(workloads *cartov1alpha1.WorkloadList
 	return rows, nil
 }
 
+// printAppTree prints a row for each application, with how many of its workloads are ready, followed
+// by the rows of its workloads as branches. The workloads are expected grouped by application.
+func (opts *WorkloadListOptions) printAppTree(workloads *cartov1alpha1.WorkloadList, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
+	rows := []metav1beta1.TableRow{}
+	for start := 0; start < len(workloads.Items); {
+		app := workloads.Items[start].Labels[apis.AppPartOfLabelName]
+		end := start
+		for end < len(workloads.Items) && workloads.Items[end].Labels[apis.AppPartOfLabelName] == app {
+			end++
+		}
+		group := workloads.Items[start:end]
+
+		rows = append(rows, opts.printApp(app, group))
+		for i := range group {
+			r, err := opts.print(&group[i], printOpts)
+			if err != nil {
+				return nil, err
+			}
+			branch := "├─ "
+			if i == len(group)-1 {
+				branch = "└─ "
+			}
+			for j := range r {
+				r[j].Cells[0] = branch + r[j].Cells[0].(string)
+			}
+			rows = append(rows, r...)
+		}
+		start = end
+	}
+	return rows, nil
+}
+
+// printApp rolls up the workloads of an application: how many of them are ready, the time the
+// application became ready, once all its workloads are, and the age of its oldest workload
+func (opts *WorkloadListOptions) printApp(app string, workloads []cartov1alpha1.Workload) metav1beta1.TableRow {
+	now := time.Now()
+	ready, failed := 0, 0
+	readyTime := time.Time{}
+	created := workloads[0].CreationTimestamp
+	for i := range workloads {
+		workload := &workloads[i]
+		cond := printer.FindCondition(workload.Status.Conditions, cartov1alpha1.WorkloadConditionReady)
+		switch {
+		case cond == nil:
+		case cond.Status == metav1.ConditionTrue:
+			ready++
+		case cond.Status == metav1.ConditionFalse:
+			failed++
+		}
+		if t := latestReadyTime(workload); t.After(readyTime) {
+			readyTime = t
+		}
+		if workload.CreationTimestamp.Before(&created) {
+			created = workload.CreationTimestamp
+		}
+	}
+
+	readiness := fmt.Sprintf("%d/%d", ready, len(workloads))
+	appReadyTime := printer.EmptyString("")
+	switch {
+	case ready == len(workloads):
+		readiness = printer.Ssuccessf(readiness)
+		appReadyTime = printer.TimestampSince(metav1.NewTime(readyTime), now)
+	case failed != 0:
+		readiness = printer.Serrorf(readiness)
+	default:
+		readiness = printer.Swarnf(readiness)
+	}
+
+	return metav1beta1.TableRow{
+		Cells: []interface{}{printer.EmptyString(app), "", readiness, appReadyTime, printer.TimestampSince(created, now)},
+	}
+}
+
 func (opts *WorkloadListOptions) print(workload *cartov1alpha1.Workload, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
 	now := time.Now()
 	row := metav1beta1.TableRow{
@@ -170,11 +516,17 @@ func (opts *WorkloadListOptions) print(workload *cartov1alpha1.Workload, _ table
 
 	row.Cells = append(row.Cells, workload.Name,
 		printer.EmptyString(labels[apis.WorkloadTypeLabelName]))
-	if opts.App == "" {
+	if opts.showApp() {
 		row.Cells = append(row.Cells, printer.EmptyString(labels[apis.AppPartOfLabelName]))
 	}
+	readyCond := printer.FindCondition(workload.Status.Conditions, cartov1alpha1.WorkloadConditionReady)
+	readyTime := printer.EmptyString("")
+	if readyCond != nil && readyCond.Status == metav1.ConditionTrue {
+		readyTime = printer.TimestampSince(readyCond.LastTransitionTime, now)
+	}
 	row.Cells = append(row.Cells,
-		printer.ConditionStatus(printer.FindCondition(workload.Status.Conditions, cartov1alpha1.WorkloadConditionReady)),
+		printer.ConditionStatus(readyCond),
+		readyTime,
 		printer.TimestampSince(workload.CreationTimestamp, now),
 	)
 	return []metav1beta1.TableRow{row}, nil
@@ -185,13 +537,20 @@ func (opts *WorkloadListOptions) printColumns() []metav1beta1.TableColumnDefinit
 
 	cols = append(cols, metav1beta1.TableColumnDefinition{Name: "Name", Type: "string"},
 		metav1beta1.TableColumnDefinition{Name: "Type", Type: "string"})
-	if opts.App == "" {
+	if opts.showApp() {
 		cols = append(cols, metav1beta1.TableColumnDefinition{Name: "App", Type: "string"})
 	}
 	cols = append(cols,
 		metav1beta1.TableColumnDefinition{Name: "Ready", Type: "string"},
+		metav1beta1.TableColumnDefinition{Name: "Latest-Ready-Time", Type: "string"},
 		metav1beta1.TableColumnDefinition{Name: "Age", Type: "string"},
 	)
 
 	return cols
 }
+
+// showApp tells whether the app column is printed, it is not when listing the workloads of a single
+// application or when the workloads are grouped by application
+func (opts *WorkloadListOptions) showApp() bool {
+	return opts.App == "" && opts.GroupBy == ""
+}
@@ -84,6 +84,123 @@ func TestWorkloadListOptionsValidate(t *testing.T) {
 			},
 			ExpectFieldErrors: validation.EnumInvalidValue("myFormat", flags.OutputFlagName, []string{"json", "yaml", "yml"}),
 		},
+		{
+			Name: "sort and field selector",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace:     "default",
+				SortBy:        "ready",
+				FieldSelector: "status.ready!=True,metadata.name!=my-workload",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid sort",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace: "default",
+				SortBy:    "status",
+			},
+			ExpectFieldErrors: validation.EnumInvalidValue("status", flags.SortByFlagName, []string{"name", "type", "app", "ready", "latest-ready-time", "age"}),
+		},
+		{
+			Name: "group by app",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace: "default",
+				GroupBy:   "app",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid group by",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace: "default",
+				GroupBy:   "type",
+			},
+			ExpectFieldErrors: validation.EnumInvalidValue("type", flags.GroupByFlagName, []string{"app"}),
+		},
+		{
+			Name: "group by with output",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace: "default",
+				GroupBy:   "app",
+				Output:    "json",
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.GroupByFlagName, flags.OutputFlagName),
+		},
+		{
+			Name: "group by with export",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace: "default",
+				GroupBy:   "app",
+				Export:    true,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.GroupByFlagName, flags.ExportFlagName),
+		},
+		{
+			Name: "invalid field selector",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace:     "default",
+				FieldSelector: "status.ready",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("status.ready", flags.FieldSelectorFlagName),
+		},
+		{
+			Name: "supply chain and ready",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace:   "default",
+				SupplyChain: "source-to-url",
+				Ready:       "False",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid ready",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace: "default",
+				Ready:     "yes",
+			},
+			ExpectFieldErrors: validation.EnumInvalidValue("yes", flags.ReadyFlagName, []string{"true", "false", "unknown"}),
+		},
+		{
+			Name: "invalid supply chain",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace:   "default",
+				SupplyChain: "Source_To_URL",
+			},
+			ExpectFieldErrors: validation.K8sName("Source_To_URL", flags.SupplyChainFlagName),
+		},
+		{
+			Name: "older than and inactive",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace: "default",
+				OlderThan: 720 * time.Hour,
+				Inactive:  168 * time.Hour,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid older than",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace: "default",
+				OlderThan: -time.Hour,
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue(-time.Hour, flags.OlderThanFlagName),
+		},
+		{
+			Name: "invalid inactive",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace: "default",
+				Inactive:  -time.Hour,
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue(-time.Hour, flags.InactiveFlagName),
+		},
+		{
+			Name: "unsupported field selector",
+			Validatable: &commands.WorkloadListOptions{
+				Namespace:     "default",
+				FieldSelector: "spec.image=ubuntu",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("spec.image=ubuntu", flags.FieldSelectorFlagName),
+		},
 	}
 
 	table.Run(t)
@@ -132,8 +249,8 @@ No workloads found.
 				parent,
 			},
 			ExpectOutput: `
-NAME            TYPE      APP       READY       AGE
-test-workload   <empty>   <empty>   <unknown>   2y
+NAME            TYPE      APP       READY       LATEST-READY-TIME   AGE
+test-workload   <empty>   <empty>   <unknown>   <empty>             2y
 `,
 		},
 		{
@@ -266,6 +383,87 @@ test-workload   <empty>   <empty>   <unknown>   2y
     supplyChainRef: {}
 `,
 		},
+		{
+			Name: "export",
+			Args: []string{flags.ExportFlagName, flags.OutputFlagName, "yaml"},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("registry.example/test-workload:v1")
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionTrue),
+						)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("another-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(objTimeStamp)
+						d.AddLabel(apis.WorkloadTypeLabelName, "web")
+					}),
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  labels:
+    apps.tanzu.vmware.com/workload-type: web
+  name: another-workload
+  namespace: default
+spec: {}
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: test-workload
+  namespace: default
+spec:
+  image: registry.example/test-workload:v1
+`,
+		},
+		{
+			Name: "export in json format",
+			Args: []string{flags.ExportFlagName, flags.OutputFlagName, "json"},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ExpectOutput: `
+[
+	{
+		"apiVersion": "carto.run/v1alpha1",
+		"kind": "Workload",
+		"metadata": {
+			"name": "test-workload",
+			"namespace": "default"
+		},
+		"spec": {}
+	}
+]
+`,
+		},
+		{
+			Name: "export defaults to yaml",
+			Args: []string{flags.ExportFlagName},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ExpectOutput: `
+---
+apiVersion: carto.run/v1alpha1
+kind: Workload
+metadata:
+  name: test-workload
+  namespace: default
+spec: {}
+`,
+		},
+		{
+			Name: "export nothing",
+			Args: []string{flags.ExportFlagName, flags.OutputFlagName, "yaml"},
+		},
 		{
 			Name: "lists an item, with detail",
 			Args: []string{},
@@ -277,13 +475,15 @@ test-workload   <empty>   <empty>   <unknown>   2y
 					}).
 					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
 						d.ConditionsDie(
-							diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionTrue),
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue).
+								LastTransitionTime(metav1.NewTime(time.Now().Add(-5 * time.Minute))),
 						)
 					},
 					)},
 			ExpectOutput: `
-NAME            TYPE   APP     READY   AGE
-test-workload   web    hello   Ready   2y
+NAME            TYPE   APP     READY   LATEST-READY-TIME   AGE
+test-workload   web    hello   Ready   5m                  2y
 `,
 		},
 		{
@@ -301,8 +501,8 @@ test-workload   web    hello   Ready   2y
 					}),
 			},
 			ExpectOutput: `
-NAME            TYPE      READY       AGE
-test-workload   <empty>   <unknown>   2y
+NAME            TYPE      READY       LATEST-READY-TIME   AGE
+test-workload   <empty>   <unknown>   <empty>             2y
 `,
 		},
 		{
@@ -351,9 +551,308 @@ Error: namespace "foo" not found, it may not exist or user does not have permiss
 					}),
 			},
 			ExpectOutput: `
-NAMESPACE         NAME                  TYPE      APP       READY       AGE
-default           test-workload         <empty>   <empty>   <unknown>   2y
-other-namespace   test-other-workload   web       <empty>   <unknown>   2y
+NAMESPACE         NAME                  TYPE      APP       READY       LATEST-READY-TIME   AGE
+default           test-workload         <empty>   <empty>   <unknown>   <empty>             2y
+other-namespace   test-other-workload   web       <empty>   <unknown>   <empty>             2y
+`,
+		},
+		{
+			Name: "sort by ready",
+			Args: []string{flags.SortByFlagName, "ready"},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue).
+								LastTransitionTime(metav1.NewTime(time.Now().Add(-5 * time.Minute))),
+						)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("unknown-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(objTimeStamp)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("failed-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(objTimeStamp)
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionFalse).
+								Reason("OopsieDoodle"),
+						)
+					}),
+			},
+			ExpectOutput: `
+NAME               TYPE      APP       READY          LATEST-READY-TIME   AGE
+failed-workload    <empty>   <empty>   OopsieDoodle   <empty>             2y
+unknown-workload   <empty>   <empty>   <unknown>      <empty>             2y
+test-workload      <empty>   <empty>   Ready          5m                  2y
+`,
+		},
+		{
+			Name: "group by app",
+			Args: []string{flags.GroupByFlagName, "app"},
+			GivenObjects: []client.Object{
+				parent,
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("petclinic-api")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(objTimeStamp)
+						d.AddLabel(apis.AppPartOfLabelName, "petclinic")
+						d.AddLabel(apis.WorkloadTypeLabelName, "web")
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue).
+								LastTransitionTime(metav1.NewTime(time.Now().Add(-5 * time.Minute))),
+						)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("petclinic-db")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(objTimeStamp)
+						d.AddLabel(apis.AppPartOfLabelName, "petclinic")
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionFalse).
+								Reason("OopsieDoodle"),
+						)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("catalog")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(objTimeStamp)
+						d.AddLabel(apis.AppPartOfLabelName, "bookstore")
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue).
+								LastTransitionTime(metav1.NewTime(time.Now().Add(-time.Hour))),
+						)
+					}),
+			},
+			ExpectOutput: `
+NAME               TYPE      READY          LATEST-READY-TIME   AGE
+bookstore                    1/1            60m                 2y
+└─ catalog         <empty>   Ready          60m                 2y
+petclinic                    1/2            <empty>             2y
+├─ petclinic-api   web       Ready          5m                  2y
+└─ petclinic-db    <empty>   OopsieDoodle   <empty>             2y
+<empty>                      0/1            <empty>             2y
+└─ test-workload   <empty>   <unknown>      <empty>             2y
+`,
+		},
+		{
+			Name: "sort by age",
+			Args: []string{flags.SortByFlagName, "age"},
+			GivenObjects: []client.Object{
+				parent,
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("old-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(metav1.NewTime(time.Now().Add(-3 * 365 * 24 * time.Hour)))
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("a-new-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(metav1.NewTime(time.Now().Add(-time.Hour)))
+					}),
+			},
+			ExpectOutput: `
+NAME             TYPE      APP       READY       LATEST-READY-TIME   AGE
+old-workload     <empty>   <empty>   <unknown>   <empty>             3y
+test-workload    <empty>   <empty>   <unknown>   <empty>             2y
+a-new-workload   <empty>   <empty>   <unknown>   <empty>             60m
+`,
+		},
+		{
+			Name: "older than",
+			Args: []string{flags.OlderThanFlagName, "720h"},
+			GivenObjects: []client.Object{
+				parent,
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("a-new-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(metav1.NewTime(time.Now().Add(-time.Hour)))
+					}),
+			},
+			ExpectOutput: `
+NAME            TYPE      APP       READY       LATEST-READY-TIME   AGE
+test-workload   <empty>   <empty>   <unknown>   <empty>             2y
+`,
+		},
+		{
+			Name: "inactive",
+			Args: []string{flags.InactiveFlagName, "720h"},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionFalse).
+								Reason("OopsieDoodle").
+								LastTransitionTime(metav1.NewTime(time.Now().Add(-60 * 24 * time.Hour))),
+						)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("building-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(objTimeStamp)
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionUnknown).
+								LastTransitionTime(metav1.NewTime(time.Now().Add(-60 * 24 * time.Hour))),
+						)
+						d.Resources(cartov1alpha1.RealizedResource{
+							Name: "image-builder",
+							Conditions: []metav1.Condition{{
+								Type:               cartov1alpha1.ConditionResourceReady,
+								Status:             metav1.ConditionUnknown,
+								LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+							}},
+						})
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("a-new-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(metav1.NewTime(time.Now().Add(-time.Hour)))
+					}),
+			},
+			ExpectOutput: `
+NAME            TYPE      APP       READY          LATEST-READY-TIME   AGE
+test-workload   <empty>   <empty>   OopsieDoodle   <empty>             2y
+`,
+		},
+		{
+			Name: "field selector",
+			Args: []string{flags.FieldSelectorFlagName, "status.ready!=True"},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionTrue),
+						)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("failed-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(objTimeStamp)
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionFalse).
+								Reason("OopsieDoodle"),
+						)
+					}),
+			},
+			ExpectOutput: `
+NAME              TYPE      APP       READY          LATEST-READY-TIME   AGE
+failed-workload   <empty>   <empty>   OopsieDoodle   <empty>             2y
+`,
+		},
+		{
+			Name: "supply chain and not ready",
+			Args: []string{flags.SupplyChainFlagName, "source-to-url", flags.ReadyFlagName + "=false"},
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.SupplyChainRef(cartov1alpha1.ObjectReference{Name: "source-to-url"})
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionFalse).
+								Reason("OopsieDoodle"),
+						)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("ready-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(objTimeStamp)
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.SupplyChainRef(cartov1alpha1.ObjectReference{Name: "source-to-url"})
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionTrue),
+						)
+					}),
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("other-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(objTimeStamp)
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.SupplyChainRef(cartov1alpha1.ObjectReference{Name: "basic-image-to-url"})
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionFalse).
+								Reason("OopsieDoodle"),
+						)
+					}),
+			},
+			ExpectOutput: `
+NAME            TYPE      APP       READY          LATEST-READY-TIME   AGE
+test-workload   <empty>   <empty>   OopsieDoodle   <empty>             2y
+`,
+		},
+		{
+			Name: "ready without value",
+			Args: []string{flags.ReadyFlagName},
+			GivenObjects: []client.Object{
+				parent,
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name("ready-workload")
+						d.Namespace(defaultNamespace)
+						d.CreationTimestamp(objTimeStamp)
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.
+								Status(metav1.ConditionTrue).
+								LastTransitionTime(objTimeStamp),
+						)
+					}),
+			},
+			ExpectOutput: `
+NAME             TYPE      APP       READY   LATEST-READY-TIME   AGE
+ready-workload   <empty>   <empty>   Ready   2y                  2y
+`,
+		},
+		{
+			Name: "field selector matches nothing",
+			Args: []string{flags.FieldSelectorFlagName, "metadata.name=missing"},
+			GivenObjects: []client.Object{
+				diecorev1.NamespaceBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(defaultNamespace)
+					}),
+				parent,
+			},
+			ExpectOutput: `
+No workloads found.
 `,
 		},
 		{
@@ -0,0 +1,263 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/parsers"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+const MetadataArgumentName = "key=value"
+
+// WorkloadMetadataOptions changes the labels, or the annotations with Annotate, of the workload
+// resource, like kubectl label and kubectl annotate
+type WorkloadMetadataOptions struct {
+	Namespace string
+	Name      string
+	Changes   []string
+
+	Annotate       bool
+	Overwrite      bool
+	Force          bool
+	AllowProtected bool
+	Audit          bool
+	Yes            bool
+}
+
+var (
+	_ validation.Validatable = (*WorkloadMetadataOptions)(nil)
+	_ cli.Executable         = (*WorkloadMetadataOptions)(nil)
+)
+
+func (opts *WorkloadMetadataOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Namespace == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+	}
+
+	if opts.Name == "" {
+		errs = errs.Also(validation.ErrMissingField(cli.NameArgumentName))
+	} else {
+		errs = errs.Also(validation.K8sName(opts.Name, cli.NameArgumentName))
+	}
+
+	if len(opts.Changes) == 0 {
+		errs = errs.Also(validation.ErrMissingField(MetadataArgumentName))
+	}
+	errs = errs.Also(validation.DeletableKeyValues(opts.Changes, MetadataArgumentName))
+	if !opts.Annotate {
+		for i, change := range opts.Changes {
+			if kv := parsers.DeletableKeyValue(change); len(kv) == 2 {
+				errs = errs.Also(validation.K8sLabelValue(kv[1], validation.CurrentField).ViaFieldIndex(MetadataArgumentName, i))
+			}
+		}
+	}
+
+	return errs
+}
+
+func (opts *WorkloadMetadataOptions) Exec(ctx context.Context, c *cli.Config) error {
+	if err := validateProtectedNamespace(c, opts.Namespace, opts.AllowProtected).ToAggregate(); err != nil {
+		return err
+	}
+	if err := opts.validateProtectedPrefixes(c).ToAggregate(); err != nil {
+		return err
+	}
+
+	current := &cartov1alpha1.Workload{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, current); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		c.Errorf("Workload %q not found\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Name))
+		return cli.SilenceError(err)
+	}
+
+	workload := current.DeepCopy()
+	if err := opts.apply(workload); err != nil {
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+		return cli.SilenceError(err)
+	}
+	difference, noChange, err := printer.ResourceDiff(current, workload, c.Scheme)
+	if err != nil {
+		return err
+	}
+	if noChange {
+		c.Infof("Workload is unchanged, skipping update\n")
+		return nil
+	}
+	c.Printf("Update %ss of workload %q:\n", opts.noun(), workload.Name)
+	c.Printf("%s\n", difference)
+
+	if !opts.Yes {
+		okToUpdate := false
+		err := survey.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("Really update the %ss of workload %q?", opts.noun(), workload.Name),
+		}, &okToUpdate, printer.WithSurveyStdio(c.Stdin, c.Stdout, c.Stderr))
+		if err != nil || !okToUpdate {
+			c.Infof("Skipping workload %q\n", workload.Name)
+			return nil
+		}
+	}
+
+	if err := auditWorkload(ctx, workload, opts.Audit); err != nil {
+		return err
+	}
+	if err := c.Update(ctx, workload); err != nil {
+		if apierrs.IsConflict(err) {
+			c.Printf("%s conflict updating workload, the object was modified by another user; please run the %s command again\n", printer.Serrorf("Error:"), opts.verb())
+			return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeConflict))
+		}
+		return err
+	}
+	c.Successf("Updated %ss of workload %q\n", opts.noun(), workload.Name)
+	return nil
+}
+
+// validateProtectedPrefixes rejects the changes to a label or annotation whose key starts with a prefix
+// protected by the plugin config, unless forced
+func (opts *WorkloadMetadataOptions) validateProtectedPrefixes(c *cli.Config) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	if opts.Force {
+		return errs
+	}
+	for i, change := range opts.Changes {
+		key := parsers.DeletableKeyValue(change)[0]
+		errs = errs.Also(validateProtectedPrefix(c, key, validation.CurrentField).ViaFieldIndex(MetadataArgumentName, i))
+	}
+	return errs
+}
+
+// apply sets and removes the labels or annotations of the changes, a key already set to another
+// value is only overwritten with Overwrite
+func (opts *WorkloadMetadataOptions) apply(workload *cartov1alpha1.Workload) error {
+	for _, change := range opts.Changes {
+		kv := parsers.DeletableKeyValue(change)
+		values := workload.Labels
+		if opts.Annotate {
+			values = workload.Annotations
+		}
+		if len(kv) == 1 {
+			delete(values, kv[0])
+			continue
+		}
+		if value, ok := values[kv[0]]; ok && value != kv[1] && !opts.Overwrite {
+			return fmt.Errorf("%s %q already has a value (%s), and %s is false", opts.noun(), kv[0], value, flags.OverwriteFlagName)
+		}
+		if opts.Annotate {
+			workload.MergeAnnotations(kv[0], kv[1])
+		} else {
+			workload.MergeLabels(kv[0], kv[1])
+		}
+	}
+	return nil
+}
+
+func (opts *WorkloadMetadataOptions) verb() string {
+	if opts.Annotate {
+		return "annotate"
+	}
+	return "label"
+}
+
+func (opts *WorkloadMetadataOptions) noun() string {
+	if opts.Annotate {
+		return "annotation"
+	}
+	return "label"
+}
+
+func metadataArgs(changes *[]string) cli.Arg {
+	return cli.Arg{
+		Name:  MetadataArgumentName,
+		Arity: -1,
+		Set: func(cmd *cobra.Command, args []string, offset int) error {
+			*changes = args[offset:]
+			return nil
+		},
+	}
+}
+
+func newWorkloadMetadataCommand(ctx context.Context, c *cli.Config, opts *WorkloadMetadataOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		cli.NameArg(&opts.Name),
+		metadataArgs(&opts.Changes),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().BoolVar(&opts.Overwrite, cli.StripDash(flags.OverwriteFlagName), false, fmt.Sprintf("allow changing the value of %ss already set on the workload", opts.noun()))
+	cmd.Flags().BoolVar(&opts.Force, cli.StripDash(flags.ForceFlagName), false, fmt.Sprintf("allow changing %ss with a prefix protected by the plugin config", opts.noun()))
+	cmd.Flags().BoolVar(&opts.AllowProtected, cli.StripDash(flags.AllowProtectedFlagName), false, "allow updating a workload in a namespace protected by the plugin config")
+	auditFlag(cmd, &opts.Audit)
+	cmd.Flags().BoolVarP(&opts.Yes, cli.StripDash(flags.YesFlagName), "y", false, "accept all prompts")
+
+	return cmd
+}
+
+func NewWorkloadLabelCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	cmd := newWorkloadMetadataCommand(ctx, c, &WorkloadMetadataOptions{})
+	cmd.Use = "label"
+	cmd.Short = "Add or remove labels of a workload"
+	cmd.Long = strings.TrimSpace(`
+Add or remove labels of a workload, as "key=value" pairs to set and "key-" to
+remove, without changing the rest of the workload.
+
+The change is shown and confirmed before the workload is updated. A label already
+set to another value is only changed with --overwrite.
+`)
+	cmd.Example = examplesFor(c, "workload label")
+	return cmd
+}
+
+func NewWorkloadAnnotateCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	cmd := newWorkloadMetadataCommand(ctx, c, &WorkloadMetadataOptions{Annotate: true})
+	cmd.Use = "annotate"
+	cmd.Short = "Add or remove annotations of a workload"
+	cmd.Long = strings.TrimSpace(`
+Add or remove annotations of the workload resource, as "key=value" pairs to set
+and "key-" to remove, without changing the rest of the workload. Unlike the
+--annotation flag of workload apply, the annotations are not passed to the
+supply chain as a param.
+
+The change is shown and confirmed before the workload is updated. An annotation
+already set to another value is only changed with --overwrite.
+`)
+	cmd.Example = examplesFor(c, "workload annotate")
+	return cmd
+}
@@ -0,0 +1,471 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestWorkloadMetadataOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name:        "invalid empty",
+			Validatable: &commands.WorkloadMetadataOptions{},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrMissingField(flags.NamespaceFlagName),
+				validation.ErrMissingField(cli.NameArgumentName),
+				validation.ErrMissingField(commands.MetadataArgumentName),
+			),
+		},
+		{
+			Name: "valid",
+			Validatable: &commands.WorkloadMetadataOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Changes:   []string{"team=my-team", "draft-"},
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid changes",
+			Validatable: &commands.WorkloadMetadataOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Changes:   []string{"team", "=my-team"},
+			},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidArrayValue("team", commands.MetadataArgumentName, 0),
+				validation.ErrInvalidArrayValue("=my-team", commands.MetadataArgumentName, 1),
+			),
+		},
+		{
+			Name: "invalid label value",
+			Validatable: &commands.WorkloadMetadataOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Changes:   []string{"team=my team"},
+			},
+			ExpectFieldErrors: validation.K8sLabelValue("my team", validation.CurrentField).ViaFieldIndex(commands.MetadataArgumentName, 0),
+		},
+		{
+			Name: "annotation value",
+			Validatable: &commands.WorkloadMetadataOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Changes:   []string{"description=my team"},
+				Annotate:  true,
+			},
+			ShouldValidate: true,
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestWorkloadLabelCommand(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+
+	parent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+			d.AddLabel("team", "my-team")
+		})
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "invalid args",
+			Args:        []string{workloadName},
+			ShouldError: true,
+		},
+		{
+			Name:         "add and remove labels",
+			Args:         []string{workloadName, "tier=backend", "team-", flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				diecartov1alpha1.WorkloadBlank.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Name(workloadName)
+						d.Namespace(defaultNamespace)
+						d.AddLabel("tier", "backend")
+					}),
+			},
+			ExpectOutput: `
+Update labels of workload "my-workload":
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6     - |    team: my-team
+      6 + |    tier: backend
+  7,  7   |  name: my-workload
+  8,  8   |  namespace: default
+  9,  9   |spec: {}
+
+Updated labels of workload "my-workload"
+`,
+		},
+		{
+			Name:         "add label with audit record",
+			Args:         []string{workloadName, "tier=backend", flags.YesFlagName},
+			Prepare:      prepareAuditor,
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("tier", "backend")
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"alice","time":"2022-06-09T15:12:00Z","command":"label","flags":["--yes"],"version":"v0.9.0"}`)
+					}),
+			},
+			ExpectOutput: `
+Update labels of workload "my-workload":
+...
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6,  6   |    team: my-team
+      7 + |    tier: backend
+  7,  8   |  name: my-workload
+  8,  9   |  namespace: default
+  9, 10   |spec: {}
+
+Updated labels of workload "my-workload"
+`,
+		},
+		{
+			Name:         "add label without audit record",
+			Args:         []string{workloadName, "tier=backend", flags.AuditFlagName + "=false", flags.YesFlagName},
+			Prepare:      prepareAuditor,
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("tier", "backend")
+					}),
+			},
+			ExpectOutput: `
+Update labels of workload "my-workload":
+...
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6,  6   |    team: my-team
+      7 + |    tier: backend
+  7,  8   |  name: my-workload
+  8,  9   |  namespace: default
+  9, 10   |spec: {}
+
+Updated labels of workload "my-workload"
+`,
+		},
+		{
+			Name:         "existing label without overwrite",
+			Args:         []string{workloadName, "team=other-team", flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+			ExpectOutput: `
+Error: label "team" already has a value (my-team), and --overwrite is false
+`,
+		},
+		{
+			Name:         "existing label with overwrite",
+			Args:         []string{workloadName, "team=other-team", flags.OverwriteFlagName, flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("team", "other-team")
+					}),
+			},
+			ExpectOutput: `
+Update labels of workload "my-workload":
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6     - |    team: my-team
+      6 + |    team: other-team
+  7,  7   |  name: my-workload
+  8,  8   |  namespace: default
+  9,  9   |spec: {}
+
+Updated labels of workload "my-workload"
+`,
+		},
+		{
+			Name:         "unchanged",
+			Args:         []string{workloadName, "team=my-team", "tier-", flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ExpectOutput: `
+Workload is unchanged, skipping update
+`,
+		},
+		{
+			Name:        "workload not found",
+			Args:        []string{workloadName, "team=my-team", flags.YesFlagName},
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/my-workload" not found
+`,
+		},
+		{
+			Name:         "update conflict",
+			Args:         []string{workloadName, "tier=backend", flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("update", "Workload", clitesting.InduceFailureOpts{
+					Error: apierrs.NewConflict(schema.GroupResource{Group: "carto.run", Resource: "workloads"}, workloadName, fmt.Errorf("induced conflict")),
+				}),
+			},
+			ShouldError: true,
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("tier", "backend")
+					}),
+			},
+			Verify: verifyExitCode(cli.ExitCodeConflict),
+			ExpectOutput: `
+Update labels of workload "my-workload":
+...
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6,  6   |    team: my-team
+      7 + |    tier: backend
+  7,  8   |  name: my-workload
+  8,  9   |  namespace: default
+  9, 10   |spec: {}
+
+Error: conflict updating workload, the object was modified by another user; please run the label command again
+`,
+		},
+		{
+			Name: "protected namespace",
+			Args: []string{workloadName, "tier=backend", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.ProtectedNamespacesConfigKey, []string{defaultNamespace})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+		},
+		{
+			Name: "set protected label",
+			Args: []string{workloadName, "kapp.k14s.io/app=123", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+			Verify: func(t *testing.T, output string, err error) {
+				msg := `key=value[0]: Forbidden: "kapp.k14s.io/app" uses the prefix "kapp.k14s.io/" which is owned by the platform, changing it may break controllers managing the workload. Use --force to override`
+				if err == nil || err.Error() != msg {
+					t.Errorf("expected error %q, got %v", msg, err)
+				}
+			},
+		},
+		{
+			Name: "remove protected label",
+			Args: []string{workloadName, "kapp.k14s.io/app-", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("kapp.k14s.io/app", "123")
+					}),
+			},
+			ShouldError: true,
+		},
+		{
+			Name: "remove protected label with force",
+			Args: []string{workloadName, "kapp.k14s.io/app-", flags.ForceFlagName, flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("kapp.k14s.io/app", "123")
+					}),
+			},
+			ExpectUpdates: []client.Object{parent},
+			ExpectOutput: `
+Update labels of workload "my-workload":
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6     - |    kapp.k14s.io/app: "123"
+  7,  6   |    team: my-team
+  8,  7   |  name: my-workload
+  9,  8   |  namespace: default
+ 10,  9   |spec: {}
+
+Updated labels of workload "my-workload"
+`,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadLabelCommand(ctx, c)
+	})
+}
+
+func TestWorkloadAnnotateCommand(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+
+	parent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+		})
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:         "add annotation",
+			Args:         []string{workloadName, "description=my workload", flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation("description", "my workload")
+					}),
+			},
+			ExpectOutput: `
+Update annotations of workload "my-workload":
+  1,  1   |---
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+      5 + |  annotations:
+      6 + |    description: my workload
+  5,  7   |  name: my-workload
+  6,  8   |  namespace: default
+  7,  9   |spec: {}
+
+Updated annotations of workload "my-workload"
+`,
+		},
+		{
+			Name: "existing annotation without overwrite",
+			Args: []string{workloadName, "description=other", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation("description", "my workload")
+					}),
+			},
+			ShouldError: true,
+			ExpectOutput: `
+Error: annotation "description" already has a value (my workload), and --overwrite is false
+`,
+		},
+		{
+			Name: "set protected annotation",
+			Args: []string{workloadName, "kapp.k14s.io/identity=v1", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+		},
+		{
+			Name: "remove protected annotation",
+			Args: []string{workloadName, "kapp.k14s.io/identity-", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation("kapp.k14s.io/identity", "v1")
+					}),
+			},
+			ShouldError: true,
+		},
+		{
+			Name: "set protected annotation with force",
+			Args: []string{workloadName, "kapp.k14s.io/identity=v1", flags.ForceFlagName, flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation("kapp.k14s.io/identity", "v1")
+					}),
+			},
+			ExpectOutput: `
+Update annotations of workload "my-workload":
+  1,  1   |---
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+      5 + |  annotations:
+      6 + |    kapp.k14s.io/identity: v1
+  5,  7   |  name: my-workload
+  6,  8   |  namespace: default
+  7,  9   |spec: {}
+
+Updated annotations of workload "my-workload"
+`,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadAnnotateCommand(ctx, c)
+	})
+}
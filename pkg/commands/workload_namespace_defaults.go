@@ -0,0 +1,78 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/parsers"
+)
+
+// NamespaceDefaultsConfigMapName is the name of the optional ConfigMap platform operators create in a
+// namespace with the labels, annotations and params merged into every workload created or applied
+// in the namespace. Its "labels", "annotations" and "params" keys hold "key=value" lines.
+const NamespaceDefaultsConfigMapName = "apps-cli-defaults"
+
+// NamespaceDefaultsFromConfigMap returns the workload defaults held by the ConfigMap
+func NamespaceDefaultsFromConfigMap(cm *corev1.ConfigMap) (WorkloadDefaults, error) {
+	defaults := WorkloadDefaults{}
+	for _, into := range []struct {
+		key string
+		kvs *[]string
+	}{{"labels", &defaults.Labels}, {"annotations", &defaults.Annotations}, {"params", &defaults.Params}} {
+		data, ok := cm.Data[into.key]
+		if !ok {
+			continue
+		}
+		kvs, err := parsers.KeyValueFile([]byte(data), true)
+		if err != nil {
+			return WorkloadDefaults{}, fmt.Errorf("invalid ConfigMap %q: %s: %w", fmt.Sprintf("%s/%s", cm.Namespace, cm.Name), into.key, err)
+		}
+		*into.kvs = kvs
+	}
+	if err := defaults.validate("data").ToAggregate(); err != nil {
+		return WorkloadDefaults{}, fmt.Errorf("invalid ConfigMap %q: %w", fmt.Sprintf("%s/%s", cm.Namespace, cm.Name), err)
+	}
+	return defaults, nil
+}
+
+// applyNamespaceDefaults merges the defaults of the ConfigMap of the namespace of the workload, for
+// the labels, annotations and params not set by the flags, the file or the workload profile. The
+// ConfigMap is optional, as is the permission to read it.
+func applyNamespaceDefaults(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) (context.Context, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: workload.Namespace, Name: NamespaceDefaultsConfigMapName}, cm); err != nil {
+		if apierrs.IsNotFound(err) || apierrs.IsForbidden(err) {
+			return ctx, nil
+		}
+		return ctx, err
+	}
+	defaults, err := NamespaceDefaultsFromConfigMap(cm)
+	if err != nil {
+		return ctx, err
+	}
+	values := newWorkloadDefaultValues()
+	values.collect(defaults, fmt.Sprintf("ConfigMap %q of namespace %q", NamespaceDefaultsConfigMapName, workload.Namespace))
+	return values.merge(ctx, workload), nil
+}
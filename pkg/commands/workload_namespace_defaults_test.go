@@ -0,0 +1,82 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+)
+
+func TestNamespaceDefaultsFromConfigMap(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        map[string]string
+		expected    commands.WorkloadDefaults
+		shouldError bool
+	}{{
+		name: "empty",
+	}, {
+		name: "defaults",
+		data: map[string]string{
+			"labels":      "team=platform\ncost-center = 1234\n",
+			"annotations": "# reached on call\nowner=platform",
+			"params":      "scanning=strict",
+			"unknown":     "ignored",
+		},
+		expected: commands.WorkloadDefaults{
+			Labels:      []string{"cost-center=1234", "team=platform"},
+			Annotations: []string{"owner=platform"},
+			Params:      []string{"scanning=strict"},
+		},
+	}, {
+		name: "invalid line",
+		data: map[string]string{
+			"params": "scanning",
+		},
+		shouldError: true,
+	}, {
+		name: "missing key",
+		data: map[string]string{
+			"annotations": "=platform",
+		},
+		shouldError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      commands.NamespaceDefaultsConfigMapName,
+				},
+				Data: test.data,
+			}
+			actual, err := commands.NamespaceDefaultsFromConfigMap(cm)
+			if (err != nil) != test.shouldError {
+				t.Fatalf("NamespaceDefaultsFromConfigMap() error = %v, expected error %v", err, test.shouldError)
+			}
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Errorf("NamespaceDefaultsFromConfigMap() (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+// types of the patch of --patch-type, named like the ones of kubectl patch
+const (
+	PatchTypeJSON      = "json"
+	PatchTypeMerge     = "merge"
+	PatchTypeStrategic = "strategic"
+)
+
+var patchTypes = []string{PatchTypeJSON, PatchTypeMerge, PatchTypeStrategic}
+
+// WorkloadPatchOptions applies a JSON patch, JSON merge patch or strategic merge patch document to
+// a workload, for changes the flags of workload apply do not cover
+type WorkloadPatchOptions struct {
+	Namespace string
+	Name      string
+	Patch     string
+	PatchFile string
+	PatchType string
+
+	AllowProtected bool
+	Audit          bool
+	Yes            bool
+}
+
+var (
+	_ validation.Validatable = (*WorkloadPatchOptions)(nil)
+	_ cli.Executable         = (*WorkloadPatchOptions)(nil)
+)
+
+func (opts *WorkloadPatchOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Namespace == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+	}
+
+	if opts.Name == "" {
+		errs = errs.Also(validation.ErrMissingField(cli.NameArgumentName))
+	} else {
+		errs = errs.Also(validation.K8sName(opts.Name, cli.NameArgumentName))
+	}
+
+	if opts.Patch == "" && opts.PatchFile == "" {
+		errs = errs.Also(validation.ErrMissingOneOf(flags.PatchFlagName, flags.PatchFileFlagName))
+	}
+	if opts.Patch != "" && opts.PatchFile != "" {
+		errs = errs.Also(validation.ErrMultipleOneOf(flags.PatchFlagName, flags.PatchFileFlagName))
+	}
+	errs = errs.Also(validation.Enum(opts.PatchType, flags.PatchTypeFlagName, patchTypes))
+
+	return errs
+}
+
+func (opts *WorkloadPatchOptions) Exec(ctx context.Context, c *cli.Config) error {
+	if err := validateProtectedNamespace(c, opts.Namespace, opts.AllowProtected).ToAggregate(); err != nil {
+		return err
+	}
+
+	patch, err := opts.readPatch(c.Stdin)
+	if err != nil {
+		return err
+	}
+
+	current := &cartov1alpha1.Workload{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, current); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		c.Errorf("Workload %q not found\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Name))
+		return cli.SilenceError(err)
+	}
+
+	workload, err := opts.apply(current, patch)
+	if err != nil {
+		c.Eprintf("%s unable to patch workload %q: %s\n", printer.Serrorf("Error:"), opts.Name, err)
+		return cli.SilenceError(err)
+	}
+	difference, noChange, err := printer.ResourceDiff(current, workload, c.Scheme)
+	if err != nil {
+		return err
+	}
+	if noChange {
+		c.Infof("Workload is unchanged, skipping update\n")
+		return nil
+	}
+	c.Printf("Patch workload %q:\n", workload.Name)
+	c.Printf("%s\n", difference)
+
+	if !opts.Yes {
+		if opts.PatchFile == "-" {
+			c.Errorf("Skipping workload, cannot confirm intent. Run command with %s flag to confirm intent when providing input from stdin\n", flags.YesFlagName)
+			return nil
+		}
+		okToPatch := false
+		err := survey.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("Really patch the workload %q?", workload.Name),
+		}, &okToPatch, printer.WithSurveyStdio(c.Stdin, c.Stdout, c.Stderr))
+		if err != nil || !okToPatch {
+			c.Infof("Skipping workload %q\n", workload.Name)
+			return nil
+		}
+	}
+
+	if err := auditWorkload(ctx, workload, opts.Audit); err != nil {
+		return err
+	}
+	if err := c.Update(ctx, workload); err != nil {
+		if apierrs.IsConflict(err) {
+			c.Printf("%s conflict updating workload, the object was modified by another user; please run the patch command again\n", printer.Serrorf("Error:"))
+			return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeConflict))
+		}
+		return err
+	}
+	c.Successf("Patched workload %q\n", workload.Name)
+	return nil
+}
+
+// readPatch returns the patch of --patch or of the file of --patch-file, converted to JSON when
+// written in YAML
+func (opts *WorkloadPatchOptions) readPatch(stdin io.Reader) ([]byte, error) {
+	content := []byte(opts.Patch)
+	if opts.PatchFile != "" {
+		var err error
+		if opts.PatchFile == "-" {
+			content, err = io.ReadAll(stdin)
+		} else {
+			content, err = os.ReadFile(opts.PatchFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read patch file %q: %w", opts.PatchFile, err)
+		}
+	}
+	patch, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse patch: %w", err)
+	}
+	return patch, nil
+}
+
+// apply patches a copy of the workload. Only the labels, annotations and spec of the patched
+// workload are kept, the rest of the metadata and the status are managed by the cluster.
+func (opts *WorkloadPatchOptions) apply(current *cartov1alpha1.Workload, patch []byte) (*cartov1alpha1.Workload, error) {
+	original, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch opts.PatchType {
+	case PatchTypeJSON:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, err
+		}
+		if patched, err = operations.Apply(original); err != nil {
+			return nil, err
+		}
+	case PatchTypeMerge:
+		if patched, err = jsonpatch.MergePatch(original, patch); err != nil {
+			return nil, err
+		}
+	default:
+		if patched, err = strategicpatch.StrategicMergePatch(original, patch, &cartov1alpha1.Workload{}); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &cartov1alpha1.Workload{}
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	// a typo in the patch would otherwise be silently dropped
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(result); err != nil {
+		return nil, err
+	}
+	if result.Name != current.Name || result.Namespace != current.Namespace {
+		return nil, fmt.Errorf("the name and namespace of the workload cannot be patched")
+	}
+
+	workload := current.DeepCopy()
+	workload.Labels = result.Labels
+	workload.Annotations = result.Annotations
+	workload.Spec = result.Spec
+	return workload, nil
+}
+
+func NewWorkloadPatchCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	opts := &WorkloadPatchOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "patch",
+		Short: "Patch a workload with a JSON or strategic merge patch",
+		Long: strings.TrimSpace(`
+Patch a workload with a JSON patch, a JSON merge patch or a strategic merge patch,
+like kubectl patch, for changes the flags of workload apply do not cover. The patch
+is set with --patch or read from the file of --patch-file, in JSON or YAML.
+
+Only the labels, annotations and spec of the workload are patched. The change is
+shown and confirmed before the workload is updated.
+`),
+		Example:           examplesFor(c, "workload patch"),
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		cli.NameArg(&opts.Name),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVarP(&opts.Patch, cli.StripDash(flags.PatchFlagName), "p", "", "the `patch` to apply to the workload, in JSON or YAML")
+	cmd.Flags().StringVar(&opts.PatchFile, cli.StripDash(flags.PatchFileFlagName), "", "`file path` of the patch to apply to the workload, in JSON or YAML (\"-\" for stdin)")
+	cmd.MarkFlagFilename(cli.StripDash(flags.PatchFileFlagName), ".json", ".yaml", ".yml")
+	cmd.Flags().StringVar(&opts.PatchType, cli.StripDash(flags.PatchTypeFlagName), PatchTypeStrategic, fmt.Sprintf("`type` of the patch, one of %s", strings.Join(patchTypes, ", ")))
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.PatchTypeFlagName), func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return patchTypes, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().BoolVar(&opts.AllowProtected, cli.StripDash(flags.AllowProtectedFlagName), false, "allow updating a workload in a namespace protected by the plugin config")
+	auditFlag(cmd, &opts.Audit)
+	cmd.Flags().BoolVarP(&opts.Yes, cli.StripDash(flags.YesFlagName), "y", false, "accept all prompts")
+
+	return cmd
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestWorkloadPatchOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name:        "invalid empty",
+			Validatable: &commands.WorkloadPatchOptions{},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrMissingField(flags.NamespaceFlagName),
+				validation.ErrMissingField(cli.NameArgumentName),
+				validation.ErrMissingOneOf(flags.PatchFlagName, flags.PatchFileFlagName),
+				validation.EnumInvalidValue("", flags.PatchTypeFlagName, []string{commands.PatchTypeJSON, commands.PatchTypeMerge, commands.PatchTypeStrategic}),
+			),
+		},
+		{
+			Name: "valid",
+			Validatable: &commands.WorkloadPatchOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Patch:     `{"spec":{"serviceAccountName":"my-sa"}}`,
+				PatchType: commands.PatchTypeStrategic,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "patch and patch file",
+			Validatable: &commands.WorkloadPatchOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Patch:     `{"spec":{"serviceAccountName":"my-sa"}}`,
+				PatchFile: "patch.yaml",
+				PatchType: commands.PatchTypeMerge,
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.PatchFlagName, flags.PatchFileFlagName),
+		},
+		{
+			Name: "invalid patch type",
+			Validatable: &commands.WorkloadPatchOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				PatchFile: "patch.yaml",
+				PatchType: "apply",
+			},
+			ExpectFieldErrors: validation.EnumInvalidValue("apply", flags.PatchTypeFlagName, []string{commands.PatchTypeJSON, commands.PatchTypeMerge, commands.PatchTypeStrategic}),
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestWorkloadPatchCommand(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+
+	parent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+			d.AddLabel("team", "my-team")
+		}).
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Image("registry.example/my-workload:v1")
+		})
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "invalid args",
+			Args:        []string{flags.PatchFlagName, `{"spec":{"serviceAccountName":"my-sa"}}`},
+			ShouldError: true,
+		},
+		{
+			Name:         "strategic merge patch",
+			Args:         []string{workloadName, flags.PatchFlagName, `{"spec":{"serviceAccountName":"my-sa"}}`, flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					DieStamp(func(r *cartov1alpha1.Workload) {
+						r.Spec.MergeServiceAccountName("my-sa")
+					}),
+			},
+			ExpectOutput: `
+Patch workload "my-workload":
+...
+  7,  7   |  name: my-workload
+  8,  8   |  namespace: default
+  9,  9   |spec:
+ 10, 10   |  image: registry.example/my-workload:v1
+     11 + |  serviceAccountName: my-sa
+
+Patched workload "my-workload"
+`,
+		},
+		{
+			Name:         "patch with audit record",
+			Args:         []string{workloadName, flags.PatchFlagName, `{"spec":{"serviceAccountName":"my-sa"}}`, flags.YesFlagName},
+			Prepare:      prepareAuditor,
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"alice","time":"2022-06-09T15:12:00Z","command":"patch","flags":["--patch","--yes"],"version":"v0.9.0"}`)
+					}).
+					DieStamp(func(r *cartov1alpha1.Workload) {
+						r.Spec.MergeServiceAccountName("my-sa")
+					}),
+			},
+			ExpectOutput: `
+Patch workload "my-workload":
+...
+  7,  7   |  name: my-workload
+  8,  8   |  namespace: default
+  9,  9   |spec:
+ 10, 10   |  image: registry.example/my-workload:v1
+     11 + |  serviceAccountName: my-sa
+
+Patched workload "my-workload"
+`,
+		},
+		{
+			Name:         "merge patch",
+			Args:         []string{workloadName, flags.PatchTypeFlagName, commands.PatchTypeMerge, flags.PatchFlagName, `metadata: {labels: {team: null, tier: backend}}`, flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.Labels(map[string]string{"tier": "backend"})
+					}),
+			},
+			ExpectOutput: `
+Patch workload "my-workload":
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6     - |    team: my-team
+      6 + |    tier: backend
+  7,  7   |  name: my-workload
+  8,  8   |  namespace: default
+  9,  9   |spec:
+ 10, 10   |  image: registry.example/my-workload:v1
+
+Patched workload "my-workload"
+`,
+		},
+		{
+			Name: "json patch from stdin",
+			Args: []string{workloadName, flags.PatchTypeFlagName, commands.PatchTypeJSON, flags.PatchFileFlagName, "-", flags.YesFlagName},
+			Stdin: []byte(`
+- op: replace
+  path: /spec/image
+  value: registry.example/my-workload:v2
+`),
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("registry.example/my-workload:v2")
+					}),
+			},
+			ExpectOutput: `
+Patch workload "my-workload":
+...
+  6,  6   |    team: my-team
+  7,  7   |  name: my-workload
+  8,  8   |  namespace: default
+  9,  9   |spec:
+ 10     - |  image: registry.example/my-workload:v1
+     10 + |  image: registry.example/my-workload:v2
+
+Patched workload "my-workload"
+`,
+		},
+		{
+			Name: "patch from stdin without yes",
+			Args: []string{workloadName, flags.PatchFileFlagName, "-"},
+			Stdin: []byte(`
+spec:
+  serviceAccountName: my-sa
+`),
+			GivenObjects: []client.Object{parent},
+			ExpectOutput: `
+Patch workload "my-workload":
+...
+  7,  7   |  name: my-workload
+  8,  8   |  namespace: default
+  9,  9   |spec:
+ 10, 10   |  image: registry.example/my-workload:v1
+     11 + |  serviceAccountName: my-sa
+
+Skipping workload, cannot confirm intent. Run command with --yes flag to confirm intent when providing input from stdin
+`,
+		},
+		{
+			Name:         "unchanged",
+			Args:         []string{workloadName, flags.PatchFlagName, `{"metadata":{"labels":{"team":"my-team"}}}`, flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ExpectOutput: `
+Workload is unchanged, skipping update
+`,
+		},
+		{
+			Name:        "workload not found",
+			Args:        []string{workloadName, flags.PatchFlagName, `{"spec":{"serviceAccountName":"my-sa"}}`, flags.YesFlagName},
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/my-workload" not found
+`,
+		},
+		{
+			Name:         "unknown field",
+			Args:         []string{workloadName, flags.PatchFlagName, `{"spec":{"serviceAcountName":"my-sa"}}`, flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+			ExpectOutput: `
+Error: unable to patch workload "my-workload": json: unknown field "serviceAcountName"
+`,
+		},
+		{
+			Name:         "rename",
+			Args:         []string{workloadName, flags.PatchTypeFlagName, commands.PatchTypeJSON, flags.PatchFlagName, `[{"op":"replace","path":"/metadata/name","value":"other-workload"}]`, flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+			ExpectOutput: `
+Error: unable to patch workload "my-workload": the name and namespace of the workload cannot be patched
+`,
+		},
+		{
+			Name:         "invalid patch",
+			Args:         []string{workloadName, flags.PatchFlagName, `{"spec":`, flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+		},
+		{
+			Name:         "update conflict",
+			Args:         []string{workloadName, flags.PatchFlagName, `{"spec":{"serviceAccountName":"my-sa"}}`, flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("update", "Workload", clitesting.InduceFailureOpts{
+					Error: apierrs.NewConflict(schema.GroupResource{Group: "carto.run", Resource: "workloads"}, workloadName, fmt.Errorf("induced conflict")),
+				}),
+			},
+			ShouldError: true,
+			ExpectUpdates: []client.Object{
+				parent.
+					DieStamp(func(r *cartov1alpha1.Workload) {
+						r.Spec.MergeServiceAccountName("my-sa")
+					}),
+			},
+			Verify: verifyExitCode(cli.ExitCodeConflict),
+			ExpectOutput: `
+Patch workload "my-workload":
+...
+  7,  7   |  name: my-workload
+  8,  8   |  namespace: default
+  9,  9   |spec:
+ 10, 10   |  image: registry.example/my-workload:v1
+     11 + |  serviceAccountName: my-sa
+
+Error: conflict updating workload, the object was modified by another user; please run the patch command again
+`,
+		},
+		{
+			Name: "protected namespace",
+			Args: []string{workloadName, flags.PatchFlagName, `{"spec":{"serviceAccountName":"my-sa"}}`, flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.ProtectedNamespacesConfigKey, []string{defaultNamespace})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadPatchCommand(ctx, c)
+	})
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+// WorkloadPauseOptions sets the paused param of the workload, or removes it with Resume, for supply
+// chains to stop reconciling the workload during a maintenance window
+type WorkloadPauseOptions struct {
+	Namespace string
+	Name      string
+
+	Resume         bool
+	AllowProtected bool
+	Audit          bool
+	Yes            bool
+}
+
+var (
+	_ validation.Validatable = (*WorkloadPauseOptions)(nil)
+	_ cli.Executable         = (*WorkloadPauseOptions)(nil)
+)
+
+func (opts *WorkloadPauseOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Namespace == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+	}
+
+	if opts.Name == "" {
+		errs = errs.Also(validation.ErrMissingField(cli.NameArgumentName))
+	} else {
+		errs = errs.Also(validation.K8sName(opts.Name, cli.NameArgumentName))
+	}
+
+	return errs
+}
+
+func (opts *WorkloadPauseOptions) Exec(ctx context.Context, c *cli.Config) error {
+	if err := validateProtectedNamespace(c, opts.Namespace, opts.AllowProtected).ToAggregate(); err != nil {
+		return err
+	}
+
+	current := &cartov1alpha1.Workload{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, current); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		c.Errorf("Workload %q not found\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Name))
+		return cli.SilenceError(err)
+	}
+
+	if current.Spec.IsPaused() != opts.Resume {
+		if opts.Resume {
+			c.Infof("Workload %q is not paused, skipping update\n", opts.Name)
+		} else {
+			c.Infof("Workload %q is already paused, skipping update\n", opts.Name)
+		}
+		return nil
+	}
+
+	workload := current.DeepCopy()
+	workload.Spec.MergePaused(!opts.Resume)
+	difference, _, err := printer.ResourceDiff(current, workload, c.Scheme)
+	if err != nil {
+		return err
+	}
+	c.Printf("Update workload %q:\n", workload.Name)
+	c.Printf("%s\n", difference)
+
+	if !opts.Yes {
+		okToUpdate := false
+		err := survey.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("Really %s workload %q?", opts.verb(), workload.Name),
+		}, &okToUpdate, printer.WithSurveyStdio(c.Stdin, c.Stdout, c.Stderr))
+		if err != nil || !okToUpdate {
+			c.Infof("Skipping workload %q\n", workload.Name)
+			return nil
+		}
+	}
+
+	if err := auditWorkload(ctx, workload, opts.Audit); err != nil {
+		return err
+	}
+	if err := c.Update(ctx, workload); err != nil {
+		if apierrs.IsConflict(err) {
+			c.Printf("%s conflict updating workload, the object was modified by another user; please run the %s command again\n", printer.Serrorf("Error:"), opts.verb())
+			return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeConflict))
+		}
+		return err
+	}
+	if opts.Resume {
+		c.Successf("Resumed workload %q\n", workload.Name)
+	} else {
+		c.Successf("Paused workload %q\n", workload.Name)
+	}
+	return nil
+}
+
+func (opts *WorkloadPauseOptions) verb() string {
+	if opts.Resume {
+		return "resume"
+	}
+	return "pause"
+}
+
+func newWorkloadPauseCommand(ctx context.Context, c *cli.Config, opts *WorkloadPauseOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		cli.NameArg(&opts.Name),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().BoolVar(&opts.AllowProtected, cli.StripDash(flags.AllowProtectedFlagName), false, "allow updating a workload in a namespace protected by the plugin config")
+	auditFlag(cmd, &opts.Audit)
+	cmd.Flags().BoolVarP(&opts.Yes, cli.StripDash(flags.YesFlagName), "y", false, "accept all prompts")
+
+	return cmd
+}
+
+func NewWorkloadPauseCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	cmd := newWorkloadPauseCommand(ctx, c, &WorkloadPauseOptions{})
+	cmd.Use = "pause"
+	cmd.Short = "Pause the reconciliation of a workload"
+	cmd.Long = strings.TrimSpace(`
+Pause the reconciliation of a workload, for example during a maintenance window,
+by setting its "paused" param to true. Supply chains honoring the param stop
+updating the resources of the workload until it is resumed with workload resume.
+
+The change is shown and confirmed before the workload is updated. A paused
+workload is marked as such in the overview of workload get.
+`)
+	cmd.Example = examplesFor(c, "workload pause")
+	return cmd
+}
+
+func NewWorkloadResumeCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	cmd := newWorkloadPauseCommand(ctx, c, &WorkloadPauseOptions{Resume: true})
+	cmd.Use = "resume"
+	cmd.Short = "Resume the reconciliation of a paused workload"
+	cmd.Long = strings.TrimSpace(`
+Resume the reconciliation of a workload paused with workload pause, by removing
+its "paused" param.
+
+The change is shown and confirmed before the workload is updated.
+`)
+	cmd.Example = examplesFor(c, "workload resume")
+	return cmd
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestWorkloadPauseOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name:        "invalid empty",
+			Validatable: &commands.WorkloadPauseOptions{},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrMissingField(flags.NamespaceFlagName),
+				validation.ErrMissingField(cli.NameArgumentName),
+			),
+		},
+		{
+			Name: "valid",
+			Validatable: &commands.WorkloadPauseOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+			},
+			ShouldValidate: true,
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestWorkloadPauseCommand(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+
+	parent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+		})
+	paused := parent.
+		DieStamp(func(r *cartov1alpha1.Workload) {
+			r.Spec.MergePaused(true)
+		})
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "invalid args",
+			Args:        []string{},
+			ShouldError: true,
+		},
+		{
+			Name:          "pause",
+			Args:          []string{workloadName, flags.YesFlagName},
+			GivenObjects:  []client.Object{parent},
+			ExpectUpdates: []client.Object{paused},
+			ExpectOutput: `
+Update workload "my-workload":
+...
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7     - |spec: {}
+      7 + |spec:
+      8 + |  params:
+      9 + |  - name: paused
+     10 + |    value: true
+
+Paused workload "my-workload"
+`,
+		},
+		{
+			Name:         "pause with audit record",
+			Args:         []string{workloadName, flags.YesFlagName},
+			Prepare:      prepareAuditor,
+			GivenObjects: []client.Object{parent},
+			ExpectUpdates: []client.Object{
+				paused.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"alice","time":"2022-06-09T15:12:00Z","command":"pause","flags":["--yes"],"version":"v0.9.0"}`)
+					}),
+			},
+			ExpectOutput: `
+Update workload "my-workload":
+...
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7     - |spec: {}
+      7 + |spec:
+      8 + |  params:
+      9 + |  - name: paused
+     10 + |    value: true
+
+Paused workload "my-workload"
+`,
+		},
+		{
+			Name:         "already paused",
+			Args:         []string{workloadName, flags.YesFlagName},
+			GivenObjects: []client.Object{paused},
+			ExpectOutput: `
+Workload "my-workload" is already paused, skipping update
+`,
+		},
+		{
+			Name:        "workload not found",
+			Args:        []string{workloadName, flags.YesFlagName},
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/my-workload" not found
+`,
+		},
+		{
+			Name:         "update conflict",
+			Args:         []string{workloadName, flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("update", "Workload", clitesting.InduceFailureOpts{
+					Error: apierrs.NewConflict(schema.GroupResource{Group: "carto.run", Resource: "workloads"}, workloadName, fmt.Errorf("induced conflict")),
+				}),
+			},
+			ShouldError:   true,
+			ExpectUpdates: []client.Object{paused},
+			Verify:        verifyExitCode(cli.ExitCodeConflict),
+			ExpectOutput: `
+Update workload "my-workload":
+...
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7     - |spec: {}
+      7 + |spec:
+      8 + |  params:
+      9 + |  - name: paused
+     10 + |    value: true
+
+Error: conflict updating workload, the object was modified by another user; please run the pause command again
+`,
+		},
+		{
+			Name: "protected namespace",
+			Args: []string{workloadName, flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.ProtectedNamespacesConfigKey, []string{defaultNamespace})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadPauseCommand(ctx, c)
+	})
+}
+
+func TestWorkloadResumeCommand(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+
+	parent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+		})
+	paused := parent.
+		DieStamp(func(r *cartov1alpha1.Workload) {
+			r.Spec.MergePaused(true)
+		})
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:         "resume",
+			Args:         []string{workloadName, flags.YesFlagName},
+			GivenObjects: []client.Object{paused},
+			ExpectUpdates: []client.Object{
+				parent.
+					DieStamp(func(r *cartov1alpha1.Workload) {
+						r.Spec.Params = []cartov1alpha1.Param{}
+					}),
+			},
+			ExpectOutput: `
+Update workload "my-workload":
+...
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7     - |spec:
+  8     - |  params:
+  9     - |  - name: paused
+ 10     - |    value: true
+      7 + |spec: {}
+
+Resumed workload "my-workload"
+`,
+		},
+		{
+			Name:         "not paused",
+			Args:         []string{workloadName, flags.YesFlagName},
+			GivenObjects: []client.Object{parent},
+			ExpectOutput: `
+Workload "my-workload" is not paused, skipping update
+`,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadResumeCommand(ctx, c)
+	})
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/viper"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/parsers"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+)
+
+// WorkloadProfileConfigKey is the plugin config key holding the defaults merged into every
+// created or applied workload
+const WorkloadProfileConfigKey = "workload-profile"
+
+// WorkloadDefaults are "key=value" labels, annotations and params, in the format of the
+// matching workload flags
+type WorkloadDefaults struct {
+	Labels      []string `mapstructure:"labels"`
+	Annotations []string `mapstructure:"annotations"`
+	Params      []string `mapstructure:"params"`
+}
+
+// WorkloadProfile holds the defaults of every namespace and the defaults of single namespaces,
+// which win over the former for the same key
+type WorkloadProfile struct {
+	WorkloadDefaults `mapstructure:",squash"`
+	Namespaces       map[string]WorkloadDefaults `mapstructure:"namespaces"`
+}
+
+// WorkloadProfileFromConfig returns the workload profile declared in the plugin config
+func WorkloadProfileFromConfig(v *viper.Viper) (WorkloadProfile, error) {
+	profile := WorkloadProfile{}
+	if v == nil || !v.IsSet(WorkloadProfileConfigKey) {
+		return profile, nil
+	}
+	if err := v.UnmarshalKey(WorkloadProfileConfigKey, &profile); err != nil {
+		return WorkloadProfile{}, fmt.Errorf("invalid %s: %w", WorkloadProfileConfigKey, err)
+	}
+	if err := profile.Validate(); err != nil {
+		return WorkloadProfile{}, err
+	}
+	return profile, nil
+}
+
+// Validate checks every default is a "key=value" pair
+func (p WorkloadProfile) Validate() error {
+	errs := p.WorkloadDefaults.validate(WorkloadProfileConfigKey)
+	for namespace, defaults := range p.Namespaces {
+		errs = errs.Also(defaults.validate(fmt.Sprintf("%s.namespaces.%s", WorkloadProfileConfigKey, namespace)))
+	}
+	if err := errs.ToAggregate(); err != nil {
+		return fmt.Errorf("invalid %s: %w", WorkloadProfileConfigKey, err)
+	}
+	return nil
+}
+
+func (d WorkloadDefaults) validate(field string) validation.FieldErrors {
+	return validation.FieldErrors{}.Also(
+		validation.KeyValues(d.Labels, field+".labels"),
+		validation.KeyValues(d.Annotations, field+".annotations"),
+		validation.KeyValues(d.Params, field+".params"),
+	)
+}
+
+// Apply merges the defaults of the namespace of the workload for the labels, annotations and
+// params the workload does not already set. Each merged value is stashed as a workload notice
+// naming where it comes from, to be shown with the diff of the workload.
+func (p WorkloadProfile) Apply(ctx context.Context, workload *cartov1alpha1.Workload) context.Context {
+	values := newWorkloadDefaultValues()
+	values.collect(p.WorkloadDefaults, fmt.Sprintf("the %s of the plugin config for all namespaces", WorkloadProfileConfigKey))
+	if defaults, ok := p.Namespaces[workload.Namespace]; ok {
+		values.collect(defaults, fmt.Sprintf("the %s of the plugin config for namespace %q", WorkloadProfileConfigKey, workload.Namespace))
+	}
+	return values.merge(ctx, workload)
+}
+
+// workloadDefaultValues are the default labels, annotations and params by key, with the origin of
+// each value for its notice
+type workloadDefaultValues struct {
+	labels      map[string]workloadDefaultValue
+	annotations map[string]workloadDefaultValue
+	params      map[string]workloadDefaultValue
+}
+
+type workloadDefaultValue struct {
+	value  string
+	origin string
+}
+
+func newWorkloadDefaultValues() *workloadDefaultValues {
+	return &workloadDefaultValues{
+		labels:      map[string]workloadDefaultValue{},
+		annotations: map[string]workloadDefaultValue{},
+		params:      map[string]workloadDefaultValue{},
+	}
+}
+
+// collect adds the defaults, replacing the values collected before for the same keys
+func (v *workloadDefaultValues) collect(defaults WorkloadDefaults, origin string) {
+	for _, into := range []struct {
+		kvs     []string
+		entries map[string]workloadDefaultValue
+	}{{defaults.Labels, v.labels}, {defaults.Annotations, v.annotations}, {defaults.Params, v.params}} {
+		for _, kv := range into.kvs {
+			parts := parsers.KeyValue(kv)
+			into.entries[parts[0]] = workloadDefaultValue{value: parts[1], origin: origin}
+		}
+	}
+}
+
+// merge sets the collected values the workload does not already set, stashing a notice for each
+func (v *workloadDefaultValues) merge(ctx context.Context, workload *cartov1alpha1.Workload) context.Context {
+	for _, kind := range []struct {
+		name    string
+		entries map[string]workloadDefaultValue
+		isSet   func(key string) bool
+		merge   func(key, value string)
+	}{{
+		name:    "label",
+		entries: v.labels,
+		isSet:   func(key string) bool { _, ok := workload.Labels[key]; return ok },
+		merge:   workload.MergeLabels,
+	}, {
+		name:    "annotation",
+		entries: v.annotations,
+		isSet:   func(key string) bool { _, ok := workload.Annotations[key]; return ok },
+		merge:   workload.MergeAnnotations,
+	}, {
+		name:    "param",
+		entries: v.params,
+		isSet:   workload.Spec.HasParam,
+		merge:   func(key, value string) { workload.Spec.MergeParams(key, value) },
+	}} {
+		keys := make([]string, 0, len(kind.entries))
+		for key := range kind.entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if kind.isSet(key) {
+				continue
+			}
+			e := kind.entries[key]
+			kind.merge(key, e.value)
+			ctx = cartov1alpha1.StashWorkloadNotice(ctx, fmt.Sprintf("%s %q set to %q by %s.", kind.name, key, e.value, e.origin))
+		}
+	}
+	return ctx
+}
+
+// applyWorkloadProfile merges the workload profile of the plugin config into the workload
+func applyWorkloadProfile(ctx context.Context, v *viper.Viper, workload *cartov1alpha1.Workload) (context.Context, error) {
+	profile, err := WorkloadProfileFromConfig(v)
+	if err != nil {
+		return ctx, err
+	}
+	return profile.Apply(ctx, workload), nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+)
+
+func TestWorkloadProfileFromConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      interface{}
+		expected    commands.WorkloadProfile
+		shouldError bool
+	}{{
+		name: "no profile",
+	}, {
+		name: "profile",
+		config: map[string]interface{}{
+			"labels":      []string{"cost-center=1234"},
+			"annotations": []string{"owner=platform"},
+			"namespaces": map[string]interface{}{
+				"dev": map[string]interface{}{
+					"params": []string{"scanning=relaxed"},
+				},
+			},
+		},
+		expected: commands.WorkloadProfile{
+			WorkloadDefaults: commands.WorkloadDefaults{
+				Labels:      []string{"cost-center=1234"},
+				Annotations: []string{"owner=platform"},
+			},
+			Namespaces: map[string]commands.WorkloadDefaults{
+				"dev": {Params: []string{"scanning=relaxed"}},
+			},
+		},
+	}, {
+		name: "invalid label",
+		config: map[string]interface{}{
+			"labels": []string{"cost-center"},
+		},
+		shouldError: true,
+	}, {
+		name: "invalid namespace param",
+		config: map[string]interface{}{
+			"namespaces": map[string]interface{}{
+				"dev": map[string]interface{}{
+					"params": []string{"=relaxed"},
+				},
+			},
+		},
+		shouldError: true,
+	}, {
+		name:        "invalid shape",
+		config:      []string{"cost-center=1234"},
+		shouldError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := viper.New()
+			if test.config != nil {
+				v.Set(commands.WorkloadProfileConfigKey, test.config)
+			}
+			profile, err := commands.WorkloadProfileFromConfig(v)
+			if (err != nil) != test.shouldError {
+				t.Errorf("WorkloadProfileFromConfig() error = %v, shouldError %v", err, test.shouldError)
+			}
+			if diff := cmp.Diff(test.expected, profile); diff != "" {
+				t.Errorf("WorkloadProfileFromConfig() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkloadProfileApply(t *testing.T) {
+	profile := commands.WorkloadProfile{
+		WorkloadDefaults: commands.WorkloadDefaults{
+			Labels:      []string{"cost-center=1234", "team=platform"},
+			Annotations: []string{"owner=platform"},
+			Params:      []string{"scanning=strict"},
+		},
+		Namespaces: map[string]commands.WorkloadDefaults{
+			"dev": {Labels: []string{"cost-center=5678"}},
+		},
+	}
+	workload := &cartov1alpha1.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "dev",
+			Name:      "my-workload",
+			Labels:    map[string]string{"team": "payments"},
+		},
+		Spec: cartov1alpha1.WorkloadSpec{
+			Params: []cartov1alpha1.Param{
+				{Name: "scanning", Value: apiextensionsv1.JSON{Raw: []byte(`"relaxed"`)}},
+			},
+		},
+	}
+	expected := workload.DeepCopy()
+	expected.Labels["cost-center"] = "5678"
+	expected.Annotations = map[string]string{"owner": "platform"}
+
+	ctx := profile.Apply(context.Background(), workload)
+	if diff := cmp.Diff(expected, workload); diff != "" {
+		t.Errorf("Apply() (-want, +got) = %s", diff)
+	}
+	expectedNotices := []string{
+		`label "cost-center" set to "5678" by the workload-profile of the plugin config for namespace "dev".`,
+		`annotation "owner" set to "platform" by the workload-profile of the plugin config for all namespaces.`,
+	}
+	if diff := cmp.Diff(expectedNotices, cartov1alpha1.RetrieveWorkloadNotices(ctx)); diff != "" {
+		t.Errorf("Apply() notices (-want, +got) = %s", diff)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/wait"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+const conditionEventPhase = "condition"
+
+// conditionEvent is the machine readable transition of a condition of a workload, printed with the
+// progress events
+type conditionEvent struct {
+	Phase          string                 `json:"phase"`
+	Workload       string                 `json:"workload"`
+	Type           string                 `json:"type"`
+	Status         metav1.ConditionStatus `json:"status"`
+	PreviousStatus metav1.ConditionStatus `json:"previousStatus,omitempty"`
+	Reason         string                 `json:"reason,omitempty"`
+	Message        string                 `json:"message,omitempty"`
+}
+
+// conditionProgress observes a workload while waiting for it, reporting each condition that appears or
+// changes status since the workload was applied, so users can see where the supply chain is stuck.
+// Nothing is reported while tailing, the logs show the progress.
+type conditionProgress struct {
+	m        sync.Mutex
+	c        *cli.Config
+	disabled bool
+	json     bool
+	statuses map[string]metav1.ConditionStatus
+}
+
+func (opts *WorkloadOptions) newConditionProgress(c *cli.Config, workload *cartov1alpha1.Workload) *conditionProgress {
+	p := &conditionProgress{
+		c:        c,
+		disabled: opts.Tail || opts.TailTimestamps,
+		json:     opts.Output == printer.OutputFormatJson,
+		statuses: map[string]metav1.ConditionStatus{},
+	}
+	for _, condition := range workload.Status.Conditions {
+		p.statuses[condition.Type] = condition.Status
+	}
+	return p
+}
+
+// Condition wraps the condition waited for, reporting the transitions of each version of the workload
+// before the condition is checked
+func (p *conditionProgress) Condition(condition wait.ConditionFunc) wait.ConditionFunc {
+	if p.disabled {
+		return condition
+	}
+	return func(obj client.Object) (bool, error) {
+		if workload, ok := obj.(*cartov1alpha1.Workload); ok {
+			p.observe(workload)
+		}
+		return condition(obj)
+	}
+}
+
+func (p *conditionProgress) observe(workload *cartov1alpha1.Workload) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	for _, condition := range workload.Status.Conditions {
+		previous, seen := p.statuses[condition.Type]
+		if seen && previous == condition.Status {
+			continue
+		}
+		p.statuses[condition.Type] = condition.Status
+
+		transition := string(condition.Status)
+		if seen {
+			transition = fmt.Sprintf("%s → %s", previous, condition.Status)
+		}
+		reason := ""
+		if condition.Reason != "" {
+			reason = fmt.Sprintf(" (%s)", condition.Reason)
+		}
+		p.c.Infof("  %s=%s%s\n", condition.Type, transition, reason)
+
+		if p.json {
+			// progress is best effort, a failed write must not fail the command
+			_ = json.NewEncoder(p.c.Stderr).Encode(conditionEvent{
+				Phase:          conditionEventPhase,
+				Workload:       workload.Name,
+				Type:           condition.Type,
+				Status:         condition.Status,
+				PreviousStatus: previous,
+				Reason:         condition.Reason,
+				Message:        condition.Message,
+			})
+		}
+	}
+}
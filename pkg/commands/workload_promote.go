@@ -0,0 +1,202 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+// WorkloadPromoteOptions applies a workload of the current cluster to the cluster of another kube
+// config context, for a promotion between environments without a GitOps repository
+type WorkloadPromoteOptions struct {
+	Namespace       string
+	TargetNamespace string
+	Name            string
+	ToContext       string
+
+	AllowProtected bool
+	Audit          bool
+	Yes            bool
+}
+
+var (
+	_ validation.Validatable = (*WorkloadPromoteOptions)(nil)
+	_ cli.Executable         = (*WorkloadPromoteOptions)(nil)
+)
+
+func (opts *WorkloadPromoteOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Namespace == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+	}
+	if opts.TargetNamespace != "" {
+		errs = errs.Also(validation.K8sName(opts.TargetNamespace, flags.TargetNamespaceFlagName))
+	}
+
+	if opts.Name == "" {
+		errs = errs.Also(validation.ErrMissingField(cli.NameArgumentName))
+	} else {
+		errs = errs.Also(validation.K8sName(opts.Name, cli.NameArgumentName))
+	}
+	if opts.ToContext == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.ToContextFlagName))
+	}
+
+	return errs
+}
+
+func (opts *WorkloadPromoteOptions) Exec(ctx context.Context, c *cli.Config) error {
+	if err := validateProtectedNamespace(c, opts.targetNamespace(), opts.AllowProtected).ToAggregate(); err != nil {
+		return err
+	}
+
+	source := &cartov1alpha1.Workload{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, source); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		c.Errorf("Workload %q not found\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Name))
+		return cli.SilenceError(err)
+	}
+	desired := sanitizeWorkload(c, source, opts.targetNamespace(), source.Name)
+
+	target := c.ClientForContext(opts.ToContext)
+	current := &cartov1alpha1.Workload{}
+	if err := target.Get(ctx, client.ObjectKey{Namespace: desired.Namespace, Name: desired.Name}, current); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		current = nil
+	}
+
+	// the diff is against the target cluster, only the fields that are promoted are replaced. The labels
+	// and annotations are merged, so the ones set on the target by its controllers are kept.
+	var diff string
+	var noChange bool
+	var err error
+	workload := desired
+	if current == nil {
+		diff, noChange, err = printer.ResourceDiff(nil, workload, c.Scheme)
+	} else {
+		workload = current.DeepCopy()
+		workload.Labels = mergeStringMaps(workload.Labels, desired.Labels)
+		workload.Annotations = mergeStringMaps(workload.Annotations, desired.Annotations)
+		workload.Spec = desired.Spec
+		diff, noChange, err = printer.ResourceDiff(current, workload, c.Scheme)
+	}
+	if err != nil {
+		return err
+	}
+	if noChange {
+		c.Infof("Workload %q is already up to date in context %q\n", workload.Name, opts.ToContext)
+		return nil
+	}
+
+	c.Printf("Promote workload %q to %q in context %q:\n", fmt.Sprintf("%s/%s", source.Namespace, source.Name), fmt.Sprintf("%s/%s", workload.Namespace, workload.Name), opts.ToContext)
+	c.Printf("%s\n", diff)
+
+	if !opts.Yes {
+		okToPromote := false
+		err := survey.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("Really promote the workload %q to context %q?", workload.Name, opts.ToContext),
+		}, &okToPromote, printer.WithSurveyStdio(c.Stdin, c.Stdout, c.Stderr))
+		if err != nil || !okToPromote {
+			c.Infof("Skipping workload %q\n", workload.Name)
+			return nil
+		}
+	}
+
+	if err := auditWorkload(ctx, workload, opts.Audit); err != nil {
+		return err
+	}
+	if current == nil {
+		if err := target.Create(ctx, workload); err != nil {
+			return err
+		}
+		c.Successf("Created workload %q in context %q\n", workload.Name, opts.ToContext)
+	} else {
+		if err := target.Update(ctx, workload); err != nil {
+			return err
+		}
+		c.Successf("Updated workload %q in context %q\n", workload.Name, opts.ToContext)
+	}
+	c.Printf("\n")
+	c.Infof("To get status: \"tanzu apps workload get %s %s %s %s %s\"\n", workload.Name, flags.NamespaceFlagName, workload.Namespace, flags.ContextFlagName, opts.ToContext)
+	return nil
+}
+
+func (opts *WorkloadPromoteOptions) targetNamespace() string {
+	if opts.TargetNamespace != "" {
+		return opts.TargetNamespace
+	}
+	return opts.Namespace
+}
+
+func NewWorkloadPromoteCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	opts := &WorkloadPromoteOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "promote",
+		Short: "Apply a workload to the cluster of another context",
+		Long: strings.TrimSpace(`
+Apply a workload of the current cluster to the cluster of another kube config context,
+for example to promote a workload from a staging cluster to a production cluster
+without a GitOps repository.
+
+The labels, annotations and spec of the workload are applied, the metadata set by
+the cluster and the status are not. The labels and annotations are merged into the
+ones of the workload in the target cluster, the ones with a prefix protected by the
+plugin config are left untouched. The changes are shown as a diff against the
+workload in the target cluster and confirmed before they are applied. The workload
+is applied in the same namespace unless --target-namespace is set.
+`),
+		Example:           examplesFor(c, "workload promote"),
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		cli.NameArg(&opts.Name),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVar(&opts.ToContext, cli.StripDash(flags.ToContextFlagName), "", "kube config `context` of the cluster to apply the workload to")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.ToContextFlagName), completion.SuggestContexts(ctx, c))
+	cmd.Flags().StringVar(&opts.TargetNamespace, cli.StripDash(flags.TargetNamespaceFlagName), "", "`name` of the namespace to apply the workload to in the target cluster, defaults to the namespace of the workload")
+	cmd.Flags().BoolVar(&opts.AllowProtected, cli.StripDash(flags.AllowProtectedFlagName), false, "allow applying the workload to a namespace protected by the plugin config")
+	auditFlag(cmd, &opts.Audit)
+	cmd.Flags().BoolVarP(&opts.Yes, cli.StripDash(flags.YesFlagName), "y", false, "accept all prompts")
+
+	return cmd
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestWorkloadPromoteOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name:        "invalid empty",
+			Validatable: &commands.WorkloadPromoteOptions{},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrMissingField(flags.NamespaceFlagName),
+				validation.ErrMissingField(cli.NameArgumentName),
+				validation.ErrMissingField(flags.ToContextFlagName),
+			),
+		},
+		{
+			Name: "valid",
+			Validatable: &commands.WorkloadPromoteOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				ToContext: "production",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid names",
+			Validatable: &commands.WorkloadPromoteOptions{
+				Namespace:       "default",
+				TargetNamespace: "Apps",
+				Name:            "my_workload",
+				ToContext:       "production",
+			},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.K8sName("Apps", flags.TargetNamespaceFlagName),
+				validation.K8sName("my_workload", cli.NameArgumentName),
+			),
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestWorkloadPromoteCommand(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+
+	source := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+			d.UID("2c4f8c1e-7f0b-4d52-8a3e-5d4c8b7a9f10")
+			d.ResourceVersion("42")
+			d.AddLabel(apis.AppPartOfLabelName, "my-app")
+			d.AddAnnotation(corev1.LastAppliedConfigAnnotation, `{"metadata":{"name":"my-workload"}}`)
+		}).
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Source(&cartov1alpha1.Source{
+				Git: &cartov1alpha1.GitSource{
+					URL: "https://example.com/my-workload.git",
+					Ref: cartov1alpha1.GitRef{Tag: "v1.1.0"},
+				},
+			})
+		}).
+		StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+			d.ConditionsDie(diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionTrue))
+		})
+	promoted := func(namespace string) *diecartov1alpha1.WorkloadDie {
+		return diecartov1alpha1.WorkloadBlank.
+			MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+				d.Name(workloadName)
+				d.Namespace(namespace)
+				d.AddLabel(apis.AppPartOfLabelName, "my-app")
+			}).
+			SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+				d.Source(&cartov1alpha1.Source{
+					Git: &cartov1alpha1.GitSource{
+						URL: "https://example.com/my-workload.git",
+						Ref: cartov1alpha1.GitRef{Tag: "v1.1.0"},
+					},
+				})
+			})
+	}
+	// the workload in production is at a previous tag
+	previous := promoted(defaultNamespace).
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.ResourceVersion("7")
+		}).
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Source(&cartov1alpha1.Source{
+				Git: &cartov1alpha1.GitSource{
+					URL: "https://example.com/my-workload.git",
+					Ref: cartov1alpha1.GitRef{Tag: "v1.0.0"},
+				},
+			})
+		})
+
+	var production cli.Client
+	productionCluster := func(objects ...client.Object) func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+		return func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+			production = clitesting.NewFakeCliClient(clitesting.NewFakeClient(scheme, objects...))
+			config.ContextClients = map[string]cli.Client{"production": production}
+			return ctx, nil
+		}
+	}
+	sameCluster := func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+		config.ContextClients = map[string]cli.Client{"production": config.Client}
+		return ctx, nil
+	}
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "invalid args",
+			Args:        []string{workloadName},
+			ShouldError: true,
+		},
+		{
+			Name:         "promote to another namespace",
+			Args:         []string{workloadName, flags.ToContextFlagName, "production", flags.TargetNamespaceFlagName, "apps", flags.YesFlagName},
+			GivenObjects: []client.Object{source},
+			Prepare:      sameCluster,
+			ExpectCreates: []client.Object{
+				promoted("apps"),
+			},
+			ExpectOutput: `
+Promote workload "default/my-workload" to "apps/my-workload" in context "production":
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    app.kubernetes.io/part-of: my-app
+      7 + |  name: my-workload
+      8 + |  namespace: apps
+      9 + |spec:
+     10 + |  source:
+     11 + |    git:
+     12 + |      ref:
+     13 + |        tag: v1.1.0
+     14 + |      url: https://example.com/my-workload.git
+
+Created workload "my-workload" in context "production"
+
+To get status: "tanzu apps workload get my-workload --namespace apps --context production"
+`,
+		},
+		{
+			Name: "promote with audit record",
+			Args: []string{workloadName, flags.ToContextFlagName, "production", flags.TargetNamespaceFlagName, "apps", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				ctx, _ = sameCluster(t, ctx, config, tc)
+				return prepareAuditor(t, ctx, config, tc)
+			},
+			GivenObjects: []client.Object{source},
+			ExpectCreates: []client.Object{
+				promoted("apps").
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"alice","time":"2022-06-09T15:12:00Z","command":"promote","flags":["--target-namespace","--to-context","--yes"],"version":"v0.9.0"}`)
+					}),
+			},
+			ExpectOutput: `
+Promote workload "default/my-workload" to "apps/my-workload" in context "production":
+      1 + |---
+      2 + |apiVersion: carto.run/v1alpha1
+      3 + |kind: Workload
+      4 + |metadata:
+      5 + |  labels:
+      6 + |    app.kubernetes.io/part-of: my-app
+      7 + |  name: my-workload
+      8 + |  namespace: apps
+      9 + |spec:
+     10 + |  source:
+     11 + |    git:
+     12 + |      ref:
+     13 + |        tag: v1.1.0
+     14 + |      url: https://example.com/my-workload.git
+
+Created workload "my-workload" in context "production"
+
+To get status: "tanzu apps workload get my-workload --namespace apps --context production"
+`,
+		},
+		{
+			Name:         "update in another cluster",
+			Args:         []string{workloadName, flags.ToContextFlagName, "production", flags.YesFlagName},
+			GivenObjects: []client.Object{source},
+			Prepare:      productionCluster(previous),
+			ExpectOutput: `
+Promote workload "default/my-workload" to "default/my-workload" in context "production":
+...
+  9,  9   |spec:
+ 10, 10   |  source:
+ 11, 11   |    git:
+ 12, 12   |      ref:
+ 13     - |        tag: v1.0.0
+     13 + |        tag: v1.1.0
+ 14, 14   |      url: https://example.com/my-workload.git
+
+Updated workload "my-workload" in context "production"
+
+To get status: "tanzu apps workload get my-workload --namespace default --context production"
+`,
+			Verify: func(t *testing.T, output string, err error) {
+				actual := &cartov1alpha1.Workload{}
+				if err := production.Get(context.Background(), client.ObjectKey{Namespace: defaultNamespace, Name: workloadName}, actual); err != nil {
+					t.Fatalf("unexpected error getting the workload of the production cluster: %v", err)
+				}
+				if diff := cmp.Diff(promoted(defaultNamespace).DieRelease().Spec, actual.Spec); diff != "" {
+					t.Errorf("unexpected spec in the production cluster (-expected, +actual): %s", diff)
+				}
+			},
+		},
+		{
+			Name: "merge the metadata into the target",
+			Args: []string{workloadName, flags.ToContextFlagName, "production", flags.YesFlagName},
+			GivenObjects: []client.Object{
+				source.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel("kapp.k14s.io/app", "1654791120")
+						d.AddAnnotation("example.com/reviewed-by", "my-team")
+					}),
+			},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/"})
+				return productionCluster(
+					previous.
+						MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+							d.AddLabel("kapp.k14s.io/app", "1657000000")
+							d.AddLabel("environment", "production")
+							d.AddAnnotation("kapp.k14s.io/identity", "v1;default/carto.run/Workload/my-workload;carto.run/v1alpha1")
+						}),
+				)(t, ctx, config, tc)
+			},
+			ExpectOutput: `
+Promote workload "default/my-workload" to "default/my-workload" in context "production":
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  annotations:
+      6 + |    example.com/reviewed-by: my-team
+  6,  7   |    kapp.k14s.io/identity: v1;default/carto.run/Workload/my-workload;carto.run/v1alpha1
+  7,  8   |  labels:
+  8,  9   |    app.kubernetes.io/part-of: my-app
+  9, 10   |    environment: production
+...
+ 13, 14   |spec:
+ 14, 15   |  source:
+ 15, 16   |    git:
+ 16, 17   |      ref:
+ 17     - |        tag: v1.0.0
+     18 + |        tag: v1.1.0
+ 18, 19   |      url: https://example.com/my-workload.git
+
+Updated workload "my-workload" in context "production"
+
+To get status: "tanzu apps workload get my-workload --namespace default --context production"
+`,
+			Verify: func(t *testing.T, output string, err error) {
+				actual := &cartov1alpha1.Workload{}
+				if err := production.Get(context.Background(), client.ObjectKey{Namespace: defaultNamespace, Name: workloadName}, actual); err != nil {
+					t.Fatalf("unexpected error getting the workload of the production cluster: %v", err)
+				}
+				expectedLabels := map[string]string{
+					apis.AppPartOfLabelName: "my-app",
+					"environment":           "production",
+					"kapp.k14s.io/app":      "1657000000",
+				}
+				if diff := cmp.Diff(expectedLabels, actual.Labels); diff != "" {
+					t.Errorf("unexpected labels in the production cluster (-expected, +actual): %s", diff)
+				}
+				expectedAnnotations := map[string]string{
+					"example.com/reviewed-by": "my-team",
+					"kapp.k14s.io/identity":   "v1;default/carto.run/Workload/my-workload;carto.run/v1alpha1",
+				}
+				if diff := cmp.Diff(expectedAnnotations, actual.Annotations); diff != "" {
+					t.Errorf("unexpected annotations in the production cluster (-expected, +actual): %s", diff)
+				}
+			},
+		},
+		{
+			Name:         "already up to date",
+			Args:         []string{workloadName, flags.ToContextFlagName, "production", flags.YesFlagName},
+			GivenObjects: []client.Object{source},
+			Prepare:      productionCluster(promoted(defaultNamespace)),
+			ExpectOutput: `
+Workload "my-workload" is already up to date in context "production"
+`,
+		},
+		{
+			Name:        "not found",
+			Args:        []string{workloadName, flags.ToContextFlagName, "production", flags.YesFlagName},
+			Prepare:     productionCluster(),
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/my-workload" not found
+`,
+		},
+		{
+			Name: "protected target namespace",
+			Args: []string{workloadName, flags.ToContextFlagName, "production", flags.TargetNamespaceFlagName, "tap-install", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.ProtectedNamespacesConfigKey, []string{"tap-install"})
+				return sameCluster(t, ctx, config, tc)
+			},
+			GivenObjects: []client.Object{source},
+			ShouldError:  true,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadPromoteCommand(ctx, c)
+	})
+}
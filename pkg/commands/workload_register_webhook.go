@@ -0,0 +1,333 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	fluxnotificationv1beta1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/fluxcd/notification/v1beta1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+const (
+	// WebhookTokenSecretKey holds the token the payloads of the webhook are signed with
+	WebhookTokenSecretKey = "token"
+	// GitRepositoryKind is the kind of the resource fetching the git source of a workload
+	GitRepositoryKind = "GitRepository"
+)
+
+// WorkloadRegisterWebhookOptions registers a webhook with the git provider of the workload source,
+// for each push to trigger the fetch of the source right away rather than on the next poll of its
+// GitRepository
+type WorkloadRegisterWebhookOptions struct {
+	Namespace string
+	Name      string
+
+	ReceiverURL string
+	GitToken    string
+}
+
+var (
+	_ validation.Validatable = (*WorkloadRegisterWebhookOptions)(nil)
+	_ cli.Executable         = (*WorkloadRegisterWebhookOptions)(nil)
+)
+
+func (opts *WorkloadRegisterWebhookOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Namespace == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+	}
+
+	if opts.Name == "" {
+		errs = errs.Also(validation.ErrMissingField(cli.NameArgumentName))
+	} else {
+		errs = errs.Also(validation.K8sName(opts.Name, cli.NameArgumentName))
+	}
+
+	if opts.ReceiverURL == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.ReceiverURLFlagName))
+	} else if u, err := url.Parse(opts.ReceiverURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		errs = errs.Also(validation.ErrInvalidValue(opts.ReceiverURL, flags.ReceiverURLFlagName))
+	}
+
+	return errs
+}
+
+func (opts *WorkloadRegisterWebhookOptions) Exec(ctx context.Context, c *cli.Config) error {
+	workload := &cartov1alpha1.Workload{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, workload); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		c.Errorf("Workload %q not found\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Name))
+		return cli.SilenceError(err)
+	}
+
+	if workload.Spec.Source == nil || workload.Spec.Source.Git == nil || workload.Spec.Source.Git.URL == "" {
+		c.Errorf("Workload %q does not have a git source\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Name))
+		return cli.SilenceError(fmt.Errorf("workload %q does not have a git source", opts.Name))
+	}
+	repo := workload.Spec.Source.Git.URL
+	provider, api, path, err := gitProviderAPI(repo)
+	if err != nil {
+		c.Eprintf("%s unable to register a webhook for %q: %s\n", printer.Serrorf("Error:"), repo, err)
+		return cli.SilenceError(err)
+	}
+	// webhooks can only be managed by the owners of the repo, unlike public pull requests
+	gitToken := gitProviderToken(c, provider, repo, opts.GitToken)
+	if gitToken == "" {
+		c.Eprintf("%s a token allowed to manage the webhooks of %q is required, set %s or %s\n", printer.Serrorf("Error:"), repo, flags.GitTokenFlagName, gitProviderTokenEnv[provider])
+		if host, _, _ := parseGitRepoURL(repo); !gitTokenHostAllowed(c, host) {
+			c.Eprintf("%s is only sent to host %q once listed in %s of the plugin config\n", gitProviderTokenEnv[provider], host, GitTokenHostsConfigKey)
+		}
+		return cli.SilenceError(validation.ErrMissingField(flags.GitTokenFlagName).ToAggregate())
+	}
+
+	source := getWorkloadResourceByKind(workload, GitRepositoryKind)
+	if source == nil {
+		c.Errorf("Workload %q does not have a %s yet, wait for its supply chain to create it\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Name), GitRepositoryKind)
+		return cli.SilenceError(fmt.Errorf("workload %q does not have a %s", opts.Name, GitRepositoryKind))
+	}
+
+	token, err := opts.webhookToken(ctx, c, workload)
+	if err != nil {
+		return err
+	}
+	receiver, err := opts.applyReceiver(ctx, c, workload, provider, source.StampedRef)
+	if err != nil {
+		return err
+	}
+
+	webhookURL := strings.TrimSuffix(opts.ReceiverURL, "/") + receiver.GetWebhookPath(token)
+	header := gitProviderHeader(provider, gitToken)
+	registered, err := registerWebhook(ctx, provider, api, path, header, webhookURL, token)
+	if err != nil {
+		c.Eprintf("%s unable to register a webhook for %q: %s\n", printer.Serrorf("Error:"), repo, err)
+		return cli.SilenceError(err)
+	}
+	if !registered {
+		c.Infof("Webhook %q is already registered for %q\n", webhookURL, repo)
+		return nil
+	}
+	c.Successf("Registered webhook %q for %q\n", webhookURL, repo)
+	return nil
+}
+
+// webhookToken returns the token of the secret of the workload webhook, the secret is created with
+// a random token when it does not exist
+func (opts *WorkloadRegisterWebhookOptions) webhookToken(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) (string, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: workload.Namespace, Name: webhookSecretName(workload)}, secret)
+	if err == nil {
+		if len(secret.Data[WebhookTokenSecretKey]) == 0 {
+			c.Errorf("Secret %q does not have a %q key\n", secret.Name, WebhookTokenSecretKey)
+			return "", cli.SilenceError(fmt.Errorf("secret %q does not have a %q key", secret.Name, WebhookTokenSecretKey))
+		}
+		return string(secret.Data[WebhookTokenSecretKey]), nil
+	}
+	if !apierrs.IsNotFound(err) {
+		return "", err
+	}
+
+	random := make([]byte, 20)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       workload.Namespace,
+			Name:            webhookSecretName(workload),
+			Labels:          map[string]string{cartov1alpha1.WorkloadLabelName: workload.Name},
+			OwnerReferences: workloadOwnerReferences(workload),
+		},
+		Data: map[string][]byte{
+			WebhookTokenSecretKey: []byte(hex.EncodeToString(random)),
+		},
+	}
+	if err := c.Create(ctx, secret); err != nil {
+		return "", err
+	}
+	c.Successf("Created secret %q holding the webhook token\n", secret.Name)
+	return string(secret.Data[WebhookTokenSecretKey]), nil
+}
+
+// applyReceiver creates or updates the flux Receiver notifying the GitRepository of the workload
+// of the calls to the webhook
+func (opts *WorkloadRegisterWebhookOptions) applyReceiver(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload, provider string, source *corev1.ObjectReference) (*fluxnotificationv1beta1.Receiver, error) {
+	events := []string{"push"}
+	if provider == gitProviderGitLab {
+		events = []string{"Push Hook"}
+	}
+	receiver := &fluxnotificationv1beta1.Receiver{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       workload.Namespace,
+			Name:            workload.Name,
+			Labels:          map[string]string{cartov1alpha1.WorkloadLabelName: workload.Name},
+			OwnerReferences: workloadOwnerReferences(workload),
+		},
+		Spec: fluxnotificationv1beta1.ReceiverSpec{
+			Type:   provider,
+			Events: events,
+			Resources: []fluxnotificationv1beta1.CrossNamespaceObjectReference{{
+				APIVersion: source.APIVersion,
+				Kind:       source.Kind,
+				Name:       source.Name,
+				Namespace:  source.Namespace,
+			}},
+			SecretRef: fluxnotificationv1beta1.LocalObjectReference{Name: webhookSecretName(workload)},
+		},
+	}
+
+	current := &fluxnotificationv1beta1.Receiver{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: receiver.Namespace, Name: receiver.Name}, current)
+	if apierrs.IsNotFound(err) {
+		if err := c.Create(ctx, receiver); err != nil {
+			return nil, err
+		}
+		c.Successf("Created receiver %q for %s %q\n", receiver.Name, source.Kind, source.Name)
+		return receiver, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if equality.Semantic.DeepEqual(current.Spec, receiver.Spec) {
+		return current, nil
+	}
+	current.Spec = receiver.Spec
+	if err := c.Update(ctx, current); err != nil {
+		return nil, err
+	}
+	c.Successf("Updated receiver %q for %s %q\n", current.Name, source.Kind, source.Name)
+	return current, nil
+}
+
+// registerWebhook adds the webhook to the repo unless a webhook with the same url is registered
+func registerWebhook(ctx context.Context, provider, api, path string, header http.Header, webhookURL, token string) (bool, error) {
+	hooksURL := fmt.Sprintf("%s/repos/%s/hooks", api, path)
+	hooks := []struct {
+		URL    string `json:"url"`
+		Config struct {
+			URL string `json:"url"`
+		} `json:"config"`
+	}{}
+	hook := map[string]interface{}{
+		"name":   "web",
+		"active": true,
+		"events": []string{"push"},
+		"config": map[string]string{
+			"url":          webhookURL,
+			"content_type": "json",
+			"secret":       token,
+		},
+	}
+	if provider == gitProviderGitLab {
+		hooksURL = fmt.Sprintf("%s/projects/%s/hooks", api, url.PathEscape(path))
+		hook = map[string]interface{}{
+			"url":                     webhookURL,
+			"token":                   token,
+			"push_events":             true,
+			"enable_ssl_verification": true,
+		}
+	}
+
+	if err := getGitProviderAPI(ctx, hooksURL, header, &hooks); err != nil {
+		return false, err
+	}
+	for _, h := range hooks {
+		// GitHub nests the url of the hook in its config
+		if h.URL == webhookURL || h.Config.URL == webhookURL {
+			return false, nil
+		}
+	}
+	if err := callGitProviderAPI(ctx, http.MethodPost, hooksURL, header, hook, http.StatusCreated, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func webhookSecretName(workload *cartov1alpha1.Workload) string {
+	return workload.Name + "-webhook"
+}
+
+// workloadOwnerReferences garbage collects the resources of the webhook with the workload
+func workloadOwnerReferences(workload *cartov1alpha1.Workload) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{
+		APIVersion: cartov1alpha1.SchemeGroupVersion.String(),
+		Kind:       cartov1alpha1.WorkloadKind,
+		Name:       workload.Name,
+		UID:        workload.UID,
+	}}
+}
+
+func NewWorkloadRegisterWebhookCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	opts := &WorkloadRegisterWebhookOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "register-webhook",
+		Short: "Register a webhook triggering the fetch of the git source of a workload on push",
+		Long: strings.TrimSpace(`
+Register a webhook with the GitHub or GitLab repo of the git source of a
+workload, for each push to the repo to trigger the fetch of the source, and
+so a new build, right away rather than on the next poll of its GitRepository.
+
+The webhook calls the fluxcd notification-controller receiver of the cluster,
+exposed at the url of ` + flags.ReceiverURLFlagName + `. A Receiver for the GitRepository of the
+workload is created along with a secret holding the token the calls of the
+webhook are signed with, both are deleted with the workload. The webhook is
+only registered once, running the command again leaves it as it is.
+
+Managing the webhooks of a repo requires a token of the git provider, given by
+` + flags.GitTokenFlagName + ` or by the ` + GitHubTokenEnv + ` or ` + GitLabTokenEnv + ` environment variable. The
+environment variable is only sent to github.com, gitlab.com and the hosts of
+the ` + GitTokenHostsConfigKey + ` key of the plugin config.
+`),
+		Example:           examplesFor(c, "workload register-webhook"),
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		cli.NameArg(&opts.Name),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVar(&opts.ReceiverURL, cli.StripDash(flags.ReceiverURLFlagName), "", "`url` the webhook receiver of the fluxcd notification-controller is exposed at")
+	cmd.Flags().StringVar(&opts.GitToken, cli.StripDash(flags.GitTokenFlagName), "", "`token` of the git provider allowed to manage the webhooks of the repo, defaults to the "+GitHubTokenEnv+" or "+GitLabTokenEnv+" environment variable")
+
+	return cmd
+}
@@ -0,0 +1,426 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	fluxnotificationv1beta1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/fluxcd/notification/v1beta1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestWorkloadRegisterWebhookOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name:        "invalid empty",
+			Validatable: &commands.WorkloadRegisterWebhookOptions{},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrMissingField(flags.NamespaceFlagName),
+				validation.ErrMissingField(cli.NameArgumentName),
+				validation.ErrMissingField(flags.ReceiverURLFlagName),
+			),
+		},
+		{
+			Name: "valid",
+			Validatable: &commands.WorkloadRegisterWebhookOptions{
+				Namespace:   "default",
+				Name:        "my-workload",
+				ReceiverURL: "https://hooks.example.com",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid receiver url",
+			Validatable: &commands.WorkloadRegisterWebhookOptions{
+				Namespace:   "default",
+				Name:        "my-workload",
+				ReceiverURL: "hooks.example.com",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("hooks.example.com", flags.ReceiverURLFlagName),
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestWorkloadRegisterWebhookCommand(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+	receiverURL := "https://hooks.example.com"
+	token := "test-token"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	_ = fluxnotificationv1beta1.AddToScheme(scheme)
+
+	owner := metav1.OwnerReference{
+		APIVersion: "carto.run/v1alpha1",
+		Kind:       "Workload",
+		Name:       workloadName,
+	}
+	gitRepository := &corev1.ObjectReference{
+		APIVersion: "source.toolkit.fluxcd.io/v1beta1",
+		Kind:       "GitRepository",
+		Namespace:  defaultNamespace,
+		Name:       workloadName,
+	}
+	parent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+		}).
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Source(&cartov1alpha1.Source{
+				Git: &cartov1alpha1.GitSource{
+					URL: "https://github.com/my-org/my-repo.git",
+					Ref: cartov1alpha1.GitRef{Branch: "main"},
+				},
+			})
+		}).
+		StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+			d.Resources(
+				diecartov1alpha1.RealizedResourceBlank.
+					Name("source-provider").
+					StampedRef(gitRepository).
+					DieRelease(),
+			)
+		})
+	gitLabParent := parent.
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Source(&cartov1alpha1.Source{
+				Git: &cartov1alpha1.GitSource{
+					URL: "https://gitlab.com/my-group/my-repo.git",
+					Ref: cartov1alpha1.GitRef{Branch: "main"},
+				},
+			})
+		})
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       defaultNamespace,
+			Name:            "my-workload-webhook",
+			Labels:          map[string]string{cartov1alpha1.WorkloadLabelName: workloadName},
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+		Data: map[string][]byte{"token": []byte(token)},
+	}
+	receiver := func(provider string, events ...string) *fluxnotificationv1beta1.Receiver {
+		return &fluxnotificationv1beta1.Receiver{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       defaultNamespace,
+				Name:            workloadName,
+				Labels:          map[string]string{cartov1alpha1.WorkloadLabelName: workloadName},
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+			Spec: fluxnotificationv1beta1.ReceiverSpec{
+				Type:   provider,
+				Events: events,
+				Resources: []fluxnotificationv1beta1.CrossNamespaceObjectReference{{
+					APIVersion: gitRepository.APIVersion,
+					Kind:       gitRepository.Kind,
+					Name:       gitRepository.Name,
+					Namespace:  gitRepository.Namespace,
+				}},
+				SecretRef: fluxnotificationv1beta1.LocalObjectReference{Name: "my-workload-webhook"},
+			},
+		}
+	}
+	webhookURL := receiverURL + receiver("github").GetWebhookPath(token)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh-token" && r.Header.Get("PRIVATE-TOKEN") != "gl-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /repos/my-org/my-repo/hooks", "GET /api/v4/projects/my-group%2Fmy-repo/hooks":
+			w.Write([]byte(`[]`))
+		case "GET /repos/my-org/registered/hooks":
+			w.Write([]byte(`[{"id":1,"config":{"url":"` + webhookURL + `"}}]`))
+		case "POST /repos/my-org/my-repo/hooks":
+			hook := struct {
+				Events []string          `json:"events"`
+				Config map[string]string `json:"config"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&hook); err != nil || hook.Config["url"] != webhookURL || hook.Config["secret"] != token || hook.Events[0] != "push" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		case "POST /api/v4/projects/my-group%2Fmy-repo/hooks":
+			hook := struct {
+				URL        string `json:"url"`
+				Token      string `json:"token"`
+				PushEvents bool   `json:"push_events"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&hook); err != nil || !strings.HasPrefix(hook.URL, receiverURL+"/hook/") || hook.Token != token || !hook.PushEvents {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	// send the requests for the git providers to the test server, with a fixed webhook token
+	prepare := func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+		t.Setenv(commands.GitHubTokenEnv, "")
+		t.Setenv(commands.GitLabTokenEnv, "")
+		var transport roundTripperFunc = func(r *http.Request) (*http.Response, error) {
+			r.URL.Host = strings.TrimPrefix(server.URL, "https://")
+			return server.Client().Transport.RoundTrip(r)
+		}
+		config.Client = &webhookTokenClient{Client: config.Client, token: token}
+		return commands.StashGitProviderTransport(ctx, transport), nil
+	}
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "invalid args",
+			Args:        []string{},
+			ShouldError: true,
+		},
+		{
+			Name:         "register github webhook",
+			Args:         []string{workloadName, flags.ReceiverURLFlagName, receiverURL, flags.GitTokenFlagName, "gh-token"},
+			Prepare:      prepare,
+			GivenObjects: []client.Object{parent},
+			ExpectCreates: []client.Object{
+				secret,
+				receiver("github", "push"),
+			},
+			ExpectOutput: `
+Created secret "my-workload-webhook" holding the webhook token
+Created receiver "my-workload" for GitRepository "my-workload"
+Registered webhook "` + webhookURL + `" for "https://github.com/my-org/my-repo.git"
+`,
+		},
+		{
+			Name: "register github webhook with token from environment",
+			Args: []string{workloadName, flags.ReceiverURLFlagName, receiverURL + "/"},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				ctx, err := prepare(t, ctx, config, tc)
+				t.Setenv(commands.GitHubTokenEnv, "gh-token")
+				return ctx, err
+			},
+			GivenObjects: []client.Object{parent, secret, receiver("github", "push")},
+			ExpectOutput: `
+Registered webhook "` + webhookURL + `" for "https://github.com/my-org/my-repo.git"
+`,
+		},
+		{
+			Name:    "register gitlab webhook",
+			Args:    []string{workloadName, flags.ReceiverURLFlagName, receiverURL, flags.GitTokenFlagName, "gl-token"},
+			Prepare: prepare,
+			GivenObjects: []client.Object{
+				gitLabParent,
+				secret,
+				receiver("github", "push"),
+			},
+			ExpectUpdates: []client.Object{
+				receiver("gitlab", "Push Hook"),
+			},
+			ExpectOutput: `
+Updated receiver "my-workload" for GitRepository "my-workload"
+Registered webhook "` + receiverURL + receiver("gitlab").GetWebhookPath(token) + `" for "https://gitlab.com/my-group/my-repo.git"
+`,
+		},
+		{
+			Name:    "webhook already registered",
+			Args:    []string{workloadName, flags.ReceiverURLFlagName, receiverURL, flags.GitTokenFlagName, "gh-token"},
+			Prepare: prepare,
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "git@github.com:my-org/registered.git",
+								Ref: cartov1alpha1.GitRef{Branch: "main"},
+							},
+						})
+					}),
+				secret,
+				receiver("github", "push"),
+			},
+			ExpectOutput: `
+Webhook "` + webhookURL + `" is already registered for "git@github.com:my-org/registered.git"
+`,
+		},
+		{
+			Name:         "missing token",
+			Args:         []string{workloadName, flags.ReceiverURLFlagName, receiverURL},
+			Prepare:      prepare,
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+			ExpectOutput: `
+Error: a token allowed to manage the webhooks of "https://github.com/my-org/my-repo.git" is required, set --git-token or GITHUB_TOKEN
+`,
+		},
+		{
+			Name: "token from environment not sent to enterprise host",
+			Args: []string{workloadName, flags.ReceiverURLFlagName, receiverURL},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				ctx, err := prepare(t, ctx, config, tc)
+				t.Setenv(commands.GitHubTokenEnv, "gh-token")
+				return ctx, err
+			},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://github.example.com/my-org/my-repo.git",
+								Ref: cartov1alpha1.GitRef{Branch: "main"},
+							},
+						})
+					}),
+			},
+			ShouldError: true,
+			ExpectOutput: `
+Error: a token allowed to manage the webhooks of "https://github.example.com/my-org/my-repo.git" is required, set --git-token or GITHUB_TOKEN
+GITHUB_TOKEN is only sent to host "github.example.com" once listed in git-token-hosts of the plugin config
+`,
+		},
+		{
+			Name:         "token not allowed to manage webhooks",
+			Args:         []string{workloadName, flags.ReceiverURLFlagName, receiverURL, flags.GitTokenFlagName, "other-token"},
+			Prepare:      prepare,
+			GivenObjects: []client.Object{parent, secret, receiver("github", "push")},
+			ShouldError:  true,
+			ExpectOutput: `
+Error: unable to register a webhook for "https://github.com/my-org/my-repo.git": GET https://api.github.com/repos/my-org/my-repo/hooks returned 401, expected 200
+`,
+		},
+		{
+			Name:    "unsupported git provider",
+			Args:    []string{workloadName, flags.ReceiverURLFlagName, receiverURL, flags.GitTokenFlagName, "gh-token"},
+			Prepare: prepare,
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{
+							Git: &cartov1alpha1.GitSource{
+								URL: "https://bitbucket.org/my-org/my-repo.git",
+								Ref: cartov1alpha1.GitRef{Branch: "main"},
+							},
+						})
+					}),
+			},
+			ShouldError: true,
+			ExpectOutput: `
+Error: unable to register a webhook for "https://bitbucket.org/my-org/my-repo.git": git provider "bitbucket.org" is not supported, only GitHub and GitLab are
+`,
+		},
+		{
+			Name:    "workload without git source",
+			Args:    []string{workloadName, flags.ReceiverURLFlagName, receiverURL, flags.GitTokenFlagName, "gh-token"},
+			Prepare: prepare,
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(&cartov1alpha1.Source{Image: "registry.example/source:latest"})
+					}),
+			},
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/my-workload" does not have a git source
+`,
+		},
+		{
+			Name:    "workload without git repository",
+			Args:    []string{workloadName, flags.ReceiverURLFlagName, receiverURL, flags.GitTokenFlagName, "gh-token"},
+			Prepare: prepare,
+			GivenObjects: []client.Object{
+				parent.
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.Resources()
+					}),
+			},
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/my-workload" does not have a GitRepository yet, wait for its supply chain to create it
+`,
+		},
+		{
+			Name:        "workload not found",
+			Args:        []string{workloadName, flags.ReceiverURLFlagName, receiverURL, flags.GitTokenFlagName, "gh-token"},
+			Prepare:     prepare,
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/my-workload" not found
+`,
+		},
+		{
+			Name:    "error getting workload",
+			Args:    []string{workloadName, flags.ReceiverURLFlagName, receiverURL, flags.GitTokenFlagName, "gh-token"},
+			Prepare: prepare,
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("get", "Workload"),
+			},
+			ShouldError: true,
+		},
+		{
+			Name:         "error creating receiver",
+			Args:         []string{workloadName, flags.ReceiverURLFlagName, receiverURL, flags.GitTokenFlagName, "gh-token"},
+			Prepare:      prepare,
+			GivenObjects: []client.Object{parent, secret},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("create", "Receiver"),
+			},
+			ExpectCreates: []client.Object{
+				receiver("github", "push"),
+			},
+			ShouldError: true,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadRegisterWebhookCommand(ctx, c)
+	})
+}
+
+// webhookTokenClient replaces the random token of the created webhook secrets with a known token
+type webhookTokenClient struct {
+	cli.Client
+	token string
+}
+
+func (c *webhookTokenClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if secret, ok := obj.(*corev1.Secret); ok {
+		secret.Data["token"] = []byte(c.token)
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+type WorkloadRelabelOptions struct {
+	Namespace string
+	Name      string
+	Selector  string
+
+	PartOf string
+	Owner  string
+
+	AllowProtected bool
+	Audit          bool
+	Yes            bool
+}
+
+var (
+	_ validation.Validatable = (*WorkloadRelabelOptions)(nil)
+	_ cli.Executable         = (*WorkloadRelabelOptions)(nil)
+)
+
+func (opts *WorkloadRelabelOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Namespace == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+	}
+
+	if opts.Name == "" && opts.Selector == "" {
+		errs = errs.Also(validation.ErrMissingOneOf(cli.NameArgumentName, flags.SelectorFlagName))
+	}
+	if opts.Name != "" && opts.Selector != "" {
+		errs = errs.Also(validation.ErrMultipleOneOf(cli.NameArgumentName, flags.SelectorFlagName))
+	}
+	if opts.Name != "" {
+		errs = errs.Also(validation.K8sName(opts.Name, cli.NameArgumentName))
+	}
+	if opts.Selector != "" {
+		if _, err := labels.Parse(opts.Selector); err != nil {
+			errs = errs.Also(validation.ErrInvalidValue(opts.Selector, flags.SelectorFlagName))
+		}
+	}
+
+	if opts.PartOf == "" && opts.Owner == "" {
+		errs = errs.Also(validation.ErrMissingOneOf(flags.PartOfFlagName, flags.OwnerFlagName))
+	}
+	if opts.PartOf != "" {
+		errs = errs.Also(validation.K8sLabelValue(opts.PartOf, flags.PartOfFlagName))
+	}
+	if opts.Owner != "" {
+		errs = errs.Also(validation.K8sLabelValue(opts.Owner, flags.OwnerFlagName))
+	}
+
+	return errs
+}
+
+func (opts *WorkloadRelabelOptions) Exec(ctx context.Context, c *cli.Config) error {
+	if err := validateProtectedNamespace(c, opts.Namespace, opts.AllowProtected).ToAggregate(); err != nil {
+		return err
+	}
+
+	workloads, err := opts.matchingWorkloads(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	// compute and show every change before asking, so the whole set is confirmed at once
+	changed := []*cartov1alpha1.Workload{}
+	for i := range workloads {
+		current := &workloads[i]
+		workload := current.DeepCopy()
+		opts.relabel(workload)
+		difference, noChange, err := printer.ResourceDiff(current, workload, c.Scheme)
+		if err != nil {
+			return err
+		}
+		if noChange {
+			continue
+		}
+		c.Printf("Relabel workload %q:\n", workload.Name)
+		c.Printf("%s\n", difference)
+		changed = append(changed, workload)
+	}
+	if len(changed) == 0 {
+		c.Infof("Workloads are unchanged, skipping relabel\n")
+		return nil
+	}
+
+	if !opts.Yes {
+		okToRelabel := false
+		err := survey.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("Really relabel %d workload(s)?", len(changed)),
+		}, &okToRelabel, printer.WithSurveyStdio(c.Stdin, c.Stdout, c.Stderr))
+		if err != nil || !okToRelabel {
+			c.Infof("Skipping relabel\n")
+			return nil
+		}
+	}
+
+	relabeled := []*cartov1alpha1.Workload{}
+	for _, workload := range changed {
+		if err := auditWorkload(ctx, workload, opts.Audit); err != nil {
+			return err
+		}
+		if err := c.Update(ctx, workload); err != nil {
+			c.Eprintf("%s unable to relabel workload %q: %s\n", printer.Serrorf("Error:"), workload.Name, err)
+			opts.rollback(ctx, c, workloads, relabeled)
+			if apierrs.IsConflict(err) {
+				return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeConflict))
+			}
+			return cli.SilenceError(err)
+		}
+		relabeled = append(relabeled, workload)
+	}
+	for _, workload := range relabeled {
+		c.Successf("Relabeled workload %q\n", workload.Name)
+	}
+	return nil
+}
+
+func (opts *WorkloadRelabelOptions) matchingWorkloads(ctx context.Context, c *cli.Config) ([]cartov1alpha1.Workload, error) {
+	if opts.Name != "" {
+		workload := &cartov1alpha1.Workload{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, workload); err != nil {
+			if !apierrs.IsNotFound(err) {
+				return nil, err
+			}
+			c.Errorf("Workload %q not found\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Name))
+			return nil, cli.SilenceError(err)
+		}
+		return []cartov1alpha1.Workload{*workload}, nil
+	}
+
+	selector, err := labels.Parse(opts.Selector)
+	if err != nil {
+		return nil, err
+	}
+	workloads := &cartov1alpha1.WorkloadList{}
+	if err := c.List(ctx, workloads, client.InNamespace(opts.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	if len(workloads.Items) == 0 {
+		c.Infof("No workloads found matching %q\n", opts.Selector)
+	}
+	workloads = workloads.DeepCopy()
+	printer.SortByNamespaceAndName(workloads.Items)
+	return workloads.Items, nil
+}
+
+func (opts *WorkloadRelabelOptions) relabel(workload *cartov1alpha1.Workload) {
+	if opts.PartOf != "" {
+		workload.MergeLabels(apis.AppPartOfLabelName, opts.PartOf)
+	}
+	if opts.Owner != "" {
+		workload.MergeLabels(apis.OwnerLabelName, opts.Owner)
+	}
+}
+
+// rollback restores the labels of the workloads relabeled before an update failed, so the set of
+// workloads is not left half relabeled
+func (opts *WorkloadRelabelOptions) rollback(ctx context.Context, c *cli.Config, original []cartov1alpha1.Workload, relabeled []*cartov1alpha1.Workload) {
+	previous := map[string]map[string]string{}
+	for _, workload := range original {
+		previous[workload.Name] = workload.Labels
+	}
+	for _, workload := range relabeled {
+		restored := &cartov1alpha1.Workload{}
+		err := c.Get(ctx, client.ObjectKey{Namespace: workload.Namespace, Name: workload.Name}, restored)
+		if err == nil {
+			for _, key := range []string{apis.AppPartOfLabelName, apis.OwnerLabelName} {
+				if value, ok := previous[workload.Name][key]; ok {
+					restored.MergeLabels(key, value)
+				} else {
+					delete(restored.Labels, key)
+				}
+			}
+			err = c.Update(ctx, restored)
+		}
+		if err != nil {
+			c.Eprintf("%s unable to restore the labels of workload %q: %s\n", printer.Serrorf("Error:"), workload.Name, err)
+			continue
+		}
+		c.Infof("Restored the labels of workload %q\n", workload.Name)
+	}
+}
+
+func NewWorkloadRelabelCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	opts := &WorkloadRelabelOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "relabel",
+		Short: "Change the app and owner labels of workloads",
+		Long: strings.TrimSpace(`
+Change the "` + apis.AppPartOfLabelName + `" and "` + apis.OwnerLabelName + `"
+labels of a workload, or of every workload matching a label selector within a
+namespace.
+
+The changes to all the workloads are shown and confirmed at once. When a workload
+fails to be updated, the labels of the workloads already updated are restored.
+`),
+		Example:           examplesFor(c, "workload relabel"),
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		cli.OptionalNameArg(&opts.Name),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVar(&opts.Selector, cli.StripDash(flags.SelectorFlagName), "", "label `selector` of the workloads to relabel within the namespace")
+	cmd.Flags().StringVar(&opts.PartOf, cli.StripDash(flags.PartOfFlagName), "", "application `name` the workloads are a part of")
+	cmd.Flags().StringVar(&opts.Owner, cli.StripDash(flags.OwnerFlagName), "", "`team` owning the workloads")
+	cmd.Flags().BoolVar(&opts.AllowProtected, cli.StripDash(flags.AllowProtectedFlagName), false, "allow relabeling workloads in a namespace protected by the plugin config")
+	auditFlag(cmd, &opts.Audit)
+	cmd.Flags().BoolVarP(&opts.Yes, cli.StripDash(flags.YesFlagName), "y", false, "accept all prompts")
+
+	return cmd
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestWorkloadRelabelOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name:        "invalid empty",
+			Validatable: &commands.WorkloadRelabelOptions{},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrMissingField(flags.NamespaceFlagName),
+				validation.ErrMissingOneOf(cli.NameArgumentName, flags.SelectorFlagName),
+				validation.ErrMissingOneOf(flags.PartOfFlagName, flags.OwnerFlagName),
+			),
+		},
+		{
+			Name: "valid name",
+			Validatable: &commands.WorkloadRelabelOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				PartOf:    "my-app",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "valid selector",
+			Validatable: &commands.WorkloadRelabelOptions{
+				Namespace: "default",
+				Selector:  "app.kubernetes.io/part-of=old-app",
+				Owner:     "my-team",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "name and selector",
+			Validatable: &commands.WorkloadRelabelOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Selector:  "app.kubernetes.io/part-of=old-app",
+				PartOf:    "my-app",
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(cli.NameArgumentName, flags.SelectorFlagName),
+		},
+		{
+			Name: "invalid selector",
+			Validatable: &commands.WorkloadRelabelOptions{
+				Namespace: "default",
+				Selector:  "app in (",
+				PartOf:    "my-app",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("app in (", flags.SelectorFlagName),
+		},
+		{
+			Name: "invalid label values",
+			Validatable: &commands.WorkloadRelabelOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				PartOf:    "my app",
+				Owner:     "-team",
+			},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.K8sLabelValue("my app", flags.PartOfFlagName),
+				validation.K8sLabelValue("-team", flags.OwnerFlagName),
+			),
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestWorkloadRelabelCommand(t *testing.T) {
+	defaultNamespace := "default"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+
+	workload := func(name string) *diecartov1alpha1.WorkloadDie {
+		return diecartov1alpha1.WorkloadBlank.
+			MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+				d.Name(name)
+				d.Namespace(defaultNamespace)
+				d.AddLabel(apis.AppPartOfLabelName, "old-app")
+			})
+	}
+	relabeled := func(name string) *diecartov1alpha1.WorkloadDie {
+		return workload(name).
+			MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+				d.AddLabel(apis.AppPartOfLabelName, "new-app")
+				d.AddLabel(apis.OwnerLabelName, "new-team")
+			})
+	}
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "invalid args",
+			Args:        []string{},
+			ShouldError: true,
+		},
+		{
+			Name:         "relabel workload",
+			Args:         []string{"api", flags.PartOfFlagName, "new-app", flags.OwnerFlagName, "new-team", flags.YesFlagName},
+			GivenObjects: []client.Object{workload("api"), workload("worker")},
+			ExpectUpdates: []client.Object{
+				relabeled("api"),
+			},
+			ExpectOutput: `
+Relabel workload "api":
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6     - |    app.kubernetes.io/part-of: old-app
+      6 + |    app.kubernetes.io/part-of: new-app
+      7 + |    apps.tanzu.vmware.com/owner: new-team
+  7,  8   |  name: api
+  8,  9   |  namespace: default
+  9, 10   |spec: {}
+
+Relabeled workload "api"
+`,
+		},
+		{
+			Name:         "relabel workload with audit record",
+			Args:         []string{"api", flags.PartOfFlagName, "new-app", flags.OwnerFlagName, "new-team", flags.YesFlagName},
+			Prepare:      prepareAuditor,
+			GivenObjects: []client.Object{workload("api")},
+			ExpectUpdates: []client.Object{
+				relabeled("api").
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.LastModifiedByAnnotationName, `{"user":"alice","time":"2022-06-09T15:12:00Z","command":"relabel","flags":["--owner","--part-of","--yes"],"version":"v0.9.0"}`)
+					}),
+			},
+			ExpectOutput: `
+Relabel workload "api":
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6     - |    app.kubernetes.io/part-of: old-app
+      6 + |    app.kubernetes.io/part-of: new-app
+      7 + |    apps.tanzu.vmware.com/owner: new-team
+  7,  8   |  name: api
+  8,  9   |  namespace: default
+  9, 10   |spec: {}
+
+Relabeled workload "api"
+`,
+		},
+		{
+			Name:        "workload not found",
+			Args:        []string{"api", flags.PartOfFlagName, "new-app", flags.YesFlagName},
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/api" not found
+`,
+		},
+		{
+			Name:         "relabel workloads matching selector",
+			Args:         []string{flags.SelectorFlagName, apis.AppPartOfLabelName + "=old-app", flags.PartOfFlagName, "new-app", flags.OwnerFlagName, "new-team", flags.YesFlagName},
+			GivenObjects: []client.Object{workload("worker"), workload("api"), relabeled("other")},
+			ExpectUpdates: []client.Object{
+				relabeled("api"),
+				relabeled("worker"),
+			},
+			ExpectOutput: `
+Relabel workload "api":
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6     - |    app.kubernetes.io/part-of: old-app
+      6 + |    app.kubernetes.io/part-of: new-app
+      7 + |    apps.tanzu.vmware.com/owner: new-team
+  7,  8   |  name: api
+  8,  9   |  namespace: default
+  9, 10   |spec: {}
+
+Relabel workload "worker":
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6     - |    app.kubernetes.io/part-of: old-app
+      6 + |    app.kubernetes.io/part-of: new-app
+      7 + |    apps.tanzu.vmware.com/owner: new-team
+  7,  8   |  name: worker
+  8,  9   |  namespace: default
+  9, 10   |spec: {}
+
+Relabeled workload "api"
+Relabeled workload "worker"
+`,
+		},
+		{
+			Name:         "no workloads matching selector",
+			Args:         []string{flags.SelectorFlagName, apis.AppPartOfLabelName + "=old-app", flags.PartOfFlagName, "new-app", flags.YesFlagName},
+			GivenObjects: []client.Object{relabeled("api")},
+			ExpectOutput: `
+No workloads found matching "app.kubernetes.io/part-of=old-app"
+Workloads are unchanged, skipping relabel
+`,
+		},
+		{
+			Name:         "unchanged",
+			Args:         []string{"api", flags.PartOfFlagName, "new-app", flags.OwnerFlagName, "new-team", flags.YesFlagName},
+			GivenObjects: []client.Object{relabeled("api")},
+			ExpectOutput: `
+Workloads are unchanged, skipping relabel
+`,
+		},
+		{
+			Name:         "restores labels when an update fails",
+			Args:         []string{flags.SelectorFlagName, apis.AppPartOfLabelName + "=old-app", flags.PartOfFlagName, "new-app", flags.OwnerFlagName, "new-team", flags.YesFlagName},
+			GivenObjects: []client.Object{workload("api"), workload("worker")},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("update", "Workload", clitesting.InduceFailureOpts{Name: "worker"}),
+			},
+			ShouldError: true,
+			ExpectUpdates: []client.Object{
+				relabeled("api"),
+				relabeled("worker"),
+				workload("api"),
+			},
+			ExpectOutput: `
+Relabel workload "api":
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6     - |    app.kubernetes.io/part-of: old-app
+      6 + |    app.kubernetes.io/part-of: new-app
+      7 + |    apps.tanzu.vmware.com/owner: new-team
+  7,  8   |  name: api
+  8,  9   |  namespace: default
+  9, 10   |spec: {}
+
+Relabel workload "worker":
+...
+  2,  2   |apiVersion: carto.run/v1alpha1
+  3,  3   |kind: Workload
+  4,  4   |metadata:
+  5,  5   |  labels:
+  6     - |    app.kubernetes.io/part-of: old-app
+      6 + |    app.kubernetes.io/part-of: new-app
+      7 + |    apps.tanzu.vmware.com/owner: new-team
+  7,  8   |  name: worker
+  8,  9   |  namespace: default
+  9, 10   |spec: {}
+
+Error: unable to relabel workload "worker": inducing failure for update Workload
+Restored the labels of workload "api"
+`,
+		},
+		{
+			Name: "protected namespace",
+			Args: []string{"api", flags.PartOfFlagName, "new-app", flags.YesFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(commands.ProtectedNamespacesConfigKey, []string{defaultNamespace})
+				return ctx, nil
+			},
+			GivenObjects: []client.Object{workload("api")},
+			ShouldError:  true,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadRelabelCommand(ctx, c)
+	})
+}
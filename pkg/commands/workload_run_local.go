@@ -0,0 +1,251 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/source"
+)
+
+type WorkloadRunLocalOptions struct {
+	Namespace string
+	Name      string
+
+	LocalPath    string
+	SourceImage  string
+	PollInterval time.Duration
+	Debounce     time.Duration
+
+	AllowProtected bool
+
+	CACertPaths      []string
+	RegistryCAs      []string
+	RegistryUsername string
+	RegistryPassword string
+	RegistryToken    string
+	RegistryProxy    string
+}
+
+var (
+	_ validation.Validatable = (*WorkloadRunLocalOptions)(nil)
+	_ cli.Executable         = (*WorkloadRunLocalOptions)(nil)
+)
+
+func (opts *WorkloadRunLocalOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Namespace == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+	}
+
+	if opts.Name == "" {
+		errs = errs.Also(validation.ErrMissingField(cli.NameArgumentName))
+	} else {
+		errs = errs.Also(validation.K8sName(opts.Name, cli.NameArgumentName))
+	}
+
+	if opts.LocalPath == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.LocalPathFlagName))
+	} else if !source.IsDir(opts.LocalPath) {
+		errs = errs.Also(validation.ErrInvalidValue(opts.LocalPath, flags.LocalPathFlagName))
+	}
+
+	errs = errs.Also(validation.KeyValues(opts.RegistryCAs, flags.RegistryCAFlagName))
+	if opts.RegistryProxy != "" {
+		if _, err := source.ParseProxy(opts.RegistryProxy); err != nil {
+			errs = errs.Also(validation.ErrInvalidValue(opts.RegistryProxy, flags.RegistryProxyFlagName))
+		}
+	}
+
+	if opts.PollInterval <= 0 {
+		errs = errs.Also(validation.ErrInvalidValue(opts.PollInterval, flags.PollIntervalFlagName))
+	}
+	if opts.Debounce < 0 {
+		errs = errs.Also(validation.ErrInvalidValue(opts.Debounce, flags.DebounceFlagName))
+	}
+
+	return errs
+}
+
+func (opts *WorkloadRunLocalOptions) Exec(ctx context.Context, c *cli.Config) error {
+	if err := validateProtectedNamespace(c, opts.Namespace, opts.AllowProtected).ToAggregate(); err != nil {
+		return err
+	}
+
+	workload := &cartov1alpha1.Workload{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, workload)
+	if err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		c.Errorf("Workload %q not found\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Name))
+		return cli.SilenceError(err)
+	}
+
+	sourceImage := opts.SourceImage
+	if sourceImage == "" && workload.Spec.Source != nil {
+		sourceImage = strings.Split(workload.Spec.Source.Image, "@sha")[0]
+	}
+	if sourceImage == "" {
+		err := fmt.Errorf("workload %q does not have a source image, use %s to set one", workload.Name, flags.SourceImageFlagName)
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+		return cli.SilenceError(err)
+	}
+
+	// the source is packaged and the workload updated with the same machinery as workload apply,
+	// confirmation is implied by running the loop
+	publishOpts := &WorkloadOptions{
+		Namespace:        opts.Namespace,
+		Name:             opts.Name,
+		LocalPath:        opts.LocalPath,
+		SourceImage:      sourceImage,
+		CACertPaths:      opts.CACertPaths,
+		RegistryCAs:      opts.RegistryCAs,
+		RegistryUsername: opts.RegistryUsername,
+		RegistryPassword: opts.RegistryPassword,
+		RegistryToken:    opts.RegistryToken,
+		RegistryProxy:    opts.RegistryProxy,
+		ExcludePathFile:  c.TanzuIgnoreFile,
+		Yes:              true,
+	}
+	excludedPaths := publishOpts.loadExcludedPaths(c)
+
+	last, err := source.Fingerprint(opts.LocalPath, excludedPaths)
+	if err != nil {
+		return err
+	}
+	opts.sync(ctx, c, publishOpts)
+
+	c.Infof("Watching %q for changes to workload %q, press Ctrl-C to stop\n", opts.LocalPath, opts.Name)
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	// a sync waits until the source has been stable for the debounce period, so a burst of saves
+	// publishes a single image
+	var changedAt time.Time
+	pending := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			// the ignore files may have changed since the last poll, a path they now exclude is not a change
+			if paths, err := publishOpts.excludedPaths(); err == nil {
+				excludedPaths = paths
+			}
+			fingerprint, err := source.Fingerprint(opts.LocalPath, excludedPaths)
+			if err != nil {
+				c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+				continue
+			}
+			if fingerprint != last {
+				last = fingerprint
+				changedAt = now
+				pending = true
+				continue
+			}
+			if pending && now.Sub(changedAt) >= opts.Debounce {
+				pending = false
+				opts.sync(ctx, c, publishOpts)
+			}
+		}
+	}
+}
+
+// sync publishes the local source and updates the workload to the published image. Failures are
+// reported without stopping the loop, the next change retries.
+func (opts *WorkloadRunLocalOptions) sync(ctx context.Context, c *cli.Config, publishOpts *WorkloadOptions) {
+	currentWorkload := &cartov1alpha1.Workload{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, currentWorkload); err != nil {
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+		return
+	}
+
+	workload := currentWorkload.DeepCopy()
+	subPath := ""
+	if workload.Spec.Source != nil {
+		subPath = workload.Spec.Source.Subpath
+	}
+	workload.Spec.MergeSourceImage(publishOpts.SourceImage)
+	if subPath != "" {
+		workload.Spec.MergeSubPath(subPath)
+	}
+	if _, err := publishOpts.PublishLocalSource(ctx, c, currentWorkload, workload); err != nil {
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+		return
+	}
+	if _, err := publishOpts.Update(ctx, c, currentWorkload, workload); err != nil && !errors.Is(err, cli.SilentError) {
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+	}
+}
+
+func NewWorkloadRunLocalCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	opts := &WorkloadRunLocalOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "run-local",
+		Short: "Republish local source code to a workload as it changes",
+		Long: strings.TrimSpace(`
+Watch the source code in ` + flags.LocalPathFlagName + ` and, whenever it changes, publish
+it to the source image and update the workload to the new image. Changes are
+detected by polling and are collected until the source has been stable for the
+` + flags.DebounceFlagName + ` period, so a burst of saves publishes a single image.
+
+The source image defaults to the image the workload already runs from. The
+command keeps running until it is interrupted.
+`),
+		Example:           examplesFor(c, "workload run-local"),
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		cli.NameArg(&opts.Name),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVar(&opts.LocalPath, cli.StripDash(flags.LocalPathFlagName), "", "`path` to a directory containing workload source code to watch")
+	cmd.MarkFlagDirname(cli.StripDash(flags.LocalPathFlagName))
+	cmd.Flags().StringVarP(&opts.SourceImage, cli.StripDash(flags.SourceImageFlagName), "s", "", "destination `image` repository where source code is staged before being built, defaults to the workload source image")
+	cmd.Flags().DurationVar(&opts.PollInterval, cli.StripDash(flags.PollIntervalFlagName), 500*time.Millisecond, "how often the local source is checked for changes")
+	cmd.Flags().DurationVar(&opts.Debounce, cli.StripDash(flags.DebounceFlagName), time.Second, "how long the local source must be unchanged before it is published")
+	cmd.Flags().BoolVar(&opts.AllowProtected, cli.StripDash(flags.AllowProtectedFlagName), false, "allow updating a workload in a namespace protected by the plugin config")
+	cmd.Flags().StringArrayVar(&opts.CACertPaths, cli.StripDash(flags.RegistryCertFlagName), []string{}, "file path to CA certificate used to authenticate with registry, flag can be used multiple times")
+	cmd.Flags().StringArrayVar(&opts.RegistryCAs, cli.StripDash(flags.RegistryCAFlagName), []string{}, "CA certificate used to authenticate with one registry only, represented as a `\"host=path\" pair` (flag can be used multiple times)")
+	cmd.Flags().StringVar(&opts.RegistryPassword, cli.StripDash(flags.RegistryPasswordFlagName), "", "password for authenticating with registry")
+	cmd.Flags().StringVar(&opts.RegistryUsername, cli.StripDash(flags.RegistryUsernameFlagName), "", "username for authenticating with registry")
+	cmd.Flags().StringVar(&opts.RegistryToken, cli.StripDash(flags.RegistryTokenFlagName), "", "token for authenticating with registry")
+	cmd.Flags().StringVar(&opts.RegistryProxy, cli.StripDash(flags.RegistryProxyFlagName), "", "`url` of the proxy the registry is reached through, taking precedence over HTTPS_PROXY, hosts of NO_PROXY are reached directly")
+	return cmd
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	diemetav1 "dies.dev/apis/meta/v1"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/logger"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/source"
+)
+
+func TestWorkloadRunLocalOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name:        "invalid empty",
+			Validatable: &commands.WorkloadRunLocalOptions{},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrMissingField(flags.NamespaceFlagName),
+				validation.ErrMissingField(cli.NameArgumentName),
+				validation.ErrMissingField(flags.LocalPathFlagName),
+				validation.ErrInvalidValue(time.Duration(0), flags.PollIntervalFlagName),
+			),
+		},
+		{
+			Name: "valid",
+			Validatable: &commands.WorkloadRunLocalOptions{
+				Namespace:    "default",
+				Name:         "my-workload",
+				LocalPath:    "testdata/local-source",
+				PollInterval: time.Second,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "local path is not a directory",
+			Validatable: &commands.WorkloadRunLocalOptions{
+				Namespace:    "default",
+				Name:         "my-workload",
+				LocalPath:    "testdata/local-source/hello.txt",
+				PollInterval: time.Second,
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("testdata/local-source/hello.txt", flags.LocalPathFlagName),
+		},
+		{
+			Name: "negative debounce",
+			Validatable: &commands.WorkloadRunLocalOptions{
+				Namespace:    "default",
+				Name:         "my-workload",
+				LocalPath:    "testdata/local-source",
+				PollInterval: time.Second,
+				Debounce:     -time.Second,
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue(-time.Second, flags.DebounceFlagName),
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestWorkloadRunLocalCommand(t *testing.T) {
+	workloadName := "my-workload"
+	defaultNamespace := "default"
+	sourceImage := "registry.example/repository:tag"
+	// digests of the source before and after the change made by the tests
+	initialDigest := "sha256:5bd1d45be83b0e405063424fcc5c1228841c346738e7bb466fe6ecfb1069e596"
+	changedDigest := "sha256:c6da78d949f6162f06f90dd16cfe7d38fd477d97010190ee809d68ea935e996b"
+	ignoredDigest := "sha256:c197cd7f140242759bd5bb5de95f1b926247cb7782c84c50f4314b793effcaa7"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+
+	reg, err := ggcrregistry.TLS("registry.example")
+	utilruntime.Must(err)
+	defer reg.Close()
+
+	dir := t.TempDir()
+	writeSource := func(name, content string) {
+		utilruntime.Must(os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+		// the uploaded source digest depends on the file mode, do not let the umask change it
+		utilruntime.Must(os.Chmod(filepath.Join(dir, name), 0644))
+	}
+	writeSource("hello.go", "package main\n")
+
+	parent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+		}).
+		SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+			d.Source(&cartov1alpha1.Source{Image: sourceImage})
+		})
+	published := func(digest string) *diecartov1alpha1.WorkloadDie {
+		return parent.
+			SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+				d.Source(&cartov1alpha1.Source{Image: sourceImage + "@" + digest})
+			})
+	}
+
+	// set once a file excluded by an ignore file written during the loop changes
+	ignoredChanged := false
+	syncsAfterIgnoredChange := 0
+
+	// cancel stops the loop, simulating the user pressing Ctrl-C
+	var cancel context.CancelFunc
+	prepareWithTimeout := func(timeout time.Duration) func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+		return func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+			ctx = source.StashContainerRemoteTransport(ctx, reg.Client().Transport)
+			ctx = logger.StashSourceImageLogger(ctx, logger.NewNoopLogger())
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			return ctx, nil
+		}
+	}
+	prepare := prepareWithTimeout(100 * time.Millisecond)
+	loopFlags := []string{flags.LocalPathFlagName, dir, flags.PollIntervalFlagName, "5ms", flags.DebounceFlagName, "10ms"}
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "empty",
+			Args:        []string{},
+			ShouldError: true,
+		},
+		{
+			Name:        "missing workload",
+			Args:        append([]string{workloadName}, loopFlags...),
+			Prepare:     prepare,
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/my-workload" not found
+`,
+		},
+		{
+			Name: "failed to get workload",
+			Args: append([]string{workloadName}, loopFlags...),
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("get", "Workload"),
+			},
+			Prepare:     prepare,
+			ShouldError: true,
+		},
+		{
+			Name: "workload without source image",
+			Args: append([]string{workloadName}, loopFlags...),
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Source(nil)
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			Prepare:     prepare,
+			ShouldError: true,
+			ExpectOutput: `
+Error: workload "my-workload" does not have a source image, use --source-image to set one
+`,
+		},
+		{
+			Name:         "source unchanged",
+			Args:         append([]string{workloadName}, loopFlags...),
+			GivenObjects: []client.Object{published(initialDigest)},
+			Prepare:      prepare,
+			ExpectOutput: fmt.Sprintf(`
+Publishing source in %q to %q...
+No source code is changed
+Workload is unchanged, skipping update
+Watching %q for changes to workload "my-workload", press Ctrl-C to stop
+`, dir, sourceImage, dir),
+		},
+		{
+			Name:         "republish on change",
+			Args:         append([]string{workloadName}, loopFlags...),
+			GivenObjects: []client.Object{parent},
+			// allow time to detect the change, the loop is stopped once the change is published
+			Prepare: prepareWithTimeout(10 * time.Second),
+			WithReactors: []clitesting.ReactionFunc{
+				func(action clitesting.Action) (bool, runtime.Object, error) {
+					if action.GetVerb() != "update" {
+						return false, nil, nil
+					}
+					update := action.(clitesting.UpdateAction).GetObject().(*cartov1alpha1.Workload)
+					if update.Spec.Source.Image == sourceImage+"@"+initialDigest {
+						writeSource("hello.go", "package main\n\nfunc main() {}\n")
+					} else {
+						cancel()
+					}
+					return false, nil, nil
+				},
+			},
+			ExpectUpdates: []client.Object{
+				published(initialDigest),
+				published(changedDigest),
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				writeSource("hello.go", "package main\n")
+				return nil
+			},
+		},
+		{
+			Name:         "ignore file changed",
+			Args:         append([]string{workloadName}, loopFlags...),
+			GivenObjects: []client.Object{parent},
+			// the loop runs past the change to the ignored file, which must not be published
+			Prepare: prepareWithTimeout(2 * time.Second),
+			WithReactors: []clitesting.ReactionFunc{
+				func(action clitesting.Action) (bool, runtime.Object, error) {
+					if action.GetVerb() != "update" {
+						return false, nil, nil
+					}
+					update := action.(clitesting.UpdateAction).GetObject().(*cartov1alpha1.Workload)
+					if update.Spec.Source.Image == sourceImage+"@"+initialDigest {
+						writeSource(".gitignore", "build.log\n")
+						writeSource("build.log", "started\n")
+					} else {
+						writeSource("build.log", "finished\n")
+						ignoredChanged = true
+					}
+					return false, nil, nil
+				},
+				func(action clitesting.Action) (bool, runtime.Object, error) {
+					// every sync starts by getting the workload
+					if action.GetVerb() == "get" && ignoredChanged {
+						syncsAfterIgnoredChange++
+					}
+					return false, nil, nil
+				},
+			},
+			ExpectUpdates: []client.Object{
+				published(initialDigest),
+				published(ignoredDigest),
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				utilruntime.Must(os.Remove(filepath.Join(dir, ".gitignore")))
+				utilruntime.Must(os.Remove(filepath.Join(dir, "build.log")))
+				if syncsAfterIgnoredChange != 0 {
+					return fmt.Errorf("expected no sync after a change to an ignored file, got %d", syncsAfterIgnoredChange)
+				}
+				return nil
+			},
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadRunLocalCommand(ctx, c)
+	})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+// workloadCRDName is the name of the CustomResourceDefinition of the workloads installed with
+// Cartographer
+const workloadCRDName = "workloads.carto.run"
+
+// fileSchemaWarnings compares the content of --file with the schema of the Workload CRD installed
+// in the cluster. It returns a warning for a deprecated API version and for each field of the file
+// unknown to the schema, which the API server would drop when saving the workload.
+func fileSchemaWarnings(ctx context.Context, c *cli.Config, content []byte) ([]string, error) {
+	raw := map[string]interface{}{}
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for len(raw) == 0 {
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := c.Get(ctx, types.NamespacedName{Name: workloadCRDName}, crd); err != nil {
+		return nil, err
+	}
+	apiVersion, _ := raw["apiVersion"].(string)
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	var version *apiextensionsv1.CustomResourceDefinitionVersion
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Name == gv.Version {
+			version = &crd.Spec.Versions[i]
+		}
+	}
+	if version == nil || !version.Served {
+		return []string{fmt.Sprintf("API version %q of Workload is not served by the cluster", apiVersion)}, nil
+	}
+
+	warnings := []string{}
+	if version.Deprecated {
+		warning := fmt.Sprintf("API version %q of Workload is deprecated", apiVersion)
+		if version.DeprecationWarning != nil {
+			warning = *version.DeprecationWarning
+		}
+		warnings = append(warnings, warning)
+	}
+	if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+		return warnings, nil
+	}
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(version.Schema.OpenAPIV3Schema, internal, nil); err != nil {
+		return nil, err
+	}
+	structural, err := structuralschema.NewStructural(internal)
+	if err != nil {
+		return nil, err
+	}
+	// the fields pruned from the file are the ones the API server would drop
+	for _, field := range pruning.PruneWithOptions(raw, structural, true, pruning.PruneOptions{ReturnPruned: true}) {
+		warnings = append(warnings, fmt.Sprintf("field %q is unknown to the Workload schema of the cluster and is dropped", field))
+	}
+	return warnings, nil
+}
+
+// checkFileSchema warns about the content of --file that does not match the Workload schema of the
+// cluster, with --strict the apply fails instead. Without --strict, a schema that cannot be read,
+// for example when the user is not allowed to get CRDs, skips the check.
+func (opts *WorkloadApplyOptions) checkFileSchema(ctx context.Context, c *cli.Config, content []byte) error {
+	warnings, err := fileSchemaWarnings(ctx, c, content)
+	if err != nil {
+		if opts.Strict {
+			return fmt.Errorf("unable to check file %q against the Workload schema of the cluster: %w", opts.FilePath, err)
+		}
+		return nil
+	}
+	if len(warnings) == 0 {
+		return nil
+	}
+	if opts.Strict {
+		return fmt.Errorf("file %q does not match the Workload schema of the cluster: %s", opts.FilePath, strings.Join(warnings, ", "))
+	}
+	for _, warning := range warnings {
+		c.Eprintf("%s %s\n", printer.Swarnf("Warning:"), warning)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/openpgp"
+)
+
+var errSignatureMismatch = errors.New("signature does not match")
+
+// verifyFileSignature checks the content of --file against the detached signature of
+// --verify-signature with the public key of --signature-key
+func (opts *WorkloadOptions) verifyFileSignature(ctx context.Context, content []byte) error {
+	var signature []byte
+	var err error
+	if isFileURL(opts.VerifySignature) {
+		signature, err = downloadFile(ctx, opts.VerifySignature)
+		if err != nil {
+			return fmt.Errorf("unable to download signature %q: %w", opts.VerifySignature, err)
+		}
+	} else if signature, err = os.ReadFile(opts.VerifySignature); err != nil {
+		return fmt.Errorf("unable to read signature %q: %w", opts.VerifySignature, err)
+	}
+	key, err := os.ReadFile(opts.SignatureKey)
+	if err != nil {
+		return fmt.Errorf("unable to read signature key %q: %w", opts.SignatureKey, err)
+	}
+	if err := verifySignature(content, signature, key); err != nil {
+		return fmt.Errorf("file %q does not verify with signature %q and key %q: %w", opts.FilePath, opts.VerifySignature, opts.SignatureKey, err)
+	}
+	return nil
+}
+
+// verifySignature checks a detached signature of content. An armored GPG public key checks a binary
+// or armored GPG signature, a PEM public key checks a base64 encoded signature of the sha256 digest
+// of the content, as created by cosign sign-blob.
+func verifySignature(content, signature, key []byte) error {
+	if bytes.Contains(key, []byte("-----BEGIN PGP PUBLIC KEY BLOCK-----")) {
+		keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(key))
+		if err != nil {
+			return fmt.Errorf("invalid GPG public key: %w", err)
+		}
+		if bytes.Contains(signature, []byte("-----BEGIN PGP SIGNATURE-----")) {
+			_, err = openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(content), bytes.NewReader(signature))
+		} else {
+			_, err = openpgp.CheckDetachedSignature(keyring, bytes.NewReader(content), bytes.NewReader(signature))
+		}
+		return err
+	}
+
+	block, _ := pem.Decode(key)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return errors.New("invalid public key, expected an armored GPG public key or a PEM public key")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return fmt.Errorf("invalid signature, expected base64: %w", err)
+	}
+	digest := sha256.Sum256(content)
+	switch k := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest[:], raw) {
+			return errSignatureMismatch
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], raw); err != nil {
+			return errSignatureMismatch
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, content, raw) {
+			return errSignatureMismatch
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+	return nil
+}
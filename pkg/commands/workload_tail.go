@@ -19,10 +19,12 @@ package commands
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,6 +36,7 @@ import (
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
 )
 
 type WorkloadTailOptions struct {
@@ -42,7 +45,13 @@ type WorkloadTailOptions struct {
 
 	Component  string
 	Since      time.Duration
+	SinceTime  string
+	Lines      int64
 	Timestamps bool
+	Events     bool
+	Follow     bool
+
+	NoReconnect bool
 }
 
 var (
@@ -67,6 +76,19 @@ func (opts *WorkloadTailOptions) Validate(ctx context.Context) validation.FieldE
 		errs = errs.Also(validation.ErrInvalidValue(opts.Since, flags.SinceFlagName))
 	}
 
+	if opts.SinceTime != "" {
+		if sinceTime, err := time.Parse(time.RFC3339, opts.SinceTime); err != nil || sinceTime.After(time.Now()) {
+			errs = errs.Also(validation.ErrInvalidValue(opts.SinceTime, flags.SinceTimeFlagName))
+		}
+		if cmd := cli.CommandFromContext(ctx); cmd != nil && cmd.Flags().Changed(cli.StripDash(flags.SinceFlagName)) {
+			errs = errs.Also(validation.ErrMultipleOneOf(flags.SinceFlagName, flags.SinceTimeFlagName))
+		}
+	}
+
+	if opts.Lines < 0 && opts.Lines != logs.AllLines {
+		errs = errs.Also(validation.ErrInvalidValue(opts.Lines, flags.LinesFlagName))
+	}
+
 	errs = errs.Also(validation.K8sLabelValue(opts.Component, flags.ComponentFlagName))
 	return errs
 }
@@ -90,25 +112,184 @@ func (opts *WorkloadTailOptions) Exec(ctx context.Context, c *cli.Config) error
 	if err != nil {
 		panic(err)
 	}
+	since := opts.Since
+	if opts.SinceTime != "" {
+		sinceTime, err := time.Parse(time.RFC3339, opts.SinceTime)
+		if err != nil {
+			return err
+		}
+		since = time.Since(sinceTime)
+	}
+	if !opts.Follow {
+		return opts.dump(ctx, c, workload, selector, since)
+	}
+	if opts.Events {
+		events := logs.NewEventTail(opts.Namespace, workloadEventFilter(workload), since)
+		if err := events.Poll(ctx, c); err != nil {
+			c.Eprintf("%s unable to list events: %s\n", printer.Swarnf("Warning:"), err)
+		} else {
+			eventsCtx, stopEvents := context.WithCancel(ctx)
+			eventsDone := make(chan struct{})
+			go func() {
+				defer close(eventsDone)
+				events.Run(eventsCtx, c)
+			}()
+			defer func() {
+				stopEvents()
+				<-eventsDone
+			}()
+		}
+	}
+
+	// --lines alone limits the lines shown of each container rather than the time window, as in dump
+	if opts.Lines != logs.AllLines && opts.SinceTime == "" && !cli.CommandFromContext(ctx).Flags().Changed(cli.StripDash(flags.SinceFlagName)) {
+		since = 0
+	}
+
 	containers := []string{}
-	return logs.Tail(ctx, c, opts.Namespace, selector, containers, opts.Since, opts.Timestamps)
+	deliveredNamespaces := opts.deliveredNamespaces(ctx, c, workload)
+	if len(deliveredNamespaces) == 0 {
+		return opts.tail(ctx, c, opts.Namespace, selector, containers, since)
+	}
+
+	// tail every namespace at once, the first tail to fail stops the others
+	for _, namespace := range deliveredNamespaces {
+		c.Infof("Also tailing logs in namespace %q where the workload is delivered\n", namespace)
+	}
+	ctx = logs.StashNamespacePrefix(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	namespaces := append([]string{opts.Namespace}, deliveredNamespaces...)
+	tailErrs := make(chan error, len(namespaces))
+	for _, namespace := range namespaces {
+		go func(namespace string) {
+			tailErrs <- opts.tail(ctx, c, namespace, selector, containers, since)
+		}(namespace)
+	}
+	var tailErr error
+	for range namespaces {
+		if err := <-tailErrs; err != nil && tailErr == nil {
+			tailErr = err
+			cancel()
+		}
+	}
+	return tailErr
+}
+
+// tail streams the logs of a namespace, reconnecting when the API server closes the streams unless
+// --no-reconnect is set
+func (opts *WorkloadTailOptions) tail(ctx context.Context, c *cli.Config, namespace string, selector labels.Selector, containers []string, since time.Duration) error {
+	if opts.NoReconnect {
+		return logs.Tail(ctx, c, namespace, selector, containers, since, opts.Lines, opts.Timestamps)
+	}
+	return logs.TailReconnecting(ctx, c, namespace, selector, containers, since, opts.Lines, opts.Timestamps)
+}
+
+// dump prints the logs and warning events of the workload so far, and returns. Without --since or
+// --since-time, every log line of the pods is printed.
+func (opts *WorkloadTailOptions) dump(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload, selector labels.Selector, since time.Duration) error {
+	if opts.SinceTime == "" && !cli.CommandFromContext(ctx).Flags().Changed(cli.StripDash(flags.SinceFlagName)) {
+		since = 0
+	}
+	if opts.Events {
+		eventsSince := since
+		if eventsSince == 0 {
+			eventsSince = time.Since(workload.CreationTimestamp.Time)
+		}
+		events := logs.NewEventTail(opts.Namespace, workloadEventFilter(workload), eventsSince)
+		if err := events.Poll(ctx, c); err != nil {
+			c.Eprintf("%s unable to list events: %s\n", printer.Swarnf("Warning:"), err)
+		}
+	}
+
+	containers := []string{}
+	deliveredNamespaces := opts.deliveredNamespaces(ctx, c, workload)
+	for _, namespace := range deliveredNamespaces {
+		c.Infof("Also printing logs in namespace %q where the workload is delivered\n", namespace)
+	}
+	if len(deliveredNamespaces) != 0 {
+		ctx = logs.StashNamespacePrefix(ctx)
+	}
+	// one namespace after the other, for the output to be the same every time
+	for _, namespace := range append([]string{opts.Namespace}, deliveredNamespaces...) {
+		if err := logs.Dump(ctx, c, namespace, selector, containers, since, opts.Lines, opts.Timestamps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliveredNamespaces returns the namespaces, other than the namespace of the workload, where the
+// deliverable of the workload places its resources. The deliverable is optional, any error reading it
+// only limits the tail to the namespace of the workload.
+func (opts *WorkloadTailOptions) deliveredNamespaces(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) []string {
+	wldDeliverable := getWorkloadResourceByKind(workload, cartov1alpha1.DeliverableKind)
+	if wldDeliverable == nil {
+		return nil
+	}
+	deliverable := &cartov1alpha1.Deliverable{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: wldDeliverable.StampedRef.Namespace, Name: wldDeliverable.StampedRef.Name}, deliverable); err != nil {
+		return nil
+	}
+	namespaces := []string{}
+	seen := map[string]bool{opts.Namespace: true}
+	for _, resource := range deliverable.Status.Resources {
+		if resource.StampedRef == nil || resource.StampedRef.Namespace == "" || seen[resource.StampedRef.Namespace] {
+			continue
+		}
+		seen[resource.StampedRef.Namespace] = true
+		namespaces = append(namespaces, resource.StampedRef.Namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// workloadEventFilter accepts the events of the workload, of the resources stamped for it by the
+// supply chain, like its GitRepository, and of the objects named after it, like its build pods
+func workloadEventFilter(workload *cartov1alpha1.Workload) logs.EventFilter {
+	stamped := map[string]bool{}
+	for _, resource := range workload.Status.Resources {
+		if resource.StampedRef != nil {
+			stamped[resource.StampedRef.Kind+"/"+resource.StampedRef.Name] = true
+		}
+	}
+	return func(involved corev1.ObjectReference) bool {
+		return stamped[involved.Kind+"/"+involved.Name] || involved.Name == workload.Name || strings.HasPrefix(involved.Name, workload.Name+"-")
+	}
 }
 
 func NewWorkloadTailCommand(ctx context.Context, c *cli.Config) *cobra.Command {
 	opts := &WorkloadTailOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "tail",
-		Short: "Watch workload related logs",
+		Use:     "tail",
+		Aliases: []string{"t", "logs"},
+		Short:   "Watch workload related logs",
 		Long: strings.TrimSpace(`
 Stream logs for a workload until canceled. To cancel, press Ctl-c in
 the shell or kill the process. As new workload pods are started, the logs
-are displayed. To show historical logs use ` + flags.SinceFlagName + `.
+are displayed. To show historical logs use ` + flags.SinceFlagName + ` or
+` + flags.SinceTimeFlagName + `, and ` + flags.LinesFlagName + ` to limit how many lines are shown for
+each container.
+
+When the deliverable of the workload places resources in other namespaces, the
+logs of the workload pods in those namespaces are streamed as well, each line
+prefixed with the namespace of its pod.
+
+Warning events of the workload and of the resources stamped for it, like a
+GitRepository failing to fetch the source or an image that can not be pulled,
+are printed along with the logs, each line prefixed with "` + logs.EventPrefix + `", to explain why
+no pods are started. Use ` + flags.EventsFlagName + `=false to only stream the logs.
+
+Use ` + flags.FollowFlagName + `=false to print the logs the workload pods logged so far, like the
+logs of a completed build, and exit instead of streaming them. Every log line
+is printed unless ` + flags.SinceFlagName + ` or ` + flags.SinceTimeFlagName + ` is set.
+
+When the API server closes the log streams, for example during a long build,
+the logs are streamed again after a backoff, starting from the time the stream
+was lost. Use ` + flags.NoReconnectFlagName + ` to stop instead.
 `),
-		Example: strings.Join([]string{
-			fmt.Sprintf("%s workload tail my-workload", c.Name),
-			fmt.Sprintf("%s workload tail my-workload %s 1h", c.Name, flags.SinceFlagName),
-		}, "\n"),
+		Example:           examplesFor(c, "workload tail"),
 		PreRunE:           cli.ValidateE(ctx, opts),
 		RunE:              cli.ExecE(ctx, c, opts),
 		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
@@ -119,10 +300,16 @@ are displayed. To show historical logs use ` + flags.SinceFlagName + `.
 	)
 
 	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
-	cmd.Flags().StringVar(&opts.Component, cli.StripDash(flags.ComponentFlagName), "", "workload component `name` (e.g. build)")
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVarP(&opts.Component, cli.StripDash(flags.ComponentFlagName), "c", "", "workload component `name` (e.g. build)")
 	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.ComponentFlagName), completion.SuggestComponentNames(ctx, c))
 	cmd.Flags().BoolVarP(&opts.Timestamps, cli.StripDash(flags.TimestampFlagName), "t", false, "print timestamp for each log line")
 	cmd.Flags().DurationVar(&opts.Since, cli.StripDash(flags.SinceFlagName), time.Second, "time `duration` to start reading logs from")
 	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.SinceFlagName), completion.SuggestDurationUnits(ctx, completion.CommonDurationUnits))
+	cmd.Flags().StringVar(&opts.SinceTime, cli.StripDash(flags.SinceTimeFlagName), "", "RFC3339 `timestamp` to start reading logs from (e.g. 2022-01-02T15:04:05Z), cannot be used with "+flags.SinceFlagName)
+	cmd.Flags().Int64Var(&opts.Lines, cli.StripDash(flags.LinesFlagName), logs.AllLines, "`number` of most recent log lines to show for each container, -1 shows all lines")
+	cmd.Flags().BoolVar(&opts.Events, cli.StripDash(flags.EventsFlagName), true, "print the warning events of the workload and of the resources stamped for it along with the logs ("+flags.EventsFlagName+"=false to disable)")
+	cmd.Flags().BoolVar(&opts.Follow, cli.StripDash(flags.FollowFlagName), true, "stream the logs until canceled ("+flags.FollowFlagName+"=false to print the logs so far and exit)")
+	cmd.Flags().BoolVar(&opts.NoReconnect, cli.StripDash(flags.NoReconnectFlagName), false, "stop streaming when the API server closes the log streams, instead of reconnecting")
 	return cmd
 }
@@ -25,8 +25,11 @@ import (
 	diemetav1 "dies.dev/apis/meta/v1"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
@@ -84,6 +87,60 @@ func TestWorkloadTailOptionsValidate(t *testing.T) {
 			},
 			ExpectFieldErrors: validation.ErrInvalidValue(-1*time.Nanosecond, flags.SinceFlagName),
 		},
+		{
+			Name: "since time",
+			Validatable: &commands.WorkloadTailOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				SinceTime: "2022-01-02T15:04:05Z",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid since time",
+			Validatable: &commands.WorkloadTailOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				SinceTime: "yesterday",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("yesterday", flags.SinceTimeFlagName),
+		},
+		{
+			Name: "since time in the future",
+			Validatable: &commands.WorkloadTailOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				SinceTime: "2999-01-02T15:04:05Z",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("2999-01-02T15:04:05Z", flags.SinceTimeFlagName),
+		},
+		{
+			Name: "lines",
+			Validatable: &commands.WorkloadTailOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Lines:     100,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "all lines",
+			Validatable: &commands.WorkloadTailOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Lines:     logs.AllLines,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid lines",
+			Validatable: &commands.WorkloadTailOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				Lines:     -2,
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue(int64(-2), flags.LinesFlagName),
+		},
 		{
 			Name: "component",
 			Validatable: &commands.WorkloadTailOptions{
@@ -112,6 +169,7 @@ func TestWorkloadTailCommand(t *testing.T) {
 
 	scheme := runtime.NewScheme()
 	_ = cartov1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
 
 	parent := diecartov1alpha1.WorkloadBlank.
 		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
@@ -119,6 +177,71 @@ func TestWorkloadTailCommand(t *testing.T) {
 			d.Namespace(defaultNamespace)
 		})
 
+	deliveredParent := parent.
+		StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+			d.Resources(
+				diecartov1alpha1.RealizedResourceBlank.
+					Name("deliverable").
+					StampedRef(&corev1.ObjectReference{
+						Kind:      cartov1alpha1.DeliverableKind,
+						Namespace: defaultNamespace,
+						Name:      workloadName,
+					}).
+					DieRelease(),
+			)
+		})
+	deliverable := diecartov1alpha1.DeliverableBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+		}).
+		StatusDie(func(d *diecartov1alpha1.DeliverableStatusDie) {
+			d.Resources(
+				diecartov1alpha1.RealizedResourceBlank.
+					Name("source-provider").
+					StampedRef(&corev1.ObjectReference{Kind: "ImageRepository", Namespace: defaultNamespace, Name: workloadName}).
+					DieRelease(),
+				diecartov1alpha1.RealizedResourceBlank.
+					Name("deployer").
+					StampedRef(&corev1.ObjectReference{Kind: "App", Namespace: "run-dev", Name: workloadName}).
+					DieRelease(),
+				diecartov1alpha1.RealizedResourceBlank.
+					Name("config").
+					StampedRef(&corev1.ObjectReference{Kind: "ConfigMap", Namespace: "run-dev", Name: workloadName}).
+					DieRelease(),
+			)
+		})
+
+	sourcedParent := parent.
+		StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+			d.Resources(
+				diecartov1alpha1.RealizedResourceBlank.
+					Name("source-provider").
+					StampedRef(&corev1.ObjectReference{Kind: "GitRepository", Namespace: defaultNamespace, Name: "test-source"}).
+					DieRelease(),
+			)
+		})
+	warning := func(name, kind, involved, reason, message string, ago time.Duration) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: defaultNamespace,
+				Name:      name,
+				UID:       types.UID(name),
+			},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Namespace: defaultNamespace, Name: involved},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        message,
+			Count:          1,
+			LastTimestamp:  metav1.NewTime(time.Now().Add(-ago)),
+		}
+	}
+	events := []client.Object{
+		warning("source", "GitRepository", "test-source", "GitOperationFailed", "failed to checkout and determine revision: unable to clone", 2*time.Minute),
+		warning("build", "Pod", "test-workload-build-1-build-pod", "Failed", "Failed to pull image \"registry.example.com/builder\": not found", time.Minute),
+		warning("other", "GitRepository", "other-source", "GitOperationFailed", "failed to checkout and determine revision: unable to clone", time.Minute),
+	}
+
 	table := clitesting.CommandTestSuite{
 		{
 			Name:        "empty",
@@ -159,7 +282,7 @@ Workload "default/test-workload" not found
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
 				tailer := &logs.FakeTailer{}
 				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
-				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, false).Return(nil).Once()
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, logs.AllLines, false).Return(nil).Once()
 				ctx = logs.StashTailer(ctx, tailer)
 				// simulate a user exit after 10ms
 				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
@@ -184,7 +307,7 @@ Workload "default/test-workload" not found
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
 				tailer := &logs.FakeTailer{}
 				selector, _ := labels.Parse(fmt.Sprintf("%s=%s,%s=%s", cartov1alpha1.WorkloadLabelName, workloadName, apis.ComponentLabelName, "build"))
-				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, false).Return(nil).Once()
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, logs.AllLines, false).Return(nil).Once()
 				ctx = logs.StashTailer(ctx, tailer)
 				// simulate a user exit after 10ms
 				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
@@ -205,11 +328,11 @@ Workload "default/test-workload" not found
 		},
 		{
 			Name: "error tailing logs",
-			Args: []string{flags.NamespaceFlagName, defaultNamespace, flags.SinceFlagName, "1h", workloadName},
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, flags.SinceFlagName, "1h", workloadName, flags.NoReconnectFlagName},
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
 				tailer := &logs.FakeTailer{}
 				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
-				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, false).Return(fmt.Errorf("tail error")).Once()
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, logs.AllLines, false).Return(fmt.Errorf("tail error")).Once()
 				ctx = logs.StashTailer(ctx, tailer)
 				return ctx, nil
 			},
@@ -224,6 +347,36 @@ Workload "default/test-workload" not found
 			ShouldError: true,
 			ExpectOutput: `
 ...tail output...
+`,
+		},
+		{
+			Name: "reconnect after losing the log stream",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, flags.SinceFlagName, "1h", workloadName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, logs.AllLines, false).Return(fmt.Errorf("lost watch connection")).Once()
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				ctx = logs.StashReconnect(ctx, logs.Reconnect{Initial: time.Millisecond, Max: time.Millisecond})
+				// simulate a user exit after 50ms
+				ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+				_ = cancel
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ExpectOutput: `
+...tail output...
+Log stream lost (lost watch connection), reconnecting in 1ms
+Stream reconnected
+...tail output...
 `,
 		},
 		{
@@ -232,7 +385,35 @@ Workload "default/test-workload" not found
 			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
 				tailer := &logs.FakeTailer{}
 				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
-				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, true).Return(nil).Once()
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, logs.AllLines, true).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				// simulate a user exit after 10ms
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				_ = cancel
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ExpectOutput: `
+...tail output...
+`,
+		},
+		{
+			Name: "show logs for workload since time",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, flags.SinceTimeFlagName, "2022-01-02T15:04:05Z", workloadName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				since := mock.MatchedBy(func(since time.Duration) bool {
+					return since >= time.Since(time.Date(2022, time.January, 2, 15, 4, 5, 0, time.UTC))-time.Minute
+				})
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, since, logs.AllLines, false).Return(nil).Once()
 				ctx = logs.StashTailer(ctx, tailer)
 				// simulate a user exit after 10ms
 				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
@@ -249,6 +430,314 @@ Workload "default/test-workload" not found
 			},
 			ExpectOutput: `
 ...tail output...
+`,
+		},
+		{
+			Name:        "since and since time",
+			Args:        []string{flags.NamespaceFlagName, defaultNamespace, flags.SinceFlagName, "1h", flags.SinceTimeFlagName, "2022-01-02T15:04:05Z", workloadName},
+			ShouldError: true,
+			GivenObjects: []client.Object{
+				parent,
+			},
+		},
+		{
+			Name: "show last lines of logs for workload",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, flags.LinesFlagName, "20", workloadName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Duration(0), int64(20), false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				// simulate a user exit after 10ms
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				_ = cancel
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ExpectOutput: `
+...tail output...
+`,
+		},
+		{
+			Name: "show last lines of logs since a duration for workload",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, flags.SinceFlagName, "1m", flags.LinesFlagName, "20", workloadName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Minute, int64(20), false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				// simulate a user exit after 10ms
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				_ = cancel
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ExpectOutput: `
+...tail output...
+`,
+		},
+		{
+			Name: "show logs for workload delivered to other namespaces",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, workloadName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, false).Return(nil).Once()
+				tailer.On("Tail", mock.Anything, "run-dev", selector, []string{}, time.Second, logs.AllLines, false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				// simulate a user exit after 10ms
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				_ = cancel
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				deliveredParent,
+				deliverable,
+			},
+			ExpectOutput: `
+Also tailing logs in namespace "run-dev" where the workload is delivered
+...tail output...
+...tail output...
+`,
+		},
+		{
+			Name: "show logs for workload with deliverable not found",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, workloadName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				// simulate a user exit after 10ms
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				_ = cancel
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				deliveredParent,
+			},
+			ExpectOutput: `
+...tail output...
+`,
+		},
+		{
+			Name: "error tailing logs of delivered namespace",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, workloadName, flags.NoReconnectFlagName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, false).Return(nil).Once()
+				tailer.On("Tail", mock.Anything, "run-dev", selector, []string{}, time.Second, logs.AllLines, false).Return(fmt.Errorf("tail error")).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				deliveredParent,
+				deliverable,
+			},
+			ShouldError: true,
+			ExpectOutput: `
+Also tailing logs in namespace "run-dev" where the workload is delivered
+...tail output...
+...tail output...
+`,
+		},
+		{
+			Name: "show events of workload",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, flags.SinceFlagName, "1h", workloadName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, logs.AllLines, false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				// simulate a user exit after 10ms
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				_ = cancel
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: append([]client.Object{sourcedParent}, events...),
+			ExpectOutput: `
+[event] GitRepository/test-source GitOperationFailed: failed to checkout and determine revision: unable to clone
+[event] Pod/test-workload-build-1-build-pod Failed: Failed to pull image "registry.example.com/builder": not found
+...tail output...
+`,
+		},
+		{
+			Name: "show logs without events",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, flags.SinceFlagName, "1h", flags.EventsFlagName + "=false", workloadName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, logs.AllLines, false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				// simulate a user exit after 10ms
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				_ = cancel
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: append([]client.Object{sourcedParent}, events...),
+			ExpectOutput: `
+...tail output...
+`,
+		},
+		{
+			Name: "error listing events",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, flags.SinceFlagName, "1h", workloadName},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Hour, logs.AllLines, false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				// simulate a user exit after 10ms
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				_ = cancel
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				sourcedParent,
+			},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("list", "EventList"),
+			},
+			ExpectOutput: `
+Warning: unable to list events: inducing failure for list EventList
+...tail output...
+`,
+		},
+		{
+			Name: "print build logs without following",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, workloadName, flags.ComponentFlagName, "build", flags.FollowFlagName + "=false"},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s,%s=%s", cartov1alpha1.WorkloadLabelName, workloadName, apis.ComponentLabelName, "build"))
+				tailer.On("Dump", mock.Anything, "default", selector, []string{}, time.Duration(0), logs.AllLines, false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: append([]client.Object{sourcedParent}, events...),
+			ExpectOutput: `
+[event] GitRepository/test-source GitOperationFailed: failed to checkout and determine revision: unable to clone
+[event] Pod/test-workload-build-1-build-pod Failed: Failed to pull image "registry.example.com/builder": not found
+...dump output...
+`,
+		},
+		{
+			Name: "print logs since a duration without following",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, flags.SinceFlagName, "90s", flags.LinesFlagName, "20", workloadName, flags.FollowFlagName + "=false"},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Dump", mock.Anything, "default", selector, []string{}, 90*time.Second, int64(20), false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: append([]client.Object{sourcedParent}, events...),
+			ExpectOutput: `
+[event] Pod/test-workload-build-1-build-pod Failed: Failed to pull image "registry.example.com/builder": not found
+...dump output...
+`,
+		},
+		{
+			Name: "print logs of workload delivered to other namespaces without following",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, workloadName, flags.FollowFlagName + "=false"},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Dump", mock.Anything, "default", selector, []string{}, time.Duration(0), logs.AllLines, false).Return(nil).Once()
+				tailer.On("Dump", mock.Anything, "run-dev", selector, []string{}, time.Duration(0), logs.AllLines, false).Return(nil).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				deliveredParent,
+				deliverable,
+			},
+			ExpectOutput: `
+Also printing logs in namespace "run-dev" where the workload is delivered
+...dump output...
+...dump output...
+`,
+		},
+		{
+			Name: "error printing logs without following",
+			Args: []string{flags.NamespaceFlagName, defaultNamespace, workloadName, flags.FollowFlagName + "=false"},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				tailer := &logs.FakeTailer{}
+				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
+				tailer.On("Dump", mock.Anything, "default", selector, []string{}, time.Duration(0), logs.AllLines, false).Return(fmt.Errorf("dump error")).Once()
+				ctx = logs.StashTailer(ctx, tailer)
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				tailer := logs.RetrieveTailer(ctx).(*logs.FakeTailer)
+				tailer.AssertExpectations(t)
+				return nil
+			},
+			GivenObjects: []client.Object{
+				parent,
+			},
+			ShouldError: true,
+			ExpectOutput: `
+...dump output...
 `,
 		},
 	}
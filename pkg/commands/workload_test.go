@@ -19,18 +19,32 @@ package commands_test
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/pem"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
+	"github.com/acarl005/stripansi"
 	"github.com/google/go-cmp/cmp"
 	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
@@ -38,6 +52,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -71,6 +86,135 @@ func TestWorkloadCommand(t *testing.T) {
 	table.Run(t, scheme, commands.NewWorkloadCommand)
 }
 
+func TestWorkloadCommandRequestFlags(t *testing.T) {
+	scheme := runtime.NewScheme()
+	c := cli.NewDefaultConfig("test", scheme)
+	cmd := commands.NewWorkloadCommand(context.Background(), c)
+
+	if expected, actual := 3, c.Retries; expected != actual {
+		t.Errorf("expected default retries %d, got %d", expected, actual)
+	}
+
+	// the flags are inherited by every workload command, apply included
+	apply, _, err := cmd.Find([]string{"apply"})
+	if err != nil {
+		t.Fatalf("Find() errored %v", err)
+	}
+	if err := apply.ParseFlags([]string{flags.RequestTimeoutFlagName, "10s", flags.RetriesFlagName, "5"}); err != nil {
+		t.Fatalf("ParseFlags() errored %v", err)
+	}
+	if expected, actual := 10*time.Second, c.RequestTimeout; expected != actual {
+		t.Errorf("expected request timeout %s, got %s", expected, actual)
+	}
+	if expected, actual := 5, c.Retries; expected != actual {
+		t.Errorf("expected retries %d, got %d", expected, actual)
+	}
+}
+
+func TestWorkloadCommandNegativeRetries(t *testing.T) {
+	scheme := runtime.NewScheme()
+	c := cli.NewDefaultConfig("test", scheme)
+	cmd := commands.NewWorkloadCommand(context.Background(), c)
+
+	// every workload command rejects negative retries, not only apply
+	get, _, err := cmd.Find([]string{"get"})
+	if err != nil {
+		t.Fatalf("Find() errored %v", err)
+	}
+	if err := get.ParseFlags([]string{flags.RetriesFlagName, "-1"}); err != nil {
+		t.Fatalf("ParseFlags() errored %v", err)
+	}
+	expected := validation.ErrInvalidValue(-1, flags.RetriesFlagName).ToAggregate().Error()
+	if err := cmd.PersistentPreRunE(get, []string{}); err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestWorkloadCommandAliases(t *testing.T) {
+	scheme := runtime.NewScheme()
+	c := cli.NewDefaultConfig("test", scheme)
+	cmd := commands.NewWorkloadCommand(context.Background(), c)
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"ls"}, expected: "list"},
+		{args: []string{"l"}, expected: "list"},
+		{args: []string{"g"}, expected: "get"},
+		{args: []string{"t"}, expected: "tail"},
+		{args: []string{"logs"}, expected: "tail"},
+		{args: []string{"c"}, expected: "create"},
+		{args: []string{"u"}, expected: "update"},
+		{args: []string{"a"}, expected: "apply"},
+		{args: []string{"d"}, expected: "delete"},
+		{args: []string{"del"}, expected: "delete"},
+		{args: []string{"rm"}, expected: "delete"},
+	}
+	for _, test := range tests {
+		t.Run(strings.Join(test.args, " "), func(t *testing.T) {
+			found, _, err := cmd.Find(test.args)
+			if err != nil {
+				t.Fatalf("Find() errored %v", err)
+			}
+			if found.Name() != test.expected {
+				t.Errorf("Find() wanted %q, got %q", test.expected, found.Name())
+			}
+		})
+	}
+
+	seen := map[string]string{}
+	for _, sub := range cmd.Commands() {
+		for _, name := range append([]string{sub.Name()}, sub.Aliases...) {
+			if other, ok := seen[name]; ok {
+				t.Errorf("%q is used by both %q and %q", name, other, sub.Name())
+			}
+			seen[name] = sub.Name()
+		}
+	}
+}
+
+func TestWorkloadCommandShortFlags(t *testing.T) {
+	scheme := runtime.NewScheme()
+	c := cli.NewDefaultConfig("test", scheme)
+	cmd := commands.NewWorkloadCommand(context.Background(), c)
+
+	tests := []struct {
+		command   string
+		shorthand string
+		expected  string
+	}{
+		{command: "apply", shorthand: "n", expected: cli.StripDash(flags.NamespaceFlagName)},
+		{command: "apply", shorthand: "f", expected: cli.StripDash(flags.FilePathFlagName)},
+		{command: "apply", shorthand: "l", expected: cli.StripDash(flags.LabelFlagName)},
+		{command: "apply", shorthand: "o", expected: cli.StripDash(flags.OutputFlagName)},
+		{command: "apply", shorthand: "y", expected: cli.StripDash(flags.YesFlagName)},
+		{command: "create", shorthand: "l", expected: cli.StripDash(flags.LabelFlagName)},
+		{command: "update", shorthand: "l", expected: cli.StripDash(flags.LabelFlagName)},
+		{command: "delete", shorthand: "f", expected: cli.StripDash(flags.FilePathFlagName)},
+		{command: "get", shorthand: "o", expected: cli.StripDash(flags.OutputFlagName)},
+		{command: "list", shorthand: "A", expected: cli.StripDash(flags.AllNamespacesFlagName)},
+		{command: "list", shorthand: "o", expected: cli.StripDash(flags.OutputFlagName)},
+		{command: "tail", shorthand: "c", expected: cli.StripDash(flags.ComponentFlagName)},
+		{command: "tail", shorthand: "t", expected: cli.StripDash(flags.TimestampFlagName)},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s -%s", test.command, test.shorthand), func(t *testing.T) {
+			sub, _, err := cmd.Find([]string{test.command})
+			if err != nil {
+				t.Fatalf("Find() errored %v", err)
+			}
+			flag := sub.Flags().ShorthandLookup(test.shorthand)
+			if flag == nil {
+				t.Fatalf("expected shorthand -%s", test.shorthand)
+			}
+			if flag.Name != test.expected {
+				t.Errorf("shorthand -%s wanted %q, got %q", test.shorthand, test.expected, flag.Name)
+			}
+		})
+	}
+}
+
 func TestWorkloadOptionsValidate(t *testing.T) {
 	table := clitesting.ValidatableTestSuite{
 		{
@@ -87,6 +231,187 @@ func TestWorkloadOptionsValidate(t *testing.T) {
 			},
 			ExpectFieldErrors: validation.ErrInvalidValue("", cli.NameArgumentName),
 		},
+		{
+			Name: "json output",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				Name:      "my-resource",
+				Output:    "json",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "file url",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:  "default",
+				FilePath:   "https://catalog.example/workload.yaml",
+				FileSHA256: "3b5c7e1a1a0b1f5b4f0c3b8b2f1ea8d6c6c1c9b4a0e8f2d7c5b3a1f0e9d8c7b6",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "insecure file url",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				FilePath:  "http://catalog.example/workload.yaml",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("http://catalog.example/workload.yaml", flags.FilePathFlagName),
+		},
+		{
+			Name: "insecure file url in upper case",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				FilePath:  "HTTP://catalog.example/workload.yaml",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("HTTP://catalog.example/workload.yaml", flags.FilePathFlagName),
+		},
+		{
+			Name: "git pr",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:  "default",
+				Name:       "my-resource",
+				GitRepo:    "https://github.com/my-org/my-repo.git",
+				GitPR:      42,
+				GitPRLabel: true,
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "git pr with branch",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				Name:      "my-resource",
+				GitPR:     42,
+				GitBranch: "main",
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.GitPRFlagName, flags.GitBranchFlagName, flags.GitCommitFlagName, flags.GitTagFlagName),
+		},
+		{
+			Name: "invalid git pr",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				Name:      "my-resource",
+				GitPR:     -1,
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue(-1, flags.GitPRFlagName),
+		},
+		{
+			Name: "git pr label without git pr",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:  "default",
+				Name:       "my-resource",
+				GitPRLabel: true,
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.GitPRFlagName),
+		},
+		{
+			Name: "file sha256 without file",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:  "default",
+				Name:       "my-resource",
+				FileSHA256: "3b5c7e1a1a0b1f5b4f0c3b8b2f1ea8d6c6c1c9b4a0e8f2d7c5b3a1f0e9d8c7b6",
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.FilePathFlagName),
+		},
+		{
+			Name: "verify signature",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:       "default",
+				FilePath:        "https://catalog.example/workload.yaml",
+				VerifySignature: "https://catalog.example/workload.yaml.sig",
+				SignatureKey:    "cosign.pub",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "verify signature without file and key",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:       "default",
+				Name:            "my-resource",
+				VerifySignature: "http://catalog.example/workload.yaml.sig",
+			},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrMissingField(flags.FilePathFlagName),
+				validation.ErrInvalidValue("http://catalog.example/workload.yaml.sig", flags.VerifySignatureFlagName),
+				validation.ErrMissingField(flags.SignatureKeyFlagName),
+			),
+		},
+		{
+			Name: "insecure signature url in mixed case",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:       "default",
+				FilePath:        "https://catalog.example/workload.yaml",
+				VerifySignature: "Http://catalog.example/workload.yaml.sig",
+				SignatureKey:    "cosign.pub",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("Http://catalog.example/workload.yaml.sig", flags.VerifySignatureFlagName),
+		},
+		{
+			Name: "signature key without signature",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:    "default",
+				FilePath:     "testdata/workload.yaml",
+				SignatureKey: "cosign.pub",
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.VerifySignatureFlagName),
+		},
+		{
+			Name: "invalid file sha256",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:  "default",
+				FilePath:   "testdata/workload.yaml",
+				FileSHA256: "sha256:abc",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("sha256:abc", flags.FileSHA256FlagName),
+		},
+		{
+			Name: "unsupported output",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				Name:      "my-resource",
+				Output:    "yaml",
+			},
+			ExpectFieldErrors: validation.EnumInvalidValue("yaml", flags.OutputFlagName, []string{"json", "name-and-url"}),
+		},
+		{
+			Name: "unsupported visibility",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:  "default",
+				Name:       "my-resource",
+				Visibility: "private",
+			},
+			ExpectFieldErrors: validation.EnumInvalidValue("private", flags.VisibilityFlagName, []string{"cluster-local", "public"}),
+		},
+		{
+			Name: "verify while waiting",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:     "default",
+				Name:          "my-resource",
+				Wait:          true,
+				VerifyURL:     "/healthz",
+				VerifyCommand: "curl -f $WORKLOAD_URL",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "verify without waiting",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				Name:      "my-resource",
+				VerifyURL: "https://my-workload.example.com/healthz",
+			},
+			ExpectFieldErrors: validation.ErrMissingField(flags.WaitFlagName),
+		},
+		{
+			Name: "invalid verify url",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				Name:      "my-resource",
+				Tail:      true,
+				VerifyURL: "healthz",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("healthz", flags.VerifyURLFlagName),
+		},
 		{
 			Name: "valid env",
 			Validatable: &commands.WorkloadOptions{
@@ -159,6 +484,15 @@ func TestWorkloadOptionsValidate(t *testing.T) {
 			},
 			ExpectFieldErrors: validation.ErrInvalidArrayValue("FOO", flags.BuildEnvFlagName, 0),
 		},
+		{
+			Name: "invalid builder",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				Name:      "my-resource",
+				Builder:   "My_Builder",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("My_Builder", flags.BuilderFlagName),
+		},
 		{
 			Name: "params",
 			Validatable: &commands.WorkloadOptions{
@@ -178,6 +512,29 @@ func TestWorkloadOptionsValidate(t *testing.T) {
 			ShouldValidate:    false,
 			ExpectFieldErrors: validation.ErrInvalidValue("bleep", flags.ParamFlagName+"[1]"),
 		},
+		{
+			Name: "typed params",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:  "default",
+				Name:       "my-resource",
+				ParamsInt:  []string{"replicas=3", "timeout-"},
+				ParamsBool: []string{"debug=true", "cache-"},
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid typed params",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:  "default",
+				Name:       "my-resource",
+				ParamsInt:  []string{"replicas=three"},
+				ParamsBool: []string{"debug=true", "cache=maybe"},
+			},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidValue("replicas=three", flags.ParamIntFlagName+"[0]"),
+				validation.ErrInvalidValue("cache=maybe", flags.ParamBoolFlagName+"[1]"),
+			),
+		},
 		{
 			Name: "valid resources limits",
 			Validatable: &commands.WorkloadOptions{
@@ -222,10 +579,178 @@ func TestWorkloadOptionsValidate(t *testing.T) {
 			},
 			ShouldValidate: false,
 			ExpectFieldErrors: validation.FieldErrors{}.Also(
-				validation.ErrInvalidValue("nan-cpu", flags.LimitCPUFlagName),
-				validation.ErrInvalidValue("nan-memory", flags.LimitMemoryFlagName),
+				validation.ErrInvalidQuantity("nan-cpu", flags.LimitCPUFlagName),
+				validation.ErrInvalidQuantity("nan-memory", flags.LimitMemoryFlagName),
+			),
+		},
+		{
+			Name: "resource limits with misspelled suffixes",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:   "default",
+				Name:        "my-resource",
+				LimitCPU:    "500 m",
+				LimitMemory: "500mi",
+			},
+			ShouldValidate: false,
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidQuantity("500 m", flags.LimitCPUFlagName),
+				validation.ErrInvalidQuantity("500mi", flags.LimitMemoryFlagName),
+			),
+		},
+		{
+			Name: "invalid wait for",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				Name:      "my-resource",
+				WaitFor:   []string{"condition=SupplyChainReady", "condition=deliverable/Ready=false", "Ready", "condition=Ready=maybe", "condition=/Ready"},
+			},
+			ShouldValidate: false,
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidValue("Ready", flags.WaitForFlagName),
+				validation.ErrInvalidValue("condition=Ready=maybe", flags.WaitForFlagName),
+				validation.ErrInvalidValue("condition=/Ready", flags.WaitForFlagName),
+			),
+		},
+		{
+			Name: "registry ca",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:   "default",
+				Name:        "my-resource",
+				LocalPath:   "testdata/local-source",
+				SourceImage: "registry.example/hello:source",
+				RegistryCAs: []string{"registry.example=ca.crt"},
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "registry proxy",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:     "default",
+				Name:          "my-resource",
+				RegistryProxy: "http://proxy.example.com:3128",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid registry proxy",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:     "default",
+				Name:          "my-resource",
+				RegistryProxy: "ftp://proxy.example.com",
+			},
+			ShouldValidate: false,
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidValue("ftp://proxy.example.com", flags.RegistryProxyFlagName),
+			),
+		},
+		{
+			Name: "invalid registry ca",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:   "default",
+				Name:        "my-resource",
+				RegistryCAs: []string{"ca.crt"},
+			},
+			ShouldValidate: false,
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidArrayValue("ca.crt", flags.RegistryCAFlagName, 0),
+				validation.ErrMissingOneOf(flags.LocalPathFlagName, flags.ImagePinFlagName, flags.SourceImagePullFlagName),
 			),
 		},
+		{
+			Name: "source image pull",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:       "default",
+				Name:            "my-resource",
+				SourceImage:     "registry.example/my-source:1.0",
+				SourceImagePull: commands.SourceImagePullDigest,
+				RegistryCAs:     []string{"registry.example=ca.crt"},
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid source image pull",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:       "default",
+				Name:            "my-resource",
+				SourceImage:     "registry.example/my-source:1.0",
+				SourceImagePull: "latest",
+			},
+			ExpectFieldErrors: validation.EnumInvalidValue("latest", flags.SourceImagePullFlagName, []string{commands.SourceImagePullTag, commands.SourceImagePullDigest}),
+		},
+		{
+			Name: "source image pull with local path",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:       "default",
+				Name:            "my-resource",
+				SourceImage:     "registry.example/my-source:1.0",
+				SourceImagePull: commands.SourceImagePullTag,
+				LocalPath:       ".",
+			},
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.LocalPathFlagName, flags.SourceImagePullFlagName),
+		},
+		{
+			Name: "invalid max source size",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:     "default",
+				Name:          "my-resource",
+				MaxSourceSize: "100MB",
+			},
+			ShouldValidate:    false,
+			ExpectFieldErrors: validation.ErrInvalidQuantity("100MB", flags.MaxSourceSizeFlagName),
+		},
+		{
+			Name: "negative conflict retries",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:       "default",
+				Name:            "my-resource",
+				ConflictRetries: -1,
+			},
+			ShouldValidate:    false,
+			ExpectFieldErrors: validation.ErrInvalidValue(-1, flags.ConflictRetriesFlagName),
+		},
+		{
+			Name: "dry run output format",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:    "default",
+				Name:         "my-resource",
+				DryRun:       true,
+				OutputFormat: "kustomize-patch",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid dry run output format",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:    "default",
+				Name:         "my-resource",
+				DryRun:       true,
+				OutputFormat: "helm",
+			},
+			ShouldValidate:    false,
+			ExpectFieldErrors: validation.EnumInvalidValue("helm", flags.OutputFormatFlagName, []string{"yaml", "kustomize-patch", "ytt"}),
+		},
+		{
+			Name: "output format without dry run",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:    "default",
+				Name:         "my-resource",
+				OutputFormat: "ytt",
+			},
+			ShouldValidate:    false,
+			ExpectFieldErrors: validation.ErrMissingField(flags.DryRunFlagName),
+		},
+		{
+			Name: "patch output format with create namespace",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:       "default",
+				Name:            "my-resource",
+				DryRun:          true,
+				OutputFormat:    "ytt",
+				CreateNamespace: true,
+			},
+			ShouldValidate:    false,
+			ExpectFieldErrors: validation.ErrMultipleOneOf(flags.CreateNamespaceFlagName, flags.OutputFormatFlagName),
+		},
 		{
 			Name: "valid resources requests",
 			Validatable: &commands.WorkloadOptions{
@@ -246,8 +771,8 @@ func TestWorkloadOptionsValidate(t *testing.T) {
 			},
 			ShouldValidate: false,
 			ExpectFieldErrors: validation.FieldErrors{}.Also(
-				validation.ErrInvalidValue("nan-cpu", flags.RequestCPUFlagName),
-				validation.ErrInvalidValue("nan-memory", flags.RequestMemoryFlagName),
+				validation.ErrInvalidQuantity("nan-cpu", flags.RequestCPUFlagName),
+				validation.ErrInvalidQuantity("nan-memory", flags.RequestMemoryFlagName),
 			),
 		},
 		{
@@ -260,7 +785,7 @@ func TestWorkloadOptionsValidate(t *testing.T) {
 			},
 			ShouldValidate: false,
 			ExpectFieldErrors: validation.FieldErrors{}.Also(
-				validation.ErrInvalidValue("2", flags.RequestCPUFlagName),
+				validation.ErrQuantityAboveLimit("2", "1", flags.RequestCPUFlagName),
 			),
 		},
 		{
@@ -273,7 +798,44 @@ func TestWorkloadOptionsValidate(t *testing.T) {
 			},
 			ShouldValidate: false,
 			ExpectFieldErrors: validation.FieldErrors{}.Also(
-				validation.ErrInvalidValue("2Gi", flags.RequestMemoryFlagName),
+				validation.ErrQuantityAboveLimit("2Gi", "1Gi", flags.RequestMemoryFlagName),
+			),
+		},
+		{
+			Name: "extended resources",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				Name:      "my-resource",
+				Limits:    []string{"nvidia.com/gpu=2", "example.com/fpga-"},
+				Requests:  []string{"nvidia.com/gpu=1", "ephemeral-storage=1Gi"},
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid extended resources",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				Name:      "my-resource",
+				Limits:    []string{"nvidia.com/gpu=one"},
+				Requests:  []string{"nvidia.com/gpu"},
+			},
+			ShouldValidate: false,
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidValue("nvidia.com/gpu=one", flags.LimitFlagName+"[0]"),
+				validation.ErrInvalidValue("nvidia.com/gpu", flags.RequestFlagName+"[0]"),
+			),
+		},
+		{
+			Name: "invalid extended resource requests compared to limit",
+			Validatable: &commands.WorkloadOptions{
+				Namespace: "default",
+				Name:      "my-resource",
+				Limits:    []string{"nvidia.com/gpu=1"},
+				Requests:  []string{"cpu=1", "nvidia.com/gpu=2"},
+			},
+			ShouldValidate: false,
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidValue("nvidia.com/gpu=2", flags.RequestFlagName+"[1]"),
 			),
 		},
 		{
@@ -332,6 +894,25 @@ func TestWorkloadOptionsValidate(t *testing.T) {
 			},
 			ShouldValidate: true,
 		},
+		{
+			Name: "pod annotations",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:      "default",
+				Name:           "my-resource",
+				PodAnnotations: []string{"sidecar.istio.io/inject=true", "vault.hashicorp.com/agent-inject-"},
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid pod annotations",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:      "default",
+				Name:           "my-resource",
+				PodAnnotations: []string{"sidecar.istio.io/inject"},
+			},
+			ShouldValidate:    false,
+			ExpectFieldErrors: validation.ErrInvalidValue("sidecar.istio.io/inject", flags.PodAnnotationFlagName+"[0]"),
+		},
 		{
 			Name: "valid service references",
 			Validatable: &commands.WorkloadOptions{
@@ -348,39 +929,96 @@ func TestWorkloadOptionsValidate(t *testing.T) {
 				Name:        "my-resource",
 				ServiceRefs: []string{"database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-ns:my-prod-db"},
 			},
-			ShouldValidate: true,
+			ShouldValidate: true,
+		},
+		{
+			Name: "valid delete service references",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:   "default",
+				Name:        "my-resource",
+				ServiceRefs: []string{"database-"},
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid service references",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:   "default",
+				Name:        "my-resource",
+				ServiceRefs: []string{"database=PostgreSQL:my-prod-db"},
+			},
+			ShouldValidate: false,
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidArrayValue("PostgreSQL:my-prod-db", flags.ServiceRefFlagName, 0),
+			),
+		},
+		{
+			Name: "invalid service references with spaces",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:   "default",
+				Name:        "my-resource",
+				ServiceRefs: []string{"data base=v1:PostgreSQL:my-prod-db"},
+			},
+			ShouldValidate: false,
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidArrayValue("data base", flags.ServiceRefFlagName, 0),
+			),
+		},
+		{
+			Name: "valid service ref secrets",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:         "default",
+				Name:              "my-resource",
+				ServiceRefs:       []string{"database-"},
+				ServiceRefSecrets: []string{"database=my-db-credentials", "cache-"},
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid service ref secrets",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:         "default",
+				Name:              "my-resource",
+				ServiceRefSecrets: []string{"database", "cache=my_cache"},
+			},
+			ShouldValidate: false,
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrInvalidArrayValue("database", flags.ServiceRefSecretFlagName, 0),
+				validation.ErrInvalidArrayValue("my_cache", flags.ServiceRefSecretFlagName, 1),
+			),
 		},
 		{
-			Name: "valid delete service references",
+			Name: "valid image pull secrets",
 			Validatable: &commands.WorkloadOptions{
-				Namespace:   "default",
-				Name:        "my-resource",
-				ServiceRefs: []string{"database-"},
+				Namespace:        "default",
+				Name:             "my-resource",
+				ImagePullSecrets: []string{"registry-credentials", "registry-token-"},
 			},
 			ShouldValidate: true,
 		},
 		{
-			Name: "invalid service references",
+			Name: "invalid image pull secrets",
 			Validatable: &commands.WorkloadOptions{
-				Namespace:   "default",
-				Name:        "my-resource",
-				ServiceRefs: []string{"database=PostgreSQL:my-prod-db"},
+				Namespace:        "default",
+				Name:             "my-resource",
+				ImagePullSecrets: []string{"registry-credentials", "Registry_Token"},
 			},
 			ShouldValidate: false,
 			ExpectFieldErrors: validation.FieldErrors{}.Also(
-				validation.ErrInvalidArrayValue("PostgreSQL:my-prod-db", flags.ServiceRefFlagName, 0),
+				validation.ErrInvalidArrayValue("Registry_Token", flags.ImagePullSecretFlagName, 1),
 			),
 		},
 		{
-			Name: "invalid service references with spaces",
+			Name: "service ref bound to a secret and a service",
 			Validatable: &commands.WorkloadOptions{
-				Namespace:   "default",
-				Name:        "my-resource",
-				ServiceRefs: []string{"data base=v1:PostgreSQL:my-prod-db"},
+				Namespace:         "default",
+				Name:              "my-resource",
+				ServiceRefs:       []string{"database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-db"},
+				ServiceRefSecrets: []string{"database=my-db-credentials"},
 			},
 			ShouldValidate: false,
 			ExpectFieldErrors: validation.FieldErrors{}.Also(
-				validation.ErrInvalidArrayValue("data base", flags.ServiceRefFlagName, 0),
+				validation.ErrMultipleOneOf(flags.ServiceRefFlagName, flags.ServiceRefSecretFlagName),
 			),
 		},
 		{
@@ -469,6 +1107,15 @@ func TestWorkloadOptionsValidate(t *testing.T) {
 			},
 			ExpectFieldErrors: validation.ErrInvalidValue("ports_json={\"name\": \"smtp\", \"port\": 1026", flags.ParamYamlFlagName+"[1]"),
 		},
+		{
+			Name: "invalid param file",
+			Validatable: &commands.WorkloadOptions{
+				Namespace:  "default",
+				Name:       "my-resource",
+				ParamFiles: []string{"ports=testdata/param-file.yaml", "testdata/param-file.yaml"},
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("testdata/param-file.yaml", flags.ParamFileFlagName+"[1]"),
+		},
 		{
 			Name: "registry username and pass",
 			Validatable: &commands.WorkloadOptions{
@@ -676,6 +1323,48 @@ func TestWorkloadOptionsApplyOptionsToWorkload(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "cluster-local visibility",
+			args: []string{flags.VisibilityFlagName, "cluster-local"},
+			input: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+				},
+			},
+			expected: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+					Labels: map[string]string{
+						apis.KnativeVisibilityLabelName: "cluster-local",
+					},
+				},
+			},
+		},
+		{
+			name: "public visibility",
+			args: []string{flags.VisibilityFlagName, "public"},
+			input: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+					Labels: map[string]string{
+						apis.WorkloadTypeLabelName:      "web",
+						apis.KnativeVisibilityLabelName: "cluster-local",
+					},
+				},
+			},
+			expected: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+					Labels: map[string]string{
+						apis.WorkloadTypeLabelName: "web",
+					},
+				},
+			},
+		},
 		{
 			name: "Support comma separated list of labels",
 			args: []string{flags.LabelFlagName, "NEW=value,FOO=bar,BAR-"},
@@ -766,6 +1455,94 @@ func TestWorkloadOptionsApplyOptionsToWorkload(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "add/update/remove typed params",
+			args: []string{flags.ParamIntFlagName, "replicas=3", flags.ParamBoolFlagName, "debug=true", flags.ParamIntFlagName, "timeout-", flags.ParamFlagName, "scanning=strict"},
+			input: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					Image: "ubuntu:bionic",
+					Params: []cartov1alpha1.Param{
+						{
+							Name:  "replicas",
+							Value: apiextensionsv1.JSON{Raw: []byte(`"1"`)},
+						},
+						{
+							Name:  "timeout",
+							Value: apiextensionsv1.JSON{Raw: []byte(`30`)},
+						},
+					},
+				},
+			},
+			expected: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					Image: "ubuntu:bionic",
+					Params: []cartov1alpha1.Param{
+						{
+							Name:  "replicas",
+							Value: apiextensionsv1.JSON{Raw: []byte(`3`)},
+						},
+						{
+							Name:  "scanning",
+							Value: apiextensionsv1.JSON{Raw: []byte(`"strict"`)},
+						},
+						{
+							Name:  "debug",
+							Value: apiextensionsv1.JSON{Raw: []byte(`true`)},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "add/update pod annotation",
+			args: []string{flags.PodAnnotationFlagName, "sidecar.istio.io/inject=true", flags.PodAnnotationFlagName, "removeme-"},
+			input: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					Image: "ubuntu:bionic",
+					Params: []cartov1alpha1.Param{
+						{
+							Name:  "annotations",
+							Value: apiextensionsv1.JSON{Raw: []byte(`{"foo":"baz"}`)},
+						},
+						{
+							Name:  "pod-annotations",
+							Value: apiextensionsv1.JSON{Raw: []byte(`{"removeme":"xyz"}`)},
+						},
+					},
+				},
+			},
+			expected: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					Image: "ubuntu:bionic",
+					Params: []cartov1alpha1.Param{
+						{
+							Name:  "annotations",
+							Value: apiextensionsv1.JSON{Raw: []byte(`{"foo":"baz"}`)},
+						},
+						{
+							Name:  "pod-annotations",
+							Value: apiextensionsv1.JSON{Raw: []byte(`{"sidecar.istio.io/inject":"true"}`)},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "add maven with param yaml",
 			args: []string{flags.ParamYamlFlagName, `maven={"artifactId": "spring-petclinic", "version": "2.6.0", "groupId": "org.springframework.samples"}`},
@@ -1204,6 +1981,40 @@ func TestWorkloadOptionsApplyOptionsToWorkload(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "add/update/remove build params",
+			args: []string{flags.BuilderFlagName, "my-builder", flags.RunImageFlagName, "registry.example/run:bionic", flags.BuildCacheImageFlagName, ""},
+			input: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					Params: []cartov1alpha1.Param{
+						{Name: cartov1alpha1.WorkloadBuildCacheImageParam, Value: apiextensionsv1.JSON{Raw: []byte(`"registry.example/cache"`)}},
+						{Name: cartov1alpha1.WorkloadClusterBuilderParam, Value: apiextensionsv1.JSON{Raw: []byte(`"default"`)}},
+					},
+					Source: &cartov1alpha1.Source{
+						Image: "registry.example/source:latest",
+					},
+				},
+			},
+			expected: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					Params: []cartov1alpha1.Param{
+						{Name: cartov1alpha1.WorkloadClusterBuilderParam, Value: apiextensionsv1.JSON{Raw: []byte(`"my-builder"`)}},
+						{Name: cartov1alpha1.WorkloadRunImageParam, Value: apiextensionsv1.JSON{Raw: []byte(`"registry.example/run:bionic"`)}},
+					},
+					Source: &cartov1alpha1.Source{
+						Image: "registry.example/source:latest",
+					},
+				},
+			},
+		},
 		{
 			name: "add/update/remove build env",
 			args: []string{flags.BuildEnvFlagName, "NEW=value", flags.BuildEnvFlagName, "FOO=bar", flags.BuildEnvFlagName, "BAR-"},
@@ -1340,6 +2151,58 @@ func TestWorkloadOptionsApplyOptionsToWorkload(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "add/remove service ref secrets",
+			args: []string{flags.ServiceRefSecretFlagName, "database=my-db-credentials", flags.ServiceRefSecretFlagName, "cache-"},
+			input: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+					Annotations: map[string]string{
+						apis.ServiceClaimAnnotationName: `{"kind":"ServiceClaimsExtension","apiVersion":"supplychain.apps.x-tanzu.vmware.com/v1alpha1","spec":{"serviceClaims":{"database":{"namespace":"my-prod-ns"}}}}`,
+					},
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
+						{
+							Name: "cache",
+							Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+								APIVersion: "services.tanzu.vmware.com/v1alpha1",
+								Kind:       "redis",
+								Name:       "my-cache",
+							},
+						},
+						{
+							Name: "database",
+							Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+								APIVersion: "services.tanzu.vmware.com/v1alpha1",
+								Kind:       "PostgreSQL",
+								Name:       "my-prod-db",
+							},
+						},
+					},
+				},
+			},
+			expected: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   defaultNamespace,
+					Name:        workloadName,
+					Annotations: map[string]string{},
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					ServiceClaims: []cartov1alpha1.WorkloadServiceClaim{
+						{
+							Name: "database",
+							Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+								APIVersion: "v1",
+								Kind:       "Secret",
+								Name:       "my-db-credentials",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "add/remove service references",
 			args: []string{flags.ServiceRefFlagName, "database=services.tanzu.vmware.com/v1alpha1:PostgreSQL:my-prod-db", flags.ServiceRefFlagName, "cache-"},
@@ -1738,21 +2601,85 @@ func TestWorkloadOptionsApplyOptionsToWorkload(t *testing.T) {
 								Name:       "my-prod-db",
 							},
 						},
-						{
-							Name: "cache",
-							Ref: &cartov1alpha1.WorkloadServiceClaimReference{
-								APIVersion: "services.tanzu.vmware.com/v1alpha1",
-								Kind:       "MySQL",
-								Name:       "my-prod-cache",
-							},
+						{
+							Name: "cache",
+							Ref: &cartov1alpha1.WorkloadServiceClaimReference{
+								APIVersion: "services.tanzu.vmware.com/v1alpha1",
+								Kind:       "MySQL",
+								Name:       "my-prod-cache",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "update resources limits",
+			args: []string{flags.LimitCPUFlagName, "500m", flags.LimitMemoryFlagName, "1Gi"},
+			input: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					Image: "ubuntu:bionic",
+				},
+			},
+			expected: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					Image: "ubuntu:bionic",
+					Resources: &corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("500m"),
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "update extended resources",
+			args: []string{flags.LimitFlagName, "nvidia.com/gpu=2", flags.LimitFlagName, "example.com/fpga-", flags.RequestFlagName, "nvidia.com/gpu=1", flags.RequestFlagName, "cpu=1", flags.RequestCPUFlagName, "500m"},
+			input: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					Image: "ubuntu:bionic",
+					Resources: &corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							"example.com/fpga": resource.MustParse("1"),
+						},
+					},
+				},
+			},
+			expected: &cartov1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: defaultNamespace,
+					Name:      workloadName,
+				},
+				Spec: cartov1alpha1.WorkloadSpec{
+					Image: "ubuntu:bionic",
+					Resources: &corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							"nvidia.com/gpu": resource.MustParse("2"),
+						},
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("500m"),
+							"nvidia.com/gpu":   resource.MustParse("1"),
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "update resources limits",
-			args: []string{flags.LimitCPUFlagName, "500m", flags.LimitMemoryFlagName, "1Gi"},
+			name: "remove extended resources",
+			args: []string{flags.LimitFlagName, "nvidia.com/gpu-", flags.RequestFlagName, "nvidia.com/gpu-"},
 			input: &cartov1alpha1.Workload{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: defaultNamespace,
@@ -1760,6 +2687,14 @@ func TestWorkloadOptionsApplyOptionsToWorkload(t *testing.T) {
 				},
 				Spec: cartov1alpha1.WorkloadSpec{
 					Image: "ubuntu:bionic",
+					Resources: &corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							"nvidia.com/gpu": resource.MustParse("1"),
+						},
+						Requests: corev1.ResourceList{
+							"nvidia.com/gpu": resource.MustParse("1"),
+						},
+					},
 				},
 			},
 			expected: &cartov1alpha1.Workload{
@@ -1769,12 +2704,6 @@ func TestWorkloadOptionsApplyOptionsToWorkload(t *testing.T) {
 				},
 				Spec: cartov1alpha1.WorkloadSpec{
 					Image: "ubuntu:bionic",
-					Resources: &corev1.ResourceRequirements{
-						Limits: corev1.ResourceList{
-							corev1.ResourceCPU:    resource.MustParse("500m"),
-							corev1.ResourceMemory: resource.MustParse("1Gi"),
-						},
-					},
 				},
 			},
 		},
@@ -1831,10 +2760,19 @@ func TestWorkloadOptionsPublishLocalSourcePrivateRegistry(t *testing.T) {
 		expectedOutput: `
 Publishing source in "testdata/local-source" to "` + registryHost + `/hello:source"...
 Published source
+`,
+	}, {
+		name:     "local source to private registry with ca for the registry host",
+		args:     []string{flags.LocalPathFlagName, "testdata/local-source", flags.RegistryCAFlagName, fmt.Sprintf("%s=%s", registryHost, cert.Name()), flags.ForcePushFlagName, flags.YesFlagName},
+		input:    fmt.Sprintf("%s/hello:source", registryHost),
+		expected: fmt.Sprintf("%s/hello:source@sha256:%s", registryHost, "111d543b7736846f502387eed53be08c5ceb0a6010faaaf043409702074cf652"),
+		expectedOutput: `
+Publishing source in "testdata/local-source" to "` + registryHost + `/hello:source"...
+Published source
 `,
 	}, {
 		name:     "local source to private registry with username and pass",
-		args:     []string{flags.LocalPathFlagName, "testdata/local-source", flags.RegistryCertFlagName, cert.Name(), flags.RegistryUsernameFlagName, "admin", flags.RegistryPasswordFlagName, "password", flags.YesFlagName},
+		args:     []string{flags.LocalPathFlagName, "testdata/local-source", flags.RegistryCertFlagName, cert.Name(), flags.RegistryUsernameFlagName, "admin", flags.RegistryPasswordFlagName, "password", flags.ForcePushFlagName, flags.YesFlagName},
 		input:    fmt.Sprintf("%s/hello:source", registryHost),
 		expected: fmt.Sprintf("%s/hello:source@sha256:%s", registryHost, "111d543b7736846f502387eed53be08c5ceb0a6010faaaf043409702074cf652"),
 		expectedOutput: `
@@ -1843,7 +2781,7 @@ Published source
 `,
 	}, {
 		name:     "local source to private registry with token",
-		args:     []string{flags.LocalPathFlagName, "testdata/local-source", flags.RegistryCertFlagName, cert.Name(), flags.RegistryTokenFlagName, "myToken123", flags.YesFlagName},
+		args:     []string{flags.LocalPathFlagName, "testdata/local-source", flags.RegistryCertFlagName, cert.Name(), flags.RegistryTokenFlagName, "myToken123", flags.ForcePushFlagName, flags.YesFlagName},
 		input:    fmt.Sprintf("%s/hello:source", registryHost),
 		expected: fmt.Sprintf("%s/hello:source@sha256:%s", registryHost, "111d543b7736846f502387eed53be08c5ceb0a6010faaaf043409702074cf652"),
 		expectedOutput: `
@@ -1913,6 +2851,7 @@ func TestWorkloadOptionsPublishLocalSource(t *testing.T) {
 		args             []string
 		input            string
 		expected         string
+		stdin            string
 		shouldError      bool
 		expectedOutput   string
 		existingWorkload *cartov1alpha1.Workload
@@ -1934,6 +2873,41 @@ Published source
 		expectedOutput: `
 Publishing source in "testdata/local-source" to "` + registryHost + `/hello:source"...
 Published source
+`,
+	}, {
+		name:     "unchanged local source",
+		args:     []string{flags.LocalPathFlagName, "testdata/local-source", flags.YesFlagName},
+		input:    fmt.Sprintf("%s/hello:source", registryHost),
+		expected: fmt.Sprintf("%s/hello:source@sha256:%s", registryHost, "111d543b7736846f502387eed53be08c5ceb0a6010faaaf043409702074cf652"),
+		expectedOutput: `
+Source in "testdata/local-source" unchanged, skipping publish to "` + registryHost + `/hello:source"
+`,
+	}, {
+		name:     "local source larger than max source size",
+		args:     []string{flags.LocalPathFlagName, "testdata/local-source", flags.MaxSourceSizeFlagName, "1", flags.ForcePushFlagName, flags.YesFlagName},
+		input:    fmt.Sprintf("%s/hello:source", registryHost),
+		expected: fmt.Sprintf("%s/hello:source@sha256:%s", registryHost, "111d543b7736846f502387eed53be08c5ceb0a6010faaaf043409702074cf652"),
+		expectedOutput: `
+WARNING: the source in "testdata/local-source" is 6B, larger than --max-source-size 1. The largest files are:
+  6B       hello.txt
+Files that are not needed to build the workload can be excluded with the .tanzuignore file
+Publishing source in "testdata/local-source" to "` + registryHost + `/hello:source"...
+Published source
+`,
+	}, {
+		name:     "local source larger than max source size declined",
+		args:     []string{flags.LocalPathFlagName, "testdata/local-source", flags.MaxSourceSizeFlagName, "1", flags.ForcePushFlagName},
+		input:    fmt.Sprintf("%s/hello:source", registryHost),
+		expected: fmt.Sprintf("%s/hello:source", registryHost),
+		// the prompt asks for the position of the cursor before reading the answer
+		stdin: "\x1b[1;1R\x1b[1;1Rn\r",
+		expectedOutput: `
+WARNING: the source in "testdata/local-source" is 6B, larger than --max-source-size 1. The largest files are:
+  6B       hello.txt
+Files that are not needed to build the workload can be excluded with the .tanzuignore file
+? Publish source in "testdata/local-source" to "` + registryHost + `/hello:source"? It may be visible to others who can pull images from that repository (y/N) n
+? Publish source in "testdata/local-source" to "` + registryHost + `/hello:source"? It may be visible to others who can pull images from that repository No
+Skipping workload ""
 `,
 	}, {
 		name:     "jar file",
@@ -1993,11 +2967,13 @@ No source code is changed
 			output := &bytes.Buffer{}
 			c.Stdout = output
 			c.Stderr = output
+			c.Stdin = iotest.OneByteReader(strings.NewReader(test.stdin))
 			c.Client = clitesting.NewFakeCliClient(clitesting.NewFakeClient(scheme))
 
 			cmd := &cobra.Command{}
 			ctx := cli.WithCommand(context.Background(), cmd)
 			ctx = source.StashContainerRemoteTransport(ctx, reg.Client().Transport)
+			ctx = source.StashUploadCacheDir(ctx, t.TempDir())
 			ctx = logger.StashSourceImageLogger(ctx, logger.NewNoopLogger())
 			opts := &commands.WorkloadOptions{}
 			opts.LoadDefaults(c)
@@ -2029,13 +3005,75 @@ No source code is changed
 				t.Errorf("PublishLocalSource() wanted %q, got %q", test.expected, workload.Spec.Source.Image)
 			}
 
-			if diff := cmp.Diff(strings.TrimSpace(test.expectedOutput), strings.TrimSpace(output.String())); diff != "" {
+			if diff := cmp.Diff(strings.TrimSpace(test.expectedOutput), strings.TrimSpace(stripansi.Strip(output.String()))); diff != "" {
 				t.Errorf("PublishLocalSource() (-want, +got) = %s", diff)
 			}
 		})
 	}
 }
 
+func TestWorkloadOptionsPublishLocalSourceLineEndings(t *testing.T) {
+	reg, err := ggcrregistry.TLS("localhost")
+	utilruntime.Must(err)
+	defer reg.Close()
+	u, err := url.Parse(reg.URL)
+	utilruntime.Must(err)
+	image := fmt.Sprintf("%s/hello:source", u.Host)
+
+	// the same script, as checked out on Windows and on Linux
+	crlfDir, lfDir := t.TempDir(), t.TempDir()
+	utilruntime.Must(os.WriteFile(filepath.Join(crlfDir, "build.sh"), []byte("#!/bin/sh\r\necho hello\r\n"), 0644))
+	utilruntime.Must(os.WriteFile(filepath.Join(lfDir, "build.sh"), []byte("#!/bin/sh\necho hello\n"), 0644))
+
+	publish := func(dir, lineEndings string) (string, error) {
+		scheme := runtime.NewScheme()
+		c := cli.NewDefaultConfig("test", scheme)
+		c.Stdout = &bytes.Buffer{}
+		c.Stderr = &bytes.Buffer{}
+		c.Viper.Set(commands.SourceLineEndingsConfigKey, lineEndings)
+
+		cmd := &cobra.Command{}
+		ctx := cli.WithCommand(context.Background(), cmd)
+		ctx = source.StashContainerRemoteTransport(ctx, reg.Client().Transport)
+		ctx = source.StashUploadCacheDir(ctx, t.TempDir())
+		ctx = logger.StashSourceImageLogger(ctx, logger.NewNoopLogger())
+		opts := &commands.WorkloadOptions{}
+		opts.LoadDefaults(c)
+		opts.DefineFlags(ctx, c, cmd)
+		cmd.ParseFlags([]string{flags.LocalPathFlagName, dir, flags.ForcePushFlagName, flags.YesFlagName})
+
+		workload := &cartov1alpha1.Workload{
+			Spec: cartov1alpha1.WorkloadSpec{
+				Source: &cartov1alpha1.Source{Image: image},
+			},
+		}
+		_, err := opts.PublishLocalSource(ctx, c, nil, workload)
+		return workload.Spec.Source.Image, err
+	}
+
+	lf, err := publish(lfDir, "")
+	if err != nil {
+		t.Fatalf("PublishLocalSource() errored %v", err)
+	}
+	kept, err := publish(crlfDir, source.LineEndingsKeep)
+	if err != nil {
+		t.Fatalf("PublishLocalSource() errored %v", err)
+	}
+	if kept == lf {
+		t.Errorf("PublishLocalSource() expected CRLF line endings to be kept with %q", source.LineEndingsKeep)
+	}
+	normalized, err := publish(crlfDir, source.LineEndingsLF)
+	if err != nil {
+		t.Fatalf("PublishLocalSource() errored %v", err)
+	}
+	if normalized != lf {
+		t.Errorf("PublishLocalSource() wanted %q with CRLF line endings converted, got %q", lf, normalized)
+	}
+	if _, err := publish(crlfDir, "crlf"); err == nil || err.Error() != `invalid source-line-endings "crlf" in the plugin config, expected one of keep, lf` {
+		t.Errorf("PublishLocalSource() expected error for an invalid config, got %v", err)
+	}
+}
+
 func TestWorkloadOptionsCreate(t *testing.T) {
 	defaultNamespace := "default"
 	workloadName := "my-workload"
@@ -2172,7 +3210,7 @@ Created workload "my-workload"`,
 				client.PrependReactor("*", "*", reactor)
 			}
 
-			_, err := opts.Create(ctx, c, test.input)
+			_, err := opts.Create(ctx, c, nil, test.input)
 
 			if err != nil && !test.shouldError {
 				t.Errorf("Create() errored %v", err)
@@ -2412,9 +3450,24 @@ func TestLoadInputWorkload(t *testing.T) {
 	scheme := runtime.NewScheme()
 	c := cli.NewDefaultConfig("test", scheme)
 
+	content, err := os.ReadFile("testdata/workload.yaml")
+	utilruntime.Must(err)
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/workload.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	}))
+	defer server.Close()
+	ctx := commands.StashFileTransport(context.Background(), server.Client().Transport)
+
 	tests := []struct {
 		name        string
 		file        string
+		sha256      string
 		shouldError bool
 		stdin       io.Reader
 	}{
@@ -2453,6 +3506,36 @@ spec:
 `,
 			),
 		},
+		{
+			name:  "loads workload from url",
+			file:  server.URL + "/workload.yaml",
+			stdin: c.Stdin,
+		},
+		{
+			name:   "loads workload from url with sha256",
+			file:   server.URL + "/workload.yaml",
+			sha256: digest,
+			stdin:  c.Stdin,
+		},
+		{
+			name:   "loads workload from file with sha256",
+			file:   "testdata/workload.yaml",
+			sha256: digest,
+			stdin:  c.Stdin,
+		},
+		{
+			name:        "error with sha256 mismatch",
+			file:        server.URL + "/workload.yaml",
+			sha256:      strings.Repeat("0", 64),
+			stdin:       c.Stdin,
+			shouldError: true,
+		},
+		{
+			name:        "error downloading missing url",
+			file:        server.URL + "/missing.yaml",
+			stdin:       c.Stdin,
+			shouldError: true,
+		},
 		{
 			name:        "error loading non-existent file",
 			file:        "testdata/workload1.yaml",
@@ -2495,10 +3578,11 @@ spec:
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			opts := &commands.WorkloadOptions{
-				FilePath: test.file,
+				FilePath:   test.file,
+				FileSHA256: test.sha256,
 			}
 
-			err := opts.LoadInputWorkload(test.stdin, &cartov1alpha1.Workload{})
+			err := opts.LoadInputWorkload(ctx, test.stdin, &cartov1alpha1.Workload{})
 
 			if (err == nil) == test.shouldError {
 				t.Errorf("Load() shouldErr %t, got %v", test.shouldError, err)
@@ -2508,3 +3592,415 @@ spec:
 		})
 	}
 }
+
+func TestLoadInputWorkloadSignature(t *testing.T) {
+	content, err := os.ReadFile("testdata/workload.yaml")
+	utilruntime.Must(err)
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		utilruntime.Must(os.WriteFile(path, data, 0644))
+		return path
+	}
+
+	// cosign sign-blob signs the sha256 digest of the content and encodes the signature in base64
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	utilruntime.Must(err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&ecdsaKey.PublicKey)
+	utilruntime.Must(err)
+	digest := sha256.Sum256(content)
+	cosignSignature, err := ecdsa.SignASN1(rand.Reader, ecdsaKey, digest[:])
+	utilruntime.Must(err)
+	cosignKeyPath := write("cosign.pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+	cosignSignaturePath := write("workload.yaml.sig", []byte(base64.StdEncoding.EncodeToString(cosignSignature)+"\n"))
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	utilruntime.Must(err)
+	otherPublicKey, err := x509.MarshalPKIXPublicKey(&otherKey.PublicKey)
+	utilruntime.Must(err)
+	otherKeyPath := write("other.pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: otherPublicKey}))
+
+	entity, err := openpgp.NewEntity("Platform", "", "platform@example.com", nil)
+	utilruntime.Must(err)
+	gpgKey := &bytes.Buffer{}
+	armored, err := armor.Encode(gpgKey, openpgp.PublicKeyType, nil)
+	utilruntime.Must(err)
+	utilruntime.Must(entity.Serialize(armored))
+	utilruntime.Must(armored.Close())
+	gpgSignature := &bytes.Buffer{}
+	utilruntime.Must(openpgp.ArmoredDetachSign(gpgSignature, entity, bytes.NewReader(content), nil))
+	gpgKeyPath := write("gpg.asc", gpgKey.Bytes())
+	gpgSignaturePath := write("workload.yaml.asc", gpgSignature.Bytes())
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/workload.yaml":
+			w.Write(content)
+		case "/workload.yaml.sig":
+			w.Write([]byte(base64.StdEncoding.EncodeToString(cosignSignature)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	ctx := commands.StashFileTransport(context.Background(), server.Client().Transport)
+
+	tests := []struct {
+		name        string
+		file        string
+		signature   string
+		key         string
+		shouldError bool
+	}{{
+		name:      "cosign signature",
+		file:      "testdata/workload.yaml",
+		signature: cosignSignaturePath,
+		key:       cosignKeyPath,
+	}, {
+		name:      "cosign signature from url",
+		file:      server.URL + "/workload.yaml",
+		signature: server.URL + "/workload.yaml.sig",
+		key:       cosignKeyPath,
+	}, {
+		name:      "gpg signature",
+		file:      "testdata/workload.yaml",
+		signature: gpgSignaturePath,
+		key:       gpgKeyPath,
+	}, {
+		name:        "signature of another key",
+		file:        "testdata/workload.yaml",
+		signature:   cosignSignaturePath,
+		key:         otherKeyPath,
+		shouldError: true,
+	}, {
+		name:        "gpg signature checked with pem key",
+		file:        "testdata/workload.yaml",
+		signature:   gpgSignaturePath,
+		key:         cosignKeyPath,
+		shouldError: true,
+	}, {
+		name:        "signature of another file",
+		file:        "testdata/workload-subPath.yaml",
+		signature:   gpgSignaturePath,
+		key:         gpgKeyPath,
+		shouldError: true,
+	}, {
+		name:        "missing signature",
+		file:        server.URL + "/workload.yaml",
+		signature:   server.URL + "/missing.sig",
+		key:         cosignKeyPath,
+		shouldError: true,
+	}, {
+		name:        "missing key",
+		file:        "testdata/workload.yaml",
+		signature:   cosignSignaturePath,
+		key:         filepath.Join(dir, "missing.pub"),
+		shouldError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := &commands.WorkloadOptions{
+				FilePath:        test.file,
+				VerifySignature: test.signature,
+				SignatureKey:    test.key,
+			}
+
+			err := opts.LoadInputWorkload(ctx, nil, &cartov1alpha1.Workload{})
+			if (err != nil) != test.shouldError {
+				t.Errorf("LoadInputWorkload() error = %v, shouldError %t", err, test.shouldError)
+			}
+		})
+	}
+}
+
+func TestWorkloadOptionsResolveGitPR(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/repos/my-org/my-repo/pulls/42":
+			w.Write([]byte(`{"state":"open","head":{"ref":"feature","repo":{"full_name":"my-org/my-repo","clone_url":"https://github.com/my-org/my-repo.git"}},"base":{"ref":"main","repo":{"full_name":"my-org/my-repo","clone_url":"https://github.com/my-org/my-repo.git"}}}`))
+		case "/repos/my-org/my-repo/pulls/43":
+			w.Write([]byte(`{"state":"open","head":{"ref":"fix","repo":{"full_name":"my-fork/my-repo","clone_url":"https://github.com/my-fork/my-repo.git"}},"base":{"ref":"main","repo":{"full_name":"my-org/my-repo","clone_url":"https://github.com/my-org/my-repo.git"}}}`))
+		case "/api/v4/projects/my-group%2Fmy-repo/merge_requests/7":
+			w.Write([]byte(`{"state":"opened","source_branch":"feature","source_project_id":1,"target_project_id":1}`))
+		case "/api/v3/repos/my-org/my-repo/pulls/42":
+			w.Write([]byte(`{"state":"closed","head":{"ref":"feature","repo":{"full_name":"my-org/my-repo","clone_url":"https://github.example.com/my-org/my-repo.git"}},"base":{"ref":"main","repo":{"full_name":"my-org/my-repo","clone_url":"https://github.example.com/my-org/my-repo.git"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	// send the requests for the git providers to the test server, recording the token they carry
+	var authorization string
+	var transport roundTripperFunc = func(r *http.Request) (*http.Response, error) {
+		authorization = r.Header.Get("Authorization") + r.Header.Get("PRIVATE-TOKEN")
+		r.URL.Host = strings.TrimPrefix(server.URL, "https://")
+		return server.Client().Transport.RoundTrip(r)
+	}
+	ctx := commands.StashGitProviderTransport(context.Background(), transport)
+	t.Setenv(commands.GitHubTokenEnv, "gh-token")
+	t.Setenv(commands.GitLabTokenEnv, "gl-token")
+
+	gitWorkload := func(url, branch string) *cartov1alpha1.Workload {
+		return &cartov1alpha1.Workload{
+			Spec: cartov1alpha1.WorkloadSpec{
+				Source: &cartov1alpha1.Source{
+					Git: &cartov1alpha1.GitSource{
+						URL: url,
+						Ref: cartov1alpha1.GitRef{Branch: branch},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name                  string
+		opts                  *commands.WorkloadOptions
+		gitTokenHosts         []string
+		given                 *cartov1alpha1.Workload
+		expected              *cartov1alpha1.Workload
+		expectedAuthorization string
+		expectedOutput        string
+		shouldError           bool
+	}{{
+		name:     "no pull request",
+		opts:     &commands.WorkloadOptions{},
+		given:    gitWorkload("https://github.com/my-org/my-repo.git", "main"),
+		expected: gitWorkload("https://github.com/my-org/my-repo.git", "main"),
+	}, {
+		name:                  "github pull request",
+		opts:                  &commands.WorkloadOptions{GitPR: 42},
+		given:                 gitWorkload("https://github.com/my-org/my-repo.git", "main"),
+		expected:              gitWorkload("https://github.com/my-org/my-repo.git", "feature"),
+		expectedAuthorization: "Bearer gh-token",
+		expectedOutput: `
+Resolved pull request 42 to branch "feature" of "https://github.com/my-org/my-repo.git"
+`,
+	}, {
+		name:     "github enterprise pull request without token",
+		opts:     &commands.WorkloadOptions{GitPR: 42},
+		given:    gitWorkload("https://github.example.com/my-org/my-repo.git", "main"),
+		expected: gitWorkload("https://github.example.com/my-org/my-repo.git", "feature"),
+		expectedOutput: `
+Warning: pull request 42 of "https://github.example.com/my-org/my-repo.git" is closed
+Resolved pull request 42 to branch "feature" of "https://github.example.com/my-org/my-repo.git"
+`,
+	}, {
+		name:                  "github enterprise pull request with allowed token",
+		opts:                  &commands.WorkloadOptions{GitPR: 42},
+		gitTokenHosts:         []string{"github.example.com"},
+		given:                 gitWorkload("https://github.example.com/my-org/my-repo.git", "main"),
+		expected:              gitWorkload("https://github.example.com/my-org/my-repo.git", "feature"),
+		expectedAuthorization: "Bearer gh-token",
+		expectedOutput: `
+Warning: pull request 42 of "https://github.example.com/my-org/my-repo.git" is closed
+Resolved pull request 42 to branch "feature" of "https://github.example.com/my-org/my-repo.git"
+`,
+	}, {
+		name:  "github pull request with label",
+		opts:  &commands.WorkloadOptions{GitPR: 42, GitPRLabel: true},
+		given: gitWorkload("git@github.com:my-org/my-repo.git", ""),
+		expected: func() *cartov1alpha1.Workload {
+			w := gitWorkload("git@github.com:my-org/my-repo.git", "feature")
+			w.Labels = map[string]string{apis.PullRequestLabelName: "42"}
+			return w
+		}(),
+		expectedAuthorization: "Bearer gh-token",
+		expectedOutput: `
+Resolved pull request 42 to branch "feature" of "git@github.com:my-org/my-repo.git"
+`,
+	}, {
+		name:                  "github pull request from fork",
+		opts:                  &commands.WorkloadOptions{GitPR: 43},
+		given:                 gitWorkload("https://github.com/my-org/my-repo", ""),
+		expected:              gitWorkload("https://github.com/my-fork/my-repo.git", "fix"),
+		expectedAuthorization: "Bearer gh-token",
+		expectedOutput: `
+Resolved pull request 43 to branch "fix" of "https://github.com/my-fork/my-repo.git"
+`,
+	}, {
+		name:                  "gitlab merge request",
+		opts:                  &commands.WorkloadOptions{GitPR: 7},
+		given:                 gitWorkload("https://gitlab.com/my-group/my-repo.git", "main"),
+		expected:              gitWorkload("https://gitlab.com/my-group/my-repo.git", "feature"),
+		expectedAuthorization: "gl-token",
+		expectedOutput: `
+Resolved pull request 7 to branch "feature" of "https://gitlab.com/my-group/my-repo.git"
+`,
+	}, {
+		name:        "missing pull request",
+		opts:        &commands.WorkloadOptions{GitPR: 44},
+		given:       gitWorkload("https://github.com/my-org/my-repo.git", "main"),
+		shouldError: true,
+	}, {
+		name:        "unsupported git provider",
+		opts:        &commands.WorkloadOptions{GitPR: 42},
+		given:       gitWorkload("https://example.com/my-org/my-repo.git", "main"),
+		shouldError: true,
+	}, {
+		name:        "missing git repo",
+		opts:        &commands.WorkloadOptions{GitPR: 42},
+		given:       &cartov1alpha1.Workload{},
+		shouldError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			c := cli.NewDefaultConfig("test", scheme)
+			output := &bytes.Buffer{}
+			c.Stdout = output
+			c.Stderr = output
+			c.Viper.Set(commands.GitTokenHostsConfigKey, test.gitTokenHosts)
+			authorization = ""
+
+			err := test.opts.ResolveGitPR(ctx, c, test.given)
+			if (err != nil) != test.shouldError {
+				t.Errorf("ResolveGitPR() error = %v, shouldError %t", err, test.shouldError)
+			}
+			if test.shouldError {
+				return
+			}
+			if diff := cmp.Diff(test.expected, test.given); diff != "" {
+				t.Errorf("ResolveGitPR() (-expected, +actual) = %s", diff)
+			}
+			if diff := cmp.Diff(test.expectedAuthorization, authorization); diff != "" {
+				t.Errorf("ResolveGitPR() authorization (-expected, +actual) = %s", diff)
+			}
+			if diff := cmp.Diff(strings.TrimSpace(test.expectedOutput), strings.TrimSpace(output.String())); diff != "" {
+				t.Errorf("ResolveGitPR() output (-expected, +actual) = %s", diff)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestWorkloadOptionsValidateProtectedPrefixes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	c := cli.NewDefaultConfig("test", scheme)
+	c.Viper.Set(commands.LabelPrefixGuardConfigKey, []string{"kapp.k14s.io/", "apps.tanzu.vmware.com/", "networking.knative.dev/"})
+
+	tests := []struct {
+		name     string
+		opts     *commands.WorkloadOptions
+		expected validation.FieldErrors
+	}{
+		{
+			name: "unprotected label",
+			opts: &commands.WorkloadOptions{
+				Labels: []string{"my-label=value"},
+			},
+			expected: validation.FieldErrors{},
+		},
+		{
+			name: "protected label",
+			opts: &commands.WorkloadOptions{
+				Labels: []string{"kapp.k14s.io/app=value"},
+			},
+			expected: validation.ErrForbiddenFieldWithDetail(flags.LabelFlagName, `"kapp.k14s.io/app" uses the prefix "kapp.k14s.io/" which is owned by the platform, changing it may break controllers managing the workload. Use --force to override`),
+		},
+		{
+			name: "protected label removal",
+			opts: &commands.WorkloadOptions{
+				Labels: []string{"kapp.k14s.io/app-"},
+			},
+			expected: validation.ErrForbiddenFieldWithDetail(flags.LabelFlagName, `"kapp.k14s.io/app" uses the prefix "kapp.k14s.io/" which is owned by the platform, changing it may break controllers managing the workload. Use --force to override`),
+		},
+		{
+			name: "protected visibility",
+			opts: &commands.WorkloadOptions{
+				Visibility: "cluster-local",
+			},
+			expected: validation.ErrForbiddenFieldWithDetail(flags.VisibilityFlagName, `"networking.knative.dev/visibility" uses the prefix "networking.knative.dev/" which is owned by the platform, changing it may break controllers managing the workload. Use --force to override`),
+		},
+		{
+			name: "protected annotation and type",
+			opts: &commands.WorkloadOptions{
+				Annotations: []string{"kapp.k14s.io/change-group=value"},
+				Type:        "web",
+			},
+			expected: validation.FieldErrors{}.Also(
+				validation.ErrForbiddenFieldWithDetail(flags.AnnotationFlagName, `"kapp.k14s.io/change-group" uses the prefix "kapp.k14s.io/" which is owned by the platform, changing it may break controllers managing the workload. Use --force to override`),
+				validation.ErrForbiddenFieldWithDetail(flags.TypeFlagName, `"apps.tanzu.vmware.com/workload-type" uses the prefix "apps.tanzu.vmware.com/" which is owned by the platform, changing it may break controllers managing the workload. Use --force to override`),
+			),
+		},
+		{
+			name: "protected pod annotation",
+			opts: &commands.WorkloadOptions{
+				PodAnnotations: []string{"kapp.k14s.io/change-group-"},
+			},
+			expected: validation.ErrForbiddenFieldWithDetail(flags.PodAnnotationFlagName, `"kapp.k14s.io/change-group" uses the prefix "kapp.k14s.io/" which is owned by the platform, changing it may break controllers managing the workload. Use --force to override`),
+		},
+		{
+			name: "forced",
+			opts: &commands.WorkloadOptions{
+				Labels: []string{"kapp.k14s.io/app=value"},
+				Force:  true,
+			},
+			expected: validation.FieldErrors{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := test.opts.ValidateProtectedPrefixes(c)
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Errorf("ValidateProtectedPrefixes() (-expected, +actual) = %s", diff)
+			}
+		})
+	}
+}
+
+// prepareAuditor records the changes made by the command as alice, at a fixed time
+func prepareAuditor(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+	ctx = commands.StashClock(ctx, func() time.Time {
+		return time.Date(2022, time.June, 9, 15, 12, 0, 0, time.UTC)
+	})
+	ctx = commands.StashAuditor(ctx, &commands.Auditor{
+		Version: "v0.9.0",
+		User:    func() string { return "alice" },
+	})
+	return ctx, nil
+}
+
+func verifyExitCode(expected int) func(t *testing.T, output string, err error) {
+	return func(t *testing.T, output string, err error) {
+		if actual := cli.ExitCode(err); actual != expected {
+			t.Errorf("expected exit code %d, actually %d", expected, actual)
+		}
+	}
+}
+
+// fieldManagerClient records the field manager of the apply patches sent by the command
+type fieldManagerClient struct {
+	cli.Client
+	managers []string
+}
+
+func (c *fieldManagerClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() == types.ApplyPatchType {
+		patchOpts := &client.PatchOptions{}
+		patchOpts.ApplyOptions(opts)
+		c.managers = append(c.managers, patchOpts.FieldManager)
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func recordFieldManagers(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+	config.Client = &fieldManagerClient{Client: config.Client}
+	return ctx, nil
+}
+
+func verifyFieldManagers(expected ...string) func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+	return func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+		if diff := cmp.Diff(expected, config.Client.(*fieldManagerClient).managers); diff != "" {
+			t.Errorf("field managers (-expected, +actual) = %s", diff)
+		}
+		return nil
+	}
+}
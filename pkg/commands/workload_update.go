@@ -18,6 +18,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -55,11 +56,26 @@ func (opts *WorkloadUpdateOptions) Validate(ctx context.Context) validation.Fiel
 }
 
 func (opts *WorkloadUpdateOptions) Exec(ctx context.Context, c *cli.Config) error {
+	stdout, restoreOutput := opts.redirectOutput(c)
+	defer restoreOutput()
+
 	c.Infof("WARNING: the update command has been deprecated and will be removed in a future update. Please use \"tanzu apps workload apply\" instead.\n\n")
+	if err := opts.LoadMetadataFiles(); err != nil {
+		return err
+	}
+	if err := opts.LoadParamFiles(); err != nil {
+		return err
+	}
+	if err := opts.LoadBuildEnvFile(); err != nil {
+		return err
+	}
+	if err := opts.ValidateProtectedPrefixes(c).ToAggregate(); err != nil {
+		return err
+	}
 
 	fileWorkload := &cartov1alpha1.Workload{}
 	if opts.FilePath != "" {
-		if err := opts.WorkloadOptions.LoadInputWorkload(c.Stdin, fileWorkload); err != nil {
+		if err := opts.WorkloadOptions.LoadInputWorkload(ctx, c.Stdin, fileWorkload); err != nil {
 			return err
 		}
 
@@ -79,6 +95,7 @@ func (opts *WorkloadUpdateOptions) Exec(ctx context.Context, c *cli.Config) erro
 	if opts.Namespace == "" {
 		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
 	}
+	errs = errs.Also(validateProtectedNamespace(c, opts.Namespace, opts.AllowProtected))
 	if err := errs.ToAggregate(); err != nil {
 		return err
 	}
@@ -125,9 +142,32 @@ func (opts *WorkloadUpdateOptions) Exec(ctx context.Context, c *cli.Config) erro
 		return err
 	}
 
+	if err := opts.validateServiceRefs(ctx, c, workload.Namespace); err != nil {
+		return err
+	}
+
+	if err := opts.validateImagePullSecrets(ctx, c, workload.Namespace); err != nil {
+		return err
+	}
+
+	if err := opts.validateType(ctx, c, workload); err != nil {
+		return err
+	}
+
+	if err := opts.ResolveGitPR(ctx, c, workload); err != nil {
+		return err
+	}
+
+	if err := opts.PinImage(ctx, c, workload); err != nil {
+		return err
+	}
+
+	if err := opts.PullSourceImage(ctx, c, workload); err != nil {
+		return err
+	}
+
 	if opts.DryRun {
-		cli.DryRunResource(ctx, workload, workload.GetGroupVersionKind())
-		return nil
+		return opts.PrintDryRun(ctx, c, nil, workload)
 	}
 
 	// If user answers yes to survey prompt about publishing source, continue with workload update
@@ -149,16 +189,19 @@ func (opts *WorkloadUpdateOptions) Exec(ctx context.Context, c *cli.Config) erro
 	}
 
 	anyTail := opts.Tail || opts.TailTimestamps
-	if okToUpdate && (opts.Wait || anyTail) {
-		c.Infof("Waiting for workload %q to become ready...\n", opts.Name)
+	if okToUpdate && (opts.waiting() || anyTail) {
+		c.Infof("Waiting for workload %q to %s...\n", opts.Name, opts.waitGoal())
 
+		ctx := withPollBackoff(ctx, c)
+		latency := newLatencyRecorder(ctx, workload)
+		progress := opts.newConditionProgress(c, workload)
 		workers := []wait.Worker{
 			func(ctx context.Context) error {
 				clientWithWatch, err := watch.GetWatcher(ctx, c)
 				if err != nil {
 					panic(err)
 				}
-				return wait.UntilCondition(ctx, clientWithWatch, types.NamespacedName{Name: workload.Name, Namespace: workload.Namespace}, &cartov1alpha1.WorkloadList{}, cartov1alpha1.WorkloadReadyConditionFunc)
+				return wait.UntilCondition(ctx, clientWithWatch, types.NamespacedName{Name: workload.Name, Namespace: workload.Namespace}, &cartov1alpha1.WorkloadList{}, progress.Condition(latency.Condition(opts.waitCondition())))
 			},
 		}
 
@@ -169,19 +212,29 @@ func (opts *WorkloadUpdateOptions) Exec(ctx context.Context, c *cli.Config) erro
 					panic(err)
 				}
 				containers := []string{}
-				return logs.Tail(ctx, c, opts.Namespace, selector, containers, time.Second, opts.TailTimestamps)
+				return logs.Tail(ctx, c, opts.Namespace, selector, containers, time.Second, logs.AllLines, opts.TailTimestamps)
 			})
 		}
 
 		if err := wait.Race(ctx, opts.WaitTimeout, workers); err != nil {
 			if err == context.DeadlineExceeded {
-				c.Printf("%s timeout after %s waiting for %q to become ready\n", printer.Serrorf("Error:"), opts.WaitTimeout, workload.Name)
-				return cli.SilenceError(err)
+				c.Printf("%s timeout after %s waiting for %q to %s\n", printer.Serrorf("Error:"), opts.WaitTimeout, workload.Name, opts.waitGoal())
+				return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeTimeout))
 			}
 			c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+			if errors.Is(err, wait.ErrConditionFailed) {
+				return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeFailedCondition))
+			}
 			return cli.SilenceError(err)
 		}
-		c.Infof("Workload %q is ready\n", workload.Name)
+		c.Infof("Workload %q %s\n", workload.Name, opts.waitReached())
+		opts.printLatency(c, workload, latency.Latency())
+		if err := opts.Verify(ctx, c, workload); err != nil {
+			return err
+		}
+		if err := opts.printNameAndURL(ctx, c, stdout, workload); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -195,8 +248,9 @@ func NewWorkloadUpdateCommand(ctx context.Context, c *cli.Config) *cobra.Command
 	opts.LoadDefaults(c)
 
 	cmd := &cobra.Command{
-		Use:   "update",
-		Short: "Update configuration of an existing workload",
+		Use:     "update",
+		Aliases: []string{"u"},
+		Short:   "Update configuration of an existing workload",
 		Long: strings.TrimSpace(`
 Update configuration of an existing workload.
 
@@ -206,13 +260,7 @@ Workload configuration options include:
 - environment variables
 - services to bind
 `),
-		Example: strings.Join([]string{
-			fmt.Sprintf("%s workload update my-workload %s=false", c.Name, flags.DebugFlagName),
-			fmt.Sprintf("%s workload update my-workload %s .", c.Name, flags.LocalPathFlagName),
-			fmt.Sprintf("%s workload update my-workload %s %s", c.Name, flags.EnvFlagName, "key=value"),
-			fmt.Sprintf("%s workload update my-workload %s %s", c.Name, flags.BuildEnvFlagName, "key=value"),
-			fmt.Sprintf("%s workload update %s workload.yaml", c.Name, flags.FilePathFlagName),
-		}, "\n"),
+		Example:           examplesFor(c, "workload update"),
 		PreRunE:           cli.ValidateE(ctx, opts),
 		RunE:              cli.ExecE(ctx, c, opts),
 		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
@@ -225,5 +273,8 @@ Workload configuration options include:
 	// Define common flags
 	opts.DefineFlags(ctx, c, cmd)
 
+	// Bind flags to environment variables
+	opts.DefineEnvVars(ctx, c, cmd)
+
 	return cmd
 }
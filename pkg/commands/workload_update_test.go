@@ -19,6 +19,7 @@ package commands_test
 import (
 	"context"
 	"fmt"
+	"os"
 	"testing"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	diemetav1 "dies.dev/apis/meta/v1"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/mock"
+	rtesting "github.com/vmware-labs/reconciler-runtime/testing"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -34,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -101,6 +104,19 @@ func TestWorkloadUpdateCommand(t *testing.T) {
 
 	var cmd *cobra.Command
 
+	// removal is the merge patch of the fields removed by an update that no field manager owns, an apply
+	// cannot remove them
+	removal := func(namespace, name, patch string) rtesting.PatchRef {
+		return rtesting.PatchRef{
+			Group:     "carto.run",
+			Kind:      "Workload",
+			Namespace: namespace,
+			Name:      name,
+			PatchType: types.MergePatchType,
+			Patch:     []byte(patch),
+		}
+	}
+
 	parent := diecartov1alpha1.WorkloadBlank.
 		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
 			d.Name(workloadName)
@@ -284,6 +300,57 @@ status:
 				},
 			},
 		},
+		{
+			Name: "type from plugin config default",
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				config.Viper.Set(cli.DefaultsConfigKey, map[string]interface{}{"type": "worker"})
+				return ctx, nil
+			},
+			Args: []string{workloadName, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.WorkloadTypeLabelName, "worker")
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+		},
+		{
+			Name: "type from env var over plugin config default",
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				os.Setenv("TANZU_APPS_TYPE", "web")
+				config.Viper.Set(cli.DefaultsConfigKey, map[string]interface{}{"type": "worker"})
+				return ctx, nil
+			},
+			CleanUp: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) error {
+				os.Unsetenv("TANZU_APPS_TYPE")
+				return nil
+			},
+			Args: []string{workloadName, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+			ExpectUpdates: []client.Object{
+				parent.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddLabel(apis.WorkloadTypeLabelName, "web")
+					}).
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}),
+			},
+		},
 		{
 			Name: "override subPath for source image source",
 			Args: []string{workloadName, flags.SubPathFlagName, "./app", flags.YesFlagName},
@@ -355,7 +422,7 @@ status:
 		},
 		{
 			Name: "conflict during update",
-			Args: []string{workloadName, flags.DebugFlagName, flags.YesFlagName},
+			Args: []string{workloadName, flags.DebugFlagName, flags.YesFlagName, flags.ConflictRetriesFlagName, "0"},
 			WithReactors: []clitesting.ReactionFunc{
 				clitesting.InduceFailure("update", "Workload", clitesting.InduceFailureOpts{
 					Error: apierrors.NewConflict(schema.GroupResource{Group: "carto.run", Resource: "workloads"}, workloadName, fmt.Errorf("induced conflict")),
@@ -386,6 +453,7 @@ status:
 				},
 			},
 			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeConflict),
 			ExpectOutput: `
 WARNING: the update command has been deprecated and will be removed in a future update. Please use "tanzu apps workload apply" instead.
 
@@ -454,6 +522,7 @@ Error: conflict updating workload, the object was modified by another user; plea
 				},
 			},
 			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeTimeout),
 			ExpectOutput: `
 WARNING: the update command has been deprecated and will be removed in a future update. Please use "tanzu apps workload apply" instead.
 
@@ -533,6 +602,7 @@ Error: timeout after 1ns waiting for "my-workload" to become ready
 				},
 			},
 			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeFailedCondition),
 			ExpectOutput: `
 WARNING: the update command has been deprecated and will be removed in a future update. Please use "tanzu apps workload apply" instead.
 
@@ -555,6 +625,7 @@ To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
 Waiting for workload "my-workload" to become ready...
+  Ready=False (OopsieDoodle)
 Error: Failed to become ready: a hopefully informative message about what went wrong
 `,
 		},
@@ -631,7 +702,92 @@ To see logs:   "tanzu apps workload tail my-workload"
 To get status: "tanzu apps workload get my-workload"
 
 Waiting for workload "my-workload" to become ready...
+  Ready=True
 Workload "my-workload" is ready
+
+Latency
+   ready:   0s
+`,
+		},
+		{
+			Name: "report condition transitions while waiting for ready condition",
+			Args: []string{workloadName, flags.ImageFlagName, "ubuntu:focal", flags.WaitFlagName, flags.YesFlagName},
+			GivenObjects: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:bionic")
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionUnknown),
+							diemetav1.ConditionBlank.Type(cartov1alpha1.WorkloadResourceSubmitted).Status(metav1.ConditionUnknown),
+						)
+					}),
+			},
+			Prepare: func(t *testing.T, ctx context.Context, config *cli.Config, tc *clitesting.CommandTestCase) (context.Context, error) {
+				workload := func(conditions ...metav1.Condition) *cartov1alpha1.Workload {
+					return &cartov1alpha1.Workload{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: defaultNamespace,
+							Name:      workloadName,
+						},
+						Status: cartov1alpha1.WorkloadStatus{
+							Conditions: conditions,
+						},
+					}
+				}
+				fakeWatcher := watchfakes.NewFakeWithWatch(false, config.Client, []watch.Event{
+					{Type: watch.Modified, Object: workload(
+						metav1.Condition{Type: cartov1alpha1.WorkloadConditionReady, Status: metav1.ConditionUnknown},
+						metav1.Condition{Type: cartov1alpha1.WorkloadSupplyChainReady, Status: metav1.ConditionTrue, Reason: "Ready"},
+						metav1.Condition{Type: cartov1alpha1.WorkloadResourceSubmitted, Status: metav1.ConditionUnknown},
+					)},
+					{Type: watch.Modified, Object: workload(
+						metav1.Condition{Type: cartov1alpha1.WorkloadConditionReady, Status: metav1.ConditionTrue, Reason: "Ready"},
+						metav1.Condition{Type: cartov1alpha1.WorkloadSupplyChainReady, Status: metav1.ConditionTrue, Reason: "Ready"},
+						metav1.Condition{Type: cartov1alpha1.WorkloadResourceSubmitted, Status: metav1.ConditionTrue, Reason: "ResourceSubmissionComplete"},
+					)},
+				})
+				ctx = watchhelper.WithWatcher(ctx, fakeWatcher)
+				return ctx, nil
+			},
+			ExpectUpdates: []client.Object{
+				parent.
+					SpecDie(func(d *diecartov1alpha1.WorkloadSpecDie) {
+						d.Image("ubuntu:focal")
+					}).
+					StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+						d.ConditionsDie(
+							diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionUnknown),
+							diemetav1.ConditionBlank.Type(cartov1alpha1.WorkloadResourceSubmitted).Status(metav1.ConditionUnknown),
+						)
+					}),
+			},
+			ExpectOutput: `
+WARNING: the update command has been deprecated and will be removed in a future update. Please use "tanzu apps workload apply" instead.
+
+Update workload:
+...
+  4,  4   |metadata:
+  5,  5   |  name: my-workload
+  6,  6   |  namespace: default
+  7,  7   |spec:
+  8     - |  image: ubuntu:bionic
+      8 + |  image: ubuntu:focal
+
+Updated workload "my-workload"
+
+To see logs:   "tanzu apps workload tail my-workload"
+To get status: "tanzu apps workload get my-workload"
+
+Waiting for workload "my-workload" to become ready...
+  SupplyChainReady=True (Ready)
+  Ready=Unknown → True (Ready)
+  ResourcesSubmitted=Unknown → True (ResourceSubmissionComplete)
+Workload "my-workload" is ready
+
+Latency
+   ready:   0s
 `,
 		},
 		{
@@ -659,7 +815,7 @@ Workload "my-workload" is ready
 
 				tailer := &logs.FakeTailer{}
 				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
-				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, false).Return(nil).Once()
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, false).Return(nil).Once()
 				ctx = logs.StashTailer(ctx, tailer)
 
 				return ctx, nil
@@ -720,6 +876,9 @@ To get status: "tanzu apps workload get my-workload"
 Waiting for workload "my-workload" to become ready...
 ...tail output...
 Workload "my-workload" is ready
+
+Latency
+   ready:   0s
 `,
 		},
 		{
@@ -747,7 +906,7 @@ Workload "my-workload" is ready
 
 				tailer := &logs.FakeTailer{}
 				selector, _ := labels.Parse(fmt.Sprintf("%s=%s", cartov1alpha1.WorkloadLabelName, workloadName))
-				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, true).Return(nil).Once()
+				tailer.On("Tail", mock.Anything, "default", selector, []string{}, time.Second, logs.AllLines, true).Return(nil).Once()
 				ctx = logs.StashTailer(ctx, tailer)
 
 				return ctx, nil
@@ -809,6 +968,9 @@ To get status: "tanzu apps workload get my-workload"
 Waiting for workload "my-workload" to become ready...
 ...tail output...
 Workload "my-workload" is ready
+
+Latency
+   ready:   0s
 `,
 		},
 		{
@@ -870,6 +1032,9 @@ Workload "my-workload" is ready
 					},
 				},
 			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, "spring-petclinic", `{"metadata":{"resourceVersion":"999"},"spec":{"image":null}}`),
+			},
 			ExpectOutput: `
 WARNING: the update command has been deprecated and will be removed in a future update. Please use "tanzu apps workload apply" instead.
 
@@ -995,6 +1160,9 @@ spec:
 					},
 				},
 			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal(defaultNamespace, "spring-petclinic", `{"metadata":{"resourceVersion":"999"},"spec":{"image":null}}`),
+			},
 			ExpectOutput: `
 WARNING: the update command has been deprecated and will be removed in a future update. Please use "tanzu apps workload apply" instead.
 
@@ -1152,6 +1320,9 @@ spec:
 					},
 				},
 			},
+			ExpectPatches: []rtesting.PatchRef{
+				removal("test-namespace", workloadName, `{"metadata":{"resourceVersion":"999"},"spec":{"image":null}}`),
+			},
 			ExpectOutput: `
 WARNING: the update command has been deprecated and will be removed in a future update. Please use "tanzu apps workload apply" instead.
 
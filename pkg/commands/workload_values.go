@@ -0,0 +1,106 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/parsers"
+)
+
+// templateNoValue is printed by text/template for a value missing from the map, it is rendered as
+// empty instead, like helm does, so templates can fall back on a default
+const templateNoValue = "<no value>"
+
+// templating tells whether --file is a template to render with --values and --set
+func templating(valuesFiles, set []string) bool {
+	return len(valuesFiles) != 0 || len(set) != 0
+}
+
+// loadTemplateValues merges the values of the --values files, in order, with the --set values
+// layered on top. A dotted key of --set sets a nested value.
+func loadTemplateValues(valuesFiles, set []string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, f := range valuesFiles {
+		content, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read values file %q: %w", f, err)
+		}
+		fileValues := map[string]interface{}{}
+		if err := yaml.Unmarshal(content, &fileValues); err != nil {
+			return nil, fmt.Errorf("unable to load values file %q: %w", f, err)
+		}
+		mergeTemplateValues(values, fileValues)
+	}
+	for _, kv := range set {
+		parts := parsers.KeyValue(kv)
+		setTemplateValue(values, strings.Split(parts[0], "."), parts[1])
+	}
+	return values, nil
+}
+
+// mergeTemplateValues deep merges the src values into dst, the values of src win
+func mergeTemplateValues(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeTemplateValues(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+// setTemplateValue sets the value at the path of keys, replacing values that are not maps on the way
+func setTemplateValue(values map[string]interface{}, path []string, value string) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := values[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			values[key] = next
+		}
+		values = next
+	}
+	values[path[len(path)-1]] = value
+}
+
+// renderWorkloadTemplate renders the Go template placeholders of the content of --file, the values
+// are available as .Values along with the sprig functions helm templates use
+func renderWorkloadTemplate(name string, content []byte, values map[string]interface{}) ([]byte, error) {
+	funcs := sprig.TxtFuncMap()
+	// like helm, the templates do not read the environment, values are passed explicitly
+	delete(funcs, "env")
+	delete(funcs, "expandenv")
+
+	t, err := template.New(name).Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse template %q: %w", name, err)
+	}
+	var out bytes.Buffer
+	if err := t.Execute(&out, map[string]interface{}{"Values": values}); err != nil {
+		return nil, fmt.Errorf("unable to render template %q: %w", name, err)
+	}
+	return bytes.ReplaceAll(out.Bytes(), []byte(templateNoValue), []byte{}), nil
+}
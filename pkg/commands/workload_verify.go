@@ -0,0 +1,233 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+// verifyHTTPClient performs the HTTP smoke checks, a hanging endpoint fails the check rather than
+// blocking the command
+var verifyHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+type WorkloadVerifyOptions struct {
+	Namespace string
+	Name      string
+
+	VerifyURL      string
+	VerifyCommand  string
+	TrustVerifyCmd bool
+}
+
+var (
+	_ validation.Validatable = (*WorkloadVerifyOptions)(nil)
+	_ cli.Executable         = (*WorkloadVerifyOptions)(nil)
+)
+
+func (opts *WorkloadVerifyOptions) Validate(ctx context.Context) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+
+	if opts.Namespace == "" {
+		errs = errs.Also(validation.ErrMissingField(flags.NamespaceFlagName))
+	}
+
+	if opts.Name == "" {
+		errs = errs.Also(validation.ErrMissingField(cli.NameArgumentName))
+	} else {
+		errs = errs.Also(validation.K8sName(opts.Name, cli.NameArgumentName))
+	}
+
+	errs = errs.Also(validateVerifyURL(opts.VerifyURL))
+	return errs
+}
+
+func (opts *WorkloadVerifyOptions) Exec(ctx context.Context, c *cli.Config) error {
+	workload := &cartov1alpha1.Workload{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.Name}, workload)
+	if err != nil {
+		if !apierrs.IsNotFound(err) {
+			return err
+		}
+		c.Errorf("Workload %q not found\n", fmt.Sprintf("%s/%s", opts.Namespace, opts.Name))
+		return cli.SilenceError(err)
+	}
+
+	if ready, err := cartov1alpha1.WorkloadReadyConditionFunc(workload); !ready || err != nil {
+		err = fmt.Errorf("workload %q is not ready", workload.Name)
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+		return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeFailedCondition))
+	}
+
+	verified, err := verifyWorkload(ctx, c, workload, opts.VerifyURL, opts.VerifyCommand, opts.TrustVerifyCmd)
+	if err != nil {
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+		return cli.SilenceError(cli.WithExitCode(err, cli.ExitCodeVerifyFailed))
+	}
+	if !verified {
+		err := fmt.Errorf("no smoke check configured, use %s or %s, or annotate the workload with %q or %q", flags.VerifyURLFlagName, flags.VerifyCmdFlagName, apis.VerifyURLAnnotationName, apis.VerifyCommandAnnotationName)
+		c.Eprintf("%s %s\n", printer.Serrorf("Error:"), err)
+		return cli.SilenceError(err)
+	}
+	return nil
+}
+
+func NewWorkloadVerifyCommand(ctx context.Context, c *cli.Config) *cobra.Command {
+	opts := &WorkloadVerifyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Run smoke checks against a ready workload",
+		Long: strings.TrimSpace(`
+Run a smoke check against a workload that is ready. The check is either an HTTP
+GET that must return 200, or a command that must exit successfully. A URL that
+starts with "/" is resolved against the URL of the workload Knative service. The
+command runs in a shell with WORKLOAD_NAME, WORKLOAD_NAMESPACE and WORKLOAD_URL
+set.
+
+When no flag is set the checks are read from the "` + apis.VerifyURLAnnotationName + `"
+and "` + apis.VerifyCommandAnnotationName + `" workload annotations. The command of the
+annotation can be set by anyone allowed to edit the workload, it only runs with
+` + flags.TrustVerifyCmdFlagName + `. The same checks run after ` + flags.WaitFlagName + ` or ` + flags.TailFlagName + ` in workload
+create, update and apply.
+`),
+		Example:           examplesFor(c, "workload verify"),
+		PreRunE:           cli.ValidateE(ctx, opts),
+		RunE:              cli.ExecE(ctx, c, opts),
+		ValidArgsFunction: completion.SuggestWorkloadNames(ctx, c),
+	}
+
+	cli.Args(cmd,
+		cli.NameArg(&opts.Name),
+	)
+
+	cli.NamespaceFlag(ctx, cmd, c, &opts.Namespace)
+	cmd.RegisterFlagCompletionFunc(cli.StripDash(flags.NamespaceFlagName), completion.SuggestNamespaces(ctx, c))
+	cmd.Flags().StringVar(&opts.VerifyURL, cli.StripDash(flags.VerifyURLFlagName), "", "`url` that must answer an HTTP GET with 200, a path is resolved against the workload URL")
+	cmd.Flags().StringVar(&opts.VerifyCommand, cli.StripDash(flags.VerifyCmdFlagName), "", "shell `command` that must exit successfully")
+	cmd.Flags().BoolVar(&opts.TrustVerifyCmd, cli.StripDash(flags.TrustVerifyCmdFlagName), false, fmt.Sprintf("run the shell command of the %q annotation of the workload when %s is not set", apis.VerifyCommandAnnotationName, flags.VerifyCmdFlagName))
+	return cmd
+}
+
+func validateVerifyURL(verifyURL string) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	if verifyURL == "" || strings.HasPrefix(verifyURL, "/") {
+		return errs
+	}
+	if u, err := url.Parse(verifyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = errs.Also(validation.ErrInvalidValue(verifyURL, flags.VerifyURLFlagName))
+	}
+	return errs
+}
+
+// verifyWorkload runs the smoke checks for a ready workload, the url and command fall back to the
+// workload annotations. The command of the annotation is only run when trusted, as anyone allowed to
+// edit the workload could otherwise run commands on the machine of the user. False is returned when
+// no check is configured.
+func verifyWorkload(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload, verifyURL, verifyCommand string, trustVerifyCmd bool) (bool, error) {
+	if verifyURL == "" {
+		verifyURL = workload.Annotations[apis.VerifyURLAnnotationName]
+	}
+	if annotationCommand := workload.Annotations[apis.VerifyCommandAnnotationName]; verifyCommand == "" && annotationCommand != "" {
+		if !trustVerifyCmd {
+			c.Eprintf("%s skipping the command %q of the %q annotation, use %s to run it\n", printer.Swarnf("Warning:"), annotationCommand, apis.VerifyCommandAnnotationName, flags.TrustVerifyCmdFlagName)
+		} else {
+			c.Infof("Running the command of the %q annotation of workload %q\n", apis.VerifyCommandAnnotationName, workload.Name)
+			verifyCommand = annotationCommand
+		}
+	}
+	if verifyURL == "" && verifyCommand == "" {
+		return false, nil
+	}
+
+	workloadURL := getWorkloadURL(ctx, c, workload)
+
+	if verifyURL != "" {
+		if strings.HasPrefix(verifyURL, "/") {
+			if workloadURL == "" {
+				return true, fmt.Errorf("unable to resolve %q, workload %q has no URL", verifyURL, workload.Name)
+			}
+			verifyURL = strings.TrimSuffix(workloadURL, "/") + verifyURL
+		}
+		c.Infof("Verifying workload %q with GET %s\n", workload.Name, verifyURL)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, verifyURL, nil)
+		if err != nil {
+			return true, err
+		}
+		resp, err := verifyHTTPClient.Do(req)
+		if err != nil {
+			return true, fmt.Errorf("smoke check failed: %w", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return true, fmt.Errorf("smoke check failed: GET %s returned %d, expected %d", verifyURL, resp.StatusCode, http.StatusOK)
+		}
+	}
+
+	if verifyCommand != "" {
+		c.Infof("Verifying workload %q with %q\n", workload.Name, verifyCommand)
+		cmd := c.Exec(ctx, "sh", "-c", verifyCommand)
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env,
+			fmt.Sprintf("WORKLOAD_NAME=%s", workload.Name),
+			fmt.Sprintf("WORKLOAD_NAMESPACE=%s", workload.Namespace),
+			fmt.Sprintf("WORKLOAD_URL=%s", workloadURL),
+		)
+		cmd.Stdout = c.Stdout
+		cmd.Stderr = c.Stderr
+		if err := cmd.Run(); err != nil {
+			return true, fmt.Errorf("smoke check failed: %w", err)
+		}
+	}
+
+	c.Successf("Workload %q verified\n", workload.Name)
+	return true, nil
+}
+
+// getWorkloadURL returns the URL of the Knative service created for the workload, if any
+func getWorkloadURL(ctx context.Context, c *cli.Config, workload *cartov1alpha1.Workload) string {
+	ksvcs := &knativeservingv1.ServiceList{}
+	if err := c.List(ctx, ksvcs, client.InNamespace(workload.Namespace), client.MatchingLabels{cartov1alpha1.WorkloadLabelName: workload.Name}); err != nil {
+		return ""
+	}
+	for _, ksvc := range ksvcs.Items {
+		if ksvc.Status.URL != "" {
+			return ksvc.Status.URL
+		}
+	}
+	return ""
+}
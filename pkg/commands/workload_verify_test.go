@@ -0,0 +1,298 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	diemetav1 "dies.dev/apis/meta/v1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	diecartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/cartographer/v1alpha1"
+	diev1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/dies/knative/serving/v1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestWorkloadVerifyOptionsValidate(t *testing.T) {
+	table := clitesting.ValidatableTestSuite{
+		{
+			Name:        "invalid empty",
+			Validatable: &commands.WorkloadVerifyOptions{},
+			ExpectFieldErrors: validation.FieldErrors{}.Also(
+				validation.ErrMissingField(flags.NamespaceFlagName),
+				validation.ErrMissingField(cli.NameArgumentName),
+			),
+		},
+		{
+			Name: "valid",
+			Validatable: &commands.WorkloadVerifyOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid name",
+			Validatable: &commands.WorkloadVerifyOptions{
+				Namespace: "default",
+				Name:      "my-",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("my-", cli.NameArgumentName),
+		},
+		{
+			Name: "verify url",
+			Validatable: &commands.WorkloadVerifyOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				VerifyURL: "https://my-workload.example.com/healthz",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "verify path",
+			Validatable: &commands.WorkloadVerifyOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				VerifyURL: "/healthz",
+			},
+			ShouldValidate: true,
+		},
+		{
+			Name: "invalid verify url",
+			Validatable: &commands.WorkloadVerifyOptions{
+				Namespace: "default",
+				Name:      "my-workload",
+				VerifyURL: "ftp://my-workload.example.com",
+			},
+			ExpectFieldErrors: validation.ErrInvalidValue("ftp://my-workload.example.com", flags.VerifyURLFlagName),
+		},
+	}
+
+	table.Run(t)
+}
+
+func TestWorkloadVerifyCommand(t *testing.T) {
+	workloadName := "my-workload"
+	defaultNamespace := "default"
+
+	scheme := runtime.NewScheme()
+	_ = cartov1alpha1.AddToScheme(scheme)
+	_ = knativeservingv1.AddToScheme(scheme)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	parent := diecartov1alpha1.WorkloadBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+		})
+	ready := parent.
+		StatusDie(func(d *diecartov1alpha1.WorkloadStatusDie) {
+			d.ConditionsDie(
+				diecartov1alpha1.WorkloadConditionReadyBlank.Status(metav1.ConditionTrue),
+			)
+		})
+	ksvc := diev1.ServiceBlank.
+		MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+			d.Name(workloadName)
+			d.Namespace(defaultNamespace)
+			d.AddLabel(cartov1alpha1.WorkloadLabelName, workloadName)
+		}).
+		StatusDie(func(d *diev1.ServiceStatusDie) {
+			d.URL(server.URL)
+		})
+
+	table := clitesting.CommandTestSuite{
+		{
+			Name:        "empty",
+			Args:        []string{},
+			ShouldError: true,
+		},
+		{
+			Name:        "missing workload",
+			Args:        []string{workloadName},
+			ShouldError: true,
+			ExpectOutput: `
+Workload "default/my-workload" not found
+`,
+		},
+		{
+			Name: "failed to get workload",
+			Args: []string{workloadName},
+			WithReactors: []clitesting.ReactionFunc{
+				clitesting.InduceFailure("get", "Workload"),
+			},
+			ShouldError: true,
+		},
+		{
+			Name:         "workload not ready",
+			Args:         []string{workloadName, flags.VerifyURLFlagName, server.URL + "/healthz"},
+			GivenObjects: []client.Object{parent},
+			ShouldError:  true,
+			Verify:       verifyExitCode(cli.ExitCodeFailedCondition),
+			ExpectOutput: `
+Error: workload "my-workload" is not ready
+`,
+		},
+		{
+			Name:         "no smoke check",
+			Args:         []string{workloadName},
+			GivenObjects: []client.Object{ready},
+			ShouldError:  true,
+			ExpectOutput: fmt.Sprintf(`
+Error: no smoke check configured, use %s or %s, or annotate the workload with %q or %q
+`, flags.VerifyURLFlagName, flags.VerifyCmdFlagName, apis.VerifyURLAnnotationName, apis.VerifyCommandAnnotationName),
+		},
+		{
+			Name:         "verify url",
+			Args:         []string{workloadName, flags.VerifyURLFlagName, server.URL + "/healthz"},
+			GivenObjects: []client.Object{ready},
+			ExpectOutput: `
+Verifying workload "my-workload" with GET ` + server.URL + `/healthz
+Workload "my-workload" verified
+`,
+		},
+		{
+			Name:         "verify url fails",
+			Args:         []string{workloadName, flags.VerifyURLFlagName, server.URL + "/broken"},
+			GivenObjects: []client.Object{ready},
+			ShouldError:  true,
+			Verify:       verifyExitCode(cli.ExitCodeVerifyFailed),
+			ExpectOutput: `
+Verifying workload "my-workload" with GET ` + server.URL + `/broken
+Error: smoke check failed: GET ` + server.URL + `/broken returned 503, expected 200
+`,
+		},
+		{
+			Name:         "verify path of workload url",
+			Args:         []string{workloadName, flags.VerifyURLFlagName, "/healthz"},
+			GivenObjects: []client.Object{ready, ksvc},
+			ExpectOutput: `
+Verifying workload "my-workload" with GET ` + server.URL + `/healthz
+Workload "my-workload" verified
+`,
+		},
+		{
+			Name:         "verify path without workload url",
+			Args:         []string{workloadName, flags.VerifyURLFlagName, "/healthz"},
+			GivenObjects: []client.Object{ready},
+			ShouldError:  true,
+			Verify:       verifyExitCode(cli.ExitCodeVerifyFailed),
+			ExpectOutput: `
+Error: unable to resolve "/healthz", workload "my-workload" has no URL
+`,
+		},
+		{
+			Name: "verify url from annotation",
+			Args: []string{workloadName},
+			GivenObjects: []client.Object{
+				ready.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.VerifyURLAnnotationName, "/healthz")
+					}),
+				ksvc,
+			},
+			ExpectOutput: `
+Verifying workload "my-workload" with GET ` + server.URL + `/healthz
+Workload "my-workload" verified
+`,
+		},
+		{
+			Name:         "verify command",
+			Args:         []string{workloadName, flags.VerifyCmdFlagName, "./smoke-test.sh"},
+			GivenObjects: []client.Object{ready, ksvc},
+			ExecHelper:   "VerifyCommand",
+			ExpectOutput: `
+Verifying workload "my-workload" with "./smoke-test.sh"
+smoke test passed for my-workload
+Workload "my-workload" verified
+`,
+		},
+		{
+			Name: "verify command from annotation ignored without trust",
+			Args: []string{workloadName},
+			GivenObjects: []client.Object{
+				ready.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.VerifyCommandAnnotationName, "./smoke-test.sh")
+					}),
+				ksvc,
+			},
+			ExecHelper:  "VerifyCommand",
+			ShouldError: true,
+			ExpectOutput: fmt.Sprintf(`
+Warning: skipping the command "./smoke-test.sh" of the %q annotation, use %s to run it
+Error: no smoke check configured, use %s or %s, or annotate the workload with %q or %q
+`, apis.VerifyCommandAnnotationName, flags.TrustVerifyCmdFlagName, flags.VerifyURLFlagName, flags.VerifyCmdFlagName, apis.VerifyURLAnnotationName, apis.VerifyCommandAnnotationName),
+		},
+		{
+			Name: "verify command from annotation fails",
+			Args: []string{workloadName, flags.TrustVerifyCmdFlagName},
+			GivenObjects: []client.Object{
+				ready.
+					MetadataDie(func(d *diemetav1.ObjectMetaDie) {
+						d.AddAnnotation(apis.VerifyCommandAnnotationName, "./smoke-test.sh")
+					}),
+			},
+			ExecHelper:  "VerifyCommand",
+			ShouldError: true,
+			Verify:      verifyExitCode(cli.ExitCodeVerifyFailed),
+			ExpectOutput: `
+Running the command of the "apps.tanzu.vmware.com/verify-cmd" annotation of workload "my-workload"
+Verifying workload "my-workload" with "./smoke-test.sh"
+smoke test failed, no url
+Error: smoke check failed: exit status 1
+`,
+		},
+	}
+
+	table.Run(t, scheme, func(ctx context.Context, c *cli.Config) *cobra.Command {
+		return commands.NewWorkloadVerifyCommand(ctx, c)
+	})
+}
+
+func TestHelperProcess_VerifyCommand(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	if os.Getenv("WORKLOAD_URL") == "" {
+		fmt.Println("smoke test failed, no url")
+		os.Exit(1)
+	}
+	fmt.Printf("smoke test passed for %s\n", os.Getenv("WORKLOAD_NAME"))
+	os.Exit(0)
+}
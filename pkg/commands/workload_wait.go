@@ -0,0 +1,144 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/validation"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/wait"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+const waitForConditionPrefix = "condition="
+
+// waitForCondition is a condition of the workload, or of a resource of its supply chain, waited for with --wait-for
+type waitForCondition struct {
+	Resource string
+	Type     string
+	Status   metav1.ConditionStatus
+}
+
+// parseWaitFor parses the "condition=[resource/]type[=status]" value of --wait-for, the status defaults to True
+func parseWaitFor(value string) (waitForCondition, error) {
+	if !strings.HasPrefix(value, waitForConditionPrefix) {
+		return waitForCondition{}, fmt.Errorf("expected %q prefix", waitForConditionPrefix)
+	}
+	condition := waitForCondition{Status: metav1.ConditionTrue}
+	name := strings.TrimPrefix(value, waitForConditionPrefix)
+	if i := strings.Index(name, "="); i != -1 {
+		switch status := name[i+1:]; strings.ToLower(status) {
+		case "true":
+			condition.Status = metav1.ConditionTrue
+		case "false":
+			condition.Status = metav1.ConditionFalse
+		case "unknown":
+			condition.Status = metav1.ConditionUnknown
+		default:
+			return waitForCondition{}, fmt.Errorf("unknown status %q", status)
+		}
+		name = name[:i]
+	}
+	if i := strings.Index(name, "/"); i != -1 {
+		condition.Resource = name[:i]
+		name = name[i+1:]
+		if condition.Resource == "" {
+			return waitForCondition{}, fmt.Errorf("empty resource name")
+		}
+	}
+	if name == "" {
+		return waitForCondition{}, fmt.Errorf("empty condition type")
+	}
+	condition.Type = name
+	return condition, nil
+}
+
+func validateWaitFor(values []string) validation.FieldErrors {
+	errs := validation.FieldErrors{}
+	for _, value := range values {
+		if _, err := parseWaitFor(value); err != nil {
+			errs = errs.Also(validation.ErrInvalidValue(value, flags.WaitForFlagName))
+		}
+	}
+	return errs
+}
+
+// waiting is true when the command waits for the workload after it is applied, the URL printed with
+// --output name-and-url is only known once the workload is ready
+func (opts *WorkloadOptions) waiting() bool {
+	return opts.Wait || len(opts.WaitFor) != 0 || opts.Output == OutputFormatNameAndURL
+}
+
+// waitCondition is the condition waited for, the workload becoming ready or, with --wait-for, every
+// condition of the flag being met
+func (opts *WorkloadOptions) waitCondition() wait.ConditionFunc {
+	if len(opts.WaitFor) == 0 {
+		return cartov1alpha1.WorkloadReadyConditionFunc
+	}
+	conditions := []wait.ConditionFunc{}
+	for _, value := range opts.WaitFor {
+		// parse errors are handled by the opt validation
+		c, _ := parseWaitFor(value)
+		conditions = append(conditions, cartov1alpha1.WorkloadConditionFunc(c.Resource, c.Type, c.Status))
+	}
+	return func(obj client.Object) (bool, error) {
+		met := true
+		for _, condition := range conditions {
+			done, err := condition(obj)
+			if err != nil {
+				return true, err
+			}
+			met = met && done
+		}
+		return met, nil
+	}
+}
+
+// waitGoal describes what the workload is waited for, to complete "Waiting for workload to ..."
+func (opts *WorkloadOptions) waitGoal() string {
+	if len(opts.WaitFor) == 0 {
+		return "become ready"
+	}
+	return "meet " + strings.Join(opts.WaitFor, ", ")
+}
+
+// waitReached describes the workload once it reached the wait goal, to complete "Workload ..."
+func (opts *WorkloadOptions) waitReached() string {
+	if len(opts.WaitFor) == 0 {
+		return "is ready"
+	}
+	return "met " + strings.Join(opts.WaitFor, ", ")
+}
+
+// withPollBackoff stashes the backoff of the plugin config spacing the polls of the cluster while
+// waiting, an invalid backoff is ignored with a warning
+func withPollBackoff(ctx context.Context, c *cli.Config) context.Context {
+	backoff, err := wait.BackoffFromConfig(c.Viper)
+	if err != nil {
+		c.Eprintf("%s %s, polling with the default backoff\n", printer.Swarnf("Warning:"), err)
+		return ctx
+	}
+	return wait.StashBackoff(ctx, backoff)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"context"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+)
+
+// SuggestContexts suggests the contexts of the kubeconfig set with --kubeconfig, or else found the
+// same way the client finds it, through the KUBECONFIG env var or in $HOME/.kube/config
+func SuggestContexts(ctx context.Context, c *cli.Config) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		suggestions := []string{}
+		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+		loadingRules.ExplicitPath = c.KubeConfigFile
+		kubeConfig, err := loadingRules.Load()
+		if err != nil {
+			return suggestions, cobra.ShellCompDirectiveError
+		}
+		for name := range kubeConfig.Contexts {
+			suggestions = append(suggestions, name)
+		}
+		sort.Strings(suggestions)
+		return suggestions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
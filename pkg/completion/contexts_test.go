@@ -0,0 +1,103 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+)
+
+func TestSuggestContexts(t *testing.T) {
+	dir := t.TempDir()
+	kubeConfig := filepath.Join(dir, "config")
+	if err := os.WriteFile(kubeConfig, []byte(`
+apiVersion: v1
+kind: Config
+clusters:
+- name: build
+  cluster:
+    server: https://build.example.com
+- name: run
+  cluster:
+    server: https://run.example.com
+users:
+- name: developer
+contexts:
+- name: run-cluster
+  context:
+    cluster: run
+    user: developer
+- name: build-cluster
+  context:
+    cluster: build
+    user: developer
+current-context: build-cluster
+`), 0600); err != nil {
+		t.Fatalf("unable to write kubeconfig: %v", err)
+	}
+	invalidKubeConfig := filepath.Join(dir, "invalid")
+	if err := os.WriteFile(invalidKubeConfig, []byte("contexts: {"), 0600); err != nil {
+		t.Fatalf("unable to write kubeconfig: %v", err)
+	}
+
+	tests := []struct {
+		name               string
+		kubeConfig         string
+		sugestions         []string
+		shellCompDirective cobra.ShellCompDirective
+	}{{
+		name:               "contexts",
+		kubeConfig:         kubeConfig,
+		sugestions:         []string{"build-cluster", "run-cluster"},
+		shellCompDirective: cobra.ShellCompDirectiveNoFileComp,
+	}, {
+		name:               "missing kubeconfig",
+		kubeConfig:         filepath.Join(dir, "missing"),
+		sugestions:         []string{},
+		shellCompDirective: cobra.ShellCompDirectiveError,
+	}, {
+		name:               "invalid kubeconfig",
+		kubeConfig:         invalidKubeConfig,
+		sugestions:         []string{},
+		shellCompDirective: cobra.ShellCompDirectiveError,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := cli.NewDefaultConfig("test", runtime.NewScheme())
+			c.KubeConfigFile = test.kubeConfig
+
+			suggestions, shellCompDirective := completion.SuggestContexts(ctx, c)(&cobra.Command{}, []string{}, "")
+			if diff := cmp.Diff(test.sugestions, suggestions); diff != "" {
+				t.Errorf("SuggestContexts() (-want, +got) = %s", diff)
+			}
+			if diff := cmp.Diff(test.shellCompDirective, shellCompDirective); diff != "" {
+				t.Errorf("SuggestContexts() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+)
+
+func SuggestNamespaces(ctx context.Context, c *cli.Config) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		suggestions := []string{}
+		namespaces := &corev1.NamespaceList{}
+
+		err := c.List(ctx, namespaces)
+		if err != nil {
+			return suggestions, cobra.ShellCompDirectiveError
+		}
+		for _, ns := range namespaces.Items {
+			suggestions = append(suggestions, ns.Name)
+		}
+		return suggestions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
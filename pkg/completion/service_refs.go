@@ -0,0 +1,69 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+// ServiceRefKinds are the kinds of resources suggested as services to bind to a workload
+var ServiceRefKinds = []schema.GroupVersionKind{
+	{Group: "services.apps.tanzu.vmware.com", Version: "v1alpha1", Kind: "ClassClaim"},
+	{Group: "services.apps.tanzu.vmware.com", Version: "v1alpha1", Kind: "ResourceClaim"},
+	{Group: "", Version: "v1", Kind: "Secret"},
+}
+
+// SuggestServiceRefs suggests object references for the services found in the namespace, once the
+// service ref name is typed ("service-ref-name=apiVersion:kind:service-binding-name")
+func SuggestServiceRefs(ctx context.Context, c *cli.Config) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		suggestions := []string{}
+		i := strings.Index(toComplete, "=")
+		if i < 0 {
+			return suggestions, cobra.ShellCompDirectiveNoFileComp
+		}
+		prefix := toComplete[:i+1]
+
+		namespace := cmd.Flag(cli.StripDash(flags.NamespaceFlagName)).Value.String()
+		if namespace == "" {
+			namespace = c.DefaultNamespace()
+		}
+		for _, gvk := range ServiceRefKinds {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			// the kind may not be installed on the cluster, skip it
+			if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+				continue
+			}
+			apiVersion := gvk.GroupVersion().String()
+			for _, item := range list.Items {
+				suggestions = append(suggestions, fmt.Sprintf("%s%s:%s:%s", prefix, apiVersion, gvk.Kind, item.GetName()))
+			}
+		}
+		return suggestions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
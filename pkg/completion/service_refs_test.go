@@ -0,0 +1,126 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	clitesting "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/testing"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/completion"
+)
+
+func TestSuggestServiceRefs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	claimGVK := schema.GroupVersionKind{Group: "services.apps.tanzu.vmware.com", Version: "v1alpha1", Kind: "ResourceClaim"}
+	scheme.AddKnownTypeWithName(claimGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(claimGVK.GroupVersion().WithKind("ResourceClaimList"), &unstructured.UnstructuredList{})
+
+	claim := &unstructured.Unstructured{}
+	claim.SetGroupVersionKind(claimGVK)
+	claim.SetNamespace("default")
+	claim.SetName("petclinic-db")
+
+	tests := []struct {
+		name               string
+		toComplete         string
+		given              []client.Object
+		reactor            clitesting.ReactionFunc
+		sugestions         []string
+		shellCompDirective cobra.ShellCompDirective
+	}{{
+		name:               "missing service ref name",
+		toComplete:         "db",
+		given:              []client.Object{claim},
+		sugestions:         []string{},
+		shellCompDirective: cobra.ShellCompDirectiveNoFileComp,
+	}, {
+		name:       "services",
+		toComplete: "db=",
+		given: []client.Object{
+			claim,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "petclinic-creds",
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "other",
+					Name:      "other-creds",
+				},
+			},
+		},
+		sugestions: []string{
+			"db=services.apps.tanzu.vmware.com/v1alpha1:ResourceClaim:petclinic-db",
+			"db=v1:Secret:petclinic-creds",
+		},
+		shellCompDirective: cobra.ShellCompDirectiveNoFileComp,
+	}, {
+		name:       "list error skips kind",
+		toComplete: "db=",
+		given: []client.Object{
+			claim,
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "petclinic-creds",
+				},
+			},
+		},
+		reactor: clitesting.InduceFailure("list", "SecretList"),
+		sugestions: []string{
+			"db=services.apps.tanzu.vmware.com/v1alpha1:ResourceClaim:petclinic-db",
+		},
+		shellCompDirective: cobra.ShellCompDirectiveNoFileComp,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.TODO()
+
+			c := cli.NewDefaultConfig("test", scheme)
+			client := clitesting.NewFakeClient(scheme, test.given...)
+			if test.reactor != nil {
+				client.AddReactor("*", "*", test.reactor)
+			}
+			c.Client = clitesting.NewFakeCliClient(client)
+			cmd := &cobra.Command{}
+			cmd.Flags().String("namespace", "default", "")
+
+			suggestions, directive := completion.SuggestServiceRefs(ctx, c)(cmd, []string{}, test.toComplete)
+			if diff := cmp.Diff(suggestions, test.sugestions); diff != "" {
+				t.Errorf("SuggestServiceRefs() sugestions (-want, +got) = %v", diff)
+			}
+			if want, got := test.shellCompDirective, directive; want != got {
+				t.Errorf("SuggestServiceRefs() ShellCompDirective: want %d, got %d", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+)
+
+// SuggestWorkloadTypes suggests the workload types selected by the cluster supply chains
+func SuggestWorkloadTypes(ctx context.Context, c *cli.Config) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		suggestions := []string{}
+		clustersupplychains := &cartov1alpha1.ClusterSupplyChainList{}
+
+		err := c.List(ctx, clustersupplychains)
+		if err != nil {
+			return suggestions, cobra.ShellCompDirectiveError
+		}
+		types := sets.NewString()
+		for i := range clustersupplychains.Items {
+			types.Insert(clustersupplychains.Items[i].WorkloadTypes()...)
+		}
+		suggestions = append(suggestions, types.List()...)
+		return suggestions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -293,3 +293,10 @@ func (d *ServiceStatusDie) URL(v string) *ServiceStatusDie {
 		r.URL = v
 	})
 }
+
+// LatestReadyRevisionName holds the name of the latest Revision stamped out from this Service's Configuration that has had its "Ready" condition become "True".
+func (d *ServiceStatusDie) LatestReadyRevisionName(v string) *ServiceStatusDie {
+	return d.DieStamp(func(r *servingv1.ServiceStatus) {
+		r.LatestReadyRevisionName = v
+	})
+}
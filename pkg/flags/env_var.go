@@ -24,10 +24,22 @@ var (
 	EnvVarAllowedList = map[string]struct{}{
 		FlagToEnvVar(RegistryCertFlagName):     {},
 		FlagToEnvVar(RegistryPasswordFlagName): {},
+		FlagToEnvVar(RegistryProxyFlagName):    {},
 		FlagToEnvVar(RegistryTokenFlagName):    {},
 		FlagToEnvVar(RegistryUsernameFlagName): {},
 		FlagToEnvVar(TypeFlagName):             {},
 	}
+	// DefaultsAllowedList are the flags of workload commands that can be defaulted from the plugin config
+	DefaultsAllowedList = map[string]struct{}{
+		MaxSourceSizeFlagName:    {},
+		RegistryCertFlagName:     {},
+		RegistryPasswordFlagName: {},
+		RegistryProxyFlagName:    {},
+		RegistryTokenFlagName:    {},
+		RegistryUsernameFlagName: {},
+		TypeFlagName:             {},
+		WaitTimeoutFlagName:      {},
+	}
 )
 
 func FlagToEnvVar(name string) string {
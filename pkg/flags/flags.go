@@ -21,57 +21,145 @@ import (
 )
 
 const (
-	AllFlagName              = "--all"
-	AllNamespacesFlagName    = cli.AllNamespacesFlagName
-	AnnotationFlagName       = "--annotation"
-	AppFlagName              = "--app"
-	BuildEnvFlagName         = "--build-env"
-	ComponentFlagName        = "--component"
-	ConfigFlagName           = "--config"
-	ContextFlagName          = cli.ContextFlagName
-	DebugFlagName            = "--debug"
-	DryRunFlagName           = "--dry-run"
-	EnvFlagName              = "--env"
-	ExportFlagName           = "--export"
-	FilePathFlagName         = "--file"
-	GitBranchFlagName        = "--git-branch"
-	GitCommitFlagName        = "--git-commit"
-	GitFlagWildcard          = "--git-*"
-	GitRepoFlagName          = "--git-repo"
-	GitTagFlagName           = "--git-tag"
-	ImageFlagName            = "--image"
-	KubeConfigFlagName       = cli.KubeConfigFlagName
-	LabelFlagName            = "--label"
-	LimitCPUFlagName         = "--limit-cpu"
-	LimitMemoryFlagName      = "--limit-memory"
-	LiveUpdateFlagName       = "--live-update"
-	LocalPathFlagName        = "--local-path"
-	MavenArtifactFlagName    = "--maven-artifact"
-	MavenGroupFlagName       = "--maven-group"
-	MavenTypeFlagName        = "--maven-type"
-	MavenVersionFlagName     = "--maven-version"
-	NamespaceFlagName        = cli.NamespaceFlagName
-	NoColorFlagName          = cli.NoColorFlagName
-	OutputFlagName           = "--output"
-	ParamFlagName            = "--param"
-	ParamYamlFlagName        = "--param-yaml"
-	RegistryCertFlagName     = "--registry-ca-cert"
-	RegistryPasswordFlagName = "--registry-password"
-	RegistryTokenFlagName    = "--registry-token"
-	RegistryUsernameFlagName = "--registry-username"
-	RequestCPUFlagName       = "--request-cpu"
-	RequestMemoryFlagName    = "--request-memory"
-	ServiceAccountFlagName   = "--service-account"
-	ServiceRefFlagName       = "--service-ref"
-	SinceFlagName            = "--since"
-	SourceImageFlagName      = "--source-image"
-	SubPathFlagName          = "--sub-path"
-	TailFlagName             = "--tail"
-	TimestampFlagName        = "--timestamp"
-	TailTimestampFlagName    = "--tail-timestamp"
-	TypeFlagName             = "--type"
-	VerboseLevelFlagName     = "--verbose"
-	WaitFlagName             = "--wait"
-	WaitTimeoutFlagName      = "--wait-timeout"
-	YesFlagName              = "--yes"
+	AllFlagName               = "--all"
+	AllMessagesFlagName       = "--all-messages"
+	AllowProtectedFlagName    = "--allow-protected"
+	AllNamespacesFlagName     = cli.AllNamespacesFlagName
+	AnnotationFlagName        = "--annotation"
+	AnnotationFileFlagName    = "--annotation-file"
+	AppFlagName               = "--app"
+	AuditFlagName             = "--audit"
+	BuildCacheImageFlagName   = "--build-cache-image"
+	BuildEnvFlagName          = "--build-env"
+	BuildEnvFileFlagName      = "--build-env-file"
+	BuilderFlagName           = "--builder"
+	ComponentFlagName         = "--component"
+	ConfigFlagName            = "--config"
+	ConflictRetriesFlagName   = "--conflict-retries"
+	ContextFlagName           = cli.ContextFlagName
+	CreateNamespaceFlagName   = "--create-namespace"
+	DebounceFlagName          = "--debounce"
+	DebugFlagName             = "--debug"
+	DiffToolFlagName          = "--diff-tool"
+	DryRunFlagName            = "--dry-run"
+	EnvFlagName               = "--env"
+	ErrorFormatFlagName       = cli.ErrorFormatFlagName
+	EventsFlagName            = "--events"
+	ExportFlagName            = "--export"
+	ExportDeliverableFlagName = "--export-deliverable"
+	ExportProfileFlagName     = "--export-profile"
+	FieldManagerFlagName      = "--field-manager"
+	FieldSelectorFlagName     = "--field-selector"
+	FilePathFlagName          = "--file"
+	FileSHA256FlagName        = "--file-sha256"
+	FollowFlagName            = "--follow"
+	ForceFlagName             = "--force"
+	ForceConflictsFlagName    = "--force-conflicts"
+	ForcePushFlagName         = "--force-push"
+	FromFlagName              = "--from"
+	FromImageScanFlagName     = "--from-image-scan"
+	FromListFlagName          = "--from-list"
+	GitBranchFlagName         = "--git-branch"
+	GitCommitFlagName         = "--git-commit"
+	GitFlagWildcard           = "--git-*"
+	GitPRFlagName             = "--git-pr"
+	GitPRLabelFlagName        = "--git-pr-label"
+	GitRepoFlagName           = "--git-repo"
+	GitTagFlagName            = "--git-tag"
+	GitTokenFlagName          = "--git-token"
+	GroupByFlagName           = "--group-by"
+	IgnoreUnknownFlagName     = "--ignore-unknown"
+	ImageFlagName             = "--image"
+	ImagePinFlagName          = "--image-pin"
+	ImagePullSecretFlagName   = "--image-pull-secret"
+	InactiveFlagName          = "--inactive"
+	IncludeSummaryFlagName    = "--include-summary"
+	KubeConfigFlagName        = cli.KubeConfigFlagName
+	LabelFlagName             = "--label"
+	LabelFileFlagName         = "--label-file"
+	LimitFlagName             = "--limit"
+	LimitCPUFlagName          = "--limit-cpu"
+	LimitMemoryFlagName       = "--limit-memory"
+	LinesFlagName             = "--lines"
+	LiveUpdateFlagName        = "--live-update"
+	LocalPathFlagName         = "--local-path"
+	MavenArtifactFlagName     = "--maven-artifact"
+	MavenGroupFlagName        = "--maven-group"
+	MavenTypeFlagName         = "--maven-type"
+	MavenVersionFlagName      = "--maven-version"
+	MaxSourceSizeFlagName     = "--max-source-size"
+	NamespaceFlagName         = cli.NamespaceFlagName
+	NamespaceLabelFlagName    = "--namespace-label"
+	NoColorFlagName           = cli.NoColorFlagName
+	NoReconnectFlagName       = "--no-reconnect"
+	OfflineFlagName           = "--offline"
+	OlderThanFlagName         = "--older-than"
+	OutputFlagName            = "--output"
+	OutputFormatFlagName      = "--output-format"
+	OverwriteFlagName         = "--overwrite"
+	OwnerFlagName             = "--owner"
+	ParamFlagName             = "--param"
+	ParamBoolFlagName         = "--param-bool"
+	ParamFileFlagName         = "--param-file"
+	ParamIntFlagName          = "--param-int"
+	ParamYamlFlagName         = "--param-yaml"
+	PartOfFlagName            = "--part-of"
+	PatchFlagName             = "--patch"
+	PatchFileFlagName         = "--patch-file"
+	PatchTypeFlagName         = "--patch-type"
+	PodAnnotationFlagName     = "--pod-annotation"
+	PollIntervalFlagName      = "--poll-interval"
+	PreviousFlagName          = "--previous"
+	ReadyFlagName             = "--ready"
+	ReceiverURLFlagName       = "--receiver-url"
+	RegistryCAFlagName        = "--registry-ca"
+	RegistryCertFlagName      = "--registry-ca-cert"
+	RegistryPasswordFlagName  = "--registry-password"
+	RegistryProxyFlagName     = "--registry-proxy"
+	RegistryTokenFlagName     = "--registry-token"
+	RegistryUsernameFlagName  = "--registry-username"
+	ReplaceClaimsFlagName     = "--replace-service-claims"
+	RequestFlagName           = "--request"
+	RequestCPUFlagName        = "--request-cpu"
+	RequestMemoryFlagName     = "--request-memory"
+	RequestTimeoutFlagName    = "--request-timeout"
+	RetriesFlagName           = "--retries"
+	RetryBackoffFlagName      = "--retry-backoff"
+	RetryOnFlagName           = "--retry-on"
+	RunImageFlagName          = "--run-image"
+	SelectorFlagName          = "--selector"
+	ServiceAccountFlagName    = "--service-account"
+	ServiceRefFlagName        = "--service-ref"
+	ServiceRefSecretFlagName  = "--service-ref-secret"
+	SetFlagName               = "--set"
+	SignatureKeyFlagName      = "--signature-key"
+	SinceFlagName             = "--since"
+	SinceTimeFlagName         = "--since-time"
+	SortByFlagName            = "--sort-by"
+	SourceImageFlagName       = "--source-image"
+	SourceImagePullFlagName   = "--source-image-pull"
+	StrictFlagName            = "--strict"
+	SubPathFlagName           = "--sub-path"
+	SupplyChainFlagName       = "--supply-chain"
+	TailFlagName              = "--tail"
+	TimestampFlagName         = "--timestamp"
+	TimestampsFlagName        = "--timestamps"
+	ToContextFlagName         = "--to-context"
+	TailTimestampFlagName     = "--tail-timestamp"
+	TargetNamespaceFlagName   = "--target-namespace"
+	TrustVerifyCmdFlagName    = "--trust-verify-cmd"
+	TypeFlagName              = "--type"
+	ValidateTypeFlagName      = "--validate-type"
+	ValuesFlagName            = "--values"
+	VerboseLevelFlagName      = "--verbose"
+	VerifyCmdFlagName         = "--verify-cmd"
+	VerifySignatureFlagName   = "--verify-signature"
+	VerifyURLFlagName         = "--verify-url"
+	VisibilityFlagName        = "--visibility"
+	WaitFlagName              = "--wait"
+	WaitDeliverableFlagName   = "--wait-deliverable"
+	WaitForFlagName           = "--wait-for"
+	WaitTimeoutFlagName       = "--wait-timeout"
+	WithLogsFlagName          = "--with-logs"
+	YesFlagName               = "--yes"
 )
@@ -22,13 +22,17 @@ import (
 
 type Object = printer.Object
 
+var EmptyString = printer.EmptyString
 var ExportResource = printer.ExportResource
 var OutputResource = printer.OutputResource
+var OutputResourceWithFields = printer.OutputResourceWithFields
+var OutputResources = printer.OutputResources
 var FindCondition = printer.FindCondition
 var ResourceDiff = printer.ResourceDiff
 var ResourceStatus = printer.ResourceStatus
 var Serrorf = printer.Serrorf
 var SortByNamespaceAndName = printer.SortByNamespaceAndName
+var Swarnf = printer.Swarnf
 var WithSurveyStdio = printer.WithSurveyStdio
 
 type OutputFormat = printer.OutputFormat
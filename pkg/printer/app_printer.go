@@ -0,0 +1,112 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer/table"
+)
+
+// AppOverviewPrinter prints the name of an application, how many of its workloads are ready and
+// the namespaces they are in
+func AppOverviewPrinter(w io.Writer, app string, workloads *cartov1alpha1.WorkloadList) error {
+	printAppOverview := func(workloads *cartov1alpha1.WorkloadList, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		ready := 0
+		namespaces := []string{}
+		seen := map[string]bool{}
+		for i := range workloads.Items {
+			workload := &workloads.Items[i]
+			if cond := printer.FindCondition(workload.Status.Conditions, cartov1alpha1.WorkloadConditionReady); cond != nil && cond.Status == metav1.ConditionTrue {
+				ready++
+			}
+			if !seen[workload.Namespace] {
+				seen[workload.Namespace] = true
+				namespaces = append(namespaces, workload.Namespace)
+			}
+		}
+		rows := []metav1beta1.TableRow{
+			{Cells: []interface{}{"app:", app}},
+			{Cells: []interface{}{"workloads:", fmt.Sprintf("%d (%d ready)", len(workloads.Items), ready)}},
+			{Cells: []interface{}{"namespaces:", strings.Join(namespaces, ", ")}},
+		}
+		return rows, nil
+	}
+
+	tablePrinter := table.NewTablePrinter(table.PrintOptions{NoHeaders: true, PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
+		h.TableHandler(nil, printAppOverview)
+	})
+
+	return tablePrinter.PrintObj(workloads, w)
+}
+
+// AppWorkloadsPrinter prints the status of each workload of an application
+func AppWorkloadsPrinter(w io.Writer, workloads *cartov1alpha1.WorkloadList) error {
+	now := time.Now()
+	printWorkloadRow := func(workload *cartov1alpha1.Workload, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		supplyChain := workload.Status.SupplyChainRef.Name
+		row := metav1beta1.TableRow{
+			Object: runtime.RawExtension{Object: workload},
+			Cells: []interface{}{
+				workload.Namespace,
+				workload.Name,
+				printer.EmptyString(workload.Labels[apis.WorkloadTypeLabelName]),
+				printer.EmptyString(supplyChain),
+				printer.ConditionStatus(printer.FindCondition(workload.Status.Conditions, cartov1alpha1.WorkloadConditionReady)),
+				printer.TimestampSince(workload.CreationTimestamp, now),
+			},
+		}
+		return []metav1beta1.TableRow{row}, nil
+	}
+
+	printWorkloadList := func(workloads *cartov1alpha1.WorkloadList, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		rows := make([]metav1beta1.TableRow, 0, len(workloads.Items))
+		for i := range workloads.Items {
+			row, err := printWorkloadRow(&workloads.Items[i], printOpts)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, row...)
+		}
+		return rows, nil
+	}
+
+	tablePrinter := table.NewTablePrinter(table.PrintOptions{PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
+		columns := []metav1beta1.TableColumnDefinition{
+			{Name: "Namespace", Type: "string"},
+			{Name: "Name", Type: "string"},
+			{Name: "Type", Type: "string"},
+			{Name: "Supply Chain", Type: "string"},
+			{Name: "Ready", Type: "string"},
+			{Name: "Age", Type: "string"},
+		}
+		h.TableHandler(columns, printWorkloadList)
+		h.TableHandler(columns, printWorkloadRow)
+	})
+
+	return tablePrinter.PrintObj(workloads, w)
+}
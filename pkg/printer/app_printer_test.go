@@ -0,0 +1,89 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+func TestAppPrinters(t *testing.T) {
+	created := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	workloads := &cartov1alpha1.WorkloadList{
+		Items: []cartov1alpha1.Workload{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "my-workload",
+				Namespace:         "dev",
+				CreationTimestamp: created,
+				Labels:            map[string]string{apis.WorkloadTypeLabelName: "web"},
+			},
+			Status: cartov1alpha1.WorkloadStatus{
+				SupplyChainRef: cartov1alpha1.ObjectReference{Name: "source-to-url"},
+				Conditions:     []metav1.Condition{{Type: cartov1alpha1.WorkloadConditionReady, Status: metav1.ConditionTrue}},
+			},
+		}, {
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "my-workload",
+				Namespace:         "prod",
+				CreationTimestamp: created,
+			},
+			Status: cartov1alpha1.WorkloadStatus{
+				Conditions: []metav1.Condition{{Type: cartov1alpha1.WorkloadConditionReady, Status: metav1.ConditionFalse, Reason: "OopsieDoodle", Message: "something went wrong"}},
+			},
+		}},
+	}
+
+	output := &bytes.Buffer{}
+	if err := printer.AppOverviewPrinter(output, "my-app", workloads); err != nil {
+		t.Errorf("AppOverviewPrinter() expected no error, got %v", err)
+	}
+	expectedOutput := `
+   app:          my-app
+   workloads:    2 (1 ready)
+   namespaces:   dev, prod
+`
+	if diff := cmp.Diff(strings.TrimPrefix(expectedOutput, "\n"), output.String()); diff != "" {
+		t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+	}
+
+	output = &bytes.Buffer{}
+	if err := printer.AppWorkloadsPrinter(output, workloads); err != nil {
+		t.Errorf("AppWorkloadsPrinter() expected no error, got %v", err)
+	}
+	expectedOutput = `
+   NAMESPACE   NAME          TYPE      SUPPLY CHAIN    READY          AGE
+   dev         my-workload   web       source-to-url   Ready          120m
+   prod        my-workload   <empty>   <empty>         OopsieDoodle   120m
+`
+	if diff := cmp.Diff(strings.TrimPrefix(expectedOutput, "\n"), output.String()); diff != "" {
+		t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+	}
+
+	expectedMessages := []printer.Message{{Source: "prod/my-workload", Reason: "OopsieDoodle", Text: "something went wrong"}}
+	if diff := cmp.Diff(expectedMessages, printer.AppMessages(workloads)); diff != "" {
+		t.Errorf("AppMessages() (-expected, +actual): %s", diff)
+	}
+}
@@ -17,9 +17,12 @@ limitations under the License.
 package printer
 
 import (
+	"fmt"
+
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
 	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
 	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
@@ -37,7 +40,11 @@ func KnativeServicePrinter(c *cli.Config, kserviceList *knativeservingv1.Service
 		row.Cells = append(row.Cells,
 			printer.ConditionStatus(printer.FindCondition(ksvc.Status.Conditions, knativeservingv1.ServiceConditionReady)),
 		)
-		row.Cells = append(row.Cells, printer.EmptyString(ksvc.Status.URL))
+		url := printer.EmptyString(ksvc.Status.URL)
+		if ksvc.Labels[apis.KnativeVisibilityLabelName] == apis.KnativeVisibilityClusterLocal {
+			url = fmt.Sprintf("%s (%s)", url, apis.KnativeVisibilityClusterLocal)
+		}
+		row.Cells = append(row.Cells, url)
 		return []metav1beta1.TableRow{row}, nil
 	}
 	printKnativeServiceList := func(kserviceList *knativeservingv1.ServiceList, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
@@ -62,3 +69,49 @@ func KnativeServicePrinter(c *cli.Config, kserviceList *knativeservingv1.Service
 	})
 	return tablePrinter.PrintObj(kserviceList, c.Stdout)
 }
+
+func KnativeRouteTrafficPrinter(c *cli.Config, route *knativeservingv1.Route) error {
+	printTrafficTargetRow := func(target *knativeservingv1.TrafficTarget, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		row := metav1beta1.TableRow{
+			Object: runtime.RawExtension{Object: route},
+		}
+		latest := false
+		if target.LatestRevision != nil {
+			latest = *target.LatestRevision
+		}
+		percent := ""
+		if target.Percent != nil {
+			percent = fmt.Sprintf("%d%%", *target.Percent)
+		}
+		row.Cells = append(row.Cells,
+			printer.EmptyString(target.RevisionName),
+			latest,
+			printer.EmptyString(percent),
+			printer.EmptyString(target.Tag),
+			printer.EmptyString(target.URL),
+		)
+		return []metav1beta1.TableRow{row}, nil
+	}
+	printRoute := func(route *knativeservingv1.Route, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		rows := make([]metav1beta1.TableRow, 0, len(route.Status.Traffic))
+		for i := range route.Status.Traffic {
+			r, err := printTrafficTargetRow(&route.Status.Traffic[i], printOpts)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, r...)
+		}
+		return rows, nil
+	}
+	tablePrinter := table.NewTablePrinter(table.PrintOptions{PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
+		columns := []metav1beta1.TableColumnDefinition{
+			{Name: "Revision", Type: "string"},
+			{Name: "Latest", Type: "boolean"},
+			{Name: "Traffic", Type: "string"},
+			{Name: "Tag", Type: "string"},
+			{Name: "URL", Type: "string"},
+		}
+		h.TableHandler(columns, printRoute)
+	})
+	return tablePrinter.PrintObj(route, c.Stdout)
+}
@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
 	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
 	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
@@ -52,6 +53,21 @@ func TestKnativeServiceTablePrinter(t *testing.T) {
 				}},
 				URL: url,
 			},
+		}, {
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-ksvc-local",
+				Namespace: defaultNamespace,
+				Labels: map[string]string{
+					apis.KnativeVisibilityLabelName: apis.KnativeVisibilityClusterLocal,
+				},
+			},
+			Status: knativeservingv1.ServiceStatus{
+				Conditions: []metav1.Condition{{
+					Status: metav1.ConditionTrue,
+					Type:   knativeservingv1.ServiceConditionReady,
+				}},
+				URL: "http://my-ksvc-local.default.svc.cluster.local",
+			},
 		}, {
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "my-ksvc-no-url",
@@ -79,6 +95,7 @@ func TestKnativeServiceTablePrinter(t *testing.T) {
 	expectedOutput := `
    NAME                READY       URL
    my-ksvc             not-Ready   https://example.com
+   my-ksvc-local       Ready       http://my-ksvc-local.default.svc.cluster.local (cluster-local)
    my-ksvc-no-url      Unknown     <empty>
    my-ksvc-no-status   <unknown>   <empty>
 `
@@ -86,3 +103,52 @@ func TestKnativeServiceTablePrinter(t *testing.T) {
 		t.Errorf("Unexpected output (-expected, +actual): %s", diff)
 	}
 }
+
+func TestKnativeRouteTrafficPrinter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = knativeservingv1.AddToScheme(scheme)
+	testConfig := cli.NewDefaultConfig("test", scheme)
+	output := &bytes.Buffer{}
+	testConfig.Stdout = output
+	latest := true
+	notLatest := false
+	ninety := int64(90)
+	ten := int64(10)
+
+	route := &knativeservingv1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-ksvc",
+			Namespace: "default",
+		},
+		Status: knativeservingv1.RouteStatus{
+			Traffic: []knativeservingv1.TrafficTarget{{
+				RevisionName:   "my-ksvc-00002",
+				LatestRevision: &latest,
+				Percent:        &ninety,
+			}, {
+				RevisionName:   "my-ksvc-00001",
+				LatestRevision: &notLatest,
+				Percent:        &ten,
+				Tag:            "previous",
+				URL:            "https://previous-my-ksvc.default.example.com",
+			}, {
+				RevisionName: "my-ksvc-00003",
+			}},
+		},
+	}
+
+	if err := printer.KnativeRouteTrafficPrinter(testConfig, route); err != nil {
+		t.Errorf("KnativeRouteTrafficPrinter() expected no error, got %v", err)
+	}
+
+	outputString := output.String()
+	expectedOutput := `
+   REVISION        LATEST   TRAFFIC   TAG        URL
+   my-ksvc-00002   true     90%       <empty>    <empty>
+   my-ksvc-00001   false    10%       previous   https://previous-my-ksvc.default.example.com
+   my-ksvc-00003   false    <empty>   <empty>    <empty>
+`
+	if diff := cmp.Diff(strings.TrimPrefix(expectedOutput, "\n"), outputString); diff != "" {
+		t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+	}
+}
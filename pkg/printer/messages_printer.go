@@ -0,0 +1,154 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer/table"
+)
+
+// Message is a condition message reported by a workload, a deliverable or one of their resources
+type Message struct {
+	Source string
+	Reason string
+	Text   string
+	// Resource is set for the messages of supply chain and delivery resources
+	Resource bool
+}
+
+// messageList is the object printed by MessagesPrinter
+type messageList struct {
+	metav1.TypeMeta
+	messages []Message
+	all      bool
+}
+
+func (l *messageList) DeepCopyObject() runtime.Object {
+	c := *l
+	c.messages = append([]Message{}, l.messages...)
+	return &c
+}
+
+// WorkloadMessages returns the messages of the workload conditions followed by the messages of the
+// supply chain resources that are not ready or not healthy
+func WorkloadMessages(workload *cartov1alpha1.Workload) []Message {
+	messages := conditionMessages(cartov1alpha1.WorkloadKind, workload.Status.Conditions)
+	return append(messages, resourceMessages(workload.Status.Resources)...)
+}
+
+// DeliverableMessages returns the messages of the deliverable conditions followed by the messages
+// of the delivery resources that are not ready or not healthy
+func DeliverableMessages(deliverable *cartov1alpha1.Deliverable) []Message {
+	messages := conditionMessages(cartov1alpha1.DeliverableKind, deliverable.Status.Conditions)
+	return append(messages, resourceMessages(deliverable.Status.Resources)...)
+}
+
+// AppMessages returns the messages of the conditions of each workload of an application, labelled
+// with the workload namespace and name
+func AppMessages(workloads *cartov1alpha1.WorkloadList) []Message {
+	messages := []Message{}
+	for i := range workloads.Items {
+		workload := &workloads.Items[i]
+		messages = append(messages, conditionMessages(fmt.Sprintf("%s/%s", workload.Namespace, workload.Name), workload.Status.Conditions)...)
+	}
+	return messages
+}
+
+// conditionMessages returns the Ready message of an object and its ResourcesHealthy message when
+// it is different. Nothing is returned without a Ready condition.
+func conditionMessages(source string, conditions []metav1.Condition) []Message {
+	messages := []Message{}
+	readyCondition := printer.FindCondition(conditions, cartov1alpha1.ConditionReady)
+	if readyCondition == nil {
+		return messages
+	}
+	if strings.TrimSpace(readyCondition.Message) != "" {
+		messages = append(messages, Message{Source: source, Reason: readyCondition.Reason, Text: readyCondition.Message})
+	}
+	healthyCondition := printer.FindCondition(conditions, cartov1alpha1.ResourcesHealthy)
+	if healthyCondition != nil && strings.TrimSpace(healthyCondition.Message) != "" && healthyCondition.Message != readyCondition.Message {
+		messages = append(messages, Message{Source: source, Reason: healthyCondition.Reason, Text: healthyCondition.Message})
+	}
+	return messages
+}
+
+func resourceMessages(resources []cartov1alpha1.RealizedResource) []Message {
+	messages := []Message{}
+	for _, resource := range resources {
+		for _, conditionType := range []string{cartov1alpha1.ConditionResourceReady, cartov1alpha1.ConditionResourceHealthy} {
+			cond := printer.FindCondition(resource.Conditions, conditionType)
+			if cond == nil || cond.Status == metav1.ConditionTrue || strings.TrimSpace(cond.Message) == "" {
+				continue
+			}
+			messages = append(messages, Message{Source: resource.Name, Reason: cond.Reason, Text: cond.Message, Resource: true})
+			// the healthy message of a resource usually repeats why it is not ready
+			break
+		}
+	}
+	return messages
+}
+
+// MessagesPrinter prints each message with its source and reason. Unless all is set, identical
+// messages are printed once, for the first source, followed by the number of resources reporting
+// it when there are several. The message of a workload or deliverable condition repeats the message
+// of the resource that caused it, so it is only counted through that resource.
+func MessagesPrinter(w io.Writer, messages []Message, all bool) error {
+	printMessages := func(list *messageList, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		rows := []metav1beta1.TableRow{}
+		counts := map[string]int{}
+		for _, message := range list.messages {
+			if message.Resource {
+				counts[message.Text]++
+			}
+		}
+		printed := map[string]bool{}
+		for _, message := range list.messages {
+			text := message.Text
+			if !list.all {
+				if printed[text] {
+					continue
+				}
+				printed[text] = true
+				if counts[text] > 1 {
+					text = fmt.Sprintf("%s %s", text, printer.Sfaintf("(×%d resources)", counts[text]))
+				}
+			}
+			rows = append(rows, metav1beta1.TableRow{
+				Cells: []interface{}{
+					fmt.Sprintf("%s %s:", message.Source, printer.Sfaintf("[%s]", message.Reason)),
+					text,
+				},
+			})
+		}
+		return rows, nil
+	}
+
+	tablePrinter := table.NewTablePrinter(table.PrintOptions{NoHeaders: true, PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
+		h.TableHandler(nil, printMessages)
+	})
+
+	return tablePrinter.PrintObj(&messageList{messages: messages, all: all}, w)
+}
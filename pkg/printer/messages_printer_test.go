@@ -0,0 +1,139 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+func TestWorkloadMessages(t *testing.T) {
+	workload := &cartov1alpha1.Workload{
+		Status: cartov1alpha1.WorkloadStatus{
+			Conditions: []metav1.Condition{{
+				Type:    cartov1alpha1.ConditionReady,
+				Status:  metav1.ConditionFalse,
+				Reason:  "TemplateRejectedByAPIServer",
+				Message: "quota exceeded",
+			}},
+			Resources: []cartov1alpha1.RealizedResource{{
+				Name: "source-provider",
+				Conditions: []metav1.Condition{{
+					Type:   cartov1alpha1.ConditionResourceReady,
+					Status: metav1.ConditionTrue,
+				}},
+			}, {
+				Name: "image-builder",
+				Conditions: []metav1.Condition{{
+					Type:    cartov1alpha1.ConditionResourceReady,
+					Status:  metav1.ConditionFalse,
+					Reason:  "TemplateRejectedByAPIServer",
+					Message: "quota exceeded",
+				}, {
+					Type:    cartov1alpha1.ConditionResourceHealthy,
+					Status:  metav1.ConditionFalse,
+					Reason:  "ReadyCondition",
+					Message: "quota exceeded",
+				}},
+			}, {
+				Name: "config-provider",
+				Conditions: []metav1.Condition{{
+					Type:    cartov1alpha1.ConditionResourceReady,
+					Status:  metav1.ConditionUnknown,
+					Reason:  "MissingValueAtPath",
+					Message: "waiting to read value",
+				}},
+			}},
+		},
+	}
+
+	expected := []printer.Message{
+		{Source: cartov1alpha1.WorkloadKind, Reason: "TemplateRejectedByAPIServer", Text: "quota exceeded"},
+		{Source: "image-builder", Reason: "TemplateRejectedByAPIServer", Text: "quota exceeded", Resource: true},
+		{Source: "config-provider", Reason: "MissingValueAtPath", Text: "waiting to read value", Resource: true},
+	}
+	if diff := cmp.Diff(expected, printer.WorkloadMessages(workload)); diff != "" {
+		t.Errorf("WorkloadMessages() (-expected, +actual): %s", diff)
+	}
+}
+
+func TestMessagesPrinter(t *testing.T) {
+	messages := []printer.Message{
+		{Source: cartov1alpha1.WorkloadKind, Reason: "TemplateRejectedByAPIServer", Text: "quota exceeded"},
+		{Source: "image-builder", Reason: "TemplateRejectedByAPIServer", Text: "quota exceeded", Resource: true},
+		{Source: "config-provider", Reason: "TemplateRejectedByAPIServer", Text: "quota exceeded", Resource: true},
+		{Source: "app-config", Reason: "TemplateRejectedByAPIServer", Text: "quota exceeded", Resource: true},
+		{Source: "source-tester", Reason: "MissingValueAtPath", Text: "waiting to read value", Resource: true},
+	}
+
+	tests := []struct {
+		name           string
+		messages       []printer.Message
+		all            bool
+		expectedOutput string
+	}{{
+		name:     "collapse repeated messages",
+		messages: messages,
+		expectedOutput: `
+   Workload [TemplateRejectedByAPIServer]:   quota exceeded (×3 resources)
+   source-tester [MissingValueAtPath]:       waiting to read value
+`,
+	}, {
+		name:     "all messages",
+		messages: messages,
+		all:      true,
+		expectedOutput: `
+   Workload [TemplateRejectedByAPIServer]:          quota exceeded
+   image-builder [TemplateRejectedByAPIServer]:     quota exceeded
+   config-provider [TemplateRejectedByAPIServer]:   quota exceeded
+   app-config [TemplateRejectedByAPIServer]:        quota exceeded
+   source-tester [MissingValueAtPath]:              waiting to read value
+`,
+	}, {
+		name: "condition message repeated by a single resource",
+		messages: []printer.Message{
+			{Source: cartov1alpha1.WorkloadKind, Reason: "TemplateRejectedByAPIServer", Text: "quota exceeded"},
+			{Source: "image-builder", Reason: "TemplateRejectedByAPIServer", Text: "quota exceeded", Resource: true},
+		},
+		expectedOutput: `
+   Workload [TemplateRejectedByAPIServer]:   quota exceeded
+`,
+	}, {
+		name:           "no messages",
+		messages:       []printer.Message{},
+		expectedOutput: ``,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			if err := printer.MessagesPrinter(output, test.messages, test.all); err != nil {
+				t.Errorf("MessagesPrinter() expected no error, got %v", err)
+			}
+			if diff := cmp.Diff(strings.TrimPrefix(test.expectedOutput, "\n"), output.String()); diff != "" {
+				t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
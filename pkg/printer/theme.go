@@ -0,0 +1,159 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+)
+
+const (
+	// ThemeConfigKey is the plugin config key holding the theme of the workload get sections
+	ThemeConfigKey = "theme"
+
+	DefaultThemeName   = "default"
+	CorporateThemeName = "corporate"
+)
+
+// Section is the header of a workload get section, preceded by its icon when set
+type Section struct {
+	Icon   string
+	Header string
+}
+
+func (s Section) String() string {
+	if s.Icon == "" {
+		return s.Header
+	}
+	return fmt.Sprintf("%s %s", s.Icon, s.Header)
+}
+
+// Theme holds the section headers printed by workload get
+type Theme struct {
+	Overview        Section
+	Source          Section
+	SupplyChain     Section
+	GitOps          Section
+	Delivery        Section
+	Messages        Section
+	Services        Section
+	Pods            Section
+	KnativeServices Section
+}
+
+// Themes are the built-in themes, the corporate theme prints the headers without emoji for
+// terminals or policies that do not allow them
+var Themes = map[string]Theme{
+	DefaultThemeName: {
+		Overview:        Section{Icon: string(cli.Antenna), Header: "Overview"},
+		Source:          Section{Icon: string(cli.FloppyDisk), Header: "Source"},
+		SupplyChain:     Section{Icon: string(cli.Package), Header: "Supply Chain"},
+		GitOps:          Section{Icon: string(cli.Outbox), Header: "GitOps"},
+		Delivery:        Section{Icon: string(cli.Delivery), Header: "Delivery"},
+		Messages:        Section{Icon: string(cli.SpeechBalloon), Header: "Messages"},
+		Services:        Section{Icon: string(cli.Repeat), Header: "Services"},
+		Pods:            Section{Icon: string(cli.Canoe), Header: "Pods"},
+		KnativeServices: Section{Icon: string(cli.Ship), Header: "Knative Services"},
+	},
+	CorporateThemeName: {
+		Overview:        Section{Header: "Overview"},
+		Source:          Section{Header: "Source"},
+		SupplyChain:     Section{Header: "Supply Chain"},
+		GitOps:          Section{Header: "GitOps"},
+		Delivery:        Section{Header: "Delivery"},
+		Messages:        Section{Header: "Messages"},
+		Services:        Section{Header: "Services"},
+		Pods:            Section{Header: "Pods"},
+		KnativeServices: Section{Header: "Knative Services"},
+	},
+}
+
+// themeConfig is the theme as declared in the plugin config, sections override the icon or the
+// header of the named built-in theme
+type themeConfig struct {
+	Name     string                   `mapstructure:"name"`
+	Sections map[string]sectionConfig `mapstructure:"sections"`
+}
+
+// sectionConfig overrides the fields that are set, an empty icon removes the icon
+type sectionConfig struct {
+	Icon   *string `mapstructure:"icon"`
+	Header string  `mapstructure:"header"`
+}
+
+// ThemeFromConfig returns the theme declared in the plugin config, or the default theme when
+// there is none. The default theme is returned along with the error when the theme is invalid.
+func ThemeFromConfig(v *viper.Viper) (Theme, error) {
+	theme := Themes[DefaultThemeName]
+	if v == nil || !v.IsSet(ThemeConfigKey) {
+		return theme, nil
+	}
+
+	config := themeConfig{}
+	// the theme may be only a built-in theme name
+	if name, ok := v.Get(ThemeConfigKey).(string); ok {
+		config.Name = name
+	} else if err := v.UnmarshalKey(ThemeConfigKey, &config); err != nil {
+		return theme, fmt.Errorf("invalid %s: %w", ThemeConfigKey, err)
+	}
+	if config.Name != "" {
+		builtin, ok := Themes[config.Name]
+		if !ok {
+			return theme, fmt.Errorf("unknown %s %q, expected one of %s", ThemeConfigKey, config.Name, strings.Join(themeNames(), ", "))
+		}
+		theme = builtin
+	}
+
+	sections := map[string]*Section{
+		"overview":         &theme.Overview,
+		"source":           &theme.Source,
+		"supply-chain":     &theme.SupplyChain,
+		"gitops":           &theme.GitOps,
+		"delivery":         &theme.Delivery,
+		"messages":         &theme.Messages,
+		"services":         &theme.Services,
+		"pods":             &theme.Pods,
+		"knative-services": &theme.KnativeServices,
+	}
+	for name, override := range config.Sections {
+		section, ok := sections[name]
+		if !ok {
+			return Themes[DefaultThemeName], fmt.Errorf("unknown %s section %q", ThemeConfigKey, name)
+		}
+		if override.Icon != nil {
+			section.Icon = *override.Icon
+		}
+		if override.Header != "" {
+			section.Header = override.Header
+		}
+	}
+	return theme, nil
+}
+
+func themeNames() []string {
+	names := []string{}
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
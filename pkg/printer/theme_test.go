@@ -0,0 +1,121 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+func TestThemeFromConfig(t *testing.T) {
+	defaultTheme := printer.Themes[printer.DefaultThemeName]
+	corporateTheme := printer.Themes[printer.CorporateThemeName]
+
+	tests := []struct {
+		name        string
+		config      interface{}
+		expected    printer.Theme
+		shouldError bool
+	}{{
+		name:     "no theme",
+		expected: defaultTheme,
+	}, {
+		name:     "theme name",
+		config:   "corporate",
+		expected: corporateTheme,
+	}, {
+		name:     "named theme",
+		config:   map[string]interface{}{"name": "corporate"},
+		expected: corporateTheme,
+	}, {
+		name: "overridden sections",
+		config: map[string]interface{}{
+			"name": "corporate",
+			"sections": map[string]interface{}{
+				"messages":         map[string]interface{}{"icon": "!", "header": "Issues"},
+				"knative-services": map[string]interface{}{"header": "Services (Knative)"},
+			},
+		},
+		expected: func() printer.Theme {
+			theme := corporateTheme
+			theme.Messages = printer.Section{Icon: "!", Header: "Issues"}
+			theme.KnativeServices = printer.Section{Header: "Services (Knative)"}
+			return theme
+		}(),
+	}, {
+		name: "removed icon",
+		config: map[string]interface{}{
+			"sections": map[string]interface{}{
+				"pods": map[string]interface{}{"icon": ""},
+			},
+		},
+		expected: func() printer.Theme {
+			theme := defaultTheme
+			theme.Pods = printer.Section{Header: "Pods"}
+			return theme
+		}(),
+	}, {
+		name:        "unknown theme",
+		config:      "neon",
+		expected:    defaultTheme,
+		shouldError: true,
+	}, {
+		name: "unknown section",
+		config: map[string]interface{}{
+			"name": "corporate",
+			"sections": map[string]interface{}{
+				"logs": map[string]interface{}{"header": "Logs"},
+			},
+		},
+		expected:    defaultTheme,
+		shouldError: true,
+	}, {
+		name:        "invalid theme",
+		config:      []string{"corporate"},
+		expected:    defaultTheme,
+		shouldError: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := viper.New()
+			if test.config != nil {
+				v.Set(printer.ThemeConfigKey, test.config)
+			}
+			theme, err := printer.ThemeFromConfig(v)
+			if (err != nil) != test.shouldError {
+				t.Errorf("ThemeFromConfig() error = %v, shouldError %v", err, test.shouldError)
+			}
+			if diff := cmp.Diff(test.expected, theme); diff != "" {
+				t.Errorf("ThemeFromConfig() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestSectionString(t *testing.T) {
+	if got := (printer.Section{Icon: "💬", Header: "Messages"}).String(); got != "💬 Messages" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (printer.Section{Header: "Messages"}).String(); got != "Messages" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -17,9 +17,7 @@ limitations under the License.
 package printer
 
 import (
-	"fmt"
 	"io"
-	"strings"
 
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
 
@@ -28,7 +26,9 @@ import (
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer/table"
 )
 
-func DeliverableResourcesPrinter(w io.Writer, deliverable *cartov1alpha1.Deliverable) error {
+// DeliverableResourcesPrinter prints the resources of the delivery of the deliverable, timestamps are
+// handled like for WorkloadResourcesPrinter
+func DeliverableResourcesPrinter(w io.Writer, deliverable *cartov1alpha1.Deliverable, timestamps bool) error {
 	printResourceInfoRow := func(resource *cartov1alpha1.RealizedResource, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
 		var healthy string
 		healthyCond := printer.FindCondition(resource.Conditions, cartov1alpha1.ConditionResourceHealthy)
@@ -36,7 +36,7 @@ func DeliverableResourcesPrinter(w io.Writer, deliverable *cartov1alpha1.Deliver
 			healthy = printer.ColorConditionStatus(string(healthyCond.Status))
 		}
 
-		ready, elapsedTransitionTime := findConditionReady(resource.Conditions, cartov1alpha1.ConditionResourceReady)
+		ready, elapsedTransitionTime := findConditionReady(resource.Conditions, cartov1alpha1.ConditionResourceReady, timestamps)
 		row := metav1beta1.TableRow{
 			Cells: []interface{}{
 				resource.Name,
@@ -99,39 +99,26 @@ func DeliveryInfoPrinter(w io.Writer, deliverable *cartov1alpha1.Deliverable) er
 }
 
 func DeliverableIssuesPrinter(w io.Writer, deliverable *cartov1alpha1.Deliverable) error {
-	readyCondition := printer.FindCondition(deliverable.Status.Conditions, cartov1alpha1.ConditionReady)
-	healthyCondition := printer.FindCondition(deliverable.Status.Conditions, cartov1alpha1.ResourcesHealthy)
-	if readyCondition == nil {
-		return nil
-	}
-	printIssues := func(deliverable *cartov1alpha1.Deliverable, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
-		rows := []metav1beta1.TableRow{}
-		if strings.TrimSpace(readyCondition.Message) != "" {
-			readyRow := metav1beta1.TableRow{
-				Cells: []interface{}{
-					fmt.Sprintf("%s %s:", cartov1alpha1.DeliverableKind, printer.Sfaintf("[%s]", readyCondition.Reason)),
-					readyCondition.Message,
-				},
-			}
-			rows = append(rows, readyRow)
-		}
+	return MessagesPrinter(w, conditionMessages(cartov1alpha1.DeliverableKind, deliverable.Status.Conditions), true)
+}
 
-		if healthyCondition != nil && strings.TrimSpace(healthyCondition.Message) != "" {
-			if strings.Compare(healthyCondition.Message, readyCondition.Message) != 0 {
-				healthyRow := metav1beta1.TableRow{
-					Cells: []interface{}{
-						fmt.Sprintf("%s %s:", cartov1alpha1.DeliverableKind, printer.Sfaintf("[%s]", healthyCondition.Reason)),
-						healthyCondition.Message,
-					},
-				}
-				rows = append(rows, healthyRow)
-			}
+// DeliverableOverviewPrinter prints the name of a deliverable and the workload it was stamped for,
+// deliverables promoted to another cluster with GitOps have no workload there
+func DeliverableOverviewPrinter(w io.Writer, deliverable *cartov1alpha1.Deliverable) error {
+	printOverview := func(deliverable *cartov1alpha1.Deliverable, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		rows := []metav1beta1.TableRow{{
+			Cells: []interface{}{"name:", deliverable.Name},
+		}}
+		if workload := deliverable.Labels[cartov1alpha1.WorkloadLabelName]; workload != "" {
+			rows = append(rows, metav1beta1.TableRow{
+				Cells: []interface{}{"workload:", workload},
+			})
 		}
 		return rows, nil
 	}
 
 	tablePrinter := table.NewTablePrinter(table.PrintOptions{NoHeaders: true, PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
-		h.TableHandler(nil, printIssues)
+		h.TableHandler(nil, printOverview)
 	})
 
 	return tablePrinter.PrintObj(deliverable, w)
@@ -36,6 +36,7 @@ func TestDeliverableResourcesPrinter(t *testing.T) {
 	tests := []struct {
 		name            string
 		testDeliverable *cartov1alpha1.Deliverable
+		timestamps      bool
 		expectedOutput  string
 	}{{
 		name: "various resources",
@@ -348,7 +349,7 @@ func TestDeliverableResourcesPrinter(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			output := &bytes.Buffer{}
-			if err := printer.DeliverableResourcesPrinter(output, test.testDeliverable); err != nil {
+			if err := printer.DeliverableResourcesPrinter(output, test.testDeliverable, test.timestamps); err != nil {
 				t.Errorf("DeliverableSourcePrinter() expected no error, got %v", err)
 			}
 			outputString := output.String()
@@ -627,3 +628,49 @@ func TestDeliverablesIssuesPrinter(t *testing.T) {
 		})
 	}
 }
+
+func TestDeliverableOverviewPrinter(t *testing.T) {
+	tests := []struct {
+		name            string
+		testDeliverable *cartov1alpha1.Deliverable
+		expectedOutput  string
+	}{{
+		name: "stamped by a workload",
+		testDeliverable: &cartov1alpha1.Deliverable{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-deliverable",
+				Namespace: "default",
+				Labels: map[string]string{
+					cartov1alpha1.WorkloadLabelName: "my-workload",
+				},
+			},
+		},
+		expectedOutput: `
+   name:       my-deliverable
+   workload:   my-workload
+`,
+	}, {
+		name: "without workload",
+		testDeliverable: &cartov1alpha1.Deliverable{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-deliverable",
+				Namespace: "default",
+			},
+		},
+		expectedOutput: `
+   name:   my-deliverable
+`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			if err := printer.DeliverableOverviewPrinter(output, test.testDeliverable); err != nil {
+				t.Errorf("DeliverableOverviewPrinter() expected no error, got %v", err)
+			}
+			if diff := cmp.Diff(strings.TrimPrefix(test.expectedOutput, "\n"), output.String()); diff != "" {
+				t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer/table"
+)
+
+// ConfigWriterResourceName is the name of the resource of the supply chain that writes the configuration
+// of the workload to its GitOps destination
+const ConfigWriterResourceName = "config-writer"
+
+// gitOpsOutputs are the outputs of the config writer that describe its destination, by row label. The
+// branch falls back to the revision when the config writer does not report one.
+var gitOpsOutputs = []struct {
+	label   string
+	outputs []string
+}{
+	{label: "url:", outputs: []string{"url"}},
+	{label: "branch:", outputs: []string{"branch", "revision"}},
+	{label: "path:", outputs: []string{"path"}},
+}
+
+// HasWorkloadGitOps returns whether the config writer of the workload reports where the configuration
+// of the workload was delivered
+func HasWorkloadGitOps(workload *cartov1alpha1.Workload) bool {
+	return len(workloadGitOpsRows(workload)) != 0
+}
+
+// WorkloadGitOpsPrinter prints where the config writer of the supply chain delivered the configuration
+// of the workload, the ConfigMap it stamped or the git url, branch and path it reports as outputs
+func WorkloadGitOpsPrinter(w io.Writer, workload *cartov1alpha1.Workload) error {
+	printGitOps := func(workload *cartov1alpha1.Workload, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		return workloadGitOpsRows(workload), nil
+	}
+
+	tablePrinter := table.NewTablePrinter(table.PrintOptions{NoHeaders: true, PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
+		h.TableHandler(nil, printGitOps)
+	})
+
+	return tablePrinter.PrintObj(workload, w)
+}
+
+func workloadGitOpsRows(workload *cartov1alpha1.Workload) []metav1beta1.TableRow {
+	var configWriter *cartov1alpha1.RealizedResource
+	for i := range workload.Status.Resources {
+		if workload.Status.Resources[i].Name == ConfigWriterResourceName {
+			configWriter = &workload.Status.Resources[i]
+		}
+	}
+	if configWriter == nil {
+		return nil
+	}
+
+	rows := []metav1beta1.TableRow{}
+	if ref := configWriter.StampedRef; ref != nil && ref.Kind == "ConfigMap" {
+		rows = append(rows, metav1beta1.TableRow{
+			Cells: []interface{}{"configmap:", fmt.Sprintf("%s/%s", ref.Namespace, ref.Name)},
+		})
+		return rows
+	}
+	values := map[string]string{}
+	for _, output := range configWriter.Outputs {
+		values[output.Name] = strings.TrimSpace(output.Preview)
+	}
+	for _, o := range gitOpsOutputs {
+		for _, name := range o.outputs {
+			if value := values[name]; value != "" {
+				rows = append(rows, metav1beta1.TableRow{
+					Cells: []interface{}{o.label, value},
+				})
+				break
+			}
+		}
+	}
+	return rows
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+func TestWorkloadGitOpsPrinter(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	tests := []struct {
+		name           string
+		testWorkload   *cartov1alpha1.Workload
+		expectedGitOps bool
+		expectedOutput string
+	}{{
+		name: "git outputs",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+			},
+			Status: cartov1alpha1.WorkloadStatus{
+				Resources: []cartov1alpha1.RealizedResource{{
+					Name: "source-provider",
+					Outputs: []cartov1alpha1.Output{{
+						Name:    "url",
+						Preview: "http://source-controller.flux-system.svc.cluster.local./gitrepository/default/my-workload/abc123.tar.gz\n",
+					}},
+				}, {
+					Name: "config-writer",
+					StampedRef: &corev1.ObjectReference{
+						Kind: "Runnable",
+						Name: "my-workload-config-writer",
+					},
+					Outputs: []cartov1alpha1.Output{{
+						Name:    "url",
+						Preview: "https://github.com/example/gitops.git\n",
+					}, {
+						Name:    "branch",
+						Preview: "main\n",
+					}, {
+						Name:    "path",
+						Preview: "config/default/my-workload\n",
+					}},
+				}},
+			},
+		},
+		expectedGitOps: true,
+		expectedOutput: `
+   url:      https://github.com/example/gitops.git
+   branch:   main
+   path:     config/default/my-workload
+`,
+	}, {
+		name: "revision as branch",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+			},
+			Status: cartov1alpha1.WorkloadStatus{
+				Resources: []cartov1alpha1.RealizedResource{{
+					Name: "config-writer",
+					Outputs: []cartov1alpha1.Output{{
+						Name:    "url",
+						Preview: "https://github.com/example/gitops.git\n",
+					}, {
+						Name:    "revision",
+						Preview: "staging\n",
+					}},
+				}},
+			},
+		},
+		expectedGitOps: true,
+		expectedOutput: `
+   url:      https://github.com/example/gitops.git
+   branch:   staging
+`,
+	}, {
+		name: "configmap",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+			},
+			Status: cartov1alpha1.WorkloadStatus{
+				Resources: []cartov1alpha1.RealizedResource{{
+					Name: "config-writer",
+					StampedRef: &corev1.ObjectReference{
+						Kind:      "ConfigMap",
+						Namespace: defaultNamespace,
+						Name:      "my-workload-deliverable",
+					},
+				}},
+			},
+		},
+		expectedGitOps: true,
+		expectedOutput: `
+   configmap:   default/my-workload-deliverable
+`,
+	}, {
+		name: "config writer without outputs",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+			},
+			Status: cartov1alpha1.WorkloadStatus{
+				Resources: []cartov1alpha1.RealizedResource{{
+					Name: "config-writer",
+					StampedRef: &corev1.ObjectReference{
+						Kind: "Runnable",
+						Name: "my-workload-config-writer",
+					},
+				}},
+			},
+		},
+		expectedOutput: ``,
+	}, {
+		name: "no config writer",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+			},
+		},
+		expectedOutput: ``,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := printer.HasWorkloadGitOps(test.testWorkload); actual != test.expectedGitOps {
+				t.Errorf("HasWorkloadGitOps() expected %t, got %t", test.expectedGitOps, actual)
+			}
+			output := &bytes.Buffer{}
+			if err := printer.WorkloadGitOpsPrinter(output, test.testWorkload); err != nil {
+				t.Errorf("WorkloadGitOpsPrinter() expected no error, got %v", err)
+			}
+			if diff := cmp.Diff(strings.TrimPrefix(test.expectedOutput, "\n"), output.String()); diff != "" {
+				t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
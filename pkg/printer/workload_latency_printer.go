@@ -0,0 +1,63 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"io"
+	"time"
+
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer/table"
+)
+
+// WorkloadLatency is the time a workload took to reach each stage of its supply chain after being
+// applied, a zero duration is a stage that was not observed
+type WorkloadLatency struct {
+	SourceResolved time.Duration
+	ImageBuilt     time.Duration
+	Ready          time.Duration
+}
+
+func WorkloadLatencyPrinter(w io.Writer, workload *cartov1alpha1.Workload, latency WorkloadLatency) error {
+	printLatency := func(workload *cartov1alpha1.Workload, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		rows := []metav1beta1.TableRow{}
+		stages := []struct {
+			name     string
+			duration time.Duration
+		}{
+			{"source resolved:", latency.SourceResolved},
+			{"image built:", latency.ImageBuilt},
+			{"ready:", latency.Ready},
+		}
+		for _, stage := range stages {
+			if stage.duration == 0 && stage.name != "ready:" {
+				continue
+			}
+			rows = append(rows, metav1beta1.TableRow{
+				Cells: []interface{}{stage.name, stage.duration.Round(time.Second).String()},
+			})
+		}
+		return rows, nil
+	}
+	tablePrinter := table.NewTablePrinter(table.PrintOptions{NoHeaders: true, PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
+		h.TableHandler(nil, printLatency)
+	})
+
+	return tablePrinter.PrintObj(workload, w)
+}
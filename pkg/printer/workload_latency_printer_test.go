@@ -0,0 +1,76 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
+)
+
+func TestWorkloadLatencyPrinter(t *testing.T) {
+	workload := &cartov1alpha1.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-workload",
+			Namespace: "default",
+		},
+	}
+	tests := []struct {
+		name           string
+		latency        printer.WorkloadLatency
+		expectedOutput string
+	}{{
+		name: "every stage",
+		latency: printer.WorkloadLatency{
+			SourceResolved: 12 * time.Second,
+			ImageBuilt:     2*time.Minute + 3400*time.Millisecond,
+			Ready:          2*time.Minute + 41*time.Second,
+		},
+		expectedOutput: `
+   source resolved:   12s
+   image built:       2m3s
+   ready:             2m41s
+`,
+	}, {
+		name: "stages not observed",
+		latency: printer.WorkloadLatency{
+			Ready: 40 * time.Second,
+		},
+		expectedOutput: `
+   ready:   40s
+`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			if err := printer.WorkloadLatencyPrinter(output, workload, test.latency); err != nil {
+				t.Errorf("WorkloadLatencyPrinter() expected no error, got %v", err)
+			}
+			if diff := cmp.Diff(strings.TrimPrefix(test.expectedOutput, "\n"), output.String()); diff != "" {
+				t.Errorf("WorkloadLatencyPrinter() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
@@ -47,6 +47,14 @@ func WorkloadOverviewPrinter(w io.Writer, workload *cartov1alpha1.Workload) erro
 		}
 
 		rows := []metav1beta1.TableRow{nameRow, sourceRow}
+		if workload.Spec.IsPaused() {
+			rows = append(rows, metav1beta1.TableRow{
+				Cells: []interface{}{
+					"paused:",
+					"true",
+				},
+			})
+		}
 
 		return rows, nil
 	}
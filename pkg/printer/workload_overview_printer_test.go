@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
@@ -68,6 +69,29 @@ func TestWorkloadDetailsPrinter(t *testing.T) {
 		expectedOutput: `
    name:   my-workload
    type:   web
+`,
+	}, {
+		name: "paused",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+				Labels:    labels,
+			},
+			Spec: cartov1alpha1.WorkloadSpec{
+				Image: "my-image",
+				Params: []cartov1alpha1.Param{
+					{
+						Name:  cartov1alpha1.WorkloadPausedParam,
+						Value: apiextensionsv1.JSON{Raw: []byte(`true`)},
+					},
+				},
+			},
+		},
+		expectedOutput: `
+   name:     my-workload
+   type:     web
+   paused:   true
 `,
 	}}
 
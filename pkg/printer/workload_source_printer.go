@@ -17,34 +17,53 @@ limitations under the License.
 package printer
 
 import (
+	"fmt"
 	"io"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
 
 	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer/table"
 )
 
-func WorkloadSourceImagePrinter(w io.Writer, workload *cartov1alpha1.Workload) error {
-	printImageInfo := func(workload *cartov1alpha1.Workload, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
-		sourceRow := metav1beta1.TableRow{
-			Cells: []interface{}{
-				"type:",
-				"image",
-			},
+// WorkloadSourcePrinter prints every source of the workload in a single table, followed by the
+// revision fetched by its source provider, a flux GitRepository or an ImageRepository, when not nil
+func WorkloadSourcePrinter(w io.Writer, workload *cartov1alpha1.Workload, sourceProvider *unstructured.Unstructured) error {
+	printSourceInfo := func(workload *cartov1alpha1.Workload, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		rows := []metav1beta1.TableRow{}
+		if workload.Spec.Image != "" {
+			rows = append(rows, workloadSourceImageRows(workload)...)
 		}
-
-		imageRow := metav1beta1.TableRow{
-			Cells: []interface{}{
-				"image:",
-				workload.Spec.Image,
-			},
+		if workload.Spec.Source != nil {
+			if workload.Spec.Source.Image != "" {
+				rows = append(rows, workloadLocalSourceImageRows(workload)...)
+			}
+			if workload.Spec.Source.Git != nil {
+				rows = append(rows, workloadSourceGitRows(workload)...)
+			}
+		}
+		if sourceProvider != nil {
+			rows = append(rows, sourceRevisionRows(sourceProvider)...)
 		}
-
-		rows := []metav1beta1.TableRow{sourceRow, imageRow}
 		return rows, nil
 	}
 
+	tablePrinter := table.NewTablePrinter(table.PrintOptions{NoHeaders: true, PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
+		h.TableHandler(nil, printSourceInfo)
+	})
+
+	return tablePrinter.PrintObj(workload, w)
+}
+
+func WorkloadSourceImagePrinter(w io.Writer, workload *cartov1alpha1.Workload) error {
+	printImageInfo := func(workload *cartov1alpha1.Workload, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		return workloadSourceImageRows(workload), nil
+	}
+
 	tablePrinter := table.NewTablePrinter(table.PrintOptions{NoHeaders: true, PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
 		h.TableHandler(nil, printImageInfo)
 	})
@@ -52,108 +71,166 @@ func WorkloadSourceImagePrinter(w io.Writer, workload *cartov1alpha1.Workload) e
 	return tablePrinter.PrintObj(workload, w)
 }
 
+func workloadSourceImageRows(workload *cartov1alpha1.Workload) []metav1beta1.TableRow {
+	sourceRow := metav1beta1.TableRow{
+		Cells: []interface{}{
+			"type:",
+			"image",
+		},
+	}
+
+	imageRow := metav1beta1.TableRow{
+		Cells: []interface{}{
+			"image:",
+			workload.Spec.Image,
+		},
+	}
+
+	rows := []metav1beta1.TableRow{sourceRow, imageRow}
+	return rows
+}
+
 func WorkloadLocalSourceImagePrinter(w io.Writer, workload *cartov1alpha1.Workload) error {
 	printLocalSourceInfo := func(workload *cartov1alpha1.Workload, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
-		sourceRow := metav1beta1.TableRow{
-			Cells: []interface{}{
-				"type:",
-				"source image",
-			},
-		}
+		return workloadLocalSourceImageRows(workload), nil
+	}
+	tablePrinter := table.NewTablePrinter(table.PrintOptions{NoHeaders: true, PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
+		h.TableHandler(nil, printLocalSourceInfo)
+	})
 
-		rows := []metav1beta1.TableRow{sourceRow}
+	return tablePrinter.PrintObj(workload, w)
+}
 
-		if workload.Spec.Source.Subpath != "" {
-			subPathRow := metav1beta1.TableRow{
-				Cells: []interface{}{
-					"sub-path:",
-					workload.Spec.Source.Subpath,
-				},
-			}
-			rows = append(rows, subPathRow)
-		}
+func workloadLocalSourceImageRows(workload *cartov1alpha1.Workload) []metav1beta1.TableRow {
+	sourceRow := metav1beta1.TableRow{
+		Cells: []interface{}{
+			"type:",
+			"source image",
+		},
+	}
+
+	rows := []metav1beta1.TableRow{sourceRow}
 
-		imageRow := metav1beta1.TableRow{
+	if workload.Spec.Source.Subpath != "" {
+		subPathRow := metav1beta1.TableRow{
 			Cells: []interface{}{
-				"image:",
-				workload.Spec.Source.Image,
+				"sub-path:",
+				workload.Spec.Source.Subpath,
 			},
 		}
-		rows = append(rows, imageRow)
+		rows = append(rows, subPathRow)
+	}
 
-		return rows, nil
+	imageRow := metav1beta1.TableRow{
+		Cells: []interface{}{
+			"image:",
+			workload.Spec.Source.Image,
+		},
 	}
+	rows = append(rows, imageRow)
+
+	return rows
+}
+
+func WorkloadSourceGitPrinter(w io.Writer, workload *cartov1alpha1.Workload) error {
+	printGitInfo := func(workload *cartov1alpha1.Workload, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		return workloadSourceGitRows(workload), nil
+	}
+
 	tablePrinter := table.NewTablePrinter(table.PrintOptions{NoHeaders: true, PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
-		h.TableHandler(nil, printLocalSourceInfo)
+		h.TableHandler(nil, printGitInfo)
 	})
 
 	return tablePrinter.PrintObj(workload, w)
 }
 
-func WorkloadSourceGitPrinter(w io.Writer, workload *cartov1alpha1.Workload) error {
-	printGitInfo := func(workload *cartov1alpha1.Workload, printOpts table.PrintOptions) ([]metav1beta1.TableRow, error) {
-		sourceRow := metav1beta1.TableRow{
+func workloadSourceGitRows(workload *cartov1alpha1.Workload) []metav1beta1.TableRow {
+	sourceRow := metav1beta1.TableRow{
+		Cells: []interface{}{
+			"type:",
+			"git",
+		},
+	}
+
+	urlRow := metav1beta1.TableRow{
+		Cells: []interface{}{
+			"url:",
+			workload.Spec.Source.Git.URL,
+		},
+	}
+
+	rows := []metav1beta1.TableRow{sourceRow, urlRow}
+
+	if workload.Spec.Source.Subpath != "" {
+		subPathRow := metav1beta1.TableRow{
 			Cells: []interface{}{
-				"type:",
-				"git",
+				"sub-path:",
+				workload.Spec.Source.Subpath,
 			},
 		}
+		rows = append(rows, subPathRow)
+	}
 
-		urlRow := metav1beta1.TableRow{
+	if workload.Spec.Source.Git.Ref.Branch != "" {
+		branchRow := metav1beta1.TableRow{
 			Cells: []interface{}{
-				"url:",
-				workload.Spec.Source.Git.URL,
+				"branch:",
+				workload.Spec.Source.Git.Ref.Branch,
 			},
 		}
+		rows = append(rows, branchRow)
+	}
 
-		rows := []metav1beta1.TableRow{sourceRow, urlRow}
-
-		if workload.Spec.Source.Subpath != "" {
-			subPathRow := metav1beta1.TableRow{
-				Cells: []interface{}{
-					"sub-path:",
-					workload.Spec.Source.Subpath,
-				},
-			}
-			rows = append(rows, subPathRow)
-		}
-
-		if workload.Spec.Source.Git.Ref.Branch != "" {
-			branchRow := metav1beta1.TableRow{
-				Cells: []interface{}{
-					"branch:",
-					workload.Spec.Source.Git.Ref.Branch,
-				},
-			}
-			rows = append(rows, branchRow)
+	if workload.Spec.Source.Git.Ref.Tag != "" {
+		tagRow := metav1beta1.TableRow{
+			Cells: []interface{}{
+				"tag:",
+				workload.Spec.Source.Git.Ref.Tag,
+			},
 		}
+		rows = append(rows, tagRow)
+	}
 
-		if workload.Spec.Source.Git.Ref.Tag != "" {
-			tagRow := metav1beta1.TableRow{
-				Cells: []interface{}{
-					"tag:",
-					workload.Spec.Source.Git.Ref.Tag,
-				},
-			}
-			rows = append(rows, tagRow)
+	if workload.Spec.Source.Git.Ref.Commit != "" {
+		commitRow := metav1beta1.TableRow{
+			Cells: []interface{}{
+				"commit:",
+				workload.Spec.Source.Git.Ref.Commit,
+			},
 		}
+		rows = append(rows, commitRow)
+	}
 
-		if workload.Spec.Source.Git.Ref.Commit != "" {
-			commitRow := metav1beta1.TableRow{
-				Cells: []interface{}{
-					"commit:",
-					workload.Spec.Source.Git.Ref.Commit,
-				},
-			}
-			rows = append(rows, commitRow)
-		}
+	return rows
+}
 
-		return rows, nil
+func sourceRevisionRows(sourceProvider *unstructured.Unstructured) []metav1beta1.TableRow {
+	revision, _, _ := unstructured.NestedString(sourceProvider.Object, "status", "artifact", "revision")
+	if revision == "" {
+		revision = printer.Swarnf("<unknown>")
+	}
+	revisionRow := metav1beta1.TableRow{
+		Cells: []interface{}{
+			"revision:",
+			revision,
+		},
 	}
 
-	tablePrinter := table.NewTablePrinter(table.PrintOptions{NoHeaders: true, PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
-		h.TableHandler(nil, printGitInfo)
-	})
+	fetched := metav1.Time{}
+	if lastUpdateTime, _, _ := unstructured.NestedString(sourceProvider.Object, "status", "artifact", "lastUpdateTime"); lastUpdateTime != "" {
+		// an invalid time is shown as unknown
+		_ = fetched.UnmarshalQueryParameter(lastUpdateTime)
+	}
+	fetchedAge := printer.TimestampSince(fetched, time.Now())
+	if !fetched.IsZero() {
+		fetchedAge = fmt.Sprintf("%s ago", fetchedAge)
+	}
+	fetchedRow := metav1beta1.TableRow{
+		Cells: []interface{}{
+			"fetched:",
+			fetchedAge,
+		},
+	}
 
-	return tablePrinter.PrintObj(workload, w)
+	return []metav1beta1.TableRow{revisionRow, fetchedRow}
 }
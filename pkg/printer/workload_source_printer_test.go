@@ -20,14 +20,115 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/printer"
 )
 
+func TestWorkloadSourcePrinter(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	gitWorkload := &cartov1alpha1.Workload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: defaultNamespace,
+		},
+		Spec: cartov1alpha1.WorkloadSpec{
+			Source: &cartov1alpha1.Source{
+				Git: &cartov1alpha1.GitSource{
+					URL: "https://example.com/repo.git",
+					Ref: cartov1alpha1.GitRef{
+						Branch: "main",
+					},
+				},
+			},
+		},
+	}
+	sourceProvider := func(status map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "source.toolkit.fluxcd.io/v1beta1",
+				"kind":       "GitRepository",
+				"metadata": map[string]interface{}{
+					"namespace": defaultNamespace,
+					"name":      workloadName,
+				},
+				"status": status,
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		testWorkload   *cartov1alpha1.Workload
+		sourceProvider *unstructured.Unstructured
+		expectedOutput string
+	}{{
+		name:         "without source provider",
+		testWorkload: gitWorkload,
+		expectedOutput: `
+   type:     git
+   url:      https://example.com/repo.git
+   branch:   main
+`,
+	}, {
+		name:         "fetched revision",
+		testWorkload: gitWorkload,
+		sourceProvider: sourceProvider(map[string]interface{}{
+			"artifact": map[string]interface{}{
+				"revision":       "main/3d4c5a7b1e2f",
+				"lastUpdateTime": time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339),
+			},
+		}),
+		expectedOutput: `
+   type:       git
+   url:        https://example.com/repo.git
+   branch:     main
+   revision:   main/3d4c5a7b1e2f
+   fetched:    120m ago
+`,
+	}, {
+		name: "source image not fetched yet",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+			},
+			Spec: cartov1alpha1.WorkloadSpec{
+				Source: &cartov1alpha1.Source{
+					Image: "my-source-image",
+				},
+			},
+		},
+		sourceProvider: sourceProvider(map[string]interface{}{}),
+		expectedOutput: `
+   type:       source image
+   image:      my-source-image
+   revision:   <unknown>
+   fetched:    <unknown>
+`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			if err := printer.WorkloadSourcePrinter(output, test.testWorkload, test.sourceProvider); err != nil {
+				t.Errorf("WorkloadSourcePrinter() expected no error, got %v", err)
+			}
+			outputString := output.String()
+			if diff := cmp.Diff(strings.TrimPrefix(test.expectedOutput, "\n"), outputString); diff != "" {
+				t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
+
 func TestWorkloadSourceImagePrinter(t *testing.T) {
 	defaultNamespace := "default"
 	workloadName := "my-workload"
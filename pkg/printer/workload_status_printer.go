@@ -30,7 +30,19 @@ import (
 	"github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime/printer/table"
 )
 
-func WorkloadResourcesPrinter(w io.Writer, workload *cartov1alpha1.Workload) error {
+// WorkloadResourcesPrinter prints the resources of the supply chain of the workload, with timestamps the
+// time of a resource falls back to the latest transition of any of its conditions and is shown as an age
+func WorkloadResourcesPrinter(w io.Writer, workload *cartov1alpha1.Workload, timestamps bool) error {
+	return workloadResourcesPrinter(w, workload, timestamps, false)
+}
+
+// WorkloadResourcesWidePrinter prints the resources of the supply chain of the workload like
+// WorkloadResourcesPrinter, with the api version and namespace of the object stamped by each resource
+func WorkloadResourcesWidePrinter(w io.Writer, workload *cartov1alpha1.Workload, timestamps bool) error {
+	return workloadResourcesPrinter(w, workload, timestamps, true)
+}
+
+func workloadResourcesPrinter(w io.Writer, workload *cartov1alpha1.Workload, timestamps bool, wide bool) error {
 	printResourceInfoRow := func(resource *cartov1alpha1.RealizedResource, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
 		var healthy string
 		healthyCond := printer.FindCondition(resource.Conditions, cartov1alpha1.ConditionResourceHealthy)
@@ -38,7 +50,7 @@ func WorkloadResourcesPrinter(w io.Writer, workload *cartov1alpha1.Workload) err
 			healthy = printer.ColorConditionStatus(string(healthyCond.Status))
 		}
 
-		ready, elapsedTransitionTime := findConditionReady(resource.Conditions, cartov1alpha1.ConditionResourceReady)
+		ready, elapsedTransitionTime := findConditionReady(resource.Conditions, cartov1alpha1.ConditionResourceReady, timestamps)
 		row := metav1beta1.TableRow{
 			Cells: []interface{}{
 				resource.Name,
@@ -48,6 +60,14 @@ func WorkloadResourcesPrinter(w io.Writer, workload *cartov1alpha1.Workload) err
 				getOutputRef(resource),
 			},
 		}
+		if wide {
+			var apiVersion, namespace string
+			if resource.StampedRef != nil {
+				apiVersion = resource.StampedRef.APIVersion
+				namespace = resource.StampedRef.Namespace
+			}
+			row.Cells = append(row.Cells, printer.EmptyString(apiVersion), printer.EmptyString(namespace))
+		}
 		return []metav1beta1.TableRow{row}, nil
 	}
 
@@ -78,6 +98,12 @@ func WorkloadResourcesPrinter(w io.Writer, workload *cartov1alpha1.Workload) err
 			{Name: "Time", Type: "string"},
 			{Name: "Output", Type: "string"},
 		}
+		if wide {
+			columns = append(columns,
+				metav1beta1.TableColumnDefinition{Name: "API Version", Type: "string"},
+				metav1beta1.TableColumnDefinition{Name: "Namespace", Type: "string"},
+			)
+		}
 		h.TableHandler(columns, printResourceInfoList)
 		h.TableHandler(columns, printResourceInfoRow)
 	})
@@ -85,6 +111,47 @@ func WorkloadResourcesPrinter(w io.Writer, workload *cartov1alpha1.Workload) err
 	return tablePrinter.PrintObj(workload, w)
 }
 
+// WorkloadResourceOutputsPrinter prints the outputs each resource of the supply chain passed to the next
+// ones, like the url and revision of the source or the image that was built, with the digest of their
+// full value. Only the first line of a value is shown.
+func WorkloadResourceOutputsPrinter(w io.Writer, workload *cartov1alpha1.Workload) error {
+	printOutputsList := func(workload *cartov1alpha1.Workload, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
+		rows := []metav1beta1.TableRow{}
+		for _, r := range workload.Status.Resources {
+			if r.StampedRef != nil && supplyChainResourcesKindExcludeList[r.StampedRef.Kind] {
+				continue
+			}
+			for _, output := range r.Outputs {
+				value := strings.TrimSpace(output.Preview)
+				if i := strings.Index(value, "\n"); i != -1 {
+					value = value[:i] + " ..."
+				}
+				rows = append(rows, metav1beta1.TableRow{
+					Cells: []interface{}{
+						r.Name,
+						output.Name,
+						printer.EmptyString(value),
+						printer.EmptyString(output.Digest),
+					},
+				})
+			}
+		}
+		return rows, nil
+	}
+
+	tablePrinter := table.NewTablePrinter(table.PrintOptions{PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
+		columns := []metav1beta1.TableColumnDefinition{
+			{Name: "Resource", Type: "string"},
+			{Name: "Output", Type: "string"},
+			{Name: "Value", Type: "string"},
+			{Name: "Digest", Type: "string"},
+		}
+		h.TableHandler(columns, printOutputsList)
+	})
+
+	return tablePrinter.PrintObj(workload, w)
+}
+
 func WorkloadSupplyChainInfoPrinter(w io.Writer, workload *cartov1alpha1.Workload) error {
 	printSupplyChainInfo := func(workload *cartov1alpha1.Workload, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
 		workloadStatus := &workload.Status
@@ -112,45 +179,10 @@ func WorkloadSupplyChainInfoPrinter(w io.Writer, workload *cartov1alpha1.Workloa
 }
 
 func WorkloadIssuesPrinter(w io.Writer, workload *cartov1alpha1.Workload) error {
-	readyCondition := printer.FindCondition(workload.Status.Conditions, cartov1alpha1.ConditionReady)
-	healthyCondition := printer.FindCondition(workload.Status.Conditions, cartov1alpha1.ResourcesHealthy)
-	if readyCondition == nil {
-		return nil
-	}
-	printIssues := func(workload *cartov1alpha1.Workload, _ table.PrintOptions) ([]metav1beta1.TableRow, error) {
-		rows := []metav1beta1.TableRow{}
-		if strings.TrimSpace(readyCondition.Message) != "" {
-			readyRow := metav1beta1.TableRow{
-				Cells: []interface{}{
-					fmt.Sprintf("%s %s:", cartov1alpha1.WorkloadKind, printer.Sfaintf("[%s]", readyCondition.Reason)),
-					readyCondition.Message,
-				},
-			}
-			rows = append(rows, readyRow)
-		}
-
-		if healthyCondition != nil && strings.TrimSpace(healthyCondition.Message) != "" {
-			if strings.Compare(healthyCondition.Message, readyCondition.Message) != 0 {
-				healthyRow := metav1beta1.TableRow{
-					Cells: []interface{}{
-						fmt.Sprintf("%s %s:", cartov1alpha1.WorkloadKind, printer.Sfaintf("[%s]", healthyCondition.Reason)),
-						healthyCondition.Message,
-					},
-				}
-				rows = append(rows, healthyRow)
-			}
-		}
-		return rows, nil
-	}
-
-	tablePrinter := table.NewTablePrinter(table.PrintOptions{NoHeaders: true, PaddingStart: paddingStart}).With(func(h table.PrintHandler) {
-		h.TableHandler(nil, printIssues)
-	})
-
-	return tablePrinter.PrintObj(workload, w)
+	return MessagesPrinter(w, conditionMessages(cartov1alpha1.WorkloadKind, workload.Status.Conditions), true)
 }
 
-func findConditionReady(conditions []metav1.Condition, strReadyCondition string) (string, string) {
+func findConditionReady(conditions []metav1.Condition, strReadyCondition string, timestamps bool) (string, string) {
 	var ready string
 	var elapsedTransitionTime string
 
@@ -160,10 +192,33 @@ func findConditionReady(conditions []metav1.Condition, strReadyCondition string)
 		ready = string(conditionReady.Status)
 		elapsedTransitionTime = printer.TimestampSince(conditionReady.LastTransitionTime, time.Now())
 	}
+	if timestamps {
+		elapsedTransitionTime = transitionAge(conditions, conditionReady, time.Now())
+	}
 
 	return ready, elapsedTransitionTime
 }
 
+// transitionAge is how long ago the ready condition transitioned, or when its time is missing, the most
+// recent transition of any condition
+func transitionAge(conditions []metav1.Condition, conditionReady *metav1.Condition, now time.Time) string {
+	var lastTransitionTime metav1.Time
+	if conditionReady != nil {
+		lastTransitionTime = conditionReady.LastTransitionTime
+	}
+	if lastTransitionTime.IsZero() {
+		for _, condition := range conditions {
+			if condition.LastTransitionTime.After(lastTransitionTime.Time) {
+				lastTransitionTime = condition.LastTransitionTime
+			}
+		}
+	}
+	if lastTransitionTime.IsZero() {
+		return printer.TimestampSince(lastTransitionTime, now)
+	}
+	return fmt.Sprintf("%s ago", printer.TimestampSince(lastTransitionTime, now))
+}
+
 func getOutputRef(resource *cartov1alpha1.RealizedResource) string {
 	ref := printer.Sfaintf("not found")
 	if resource != nil && resource.StampedRef != nil {
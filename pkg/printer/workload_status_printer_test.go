@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -36,6 +37,7 @@ func TestWorkloadResourcesPrinter(t *testing.T) {
 	tests := []struct {
 		name           string
 		testWorkload   *cartov1alpha1.Workload
+		timestamps     bool
 		expectedOutput string
 	}{{
 		name: "various resources",
@@ -341,13 +343,61 @@ func TestWorkloadResourcesPrinter(t *testing.T) {
    RESOURCE          READY   HEALTHY   TIME   OUTPUT
    source-provider                            not found
    deliverable                                not found
+`,
+	}, {
+		name: "timestamps",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+			},
+			Status: cartov1alpha1.WorkloadStatus{
+				Resources: []cartov1alpha1.RealizedResource{{
+					Name: "source-provider",
+					Conditions: []metav1.Condition{
+						{
+							Type:               cartov1alpha1.ConditionResourceReady,
+							Status:             metav1.ConditionTrue,
+							LastTransitionTime: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+						},
+					},
+				}, {
+					Name: "image-builder",
+					Conditions: []metav1.Condition{
+						{
+							Type:   cartov1alpha1.ConditionResourceReady,
+							Status: metav1.ConditionUnknown,
+						},
+						{
+							Type:               cartov1alpha1.ConditionResourceSubmitted,
+							Status:             metav1.ConditionTrue,
+							LastTransitionTime: metav1.NewTime(time.Now().Add(-3 * time.Minute)),
+						},
+					},
+				}, {
+					Name: "deliverable",
+					Conditions: []metav1.Condition{
+						{
+							Type:   cartov1alpha1.ConditionResourceReady,
+							Status: metav1.ConditionUnknown,
+						},
+					},
+				}},
+			},
+		},
+		timestamps: true,
+		expectedOutput: `
+   RESOURCE          READY     HEALTHY   TIME        OUTPUT
+   source-provider   True                5m ago      not found
+   image-builder     Unknown             3m ago      not found
+   deliverable       Unknown             <unknown>   not found
 `,
 	}}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			output := &bytes.Buffer{}
-			if err := printer.WorkloadResourcesPrinter(output, test.testWorkload); err != nil {
+			if err := printer.WorkloadResourcesPrinter(output, test.testWorkload, test.timestamps); err != nil {
 				t.Errorf("WorkloadSourcePrinter() expected no error, got %v", err)
 			}
 			outputString := output.String()
@@ -358,6 +408,166 @@ func TestWorkloadResourcesPrinter(t *testing.T) {
 	}
 }
 
+func TestWorkloadResourcesWidePrinter(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	tests := []struct {
+		name           string
+		testWorkload   *cartov1alpha1.Workload
+		expectedOutput string
+	}{{
+		name: "stamped resources",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+			},
+			Status: cartov1alpha1.WorkloadStatus{
+				Resources: []cartov1alpha1.RealizedResource{{
+					Name: "source-provider",
+					StampedRef: &corev1.ObjectReference{
+						APIVersion: "source.toolkit.fluxcd.io/v1beta1",
+						Kind:       "GitRepository",
+						Namespace:  defaultNamespace,
+						Name:       workloadName,
+					},
+					Conditions: []metav1.Condition{{
+						Type:   cartov1alpha1.ConditionResourceReady,
+						Status: metav1.ConditionTrue,
+					}},
+				}, {
+					Name: "deliverable",
+					StampedRef: &corev1.ObjectReference{
+						APIVersion: "carto.run/v1alpha1",
+						Kind:       "Deliverable",
+						Namespace:  defaultNamespace,
+						Name:       workloadName,
+					},
+				}, {
+					Name: "image-provider",
+				}},
+			},
+		},
+		expectedOutput: `
+   RESOURCE          READY   HEALTHY   TIME        OUTPUT                      API VERSION                        NAMESPACE
+   source-provider   True              <unknown>   GitRepository/my-workload   source.toolkit.fluxcd.io/v1beta1   default
+   image-provider                                  not found                   <empty>                            <empty>
+`,
+	}, {
+		name: "no resources",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+			},
+		},
+		expectedOutput: `
+   RESOURCE   READY   HEALTHY   TIME   OUTPUT   API VERSION   NAMESPACE
+`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			if err := printer.WorkloadResourcesWidePrinter(output, test.testWorkload, false); err != nil {
+				t.Errorf("WorkloadResourcesWidePrinter() expected no error, got %v", err)
+			}
+			if diff := cmp.Diff(strings.TrimPrefix(test.expectedOutput, "\n"), output.String()); diff != "" {
+				t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkloadResourceOutputsPrinter(t *testing.T) {
+	defaultNamespace := "default"
+	workloadName := "my-workload"
+
+	tests := []struct {
+		name           string
+		testWorkload   *cartov1alpha1.Workload
+		expectedOutput string
+	}{{
+		name: "outputs",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+			},
+			Status: cartov1alpha1.WorkloadStatus{
+				Resources: []cartov1alpha1.RealizedResource{{
+					Name: "source-provider",
+					Outputs: []cartov1alpha1.Output{{
+						Name:    "url",
+						Preview: "http://source-controller.flux-system.svc.cluster.local./gitrepository/default/my-workload/abc123.tar.gz\n",
+						Digest:  "sha256:1f2e",
+					}, {
+						Name:    "revision",
+						Preview: "main/abc123\n",
+						Digest:  "sha256:3d4c",
+					}},
+				}, {
+					Name: "image-provider",
+					Outputs: []cartov1alpha1.Output{{
+						Name:    "image",
+						Preview: "registry.example/my-workload@sha256:978be33a7f0cbe89bf48fbb438846047a28e1298d6d10d0de2d64bdc102a9e69\n",
+						Digest:  "sha256:5b6a",
+					}},
+				}, {
+					Name: "config-provider",
+					Outputs: []cartov1alpha1.Output{{
+						Name:    "config",
+						Preview: "metadata:\n  annotations:\n    developer.conventions/target-containers: workload\n",
+						Digest:  "sha256:7f8e",
+					}},
+				}, {
+					Name: "deliverable",
+					StampedRef: &corev1.ObjectReference{
+						Kind: "Deliverable",
+						Name: workloadName,
+					},
+					Outputs: []cartov1alpha1.Output{{
+						Name: "config",
+					}},
+				}, {
+					Name: "app-config",
+				}},
+			},
+		},
+		expectedOutput: `
+   RESOURCE          OUTPUT     VALUE                                                                                                     DIGEST
+   source-provider   url        http://source-controller.flux-system.svc.cluster.local./gitrepository/default/my-workload/abc123.tar.gz   sha256:1f2e
+   source-provider   revision   main/abc123                                                                                               sha256:3d4c
+   image-provider    image      registry.example/my-workload@sha256:978be33a7f0cbe89bf48fbb438846047a28e1298d6d10d0de2d64bdc102a9e69      sha256:5b6a
+   config-provider   config     metadata: ...                                                                                             sha256:7f8e
+`,
+	}, {
+		name: "no outputs",
+		testWorkload: &cartov1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workloadName,
+				Namespace: defaultNamespace,
+			},
+		},
+		expectedOutput: `
+   RESOURCE   OUTPUT   VALUE   DIGEST
+`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			if err := printer.WorkloadResourceOutputsPrinter(output, test.testWorkload); err != nil {
+				t.Errorf("WorkloadResourceOutputsPrinter() expected no error, got %v", err)
+			}
+			if diff := cmp.Diff(strings.TrimPrefix(test.expectedOutput, "\n"), output.String()); diff != "" {
+				t.Errorf("Unexpected output (-expected, +actual): %s", diff)
+			}
+		})
+	}
+}
+
 func TestWorkloadSupplyChainInfoPrinter(t *testing.T) {
 	defaultNamespace := "default"
 	workloadName := "my-workload"
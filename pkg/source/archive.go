@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // ExtractZip extracts contents of fileName zip file to dir
@@ -42,13 +43,18 @@ func ExtractZip(dir, fileName string) error {
 	}
 
 	for _, file := range zipReader.File {
-		filePath := filepath.Join(dir, file.Name)
+		// zip files created on Windows may use backslashes as separators
+		name, err := NormalizePath(file.Name)
+		if err != nil {
+			return err
+		}
+		filePath := filepath.Join(dir, name)
 		fileMode := file.Mode()
 		if isFatFile(file.FileHeader) {
 			fileMode = 0777
 		}
 
-		if file.FileInfo().IsDir() {
+		if file.FileInfo().IsDir() || strings.HasSuffix(file.Name, "\\") {
 			err := os.MkdirAll(filePath, fileMode)
 			if err != nil {
 				return err
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/source"
+)
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	utilruntime.Must(os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	utilruntime.Must(os.MkdirAll(filepath.Join(dir, "target"), 0755))
+	utilruntime.Must(os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("package main\n"), 0644))
+
+	fingerprint := func(exclusions ...string) string {
+		t.Helper()
+		fp, err := source.Fingerprint(dir, exclusions)
+		if err != nil {
+			t.Fatalf("Fingerprint() errored %v", err)
+		}
+		return fp
+	}
+
+	initial := fingerprint("target")
+	if again := fingerprint("target"); again != initial {
+		t.Errorf("Fingerprint() expected to be stable, got %q then %q", initial, again)
+	}
+
+	// excluded paths do not change the fingerprint
+	utilruntime.Must(os.WriteFile(filepath.Join(dir, "target", "app.jar"), []byte("jar"), 0644))
+	if excluded := fingerprint("target"); excluded != initial {
+		t.Errorf("Fingerprint() expected excluded files to be ignored")
+	}
+	if included := fingerprint(); included == initial {
+		t.Errorf("Fingerprint() expected files that are not excluded to change the fingerprint")
+	}
+
+	// modified source changes the fingerprint
+	later := time.Now().Add(time.Minute)
+	utilruntime.Must(os.Chtimes(filepath.Join(dir, "src", "main.go"), later, later))
+	if modified := fingerprint("target"); modified == initial {
+		t.Errorf("Fingerprint() expected a modified file to change the fingerprint")
+	}
+
+	if _, err := source.Fingerprint(filepath.Join(dir, "missing"), nil); err == nil {
+		t.Errorf("Fingerprint() expected error for a missing directory")
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GitIgnoreFile lists the files git does not track, they are not published either
+const GitIgnoreFile = ".gitignore"
+
+// DefaultExcludedNames are never published, at any depth of the source, unless an ignore file
+// negates them
+var DefaultExcludedNames = []string{".git", "node_modules"}
+
+// ignoreRule is a pattern of an ignore file, in the gitignore format
+type ignoreRule struct {
+	// base is the directory of the ignore file, relative to the source and slash separated
+	base string
+	// segments of the pattern, "**" matches any number of directories
+	segments []string
+	// anchored patterns match paths relative to base, the others match names at any depth
+	anchored bool
+	dirOnly  bool
+	negate   bool
+}
+
+// ExcludedPaths lists the files and directories of dir to leave out of the published source,
+// relative to dir. The exclusions are layered: DefaultExcludedNames first, then the patterns of
+// the ignoreFiles, read in the given order in every directory, where the files of nested
+// directories take precedence over the ones of their parents. Patterns follow the gitignore
+// format, including "!" to negate a pattern, a trailing "/" to only match directories and "**"
+// to match any number of directories. The content of an excluded directory is not listed.
+func ExcludedPaths(dir string, ignoreFiles ...string) ([]string, error) {
+	rules := []ignoreRule{}
+	for _, name := range DefaultExcludedNames {
+		rules = append(rules, parseIgnoreRule("", name))
+	}
+
+	excluded := []string{}
+	var walk func(rel string) error
+	walk = func(rel string) error {
+		abs := filepath.Join(dir, filepath.FromSlash(rel))
+		parent := len(rules)
+		for _, name := range ignoreFiles {
+			fileRules, err := readIgnoreFile(filepath.Join(abs, name), rel)
+			if err != nil {
+				return err
+			}
+			rules = append(rules, fileRules...)
+		}
+		// the rules of this directory do not apply to its siblings
+		defer func() { rules = rules[:parent] }()
+
+		entries, err := os.ReadDir(abs)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			child := path.Join(rel, entry.Name())
+			if isIgnored(rules, child, entry.IsDir()) {
+				excluded = append(excluded, filepath.FromSlash(child))
+				continue
+			}
+			if entry.IsDir() {
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(""); err != nil {
+		return nil, err
+	}
+	return excluded, nil
+}
+
+// isIgnored returns whether the last rule matching the path excludes it
+func isIgnored(rules []ignoreRule, rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.match(rel, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func readIgnoreFile(file, base string) ([]ignoreRule, error) {
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rules := []ignoreRule{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rules = append(rules, parseIgnoreRule(base, line))
+	}
+	return rules, scanner.Err()
+}
+
+func parseIgnoreRule(base, pattern string) ignoreRule {
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	// a separator at the beginning or in the middle of the pattern anchors it to the ignore file
+	rule.anchored = strings.Contains(pattern, "/")
+	rule.segments = strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	return rule
+}
+
+func (r ignoreRule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = strings.TrimPrefix(rel, r.base+"/")
+	}
+	parts := strings.Split(rel, "/")
+	if !r.anchored {
+		return matchSegments(r.segments, parts[len(parts)-1:])
+	}
+	return matchSegments(r.segments, parts)
+}
+
+func matchSegments(segments, parts []string) bool {
+	if len(segments) == 0 {
+		return len(parts) == 0
+	}
+	if segments[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(segments[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(segments[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(segments[1:], parts[1:])
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/source"
+)
+
+func TestExcludedPaths(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		ignoreFiles []string
+		expected    []string
+	}{{
+		name: "defaults",
+		files: map[string]string{
+			".git/HEAD":                    "ref: refs/heads/main",
+			"main.go":                      "package main",
+			"node_modules/left-pad/index":  "",
+			"web/node_modules/react/index": "",
+			"web/index.js":                 "",
+		},
+		expected: []string{".git", "node_modules", "web/node_modules"},
+	}, {
+		name: "gitignore",
+		files: map[string]string{
+			".gitignore":       "# build outputs\n\ntarget/\n*.log\n/local.env\n",
+			"main.go":          "package main",
+			"app.log":          "",
+			"local.env":        "",
+			"target/app.jar":   "",
+			"src/debug.log":    "",
+			"src/local.env":    "",
+			"src/target":       "a file, not a directory",
+			"src/main/main.go": "",
+		},
+		ignoreFiles: []string{source.GitIgnoreFile},
+		expected:    []string{"app.log", "local.env", "src/debug.log", "target"},
+	}, {
+		name: "nested ignore files",
+		files: map[string]string{
+			".gitignore":         "*.tmp\n",
+			"a.tmp":              "",
+			"api/.gitignore":     "generated/\n!keep.tmp\n",
+			"api/keep.tmp":       "",
+			"api/drop.tmp":       "",
+			"api/generated/a.go": "",
+			"web/generated/a.js": "",
+			"web/keep.tmp":       "",
+		},
+		ignoreFiles: []string{source.GitIgnoreFile},
+		expected:    []string{"a.tmp", "api/drop.tmp", "api/generated", "web/keep.tmp"},
+	}, {
+		name: "exclude file takes precedence over gitignore",
+		files: map[string]string{
+			".gitignore":     "dist/\n",
+			".tanzuignore":   "!dist/\ndocs\n.tanzuignore\n",
+			"dist/app.js":    "",
+			"docs/readme.md": "",
+			"main.go":        "",
+		},
+		ignoreFiles: []string{source.GitIgnoreFile, ".tanzuignore"},
+		expected:    []string{".tanzuignore", "docs"},
+	}, {
+		name: "double star",
+		files: map[string]string{
+			".tanzuignore":            "**/fixtures\nconfig/**/secret.yaml\n",
+			"config/secret.yaml":      "",
+			"config/dev/secret.yaml":  "",
+			"config/dev/app.yaml":     "",
+			"test/unit/fixtures/a":    "",
+			"test/fixtures/b":         "",
+			"test/unit/fixtures_test": "",
+		},
+		ignoreFiles: []string{".tanzuignore"},
+		expected:    []string{"config/dev/secret.yaml", "config/secret.yaml", "test/fixtures", "test/unit/fixtures"},
+	}, {
+		name: "negated default",
+		files: map[string]string{
+			".tanzuignore":         "!node_modules\n",
+			"node_modules/a/index": "",
+			".git/HEAD":            "",
+		},
+		ignoreFiles: []string{".tanzuignore"},
+		expected:    []string{".git"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range test.files {
+				p := filepath.Join(dir, filepath.FromSlash(name))
+				utilruntime.Must(os.MkdirAll(filepath.Dir(p), 0755))
+				utilruntime.Must(os.WriteFile(p, []byte(content), 0644))
+			}
+			expected := []string{}
+			for _, p := range test.expected {
+				expected = append(expected, filepath.FromSlash(p))
+			}
+
+			actual, err := source.ExcludedPaths(dir, test.ignoreFiles...)
+			if err != nil {
+				t.Fatalf("ExcludedPaths() errored %v", err)
+			}
+			if diff := cmp.Diff(expected, actual); diff != "" {
+				t.Errorf("ExcludedPaths() (-expected, +actual) = %s", diff)
+			}
+		})
+	}
+
+	if _, err := source.ExcludedPaths(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("ExcludedPaths() expected error for a missing directory")
+	}
+}
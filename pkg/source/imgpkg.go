@@ -20,10 +20,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"path"
+	"path/filepath"
 	"time"
 
 	regname "github.com/google/go-containerregistry/pkg/name"
+	regv1 "github.com/google/go-containerregistry/pkg/v1"
+	ctlimg "github.com/vmware-tanzu/carvel-imgpkg/pkg/imgpkg/image"
 	"github.com/vmware-tanzu/carvel-imgpkg/pkg/imgpkg/plainimage"
 	"github.com/vmware-tanzu/carvel-imgpkg/pkg/imgpkg/registry"
 
@@ -31,15 +35,197 @@ import (
 )
 
 type RegistryOpts struct {
-	CACertPaths      []string
+	CACertPaths []string
+	// HostCACertPaths are CA certificates by registry host, trusted in addition to CACertPaths for
+	// images of that host only
+	HostCACertPaths  map[string][]string
 	RegistryUsername string
 	RegistryPassword string
 	RegistryToken    string
+	// Proxy is the URL of the proxy the registry is reached through, instead of the one of the
+	// HTTPS_PROXY environment variable
+	Proxy string
 }
 
-func ImgpkgPush(ctx context.Context, dir string, excludedFiles []string, registryOpts *RegistryOpts, image string) (string, error) {
+func ImgpkgPush(ctx context.Context, dir string, excludedFiles []string, registryOpts *RegistryOpts, image string) (digestedImage string, err error) {
+	uploadRef, err := regname.NewTag(image, regname.WeakValidation)
+	if err != nil {
+		return "", fmt.Errorf("parsing '%s': %s", image, err)
+	}
+
+	reg, err := newRegistry(ctx, registryOpts, uploadRef.RegistryStr())
+	if err != nil {
+		return "", err
+	}
+
+	var writer plainimage.ImagesWriter = reg
+	report := RetrieveProgressReporter(ctx)
+	if report != nil {
+		writer = &progressWriter{ImagesWriter: reg, image: image, report: report}
+		report(ProgressEvent{Phase: ProgressPhasePackaging, Image: image})
+	}
+
+	state := RetrieveUploadState(ctx)
+	if state == nil || state.Image != image {
+		state, _ = PrepareUpload(ctx, dir, excludedFiles, image)
+	}
+	var u *upload
+	if state != nil {
+		u = startUpload(ctx, state)
+	}
+	var tarball string
+	if u != nil {
+		// the packaged source is kept for the next upload to resume when this one does not complete
+		tarball = u.sourceFile()
+		defer func() {
+			if err == nil {
+				u.finish()
+			}
+		}()
+	} else {
+		tmpDir, err := os.MkdirTemp("", "tanzu-apps-source")
+		if err != nil {
+			return "", err
+		}
+		defer os.RemoveAll(tmpDir)
+		tarball = filepath.Join(tmpDir, uploadSourceFileName)
+	}
+
+	excludedFiles = append(excludedFiles, path.Join(dir, ".imgpkg"))
+	if u == nil || !u.packaged() {
+		if err := packageSource(ctx, tarball, dir, excludedFiles, logger.RetrieveSourceImageLogger(ctx)); err != nil {
+			return "", err
+		}
+	}
+	img, err := ctlimg.NewFileImage(tarball, nil)
+	if err != nil {
+		return "", err
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+
+	// the image is uploaded by digest and only tagged once complete, an interrupted upload leaves the
+	// tag as it was and no other tag behind
+	digestRef := uploadRef.Context().Digest(digest.String())
+	if err := untilDone(ctx, func() error { return writer.WriteImage(digestRef, img, nil) }); err != nil {
+		return "", wrapWriteError(ctx, uploadRef, err)
+	}
+	if err := untilDone(ctx, func() error { return writer.WriteTag(uploadRef, img) }); err != nil {
+		return "", wrapWriteError(ctx, uploadRef, err)
+	}
+
+	digestedImage = fmt.Sprintf("%s@%s", uploadRef.Name(), digest)
+	if report != nil {
+		report(ProgressEvent{Phase: ProgressPhasePublished, Image: digestedImage})
+	}
+	return digestedImage, nil
+}
+
+// untilDone returns the result of fn, or the error of the context when it is done first. A request
+// to the registry cannot be cancelled half way, it is left to complete on its own.
+func untilDone(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func wrapWriteError(ctx context.Context, ref regname.Tag, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	return fmt.Errorf("Writing '%s': %s", ref.Name(), err)
+}
+
+// PublishedImage returns the image reference, with the tag and the digest, the source in dir would
+// be published as, and whether the tag of the image already points to that digest in the registry.
+// The image is packaged locally without being uploaded, its digest only depends on the content of the
+// files. Any error, such as a tag that was never pushed, means the source has to be published.
+func PublishedImage(ctx context.Context, dir string, excludedFiles []string, registryOpts *RegistryOpts, image string) (string, bool) {
+	uploadRef, err := regname.NewTag(image, regname.WeakValidation)
+	if err != nil {
+		return "", false
+	}
+
+	tmpDir, err := os.MkdirTemp("", "tanzu-apps-source")
+	if err != nil {
+		return "", false
+	}
+	defer os.RemoveAll(tmpDir)
+	tarball := filepath.Join(tmpDir, uploadSourceFileName)
+	excludedFiles = append(excludedFiles, path.Join(dir, ".imgpkg"))
+	if err := packageSource(ctx, tarball, dir, excludedFiles, logger.RetrieveSourceImageLogger(ctx)); err != nil {
+		return "", false
+	}
+	img, err := ctlimg.NewFileImage(tarball, nil)
+	if err != nil {
+		return "", false
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return "", false
+	}
+	digestedImage := fmt.Sprintf("%s@%s", uploadRef.Name(), digest)
+
+	published, err := ImageDigest(ctx, registryOpts, image)
+	if err != nil {
+		return digestedImage, false
+	}
+	return digestedImage, published == digestedImage
+}
+
+// ImageDigest resolves the tag of an image to the digest it currently points to, with a HEAD request to
+// the registry. Returns the image reference with both the tag and the digest.
+func ImageDigest(ctx context.Context, registryOpts *RegistryOpts, image string) (string, error) {
+	ref, err := regname.NewTag(image, regname.WeakValidation)
+	if err != nil {
+		return "", fmt.Errorf("parsing '%s': %s", image, err)
+	}
+
+	reg, err := newRegistry(ctx, registryOpts, ref.RegistryStr())
+	if err != nil {
+		return "", err
+	}
+	digest, err := reg.Digest(ref)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s@%s", ref.Name(), digest), nil
+}
+
+// ImageConfig fetches the config of an image, referenced by tag or digest, holding the labels and the
+// exposed ports the image was built with
+func ImageConfig(ctx context.Context, registryOpts *RegistryOpts, image string) (*regv1.ConfigFile, error) {
+	ref, err := regname.ParseReference(image, regname.WeakValidation)
+	if err != nil {
+		return nil, fmt.Errorf("parsing '%s': %s", image, err)
+	}
+
+	reg, err := newRegistry(ctx, registryOpts, ref.Context().RegistryStr())
+	if err != nil {
+		return nil, err
+	}
+	img, err := reg.Image(ref)
+	if err != nil {
+		return nil, err
+	}
+	return img.ConfigFile()
+}
+
+// newRegistry creates a client for the registry of host, trusting the CA certificates of that host along
+// with the ones of every host. The registry is reached through the proxy of the options, or the one of
+// the HTTPS_PROXY environment variable.
+func newRegistry(ctx context.Context, registryOpts *RegistryOpts, host string) (registry.Registry, error) {
 	options := registry.Opts{
-		CACertPaths:           registryOpts.CACertPaths,
+		CACertPaths:           registryOpts.CACertPathsFor(host),
 		Username:              registryOpts.RegistryUsername,
 		Password:              registryOpts.RegistryPassword,
 		Token:                 registryOpts.RegistryToken,
@@ -51,30 +237,27 @@ func ImgpkgPush(ctx context.Context, dir string, excludedFiles []string, registr
 	var reg registry.Registry
 	var err error
 	transport := RetrieveContainerRemoteTransport(ctx)
-	if transport == nil {
-		reg, err = registry.NewSimpleRegistry(options)
-	} else {
+	switch {
+	case transport != nil:
 		reg, err = registry.NewSimpleRegistryWithTransport(options, *transport)
+	case registryOpts.Proxy != "":
+		var proxyTransport http.RoundTripper
+		if proxyTransport, err = newProxyTransport(registryOpts, host); err == nil {
+			reg, err = registry.NewSimpleRegistryWithTransport(options, proxyTransport)
+		}
+	default:
+		reg, err = registry.NewSimpleRegistry(options)
 	}
 	if err != nil {
-		return "", fmt.Errorf("unable to create a registry with provided options: %v", err)
-	}
-
-	uploadRef, err := regname.NewTag(image, regname.WeakValidation)
-	if err != nil {
-		return "", fmt.Errorf("parsing '%s': %s", image, err)
-	}
-
-	excludedFiles = append(excludedFiles, path.Join(dir, ".imgpkg"))
-	logger := logger.RetrieveSourceImageLogger(ctx)
-	digest, err := plainimage.NewContents([]string{dir}, excludedFiles).Push(uploadRef, nil, reg, logger)
-	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("unable to create a registry with provided options: %v", err)
 	}
+	return reg, nil
+}
 
-	// get an image ref with a tag and digest
-	digestRef, _ := regname.NewDigest(digest, regname.WeakValidation)
-	return fmt.Sprintf("%s@%s", uploadRef.Name(), digestRef.DigestStr()), nil
+// CACertPathsFor returns the CA certificates to trust for the registry of host
+func (o *RegistryOpts) CACertPathsFor(host string) []string {
+	paths := append([]string{}, o.CACertPaths...)
+	return append(paths, o.HostCACertPaths[host]...)
 }
 
 type registryOptionsStashKey struct{}
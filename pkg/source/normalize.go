@@ -0,0 +1,120 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// line endings of the text files of the published source
+const (
+	// LineEndingsKeep publishes the files as they are
+	LineEndingsKeep = "keep"
+	// LineEndingsLF converts the CRLF line endings of text files to LF, as checked out by git on Windows
+	LineEndingsLF = "lf"
+)
+
+var LineEndings = []string{LineEndingsKeep, LineEndingsLF}
+
+// binarySniffLen is how much of a file is read to tell text from binary files, the same as git does
+const binarySniffLen = 8000
+
+// NormalizePath converts a path using either separator, like the entries of zip files created on
+// Windows, to a relative path with the separator of the current OS. Paths escaping the directory they
+// are relative to are rejected.
+func NormalizePath(name string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") || filepath.VolumeName(filepath.FromSlash(clean)) != "" {
+		return "", fmt.Errorf("illegal file path %q", name)
+	}
+	return filepath.FromSlash(clean), nil
+}
+
+// NormalizeLineEndings copies the source in dir to a new temporary directory, without the excluded
+// paths, converting the CRLF line endings of the text files to LF. The mode and modification time of
+// the files are kept, so the copy has the same fingerprint as long as the source is unchanged. The
+// caller removes the returned directory once done.
+func NormalizeLineEndings(dir string, excludedFiles []string) (string, error) {
+	staged, err := os.MkdirTemp("", "source-")
+	if err != nil {
+		return "", err
+	}
+	// the mode and times of directories are set once their files are written
+	dirs := []os.FileInfo{}
+	dirPaths := []string{}
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		for _, excluded := range excludedFiles {
+			if excluded == relPath {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		target := filepath.Join(staged, relPath)
+		if info.IsDir() {
+			dirs = append(dirs, info)
+			dirPaths = append(dirPaths, target)
+			return os.MkdirAll(target, 0700)
+		}
+		if !info.Mode().IsRegular() {
+			// the packaging rejects anything else than files and directories
+			return fmt.Errorf("expected file %q to be a regular file", p)
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if isText(content) {
+			content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+		}
+		if err := os.WriteFile(target, content, info.Mode().Perm()); err != nil {
+			return err
+		}
+		return os.Chtimes(target, info.ModTime(), info.ModTime())
+	})
+	for i := len(dirs) - 1; err == nil && i >= 0; i-- {
+		if err = os.Chmod(dirPaths[i], dirs[i].Mode().Perm()); err == nil {
+			err = os.Chtimes(dirPaths[i], dirs[i].ModTime(), dirs[i].ModTime())
+		}
+	}
+	if err != nil {
+		os.RemoveAll(staged)
+		return "", err
+	}
+	return staged, nil
+}
+
+// isText tells text from binary content, binary files have a NUL byte at the beginning
+func isText(content []byte) bool {
+	if len(content) > binarySniffLen {
+		content = content[:binarySniffLen]
+	}
+	return bytes.IndexByte(content, 0) == -1
+}
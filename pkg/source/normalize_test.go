@@ -0,0 +1,193 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/source"
+)
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		expected  string
+		shouldErr bool
+	}{{
+		name:     "slashes",
+		path:     "src/main/app.go",
+		expected: "src/main/app.go",
+	}, {
+		name:     "backslashes",
+		path:     `src\main\app.go`,
+		expected: "src/main/app.go",
+	}, {
+		name:     "mixed separators",
+		path:     `src\main/./scripts\run.sh`,
+		expected: "src/main/scripts/run.sh",
+	}, {
+		name:     "directory",
+		path:     `src\main\`,
+		expected: "src/main",
+	}, {
+		name:      "parent directory",
+		path:      `..\app.go`,
+		shouldErr: true,
+	}, {
+		name:      "nested parent directory",
+		path:      `src\..\..\app.go`,
+		shouldErr: true,
+	}, {
+		name:      "absolute",
+		path:      `\etc\passwd`,
+		shouldErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := source.NormalizePath(test.path)
+			if (err != nil) != test.shouldErr {
+				t.Fatalf("NormalizePath() shouldErr %t, got %v", test.shouldErr, err)
+			}
+			if diff := cmp.Diff(filepath.FromSlash(test.expected), actual); !test.shouldErr && diff != "" {
+				t.Errorf("NormalizePath() (-expected, +actual) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestExtractZipWindowsPaths(t *testing.T) {
+	dir := t.TempDir()
+	zipFile := filepath.Join(dir, "source.zip")
+	f, err := os.Create(zipFile)
+	utilruntime.Must(err)
+	w := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		`src\main\app.go`:     "package main\r\n",
+		`scripts\build.sh`:    "#!/bin/sh\r\n",
+		`src\main\resources\`: "",
+	} {
+		entry, err := w.Create(name)
+		utilruntime.Must(err)
+		_, err = entry.Write([]byte(content))
+		utilruntime.Must(err)
+	}
+	utilruntime.Must(w.Close())
+	utilruntime.Must(f.Close())
+
+	out := filepath.Join(dir, "out")
+	if err := source.ExtractZip(out, zipFile); err != nil {
+		t.Fatalf("ExtractZip() errored %v", err)
+	}
+	for _, name := range []string{"src/main/app.go", "scripts/build.sh"} {
+		if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(name))); err != nil {
+			t.Errorf("ExtractZip() expected file %q, %v", name, err)
+		}
+	}
+	if !source.IsDir(filepath.Join(out, "src", "main", "resources")) {
+		t.Errorf("ExtractZip() expected directory %q", "src/main/resources")
+	}
+
+	f, err = os.Create(zipFile)
+	utilruntime.Must(err)
+	w = zip.NewWriter(f)
+	_, err = w.Create(`..\evil.sh`)
+	utilruntime.Must(err)
+	utilruntime.Must(w.Close())
+	utilruntime.Must(f.Close())
+	if err := source.ExtractZip(filepath.Join(dir, "evil"), zipFile); err == nil {
+		t.Errorf("ExtractZip() expected error for an entry outside of the directory")
+	}
+}
+
+func TestNormalizeLineEndings(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"mvnw":                "#!/bin/sh\r\nexec java \"$@\"\r\n",
+		"src/main/App.java":   "class App {\r\n}\n",
+		"src/main/logo.png":   "\x89PNG\r\n\x00\x00\r\n",
+		"target/app.jar":      "excluded\r\n",
+		"docs/unchanged.txt":  "already lf\n",
+		"docs/lone-cr.txt":    "old mac\rline endings\r",
+		"src/test/empty.java": "",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		utilruntime.Must(os.MkdirAll(filepath.Dir(p), 0755))
+		utilruntime.Must(os.WriteFile(p, []byte(content), 0644))
+	}
+	utilruntime.Must(os.Chmod(filepath.Join(dir, "mvnw"), 0755))
+	excluded := []string{"target"}
+
+	staged, err := source.NormalizeLineEndings(dir, excluded)
+	if err != nil {
+		t.Fatalf("NormalizeLineEndings() errored %v", err)
+	}
+	defer os.RemoveAll(staged)
+
+	expected := map[string]string{
+		"mvnw":                "#!/bin/sh\nexec java \"$@\"\n",
+		"src/main/App.java":   "class App {\n}\n",
+		"src/main/logo.png":   "\x89PNG\r\n\x00\x00\r\n",
+		"docs/unchanged.txt":  "already lf\n",
+		"docs/lone-cr.txt":    "old mac\rline endings\r",
+		"src/test/empty.java": "",
+	}
+	actual := map[string]string{}
+	err = filepath.Walk(staged, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(staged, p)
+		content, err := os.ReadFile(p)
+		actual[filepath.ToSlash(rel)] = string(content)
+		return err
+	})
+	utilruntime.Must(err)
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("NormalizeLineEndings() (-expected, +actual) = %s", diff)
+	}
+
+	info, err := os.Stat(filepath.Join(staged, "mvnw"))
+	utilruntime.Must(err)
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("NormalizeLineEndings() expected mode 0755 to be kept, got %v", info.Mode().Perm())
+	}
+
+	// the copy of an unchanged source is the same, for a publish interrupted half way to be resumed
+	again, err := source.NormalizeLineEndings(dir, excluded)
+	if err != nil {
+		t.Fatalf("NormalizeLineEndings() errored %v", err)
+	}
+	defer os.RemoveAll(again)
+	expectedFingerprint, _ := source.Fingerprint(staged, nil)
+	actualFingerprint, _ := source.Fingerprint(again, nil)
+	if expectedFingerprint != actualFingerprint {
+		t.Errorf("NormalizeLineEndings() expected the same fingerprint for the same source")
+	}
+
+	if _, err := source.NormalizeLineEndings(filepath.Join(dir, "missing"), nil); err == nil {
+		t.Errorf("NormalizeLineEndings() expected error for a missing directory")
+	}
+}
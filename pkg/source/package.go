@@ -0,0 +1,145 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/vmware-tanzu/carvel-imgpkg/pkg/imgpkg/plainimage"
+)
+
+// packageSource writes the source in dir to the tarball at path, with the same entries and static
+// headers imgpkg packages an image with, so the digest of the image does not depend on how it was
+// packaged. The tarball is written next to path and only moved to path once complete.
+func packageSource(ctx context.Context, path, dir string, excludedFiles []string, logger plainimage.Logger) error {
+	partial := path + ".partial"
+	file, err := os.Create(partial)
+	if err != nil {
+		return err
+	}
+	err = writeTarball(ctx, file, dir, excludedFiles, logger)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(partial)
+		return err
+	}
+	return os.Rename(partial, path)
+}
+
+func writeTarball(ctx context.Context, file io.Writer, dir string, excludedFiles []string, logger plainimage.Logger) error {
+	tarWriter := tar.NewWriter(file)
+	defer tarWriter.Close()
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return addFileToTar(tarWriter, dir, filepath.Base(dir), info, excludedFiles, logger)
+	}
+	err = filepath.Walk(dir, func(walkedPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, walkedPath)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if isExcluded(relPath, excludedFiles) {
+				return filepath.SkipDir
+			}
+			logf(logger, "dir: %s\n", relPath)
+			return tarWriter.WriteHeader(&tar.Header{
+				Name:     tarPath(relPath),
+				Mode:     0700,        // static
+				ModTime:  time.Time{}, // static
+				Typeflag: tar.TypeDir,
+			})
+		}
+		if (info.Mode() & os.ModeType) != 0 {
+			return fmt.Errorf("Expected file '%s' to be a regular file", walkedPath)
+		}
+		return addFileToTar(tarWriter, walkedPath, relPath, info, excludedFiles, logger)
+	})
+	if err != nil {
+		return fmt.Errorf("Adding file '%s' to tar: %s", dir, err)
+	}
+	return nil
+}
+
+func addFileToTar(tarWriter *tar.Writer, fullPath, relPath string, info os.FileInfo, excludedFiles []string, logger plainimage.Logger) error {
+	if isExcluded(relPath, excludedFiles) {
+		return nil
+	}
+	logf(logger, "file: %s\n", relPath)
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name:     tarPath(relPath),
+		Size:     info.Size(),
+		Mode:     int64(info.Mode() & 0700), // static
+		ModTime:  time.Time{},               // static
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tarWriter, file)
+	return err
+}
+
+// tarPath keeps the same path format in images packaged on Windows
+func tarPath(relPath string) string {
+	if runtime.GOOS == "windows" {
+		return strings.ReplaceAll(relPath, "\\", "/")
+	}
+	return relPath
+}
+
+func isExcluded(relPath string, excludedFiles []string) bool {
+	for _, excluded := range excludedFiles {
+		if excluded == relPath {
+			return true
+		}
+	}
+	return false
+}
+
+func logf(logger plainimage.Logger, msg string, args ...interface{}) {
+	if logger != nil {
+		logger.Logf(msg, args...)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	regname "github.com/google/go-containerregistry/pkg/name"
+	regv1 "github.com/google/go-containerregistry/pkg/v1"
+	regremote "github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/vmware-tanzu/carvel-imgpkg/pkg/imgpkg/plainimage"
+)
+
+const (
+	ProgressPhasePackaging = "packaging"
+	ProgressPhaseUploading = "uploading"
+	ProgressPhaseTagging   = "tagging"
+	ProgressPhasePublished = "published"
+)
+
+// ProgressEvent describes the state of a source code upload at a point in time
+type ProgressEvent struct {
+	Phase      string `json:"phase"`
+	Image      string `json:"image,omitempty"`
+	Layer      string `json:"layer,omitempty"`
+	Bytes      int64  `json:"bytes"`
+	TotalBytes int64  `json:"totalBytes"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ProgressReporter receives the events emitted while publishing source code
+type ProgressReporter func(event ProgressEvent)
+
+// NewJSONProgressReporter writes each event as a single line of JSON (NDJSON)
+func NewJSONProgressReporter(w io.Writer) ProgressReporter {
+	m := sync.Mutex{}
+	encoder := json.NewEncoder(w)
+	return func(event ProgressEvent) {
+		m.Lock()
+		defer m.Unlock()
+		// progress is best effort, a failed write must not fail the upload
+		_ = encoder.Encode(event)
+	}
+}
+
+type progressReporterStashKey struct{}
+
+func StashProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterStashKey{}, reporter)
+}
+
+func RetrieveProgressReporter(ctx context.Context) ProgressReporter {
+	reporter, ok := ctx.Value(progressReporterStashKey{}).(ProgressReporter)
+	if !ok {
+		return nil
+	}
+	return reporter
+}
+
+var _ plainimage.ImagesWriter = (*progressWriter)(nil)
+
+// progressWriter forwards the registry upload updates of the wrapped writer to a ProgressReporter
+type progressWriter struct {
+	plainimage.ImagesWriter
+	image  string
+	report ProgressReporter
+}
+
+func (w *progressWriter) WriteImage(ref regname.Reference, img regv1.Image, _ chan regv1.Update) error {
+	layer := ""
+	if layers, err := img.Layers(); err == nil && len(layers) == 1 {
+		if digest, err := layers[0].Digest(); err == nil {
+			layer = digest.String()
+		}
+	}
+
+	updates := make(chan regv1.Update)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case u, ok := <-updates:
+				if !ok {
+					return
+				}
+				event := ProgressEvent{Phase: ProgressPhaseUploading, Image: w.image, Layer: layer, Bytes: u.Complete, TotalBytes: u.Total}
+				if u.Error != nil {
+					event.Error = u.Error.Error()
+				}
+				w.report(event)
+			case <-done:
+				// the updates channel is not closed when the write fails before uploading
+				return
+			}
+		}
+	}()
+
+	err := w.ImagesWriter.WriteImage(ref, img, updates)
+	close(done)
+	<-stopped
+	return err
+}
+
+func (w *progressWriter) WriteTag(ref regname.Tag, taggable regremote.Taggable) error {
+	w.report(ProgressEvent{Phase: ProgressPhaseTagging, Image: w.image})
+	return w.ImagesWriter.WriteTag(ref, taggable)
+}
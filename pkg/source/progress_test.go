@@ -0,0 +1,107 @@
+/*
+Copyright 2021 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/logger"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/source"
+)
+
+func TestNewJSONProgressReporter(t *testing.T) {
+	output := &bytes.Buffer{}
+	report := source.NewJSONProgressReporter(output)
+	report(source.ProgressEvent{Phase: source.ProgressPhasePackaging, Image: "example.com/hello:source"})
+	report(source.ProgressEvent{Phase: source.ProgressPhaseUploading, Image: "example.com/hello:source", Layer: "sha256:abc", Bytes: 10, TotalBytes: 20})
+
+	expected := strings.TrimSpace(`
+{"phase":"packaging","image":"example.com/hello:source","bytes":0,"totalBytes":0}
+{"phase":"uploading","image":"example.com/hello:source","layer":"sha256:abc","bytes":10,"totalBytes":20}
+`)
+	if diff := cmp.Diff(expected, strings.TrimSpace(output.String())); diff != "" {
+		t.Errorf("NewJSONProgressReporter() (-want, +got) = %s", diff)
+	}
+}
+
+func TestProgressReporterStash(t *testing.T) {
+	ctx := context.Background()
+	if reporter := source.RetrieveProgressReporter(ctx); reporter != nil {
+		t.Errorf("RetrieveProgressReporter() expected nil reporter")
+	}
+
+	called := false
+	ctx = source.StashProgressReporter(ctx, func(source.ProgressEvent) { called = true })
+	source.RetrieveProgressReporter(ctx)(source.ProgressEvent{})
+	if !called {
+		t.Errorf("RetrieveProgressReporter() expected stashed reporter")
+	}
+}
+
+func TestImgpkgPushProgress(t *testing.T) {
+	reg, err := ggcrregistry.TLS("localhost")
+	utilruntime.Must(err)
+	defer reg.Close()
+	u, err := url.Parse(reg.URL)
+	utilruntime.Must(err)
+	image := fmt.Sprintf("%s/hello:source", u.Host)
+
+	events := []source.ProgressEvent{}
+	ctx := source.StashContainerRemoteTransport(context.Background(), reg.Client().Transport)
+	ctx = logger.StashSourceImageLogger(ctx, logger.NewNoopLogger())
+	ctx = source.StashProgressReporter(ctx, func(event source.ProgressEvent) {
+		events = append(events, event)
+	})
+
+	digestedImage, err := source.ImgpkgPush(ctx, "testdata/hello_jar", []string{}, &source.RegistryOpts{}, image)
+	if err != nil {
+		t.Fatalf("ImgpkgPush() errored %v", err)
+	}
+
+	phases := []string{}
+	var lastUpload *source.ProgressEvent
+	for i := range events {
+		if len(phases) == 0 || phases[len(phases)-1] != events[i].Phase {
+			phases = append(phases, events[i].Phase)
+		}
+		if events[i].Phase == source.ProgressPhaseUploading {
+			lastUpload = &events[i]
+		}
+	}
+	expectedPhases := []string{source.ProgressPhasePackaging, source.ProgressPhaseUploading, source.ProgressPhaseTagging, source.ProgressPhasePublished}
+	if diff := cmp.Diff(expectedPhases, phases); diff != "" {
+		t.Errorf("ImgpkgPush() phases (-want, +got) = %s", diff)
+	}
+	if lastUpload == nil {
+		t.Fatalf("ImgpkgPush() expected upload events")
+	}
+	if lastUpload.Layer == "" || lastUpload.TotalBytes == 0 || lastUpload.Bytes != lastUpload.TotalBytes {
+		t.Errorf("ImgpkgPush() unexpected final upload event %+v", lastUpload)
+	}
+	if published := events[len(events)-1]; published.Image != digestedImage {
+		t.Errorf("ImgpkgPush() published image wanted %q, got %q", digestedImage, published.Image)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ProxySchemes are the schemes of the proxy URLs the registry client can connect through
+var ProxySchemes = []string{"http", "https", "socks5"}
+
+// ParseProxy parses the URL of a proxy, a URL without scheme is an http proxy like for HTTPS_PROXY
+func ParseProxy(proxy string) (*url.URL, error) {
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
+	}
+	supported := false
+	for _, scheme := range ProxySchemes {
+		supported = supported || u.Scheme == scheme
+	}
+	if !supported || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q, expected a URL with one of the schemes %s", proxy, strings.Join(ProxySchemes, ", "))
+	}
+	return u, nil
+}
+
+// ProxyFunc returns the proxy of the requests to the registry. An explicit proxy is used for every
+// host except the ones of NO_PROXY, like the HTTPS_PROXY environment variable it takes precedence
+// over. Without explicit proxy, the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables apply.
+func ProxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	proxyURL, err := ParseProxy(proxy)
+	if err != nil {
+		return nil, err
+	}
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(noProxy, req.URL) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// bypassProxy tells whether the host of the URL matches one of the comma separated entries of
+// NO_PROXY: "*" for every host, a domain matching itself and its subdomains, or ".domain" for its
+// subdomains only, an IP address or a CIDR range, each with an optional port
+func bypassProxy(noProxy string, u *url.URL) bool {
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+	ip := net.ParseIP(host)
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		entryHost, entryPort := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			entryHost, entryPort = h, p
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+		if entryIP := net.ParseIP(entryHost); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		host := strings.ToLower(host)
+		if strings.HasPrefix(entryHost, ".") {
+			if strings.HasSuffix(host, entryHost) {
+				return true
+			}
+			continue
+		}
+		if host == entryHost || strings.HasSuffix(host, "."+entryHost) {
+			return true
+		}
+	}
+	return false
+}
+
+// newProxyTransport creates the transport of the registry client connecting through the proxy. The
+// transport is configured like the default one of the registry client, trusting the system
+// certificates along with the CA certificates of the host.
+func newProxyTransport(registryOpts *RegistryOpts, host string) (http.RoundTripper, error) {
+	proxy, err := ProxyFunc(registryOpts.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	var pool *x509.CertPool
+	// windows does not return the system certificates, they are fetched while verifying the
+	// certificates when the pool is nil
+	if runtime.GOOS != "windows" {
+		if pool, err = x509.SystemCertPool(); err != nil {
+			return nil, err
+		}
+	}
+	caCertPaths := registryOpts.CACertPathsFor(host)
+	if pool == nil && len(caCertPaths) != 0 {
+		pool = x509.NewCertPool()
+	}
+	for _, path := range caCertPaths {
+		certs, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA certificates from %q: %s", path, err)
+		}
+		if !pool.AppendCertsFromPEM(certs) {
+			return nil, fmt.Errorf("adding CA certificates from %q: failed", path)
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.ForceAttemptHTTP2 = false
+	transport.ResponseHeaderTimeout = 30 * time.Second
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return transport, nil
+}
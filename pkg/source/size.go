@@ -0,0 +1,86 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// File is a file of the source code, with its path relative to the source directory
+type File struct {
+	Path string
+	Size int64
+}
+
+// Size returns the total size of the files in dir that would be published, along with the largest
+// top files. Excluded paths are skipped the same way ImgpkgPush skips them.
+func Size(dir string, excludedFiles []string, top int) (int64, []File, error) {
+	var total int64
+	files := []File{}
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		for _, excluded := range excludedFiles {
+			if excluded == relPath {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		total += info.Size()
+		files = append(files, File{Path: relPath, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Size > files[j].Size
+	})
+	if len(files) > top {
+		files = files[:top]
+	}
+	return total, files, nil
+}
+
+// FormatSize formats a number of bytes with the binary suffixes used by resource quantities
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	value := float64(size) / unit
+	for _, suffix := range []string{"Ki", "Mi", "Gi", "Ti"} {
+		if value < unit || suffix == "Ti" {
+			return fmt.Sprintf("%.1f%s", value, suffix)
+		}
+		value /= unit
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/source"
+)
+
+func TestSize(t *testing.T) {
+	dir := t.TempDir()
+	utilruntime.Must(os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	utilruntime.Must(os.MkdirAll(filepath.Join(dir, "target"), 0755))
+	utilruntime.Must(os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("package main\n"), 0644))
+	utilruntime.Must(os.WriteFile(filepath.Join(dir, "dump.sql"), make([]byte, 300), 0644))
+	utilruntime.Must(os.WriteFile(filepath.Join(dir, "README.md"), make([]byte, 100), 0644))
+	utilruntime.Must(os.WriteFile(filepath.Join(dir, "target", "app.jar"), make([]byte, 1000), 0644))
+
+	total, largest, err := source.Size(dir, []string{"target"}, 2)
+	if err != nil {
+		t.Fatalf("Size() errored %v", err)
+	}
+	if expected, actual := int64(413), total; expected != actual {
+		t.Errorf("Size() expected total %d, got %d", expected, actual)
+	}
+	expected := []source.File{
+		{Path: "dump.sql", Size: 300},
+		{Path: "README.md", Size: 100},
+	}
+	if diff := cmp.Diff(expected, largest); diff != "" {
+		t.Errorf("Size() (-expected, +actual) = %s", diff)
+	}
+
+	if _, _, err := source.Size(filepath.Join(dir, "missing"), nil, 2); err == nil {
+		t.Errorf("Size() expected error for a missing directory")
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size     int64
+		expected string
+	}{
+		{size: 0, expected: "0B"},
+		{size: 1023, expected: "1023B"},
+		{size: 1536, expected: "1.5Ki"},
+		{size: 100 * 1024 * 1024, expected: "100.0Mi"},
+		{size: 3 * 1024 * 1024 * 1024, expected: "3.0Gi"},
+		{size: 2048 * 1024 * 1024 * 1024 * 1024, expected: "2048.0Ti"},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if actual := source.FormatSize(test.size); test.expected != actual {
+				t.Errorf("FormatSize() expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
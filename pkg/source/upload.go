@@ -0,0 +1,160 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	uploadStateFileName  = "state.json"
+	uploadSourceFileName = "source.tar"
+)
+
+// UploadState records an upload of source code in progress. It is kept in the cache dir, along with
+// the packaged source, when the upload is interrupted. The next upload of the same source to the same
+// image resumes it: the source is not packaged again and the layers the registry already has are not
+// uploaded again.
+type UploadState struct {
+	Image       string    `json:"image"`
+	Fingerprint string    `json:"fingerprint"`
+	StartedAt   time.Time `json:"startedAt"`
+}
+
+type uploadCacheDirStashKey struct{}
+
+// StashUploadCacheDir sets the directory where the state of uploads in progress is recorded
+func StashUploadCacheDir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, uploadCacheDirStashKey{}, dir)
+}
+
+// RetrieveUploadCacheDir returns the directory where the state of uploads in progress is recorded, by
+// default $XDG_CACHE_HOME/tanzu/apps/uploads. Returns an empty string when there is no cache dir.
+func RetrieveUploadCacheDir(ctx context.Context) string {
+	if dir, ok := ctx.Value(uploadCacheDirStashKey{}).(string); ok {
+		return dir
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, "tanzu", "apps", "uploads")
+}
+
+type uploadStateStashKey struct{}
+
+// StashUploadState passes the state returned by PrepareUpload to ImgpkgPush, for the source not to
+// be fingerprinted again
+func StashUploadState(ctx context.Context, state *UploadState) context.Context {
+	return context.WithValue(ctx, uploadStateStashKey{}, state)
+}
+
+// RetrieveUploadState returns the stashed state of the upload, nil when there is none
+func RetrieveUploadState(ctx context.Context) *UploadState {
+	state, _ := ctx.Value(uploadStateStashKey{}).(*UploadState)
+	return state
+}
+
+// PrepareUpload fingerprints the source in dir and returns the state of its upload to image, along
+// with whether a previous upload of the same source did not complete. An interrupted upload is resumed
+// from the source it already packaged. A nil state is returned when there is no cache dir or the
+// source cannot be fingerprinted.
+func PrepareUpload(ctx context.Context, dir string, excludedFiles []string, image string) (*UploadState, bool) {
+	cacheDir := RetrieveUploadCacheDir(ctx)
+	if cacheDir == "" {
+		return nil, false
+	}
+	fingerprint, err := Fingerprint(dir, excludedFiles)
+	if err != nil {
+		return nil, false
+	}
+	u := &upload{dir: uploadDir(cacheDir, image)}
+	if previous, err := readUploadState(u.dir); err == nil && previous.Image == image && previous.Fingerprint == fingerprint && u.packaged() {
+		return previous, true
+	}
+	return &UploadState{Image: image, Fingerprint: fingerprint, StartedAt: time.Now()}, false
+}
+
+// upload tracks the state and the packaged source of an upload of source code in progress
+type upload struct {
+	dir string
+}
+
+// startUpload records the state of the upload in the cache dir. The source packaged by a previous
+// upload is removed unless it was packaged from the same source. The state is best effort, a nil
+// upload is returned when it cannot be recorded.
+func startUpload(ctx context.Context, state *UploadState) *upload {
+	cacheDir := RetrieveUploadCacheDir(ctx)
+	if cacheDir == "" {
+		return nil
+	}
+	u := &upload{dir: uploadDir(cacheDir, state.Image)}
+	if previous, err := readUploadState(u.dir); err != nil || previous.Fingerprint != state.Fingerprint {
+		if err := os.RemoveAll(u.dir); err != nil {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(u.dir, 0700); err != nil {
+		return nil
+	}
+	content, err := json.Marshal(state)
+	if err != nil {
+		return nil
+	}
+	if err := os.WriteFile(filepath.Join(u.dir, uploadStateFileName), content, 0600); err != nil {
+		return nil
+	}
+	return u
+}
+
+// sourceFile is the tarball the source is packaged to, kept until the upload completes
+func (u *upload) sourceFile() string {
+	return filepath.Join(u.dir, uploadSourceFileName)
+}
+
+func (u *upload) packaged() bool {
+	_, err := os.Stat(u.sourceFile())
+	return err == nil
+}
+
+// finish removes the state and the packaged source of a completed upload
+func (u *upload) finish() {
+	os.RemoveAll(u.dir)
+}
+
+func uploadDir(cacheDir, image string) string {
+	h := sha256.Sum256([]byte(image))
+	return filepath.Join(cacheDir, hex.EncodeToString(h[:]))
+}
+
+func readUploadState(dir string) (*UploadState, error) {
+	content, err := os.ReadFile(filepath.Join(dir, uploadStateFileName))
+	if err != nil {
+		return nil, err
+	}
+	state := &UploadState{}
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/logger"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/source"
+)
+
+// readOnlyTransport denies every write to the registry, like a push interrupted before it completes
+type readOnlyTransport struct {
+	http.RoundTripper
+}
+
+func (t readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.RoundTripper.RoundTrip(req)
+	}
+	return &http.Response{
+		StatusCode: http.StatusForbidden,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"errors":[{"code":"DENIED","message":"read only"}]}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestPrepareUpload(t *testing.T) {
+	reg, err := ggcrregistry.TLS("localhost")
+	utilruntime.Must(err)
+	defer reg.Close()
+	u, err := url.Parse(reg.URL)
+	utilruntime.Must(err)
+	image := fmt.Sprintf("%s/hello:source", u.Host)
+
+	cacheDir := t.TempDir()
+	ctx := source.StashUploadCacheDir(context.Background(), cacheDir)
+	ctx = logger.StashSourceImageLogger(ctx, logger.NewNoopLogger())
+
+	if state, resumed := source.PrepareUpload(ctx, "testdata/hello_jar", []string{}, image); state == nil || resumed {
+		t.Errorf("PrepareUpload() expected a new upload before the first upload, got %+v resumed %t", state, resumed)
+	}
+
+	failingCtx := source.StashContainerRemoteTransport(ctx, readOnlyTransport{RoundTripper: reg.Client().Transport})
+	if _, err := source.ImgpkgPush(failingCtx, "testdata/hello_jar", []string{}, &source.RegistryOpts{}, image); err == nil {
+		t.Fatalf("ImgpkgPush() expected error")
+	}
+	state, resumed := source.PrepareUpload(ctx, "testdata/hello_jar", []string{}, image)
+	if !resumed {
+		t.Fatalf("PrepareUpload() expected to resume the failed upload")
+	}
+	if state.Image != image || state.StartedAt.IsZero() {
+		t.Errorf("PrepareUpload() unexpected state %+v", state)
+	}
+	if _, resumed := source.PrepareUpload(ctx, "testdata/hello_jar", []string{}, image+"-other"); resumed {
+		t.Errorf("PrepareUpload() expected not to resume the upload to another image")
+	}
+	if _, resumed := source.PrepareUpload(ctx, "testdata/hello_jar", []string{"hello.go"}, image); resumed {
+		t.Errorf("PrepareUpload() expected not to resume the upload when the source changed")
+	}
+	if _, err := registryDigest(reg, image); err == nil {
+		t.Errorf("ImgpkgPush() expected the tag not to be written by the failed upload")
+	}
+
+	ctx = source.StashContainerRemoteTransport(ctx, reg.Client().Transport)
+	digestedImage, err := source.ImgpkgPush(source.StashUploadState(ctx, state), "testdata/hello_jar", []string{}, &source.RegistryOpts{}, image)
+	if err != nil {
+		t.Fatalf("ImgpkgPush() errored %v", err)
+	}
+	if published, _ := source.PublishedImage(ctx, "testdata/hello_jar", []string{}, &source.RegistryOpts{}, image); published != digestedImage {
+		t.Errorf("PublishedImage() wanted %q, got %q", digestedImage, published)
+	}
+	if _, resumed := source.PrepareUpload(ctx, "testdata/hello_jar", []string{}, image); resumed {
+		t.Errorf("PrepareUpload() expected not to resume after the upload completed")
+	}
+	if uploads, err := os.ReadDir(cacheDir); err != nil || len(uploads) != 0 {
+		t.Errorf("ImgpkgPush() expected the completed upload to be removed from the cache dir, got %d", len(uploads))
+	}
+	if tags, err := registryTags(reg, image); err != nil || len(tags) != 1 {
+		t.Errorf("ImgpkgPush() expected only the tag of the image, got %v", tags)
+	}
+}
+
+// blockingTransport cancels the context on the first write to the registry and holds the write until
+// released, like a push interrupted half way
+type blockingTransport struct {
+	http.RoundTripper
+	cancel  context.CancelFunc
+	release chan struct{}
+}
+
+func (t blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		t.cancel()
+		<-t.release
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
+
+func TestImgpkgPushCancelled(t *testing.T) {
+	reg, err := ggcrregistry.TLS("localhost")
+	utilruntime.Must(err)
+	defer reg.Close()
+	u, err := url.Parse(reg.URL)
+	utilruntime.Must(err)
+	image := fmt.Sprintf("%s/hello:source", u.Host)
+
+	release := make(chan struct{})
+	defer close(release)
+	ctx, cancel := context.WithCancel(source.StashUploadCacheDir(context.Background(), t.TempDir()))
+	defer cancel()
+	ctx = logger.StashSourceImageLogger(ctx, logger.NewNoopLogger())
+	ctx = source.StashContainerRemoteTransport(ctx, blockingTransport{RoundTripper: reg.Client().Transport, cancel: cancel, release: release})
+
+	if _, err := source.ImgpkgPush(ctx, "testdata/hello_jar", []string{}, &source.RegistryOpts{}, image); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ImgpkgPush() wanted error %v, got %v", context.Canceled, err)
+	}
+	resumeCtx := source.StashUploadCacheDir(context.Background(), source.RetrieveUploadCacheDir(ctx))
+	if _, resumed := source.PrepareUpload(resumeCtx, "testdata/hello_jar", []string{}, image); !resumed {
+		t.Errorf("PrepareUpload() expected to resume the cancelled upload")
+	}
+}
+
+func registryDigest(reg *httptest.Server, image string) (string, error) {
+	ref, err := name.NewTag(image)
+	if err != nil {
+		return "", err
+	}
+	desc, err := remote.Head(ref, remote.WithTransport(reg.Client().Transport))
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
+
+func registryTags(reg *httptest.Server, image string) ([]string, error) {
+	ref, err := name.NewTag(image)
+	if err != nil {
+		return nil, err
+	}
+	return remote.List(ref.Context(), remote.WithTransport(reg.Client().Transport))
+}
@@ -0,0 +1,134 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/cli-runtime/pkg/resource"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	cartov1alpha1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/cartographer/v1alpha1"
+	knativeservingv1 "github.com/vmware-tanzu/apps-cli-plugin/pkg/apis/knative/serving/v1"
+	cli "github.com/vmware-tanzu/apps-cli-plugin/pkg/cli-runtime"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/commands"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+// ContextEnvVar names the kubeconfig context the suite runs against, the current context is used
+// when it is not set
+const ContextEnvVar = "APPS_CLUSTER_TEST_CONTEXT"
+
+// harness runs the workload commands in process against the API server of a real cluster, each
+// test within a namespace of its own
+type harness struct {
+	config    *cli.Config
+	namespace string
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	ctx := context.Background()
+
+	kubeContext := os.Getenv(ContextEnvVar)
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+	if _, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig(); err != nil {
+		// the client exits the process without a cluster to connect to
+		t.Skipf("no cluster to run against: %v", err)
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = cartov1alpha1.AddToScheme(scheme)
+	_ = knativeservingv1.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
+
+	c := cli.NewDefaultConfig("tanzu apps", scheme)
+	c.Client = cli.NewClient("", kubeContext, scheme)
+	c.Builder = resource.NewBuilder(c.Client)
+
+	if err := c.List(ctx, &cartov1alpha1.WorkloadList{}, client.InNamespace(metav1.NamespaceDefault)); err != nil {
+		if meta.IsNoMatchError(err) {
+			t.Fatalf("the Cartographer CRDs are not installed on the cluster, see make cluster-integration-test")
+		}
+		t.Fatalf("unable to list workloads: %v", err)
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "apps-cli-" + rand.String(8),
+		},
+	}
+	if err := c.Create(ctx, namespace); err != nil {
+		t.Fatalf("unable to create namespace: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := c.Delete(ctx, namespace); err != nil {
+			t.Errorf("unable to delete namespace %q: %v", namespace.Name, err)
+		}
+	})
+
+	return &harness{config: c, namespace: namespace.Name}
+}
+
+// run executes a workload command in the namespace of the harness and returns its output
+func (h *harness) run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	output := &bytes.Buffer{}
+	h.config.Stdin = &bytes.Buffer{}
+	h.config.Stdout = output
+	h.config.Stderr = output
+
+	cmd := commands.NewWorkloadCommand(ctx, h.config)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetOutput(output)
+	cmd.SetArgs(append(args, flags.NamespaceFlagName, h.namespace))
+	err := cmd.Execute()
+	t.Logf("tanzu apps workload %v\n%s", args, output.String())
+	return output.String(), err
+}
+
+// getWorkload returns the workload of the harness namespace, or nil when it does not exist
+func (h *harness) getWorkload(t *testing.T, name string) *cartov1alpha1.Workload {
+	t.Helper()
+	workload := &cartov1alpha1.Workload{}
+	if err := h.config.Get(context.Background(), client.ObjectKey{Namespace: h.namespace, Name: name}, workload); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			t.Fatalf("unable to get workload %q: %v", name, err)
+		}
+		return nil
+	}
+	return workload
+}
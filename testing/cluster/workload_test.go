@@ -0,0 +1,153 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2022 VMware, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/apis"
+	"github.com/vmware-tanzu/apps-cli-plugin/pkg/flags"
+)
+
+func TestWorkloadLifecycle(t *testing.T) {
+	h := newHarness(t)
+	name := "petclinic"
+
+	if _, err := h.run(t, "create", name, flags.GitRepoFlagName, "https://github.com/sample-accelerators/spring-petclinic", flags.GitBranchFlagName, "main", flags.TypeFlagName, "web", flags.YesFlagName); err != nil {
+		t.Fatalf("create error = %v", err)
+	}
+	created := h.getWorkload(t, name)
+	if created == nil {
+		t.Fatalf("expected workload %q to be created", name)
+	}
+	if expected, actual := "web", created.Labels[apis.WorkloadTypeLabelName]; expected != actual {
+		t.Errorf("expected type %q, actually %q", expected, actual)
+	}
+
+	// apply updates the stored workload, going through the optimistic concurrency and the
+	// structural schema of the API server
+	if _, err := h.run(t, "apply", flags.FilePathFlagName, "testdata/workload.yaml", flags.EnvFlagName, "LOG_LEVEL=debug", flags.YesFlagName); err != nil {
+		t.Fatalf("apply error = %v", err)
+	}
+	applied := h.getWorkload(t, name)
+	if applied == nil {
+		t.Fatalf("expected workload %q to exist", name)
+	}
+	if applied.ResourceVersion == created.ResourceVersion {
+		t.Errorf("expected workload %q to be updated", name)
+	}
+	expectedEnv := []corev1.EnvVar{
+		{Name: "SPRING_PROFILES_ACTIVE", Value: "mysql"},
+		{Name: "LOG_LEVEL", Value: "debug"},
+	}
+	if diff := cmp.Diff(expectedEnv, applied.Spec.Env); diff != "" {
+		t.Errorf("unexpected env (-expected, +actual): %s", diff)
+	}
+	ports := []map[string]int{}
+	applied.Spec.GetParam("ports", &ports)
+	if diff := cmp.Diff([]map[string]int{{"containerPort": 8080, "port": 80}}, ports); diff != "" {
+		t.Errorf("unexpected ports param (-expected, +actual): %s", diff)
+	}
+
+	output, err := h.run(t, "apply", flags.FilePathFlagName, "testdata/workload.yaml", flags.EnvFlagName, "LOG_LEVEL=debug", flags.YesFlagName)
+	if err != nil {
+		t.Fatalf("apply error = %v", err)
+	}
+	if !strings.Contains(output, "Workload is unchanged, skipping update") {
+		t.Errorf("expected the second apply to leave the workload unchanged")
+	}
+
+	output, err = h.run(t, "get", name)
+	if err != nil {
+		t.Fatalf("get error = %v", err)
+	}
+	if !strings.Contains(output, name) {
+		t.Errorf("expected get to show workload %q", name)
+	}
+
+	// waiting for the delete goes through a watch of the API server
+	if _, err := h.run(t, "delete", name, flags.WaitFlagName, flags.YesFlagName); err != nil {
+		t.Fatalf("delete error = %v", err)
+	}
+	if h.getWorkload(t, name) != nil {
+		t.Errorf("expected workload %q to be deleted", name)
+	}
+}
+
+func TestWorkloadDryRun(t *testing.T) {
+	h := newHarness(t)
+
+	output, err := h.run(t, "apply", flags.FilePathFlagName, "testdata/workload.yaml", flags.DryRunFlagName)
+	if err != nil {
+		t.Fatalf("apply error = %v", err)
+	}
+	if !strings.Contains(output, "name: petclinic") {
+		t.Errorf("expected the dry run to print the workload")
+	}
+	if h.getWorkload(t, "petclinic") != nil {
+		t.Errorf("expected the dry run not to create the workload")
+	}
+}
+
+func TestWorkloadServerValidation(t *testing.T) {
+	h := newHarness(t)
+	name := "invalid"
+
+	// label values are only validated by the API server
+	if _, err := h.run(t, "create", name, flags.ImageFlagName, "registry.example/my-image:1.0", flags.LabelFlagName, "team=not a valid value", flags.YesFlagName); err == nil {
+		t.Errorf("expected create to be rejected by the API server")
+	}
+	if h.getWorkload(t, name) != nil {
+		t.Errorf("expected workload %q not to be created", name)
+	}
+}
+
+func TestWorkloadNotFound(t *testing.T) {
+	h := newHarness(t)
+	name := "missing"
+
+	for _, args := range [][]string{
+		{"get", name},
+		{"update", name, flags.EnvFlagName, "FOO=bar", flags.YesFlagName},
+	} {
+		output, err := h.run(t, args...)
+		if err == nil {
+			t.Errorf("expected %q to fail for a missing workload", strings.Join(args, " "))
+		}
+		if !strings.Contains(output, "not found") {
+			t.Errorf("expected %q to report the workload is not found", strings.Join(args, " "))
+		}
+	}
+
+	output, err := h.run(t, "delete", name, flags.YesFlagName)
+	if err != nil {
+		t.Errorf("delete error = %v", err)
+	}
+	if !strings.Contains(output, "does not exist") {
+		t.Errorf("expected delete to report the workload does not exist")
+	}
+	if h.getWorkload(t, name) != nil {
+		t.Errorf("expected workload %q not to be created", name)
+	}
+}